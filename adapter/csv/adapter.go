@@ -0,0 +1,111 @@
+package csv
+
+import (
+	"github.com/simon-lentz/yammm/location"
+)
+
+// Adapter parses CSV and TSV data into RawInstance values with optional
+// location tracking, and writes graph instances back to CSV.
+//
+// Thread Safety: Adapter is safe for concurrent Parse* and Write* calls after
+// construction. No shared mutable state exists; all context flows through
+// parameters.
+type Adapter struct {
+	registry       location.PositionRegistry
+	trackLocations bool
+	comma          rune
+	listDelimiter  string
+	columns        map[string]string // header -> property or target column
+}
+
+// ParseOption configures Adapter behavior.
+type ParseOption func(*Adapter)
+
+// NewAdapter creates a new CSV adapter with the given options.
+//
+// If WithTrackLocations(true) is set but registry is nil, returns an error.
+// The registry parameter may be nil if WithTrackLocations is not used.
+func NewAdapter(registry location.PositionRegistry, opts ...ParseOption) (*Adapter, error) {
+	a := &Adapter{
+		registry:       registry,
+		trackLocations: false, // Don't track locations by default
+		comma:          ',',
+		listDelimiter:  ";",
+		columns:        make(map[string]string),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	// Validate: can't track locations without a registry
+	if a.trackLocations && a.registry == nil {
+		return nil, ErrNilRegistry
+	}
+
+	// Validate: the field separator must be one that encoding/csv accepts
+	if !validComma(a.comma) {
+		return nil, ErrInvalidComma
+	}
+
+	// Validate: list cells need a delimiter distinct from the field separator
+	if a.listDelimiter == "" || a.listDelimiter == string(a.comma) {
+		return nil, ErrInvalidListDelimiter
+	}
+
+	return a, nil
+}
+
+// validComma reports whether r can separate fields: not a quote, line
+// break, Unicode replacement character or invalid rune.
+func validComma(r rune) bool {
+	switch r {
+	case '"', '\r', '\n', 0xFFFD:
+		return false
+	}
+	return r > 0 && r <= 0x10FFFF
+}
+
+// WithTrackLocations enables source position tracking for parsed rows and
+// cells.
+//
+// When enabled, each RawInstance carries a span covering its row, and cell
+// errors carry a span covering the offending cell. Byte offsets are resolved
+// to line/column positions via the PositionRegistry.
+//
+// Requires a non-nil PositionRegistry to be passed to NewAdapter.
+func WithTrackLocations(track bool) ParseOption {
+	return func(a *Adapter) {
+		a.trackLocations = track
+	}
+}
+
+// WithComma sets the field separator. Default is ','; use '\t' for TSV.
+//
+// Returns ErrInvalidComma from NewAdapter if r is a quote, a line break or
+// not a valid rune.
+func WithComma(r rune) ParseOption {
+	return func(a *Adapter) {
+		a.comma = r
+	}
+}
+
+// WithListDelimiter sets the separator between the elements of List, Set and
+// Vector cells, and between the targets of many-associations. Default is ";".
+//
+// Returns ErrInvalidListDelimiter from NewAdapter if delim is empty or equal
+// to the field separator.
+func WithListDelimiter(delim string) ParseOption {
+	return func(a *Adapter) {
+		a.listDelimiter = delim
+	}
+}
+
+// WithColumn maps a header to a property name or to an association target
+// column (`field._target_<pk>`), for exports whose headers do not match the
+// schema. The writer uses the mapping in reverse, emitting header for name.
+func WithColumn(header, name string) ParseOption {
+	return func(a *Adapter) {
+		a.columns[header] = name
+	}
+}
@@ -0,0 +1,336 @@
+package csv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/internal/source"
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/load"
+)
+
+const clinicSchema = `schema "clinic"
+
+type Physician {
+    id String primary
+    name String
+}
+
+type Ward {
+    building String primary
+    opened Date primary
+}
+
+type Patient {
+    id String primary
+    name String required
+    age Integer
+    weight Float
+    active Boolean
+    status Enum["admitted", "discharged"]
+    born Date
+    tags Set<String>
+    scores List<Integer>
+    meta Map<String, Integer>
+    --> PHYSICIAN (one) Physician
+    --> CONSULTANTS (many) Physician
+    --> WARD (one) Ward
+    --> WARDS (many) Ward
+}
+`
+
+// loadClinic loads the test schema.
+func loadClinic(t *testing.T) *schema.Schema {
+	t.Helper()
+	s, result, err := load.LoadString(t.Context(), clinicSchema, "clinic")
+	require.NoError(t, err)
+	require.True(t, result.OK(), "schema errors: %v", result.Messages())
+	return s
+}
+
+// trackingAdapter returns an adapter tracking locations in data, registered
+// under id.
+func trackingAdapter(t *testing.T, id string, data string, opts ...ParseOption) (*Adapter, location.SourceID) {
+	t.Helper()
+	reg := source.NewRegistry()
+	sourceID := location.NewSourceID(id)
+	require.NoError(t, reg.Register(sourceID, []byte(data)))
+	adapter, err := NewAdapter(reg, append([]ParseOption{WithTrackLocations(true)}, opts...)...)
+	require.NoError(t, err)
+	return adapter, sourceID
+}
+
+func TestNewAdapter(t *testing.T) {
+	t.Run("nil registry without tracking", func(t *testing.T) {
+		adapter, err := NewAdapter(nil)
+		require.NoError(t, err)
+		assert.NotNil(t, adapter)
+	})
+
+	t.Run("nil registry with tracking returns error", func(t *testing.T) {
+		_, err := NewAdapter(nil, WithTrackLocations(true))
+		assert.Equal(t, ErrNilRegistry, err)
+	})
+
+	t.Run("invalid comma returns error", func(t *testing.T) {
+		_, err := NewAdapter(nil, WithComma('"'))
+		assert.Equal(t, ErrInvalidComma, err)
+	})
+
+	t.Run("list delimiter equal to comma returns error", func(t *testing.T) {
+		_, err := NewAdapter(nil, WithComma(';'))
+		assert.Equal(t, ErrInvalidListDelimiter, err)
+	})
+
+	t.Run("empty list delimiter returns error", func(t *testing.T) {
+		_, err := NewAdapter(nil, WithListDelimiter(""))
+		assert.Equal(t, ErrInvalidListDelimiter, err)
+	})
+}
+
+func TestParseType(t *testing.T) {
+	s := loadClinic(t)
+	source := location.NewSourceID("test://patients.csv")
+	adapter, _ := NewAdapter(nil)
+
+	t.Run("converts cells by property constraint", func(t *testing.T) {
+		data := "id,name,age,weight,active,status,born,tags,scores,meta\n" +
+			"p1,Alice, 42 ,61.5,TRUE,Admitted,1990-04-01,vip; allergy,1;2;3,\"{\"\"visits\"\": 3}\"\n"
+		result, diags := adapter.ParseType(source, s, "Patient", []byte(data))
+		require.True(t, diags.OK(), "expected no errors: %v", diags.Messages())
+		require.Len(t, result, 1)
+		assert.Equal(t, map[string]any{
+			"id":     "p1",
+			"name":   "Alice",
+			"age":    int64(42),
+			"weight": 61.5,
+			"active": true,
+			"status": "admitted",
+			"born":   "1990-04-01",
+			"tags":   []any{"vip", "allergy"},
+			"scores": []any{int64(1), int64(2), int64(3)},
+			"meta":   map[string]any{"visits": int64(3)},
+		}, result[0].Properties)
+	})
+
+	t.Run("empty cells are absent", func(t *testing.T) {
+		result, diags := adapter.ParseType(source, s, "Patient", []byte("id,name,age\np1,Alice,\n"))
+		require.True(t, diags.OK(), "expected no errors: %v", diags.Messages())
+		require.Len(t, result, 1)
+		assert.NotContains(t, result[0].Properties, "age")
+	})
+
+	t.Run("headers match ignoring case", func(t *testing.T) {
+		result, diags := adapter.ParseType(source, s, "Patient", []byte("ID,Name\np1,Alice\n"))
+		require.True(t, diags.OK(), "expected no errors: %v", diags.Messages())
+		assert.Equal(t, "p1", result[0].Properties["id"])
+	})
+
+	t.Run("column mapping", func(t *testing.T) {
+		mapped, err := NewAdapter(nil, WithColumn("Patient ID", "id"), WithColumn("GP", "physician._target_id"))
+		require.NoError(t, err)
+		result, diags := mapped.ParseType(source, s, "Patient", []byte("Patient ID,GP\np1,d7\n"))
+		require.True(t, diags.OK(), "expected no errors: %v", diags.Messages())
+		assert.Equal(t, "p1", result[0].Properties["id"])
+		assert.Equal(t, map[string]any{"_target_id": "d7"}, result[0].Properties["physician"])
+	})
+
+	t.Run("association target columns", func(t *testing.T) {
+		data := "id,physician._target_id,consultants._target_id,ward._target_building,ward._target_opened\n" +
+			"p1,d7,d1;d2,North,2001-09-01\n"
+		result, diags := adapter.ParseType(source, s, "Patient", []byte(data))
+		require.True(t, diags.OK(), "expected no errors: %v", diags.Messages())
+		props := result[0].Properties
+		assert.Equal(t, map[string]any{"_target_id": "d7"}, props["physician"])
+		assert.Equal(t, []any{
+			map[string]any{"_target_id": "d1"},
+			map[string]any{"_target_id": "d2"},
+		}, props["consultants"])
+		assert.Equal(t, map[string]any{"_target_building": "North", "_target_opened": "2001-09-01"}, props["ward"])
+	})
+
+	t.Run("tab separated with byte order mark", func(t *testing.T) {
+		tsv, err := NewAdapter(nil, WithComma('\t'))
+		require.NoError(t, err)
+		data := "\ufeffid\tname\tage\np1\tAlice, MD\t42\n"
+		result, diags := tsv.ParseType(source, s, "Patient", []byte(data))
+		require.True(t, diags.OK(), "expected no errors: %v", diags.Messages())
+		assert.Equal(t, "Alice, MD", result[0].Properties["name"])
+		assert.Equal(t, int64(42), result[0].Properties["age"])
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		result, diags := adapter.ParseType(source, s, "Patient", nil)
+		assert.True(t, diags.OK())
+		assert.Empty(t, result)
+	})
+}
+
+func TestParseType_Errors(t *testing.T) {
+	s := loadClinic(t)
+	source := location.NewSourceID("test://patients.csv")
+	adapter, _ := NewAdapter(nil)
+
+	tests := []struct {
+		name    string
+		data    string
+		code    diag.Code
+		message string
+		kept    int
+	}{
+		{"unknown type", "id\np1\n", diag.E_INSTANCE_TYPE_NOT_FOUND, `type "Nurse" not found`, 0},
+		{"unknown column", "id,height\np1,180\n", diag.E_UNKNOWN_FIELD, `type Patient has no property "height"`, 0},
+		{"duplicate column", "id,ID\np1,p2\n", diag.E_UNKNOWN_FIELD, `both map to id`, 0},
+		{"bare target column is ambiguous", "id,_target_id\np1,d7\n", diag.E_UNKNOWN_FIELD, "has 4 associations", 0},
+		{"target column names no primary key", "id,ward._target_name\np1,x\n", diag.E_UNKNOWN_FIELD, "name is not a primary key of Ward", 0},
+		{"integer", "id,age\np1,old\np2,3\n", diag.E_TYPE_MISMATCH, `expected integer, got "old"`, 1},
+		{"boolean", "id,active\np1,yes\n", diag.E_TYPE_MISMATCH, `expected boolean, got "yes"`, 0},
+		{"enum", "id,status\np1,gone\n", diag.E_TYPE_MISMATCH, "expected one of admitted, discharged", 0},
+		{"date layout", "id,born\np1,01.04.1990\n", diag.E_TYPE_MISMATCH, `expected date in layout "2006-01-02"`, 0},
+		{"list element", "id,scores\np1,1;x\n", diag.E_TYPE_MISMATCH, `element 1: expected integer, got "x"`, 0},
+		{"map json", "id,meta\np1,{visits\n", diag.E_TYPE_MISMATCH, "expected JSON value", 0},
+		{"target key", "id,ward._target_building,ward._target_opened\np1,North,top\n", diag.E_TYPE_MISMATCH, `expected date in layout "2006-01-02", got "top"`, 0},
+		{"many targets differ in length", "id,wards._target_building,wards._target_opened\np1,North;South,2001-09-01\n", diag.E_TYPE_MISMATCH, "list different numbers of targets", 0},
+		{"row length", "id,name\np1,Alice,extra\np2,Bob\n", diag.E_ADAPTER_PARSE, "row does not match header", 1},
+		{"bare quote", "id,name\np1,Al\"ice\n", diag.E_ADAPTER_PARSE, "invalid CSV", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typeName := "Patient"
+			if tt.code == diag.E_INSTANCE_TYPE_NOT_FOUND {
+				typeName = "Nurse"
+			}
+			result, diags := adapter.ParseType(source, s, typeName, []byte(tt.data))
+			require.False(t, diags.OK())
+			issue := diags.IssuesSlice()[0]
+			assert.Equal(t, tt.code, issue.Code())
+			assert.Contains(t, issue.Message(), tt.message)
+			assert.Len(t, result, tt.kept)
+		})
+	}
+}
+
+func TestParseType_Suggestions(t *testing.T) {
+	s := loadClinic(t)
+	source := location.NewSourceID("test://patients.csv")
+	adapter, _ := NewAdapter(nil)
+
+	tests := []struct {
+		name       string
+		typeName   string
+		data       string
+		suggestion string
+	}{
+		{"type", "Patinet", "id\np1\n", "Patient"},
+		{"column", "Patient", "id,nmae\np1,Alice\n", "name"},
+		{"target column", "Patient", "id,ward._target_bulding\np1,North\n", "building"},
+		{"enum", "Patient", "id,status\np1,admited\n", "admitted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, diags := adapter.ParseType(source, s, tt.typeName, []byte(tt.data))
+			require.False(t, diags.OK())
+			issue := diags.IssuesSlice()[0]
+			assert.Equal(t, `did you mean "`+tt.suggestion+`"?`, issue.Hint())
+			assert.Contains(t, issue.Details(), diag.Detail{Key: diag.DetailKeySuggestion, Value: tt.suggestion})
+		})
+	}
+}
+
+func TestParseType_PartialCompositeKey(t *testing.T) {
+	s := loadClinic(t)
+	adapter, _ := NewAdapter(nil, WithColumn("B", "ward._target_building"))
+	loaded, result := adapter.ParseType(location.NewSourceID("test://x.csv"), s, "Patient", []byte("id,B\np1,North\n"))
+	require.True(t, result.OK(), "unexpected errors: %v", result.Messages())
+	// A partial composite key is left for instance validation to report.
+	assert.Equal(t, map[string]any{"_target_building": "North"}, loaded[0].Properties["ward"])
+}
+
+func TestLocationTracking(t *testing.T) {
+	s := loadClinic(t)
+	data := "id,name,age\np1,Alice,30\np2,\"Bob\nthe Builder\",41\n"
+	adapter, sourceID := trackingAdapter(t, "test://patients.csv", data)
+
+	result, diags := adapter.ParseType(sourceID, s, "Patient", []byte(data))
+	require.True(t, diags.OK(), "expected no errors: %v", diags.Messages())
+	require.Len(t, result, 2)
+
+	tests := []struct {
+		name  string
+		idx   int
+		path  string
+		start [2]int
+		end   [2]int
+		text  string
+	}{
+		{"first row", 0, "$[0]", [2]int{2, 1}, [2]int{2, 12}, "p1,Alice,30"},
+		{"multi-line row", 1, "$[1]", [2]int{3, 1}, [2]int{4, 16}, "p2,\"Bob\nthe Builder\",41"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prov := result[tt.idx].Provenance
+			require.NotNil(t, prov)
+			assert.Equal(t, tt.path, prov.Path().String())
+			span := prov.Span()
+			assert.Equal(t, tt.start, [2]int{span.Start.Line, span.Start.Column})
+			assert.Equal(t, tt.end, [2]int{span.End.Line, span.End.Column})
+			assert.Equal(t, tt.text, data[span.Start.Byte:span.End.Byte])
+		})
+	}
+}
+
+func TestLocationTracking_CellErrors(t *testing.T) {
+	s := loadClinic(t)
+
+	tests := []struct {
+		name string
+		data string
+		path string
+		text string
+	}{
+		{"plain cell", "id,name,age\np1,Alice,old\n", "$[0].age", "old"},
+		{"quoted cell", "id,age,name\np1,\"forty two\",Alice\n", "$[0].age", "\"forty two\""},
+		{"after byte order mark", "\ufeffid,age\np1,x\n", "$[0].age", "x"},
+		{"target cell", "id,ward._target_building,ward._target_opened\np1,North,top\n", "$[0].WARD", "top"},
+		{"header cell", "id,Height\np1,2\n", "", "Height"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter, sourceID := trackingAdapter(t, "test://patients.csv", tt.data)
+			_, diags := adapter.ParseType(sourceID, s, "Patient", []byte(tt.data))
+			require.False(t, diags.OK())
+			issue := diags.IssuesSlice()[0]
+			assert.Equal(t, tt.path, issue.Path())
+			span := issue.Span()
+			require.False(t, span.IsZero(), "issue should carry a span")
+			assert.Equal(t, tt.text, tt.data[span.Start.Byte:span.End.Byte])
+		})
+	}
+}
+
+func TestParseType_Union(t *testing.T) {
+	s, result, err := load.LoadString(t.Context(), `schema "u"
+type Level = Integer | Boolean
+type Note = String | Integer
+type Reading {
+    id String primary
+    value Level
+    note Note
+}`, "u")
+	require.NoError(t, err)
+	require.True(t, result.OK(), "schema errors: %v", result.Messages())
+
+	adapter, _ := NewAdapter(nil)
+	parsed, diags := adapter.ParseType(location.NewSourceID("test://r.csv"), s, "Reading", []byte("id,value,note\nr1,true,7\n"))
+	require.True(t, diags.OK(), "unexpected errors: %v", diags.Messages())
+	assert.Equal(t, true, parsed[0].Properties["value"])
+	assert.Equal(t, "7", parsed[0].Properties["note"], "members convert in declared order")
+
+	_, diags = adapter.ParseType(location.NewSourceID("test://r.csv"), s, "Reading", []byte("id,value\nr1,maybe\n"))
+	require.False(t, diags.OK())
+	assert.Contains(t, diags.IssuesSlice()[0].Message(), "matches no member")
+}
@@ -0,0 +1,157 @@
+package csv
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/simon-lentz/yammm/schema"
+)
+
+// fkPrefix is the prefix of association target columns, matching the
+// _target_<pk> fields of edge objects in instance data.
+const fkPrefix = "_target_"
+
+// column describes what a header cell maps to: a property, or one primary
+// key component of an association's target.
+type column struct {
+	header string
+	prop   *schema.Property // property column; nil for target columns
+	rel    *schema.Relation // association of a target column
+	target *schema.Property // target primary key component of rel
+
+	pkIndex int // position of target among the target type's primary keys
+}
+
+// resolveColumn maps a header cell of a file of typ's instances. It returns
+// an error describing why the header matches nothing.
+func (a *Adapter) resolveColumn(s *schema.Schema, typ *schema.Type, header string) (column, error) {
+	name := header
+	if mapped, ok := a.columns[header]; ok {
+		name = mapped
+	}
+
+	if field, pk, ok := splitTargetColumn(name); ok {
+		rel, err := findAssociation(typ, field)
+		if err != nil {
+			return column{}, err
+		}
+		target, ok := targetType(s, rel)
+		if !ok {
+			return column{}, fmt.Errorf("target type %s of association %s is not available", rel.Target(), rel.FieldName())
+		}
+		prop, ok := target.Property(pk)
+		if !ok || !prop.IsPrimaryKey() {
+			var pks []string
+			for _, p := range target.PrimaryKeysSlice() {
+				pks = append(pks, p.Name())
+			}
+			return column{}, unknownName(pk, pks, "%s is not a primary key of %s", pk, target.Name())
+		}
+		return column{header: header, rel: rel, target: prop}, nil
+	}
+
+	if prop, ok := typ.Property(name); ok {
+		return column{header: header, prop: prop}, nil
+	}
+	var match *schema.Property
+	for prop := range typ.AllProperties() {
+		if strings.EqualFold(prop.Name(), name) {
+			if match != nil {
+				return column{}, fmt.Errorf("column %q matches both %s and %s", header, match.Name(), prop.Name())
+			}
+			match = prop
+		}
+	}
+	if match == nil {
+		return column{}, unknownName(name, columnNames(s, typ), "type %s has no property %q", typ.Name(), name)
+	}
+	return column{header: header, prop: match}, nil
+}
+
+// splitTargetColumn splits a target column name, `field._target_<pk>` or a
+// bare `_target_<pk>`, into the association field (empty if bare) and the
+// primary key name.
+func splitTargetColumn(name string) (field, pk string, ok bool) {
+	if rest, found := strings.CutPrefix(name, fkPrefix); found {
+		return "", rest, rest != ""
+	}
+	field, rest, found := strings.Cut(name, "."+fkPrefix)
+	if !found || field == "" || rest == "" {
+		return "", "", false
+	}
+	return field, rest, true
+}
+
+// findAssociation returns the association of typ with the given field name.
+// An empty field selects the type's only association.
+func findAssociation(typ *schema.Type, field string) (*schema.Relation, error) {
+	assocs := typ.AllAssociationsSlice()
+	if field == "" {
+		if len(assocs) != 1 {
+			return nil, fmt.Errorf("type %s has %d associations; qualify the column as <field>.%s<pk>",
+				typ.Name(), len(assocs), fkPrefix)
+		}
+		return assocs[0], nil
+	}
+	for _, rel := range assocs {
+		if rel.FieldName() == field {
+			return rel, nil
+		}
+	}
+	for _, rel := range assocs {
+		if strings.EqualFold(rel.FieldName(), field) {
+			return rel, nil
+		}
+	}
+	var fields []string
+	for _, rel := range assocs {
+		fields = append(fields, rel.FieldName())
+	}
+	return nil, unknownName(field, fields, "type %s has no association %q", typ.Name(), field)
+}
+
+// columnNames returns the canonical headers of the columns a file of typ's
+// instances can have: its properties and its association target columns.
+func columnNames(s *schema.Schema, typ *schema.Type) []string {
+	var names []string
+	for prop := range typ.AllProperties() {
+		names = append(names, prop.Name())
+	}
+	for _, rel := range typ.AllAssociationsSlice() {
+		target, ok := targetType(s, rel)
+		if !ok {
+			continue
+		}
+		for _, pk := range target.PrimaryKeysSlice() {
+			names = append(names, targetColumnName(rel, pk))
+		}
+	}
+	return names
+}
+
+// targetType returns the target type of an association, which may be
+// declared in an imported schema.
+func targetType(s *schema.Schema, rel *schema.Relation) (*schema.Type, bool) {
+	if t, ok := lookupType(s, rel.TargetID()); ok {
+		return t, true
+	}
+	return s.ResolveType(rel.Target())
+}
+
+// lookupType resolves a TypeID to its schema.Type by checking local types and imports.
+func lookupType(s *schema.Schema, id schema.TypeID) (*schema.Type, bool) {
+	if id.SchemaPath() == s.SourceID() {
+		return s.Type(id.Name())
+	}
+	for imp := range s.Imports() {
+		if imp.Schema() != nil && imp.Schema().SourceID() == id.SchemaPath() {
+			return imp.Schema().Type(id.Name())
+		}
+	}
+	return nil, false
+}
+
+// targetColumnName returns the canonical header of a target column.
+func targetColumnName(rel *schema.Relation, pk *schema.Property) string {
+	return rel.FieldName() + "." + fkPrefix + pk.Name()
+}
@@ -0,0 +1,157 @@
+package csv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/simon-lentz/yammm/schema"
+)
+
+// convert converts the text of a non-empty cell to a value for constraint c.
+//
+// Integer, Float and Boolean cells are parsed; Enum cells are matched to the
+// declared values (case-insensitively if the match is unique); Date and
+// Timestamp cells are checked against their layout and kept as text; List,
+// Set and Vector cells are split on the list delimiter; Map and Object cells
+// hold JSON. Union cells take the first member that converts. Other cells
+// are kept as text for instance validation to check.
+func (a *Adapter) convert(text string, c schema.Constraint) (any, error) {
+	switch nc := resolveAlias(c).(type) {
+	case schema.IntegerConstraint:
+		v, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected integer, got %q", text)
+		}
+		return v, nil
+	case schema.FloatConstraint:
+		v, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected float, got %q", text)
+		}
+		return v, nil
+	case schema.BooleanConstraint:
+		v, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("expected boolean, got %q", text)
+		}
+		return v, nil
+	case schema.EnumConstraint:
+		return matchEnum(text, nc.Values())
+	case schema.DateConstraint:
+		return checkLayout(text, nc.Layout(), "date")
+	case schema.TimestampConstraint:
+		return checkLayout(text, nc.Layout(), "timestamp")
+	case schema.ListConstraint:
+		if _, nested := resolveAlias(nc.Element()).(schema.ListConstraint); nested {
+			return nil, errors.New("nested lists are not supported in CSV cells")
+		}
+		return a.convertElements(text, nc.Element())
+	case schema.VectorConstraint:
+		return a.convertElements(text, schema.FloatConstraint{})
+	case schema.MapConstraint, schema.ObjectConstraint:
+		return decodeJSON(text)
+	case schema.UnionConstraint:
+		for _, member := range nc.Members() {
+			if v, err := a.convert(text, member); err == nil {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("%q matches no member of %s", text, nc)
+	default:
+		return text, nil
+	}
+}
+
+// convertElements splits a list cell on the list delimiter and converts each
+// element, with surrounding whitespace removed.
+func (a *Adapter) convertElements(text string, elem schema.Constraint) ([]any, error) {
+	parts := strings.Split(text, a.listDelimiter)
+	result := make([]any, 0, len(parts))
+	for i, part := range parts {
+		v, err := a.convert(strings.TrimSpace(part), elem)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// resolveAlias follows alias constraints to the datatype they name.
+func resolveAlias(c schema.Constraint) schema.Constraint {
+	for {
+		alias, ok := c.(schema.AliasConstraint)
+		if !ok || alias.Resolved() == nil {
+			return c
+		}
+		c = alias.Resolved()
+	}
+}
+
+// matchEnum returns the declared enum value matching text, exactly or, if
+// only one value matches, ignoring case.
+func matchEnum(text string, values []string) (string, error) {
+	var folded []string
+	for _, v := range values {
+		if v == text {
+			return v, nil
+		}
+		if strings.EqualFold(v, text) {
+			folded = append(folded, v)
+		}
+	}
+	if len(folded) == 1 {
+		return folded[0], nil
+	}
+	return "", unknownName(text, values, "expected one of %s, got %q", strings.Join(values, ", "), text)
+}
+
+// checkLayout reports whether text parses with layout; the text itself is
+// the value.
+func checkLayout(text, layout, what string) (string, error) {
+	if _, err := time.Parse(layout, text); err != nil {
+		return "", fmt.Errorf("expected %s in layout %q, got %q", what, layout, text)
+	}
+	return text, nil
+}
+
+// decodeJSON decodes a JSON cell, converting numbers to int64 where exact
+// and float64 otherwise.
+func decodeJSON(text string) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(text)))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("expected JSON value: %v", err)
+	}
+	if dec.More() {
+		return nil, errors.New("expected a single JSON value")
+	}
+	return normalizeNumbers(v), nil
+}
+
+// normalizeNumbers replaces json.Number values in v.
+func normalizeNumbers(v any) any {
+	switch vv := v.(type) {
+	case json.Number:
+		if i, err := vv.Int64(); err == nil {
+			return i
+		}
+		f, _ := vv.Float64()
+		return f
+	case map[string]any:
+		for k, elem := range vv {
+			vv[k] = normalizeNumbers(elem)
+		}
+	case []any:
+		for i, elem := range vv {
+			vv[i] = normalizeNumbers(elem)
+		}
+	}
+	return v
+}
@@ -0,0 +1,63 @@
+// Package csv provides a CSV/TSV adapter for parsing tabular instance data
+// into [instance.RawInstance] values with optional source location tracking,
+// and for writing the instances of a [graph.Result] back to CSV.
+//
+// # Tables
+//
+// A file holds the instances of one type: the first record is the header
+// and every further record is one instance. [Adapter.ParseType] maps each
+// header cell onto the type:
+//
+//   - A property name, matched exactly or, if unique, ignoring case.
+//   - An association target column, `field._target_<pk>`, holding the
+//     primary key component pk of the target of association field. A type
+//     with exactly one association may use the bare `_target_<pk>`.
+//
+// [WithColumn] maps headers that do not match the schema. A header cell that
+// maps to nothing, or to the same property as another cell, is an error.
+// Compositions and edge properties cannot be expressed in a table.
+//
+// # Cell Conversion
+//
+// Cells are converted using the constraint of their property:
+//
+//	Integer, Float        parsed as numbers (surrounding whitespace ignored)
+//	Boolean               parsed by strconv.ParseBool (true, FALSE, 1, ...)
+//	Enum                  a declared value, case-insensitively if unique
+//	Date, Timestamp       checked against the constraint's layout, kept as text
+//	List, Set, Vector     split on the list delimiter (default ";")
+//	Map, Object           a JSON value
+//	union                 the first member that converts
+//	other                 the text, checked by instance validation
+//
+// Empty cells are absent properties; CSV cannot tell an empty string from a
+// missing value. The targets of a many-association are separated by the
+// list delimiter, and composite keys list their components in parallel
+// columns. A UTF-8 byte order mark at the start of the data is skipped.
+//
+// # Location Tracking
+//
+// When WithTrackLocations(true) is set, each RawInstance's provenance spans
+// its row and has the path `$[i]`, where i counts data rows from zero. A cell
+// that cannot be converted is reported as E_TYPE_MISMATCH with a span
+// covering the cell, so rendered excerpts point at it; its row is dropped.
+// Unknown header cells are reported as E_UNKNOWN_FIELD, and malformed
+// records as E_ADAPTER_PARSE. Unknown type names, header cells and enum
+// values carry a "did you mean" hint when a declared name is close.
+//
+// As with the other adapters, the adapter is a read-only consumer of the
+// registry's PositionAt method. Register the CSV content with the registry
+// before parsing to include source excerpts in diagnostics.
+//
+// # Writing
+//
+// [Adapter.MarshalType] and [Adapter.WriteType] write the instances of one
+// type in the format ParseType reads, using the same separator, list
+// delimiter and column mappings. Set elements are written in canonical order
+// so output does not depend on input order.
+//
+// # Thread Safety
+//
+// Adapter is safe for concurrent use after construction. Each call
+// maintains its own state; no shared mutable state exists.
+package csv
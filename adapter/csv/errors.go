@@ -0,0 +1,50 @@
+package csv
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/simon-lentz/yammm/internal/ident"
+)
+
+// ErrNilRegistry is returned when WithTrackLocations(true) is set but no registry was provided.
+var ErrNilRegistry = errors.New("csv adapter: WithTrackLocations(true) requires a non-nil PositionRegistry")
+
+// ErrInvalidComma is returned when WithComma is called with a rune that cannot separate fields.
+var ErrInvalidComma = errors.New("csv adapter: WithComma requires a valid separator rune")
+
+// ErrInvalidListDelimiter is returned when WithListDelimiter is called with an
+// empty delimiter or one equal to the field separator.
+var ErrInvalidListDelimiter = errors.New("csv adapter: WithListDelimiter requires a non-empty delimiter distinct from the field separator")
+
+// ErrNilResult is returned when MarshalType or WriteType is called with a nil graph result.
+var ErrNilResult = errors.New("csv adapter: nil graph result")
+
+// ErrUnknownType is returned when MarshalType or WriteType is called with a
+// type name the result's schema does not define.
+var ErrUnknownType = errors.New("csv adapter: unknown type")
+
+// unknownNameError reports input naming something not in scope (a property,
+// association, primary key or enum value), with the in-scope name it was
+// most likely meant to be.
+type unknownNameError struct {
+	msg        string
+	suggestion string
+}
+
+func (e *unknownNameError) Error() string { return e.msg }
+
+// unknownName returns an unknownNameError for name, suggesting the closest
+// of candidates.
+func unknownName(name string, candidates []string, format string, args ...any) error {
+	suggestion, _ := ident.Suggest(name, candidates)
+	return &unknownNameError{msg: fmt.Sprintf(format, args...), suggestion: suggestion}
+}
+
+// errorSuggestion returns the suggestion carried by err, or "" if there is none.
+func errorSuggestion(err error) string {
+	if uerr, ok := errors.AsType[*unknownNameError](err); ok {
+		return uerr.suggestion
+	}
+	return ""
+}
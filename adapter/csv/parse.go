@@ -0,0 +1,394 @@
+package csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/instance"
+	"github.com/simon-lentz/yammm/instance/path"
+	"github.com/simon-lentz/yammm/internal/ident"
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema"
+)
+
+// utf8BOM is the byte order mark spreadsheet exports often start with.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ParseType parses CSV data holding instances of the named type of s.
+//
+// The first record is the header. Each header cell names a property of the
+// type (exactly, or ignoring case if the match is unique), an association
+// target column `field._target_<pk>`, or a bare `_target_<pk>` if the type
+// has exactly one association; WithColumn maps other headers. Every further
+// record becomes one RawInstance. Empty cells are absent properties.
+//
+// Cells are converted using the constraint of their property. A cell that
+// cannot be converted is reported with a span covering the cell, and its row
+// is dropped. Returns a slice of RawInstance.
+//
+// Example input (with typeName="Patient"):
+//
+//	id,name,born,tags,physician._target_id
+//	p1,Alice,1990-04-01,vip;allergy,d7
+//	p2,Bob,1985-11-23,,d7
+func (a *Adapter) ParseType(source location.SourceID, s *schema.Schema, typeName string, data []byte) ([]instance.RawInstance, diag.Result) {
+	p := a.newParser(source, data)
+
+	typ, ok := s.Type(typeName)
+	if !ok {
+		suggestion, _ := ident.Suggest(typeName, s.TypeNames())
+		ib := diag.NewIssue(diag.Error, diag.E_INSTANCE_TYPE_NOT_FOUND,
+			fmt.Sprintf("type %q not found in schema", typeName)).
+			WithDetail(diag.DetailKeyTypeName, typeName).
+			WithSuggestion(suggestion)
+		p.withSpan(ib, 0, 0)
+		p.issues = append(p.issues, ib.Build())
+		return nil, p.result()
+	}
+
+	r := csv.NewReader(bytes.NewReader(p.data[p.base:]))
+	r.Comma = a.comma
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, p.result()
+	}
+	if err != nil {
+		p.readError(err)
+		return nil, p.result()
+	}
+	cols, ok := p.columns(r, s, typ, header)
+	if !ok {
+		return nil, p.result()
+	}
+
+	var result []instance.RawInstance
+	for idx := 0; ; idx++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			p.readError(err)
+			if errors.Is(err, csv.ErrFieldCount) {
+				continue
+			}
+			break
+		}
+		if raw, ok := p.row(r, cols, record, path.Root().Index(idx)); ok {
+			result = append(result, raw)
+		}
+	}
+
+	return result, p.result()
+}
+
+// parser holds the state of one ParseType call.
+type parser struct {
+	a      *Adapter
+	source location.SourceID
+	data   []byte
+	base   int // length of a skipped byte order mark
+	issues []diag.Issue
+
+	lineStarts []int // byte offset of each line after base
+}
+
+func (a *Adapter) newParser(source location.SourceID, data []byte) *parser {
+	p := &parser{a: a, source: source, data: data}
+	if bytes.HasPrefix(data, utf8BOM) {
+		p.base = len(utf8BOM)
+	}
+	p.lineStarts = append(p.lineStarts, p.base)
+	for i := p.base; i < len(data); i++ {
+		if data[i] == '\n' {
+			p.lineStarts = append(p.lineStarts, i+1)
+		}
+	}
+	return p
+}
+
+// columns resolves the header. It reports every header cell that maps to
+// nothing or to the same property or target as an earlier cell.
+func (p *parser) columns(r *csv.Reader, s *schema.Schema, typ *schema.Type, header []string) ([]column, bool) {
+	cols := make([]column, len(header))
+	seen := make(map[string]string) // property or target column -> header
+	ok := true
+	for i, h := range header {
+		col, err := p.a.resolveColumn(s, typ, h)
+		if err == nil {
+			key := col.key()
+			if prev, dup := seen[key]; dup {
+				err = fmt.Errorf("columns %q and %q both map to %s", prev, h, key)
+			}
+			seen[key] = h
+		}
+		if err != nil {
+			start, end := p.fieldSpan(r, i)
+			ib := diag.NewIssue(diag.Error, diag.E_UNKNOWN_FIELD, fmt.Sprintf("column %q: %v", h, err)).
+				WithDetail(diag.DetailKeyFormat, "csv").
+				WithDetail(diag.DetailKeyTypeName, typ.Name()).
+				WithSuggestion(errorSuggestion(err))
+			p.withSpan(ib, start, end)
+			p.issues = append(p.issues, ib.Build())
+			ok = false
+			continue
+		}
+		cols[i] = col
+	}
+	return cols, ok
+}
+
+// key returns the property or target column a column maps to.
+func (c column) key() string {
+	if c.rel != nil {
+		return targetColumnName(c.rel, c.target)
+	}
+	return c.prop.Name()
+}
+
+// row converts one record to a RawInstance at path at.
+func (p *parser) row(r *csv.Reader, cols []column, record []string, at path.Builder) (instance.RawInstance, bool) {
+	props := make(map[string]any, len(record))
+	targets := make(map[*schema.Relation]*edgeColumns)
+	var relOrder []*schema.Relation
+	ok := true
+
+	for i, text := range record {
+		if text == "" {
+			continue
+		}
+		col := cols[i]
+		if col.rel == nil {
+			v, err := p.a.convert(text, col.prop.Constraint())
+			if err != nil {
+				p.cellError(r, i, at.Key(col.prop.Name()), col.header, col.prop.Constraint().String(), err)
+				ok = false
+				continue
+			}
+			props[col.prop.Name()] = v
+			continue
+		}
+
+		parts := []string{text}
+		if col.rel.IsMany() {
+			parts = splitTrimmed(text, p.a.listDelimiter)
+		}
+		values, err := p.a.convertTargets(parts, col.target.Constraint())
+		if err != nil {
+			p.cellError(r, i, at.Key(col.rel.Name()), col.header, col.target.Constraint().String(), err)
+			ok = false
+			continue
+		}
+		ec := targets[col.rel]
+		if ec == nil {
+			ec = &edgeColumns{values: make(map[string][]any)}
+			targets[col.rel] = ec
+			relOrder = append(relOrder, col.rel)
+		}
+		ec.values[fkPrefix+col.target.Name()] = values
+		ec.cells = append(ec.cells, i)
+	}
+
+	for _, rel := range relOrder {
+		if !ok {
+			break
+		}
+		ec := targets[rel]
+		edge, err := ec.build(rel)
+		if err != nil {
+			p.cellError(r, ec.cells[0], at.Key(rel.Name()), rel.FieldName(), "", err)
+			ok = false
+			continue
+		}
+		props[rel.FieldName()] = edge
+	}
+
+	if !ok {
+		return instance.RawInstance{}, false
+	}
+	raw := instance.RawInstance{Properties: props}
+	if p.a.trackLocations && p.a.registry != nil {
+		start, _ := p.fieldSpan(r, 0)
+		_, end := p.fieldSpan(r, len(record)-1)
+		raw.Provenance = p.makeProvenance(at, start, end)
+	}
+	return raw, true
+}
+
+// convertTargets converts the primary key values of association targets.
+func (a *Adapter) convertTargets(parts []string, c schema.Constraint) ([]any, error) {
+	values := make([]any, len(parts))
+	for i, part := range parts {
+		v, err := a.convert(part, c)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// edgeColumns collects the target column values of one association in a row.
+type edgeColumns struct {
+	values map[string][]any // _target_<pk> -> one value per target
+	cells  []int            // record indices of the columns
+}
+
+// build returns the edge value of the association: an edge object for a
+// one-association, or a list of them for a many-association. Every target
+// column of a many-association must list the same number of targets.
+func (ec *edgeColumns) build(rel *schema.Relation) (any, error) {
+	n := -1
+	for _, field := range slices.Sorted(maps.Keys(ec.values)) {
+		values := ec.values[field]
+		if n >= 0 && len(values) != n {
+			return nil, fmt.Errorf("target columns of %s list different numbers of targets (%s has %d, expected %d)",
+				rel.FieldName(), field, len(values), n)
+		}
+		n = len(values)
+	}
+	edges := make([]any, n)
+	for i := range edges {
+		edge := make(map[string]any, len(ec.values))
+		for field, values := range ec.values {
+			edge[field] = values[i]
+		}
+		edges[i] = edge
+	}
+	if !rel.IsMany() {
+		return edges[0], nil
+	}
+	return edges, nil
+}
+
+// splitTrimmed splits text on delim and trims whitespace around each part.
+func splitTrimmed(text, delim string) []string {
+	parts := strings.Split(text, delim)
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	return parts
+}
+
+// fieldSpan returns the byte offsets of the start and end of field i of the
+// record most recently read from r.
+func (p *parser) fieldSpan(r *csv.Reader, i int) (int, int) {
+	line, col := r.FieldPos(i)
+	if line < 1 || line > len(p.lineStarts) {
+		return 0, 0
+	}
+	start := p.lineStarts[line-1] + col - 1
+	return start, p.fieldEnd(start)
+}
+
+// fieldEnd returns the offset just past the field starting at start: past
+// the closing quote of a quoted field, or at the next separator or line
+// break otherwise.
+func (p *parser) fieldEnd(start int) int {
+	data := p.data
+	if start < len(data) && data[start] == '"' {
+		for i := start + 1; i < len(data); i++ {
+			if data[i] != '"' {
+				continue
+			}
+			if i+1 < len(data) && data[i+1] == '"' {
+				i++
+				continue
+			}
+			return i + 1
+		}
+		return len(data)
+	}
+	for i := start; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == p.a.comma || r == '\n' || r == '\r' {
+			return i
+		}
+		i += size
+	}
+	return len(data)
+}
+
+// readError records a record syntax error reported by encoding/csv.
+func (p *parser) readError(err error) {
+	offset := 0
+	var perr *csv.ParseError
+	if errors.As(err, &perr) {
+		if perr.Line >= 1 && perr.Line <= len(p.lineStarts) {
+			offset = p.lineStarts[perr.Line-1] + max(0, perr.Column-1)
+		}
+		err = perr.Err
+	}
+	msg := "invalid CSV"
+	if errors.Is(err, csv.ErrFieldCount) {
+		msg = "row does not match header"
+	}
+	ib := diag.NewIssue(diag.Error, diag.E_ADAPTER_PARSE, msg).
+		WithDetail(diag.DetailKeyFormat, "csv").
+		WithDetail(diag.DetailKeyDetail, err.Error())
+	p.withSpan(ib, offset, offset)
+	p.issues = append(p.issues, ib.Build())
+}
+
+// cellError records an E_TYPE_MISMATCH issue for a cell that could not be
+// converted.
+func (p *parser) cellError(r *csv.Reader, i int, at path.Builder, header, expected string, err error) {
+	ib := diag.NewIssue(diag.Error, diag.E_TYPE_MISMATCH, fmt.Sprintf("column %q: %v", header, err)).
+		WithPath(p.source.String(), at.String()).
+		WithDetail(diag.DetailKeyFormat, "csv").
+		WithSuggestion(errorSuggestion(err))
+	if expected != "" {
+		ib.WithDetail(diag.DetailKeyExpected, expected)
+	}
+	start, end := p.fieldSpan(r, i)
+	p.withSpan(ib, start, end)
+	p.issues = append(p.issues, ib.Build())
+}
+
+// withSpan attaches the span between two offsets when locations are tracked.
+func (p *parser) withSpan(ib *diag.IssueBuilder, start, end int) {
+	if !p.a.trackLocations || p.a.registry == nil {
+		return
+	}
+	startPos := p.a.registry.PositionAt(p.source, start)
+	endPos := p.a.registry.PositionAt(p.source, end)
+	// Guard: only attach span if positions are valid
+	if !startPos.IsZero() && !endPos.IsZero() {
+		ib.WithSpan(location.Span{Source: p.source, Start: startPos, End: endPos})
+	}
+}
+
+// makeProvenance creates a Provenance from byte offsets.
+// If positions cannot be determined (IsZero), the span preserves only the source identity.
+func (p *parser) makeProvenance(at path.Builder, startOffset, endOffset int) *instance.Provenance {
+	startPos := p.a.registry.PositionAt(p.source, startOffset)
+	endPos := p.a.registry.PositionAt(p.source, endOffset)
+
+	var span location.Span
+	if !startPos.IsZero() && !endPos.IsZero() {
+		span = location.Span{Source: p.source, Start: startPos, End: endPos}
+	} else {
+		// Preserve source identity for diagnostics even without precise positions
+		span = location.Span{Source: p.source}
+	}
+
+	return instance.NewProvenance(p.source.String(), at, span)
+}
+
+// result returns the collected issues as a diag.Result.
+func (p *parser) result() diag.Result {
+	collector := diag.NewCollectorUnlimited()
+	for i := range p.issues {
+		collector.Collect(p.issues[i])
+	}
+	return collector.Result()
+}
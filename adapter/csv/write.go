@@ -0,0 +1,287 @@
+package csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/simon-lentz/yammm/graph"
+	"github.com/simon-lentz/yammm/immutable"
+	"github.com/simon-lentz/yammm/internal/value"
+	"github.com/simon-lentz/yammm/schema"
+)
+
+// MarshalType serializes the instances of one type of a graph.Result to CSV
+// bytes, in the format ParseType reads.
+//
+// The header holds one column per property of the type, in schema order,
+// followed by one `field._target_<pk>` column per primary key component of
+// each association target. Absent values are empty cells. List, Set and
+// Vector values are joined with the list delimiter, set elements in
+// canonical order; Map and Object values are written as JSON. The targets
+// of a many-association are joined with the list delimiter. Composed
+// children are not written.
+//
+// Returns ErrNilResult if result is nil and ErrUnknownType if the result's
+// schema has no type typeName. Returns an error if a list element or target
+// key contains the list delimiter, since the cell could not be read back.
+func (a *Adapter) MarshalType(result *graph.Result, typeName string) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := a.WriteType(&buf, result, typeName); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteType writes the instances of one type of a graph.Result to an
+// io.Writer in CSV format.
+//
+// See MarshalType for output format details.
+//
+// Returns the number of bytes written and ErrNilResult if result is nil.
+func (a *Adapter) WriteType(w io.Writer, result *graph.Result, typeName string) (int64, error) {
+	if result == nil {
+		return 0, ErrNilResult
+	}
+	instances := result.InstancesOf(typeName)
+	typ, ok := a.writeType(result.Schema(), typeName, instances)
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrUnknownType, typeName)
+	}
+
+	cols := a.writeColumns(result.Schema(), typ)
+	edges := edgesBySource(result.Edges(), typ)
+
+	cw := &countingWriter{w: w}
+	out := csv.NewWriter(cw)
+	out.Comma = a.comma
+
+	header := make([]string, len(cols))
+	headers := a.headers()
+	for i, col := range cols {
+		header[i] = col.key()
+		if h, ok := headers[header[i]]; ok {
+			header[i] = h
+		}
+	}
+	if err := out.Write(header); err != nil {
+		return cw.n, err
+	}
+
+	record := make([]string, len(cols))
+	for _, inst := range instances {
+		for i, col := range cols {
+			var cell string
+			var err error
+			if col.rel == nil {
+				cell, err = a.propertyCell(inst, col.prop)
+			} else {
+				cell, err = a.targetCell(edges[inst.PrimaryKey().String()][col.rel.Name()], col)
+			}
+			if err != nil {
+				return cw.n, fmt.Errorf("csv adapter: %s %s: %w", typeName, inst.PrimaryKey(), err)
+			}
+			record[i] = cell
+		}
+		if err := out.Write(record); err != nil {
+			return cw.n, err
+		}
+	}
+
+	out.Flush()
+	return cw.n, out.Error()
+}
+
+// writeType returns the schema type of the instances being written.
+func (a *Adapter) writeType(s *schema.Schema, typeName string, instances []*graph.Instance) (*schema.Type, bool) {
+	if s == nil {
+		return nil, false
+	}
+	if len(instances) > 0 {
+		return lookupType(s, instances[0].TypeID())
+	}
+	return s.Type(typeName)
+}
+
+// writeColumns returns the columns written for typ.
+func (a *Adapter) writeColumns(s *schema.Schema, typ *schema.Type) []column {
+	var cols []column
+	for prop := range typ.AllProperties() {
+		cols = append(cols, column{prop: prop})
+	}
+	for rel := range typ.AllAssociations() {
+		target, ok := targetType(s, rel)
+		if !ok {
+			continue
+		}
+		for i, pk := range target.PrimaryKeysSlice() {
+			cols = append(cols, column{rel: rel, target: pk, pkIndex: i})
+		}
+	}
+	return cols
+}
+
+// headers returns the WithColumn mappings in reverse: property or target
+// column -> header. If several headers map to one name, the first in
+// lexicographic order is used.
+func (a *Adapter) headers() map[string]string {
+	result := make(map[string]string, len(a.columns))
+	for header, name := range a.columns {
+		if prev, ok := result[name]; !ok || header < prev {
+			result[name] = header
+		}
+	}
+	return result
+}
+
+// edgesBySource indexes the edges from instances of typ by source primary
+// key and relation name.
+func edgesBySource(edges []*graph.Edge, typ *schema.Type) map[string]map[string][]*graph.Edge {
+	idx := make(map[string]map[string][]*graph.Edge)
+	for _, e := range edges {
+		if e.Source().TypeID() != typ.ID() {
+			continue
+		}
+		pk := e.Source().PrimaryKey().String()
+		if idx[pk] == nil {
+			idx[pk] = make(map[string][]*graph.Edge)
+		}
+		idx[pk][e.Relation()] = append(idx[pk][e.Relation()], e)
+	}
+	for _, byRel := range idx {
+		for _, es := range byRel {
+			// Sort edges by target PK for deterministic output
+			slices.SortFunc(es, func(a, b *graph.Edge) int {
+				return strings.Compare(a.Target().PrimaryKey().String(), b.Target().PrimaryKey().String())
+			})
+		}
+	}
+	return idx
+}
+
+// propertyCell formats the value of prop on inst.
+func (a *Adapter) propertyCell(inst *graph.Instance, prop *schema.Property) (string, error) {
+	v, ok := inst.Property(prop.Name())
+	if !ok || v.IsNil() {
+		return "", nil
+	}
+	return a.format(unwrapValue(v), prop.Constraint())
+}
+
+// targetCell formats one primary key component of the targets of edges.
+func (a *Adapter) targetCell(edges []*graph.Edge, col column) (string, error) {
+	parts := make([]string, 0, len(edges))
+	for _, e := range edges {
+		key := e.Target().PrimaryKey()
+		if col.pkIndex >= key.Len() {
+			continue
+		}
+		text, err := a.format(unwrapValue(key.Get(col.pkIndex)), col.target.Constraint())
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(text, a.listDelimiter) && col.rel.IsMany() {
+			return "", fmt.Errorf("target key %q of %s contains the list delimiter %q", text, col.rel.FieldName(), a.listDelimiter)
+		}
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, a.listDelimiter), nil
+}
+
+// format converts a value of constraint c to cell text.
+func (a *Adapter) format(v any, c schema.Constraint) (string, error) {
+	switch vv := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return vv, nil
+	case bool:
+		return strconv.FormatBool(vv), nil
+	case int64:
+		return strconv.FormatInt(vv, 10), nil
+	case float64:
+		return strconv.FormatFloat(vv, 'g', -1, 64), nil
+	case time.Time:
+		return vv.Format(timeLayout(c)), nil
+	case []any:
+		var elem schema.Constraint = schema.FloatConstraint{}
+		if list, ok := resolveAlias(c).(schema.ListConstraint); ok {
+			elem = list.Element()
+			if list.IsUnique() {
+				slices.SortStableFunc(vv, value.Compare)
+			}
+		}
+		parts := make([]string, len(vv))
+		for i, e := range vv {
+			text, err := a.format(e, elem)
+			if err != nil {
+				return "", err
+			}
+			if strings.Contains(text, a.listDelimiter) {
+				return "", fmt.Errorf("list element %q contains the list delimiter %q", text, a.listDelimiter)
+			}
+			parts[i] = text
+		}
+		return strings.Join(parts, a.listDelimiter), nil
+	case map[string]any:
+		data, err := json.Marshal(vv)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	default:
+		return fmt.Sprint(vv), nil
+	}
+}
+
+// timeLayout returns the layout of a Date or Timestamp constraint, or
+// RFC 3339 for any other constraint.
+func timeLayout(c schema.Constraint) string {
+	switch nc := resolveAlias(c).(type) {
+	case schema.DateConstraint:
+		return nc.Layout()
+	case schema.TimestampConstraint:
+		return nc.Layout()
+	}
+	return time.RFC3339
+}
+
+// unwrapValue recursively converts an immutable.Value to a plain Go value.
+func unwrapValue(v immutable.Value) any {
+	if v.IsNil() {
+		return nil
+	}
+	if m, ok := v.Map(); ok {
+		result := make(map[string]any, m.Len())
+		for k, val := range m.Range() {
+			result[k] = unwrapValue(val)
+		}
+		return result
+	}
+	if s, ok := v.Slice(); ok {
+		result := make([]any, s.Len())
+		for i, val := range s.Iter2() {
+			result[i] = unwrapValue(val)
+		}
+		return result
+	}
+	return v.Unwrap()
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
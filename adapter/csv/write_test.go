@@ -0,0 +1,118 @@
+package csv
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/simon-lentz/yammm/graph"
+	"github.com/simon-lentz/yammm/instance"
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema"
+)
+
+// buildGraph parses one CSV table per type, validates the instances and adds
+// them to a graph.
+func buildGraph(t *testing.T, adapter *Adapter, s *schema.Schema, tables map[string]string) *graph.Result {
+	t.Helper()
+	ctx := t.Context()
+	validator := instance.NewValidator(s)
+	g := graph.New(s)
+	for _, typeName := range []string{"Physician", "Ward", "Patient"} {
+		data, ok := tables[typeName]
+		if !ok {
+			continue
+		}
+		raws, diags := adapter.ParseType(location.NewSourceID("test://"+typeName+".csv"), s, typeName, []byte(data))
+		require.True(t, diags.OK(), "parse %s: %v", typeName, diags.Messages())
+		valid, failures, err := validator.Validate(ctx, typeName, raws)
+		require.NoError(t, err)
+		require.Empty(t, failures, "validate %s", typeName)
+		for _, inst := range valid {
+			result, err := g.Add(ctx, inst)
+			require.NoError(t, err)
+			require.True(t, result.OK(), "add %s: %v", typeName, result.Messages())
+		}
+	}
+	return g.Snapshot()
+}
+
+var clinicTables = map[string]string{
+	"Physician": "id,name\nd1,Grey\nd2,House\nd7,Who\n",
+	"Ward":      "building,opened\nNorth,2001-09-01\n",
+	"Patient": "id,name,age,tags,meta,physician._target_id,consultants._target_id,ward._target_building,ward._target_opened\n" +
+		"p1,Alice,42,vip;allergy,\"{\"\"visits\"\":3}\",d7,d2;d1,North,2001-09-01\n" +
+		"p2,\"Bob, Jr.\",,,,,,,\n",
+}
+
+func TestMarshalType(t *testing.T) {
+	s := loadClinic(t)
+	adapter, err := NewAdapter(nil)
+	require.NoError(t, err)
+	result := buildGraph(t, adapter, s, clinicTables)
+
+	data, err := adapter.MarshalType(result, "Patient")
+	require.NoError(t, err)
+	assert.Equal(t,
+		"id,name,age,weight,active,status,born,tags,scores,meta,"+
+			"physician._target_id,consultants._target_id,ward._target_building,ward._target_opened,wards._target_building,wards._target_opened\n"+
+			"p1,Alice,42,,,,,allergy;vip,,\"{\"\"visits\"\":3}\",d7,d1;d2,North,2001-09-01,,\n"+
+			"p2,\"Bob, Jr.\",,,,,,,,,,,,,,\n",
+		string(data))
+}
+
+func TestMarshalType_RoundTrip(t *testing.T) {
+	s := loadClinic(t)
+	adapter, err := NewAdapter(nil, WithComma('\t'), WithColumn("Patient ID", "id"))
+	require.NoError(t, err)
+	tsv := map[string]string{
+		"Physician": "id\tname\nd1\tGrey\nd2\tHouse\nd7\tWho\n",
+		"Ward":      "building\topened\nNorth\t2001-09-01\n",
+		"Patient":   "Patient ID\tname\tconsultants._target_id\np1\tAlice\td1;d2\n",
+	}
+	first := buildGraph(t, adapter, s, tsv)
+
+	data, err := adapter.MarshalType(first, "Patient")
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("Patient ID\tname\t")), "header uses the column mapping: %q", data)
+
+	tsv["Patient"] = string(data)
+	second := buildGraph(t, adapter, s, tsv)
+	again, err := adapter.MarshalType(second, "Patient")
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(again))
+}
+
+func TestMarshalType_Errors(t *testing.T) {
+	s := loadClinic(t)
+	adapter, err := NewAdapter(nil)
+	require.NoError(t, err)
+
+	t.Run("nil result", func(t *testing.T) {
+		_, err := adapter.MarshalType(nil, "Patient")
+		assert.ErrorIs(t, err, ErrNilResult)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := adapter.MarshalType(graph.New(s).Snapshot(), "Nurse")
+		assert.ErrorIs(t, err, ErrUnknownType)
+	})
+
+	t.Run("list element containing the delimiter", func(t *testing.T) {
+		result := buildGraph(t, adapter, s, map[string]string{
+			"Patient": "id,name,meta\np1,Alice,\"{\"\"a;b\"\":1}\"\n",
+		})
+		_, err := adapter.MarshalType(result, "Patient")
+		require.NoError(t, err, "JSON cells may contain the delimiter")
+
+		comma, err := NewAdapter(nil, WithListDelimiter("|"))
+		require.NoError(t, err)
+		result = buildGraph(t, comma, s, map[string]string{
+			"Patient": "id,name,tags\np1,Alice,a;b|c\n",
+		})
+		_, err = adapter.MarshalType(result, "Patient")
+		assert.ErrorContains(t, err, `list element "a;b" contains the list delimiter ";"`)
+	})
+}
@@ -34,4 +34,6 @@
 // # Subpackages
 //
 //   - [json]: JSON adapter with optional location tracking and JSONC support
+//   - [yaml]: YAML adapter with optional location tracking
+//   - [csv]: CSV/TSV adapter with column mapping and CSV export
 package adapter
@@ -1,4 +1,4 @@
-// Package typetag provides type tag validation for JSON instance parsing.
+// Package typetag provides type tag validation for instance adapters.
 //
 // Type tags follow DSL grammar rules: names must start with an uppercase letter,
 // aliases use dot notation (e.g., "alias.TypeName"). This package validates
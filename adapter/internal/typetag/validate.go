@@ -1,4 +1,4 @@
-// Package typetag provides validation for JSON $type field values.
+// Package typetag provides validation for $type field values.
 //
 // Type names in instance data must conform to DSL grammar syntax:
 //   - Unqualified: "Person" (UC_WORD pattern)
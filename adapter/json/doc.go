@@ -54,6 +54,14 @@
 // These invariants are verified by the internal/jsonc_test.go test suite,
 // which serves as a regression guard for jsonc dependency upgrades.
 //
+// # Streaming
+//
+// [Adapter.NewStreamReader] reads newline-delimited JSON, JSON text sequences
+// (RFC 7464) or the elements of a large top-level array one instance at a
+// time, for inputs too large to hold in memory. The reader computes positions
+// from the bytes it reads rather than from the registry, and implements
+// [ingest.Source] for loading streams into a graph with [ingest.Run].
+//
 // # Type Tag Resolution
 //
 // The adapter recognizes $type fields for type routing. Unqualified type names
@@ -10,7 +10,7 @@ import (
 
 	"github.com/tidwall/jsonc"
 
-	"github.com/simon-lentz/yammm/adapter/json/internal/typetag"
+	"github.com/simon-lentz/yammm/adapter/internal/typetag"
 	"github.com/simon-lentz/yammm/diag"
 	"github.com/simon-lentz/yammm/instance"
 	"github.com/simon-lentz/yammm/instance/path"
@@ -169,22 +169,9 @@ func (a *Adapter) ParseArray(source location.SourceID, data []byte) (map[string]
 		}
 
 		// Extract and validate type tag
-		typeTagVal, hasType := obj[a.typeField]
-		if !hasType {
-			collector.Collect(*a.missingTypeTagError(source, startOffset, idx))
-			idx++
-			continue
-		}
-
-		typeName, ok := typeTagVal.(string)
-		if !ok {
-			collector.Collect(*a.invalidTypeTagError(source, startOffset, idx, "expected string", fmt.Sprintf("%T", typeTagVal)))
-			idx++
-			continue
-		}
-
-		if err := typetag.Validate(typeName); err != nil {
-			collector.Collect(*a.typeTagError(source, startOffset, typeName, err))
+		typeName, ib := a.typeTag(obj, arrayElement(idx))
+		if ib != nil {
+			collector.Collect(*a.atOffset(ib, source, startOffset))
 			idx++
 			continue
 		}
@@ -420,25 +407,18 @@ func (a *Adapter) makeProvenance(source location.SourceID, p path.Builder, start
 // parseError creates an E_ADAPTER_PARSE issue.
 // msg is the human-readable message; detail is the machine-oriented parse detail.
 func (a *Adapter) parseError(source location.SourceID, offset int, msg, detail string) *diag.Issue {
-	ib := diag.NewIssue(diag.Error, diag.E_ADAPTER_PARSE, msg).
-		WithDetail(diag.DetailKeyFormat, "json").
-		WithDetail(diag.DetailKeyDetail, detail)
-	if a.trackLocations && a.registry != nil {
-		pos := a.registry.PositionAt(source, offset)
-		// Guard: only attach span if position is valid
-		if !pos.IsZero() {
-			span := location.Span{Source: source, Start: pos, End: pos}
-			ib.WithSpan(span)
-		}
-	}
-	issue := ib.Build()
-	return &issue
+	return a.atOffset(parseIssue(msg, detail), source, offset)
 }
 
-// missingTypeTagError creates an E_MISSING_TYPE_TAG issue.
-func (a *Adapter) missingTypeTagError(source location.SourceID, offset int, idx int) *diag.Issue {
-	msg := fmt.Sprintf("missing %s field in array element [%d]", a.typeField, idx)
-	ib := diag.NewIssue(diag.Error, diag.E_MISSING_TYPE_TAG, msg)
+// typeTagError creates an E_INVALID_TYPE_TAG issue for type name validation errors.
+func (a *Adapter) typeTagError(source location.SourceID, offset int, typeName string, err error) *diag.Issue {
+	return a.atOffset(typeTagIssue(typeName, err), source, offset)
+}
+
+// atOffset attaches a point span at offset, resolved through the registry,
+// and builds the issue. The span is omitted unless locations are tracked and
+// the position is valid.
+func (a *Adapter) atOffset(ib *diag.IssueBuilder, source location.SourceID, offset int) *diag.Issue {
 	if a.trackLocations && a.registry != nil {
 		pos := a.registry.PositionAt(source, offset)
 		// Guard: only attach span if position is valid
@@ -451,41 +431,49 @@ func (a *Adapter) missingTypeTagError(source location.SourceID, offset int, idx
 	return &issue
 }
 
-// invalidTypeTagError creates an E_INVALID_TYPE_TAG issue.
-// detail is a canonical reason string; got is the observed value/type.
-func (a *Adapter) invalidTypeTagError(source location.SourceID, offset int, idx int, detail, got string) *diag.Issue {
-	msg := fmt.Sprintf("invalid %s in array element [%d]: %s, got %s", a.typeField, idx, detail, got)
-	ib := diag.NewIssue(diag.Error, diag.E_INVALID_TYPE_TAG, msg).
-		WithDetail(diag.DetailKeyDetail, detail).
-		WithDetail(diag.DetailKeyGot, got)
-	if a.trackLocations && a.registry != nil {
-		pos := a.registry.PositionAt(source, offset)
-		// Guard: only attach span if position is valid
-		if !pos.IsZero() {
-			span := location.Span{Source: source, Start: pos, End: pos}
-			ib.WithSpan(span)
-		}
+// typeTag extracts and validates the type tag of an element. elem names the
+// element in messages. On failure it returns the issue to report.
+func (a *Adapter) typeTag(obj map[string]any, elem string) (string, *diag.IssueBuilder) {
+	typeTagVal, hasType := obj[a.typeField]
+	if !hasType {
+		msg := fmt.Sprintf("missing %s field in %s", a.typeField, elem)
+		return "", diag.NewIssue(diag.Error, diag.E_MISSING_TYPE_TAG, msg)
 	}
-	issue := ib.Build()
-	return &issue
+
+	typeName, ok := typeTagVal.(string)
+	if !ok {
+		detail, got := "expected string", fmt.Sprintf("%T", typeTagVal)
+		msg := fmt.Sprintf("invalid %s in %s: %s, got %s", a.typeField, elem, detail, got)
+		return "", diag.NewIssue(diag.Error, diag.E_INVALID_TYPE_TAG, msg).
+			WithDetail(diag.DetailKeyDetail, detail).
+			WithDetail(diag.DetailKeyGot, got)
+	}
+
+	if err := typetag.Validate(typeName); err != nil {
+		return "", typeTagIssue(typeName, err)
+	}
+	return typeName, nil
 }
 
-// typeTagError creates an E_INVALID_TYPE_TAG issue for type name validation errors.
-func (a *Adapter) typeTagError(source location.SourceID, offset int, typeName string, err error) *diag.Issue {
+// arrayElement names the element at idx of a top-level array in messages.
+func arrayElement(idx int) string {
+	return fmt.Sprintf("array element [%d]", idx)
+}
+
+// parseIssue starts an E_ADAPTER_PARSE issue without a location.
+func parseIssue(msg, detail string) *diag.IssueBuilder {
+	return diag.NewIssue(diag.Error, diag.E_ADAPTER_PARSE, msg).
+		WithDetail(diag.DetailKeyFormat, "json").
+		WithDetail(diag.DetailKeyDetail, detail)
+}
+
+// typeTagIssue starts an E_INVALID_TYPE_TAG issue for a type name that
+// fails validation.
+func typeTagIssue(typeName string, err error) *diag.IssueBuilder {
 	msg := fmt.Sprintf("invalid type name %q: %s", typeName, err.Error())
-	ib := diag.NewIssue(diag.Error, diag.E_INVALID_TYPE_TAG, msg).
+	return diag.NewIssue(diag.Error, diag.E_INVALID_TYPE_TAG, msg).
 		WithDetail(diag.DetailKeyGot, typeName).
 		WithDetail(diag.DetailKeyDetail, err.Error())
-	if a.trackLocations && a.registry != nil {
-		pos := a.registry.PositionAt(source, offset)
-		// Guard: only attach span if position is valid
-		if !pos.IsZero() {
-			span := location.Span{Source: source, Start: pos, End: pos}
-			ib.WithSpan(span)
-		}
-	}
-	issue := ib.Build()
-	return &issue
 }
 
 // skipValue consumes the remainder of a JSON value from the decoder after
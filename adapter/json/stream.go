@@ -0,0 +1,469 @@
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/simon-lentz/yammm/adapter/internal/typetag"
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/ingest"
+	"github.com/simon-lentz/yammm/instance"
+	"github.com/simon-lentz/yammm/instance/path"
+	"github.com/simon-lentz/yammm/location"
+)
+
+// StreamFormat selects how a stream is split into instances.
+type StreamFormat int
+
+const (
+	// StreamAuto detects the format from the first non-whitespace byte:
+	// '[' selects StreamArray, the record separator 0x1E selects
+	// StreamJSONSeq, and anything else StreamNDJSON.
+	StreamAuto StreamFormat = iota
+
+	// StreamNDJSON reads newline-delimited JSON: one object per line.
+	// Blank lines are skipped.
+	StreamNDJSON
+
+	// StreamJSONSeq reads JSON text sequences (RFC 7464): objects preceded
+	// by the record separator 0x1E. A record may span several lines.
+	StreamJSONSeq
+
+	// StreamArray reads the elements of one top-level array.
+	StreamArray
+)
+
+// String returns the format name.
+func (f StreamFormat) String() string {
+	switch f {
+	case StreamAuto:
+		return "auto"
+	case StreamNDJSON:
+		return "ndjson"
+	case StreamJSONSeq:
+		return "json-seq"
+	case StreamArray:
+		return "array"
+	default:
+		return fmt.Sprintf("StreamFormat(%d)", int(f))
+	}
+}
+
+// recordSeparator starts each record of a JSON text sequence.
+const recordSeparator = 0x1E
+
+// StreamOption configures a StreamReader.
+type StreamOption func(*StreamReader)
+
+// WithStreamFormat sets the stream format. Default is StreamAuto.
+func WithStreamFormat(format StreamFormat) StreamOption {
+	return func(s *StreamReader) {
+		s.format = format
+	}
+}
+
+// WithStreamType reads every instance as the given type, like
+// ParseTypedArray. Without it, each object needs a type field, like
+// ParseArray.
+func WithStreamType(typeName string) StreamOption {
+	return func(s *StreamReader) {
+		s.typeName = typeName
+	}
+}
+
+// StreamReader reads instances from a JSON stream one at a time, holding
+// only the current instance in memory. It implements [ingest.Source].
+//
+// Streams are strict JSON: comments and trailing commas are not accepted,
+// regardless of WithStrictJSON.
+//
+// Thread Safety: a StreamReader must not be used concurrently.
+type StreamReader struct {
+	adapter  *Adapter
+	source   location.SourceID
+	format   StreamFormat
+	typeName string
+
+	tracker *positionTracker
+	br      *bufio.Reader
+	dec     *json.Decoder // StreamArray only
+	offset  int64         // bytes consumed from br; record formats only
+	idx     int           // index of the next instance
+	started bool
+	done    bool
+}
+
+var _ ingest.Source = (*StreamReader)(nil)
+
+// NewStreamReader returns a reader for the instances of a JSON stream.
+//
+// Unlike the Parse* methods, a stream reader computes positions from the
+// bytes it reads, so the content need not be registered with the registry.
+// When locations are tracked, each instance's provenance spans the instance
+// and has the path `$[i]`, where i counts instances from zero.
+func (a *Adapter) NewStreamReader(source location.SourceID, r io.Reader, opts ...StreamOption) *StreamReader {
+	tracker := &positionTracker{r: r, base: newCursor()}
+	s := &StreamReader{
+		adapter: a,
+		source:  source,
+		tracker: tracker,
+		br:      bufio.NewReader(tracker),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Next returns the next instance. Problems with the instance, such as a
+// syntax error or a missing type tag, are reported in Record.Result and
+// reading continues with the next instance where the format allows it.
+//
+// Next returns io.EOF after the last instance, ctx.Err() if ctx is done, and
+// any error from the underlying reader.
+func (s *StreamReader) Next(ctx context.Context) (ingest.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return ingest.Record{}, err
+	}
+	if s.done {
+		return ingest.Record{}, io.EOF
+	}
+	if !s.started {
+		s.started = true
+		if rec, ok := s.start(); ok {
+			return rec, nil
+		}
+		if s.done {
+			return ingest.Record{}, s.readErr(io.EOF)
+		}
+	}
+
+	switch s.format {
+	case StreamArray:
+		return s.nextElement()
+	case StreamJSONSeq:
+		return s.nextRecord(recordSeparator)
+	default:
+		return s.nextRecord('\n')
+	}
+}
+
+// start validates the options, detects the format and, for arrays, reads the
+// opening bracket. It returns a record if that fails with an issue.
+func (s *StreamReader) start() (ingest.Record, bool) {
+	if s.typeName != "" {
+		if err := typetag.Validate(s.typeName); err != nil {
+			s.done = true
+			return s.issueRecord(typeTagIssue(s.typeName, err), 0), true
+		}
+	}
+
+	if s.format == StreamAuto {
+		s.format = s.detect()
+	}
+	if s.format != StreamArray {
+		return ingest.Record{}, false
+	}
+
+	s.dec = json.NewDecoder(s.br)
+	s.dec.UseNumber()
+	tok, err := s.dec.Token()
+	if s.tracker.err != nil {
+		s.done = true
+		return ingest.Record{}, false
+	}
+	if err != nil {
+		s.done = true
+		return s.issueRecord(parseIssue("invalid JSON", err.Error()), 0), true
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		s.done = true
+		return s.issueRecord(parseIssue("expected array at root", "expected array"), 0), true
+	}
+	return ingest.Record{}, false
+}
+
+// detect peeks at the first non-whitespace byte to choose a format.
+func (s *StreamReader) detect() StreamFormat {
+	for n := 1; ; n++ {
+		b, err := s.br.Peek(n)
+		if err != nil {
+			return StreamNDJSON
+		}
+		switch b[n-1] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '[':
+			return StreamArray
+		case recordSeparator:
+			return StreamJSONSeq
+		default:
+			return StreamNDJSON
+		}
+	}
+}
+
+// nextRecord reads the next record of a delimited format: a line for NDJSON,
+// or the text up to the next record separator for JSON text sequences.
+func (s *StreamReader) nextRecord(delim byte) (ingest.Record, error) {
+	for {
+		data, err := s.br.ReadBytes(delim)
+		if err != nil && !errors.Is(err, io.EOF) {
+			s.done = true
+			return ingest.Record{}, err
+		}
+		begin := s.offset
+		s.offset += int64(len(data))
+		if err != nil {
+			s.done = true
+		}
+
+		content := bytes.TrimSuffix(data, []byte{delim})
+		trimmed := bytes.TrimLeft(content, " \t\r\n")
+		start := begin + int64(len(content)-len(trimmed))
+		trimmed = bytes.TrimRight(trimmed, " \t\r\n")
+		if len(trimmed) == 0 {
+			s.tracker.release(s.offset)
+			if s.done {
+				return ingest.Record{}, s.readErr(io.EOF)
+			}
+			continue
+		}
+		end := start + int64(len(trimmed))
+
+		rec := s.decodeRecord(trimmed, start, end)
+		rec.Offset = s.offset
+		s.tracker.release(s.offset)
+		return rec, nil
+	}
+}
+
+// decodeRecord decodes one record of a delimited format.
+func (s *StreamReader) decodeRecord(data []byte, start, end int64) ingest.Record {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		errOffset := start
+		if syntaxErr, ok := errors.AsType[*json.SyntaxError](err); ok {
+			errOffset += syntaxErr.Offset
+		}
+		s.idx++
+		return s.issueRecord(parseIssue("error reading record", err.Error()), errOffset)
+	}
+	after := dec.InputOffset()
+	if tok, err := dec.Token(); err == nil {
+		rest := data[after:]
+		after += int64(len(rest) - len(bytes.TrimLeft(rest, " \t")))
+		s.idx++
+		return s.issueRecord(parseIssue("unexpected content after record", fmt.Sprintf("found %v", tok)), start+after)
+	}
+	return s.instance(obj, start, end, fmt.Sprintf("record [%d]", s.idx))
+}
+
+// nextElement reads the next element of a top-level array.
+func (s *StreamReader) nextElement() (ingest.Record, error) {
+	if !s.dec.More() {
+		s.done = true
+		return s.finishArray()
+	}
+
+	startOffset := s.dec.InputOffset()
+	var obj map[string]any
+	err := s.dec.Decode(&obj)
+	if s.tracker.err != nil {
+		s.done = true
+		return ingest.Record{}, s.tracker.err
+	}
+	endOffset := s.dec.InputOffset()
+	startOffset = s.tracker.skipSeparators(startOffset, endOffset)
+
+	var rec ingest.Record
+	if err != nil {
+		errOffset := startOffset
+		syntaxErr, isSyntaxErr := errors.AsType[*json.SyntaxError](err)
+		if isSyntaxErr {
+			errOffset = syntaxErr.Offset
+		}
+		// For syntax errors, the decoder cannot recover - stop reading
+		if isSyntaxErr || errors.Is(err, io.ErrUnexpectedEOF) {
+			s.done = true
+		}
+		s.idx++
+		rec = s.issueRecord(parseIssue("error reading array element", err.Error()), errOffset)
+	} else {
+		rec = s.instance(obj, startOffset, endOffset, arrayElement(s.idx))
+	}
+	rec.Offset = endOffset
+	s.tracker.release(endOffset)
+	return rec, nil
+}
+
+// finishArray reads the closing bracket and checks for trailing content.
+func (s *StreamReader) finishArray() (ingest.Record, error) {
+	var ib *diag.IssueBuilder
+	if _, err := s.dec.Token(); err != nil {
+		ib = parseIssue("error reading closing bracket", err.Error())
+	} else if tok, err := s.dec.Token(); err == nil {
+		ib = parseIssue("unexpected content after root array", fmt.Sprintf("found %v", tok))
+	}
+	if s.tracker.err != nil {
+		return ingest.Record{}, s.tracker.err
+	}
+	if ib == nil {
+		return ingest.Record{}, io.EOF
+	}
+	offset := s.dec.InputOffset()
+	rec := s.issueRecord(ib, offset)
+	rec.Offset = offset
+	return rec, nil
+}
+
+// instance turns a decoded object spanning [start, end) into a record.
+func (s *StreamReader) instance(obj map[string]any, start, end int64, elem string) ingest.Record {
+	idx := s.idx
+	s.idx++
+
+	// Reject null values - json.Decode into map yields nil without error
+	if obj == nil {
+		return s.issueRecord(parseIssue("expected object", "got null"), start)
+	}
+
+	typeName := s.typeName
+	if typeName == "" {
+		var ib *diag.IssueBuilder
+		typeName, ib = s.adapter.typeTag(obj, elem)
+		if ib != nil {
+			return s.issueRecord(ib, start)
+		}
+		delete(obj, s.adapter.typeField)
+	}
+
+	normalizeNumbers(obj)
+	raw := instance.RawInstance{Properties: obj}
+	if s.adapter.trackLocations {
+		span := location.Span{
+			Source: s.source,
+			Start:  s.tracker.position(start),
+			End:    s.tracker.position(end),
+		}
+		raw.Provenance = instance.NewProvenance(s.source.String(), path.Root().Index(idx), span)
+	}
+	return ingest.Record{TypeName: typeName, Raw: raw}
+}
+
+// issueRecord returns a record holding a single issue located at offset.
+func (s *StreamReader) issueRecord(ib *diag.IssueBuilder, offset int64) ingest.Record {
+	if s.adapter.trackLocations {
+		pos := s.tracker.position(offset)
+		ib.WithSpan(location.Span{Source: s.source, Start: pos, End: pos})
+	}
+	collector := diag.NewCollectorUnlimited()
+	collector.Collect(ib.Build())
+	return ingest.Record{Result: collector.Result()}
+}
+
+// readErr returns the underlying reader's error, if any, or err.
+func (s *StreamReader) readErr(err error) error {
+	if s.tracker.err != nil {
+		return s.tracker.err
+	}
+	return err
+}
+
+// positionTracker wraps the input and keeps the bytes read since the start
+// of the current instance, so that offsets within it can be converted to
+// line and column positions without holding the whole input.
+type positionTracker struct {
+	r    io.Reader
+	buf  []byte // input from base onwards
+	base cursor
+	err  error // first error from r other than io.EOF
+}
+
+func (t *positionTracker) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	t.buf = append(t.buf, p[:n]...)
+	if err != nil && !errors.Is(err, io.EOF) && t.err == nil {
+		t.err = err
+	}
+	return n, err
+}
+
+// bytesTo returns the retained input from base up to offset.
+func (t *positionTracker) bytesTo(offset int64) []byte {
+	n := int(max(0, min(offset-t.base.offset, int64(len(t.buf)))))
+	return t.buf[:n]
+}
+
+// position returns the position of an offset at or after base.
+func (t *positionTracker) position(offset int64) location.Position {
+	c := t.base
+	c.advance(t.bytesTo(offset))
+	return c.position()
+}
+
+// release drops the input before offset, which must not be needed again.
+func (t *positionTracker) release(offset int64) {
+	consumed := t.bytesTo(offset)
+	t.base.advance(consumed)
+	t.buf = t.buf[:copy(t.buf, t.buf[len(consumed):])]
+}
+
+// skipSeparators returns the offset of the first byte in [from, to) that is
+// not whitespace or a comma: the start of an array element.
+func (t *positionTracker) skipSeparators(from, to int64) int64 {
+	data := t.bytesTo(to)
+	for from < to {
+		i := from - t.base.offset
+		if i < 0 || i >= int64(len(data)) {
+			break
+		}
+		switch data[i] {
+		case ' ', '\t', '\r', '\n', ',':
+			from++
+			continue
+		}
+		break
+	}
+	return from
+}
+
+// cursor is a position in the input, advanced byte by byte. Line breaks
+// follow the source registry: \n, \r\n and a bare \r each end a line.
+type cursor struct {
+	line, column int
+	offset       int64
+	afterCR      bool // the previous byte was \r
+}
+
+func newCursor() cursor {
+	return cursor{line: 1, column: 1}
+}
+
+func (c *cursor) advance(data []byte) {
+	for _, b := range data {
+		switch {
+		case b == '\n' && c.afterCR:
+			// second byte of \r\n; the line already ended
+		case b == '\n' || b == '\r':
+			c.line++
+			c.column = 1
+		case utf8.RuneStart(b):
+			c.column++
+		}
+		c.afterCR = b == '\r'
+		c.offset++
+	}
+}
+
+func (c cursor) position() location.Position {
+	return location.NewPosition(c.line, c.column, int(c.offset))
+}
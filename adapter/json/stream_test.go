@@ -0,0 +1,248 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/ingest"
+	"github.com/simon-lentz/yammm/internal/source"
+	"github.com/simon-lentz/yammm/location"
+)
+
+// readStream reads every record from a stream reader.
+func readStream(t *testing.T, sr *StreamReader) []ingest.Record {
+	t.Helper()
+	var records []ingest.Record
+	for {
+		rec, err := sr.Next(t.Context())
+		if errors.Is(err, io.EOF) {
+			return records
+		}
+		require.NoError(t, err)
+		records = append(records, rec)
+	}
+}
+
+func TestStreamReader_Formats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format StreamFormat
+		input  string
+	}{
+		{"ndjson", StreamNDJSON, "{\"$type\":\"Person\",\"name\":\"Alice\"}\n\n{\"$type\":\"Person\",\"name\":\"Bob\"}\n"},
+		{"ndjson crlf without final newline", StreamNDJSON, "{\"$type\":\"Person\",\"name\":\"Alice\"}\r\n{\"$type\":\"Person\",\"name\":\"Bob\"}"},
+		{"json-seq", StreamJSONSeq, "\x1e{\"$type\":\"Person\",\n \"name\":\"Alice\"}\n\x1e{\"$type\":\"Person\",\"name\":\"Bob\"}\n"},
+		{"array", StreamArray, "[\n  {\"$type\":\"Person\",\"name\":\"Alice\"},\n  {\"$type\":\"Person\",\"name\":\"Bob\"}\n]\n"},
+	}
+
+	adapter, err := NewAdapter(nil)
+	require.NoError(t, err)
+	for _, tt := range tests {
+		for _, format := range []StreamFormat{tt.format, StreamAuto} {
+			t.Run(tt.name+"/"+format.String(), func(t *testing.T) {
+				sr := adapter.NewStreamReader(location.NewSourceID("test://stream"), strings.NewReader(tt.input),
+					WithStreamFormat(format))
+				records := readStream(t, sr)
+				require.Len(t, records, 2)
+				for i, want := range []string{"Alice", "Bob"} {
+					assert.True(t, records[i].Result.OK(), "record %d: %v", i, records[i].Result.Messages())
+					assert.Equal(t, "Person", records[i].TypeName)
+					assert.Equal(t, map[string]any{"name": want}, records[i].Raw.Properties)
+				}
+				assert.Less(t, records[0].Offset, records[1].Offset)
+				assert.LessOrEqual(t, records[1].Offset, int64(len(tt.input)))
+			})
+		}
+	}
+}
+
+func TestStreamReader_Positions(t *testing.T) {
+	// Multi-byte runes, CRLF and nesting exercise the column and line
+	// computation; the one-byte reader forces retained input across reads.
+	inputs := map[string]string{
+		"ndjson": "{\"$type\":\"Person\",\"name\":\"Zoë\"}\r\n   {\"$type\":\"Person\",\"name\":\"Åsa\",\"tags\":[1,2]}\n",
+		"array":  "[{\"$type\":\"Person\",\"name\":\"Zoë\"},\r\n\t{\"$type\":\"Person\",\n\"name\":\"Åsa\"} ,\n{\"$type\":\"Person\",\"name\":\"Ünal\"}]",
+		"json-seq": "\x1e{\"$type\":\"Person\",\"name\":\"Zoë\"}\n" +
+			"\x1e  {\"$type\":\"Person\",\n\"name\":\"Åsa\"}\n",
+	}
+
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			sourceID := location.NewSourceID("test://" + name)
+			reg := source.NewRegistry()
+			require.NoError(t, reg.Register(sourceID, []byte(input)))
+			adapter, err := NewAdapter(reg, WithTrackLocations(true))
+			require.NoError(t, err)
+
+			sr := adapter.NewStreamReader(sourceID, iotest.OneByteReader(strings.NewReader(input)))
+			records := readStream(t, sr)
+			require.NotEmpty(t, records)
+			for i, rec := range records {
+				require.True(t, rec.Result.OK(), "record %d: %v", i, rec.Result.Messages())
+				require.NotNil(t, rec.Raw.Provenance)
+				span := rec.Raw.Provenance.Span()
+				assert.Equal(t, reg.PositionAt(sourceID, span.Start.Byte), span.Start, "record %d start", i)
+				assert.Equal(t, reg.PositionAt(sourceID, span.End.Byte), span.End, "record %d end", i)
+				assert.Equal(t, byte('{'), input[span.Start.Byte], "record %d starts at its object", i)
+				assert.Equal(t, byte('}'), input[span.End.Byte-1], "record %d ends after its object", i)
+				assert.Equal(t, "$["+string(rune('0'+i))+"]", rec.Raw.Provenance.Path().String())
+			}
+		})
+	}
+}
+
+func TestStreamReader_RecordErrors(t *testing.T) {
+	input := "{\"$type\":\"Person\",\"name\":\"Alice\"}\n" +
+		"{\"$type\":\"Person\",\"name\":}\n" +
+		"{\"name\":\"Carol\"}\n" +
+		"{\"$type\":42}\n" +
+		"{\"$type\":\"bad type\"}\n" +
+		"null\n" +
+		"{} {}\n" +
+		"{\"$type\":\"Person\",\"name\":\"Dave\"}\n"
+	sourceID := location.NewSourceID("test://errors.ndjson")
+	reg := source.NewRegistry()
+	require.NoError(t, reg.Register(sourceID, []byte(input)))
+	adapter, err := NewAdapter(reg, WithTrackLocations(true))
+	require.NoError(t, err)
+
+	records := readStream(t, adapter.NewStreamReader(sourceID, strings.NewReader(input)))
+	require.Len(t, records, 8)
+
+	want := []struct {
+		code diag.Code
+		line int
+		col  int
+	}{
+		{},
+		{diag.E_ADAPTER_PARSE, 2, 27},
+		{diag.E_MISSING_TYPE_TAG, 3, 1},
+		{diag.E_INVALID_TYPE_TAG, 4, 1},
+		{diag.E_INVALID_TYPE_TAG, 5, 1},
+		{diag.E_ADAPTER_PARSE, 6, 1},
+		{diag.E_ADAPTER_PARSE, 7, 4},
+		{},
+	}
+	for i, w := range want {
+		rec := records[i]
+		if w.code.IsZero() {
+			assert.True(t, rec.Result.OK(), "record %d: %v", i, rec.Result.Messages())
+			continue
+		}
+		issues := rec.Result.IssuesSlice()
+		require.Len(t, issues, 1, "record %d", i)
+		assert.Equal(t, w.code, issues[0].Code(), "record %d: %s", i, issues[0].Message())
+		assert.Equal(t, w.line, issues[0].Span().Start.Line, "record %d line", i)
+		assert.Equal(t, w.col, issues[0].Span().Start.Column, "record %d column", i)
+	}
+	assert.Contains(t, records[2].Result.IssuesSlice()[0].Message(), "record [2]")
+	assert.Equal(t, "Dave", records[7].Raw.Properties["name"])
+	assert.Equal(t, "$[7]", records[7].Raw.Provenance.Path().String())
+}
+
+func TestStreamReader_ArraySyntaxErrorStops(t *testing.T) {
+	adapter, err := NewAdapter(nil)
+	require.NoError(t, err)
+	input := `[{"$type":"Person","name":"Alice"}, {"$type":"Person" "name":"Bob"}, {"$type":"Person"}]`
+
+	records := readStream(t, adapter.NewStreamReader(location.NewSourceID("test://array"), strings.NewReader(input)))
+	require.Len(t, records, 2)
+	assert.True(t, records[0].Result.OK())
+	require.True(t, records[1].Result.HasErrors())
+	assert.Equal(t, diag.E_ADAPTER_PARSE, records[1].Result.IssuesSlice()[0].Code())
+}
+
+func TestStreamReader_ArrayRootErrors(t *testing.T) {
+	adapter, err := NewAdapter(nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		input string
+		msg   string
+	}{
+		{"not an array", `{"a":1}`, "expected array at root"},
+		{"empty", ``, "invalid JSON"},
+		{"trailing content", `[] 1`, "unexpected content after root array"},
+		{"unterminated", `[{"$type":"Person"}`, "error reading array element"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := adapter.NewStreamReader(location.NewSourceID("test://array"), strings.NewReader(tt.input),
+				WithStreamFormat(StreamArray))
+			records := readStream(t, sr)
+			require.NotEmpty(t, records)
+			last := records[len(records)-1]
+			require.True(t, last.Result.HasErrors())
+			assert.Contains(t, last.Result.IssuesSlice()[0].Message(), tt.msg)
+		})
+	}
+}
+
+func TestStreamReader_WithStreamType(t *testing.T) {
+	adapter, err := NewAdapter(nil)
+	require.NoError(t, err)
+
+	t.Run("objects need no type field", func(t *testing.T) {
+		sr := adapter.NewStreamReader(location.NewSourceID("test://typed"),
+			strings.NewReader("{\"name\":\"Alice\"}\n{\"name\":\"Bob\"}\n"), WithStreamType("Person"))
+		records := readStream(t, sr)
+		require.Len(t, records, 2)
+		for _, rec := range records {
+			assert.True(t, rec.Result.OK())
+			assert.Equal(t, "Person", rec.TypeName)
+		}
+	})
+
+	t.Run("invalid type name", func(t *testing.T) {
+		sr := adapter.NewStreamReader(location.NewSourceID("test://typed"),
+			strings.NewReader("{\"name\":\"Alice\"}\n"), WithStreamType("not a type"))
+		records := readStream(t, sr)
+		require.Len(t, records, 1)
+		assert.Equal(t, diag.E_INVALID_TYPE_TAG, records[0].Result.IssuesSlice()[0].Code())
+	})
+}
+
+func TestStreamReader_ReadError(t *testing.T) {
+	adapter, err := NewAdapter(nil)
+	require.NoError(t, err)
+	boom := errors.New("disk on fire")
+
+	for _, format := range []StreamFormat{StreamNDJSON, StreamArray} {
+		t.Run(format.String(), func(t *testing.T) {
+			r := io.MultiReader(strings.NewReader(`[{"$type":"Person"}`+"\n"), iotest.ErrReader(boom))
+			if format == StreamNDJSON {
+				r = io.MultiReader(strings.NewReader(`{"$type":"Person"}`+"\n"), iotest.ErrReader(boom))
+			}
+			sr := adapter.NewStreamReader(location.NewSourceID("test://broken"), r, WithStreamFormat(format))
+
+			var got error
+			for range 3 {
+				if _, err := sr.Next(t.Context()); err != nil {
+					got = err
+					break
+				}
+			}
+			assert.ErrorIs(t, got, boom)
+		})
+	}
+}
+
+func TestStreamReader_ContextCancelled(t *testing.T) {
+	adapter, err := NewAdapter(nil)
+	require.NoError(t, err)
+	sr := adapter.NewStreamReader(location.NewSourceID("test://stream"), strings.NewReader("{\"$type\":\"Person\"}\n"))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err = sr.Next(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
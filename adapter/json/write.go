@@ -10,6 +10,7 @@ import (
 
 	"github.com/simon-lentz/yammm/graph"
 	"github.com/simon-lentz/yammm/immutable"
+	"github.com/simon-lentz/yammm/internal/value"
 	"github.com/simon-lentz/yammm/schema"
 )
 
@@ -112,7 +113,7 @@ func (a *Adapter) buildOutput(result *graph.Result, cfg *writeConfig) map[string
 		serialized := make([]map[string]any, 0, len(instances))
 
 		for _, inst := range instances {
-			obj := serializeInstance(inst, edgeIdx, s)
+			obj := a.serializeInstance(inst, edgeIdx, s)
 			serialized = append(serialized, obj)
 		}
 
@@ -176,7 +177,9 @@ func lookupType(s *schema.Schema, id schema.TypeID) (*schema.Type, bool) {
 
 // serializeInstance converts a graph.Instance to a JSON-serializable map.
 // Uses schema to determine cardinality (scalar vs array) and field names.
-func serializeInstance(inst *graph.Instance, edgeIdx edgeIndex, s *schema.Schema) map[string]any {
+// Composed children of a subtype of the composition target are tagged with
+// the adapter's type field so they round-trip through validation.
+func (a *Adapter) serializeInstance(inst *graph.Instance, edgeIdx edgeIndex, s *schema.Schema) map[string]any {
 	obj := make(map[string]any)
 
 	// Lookup the type for schema-based serialization
@@ -185,6 +188,11 @@ func serializeInstance(inst *graph.Instance, edgeIdx edgeIndex, s *schema.Schema
 	// 1. Add properties in sorted order for deterministic output
 	for name, val := range inst.Properties().SortedRange() {
 		obj[name] = unwrapValue(val)
+		if hasType {
+			if prop, ok := schemaType.Property(name); ok {
+				obj[name] = sortSets(obj[name], prop.Constraint())
+			}
+		}
 	}
 
 	// 2. Add FK references for associations
@@ -241,26 +249,35 @@ func serializeInstance(inst *graph.Instance, edgeIdx edgeIndex, s *schema.Schema
 			return compareStrings(a.PrimaryKey().String(), b.PrimaryKey().String())
 		})
 
-		// Determine field name and cardinality from schema
+		// Determine field name, cardinality and target from schema
 		fieldName := relName // fallback
 		isMany := len(children) > 1
+		var targetID schema.TypeID
 		if hasType {
 			if rel, ok := schemaType.Relation(relName); ok {
 				fieldName = rel.FieldName()
 				isMany = rel.IsMany()
+				targetID = rel.TargetID()
+			}
+		}
+		serializeChild := func(child *graph.Instance) map[string]any {
+			childObj := a.serializeInstance(child, edgeIdx, s)
+			if !targetID.IsZero() && child.TypeID() != targetID {
+				childObj[a.typeField] = child.TypeName()
 			}
+			return childObj
 		}
 
 		if isMany {
 			// Many cardinality: array of objects
 			arr := make([]map[string]any, len(children))
 			for i, child := range children {
-				arr[i] = serializeInstance(child, edgeIdx, s)
+				arr[i] = serializeChild(child)
 			}
 			obj[fieldName] = arr
 		} else if len(children) > 0 {
 			// One cardinality: inline object
-			obj[fieldName] = serializeInstance(children[0], edgeIdx, s)
+			obj[fieldName] = serializeChild(children[0])
 		}
 	}
 
@@ -304,6 +321,47 @@ func unwrapValue(v immutable.Value) any {
 	return v.Unwrap()
 }
 
+// sortSets orders the elements of sets in v, including sets nested in List,
+// Map, Object and alias constraints, by canonical value order so that
+// output does not depend on input order.
+func sortSets(v any, c schema.Constraint) any {
+	for {
+		alias, ok := c.(schema.AliasConstraint)
+		if !ok || alias.Resolved() == nil {
+			break
+		}
+		c = alias.Resolved()
+	}
+	switch nc := c.(type) {
+	case schema.ListConstraint:
+		elems, ok := v.([]any)
+		if !ok {
+			return v
+		}
+		for i, elem := range elems {
+			elems[i] = sortSets(elem, nc.Element())
+		}
+		if nc.IsUnique() {
+			slices.SortStableFunc(elems, value.Compare)
+		}
+	case schema.MapConstraint:
+		if m, ok := v.(map[string]any); ok {
+			for k, val := range m {
+				m[k] = sortSets(val, nc.Value())
+			}
+		}
+	case schema.ObjectConstraint:
+		if m, ok := v.(map[string]any); ok {
+			for _, f := range nc.Fields() {
+				if val, ok := m[f.Name()]; ok {
+					m[f.Name()] = sortSets(val, f.Constraint())
+				}
+			}
+		}
+	}
+	return v
+}
+
 // serializeDiagnostics creates the $diagnostics section with unresolved edges and duplicates.
 func serializeDiagnostics(result *graph.Result, s *schema.Schema) map[string]any {
 	diag := make(map[string]any)
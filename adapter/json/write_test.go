@@ -1030,6 +1030,47 @@ func TestWriteObject_ShortWrite(t *testing.T) {
 	assert.Equal(t, int64(5), n)
 }
 
+func TestMarshalObject_SetsSorted(t *testing.T) {
+	ctx := t.Context()
+	s, result := build.NewBuilder().
+		WithName("sets").
+		WithSourceID(location.MustNewSourceID("test://sets.yammm")).
+		AddType("Item").
+		WithPrimaryKey("id", schema.StringConstraint{}).
+		WithProperty("tags", schema.NewSetConstraint(schema.NewStringConstraint())).
+		WithProperty("history", schema.NewListConstraint(schema.NewStringConstraint())).
+		WithProperty("groups", schema.NewMapConstraint(
+			schema.NewStringConstraint(),
+			schema.NewSetConstraint(schema.NewIntegerConstraint()),
+		)).
+		Done().
+		Build()
+	require.False(t, result.HasErrors(), result.String())
+	g := graph.New(s)
+
+	inst := mustValidInstance(t, s, "Item", []any{"i1"}, map[string]any{
+		"id":      "i1",
+		"tags":    []any{"c", "a", "b"},
+		"history": []any{"c", "a", "b"},
+		"groups":  map[string]any{"x": []any{int64(3), int64(1), int64(2)}},
+	})
+	_, err := g.Add(ctx, inst)
+	require.NoError(t, err)
+
+	adapter, err := NewAdapter(nil)
+	require.NoError(t, err)
+
+	data, err := adapter.MarshalObject(g.Snapshot())
+	require.NoError(t, err)
+
+	var output map[string]any
+	require.NoError(t, json.Unmarshal(data, &output))
+	item := output["Item"].([]any)[0].(map[string]any)
+	assert.Equal(t, []any{"a", "b", "c"}, item["tags"], "set elements are sorted")
+	assert.Equal(t, []any{"c", "a", "b"}, item["history"], "list order is preserved")
+	assert.Equal(t, map[string]any{"x": []any{float64(1), float64(2), float64(3)}}, item["groups"])
+}
+
 // limitedWriter is a test writer that only accepts up to `limit` bytes.
 type limitedWriter struct {
 	limit int
@@ -0,0 +1,72 @@
+package yaml
+
+import (
+	"github.com/simon-lentz/yammm/location"
+)
+
+// Adapter parses YAML data into RawInstance values with optional location tracking.
+//
+// Thread Safety: Adapter is safe for concurrent Parse* calls after construction.
+// No shared mutable state exists; all context flows through parameters.
+type Adapter struct {
+	registry       location.PositionRegistry
+	trackLocations bool
+	typeField      string
+}
+
+// ParseOption configures Adapter behavior.
+type ParseOption func(*Adapter)
+
+// NewAdapter creates a new YAML adapter with the given options.
+//
+// If WithTrackLocations(true) is set but registry is nil, returns an error.
+// The registry parameter may be nil if WithTrackLocations is not used.
+func NewAdapter(registry location.PositionRegistry, opts ...ParseOption) (*Adapter, error) {
+	a := &Adapter{
+		registry:       registry,
+		trackLocations: false, // Don't track locations by default
+		typeField:      "$type",
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	// Validate: can't track locations without a registry
+	if a.trackLocations && a.registry == nil {
+		return nil, ErrNilRegistry
+	}
+
+	// Validate: type field cannot be empty
+	if a.typeField == "" {
+		return nil, ErrEmptyTypeField
+	}
+
+	return a, nil
+}
+
+// WithTrackLocations enables source position tracking for parsed elements.
+//
+// When enabled, the adapter converts the line/column position of each parsed
+// node to a byte offset and resolves it via the PositionRegistry, so spans
+// carry line, column and byte offset. This enables accurate diagnostic
+// locations in error messages.
+//
+// Requires a non-nil PositionRegistry to be passed to NewAdapter.
+func WithTrackLocations(track bool) ParseOption {
+	return func(a *Adapter) {
+		a.trackLocations = track
+	}
+}
+
+// WithTypeField sets the field name used for type tagging in YAML mappings.
+//
+// Default is "$type". This field is used by ParseArray to determine which
+// type each mapping belongs to.
+//
+// Returns ErrEmptyTypeField from NewAdapter if field is empty.
+func WithTypeField(field string) ParseOption {
+	return func(a *Adapter) {
+		a.typeField = field
+	}
+}
@@ -0,0 +1,403 @@
+package yaml
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/internal/source"
+	"github.com/simon-lentz/yammm/location"
+)
+
+// trackingAdapter returns an adapter tracking locations in data, registered
+// under id.
+func trackingAdapter(t *testing.T, id string, data string) (*Adapter, location.SourceID) {
+	t.Helper()
+	reg := source.NewRegistry()
+	sourceID := location.NewSourceID(id)
+	require.NoError(t, reg.Register(sourceID, []byte(data)))
+	adapter, err := NewAdapter(reg, WithTrackLocations(true))
+	require.NoError(t, err)
+	return adapter, sourceID
+}
+
+func TestNewAdapter(t *testing.T) {
+	t.Run("nil registry without tracking", func(t *testing.T) {
+		adapter, err := NewAdapter(nil)
+		require.NoError(t, err)
+		assert.NotNil(t, adapter)
+	})
+
+	t.Run("nil registry with tracking returns error", func(t *testing.T) {
+		_, err := NewAdapter(nil, WithTrackLocations(true))
+		assert.Equal(t, ErrNilRegistry, err)
+	})
+
+	t.Run("empty type field returns error", func(t *testing.T) {
+		_, err := NewAdapter(nil, WithTypeField(""))
+		assert.Equal(t, ErrEmptyTypeField, err)
+	})
+}
+
+func TestParseObject(t *testing.T) {
+	source := location.NewSourceID("test://object.yaml")
+	adapter, _ := NewAdapter(nil)
+
+	t.Run("multiple types", func(t *testing.T) {
+		data := []byte(`
+Person:
+  - name: Alice
+    age: 30
+  - {name: Bob, age: 25}
+Company:
+  - title: Acme Inc
+`)
+		result, diags := adapter.ParseObject(source, data)
+		require.True(t, diags.OK(), "expected no errors: %v", diags)
+		require.Len(t, result["Person"], 2)
+		require.Len(t, result["Company"], 1)
+		assert.Equal(t, "Alice", result["Person"][0].Properties["name"])
+		assert.Equal(t, int64(30), result["Person"][0].Properties["age"])
+		assert.Equal(t, "Bob", result["Person"][1].Properties["name"])
+	})
+
+	t.Run("multi-document stream merges sections", func(t *testing.T) {
+		data := []byte("Person:\n  - name: Alice\n---\nPerson:\n  - name: Bob\nCompany: []\n---\n")
+		result, diags := adapter.ParseObject(source, data)
+		require.True(t, diags.OK(), "expected no errors: %v", diags)
+		require.Len(t, result["Person"], 2)
+		assert.Equal(t, "Bob", result["Person"][1].Properties["name"])
+		assert.NotContains(t, result, "Company")
+	})
+
+	t.Run("invalid type name", func(t *testing.T) {
+		result, diags := adapter.ParseObject(source, []byte("person:\n  - name: Alice\n"))
+		assert.False(t, diags.OK())
+		assert.Empty(t, result)
+	})
+
+	t.Run("section not a sequence", func(t *testing.T) {
+		_, diags := adapter.ParseObject(source, []byte("Person:\n  name: Alice\n"))
+		require.False(t, diags.OK())
+		assert.Contains(t, diags.Messages()[0], "expected sequence")
+	})
+
+	t.Run("root not a mapping", func(t *testing.T) {
+		_, diags := adapter.ParseObject(source, []byte("- a\n- b\n"))
+		require.False(t, diags.OK())
+		assert.Contains(t, diags.Messages()[0], "expected mapping at root")
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		_, diags := adapter.ParseObject(source, []byte("Person: [\n  {name: Alice\n"))
+		require.False(t, diags.OK())
+		issue := diags.IssuesSlice()[0]
+		assert.Equal(t, diag.E_ADAPTER_PARSE, issue.Code())
+		assert.Equal(t, "invalid YAML", issue.Message())
+	})
+}
+
+func TestParseArray(t *testing.T) {
+	source := location.NewSourceID("test://array.yaml")
+
+	t.Run("type tags", func(t *testing.T) {
+		adapter, _ := NewAdapter(nil)
+		data := []byte(`
+- $type: Person
+  name: Alice
+- $type: Company
+  title: Acme Inc
+`)
+		result, diags := adapter.ParseArray(source, data)
+		require.True(t, diags.OK(), "expected no errors: %v", diags)
+		require.Len(t, result["Person"], 1)
+		require.Len(t, result["Company"], 1)
+		assert.NotContains(t, result["Person"][0].Properties, "$type")
+	})
+
+	t.Run("one instance per document", func(t *testing.T) {
+		adapter, _ := NewAdapter(nil, WithTypeField("kind"))
+		data := []byte("kind: Person\nname: Alice\n---\nkind: Person\nname: Bob\n")
+		result, diags := adapter.ParseArray(source, data)
+		require.True(t, diags.OK(), "expected no errors: %v", diags)
+		require.Len(t, result["Person"], 2)
+		assert.Equal(t, "Bob", result["Person"][1].Properties["name"])
+	})
+
+	t.Run("tag errors", func(t *testing.T) {
+		adapter, _ := NewAdapter(nil)
+		data := []byte("- name: Alice\n- $type: 12\n- $type: lower\n")
+		_, diags := adapter.ParseArray(source, data)
+		require.Len(t, diags.IssuesSlice(), 3)
+		assert.Contains(t, diags.Messages(), "missing $type field in array element [0]")
+		assert.Contains(t, diags.Messages(), "invalid $type in array element [1]: expected string, got int64")
+		assert.Contains(t, diags.Messages(), `invalid type name "lower": type name must start with uppercase letter`)
+	})
+}
+
+func TestParseTypedArray(t *testing.T) {
+	source := location.NewSourceID("test://typed.yaml")
+	adapter, _ := NewAdapter(nil)
+
+	result, diags := adapter.ParseTypedArray(source, "Person", []byte("- name: Alice\n- null\n- name: Bob\n"))
+	require.Len(t, diags.IssuesSlice(), 1)
+	assert.Contains(t, diags.IssuesSlice()[0].Message(), "expected mapping")
+	require.Len(t, result, 2)
+	assert.Equal(t, "Bob", result[1].Properties["name"])
+
+	_, diags = adapter.ParseTypedArray(source, "person", []byte("- name: Alice\n"))
+	assert.False(t, diags.OK())
+}
+
+func TestParseOne(t *testing.T) {
+	source := location.NewSourceID("test://one.yaml")
+	adapter, _ := NewAdapter(nil)
+
+	raw, diags := adapter.ParseOne(source, "Person", []byte("name: Alice\nage: 30\n"))
+	require.True(t, diags.OK(), "expected no errors: %v", diags)
+	assert.Equal(t, map[string]any{"name": "Alice", "age": int64(30)}, raw.Properties)
+
+	_, diags = adapter.ParseOne(source, "Person", []byte(""))
+	assert.False(t, diags.OK(), "empty input")
+
+	_, diags = adapter.ParseOne(source, "Person", []byte("name: Alice\n---\nname: Bob\n"))
+	require.False(t, diags.OK())
+	assert.Contains(t, diags.Messages()[0], "unexpected content after root object")
+
+	_, diags = adapter.ParseOne(source, "Person", []byte("- name: Alice\n"))
+	require.False(t, diags.OK())
+	assert.Contains(t, diags.Messages()[0], "expected mapping")
+}
+
+func TestScalarResolution(t *testing.T) {
+	source := location.NewSourceID("test://scalars.yaml")
+	adapter, _ := NewAdapter(nil)
+
+	data := []byte(`
+country: no
+answer: yes
+switch: on
+flag: true
+upper: FALSE
+zip: 01234
+octal: 0o17
+hex: 0x1F
+int: -42
+big: 99999999999999999999
+float: 1.5
+exp: 1e3
+inf: -.inf
+nan: .nan
+date: 2020-01-01
+stamp: 2020-01-01T10:00:00Z
+underscored: 1_000
+sexagesimal: 1:30
+tilde: ~
+empty:
+quoted: "017"
+block: |
+  line one
+  line two
+tagged_str: !!str 42
+tagged_int: !!int "017"
+tagged_float: !!float 1
+tagged_bool: !!bool true
+`)
+	raw, diags := adapter.ParseOne(source, "Scalars", data)
+	require.True(t, diags.OK(), "expected no errors: %v", diags)
+	props := raw.Properties
+
+	tests := []struct {
+		key  string
+		want any
+	}{
+		{"country", "no"},
+		{"answer", "yes"},
+		{"switch", "on"},
+		{"flag", true},
+		{"upper", false},
+		{"zip", "01234"},
+		{"octal", int64(15)},
+		{"hex", int64(31)},
+		{"int", int64(-42)},
+		{"big", 1e20},
+		{"float", 1.5},
+		{"exp", 1000.0},
+		{"inf", math.Inf(-1)},
+		{"date", "2020-01-01"},
+		{"stamp", "2020-01-01T10:00:00Z"},
+		{"underscored", "1_000"},
+		{"sexagesimal", "1:30"},
+		{"tilde", nil},
+		{"empty", nil},
+		{"quoted", "017"},
+		{"block", "line one\nline two\n"},
+		{"tagged_str", "42"},
+		{"tagged_int", int64(17)},
+		{"tagged_float", 1.0},
+		{"tagged_bool", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			require.Contains(t, props, tt.key)
+			assert.Equal(t, tt.want, props[tt.key])
+		})
+	}
+	nan, ok := props["nan"].(float64)
+	require.True(t, ok)
+	assert.True(t, math.IsNaN(nan))
+}
+
+func TestScalarResolution_Errors(t *testing.T) {
+	source := location.NewSourceID("test://scalar-errors.yaml")
+	adapter, _ := NewAdapter(nil)
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"bad int tag", "n: !!int abc\n", `cannot resolve "abc" as !!int`},
+		{"bad bool tag", "b: !!bool yes\n", `cannot resolve "yes" as !!bool`},
+		{"unsupported tag", "b: !!binary aGVsbG8=\n", "unsupported tag !!binary"},
+		{"custom tag", "b: !color red\n", "unsupported tag !color"},
+		{"duplicate key", "a: 1\na: 2\n", `key "a" is defined more than once`},
+		{"non-scalar key", "? [a, b]\n: 1\n", "mapping key must be a scalar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, diags := adapter.ParseOne(source, "T", []byte(tt.data))
+			require.False(t, diags.OK())
+			issue := diags.IssuesSlice()[0]
+			assert.Equal(t, diag.E_ADAPTER_PARSE, issue.Code())
+			assert.Contains(t, issue.Details()[1].Value, tt.want)
+		})
+	}
+}
+
+func TestAnchorsAndAliases(t *testing.T) {
+	source := location.NewSourceID("test://anchors.yaml")
+	adapter, _ := NewAdapter(nil)
+
+	t.Run("aliases expand to copies", func(t *testing.T) {
+		data := []byte(`
+Person:
+  - &alice
+    name: Alice
+    tags: &tags [a, b]
+  - name: Bob
+    tags: *tags
+  - *alice
+`)
+		result, diags := adapter.ParseObject(source, data)
+		require.True(t, diags.OK(), "expected no errors: %v", diags)
+		people := result["Person"]
+		require.Len(t, people, 3)
+		assert.Equal(t, []any{"a", "b"}, people[1].Properties["tags"])
+		assert.Equal(t, people[0].Properties, people[2].Properties)
+
+		people[1].Properties["tags"].([]any)[0] = "changed"
+		assert.Equal(t, "a", people[0].Properties["tags"].([]any)[0], "aliases must not share values")
+	})
+
+	t.Run("merge keys", func(t *testing.T) {
+		data := []byte(`
+base: &base
+  country: DE
+  active: true
+extra: &extra
+  active: false
+  tier: gold
+person:
+  <<: [*base, *extra]
+  name: Alice
+  country: FR
+`)
+		raw, diags := adapter.ParseOne(source, "T", data)
+		require.True(t, diags.OK(), "expected no errors: %v", diags)
+		assert.Equal(t, map[string]any{
+			"name":    "Alice",
+			"country": "FR", // explicit keys win
+			"active":  true, // earlier merge sources win
+			"tier":    "gold",
+		}, raw.Properties["person"])
+	})
+
+	t.Run("self-referencing alias", func(t *testing.T) {
+		_, diags := adapter.ParseOne(source, "T", []byte("a: &x [1, *x]\n"))
+		require.False(t, diags.OK())
+		assert.Contains(t, diags.IssuesSlice()[0].Details()[1].Value, "refers to itself")
+	})
+
+	t.Run("alias expansion budget", func(t *testing.T) {
+		var sb strings.Builder
+		sb.WriteString("l0: &l0 [x, x, x, x, x, x, x, x, x, x]\n")
+		for i := 1; i <= 6; i++ {
+			prev := "*l" + string(rune('0'+i-1))
+			sb.WriteString("l" + string(rune('0'+i)) + ": &l" + string(rune('0'+i)) + " [")
+			sb.WriteString(strings.TrimSuffix(strings.Repeat(prev+", ", 10), ", "))
+			sb.WriteString("]\n")
+		}
+		_, diags := adapter.ParseOne(source, "T", []byte(sb.String()))
+		require.False(t, diags.OK())
+		assert.Contains(t, diags.IssuesSlice()[0].Details()[1].Value, "alias expansion exceeds")
+	})
+}
+
+func TestLocationTracking(t *testing.T) {
+	data := "# people\nPerson:\n  - name: Alice\n    note: \"x\\\"y\"\n  - {name: Bob, tags: [a]}\n  - name: Carol\n    bio: |\n      first\n      second\n\n  - name: Dörte\n    age: 3\n"
+	adapter, sourceID := trackingAdapter(t, "test://locations.yaml", data)
+
+	result, diags := adapter.ParseObject(sourceID, []byte(data))
+	require.True(t, diags.OK(), "expected no errors: %v", diags)
+	people := result["Person"]
+	require.Len(t, people, 4)
+
+	tests := []struct {
+		path       string
+		start, end [2]int // line, column
+		text       string
+	}{
+		{"$.Person[0]", [2]int{3, 5}, [2]int{4, 17}, "name: Alice\n    note: \"x\\\"y\""},
+		{"$.Person[1]", [2]int{5, 5}, [2]int{5, 27}, "{name: Bob, tags: [a]}"},
+		{"$.Person[2]", [2]int{6, 5}, [2]int{9, 13}, "name: Carol\n    bio: |\n      first\n      second"},
+		{"$.Person[3]", [2]int{11, 5}, [2]int{12, 11}, "name: Dörte\n    age: 3"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			prov := people[i].Provenance
+			require.NotNil(t, prov)
+			assert.Equal(t, tt.path, prov.Path().String())
+			span := prov.Span()
+			assert.Equal(t, tt.start, [2]int{span.Start.Line, span.Start.Column})
+			assert.Equal(t, tt.end, [2]int{span.End.Line, span.End.Column})
+			assert.Equal(t, tt.text, data[span.Start.Byte:span.End.Byte])
+		})
+	}
+}
+
+func TestLocationTracking_Errors(t *testing.T) {
+	data := "- $type: Person\n  name: Alice\n- name: Bob\n- $type: Person\n  name: x\n  name: y\n"
+	adapter, sourceID := trackingAdapter(t, "test://errors.yaml", data)
+
+	_, diags := adapter.ParseArray(sourceID, []byte(data))
+	issues := diags.IssuesSlice()
+	require.Len(t, issues, 2)
+	assert.Equal(t, diag.E_MISSING_TYPE_TAG, issues[0].Code())
+	assert.Equal(t, location.NewPosition(3, 3, 32), issues[0].Span().Start)
+	assert.Equal(t, diag.E_ADAPTER_PARSE, issues[1].Code(), "duplicate key")
+	assert.Equal(t, location.NewPosition(6, 3, 70), issues[1].Span().Start)
+
+	// Syntax errors are placed at the start of the line yaml.v3 reports.
+	bad := "a: 1\nb: [1,\nc: 2\n"
+	adapter, sourceID = trackingAdapter(t, "test://syntax.yaml", bad)
+	_, diags = adapter.ParseOne(sourceID, "T", []byte(bad))
+	require.False(t, diags.OK())
+	issue := diags.IssuesSlice()[0]
+	assert.True(t, issue.HasSpan())
+	assert.Equal(t, 1, issue.Span().Start.Column)
+}
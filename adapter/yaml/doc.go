@@ -0,0 +1,69 @@
+// Package yaml provides a YAML adapter for parsing instance data into
+// [instance.RawInstance] values with optional source location tracking.
+//
+// The adapter mirrors the parsing surface of the JSON adapter: [Adapter.ParseObject],
+// [Adapter.ParseArray], [Adapter.ParseTypedArray] and [Adapter.ParseOne] accept the
+// same shapes, written as YAML:
+//
+//	Person:
+//	  - name: Alice
+//	    age: 30
+//	Company:
+//	  - title: Acme Inc
+//
+// # Streams
+//
+// Input may be a multi-document stream. ParseObject merges the type sections of
+// all documents, appending instances of a type in stream order. ParseArray and
+// ParseTypedArray accept documents that are sequences of instances or a single
+// instance mapping; element indices run across the whole stream. ParseOne
+// requires exactly one document. Empty documents are skipped.
+//
+// # Anchors, Aliases and Merge Keys
+//
+// Aliases are expanded into independent copies of the anchored value, so
+// instances never share nested maps or slices. Merge keys (`<<: *base` or
+// `<<: [*a, *b]`) copy the keys of the merged mappings that are not set
+// explicitly; earlier mappings take precedence. An alias that refers to its
+// own anchor, or alias expansion beyond a fixed node budget, is a parse error.
+//
+// # Scalar Resolution
+//
+// Untagged plain scalars resolve by the YAML 1.2 core schema rather than the
+// YAML 1.1 rules many tools still apply:
+//
+//   - Only true and false (in lower, title or upper case) are booleans; yes, no,
+//     on, off, y and n stay strings, so a country code "no" is not false.
+//   - Decimal integers with a leading zero (017, 0042) stay strings rather
+//     than being read as octal; write 0o17 for an octal integer.
+//   - 0x1F is a hexadecimal integer; 1_000 and 1:30 stay strings.
+//   - Dates and timestamps stay strings for the Date and Timestamp
+//     constraints to check.
+//   - ~, null and an empty value are null; .inf and .nan are floats.
+//
+// Quoted and block scalars are always strings. An explicit tag (!!str, !!int,
+// !!float, !!bool, !!null, !!timestamp) overrides resolution; other tags are
+// parse errors. Integers are int64; integers outside the int64 range fall back
+// to float64, as in the JSON adapter. Mapping keys are the text of scalar keys;
+// a duplicated key is a parse error.
+//
+// # Location Tracking
+//
+// When WithTrackLocations(true) is set, each instance's [instance.Provenance]
+// spans the YAML text of its mapping, from its first key to the end of its
+// last value. Positions are resolved via the [location.PositionRegistry]
+// interface (typically implemented by schema.SourceRegistry), so the YAML
+// content must be registered with the registry before parsing to include
+// source excerpts in diagnostics.
+//
+// # Type Tag Resolution
+//
+// The adapter recognizes $type fields for type routing. Unqualified type names
+// resolve only to locally-defined types; imported types require alias-qualified
+// form (alias.Type).
+//
+// # Thread Safety
+//
+// The Adapter type is safe for concurrent Parse* calls after construction.
+// No shared mutable state exists; all context flows through parameters.
+package yaml
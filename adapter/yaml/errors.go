@@ -0,0 +1,9 @@
+package yaml
+
+import "errors"
+
+// ErrNilRegistry is returned when WithTrackLocations(true) is set but no registry was provided.
+var ErrNilRegistry = errors.New("yaml adapter: WithTrackLocations(true) requires a non-nil PositionRegistry")
+
+// ErrEmptyTypeField is returned when WithTypeField is called with an empty field name.
+var ErrEmptyTypeField = errors.New("yaml adapter: WithTypeField requires non-empty field name")
@@ -0,0 +1,539 @@
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/location"
+)
+
+// maxAliasNodes bounds the number of nodes produced by alias expansion in one
+// parse, guarding against exponential "billion laughs" documents.
+const maxAliasNodes = 100_000
+
+// Core schema tags.
+const (
+	tagStr       = "!!str"
+	tagInt       = "!!int"
+	tagFloat     = "!!float"
+	tagBool      = "!!bool"
+	tagNull      = "!!null"
+	tagTimestamp = "!!timestamp"
+	tagMerge     = "!!merge"
+)
+
+var (
+	decimalPattern = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	octalPattern   = regexp.MustCompile(`^0o[0-7]+$`)
+	hexPattern     = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+	floatPattern   = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+	errLinePattern = regexp.MustCompile(`^yaml: line ([0-9]+): `)
+)
+
+// decoder converts the nodes of one YAML stream to instance values and
+// collects the issues found along the way.
+type decoder struct {
+	a      *Adapter
+	source location.SourceID
+	data   []byte
+	issues []diag.Issue
+
+	lineStarts []int                 // byte offset of each line, built on first use
+	expanding  map[*yamlv3.Node]bool // anchors being expanded, for cycle detection
+	aliasNodes int                   // nodes produced by alias expansion
+}
+
+func (a *Adapter) newDecoder(source location.SourceID, data []byte) *decoder {
+	return &decoder{a: a, source: source, data: data, expanding: make(map[*yamlv3.Node]bool)}
+}
+
+// documents decodes the root node of every document in the stream. Empty
+// documents are returned as nil. Decoding stops at the first syntax error.
+func (d *decoder) documents() []*yamlv3.Node {
+	dec := yamlv3.NewDecoder(bytes.NewReader(d.data))
+	var docs []*yamlv3.Node
+	for {
+		var doc yamlv3.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs
+		}
+		if err != nil {
+			offset := 0
+			if m := errLinePattern.FindStringSubmatch(err.Error()); m != nil {
+				line, _ := strconv.Atoi(m[1])
+				offset = d.offset(line, 1)
+			}
+			d.parseError(offset, "invalid YAML", err.Error())
+			return docs
+		}
+		var root *yamlv3.Node
+		if len(doc.Content) > 0 && !isNull(doc.Content[0]) {
+			root = doc.Content[0]
+		}
+		docs = append(docs, root)
+	}
+}
+
+// isNull reports whether n is an untagged or !!null null scalar.
+func isNull(n *yamlv3.Node) bool {
+	if n.Kind != yamlv3.ScalarNode {
+		return false
+	}
+	v, err := resolveScalar(n)
+	return err == nil && v == nil
+}
+
+// resolve follows an alias to its anchored node, reporting a self-referencing
+// alias. It returns nil if the alias cannot be expanded.
+func (d *decoder) resolve(n *yamlv3.Node) *yamlv3.Node {
+	if n.Kind != yamlv3.AliasNode {
+		return n
+	}
+	if n.Alias == nil || d.expanding[n.Alias] {
+		d.parseError(d.start(n), "invalid alias", fmt.Sprintf("alias *%s refers to itself", n.Value))
+		return nil
+	}
+	return n.Alias
+}
+
+// value converts n to a Go value: map[string]any, []any, string, bool,
+// int64, float64 or nil.
+func (d *decoder) value(n *yamlv3.Node) (any, bool) {
+	if n.Kind == yamlv3.AliasNode {
+		target := d.resolve(n)
+		if target == nil {
+			return nil, false
+		}
+		d.expanding[target] = true
+		defer delete(d.expanding, target)
+		v, ok := d.value(target)
+		return v, ok
+	}
+	if len(d.expanding) > 0 {
+		d.aliasNodes++
+		if d.aliasNodes == maxAliasNodes+1 {
+			d.parseError(d.start(n), "invalid alias",
+				fmt.Sprintf("alias expansion exceeds %d nodes", maxAliasNodes))
+		}
+		if d.aliasNodes > maxAliasNodes {
+			return nil, false
+		}
+	}
+
+	switch n.Kind {
+	case yamlv3.MappingNode:
+		m, ok := d.mapping(n)
+		return m, ok
+	case yamlv3.SequenceNode:
+		result := make([]any, 0, len(n.Content))
+		ok := true
+		for _, elem := range n.Content {
+			v, elemOK := d.value(elem)
+			ok = ok && elemOK
+			result = append(result, v)
+		}
+		return result, ok
+	case yamlv3.ScalarNode:
+		v, err := resolveScalar(n)
+		if err != nil {
+			d.parseError(d.start(n), "invalid scalar", err.Error())
+			return nil, false
+		}
+		return v, true
+	default:
+		d.parseError(d.start(n), "unexpected YAML node", fmt.Sprintf("node kind %d", n.Kind))
+		return nil, false
+	}
+}
+
+// mapping converts a mapping node, applying merge keys.
+func (d *decoder) mapping(n *yamlv3.Node) (map[string]any, bool) {
+	result := make(map[string]any, len(n.Content)/2)
+	var merged []map[string]any
+	ok := true
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keyNode, valNode := n.Content[i], n.Content[i+1]
+		if keyNode.Kind == yamlv3.ScalarNode && keyNode.ShortTag() == tagMerge {
+			maps, mergeOK := d.mergeSources(valNode)
+			merged = append(merged, maps...)
+			ok = ok && mergeOK
+			continue
+		}
+
+		key, keyOK := d.key(keyNode)
+		if !keyOK {
+			ok = false
+			continue
+		}
+		if _, dup := result[key]; dup {
+			d.parseError(d.start(keyNode), "duplicate key", fmt.Sprintf("key %q is defined more than once", key))
+			ok = false
+			continue
+		}
+		v, valOK := d.value(valNode)
+		ok = ok && valOK
+		result[key] = v
+	}
+	for _, m := range merged {
+		for k, v := range m {
+			if _, set := result[k]; !set {
+				result[k] = v
+			}
+		}
+	}
+	return result, ok
+}
+
+// mergeSources converts the value of a merge key: a mapping or a sequence of
+// mappings, possibly through aliases.
+func (d *decoder) mergeSources(n *yamlv3.Node) ([]map[string]any, bool) {
+	target := d.resolve(n)
+	if target == nil {
+		return nil, false
+	}
+	nodes := []*yamlv3.Node{n}
+	if target.Kind == yamlv3.SequenceNode {
+		nodes = target.Content
+	}
+	var result []map[string]any
+	ok := true
+	for _, node := range nodes {
+		if t := d.resolve(node); t == nil || t.Kind != yamlv3.MappingNode {
+			if t != nil {
+				d.parseError(d.start(node), "invalid merge", "merge key value must be a mapping or a sequence of mappings")
+			}
+			ok = false
+			continue
+		}
+		v, vOK := d.value(node)
+		if m, isMap := v.(map[string]any); isMap {
+			result = append(result, m)
+		}
+		ok = ok && vOK
+	}
+	return result, ok
+}
+
+// key returns the text of a scalar mapping key.
+func (d *decoder) key(n *yamlv3.Node) (string, bool) {
+	target := d.resolve(n)
+	if target == nil {
+		return "", false
+	}
+	if target.Kind != yamlv3.ScalarNode {
+		d.parseError(d.start(n), "invalid key", "mapping key must be a scalar")
+		return "", false
+	}
+	return target.Value, true
+}
+
+// resolveScalar resolves a scalar node by its explicit tag, or by the YAML
+// 1.2 core schema if it is an untagged plain scalar. Other scalars are
+// strings.
+func resolveScalar(n *yamlv3.Node) (any, error) {
+	if n.Style&yamlv3.TaggedStyle == 0 {
+		if n.Style&(yamlv3.DoubleQuotedStyle|yamlv3.SingleQuotedStyle|yamlv3.LiteralStyle|yamlv3.FoldedStyle) != 0 {
+			return n.Value, nil
+		}
+		return resolvePlain(n.Value), nil
+	}
+
+	switch tag := n.ShortTag(); tag {
+	case tagStr, tagTimestamp:
+		return n.Value, nil
+	case tagNull:
+		return nil, nil
+	case tagBool:
+		if v, ok := resolvePlain(n.Value).(bool); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("cannot resolve %q as %s", n.Value, tag)
+	case tagInt:
+		if v, ok := resolveNumber(n.Value, true); ok {
+			if _, isInt := v.(int64); isInt {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot resolve %q as %s", n.Value, tag)
+	case tagFloat:
+		if v, ok := resolveNumber(n.Value, true); ok {
+			f, _ := toFloat(v)
+			return f, nil
+		}
+		return nil, fmt.Errorf("cannot resolve %q as %s", n.Value, tag)
+	default:
+		return nil, fmt.Errorf("unsupported tag %s", n.Tag)
+	}
+}
+
+// resolvePlain resolves the text of an untagged plain scalar.
+func resolvePlain(text string) any {
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if v, ok := resolveNumber(text, false); ok {
+		return v
+	}
+	return text
+}
+
+// resolveNumber resolves numeric text. Decimal numbers with a leading zero
+// are rejected unless allowLeadingZero is set, as YAML 1.1 tools read them
+// as octal.
+func resolveNumber(text string, allowLeadingZero bool) (any, bool) {
+	switch text {
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1), true
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1), true
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN(), true
+	}
+
+	switch {
+	case octalPattern.MatchString(text):
+		if i, err := strconv.ParseInt(text[2:], 8, 64); err == nil {
+			return i, true
+		}
+		return nil, false
+	case hexPattern.MatchString(text):
+		if i, err := strconv.ParseInt(text[2:], 16, 64); err == nil {
+			return i, true
+		}
+		return nil, false
+	case !floatPattern.MatchString(text):
+		return nil, false
+	}
+
+	digits := strings.TrimLeft(text, "+-")
+	if !allowLeadingZero && len(digits) > 1 && digits[0] == '0' && digits[1] != '.' &&
+		digits[1] != 'e' && digits[1] != 'E' {
+		return nil, false
+	}
+	if decimalPattern.MatchString(text) {
+		if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return i, true
+		}
+	}
+	// Non-integers, and integers outside the int64 range, are float64.
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return nil, false
+	}
+	return f, true
+}
+
+// toFloat converts a resolved number to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// start returns the byte offset at which n's source text starts.
+func (d *decoder) start(n *yamlv3.Node) int {
+	return d.offset(n.Line, n.Column)
+}
+
+// end returns the byte offset just past n's source text.
+func (d *decoder) end(n *yamlv3.Node) int {
+	start := d.start(n)
+	switch n.Kind {
+	case yamlv3.DocumentNode:
+		if len(n.Content) > 0 {
+			return d.end(n.Content[len(n.Content)-1])
+		}
+		return start
+	case yamlv3.MappingNode, yamlv3.SequenceNode:
+		if n.Style&yamlv3.FlowStyle == 0 {
+			if len(n.Content) == 0 {
+				return start
+			}
+			return d.end(n.Content[len(n.Content)-1])
+		}
+		closing := byte('}')
+		if n.Kind == yamlv3.SequenceNode {
+			closing = ']'
+		}
+		from := start + 1
+		if len(n.Content) > 0 {
+			from = d.end(n.Content[len(n.Content)-1])
+		}
+		return d.skipTo(from, closing)
+	case yamlv3.AliasNode:
+		return min(start+1+len(n.Value), len(d.data))
+	default:
+		return d.scalarEnd(n, start)
+	}
+}
+
+// scalarEnd returns the byte offset just past the text of scalar n starting
+// at start.
+func (d *decoder) scalarEnd(n *yamlv3.Node, start int) int {
+	data := d.data
+	switch {
+	case n.Style&yamlv3.DoubleQuotedStyle != 0:
+		for i := start + 1; i < len(data); i++ {
+			switch data[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+	case n.Style&yamlv3.SingleQuotedStyle != 0:
+		for i := start + 1; i < len(data); i++ {
+			if data[i] != '\'' {
+				continue
+			}
+			if i+1 < len(data) && data[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	case n.Style&(yamlv3.LiteralStyle|yamlv3.FoldedStyle) != 0:
+		return d.blockEnd(n)
+	case bytes.HasPrefix(data[min(start, len(data)):], []byte(n.Value)):
+		return start + len(n.Value)
+	}
+	return d.lineEnd(start)
+}
+
+// blockEnd returns the end of a block scalar: the last non-blank line
+// indented deeper than the line holding its indicator.
+func (d *decoder) blockEnd(n *yamlv3.Node) int {
+	d.buildLines()
+	end := d.lineEnd(d.start(n))
+	base := d.indent(n.Line)
+	for line := n.Line + 1; line <= len(d.lineStarts); line++ {
+		text := bytes.TrimSpace(d.lineText(line))
+		if len(text) == 0 {
+			continue
+		}
+		if d.indent(line) <= base {
+			break
+		}
+		end = d.lineEnd(d.lineStarts[line-1])
+	}
+	return end
+}
+
+// skipTo returns the offset just past the closing byte following from,
+// skipping whitespace, commas and comments. It returns from if another byte
+// comes first.
+func (d *decoder) skipTo(from int, closing byte) int {
+	for i := from; i < len(d.data); i++ {
+		switch c := d.data[i]; c {
+		case ' ', '\t', '\r', '\n', ',':
+		case '#':
+			i = d.lineEnd(i)
+		case closing:
+			return i + 1
+		default:
+			return from
+		}
+	}
+	return from
+}
+
+// offset converts a 1-based line and rune column to a byte offset.
+func (d *decoder) offset(line, column int) int {
+	d.buildLines()
+	if line < 1 || line > len(d.lineStarts) {
+		return len(d.data)
+	}
+	offset := d.lineStarts[line-1]
+	for range column - 1 {
+		if offset >= len(d.data) || d.data[offset] == '\n' {
+			break
+		}
+		_, size := utf8.DecodeRune(d.data[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// lineEnd returns the offset of the end of the line containing offset,
+// excluding the line terminator and trailing whitespace.
+func (d *decoder) lineEnd(offset int) int {
+	end := offset
+	for end < len(d.data) && d.data[end] != '\n' {
+		end++
+	}
+	for end > offset && (d.data[end-1] == ' ' || d.data[end-1] == '\t' || d.data[end-1] == '\r') {
+		end--
+	}
+	return end
+}
+
+// lineText returns the text of a 1-based line without its terminator.
+func (d *decoder) lineText(line int) []byte {
+	start := d.lineStarts[line-1]
+	end := len(d.data)
+	if line < len(d.lineStarts) {
+		end = d.lineStarts[line] - 1
+	}
+	return d.data[start:end]
+}
+
+// indent returns the number of leading spaces of a 1-based line.
+func (d *decoder) indent(line int) int {
+	text := d.lineText(line)
+	return len(text) - len(bytes.TrimLeft(text, " "))
+}
+
+// buildLines indexes the line starts of the data.
+func (d *decoder) buildLines() {
+	if d.lineStarts != nil {
+		return
+	}
+	d.lineStarts = []int{0}
+	for i, c := range d.data {
+		if c == '\n' {
+			d.lineStarts = append(d.lineStarts, i+1)
+		}
+	}
+}
+
+// parseError records an E_ADAPTER_PARSE issue.
+// msg is the human-readable message; detail is the machine-oriented parse detail.
+func (d *decoder) parseError(offset int, msg, detail string) {
+	ib := diag.NewIssue(diag.Error, diag.E_ADAPTER_PARSE, msg).
+		WithDetail(diag.DetailKeyFormat, "yaml").
+		WithDetail(diag.DetailKeyDetail, detail)
+	d.withSpan(ib, offset)
+	d.issues = append(d.issues, ib.Build())
+}
+
+// withSpan attaches a point span at offset when locations are tracked.
+func (d *decoder) withSpan(ib *diag.IssueBuilder, offset int) {
+	if !d.a.trackLocations || d.a.registry == nil {
+		return
+	}
+	pos := d.a.registry.PositionAt(d.source, offset)
+	// Guard: only attach span if position is valid
+	if !pos.IsZero() {
+		ib.WithSpan(location.Span{Source: d.source, Start: pos, End: pos})
+	}
+}
@@ -0,0 +1,337 @@
+package yaml
+
+import (
+	"fmt"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/simon-lentz/yammm/adapter/internal/typetag"
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/instance"
+	"github.com/simon-lentz/yammm/instance/path"
+	"github.com/simon-lentz/yammm/location"
+)
+
+// ParseObject parses YAML data structured as a mapping of type names to
+// sequences of instances.
+//
+// Each top-level key is a type name, and its value must be a sequence of
+// instances. In a multi-document stream the sections of every document are
+// merged. Returns a map of type name -> slice of RawInstance.
+//
+// Example input:
+//
+//	Person:
+//	  - name: Alice
+//	  - name: Bob
+//	Company:
+//	  - title: Acme Inc
+func (a *Adapter) ParseObject(source location.SourceID, data []byte) (map[string][]instance.RawInstance, diag.Result) {
+	d := a.newDecoder(source, data)
+	result := make(map[string][]instance.RawInstance)
+	counts := make(map[string]int) // elements seen per type, for paths
+
+	for _, doc := range d.documents() {
+		if doc == nil {
+			continue
+		}
+		root := d.resolve(doc)
+		if root == nil {
+			continue
+		}
+		if root.Kind != yamlv3.MappingNode {
+			d.parseError(d.start(doc), "expected mapping at root", "expected mapping")
+			continue
+		}
+
+		// Read each type name -> sequence pair
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			keyNode, valNode := root.Content[i], root.Content[i+1]
+			typeName, ok := d.key(keyNode)
+			if !ok {
+				continue
+			}
+			if err := typetag.Validate(typeName); err != nil {
+				d.typeTagError(d.start(keyNode), typeName, err)
+				continue
+			}
+
+			basePath := path.Root().Key(typeName)
+			instances, n := d.sequence(valNode, basePath, counts[typeName])
+			counts[typeName] += n
+			if len(instances) > 0 {
+				result[typeName] = append(result[typeName], instances...)
+			}
+		}
+	}
+
+	return result, d.result()
+}
+
+// ParseArray parses YAML data as a sequence of mappings with $type fields.
+//
+// Each mapping must have a $type field (or the configured type field)
+// specifying its type name. A document may also be a single mapping, so a
+// stream can hold one instance per document. Returns a map of type name ->
+// slice of RawInstance.
+//
+// Example input:
+//
+//	---
+//	- $type: Person
+//	  name: Alice
+//	- $type: Company
+//	  title: Acme Inc
+func (a *Adapter) ParseArray(source location.SourceID, data []byte) (map[string][]instance.RawInstance, diag.Result) {
+	d := a.newDecoder(source, data)
+	result := make(map[string][]instance.RawInstance)
+
+	idx := 0
+	for _, elem := range d.elements() {
+		raw, ok := d.instance(elem, path.Root().Index(idx))
+		if !ok {
+			idx++
+			continue
+		}
+
+		// Extract and validate type tag
+		typeTagVal, hasType := raw.Properties[a.typeField]
+		if !hasType {
+			d.missingTypeTagError(d.start(elem), idx)
+			idx++
+			continue
+		}
+		typeName, ok := typeTagVal.(string)
+		if !ok {
+			d.invalidTypeTagError(d.start(elem), idx, "expected string", fmt.Sprintf("%T", typeTagVal))
+			idx++
+			continue
+		}
+		if err := typetag.Validate(typeName); err != nil {
+			d.typeTagError(d.start(elem), typeName, err)
+			idx++
+			continue
+		}
+
+		// Remove the type field from properties
+		delete(raw.Properties, a.typeField)
+
+		result[typeName] = append(result[typeName], raw)
+		idx++
+	}
+
+	return result, d.result()
+}
+
+// ParseTypedArray parses a YAML sequence where all mappings are of the
+// specified type.
+//
+// Mappings do not need a $type field; the type is provided explicitly. As in
+// ParseArray, a document may also be a single mapping. Returns a slice of
+// RawInstance.
+//
+// Example input (with typeName="Person"):
+//
+//	---
+//	- name: Alice
+//	- name: Bob
+func (a *Adapter) ParseTypedArray(source location.SourceID, typeName string, data []byte) ([]instance.RawInstance, diag.Result) {
+	d := a.newDecoder(source, data)
+
+	// Validate type name
+	if err := typetag.Validate(typeName); err != nil {
+		d.typeTagError(0, typeName, err)
+		return nil, d.result()
+	}
+
+	var result []instance.RawInstance
+	for idx, elem := range d.elements() {
+		if raw, ok := d.instance(elem, path.Root().Index(idx)); ok {
+			result = append(result, raw)
+		}
+	}
+
+	return result, d.result()
+}
+
+// ParseOne parses a single YAML mapping of the specified type.
+//
+// The mapping does not need a $type field; the type is provided explicitly.
+// The input must hold exactly one document.
+//
+// Example input (with typeName="Person"):
+//
+//	name: Alice
+//	age: 30
+func (a *Adapter) ParseOne(source location.SourceID, typeName string, data []byte) (instance.RawInstance, diag.Result) {
+	d := a.newDecoder(source, data)
+
+	// Validate type name
+	if err := typetag.Validate(typeName); err != nil {
+		d.typeTagError(0, typeName, err)
+		return instance.RawInstance{}, d.result()
+	}
+
+	docs := d.documents()
+	if len(d.issues) > 0 {
+		return instance.RawInstance{}, d.result()
+	}
+	if len(docs) == 0 || docs[0] == nil {
+		d.parseError(0, "expected mapping", "got empty document")
+		return instance.RawInstance{}, d.result()
+	}
+	if len(docs) > 1 {
+		next := len(d.data)
+		if docs[1] != nil {
+			next = d.start(docs[1])
+		}
+		d.parseError(next, "unexpected content after root object", fmt.Sprintf("found %d documents", len(docs)))
+	}
+
+	raw, ok := d.instance(docs[0], path.Root())
+	if !ok {
+		return instance.RawInstance{}, d.result()
+	}
+	return raw, d.result()
+}
+
+// elements returns the instance nodes of a stream whose documents are
+// sequences of instances or single instances.
+func (d *decoder) elements() []*yamlv3.Node {
+	var elems []*yamlv3.Node
+	for _, doc := range d.documents() {
+		if doc == nil {
+			continue
+		}
+		root := d.resolve(doc)
+		switch {
+		case root == nil:
+		case root.Kind == yamlv3.SequenceNode:
+			elems = append(elems, root.Content...)
+		case root.Kind == yamlv3.MappingNode:
+			elems = append(elems, doc)
+		default:
+			d.parseError(d.start(doc), "expected sequence at root", "expected sequence or mapping")
+		}
+	}
+	return elems
+}
+
+// sequence converts a sequence of instances, numbering them from first. It
+// also returns the number of elements, including those that failed.
+func (d *decoder) sequence(n *yamlv3.Node, basePath path.Builder, first int) ([]instance.RawInstance, int) {
+	seq := d.resolve(n)
+	if seq == nil {
+		return nil, 0
+	}
+	if seq.Kind != yamlv3.SequenceNode {
+		d.parseError(d.start(n), "expected sequence", "expected sequence")
+		return nil, 0
+	}
+
+	var result []instance.RawInstance
+	for i, elem := range seq.Content {
+		if raw, ok := d.instance(elem, basePath.Index(first+i)); ok {
+			result = append(result, raw)
+		}
+	}
+	return result, len(seq.Content)
+}
+
+// instance converts a mapping node to a RawInstance at path p.
+func (d *decoder) instance(n *yamlv3.Node, p path.Builder) (instance.RawInstance, bool) {
+	target := d.resolve(n)
+	if target == nil {
+		return instance.RawInstance{}, false
+	}
+	if target.Kind != yamlv3.MappingNode {
+		got := "got " + nodeKind(target)
+		d.parseError(d.start(n), "expected mapping", got)
+		return instance.RawInstance{}, false
+	}
+
+	v, ok := d.value(n)
+	if !ok {
+		return instance.RawInstance{}, false
+	}
+	props, _ := v.(map[string]any)
+	raw := instance.RawInstance{
+		Properties: props,
+	}
+
+	if d.a.trackLocations && d.a.registry != nil {
+		raw.Provenance = d.makeProvenance(p, d.start(n), d.end(n))
+	}
+	return raw, true
+}
+
+// nodeKind describes the kind of a node for messages.
+func nodeKind(n *yamlv3.Node) string {
+	switch n.Kind {
+	case yamlv3.SequenceNode:
+		return "sequence"
+	case yamlv3.MappingNode:
+		return "mapping"
+	case yamlv3.ScalarNode:
+		if isNull(n) {
+			return "null"
+		}
+		return "scalar"
+	}
+	return "unknown node"
+}
+
+// result returns the collected issues as a diag.Result.
+func (d *decoder) result() diag.Result {
+	collector := diag.NewCollectorUnlimited()
+	for i := range d.issues {
+		collector.Collect(d.issues[i])
+	}
+	return collector.Result()
+}
+
+// makeProvenance creates a Provenance from byte offsets.
+// If positions cannot be determined (IsZero), the span preserves only the source identity.
+func (d *decoder) makeProvenance(p path.Builder, startOffset, endOffset int) *instance.Provenance {
+	startPos := d.a.registry.PositionAt(d.source, startOffset)
+	endPos := d.a.registry.PositionAt(d.source, endOffset)
+
+	var span location.Span
+	if !startPos.IsZero() && !endPos.IsZero() {
+		span = location.Span{Source: d.source, Start: startPos, End: endPos}
+	} else {
+		// Preserve source identity for diagnostics even without precise positions
+		span = location.Span{Source: d.source}
+	}
+
+	return instance.NewProvenance(d.source.String(), p, span)
+}
+
+// missingTypeTagError records an E_MISSING_TYPE_TAG issue.
+func (d *decoder) missingTypeTagError(offset int, idx int) {
+	msg := fmt.Sprintf("missing %s field in array element [%d]", d.a.typeField, idx)
+	ib := diag.NewIssue(diag.Error, diag.E_MISSING_TYPE_TAG, msg)
+	d.withSpan(ib, offset)
+	d.issues = append(d.issues, ib.Build())
+}
+
+// invalidTypeTagError records an E_INVALID_TYPE_TAG issue.
+// detail is a canonical reason string; got is the observed value/type.
+func (d *decoder) invalidTypeTagError(offset int, idx int, detail, got string) {
+	msg := fmt.Sprintf("invalid %s in array element [%d]: %s, got %s", d.a.typeField, idx, detail, got)
+	ib := diag.NewIssue(diag.Error, diag.E_INVALID_TYPE_TAG, msg).
+		WithDetail(diag.DetailKeyDetail, detail).
+		WithDetail(diag.DetailKeyGot, got)
+	d.withSpan(ib, offset)
+	d.issues = append(d.issues, ib.Build())
+}
+
+// typeTagError records an E_INVALID_TYPE_TAG issue for type name validation errors.
+func (d *decoder) typeTagError(offset int, typeName string, err error) {
+	msg := fmt.Sprintf("invalid type name %q: %s", typeName, err.Error())
+	ib := diag.NewIssue(diag.Error, diag.E_INVALID_TYPE_TAG, msg).
+		WithDetail(diag.DetailKeyGot, typeName).
+		WithDetail(diag.DetailKeyDetail, err.Error())
+	d.withSpan(ib, offset)
+	d.issues = append(d.issues, ib.Build())
+}
@@ -2,6 +2,7 @@ package diag
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/simon-lentz/yammm/location"
 )
@@ -97,6 +98,8 @@ func FromIssue(issue Issue) *IssueBuilder {
 		b.issue.details = make([]Detail, len(issue.details))
 		copy(b.issue.details, issue.details)
 	}
+	b.issue.fixes = cloneFixes(issue.fixes)
+	b.issue.localized = slices.Clone(issue.localized)
 	return b
 }
 
@@ -127,6 +130,36 @@ func (b *IssueBuilder) WithHint(hint string) *IssueBuilder {
 	return b
 }
 
+// WithSuggestion records the name an unknown name was most likely meant to
+// be. It sets the hint to `did you mean "<suggestion>"?` and adds the
+// suggestion under [DetailKeySuggestion]. An empty suggestion is ignored.
+func (b *IssueBuilder) WithSuggestion(suggestion string) *IssueBuilder {
+	if suggestion == "" {
+		return b
+	}
+	b.issue.hint = fmt.Sprintf("did you mean %q?", suggestion)
+	return b.WithDetail(DetailKeySuggestion, suggestion)
+}
+
+// WithLocalizedMessage records the issue's message in another locale, for
+// renderers configured with [WithLocale] (see [Issue.LocalizedMessage]). A
+// later message for the same normalized locale replaces an earlier one. An
+// empty locale or message is ignored.
+func (b *IssueBuilder) WithLocalizedMessage(locale, message string) *IssueBuilder {
+	locale = NormalizeLocale(locale)
+	if locale == "" || message == "" {
+		return b
+	}
+	for i, m := range b.issue.localized {
+		if m.locale == locale {
+			b.issue.localized[i].message = message
+			return b
+		}
+	}
+	b.issue.localized = append(b.issue.localized, localizedMessage{locale: locale, message: message})
+	return b
+}
+
 // WithRelated adds related location information.
 //
 // Related locations provide context like "previous definition here" for
@@ -172,6 +205,34 @@ func (b *IssueBuilder) WithDetails(details ...Detail) *IssueBuilder {
 	return b
 }
 
+// WithFix adds a machine-applicable fix suggestion.
+//
+// title is a short imperative description (e.g., `Replace with "Person"`),
+// and edits are applied together when the fix is chosen. Edit spans are in
+// the same coordinates as [IssueBuilder.WithSpan]; they may point at other
+// sources than the issue itself (e.g., adding an import).
+//
+// WithFix panics if title is empty, no edits are given, or an edit has a
+// zero span, since such a fix cannot be applied.
+//
+// Multiple calls to WithFix append to the existing fix list. List the
+// preferred fix first.
+func (b *IssueBuilder) WithFix(title string, edits ...TextEdit) *IssueBuilder {
+	if title == "" {
+		panic("diag.IssueBuilder.WithFix: empty title")
+	}
+	if len(edits) == 0 {
+		panic("diag.IssueBuilder.WithFix: no edits")
+	}
+	for _, e := range edits {
+		if e.Span.IsZero() {
+			panic("diag.IssueBuilder.WithFix: edit with zero span")
+		}
+	}
+	b.issue.fixes = append(b.issue.fixes, Fix{Title: title, Edits: slices.Clone(edits)})
+	return b
+}
+
 // WithExpectedGot is a convenience for type mismatch issues.
 //
 // This is equivalent to calling WithDetails(ExpectedGot(expected, got)...).
@@ -181,9 +242,9 @@ func (b *IssueBuilder) WithExpectedGot(expected, got string) *IssueBuilder {
 
 // Build returns the constructed issue.
 //
-// Build deep-copies the related and details slices into fresh, tight-capacity
-// slices. This ensures builder reuse cannot mutate previously-built issues
-// (immutability guarantee).
+// Build deep-copies the related, details, fixes, and localized message
+// slices into fresh, tight-capacity slices. This ensures builder reuse cannot mutate
+// previously-built issues (immutability guarantee).
 //
 // The returned issue is guaranteed to be valid (IsValid() returns true)
 // because NewIssue requires severity, code, and message.
@@ -199,6 +260,8 @@ func (b *IssueBuilder) Build() Issue {
 		result.details = make([]Detail, len(b.issue.details))
 		copy(result.details, b.issue.details)
 	}
+	result.fixes = cloneFixes(b.issue.fixes)
+	result.localized = slices.Clone(b.issue.localized)
 
 	return result
 }
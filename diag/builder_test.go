@@ -62,6 +62,25 @@ func TestIssueBuilder_WithHint(t *testing.T) {
 	}
 }
 
+func TestIssueBuilder_WithSuggestion(t *testing.T) {
+	issue := NewIssue(Error, E_UNKNOWN_TYPE, `unknown type "Persn"`).
+		WithSuggestion("Person").
+		Build()
+
+	if issue.Hint() != `did you mean "Person"?` {
+		t.Errorf("Hint() = %q; want %q", issue.Hint(), `did you mean "Person"?`)
+	}
+	details := issue.Details()
+	if len(details) != 1 || details[0] != (Detail{Key: DetailKeySuggestion, Value: "Person"}) {
+		t.Errorf("Details() = %v; want suggestion detail", details)
+	}
+
+	plain := NewIssue(Error, E_UNKNOWN_TYPE, "test").WithHint("keep").WithSuggestion("").Build()
+	if plain.Hint() != "keep" || len(plain.Details()) != 0 {
+		t.Errorf("empty suggestion changed the issue: hint=%q details=%v", plain.Hint(), plain.Details())
+	}
+}
+
 func TestIssueBuilder_WithRelated(t *testing.T) {
 	source := location.MustNewSourceID("test://schema.yammm")
 	related1 := location.RelatedInfo{
@@ -174,6 +193,74 @@ func TestIssueBuilder_WithExpectedGot(t *testing.T) {
 	}
 }
 
+func TestIssueBuilder_WithFix(t *testing.T) {
+	source := location.MustNewSourceID("test://schema.yammm")
+	edit := TextEdit{Span: location.Range(source, 2, 1, 2, 6), NewText: "Person"}
+	edits := []TextEdit{edit}
+
+	b := NewIssue(Error, E_UNKNOWN_TYPE, "unknown type").
+		WithFix("Replace with Person", edits...).
+		WithFix("Remove", TextEdit{Span: edit.Span})
+	issue := b.Build()
+
+	// Mutating the caller's slice or reusing the builder must not affect the issue.
+	edits[0].NewText = "mutated"
+	b.WithFix("Later", edit)
+
+	fixes := issue.Fixes()
+	if len(fixes) != 2 {
+		t.Fatalf("len(Fixes()) = %d; want 2", len(fixes))
+	}
+	if fixes[0].Title != "Replace with Person" || fixes[0].Edits[0].NewText != "Person" {
+		t.Errorf("Fixes()[0] = %+v", fixes[0])
+	}
+	if fixes[1].Title != "Remove" || fixes[1].Edits[0].NewText != "" {
+		t.Errorf("Fixes()[1] = %+v", fixes[1])
+	}
+
+	// The accessor returns a deep copy.
+	fixes[0].Edits[0].NewText = "mutated"
+	if got := issue.Fixes()[0].Edits[0].NewText; got != "Person" {
+		t.Errorf("Fixes() should return a deep copy; edit text = %q", got)
+	}
+
+	// FromIssue and Clone preserve fixes.
+	if got := FromIssue(issue).Build().Fixes(); len(got) != 2 {
+		t.Errorf("FromIssue dropped fixes: %+v", got)
+	}
+	if got := issue.Clone().Fixes(); len(got) != 2 {
+		t.Errorf("Clone dropped fixes: %+v", got)
+	}
+	if NewIssue(Error, E_SYNTAX, "no fix").Build().Fixes() != nil {
+		t.Error("Fixes() should be nil without fixes")
+	}
+}
+
+func TestIssueBuilder_WithFix_Panics(t *testing.T) {
+	source := location.MustNewSourceID("test://schema.yammm")
+	valid := TextEdit{Span: location.Point(source, 1, 1), NewText: "x"}
+
+	tests := []struct {
+		name  string
+		title string
+		edits []TextEdit
+	}{
+		{"empty title", "", []TextEdit{valid}},
+		{"no edits", "Fix it", nil},
+		{"zero span", "Fix it", []TextEdit{{NewText: "x"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("WithFix should panic")
+				}
+			}()
+			NewIssue(Error, E_SYNTAX, "test").WithFix(tt.title, tt.edits...)
+		})
+	}
+}
+
 func TestIssueBuilder_FluentChaining(t *testing.T) {
 	source := location.MustNewSourceID("test://schema.yammm")
 	span := location.Point(source, 10, 5)
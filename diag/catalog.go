@@ -0,0 +1,232 @@
+package diag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Catalog holds translated message templates for issues, keyed by locale and
+// code. A [Renderer] configured with [WithLocale] and [WithCatalog] renders
+// issues with the catalog's templates.
+//
+// A template is text with placeholders naming detail keys: in
+// "Pflichtfeld {property} fehlt", {property} is replaced by the issue's
+// [DetailKeyPropertyName] detail. Write {{ and }} for literal braces. An issue
+// lacking a detail its template names keeps its original text, so a
+// translation is never rendered with a hole in it.
+//
+// Locales are matched as by [NormalizeLocale]; a regional locale falls back
+// to its language, so "de-CH" uses "de" templates when it has none of its
+// own.
+//
+// Configure a catalog with the chaining methods before sharing it. A catalog
+// that is no longer modified is safe for concurrent use. A nil catalog has
+// no templates.
+//
+//	catalog := diag.NewCatalog().
+//	    Add("de", diag.E_MISSING_REQUIRED, "Pflichtfeld {property} fehlt").
+//	    Add("de", diag.E_CONSTRAINT_FAIL, "Eigenschaft {property}: Wert ungültig ({detail})")
+type Catalog struct {
+	messages map[catalogKey]messageTemplate
+	hints    map[catalogKey]messageTemplate
+}
+
+// catalogKey identifies the templates of a code in a normalized locale.
+type catalogKey struct {
+	locale string
+	code   Code
+}
+
+// messageTemplate is a parsed template: literal text alternating with
+// placeholders.
+type messageTemplate []templatePart
+
+// templatePart is literal text, or a placeholder when key is set.
+type templatePart struct {
+	text string
+	key  string
+}
+
+// NewCatalog creates an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{}
+}
+
+// Add registers the message template for issues with the given code in
+// locale, replacing any earlier one.
+//
+// Panics if locale is empty, code is zero, or the template is malformed.
+func (c *Catalog) Add(locale string, code Code, template string) *Catalog {
+	c.messages = addTemplate(c.messages, "Add", locale, code, template)
+	return c
+}
+
+// AddHint registers the template for the hint of issues with the given code
+// in locale, replacing any earlier one. Issues without a hint are not given
+// one.
+//
+// Panics if locale is empty, code is zero, or the template is malformed.
+func (c *Catalog) AddHint(locale string, code Code, template string) *Catalog {
+	c.hints = addTemplate(c.hints, "AddHint", locale, code, template)
+	return c
+}
+
+// addTemplate parses template and stores it in m under locale and code.
+func addTemplate(m map[catalogKey]messageTemplate, method, locale string, code Code, template string) map[catalogKey]messageTemplate {
+	locale = NormalizeLocale(locale)
+	if locale == "" {
+		panic(fmt.Sprintf("diag.Catalog.%s: empty locale", method))
+	}
+	if code.IsZero() {
+		panic(fmt.Sprintf("diag.Catalog.%s: zero code", method))
+	}
+	t, err := parseTemplate(template)
+	if err != nil {
+		panic(fmt.Sprintf("diag.Catalog.%s: %s template for %s: %v", method, locale, code, err))
+	}
+	if m == nil {
+		m = make(map[catalogKey]messageTemplate)
+	}
+	m[catalogKey{locale: locale, code: code}] = t
+	return m
+}
+
+// Localize returns the issue with its message and hint in locale.
+//
+// The message is taken from the issue's own message for the locale (see
+// [IssueBuilder.WithLocalizedMessage]) if it has one, and otherwise from the
+// catalog's template for the issue's code. Parts with no translation keep
+// their original text. An empty locale returns the issue unchanged.
+func (c *Catalog) Localize(issue Issue, locale string) Issue {
+	chain := localeChain(locale)
+	if len(chain) == 0 {
+		return issue
+	}
+
+	if msg, ok := issueMessage(issue, chain); ok {
+		issue.message = msg
+	} else if msg, ok := c.render(c.messages, issue, chain); ok {
+		issue.message = msg
+	}
+	if issue.hint != "" {
+		if hint, ok := c.render(c.hints, issue, chain); ok {
+			issue.hint = hint
+		}
+	}
+	return issue
+}
+
+// render renders the first template of m for the issue's code along the
+// locale chain that the issue has the details for.
+func (c *Catalog) render(m map[catalogKey]messageTemplate, issue Issue, chain []string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	for _, locale := range chain {
+		if t, ok := m[catalogKey{locale: locale, code: issue.code}]; ok {
+			return t.render(issue)
+		}
+	}
+	return "", false
+}
+
+// issueMessage returns the issue's own message for the first locale of chain
+// it has one for.
+func issueMessage(issue Issue, chain []string) (string, bool) {
+	for _, locale := range chain {
+		if msg, ok := issue.LocalizedMessage(locale); ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// NormalizeLocale returns the canonical form of a locale tag: lower case,
+// with '_' separators replaced by '-' ("pt_BR" becomes "pt-br").
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// localeChain returns the normalized locale followed by its successively
+// shorter prefixes: "de-ch" yields "de-ch", "de".
+func localeChain(locale string) []string {
+	locale = NormalizeLocale(locale)
+	var chain []string
+	for locale != "" {
+		chain = append(chain, locale)
+		idx := strings.LastIndexByte(locale, '-')
+		if idx < 0 {
+			break
+		}
+		locale = locale[:idx]
+	}
+	return chain
+}
+
+// parseTemplate splits a template into literal text and placeholders.
+func parseTemplate(template string) (messageTemplate, error) {
+	var parts messageTemplate
+	var text strings.Builder
+	for i := 0; i < len(template); i++ {
+		ch := template[i]
+		switch {
+		case ch == '{' && strings.HasPrefix(template[i:], "{{"):
+			text.WriteByte('{')
+			i++
+		case ch == '}' && strings.HasPrefix(template[i:], "}}"):
+			text.WriteByte('}')
+			i++
+		case ch == '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated placeholder at offset %d", i)
+			}
+			key := template[i+1 : i+end]
+			if key == "" || strings.ContainsAny(key, "{ ") {
+				return nil, fmt.Errorf("invalid placeholder %q", template[i:i+end+1])
+			}
+			if text.Len() > 0 {
+				parts = append(parts, templatePart{text: text.String()})
+				text.Reset()
+			}
+			parts = append(parts, templatePart{key: key})
+			i += end
+		case ch == '}':
+			return nil, fmt.Errorf("unmatched '}' at offset %d", i)
+		default:
+			text.WriteByte(ch)
+		}
+	}
+	if text.Len() > 0 {
+		parts = append(parts, templatePart{text: text.String()})
+	}
+	return parts, nil
+}
+
+// render fills the template's placeholders from the issue's details.
+// Returns false if the issue lacks any of them.
+func (t messageTemplate) render(issue Issue) (string, bool) {
+	var sb strings.Builder
+	for _, p := range t {
+		if p.key == "" {
+			sb.WriteString(p.text)
+			continue
+		}
+		value, ok := detailValue(issue, p.key)
+		if !ok {
+			return "", false
+		}
+		sb.WriteString(value)
+	}
+	return sb.String(), true
+}
+
+// detailValue returns the value of the issue's first detail with key.
+func detailValue(issue Issue, key string) (string, bool) {
+	for _, d := range issue.details {
+		if d.Key == key {
+			return d.Value, true
+		}
+	}
+	return "", false
+}
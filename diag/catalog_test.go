@@ -0,0 +1,205 @@
+package diag
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCatalog_Localize(t *testing.T) {
+	catalog := NewCatalog().
+		Add("de", E_MISSING_REQUIRED, "Pflichtfeld {property} fehlt").
+		Add("de-AT", E_CONSTRAINT_FAIL, "Eigenschaft {property}: {{ungültig}} ({detail})").
+		AddHint("de", E_MISSING_REQUIRED, "Feld {property} ergänzen")
+
+	missing := NewIssue(Error, E_MISSING_REQUIRED, "missing required property \"name\"").
+		WithDetail(DetailKeyPropertyName, "name").
+		WithHint("add the name property").
+		Build()
+	constraint := NewIssue(Error, E_CONSTRAINT_FAIL, "property age: constraint failed").
+		WithDetail(DetailKeyPropertyName, "age").
+		WithDetail(DetailKeyDetail, "out of range").
+		Build()
+
+	tests := []struct {
+		name     string
+		issue    Issue
+		locale   string
+		wantMsg  string
+		wantHint string
+	}{
+		{"exact locale", missing, "de", "Pflichtfeld name fehlt", "Feld name ergänzen"},
+		{"regional fallback", missing, "de_CH", "Pflichtfeld name fehlt", "Feld name ergänzen"},
+		{"no translation", missing, "fr", missing.Message(), missing.Hint()},
+		{"empty locale", missing, "", missing.Message(), missing.Hint()},
+		{"escaped braces", constraint, "de-at", "Eigenschaft age: {ungültig} (out of range)", ""},
+		{"regional only", constraint, "de", constraint.Message(), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := catalog.Localize(tt.issue, tt.locale)
+			if got.Message() != tt.wantMsg {
+				t.Errorf("Message() = %q, want %q", got.Message(), tt.wantMsg)
+			}
+			if got.Hint() != tt.wantHint {
+				t.Errorf("Hint() = %q, want %q", got.Hint(), tt.wantHint)
+			}
+			if got.Code() != tt.issue.Code() {
+				t.Errorf("Code() = %v, want %v", got.Code(), tt.issue.Code())
+			}
+		})
+	}
+}
+
+func TestCatalog_Localize_MissingDetail(t *testing.T) {
+	catalog := NewCatalog().Add("de", E_MISSING_REQUIRED, "Pflichtfeld {property} fehlt")
+	issue := NewIssue(Error, E_MISSING_REQUIRED, "missing required property").Build()
+
+	got := catalog.Localize(issue, "de")
+	if got.Message() != "missing required property" {
+		t.Errorf("Message() = %q, want original message", got.Message())
+	}
+}
+
+func TestCatalog_Localize_HintNotAdded(t *testing.T) {
+	catalog := NewCatalog().AddHint("de", E_SYNTAX, "Syntax prüfen")
+	issue := NewIssue(Error, E_SYNTAX, "syntax error").Build()
+
+	got := catalog.Localize(issue, "de")
+	if got.Hint() != "" {
+		t.Errorf("Hint() = %q, want empty", got.Hint())
+	}
+}
+
+func TestCatalog_Localize_IssueMessageWins(t *testing.T) {
+	catalog := NewCatalog().Add("de", E_INVARIANT_FAIL, "Invariante verletzt")
+	issue := NewIssue(Error, E_INVARIANT_FAIL, "must be an adult").
+		WithLocalizedMessage("de", "muss volljährig sein").
+		WithLocalizedMessage("pt_BR", "deve ser adulto").
+		Build()
+
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"de", "muss volljährig sein"},
+		{"de-CH", "muss volljährig sein"},
+		{"pt-BR", "deve ser adulto"},
+		{"pt", "must be an adult"},
+		{"en", "must be an adult"},
+	}
+	for _, tt := range tests {
+		if got := catalog.Localize(issue, tt.locale).Message(); got != tt.want {
+			t.Errorf("Localize(%q).Message() = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+
+	var nilCatalog *Catalog
+	if got := nilCatalog.Localize(issue, "de").Message(); got != "muss volljährig sein" {
+		t.Errorf("nil catalog Localize().Message() = %q, want issue's own translation", got)
+	}
+}
+
+func TestIssue_LocalizedMessage(t *testing.T) {
+	issue := NewIssue(Error, E_INVARIANT_FAIL, "must be an adult").
+		WithDetail(DetailKeyTypeName, "Person").
+		WithLocalizedMessage("de", "muss erwachsen sein").
+		WithLocalizedMessage("pt_BR", "deve ser adulto").
+		WithLocalizedMessage("DE", "muss volljährig sein").
+		Build()
+
+	if got := issue.MessageLocales(); !slices.Equal(got, []string{"de", "pt-br"}) {
+		t.Errorf("MessageLocales() = %q, want [de pt-br]", got)
+	}
+	if got, ok := issue.LocalizedMessage("pt-BR"); !ok || got != "deve ser adulto" {
+		t.Errorf("LocalizedMessage(pt-BR) = %q, %v", got, ok)
+	}
+	if got, ok := issue.LocalizedMessage("de"); !ok || got != "muss volljährig sein" {
+		t.Errorf("LocalizedMessage(de) = %q, %v; want the later message", got, ok)
+	}
+	if _, ok := issue.LocalizedMessage("de-CH"); ok {
+		t.Error("LocalizedMessage(de-CH) found a message; want no fallback")
+	}
+	if got := FromIssue(issue).Build().MessageLocales(); len(got) != 2 {
+		t.Errorf("FromIssue lost localized messages: %q", got)
+	}
+
+	if details := issue.Details(); len(details) != 1 {
+		t.Errorf("Details() = %v, want only the type name", details)
+	}
+	if data := NewRenderer().FormatIssueJSON(issue); strings.Contains(string(data), "volljährig") {
+		t.Errorf("JSON output contains a localized message: %s", data)
+	}
+	if sarif := NewRenderer().FormatResultSARIF(resultOf(issue)); strings.Contains(string(sarif), "volljährig") {
+		t.Errorf("SARIF output contains a localized message: %s", sarif)
+	}
+}
+
+func TestCatalog_Add_Panics(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		code     Code
+		template string
+	}{
+		{"empty locale", " ", E_SYNTAX, "x"},
+		{"zero code", "de", Code{}, "x"},
+		{"unterminated placeholder", "de", E_SYNTAX, "Feld {property"},
+		{"empty placeholder", "de", E_SYNTAX, "Feld {}"},
+		{"unmatched brace", "de", E_SYNTAX, "Feld }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			NewCatalog().Add(tt.locale, tt.code, tt.template)
+		})
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := map[string]string{
+		"de":     "de",
+		"pt_BR":  "pt-br",
+		" EN-us": "en-us",
+		"":       "",
+	}
+	for in, want := range tests {
+		if got := NormalizeLocale(in); got != want {
+			t.Errorf("NormalizeLocale(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderer_WithLocale(t *testing.T) {
+	catalog := NewCatalog().Add("de", E_MISSING_REQUIRED, "Pflichtfeld {property} fehlt")
+	issue := NewIssue(Error, E_MISSING_REQUIRED, "missing required property \"name\"").
+		WithDetail(DetailKeyPropertyName, "name").
+		Build()
+
+	r := NewRenderer(WithLocale("de-DE"), WithCatalog(catalog))
+
+	if out := r.FormatIssue(issue); !strings.Contains(out, "Pflichtfeld name fehlt") {
+		t.Errorf("FormatIssue() = %q, want localized message", out)
+	}
+
+	var wire struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(r.FormatIssueJSON(issue), &wire); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if wire.Message != "Pflichtfeld name fehlt" {
+		t.Errorf("JSON message = %q, want localized message", wire.Message)
+	}
+
+	// Without a locale the catalog is not consulted.
+	plain := NewRenderer(WithCatalog(catalog))
+	if out := plain.FormatIssue(issue); !strings.Contains(out, "missing required property") {
+		t.Errorf("FormatIssue() without locale = %q, want original message", out)
+	}
+}
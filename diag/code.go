@@ -28,6 +28,10 @@ const (
 
 	// CategoryAdapter is for format adapter parsing errors.
 	CategoryAdapter
+
+	// CategoryLint is for schema lint findings: legal schemas that are
+	// likely mistakes or poor style.
+	CategoryLint
 )
 
 // String returns a human-readable label for the category.
@@ -47,6 +51,8 @@ func (c CodeCategory) String() string {
 		return "graph"
 	case CategoryAdapter:
 		return "adapter"
+	case CategoryLint:
+		return "lint"
 	default:
 		return "unknown"
 	}
@@ -175,6 +181,28 @@ var (
 	// E_INVALID_PRIMARY_KEY_TYPE indicates a type not allowed as a primary key.
 	// Only String, UUID, Date, and Timestamp are permitted as primary key types.
 	E_INVALID_PRIMARY_KEY_TYPE = code("E_INVALID_PRIMARY_KEY_TYPE", CategorySchema)
+
+	// W_COMPATIBLE_CHANGE indicates a schema revision change that keeps all
+	// existing data valid and all existing names resolvable. It is reported
+	// as info and never fails a result.
+	W_COMPATIBLE_CHANGE = code("W_COMPATIBLE_CHANGE", CategorySchema)
+
+	// E_DATA_BREAKING_CHANGE indicates a schema revision change that may
+	// reject data that was valid under the previous revision.
+	E_DATA_BREAKING_CHANGE = code("E_DATA_BREAKING_CHANGE", CategorySchema)
+
+	// E_API_BREAKING_CHANGE indicates a schema revision change that removes
+	// or reshapes a name that consumers of the schema depend on.
+	E_API_BREAKING_CHANGE = code("E_API_BREAKING_CHANGE", CategorySchema)
+
+	// E_INVALID_MIGRATION indicates a migration spec references unknown types
+	// or properties, or contains a transform expression that does not compile.
+	E_INVALID_MIGRATION = code("E_INVALID_MIGRATION", CategorySchema)
+
+	// E_INVALID_IGNORE indicates a yammm:ignore comment that names no code,
+	// names an unknown code, or is not attached to a declaration. It is
+	// reported as a warning.
+	E_INVALID_IGNORE = code("E_INVALID_IGNORE", CategorySchema)
 )
 
 // Syntax codes.
@@ -272,6 +300,11 @@ var (
 	// This occurs when non-strict mode is enabled and the input contains multiple
 	// field names that differ only in case (e.g., "Name" and "name").
 	E_CASE_FOLD_COLLISION = code("E_CASE_FOLD_COLLISION", CategoryInstance)
+
+	// E_MIGRATION_FAIL indicates an instance could not be rewritten by a
+	// migration step (e.g., a transform failed to evaluate or a move target
+	// is already occupied).
+	E_MIGRATION_FAIL = code("E_MIGRATION_FAIL", CategoryInstance)
 )
 
 // Adapter codes.
@@ -304,6 +337,34 @@ var (
 	E_GRAPH_MISSING_PK = code("E_GRAPH_MISSING_PK", CategoryGraph)
 )
 
+// Lint codes.
+//
+// Lint codes use the W_ prefix: they never make a result fail on their own,
+// and are reported at Warning severity or below unless a lint configuration
+// raises them.
+var (
+	// W_UNUSED_IMPORT indicates an import whose alias is never referenced.
+	W_UNUSED_IMPORT = code("W_UNUSED_IMPORT", CategoryLint)
+
+	// W_UNUSED_DATATYPE indicates a datatype alias that no type or datatype references.
+	W_UNUSED_DATATYPE = code("W_UNUSED_DATATYPE", CategoryLint)
+
+	// W_ABSTRACT_WITHOUT_SUBTYPES indicates an abstract type that no type extends.
+	W_ABSTRACT_WITHOUT_SUBTYPES = code("W_ABSTRACT_WITHOUT_SUBTYPES", CategoryLint)
+
+	// W_MISSING_DOCUMENTATION indicates a type without a doc comment.
+	W_MISSING_DOCUMENTATION = code("W_MISSING_DOCUMENTATION", CategoryLint)
+
+	// W_RELATION_NAME_CASE indicates a relation name that is not UPPER_SNAKE_CASE.
+	W_RELATION_NAME_CASE = code("W_RELATION_NAME_CASE", CategoryLint)
+
+	// W_ENUM_VALUE_CASE indicates an enum mixing lower-case and capitalized values.
+	W_ENUM_VALUE_CASE = code("W_ENUM_VALUE_CASE", CategoryLint)
+
+	// W_UNANCHORED_PATTERN indicates a Pattern regex without ^ and $ anchors.
+	W_UNANCHORED_PATTERN = code("W_UNANCHORED_PATTERN", CategoryLint)
+)
+
 // allCodes contains all defined codes for AllCodes() and uniqueness verification.
 var allCodes = []Code{
 	// Sentinel
@@ -335,6 +396,11 @@ var allCodes = []Code{
 	E_INVALID_SYNTHETIC_ID,
 	E_LIST_ON_EDGE,
 	E_INVALID_PRIMARY_KEY_TYPE,
+	W_COMPATIBLE_CHANGE,
+	E_DATA_BREAKING_CHANGE,
+	E_API_BREAKING_CHANGE,
+	E_INVALID_MIGRATION,
+	E_INVALID_IGNORE,
 	// Syntax
 	E_SYNTAX,
 	// Import
@@ -366,6 +432,7 @@ var allCodes = []Code{
 	E_MISSING_TYPE_TAG,
 	E_INVALID_TYPE_TAG,
 	E_CASE_FOLD_COLLISION,
+	E_MIGRATION_FAIL,
 	// Adapter
 	E_ADAPTER_PARSE,
 	// Graph
@@ -376,6 +443,14 @@ var allCodes = []Code{
 	E_GRAPH_PARENT_NOT_FOUND,
 	E_GRAPH_INVALID_COMPOSITION,
 	E_GRAPH_MISSING_PK,
+	// Lint
+	W_UNUSED_IMPORT,
+	W_UNUSED_DATATYPE,
+	W_ABSTRACT_WITHOUT_SUBTYPES,
+	W_MISSING_DOCUMENTATION,
+	W_RELATION_NAME_CASE,
+	W_ENUM_VALUE_CASE,
+	W_UNANCHORED_PATTERN,
 }
 
 // AllCodes returns all defined codes.
@@ -388,6 +463,19 @@ func AllCodes() []Code {
 	return result
 }
 
+// LookupCode returns the defined code whose string form is name.
+//
+// It reports false if no code has that name. Use it to turn codes named in
+// configuration or source comments back into [Code] values.
+func LookupCode(name string) (Code, bool) {
+	for _, c := range allCodes {
+		if c.value == name {
+			return c, true
+		}
+	}
+	return Code{}, false
+}
+
 // CodesByCategory returns codes in the given category.
 //
 // The returned slice is a new allocation; modifications do not affect
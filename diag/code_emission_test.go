@@ -61,6 +61,7 @@ func TestCodeEmission_Categories(t *testing.T) {
 		diag.CategoryInstance,
 		diag.CategoryGraph,
 		diag.CategoryAdapter,
+		diag.CategoryLint,
 	}
 
 	for _, cat := range categories {
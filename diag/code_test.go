@@ -93,6 +93,7 @@ func TestCodeCategory_String(t *testing.T) {
 		{CategoryInstance, "instance"},
 		{CategoryGraph, "graph"},
 		{CategoryAdapter, "adapter"},
+		{CategoryLint, "lint"},
 		{CodeCategory(255), "unknown"},
 	}
 
@@ -125,6 +126,18 @@ func TestAllCodes(t *testing.T) {
 	}
 }
 
+func TestLookupCode(t *testing.T) {
+	for _, c := range AllCodes() {
+		got, ok := LookupCode(c.String())
+		if !ok || got != c {
+			t.Errorf("LookupCode(%q) = %v, %v; want %v, true", c.String(), got, ok, c)
+		}
+	}
+	if got, ok := LookupCode("E_NO_SUCH_CODE"); ok || !got.IsZero() {
+		t.Errorf("LookupCode(unknown) = %v, %v; want zero, false", got, ok)
+	}
+}
+
 func TestAllCodes_Uniqueness(t *testing.T) {
 	// Critical test: verify all code strings are unique
 	codes := AllCodes()
@@ -198,6 +211,11 @@ func TestCodesByCategory(t *testing.T) {
 			minExpected: 1,
 			mustContain: []Code{E_ADAPTER_PARSE},
 		},
+		{
+			cat:         CategoryLint,
+			minExpected: 7,
+			mustContain: []Code{W_UNUSED_IMPORT, W_UNANCHORED_PATTERN},
+		},
 	}
 
 	for _, tt := range tests {
@@ -258,6 +276,7 @@ func TestCodesByCategory_AllCategoriesCovered(t *testing.T) {
 		CategoryInstance,
 		CategoryGraph,
 		CategoryAdapter,
+		CategoryLint,
 	}
 
 	for _, cat := range categories {
@@ -325,17 +344,17 @@ func TestContractValidationCodesExist(t *testing.T) {
 }
 
 // TestAllCodes_MatchesDefinedCodes uses AST parsing to verify that every
-// exported E_* variable in code.go appears in allCodes exactly once.
+// exported E_* or W_* variable in code.go appears in allCodes exactly once.
 // This prevents drift between code definitions and the allCodes slice.
 func TestAllCodes_MatchesDefinedCodes(t *testing.T) {
-	// Parse code.go to find all exported E_* variable declarations
+	// Parse code.go to find all exported E_* or W_* variable declarations
 	fset := token.NewFileSet()
 	f, err := parser.ParseFile(fset, "code.go", nil, 0)
 	if err != nil {
 		t.Fatalf("failed to parse code.go: %v", err)
 	}
 
-	// Collect all E_* variable names from AST
+	// Collect all E_* or W_* variable names from AST
 	definedCodes := make(map[string]bool)
 	ast.Inspect(f, func(n ast.Node) bool {
 		genDecl, ok := n.(*ast.GenDecl)
@@ -349,7 +368,7 @@ func TestAllCodes_MatchesDefinedCodes(t *testing.T) {
 				continue
 			}
 			for _, name := range valueSpec.Names {
-				if strings.HasPrefix(name.Name, "E_") && name.IsExported() {
+				if (strings.HasPrefix(name.Name, "E_") || strings.HasPrefix(name.Name, "W_")) && name.IsExported() {
 					definedCodes[name.Name] = true
 				}
 			}
@@ -358,7 +377,7 @@ func TestAllCodes_MatchesDefinedCodes(t *testing.T) {
 	})
 
 	if len(definedCodes) == 0 {
-		t.Fatal("no E_* variables found in code.go")
+		t.Fatal("no E_* or W_* variables found in code.go")
 	}
 
 	// Build map from allCodes
@@ -374,17 +393,17 @@ func TestAllCodes_MatchesDefinedCodes(t *testing.T) {
 	// Check for codes in definitions but not in allCodes
 	for name := range definedCodes {
 		if !allCodesMap[name] {
-			t.Errorf("E_* variable %s defined in code.go but missing from allCodes", name)
+			t.Errorf("E_* or W_* variable %s defined in code.go but missing from allCodes", name)
 		}
 	}
 
 	// Check for codes in allCodes but not in definitions
 	for name := range allCodesMap {
 		if !definedCodes[name] {
-			t.Errorf("allCodes contains %s but no matching E_* variable in code.go", name)
+			t.Errorf("allCodes contains %s but no matching E_* or W_* variable in code.go", name)
 		}
 	}
 
 	// Log counts for visibility
-	t.Logf("found %d E_* definitions, %d entries in allCodes", len(definedCodes), len(allCodesMap))
+	t.Logf("found %d code definitions, %d entries in allCodes", len(definedCodes), len(allCodesMap))
 }
@@ -3,6 +3,7 @@ package diag
 import (
 	"fmt"
 	"slices"
+	"strings"
 	"sync"
 
 	"github.com/simon-lentz/yammm/location"
@@ -27,6 +28,10 @@ type Collector struct {
 	limitReached bool
 	droppedCount int
 
+	// Severity policy applied on collection, and how many issues it dropped
+	policy          *SeverityPolicy
+	suppressedCount int
+
 	// Precomputed severity counts for O(1) queries
 	fatalCount   int
 	errorCount   int
@@ -66,6 +71,17 @@ func NewCollectorUnlimited() *Collector {
 	return NewCollector(NoLimit)
 }
 
+// SetPolicy applies a severity policy to issues collected afterwards.
+//
+// Issues the policy suppresses are not stored; they are counted in
+// [Result.SuppressedCount]. Issues already collected are not affected.
+// A nil policy removes the current one.
+func (c *Collector) SetPolicy(p *SeverityPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.policy = p
+}
+
 // Collect adds an issue to the collector.
 //
 // This method is thread-safe. If the limit is reached, the issue is counted
@@ -114,10 +130,14 @@ func (c *Collector) CollectAll(issues []Issue) {
 //
 // This differs from [Collect] and [CollectAll], which actively validate
 // each issue because they accept Issue values directly.
+//
+// The suppressed count of res is added to the collector's own.
 func (c *Collector) Merge(res Result) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.cachedResult = nil
+	c.suppressedCount += res.suppressedCount
 	for issue := range res.Issues() {
 		c.collectLocked(issue)
 	}
@@ -139,6 +159,14 @@ func (c *Collector) collectLocked(issue Issue) {
 	// Invalidate cached result
 	c.cachedResult = nil
 
+	// Apply the severity policy before the limit so suppressed issues
+	// do not take up room
+	issue, keep := c.policy.Apply(issue)
+	if !keep {
+		c.suppressedCount++
+		return
+	}
+
 	// Check limit
 	if c.limit > 0 && len(c.issues) >= c.limit {
 		c.limitReached = true
@@ -185,6 +213,7 @@ func (c *Collector) Result() Result {
 	slices.SortFunc(sorted, compareIssues)
 
 	result := newResult(sorted, c.limit, c.limitReached, c.droppedCount)
+	result.suppressedCount = c.suppressedCount
 	c.cachedResult = &result
 	return result
 }
@@ -197,7 +226,7 @@ func (c *Collector) Result() Result {
 //  3. Path-only: SourceName, Path
 //  4. Common tie-breakers: Code, Severity, Message, Hint
 //  5. Provenance tie-breakers: SourceName, Path (for hybrid issue total order)
-//  6. Final tie-breakers: Details, Related (for true total order)
+//  6. Final tie-breakers: Details, Related, Fixes (for true total order)
 //
 // This function implements a total order: distinct issues never compare equal.
 // This guarantees deterministic output from Collector.Result() regardless of
@@ -288,7 +317,22 @@ func compareIssues(a, b Issue) int {
 	}
 
 	// Compare related info lexicographically
-	return compareRelated(a.related, b.related)
+	if cmp := compareRelated(a.related, b.related); cmp != 0 {
+		return cmp
+	}
+
+	// Compare fixes lexicographically
+	if cmp := compareFixes(a.fixes, b.fixes); cmp != 0 {
+		return cmp
+	}
+
+	// Compare localized messages lexicographically
+	return slices.CompareFunc(a.localized, b.localized, func(x, y localizedMessage) int {
+		if c := strings.Compare(x.locale, y.locale); c != 0 {
+			return c
+		}
+		return strings.Compare(x.message, y.message)
+	})
 }
 
 // compareDetails compares two Detail slices lexicographically.
@@ -388,3 +432,10 @@ func (c *Collector) DroppedCount() int {
 	defer c.mu.RUnlock()
 	return c.droppedCount
 }
+
+// SuppressedCount returns how many issues the severity policy suppressed.
+func (c *Collector) SuppressedCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.suppressedCount
+}
@@ -562,6 +562,21 @@ func TestCompareIssues_TotalOrder_IdenticalIssuesEqual(t *testing.T) {
 	}
 }
 
+func TestCompareIssues_DifferentFixes(t *testing.T) {
+	source := location.MustNewSourceID("test://a.yammm")
+	span := location.Point(source, 1, 1)
+
+	a := NewIssue(Error, E_SYNTAX, "msg").WithSpan(span).WithFix("Insert a", TextEdit{Span: span, NewText: "a"}).Build()
+	b := NewIssue(Error, E_SYNTAX, "msg").WithSpan(span).WithFix("Insert b", TextEdit{Span: span, NewText: "b"}).Build()
+
+	if cmp := compareIssues(a, b); cmp >= 0 {
+		t.Errorf("compareIssues(a, b) = %d; want < 0", cmp)
+	}
+	if cmp := compareIssues(b, a); cmp <= 0 {
+		t.Errorf("compareIssues(b, a) = %d; want > 0", cmp)
+	}
+}
+
 func TestCompareIssues_HybridIssues_DifferentPaths(t *testing.T) {
 	// This test verifies the fix for the total order bug where hybrid issues
 	// with identical spans but different paths would incorrectly compare equal.
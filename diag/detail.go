@@ -107,6 +107,10 @@ const (
 
 	// DetailKeyImportCount is the count of imports (for limit diagnostics).
 	DetailKeyImportCount = "import_count"
+
+	// DetailKeySuggestion is the in-scope name an unknown name was most
+	// likely meant to be (see IssueBuilder.WithSuggestion).
+	DetailKeySuggestion = "suggestion"
 )
 
 // ExpectedGot creates a pair of details for type mismatch diagnostics.
@@ -54,6 +54,18 @@
 // Direct struct literal construction bypasses validity checks and will cause
 // panics when the issue is collected.
 //
+// Issues about unknown names record the closest name in scope with
+// [IssueBuilder.WithSuggestion], which sets a "did you mean" hint and the
+// [DetailKeySuggestion] detail.
+//
+// Issues may carry machine-applicable fixes, each a title plus [TextEdit]
+// values keyed by span:
+//
+//	issue := diag.NewIssue(diag.Error, diag.E_UNKNOWN_TYPE, `unknown type "Persn"`).
+//	    WithSpan(span).
+//	    WithFix(`Replace with "Person"`, diag.TextEdit{Span: span, NewText: "Person"}).
+//	    Build()
+//
 // # Collection and Results
 //
 // Use [Collector] to aggregate issues during validation:
@@ -69,13 +81,22 @@
 // [Collector] is thread-safe and provides O(1) severity queries via [Collector.OK],
 // [Collector.HasErrors], and [Collector.HasFatal].
 //
+// A [SeverityPolicy] set with [Collector.SetPolicy] changes the severity of
+// issues by code, or suppresses them, as they are collected. Suppressed
+// issues are counted by [Result.SuppressedCount]:
+//
+//	collector.SetPolicy(diag.NewSeverityPolicy().Suppress(diag.W_UNUSED_IMPORT))
+//
 // # Rendering
 //
 // The [Renderer] provides formatting for multiple output formats:
 //
 //   - Text output with optional source excerpts and ANSI colors
 //   - JSON output with stable wire format
-//   - LSP-compatible diagnostics with UTF-16 character offsets
+//   - LSP-compatible diagnostics with UTF-16 character offsets, and quick
+//     fix code actions for issues that carry fixes
+//   - SARIF 2.1.0 logs for code-scanning tools
+//   - JUnit XML reports for CI test reporters
 //
 // Example:
 //
@@ -85,6 +106,15 @@
 //	)
 //	output := renderer.FormatResult(result)
 //
+// With [WithLocale], a renderer shows messages in that locale where a
+// translation exists: the issue's own, added with
+// [IssueBuilder.WithLocalizedMessage], or else a template from the [Catalog]
+// set with [WithCatalog]:
+//
+//	catalog := diag.NewCatalog().
+//	    Add("de", diag.E_MISSING_REQUIRED, "Pflichtfeld {property} fehlt")
+//	renderer := diag.NewRenderer(diag.WithLocale("de"), diag.WithCatalog(catalog))
+//
 // # Package Dependencies
 //
 // Per the Foundation Rule, diag imports only stdlib and [location]. It must not
@@ -0,0 +1,92 @@
+package diag
+
+import (
+	"slices"
+
+	"github.com/simon-lentz/yammm/location"
+)
+
+// TextEdit replaces the source text covered by a span.
+//
+// The span is half-open like every [location.Span]: the text from Start up
+// to (not including) End is replaced by NewText. A span whose start equals
+// its end inserts NewText; an empty NewText deletes the spanned text.
+type TextEdit struct {
+	Span    location.Span
+	NewText string
+}
+
+// Fix is a machine-applicable suggestion for resolving an issue.
+//
+// Title is a short imperative description suitable for a menu entry (e.g.,
+// `Replace with "Person"`). Edits are applied together; they must not
+// overlap and may span several sources.
+type Fix struct {
+	Title string
+	Edits []TextEdit
+}
+
+// clone returns a copy of the fix that shares no memory with f.
+func (f Fix) clone() Fix {
+	f.Edits = slices.Clone(f.Edits)
+	return f
+}
+
+// cloneFixes deep-copies a fix slice, returning nil for an empty one.
+func cloneFixes(fixes []Fix) []Fix {
+	if len(fixes) == 0 {
+		return nil
+	}
+	cp := make([]Fix, len(fixes))
+	for i, f := range fixes {
+		cp[i] = f.clone()
+	}
+	return cp
+}
+
+// compareFixes compares two Fix slices lexicographically by title, then by
+// edits.
+func compareFixes(a, b []Fix) int {
+	minLen := min(len(a), len(b))
+	for i := range minLen {
+		if a[i].Title != b[i].Title {
+			if a[i].Title < b[i].Title {
+				return -1
+			}
+			return 1
+		}
+		if cmp := compareEdits(a[i].Edits, b[i].Edits); cmp != 0 {
+			return cmp
+		}
+	}
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// compareEdits compares two TextEdit slices lexicographically.
+func compareEdits(a, b []TextEdit) int {
+	minLen := min(len(a), len(b))
+	for i := range minLen {
+		if cmp := location.Compare(a[i].Span, b[i].Span); cmp != 0 {
+			return cmp
+		}
+		if a[i].NewText != b[i].NewText {
+			if a[i].NewText < b[i].NewText {
+				return -1
+			}
+			return 1
+		}
+	}
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
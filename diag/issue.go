@@ -1,6 +1,10 @@
 package diag
 
-import "github.com/simon-lentz/yammm/location"
+import (
+	"slices"
+
+	"github.com/simon-lentz/yammm/location"
+)
 
 // Issue represents a single diagnostic issue.
 //
@@ -24,6 +28,14 @@ type Issue struct {
 	hint       string                 // optional resolution suggestion
 	related    []location.RelatedInfo // additional locations (e.g., "previous definition here")
 	details    []Detail               // additional key-value context
+	fixes      []Fix                  // machine-applicable suggestions
+	localized  []localizedMessage     // the message in other locales
+}
+
+// localizedMessage is an issue's message in another locale.
+type localizedMessage struct {
+	locale  string // normalized (see NormalizeLocale)
+	message string
 }
 
 // Severity returns the issue's severity level.
@@ -167,13 +179,54 @@ func (i Issue) Details() []Detail {
 	return cp
 }
 
+// Fixes returns a copy of the machine-applicable fix suggestions.
+//
+// Returns nil if no fixes are present. The returned slice and the edits of
+// each fix are defensive copies; modifications do not affect the original
+// issue.
+func (i Issue) Fixes() []Fix {
+	return cloneFixes(i.fixes)
+}
+
+// LocalizedMessage returns the issue's own message in locale, as recorded
+// with [IssueBuilder.WithLocalizedMessage]. The locale is normalized but not
+// shortened; [Catalog.Localize] falls back from "de-ch" to "de".
+func (i Issue) LocalizedMessage(locale string) (string, bool) {
+	locale = NormalizeLocale(locale)
+	for _, m := range i.localized {
+		if m.locale == locale {
+			return m.message, true
+		}
+	}
+	return "", false
+}
+
+// MessageLocales returns the normalized locales the issue has its own
+// message in, in the order they were recorded, or nil if there are none.
+//
+// Localized messages are not details: they are not returned by [Details]
+// and are not part of JSON or SARIF output, which carry the message a
+// renderer was configured for.
+func (i Issue) MessageLocales() []string {
+	if len(i.localized) == 0 {
+		return nil
+	}
+	locales := make([]string, len(i.localized))
+	for j, m := range i.localized {
+		locales[j] = m.locale
+	}
+	return locales
+}
+
 // Clone returns a deep copy of the issue.
 //
-// INVARIANT: All slice element types (RelatedInfo, Detail) must not contain
-// mutable reference fields (maps, slices, pointers, funcs, chans). Strings
-// are permitted (immutable). If mutable reference fields are ever added to
-// these types, this method must be updated to deep-copy their targets to
-// preserve immutability guarantees.
+// INVARIANT: All slice element types (RelatedInfo, Detail, TextEdit,
+// localizedMessage) must not
+// contain mutable reference fields (maps, slices, pointers, funcs, chans).
+// Strings are permitted (immutable). Fix holds a TextEdit slice and is copied
+// by [cloneFixes]. If mutable reference fields are ever added to these types,
+// this method must be updated to deep-copy their targets to preserve
+// immutability guarantees.
 func (i Issue) Clone() Issue {
 	clone := i
 	if len(i.related) > 0 {
@@ -184,5 +237,7 @@ func (i Issue) Clone() Issue {
 		clone.details = make([]Detail, len(i.details))
 		copy(clone.details, i.details)
 	}
+	clone.fixes = cloneFixes(i.fixes)
+	clone.localized = slices.Clone(i.localized)
 	return clone
 }
@@ -22,6 +22,7 @@ type issueWire struct {
 	Hint       string            `json:"hint,omitzero"`
 	Related    []relatedInfoWire `json:"related,omitzero"`
 	Details    []detailWire      `json:"details,omitzero"`
+	Fixes      []fixWire         `json:"fixes,omitzero"`
 }
 
 // spanWire is the JSON wire format for location.Span.
@@ -55,12 +56,25 @@ type detailWire struct {
 	Value string `json:"value"`
 }
 
+// fixWire is the JSON wire format for Fix.
+type fixWire struct {
+	Title string         `json:"title"`
+	Edits []textEditWire `json:"edits"`
+}
+
+// textEditWire is the JSON wire format for TextEdit.
+type textEditWire struct {
+	Span    *spanWire `json:"span"`
+	NewText string    `json:"newText"`
+}
+
 // resultWire is the JSON wire format for Result.
 type resultWire struct {
-	Issues       []issueWire `json:"issues"`
-	Limit        int         `json:"limit,omitzero"`
-	LimitReached bool        `json:"limitReached,omitzero"`
-	DroppedCount int         `json:"droppedCount,omitzero"`
+	Issues          []issueWire `json:"issues"`
+	Limit           int         `json:"limit,omitzero"`
+	LimitReached    bool        `json:"limitReached,omitzero"`
+	DroppedCount    int         `json:"droppedCount,omitzero"`
+	SuppressedCount int         `json:"suppressedCount,omitzero"`
 }
 
 // FormatIssueJSON returns the JSON representation of a single issue.
@@ -68,7 +82,7 @@ type resultWire struct {
 // The output format is stable Optional fields with
 // zero values are omitted.
 func (r *Renderer) FormatIssueJSON(issue Issue) json.RawMessage {
-	wire := toIssueWire(issue)
+	wire := toIssueWire(r.localize(issue))
 	//nolint:errchkjson // Wire types are safe; error check is defensive
 	data, err := json.Marshal(wire)
 	if err != nil {
@@ -83,7 +97,7 @@ func (r *Renderer) FormatIssueJSON(issue Issue) json.RawMessage {
 // The output format is stable The returned JSON contains
 // an array of issues and optional limit tracking fields.
 func (r *Renderer) FormatResultJSON(res Result) json.RawMessage {
-	wire := toResultWire(res)
+	wire := r.toResultWire(res)
 	//nolint:errchkjson // Wire types are safe; error check is defensive
 	data, err := json.Marshal(wire)
 	if err != nil {
@@ -94,10 +108,10 @@ func (r *Renderer) FormatResultJSON(res Result) json.RawMessage {
 }
 
 // toResultWire converts a Result to its JSON wire format.
-func toResultWire(res Result) resultWire {
+func (r *Renderer) toResultWire(res Result) resultWire {
 	var issues []issueWire
 	for issue := range res.Issues() {
-		issues = append(issues, toIssueWire(issue))
+		issues = append(issues, toIssueWire(r.localize(issue)))
 	}
 
 	// Ensure empty slice becomes empty array, not null
@@ -106,7 +120,8 @@ func toResultWire(res Result) resultWire {
 	}
 
 	wire := resultWire{
-		Issues: issues,
+		Issues:          issues,
+		SuppressedCount: res.SuppressedCount(),
 	}
 
 	// Only include limit-related fields when the limit was reached
@@ -165,6 +180,27 @@ func toIssueWire(issue Issue) issueWire {
 		}
 	}
 
+	// Optional fixes
+	fixes := issue.Fixes()
+	if len(fixes) > 0 {
+		wire.Fixes = make([]fixWire, len(fixes))
+		for i, f := range fixes {
+			wire.Fixes[i] = toFixWire(f)
+		}
+	}
+
+	return wire
+}
+
+// toFixWire converts a Fix to its JSON wire format.
+func toFixWire(fix Fix) fixWire {
+	wire := fixWire{
+		Title: fix.Title,
+		Edits: make([]textEditWire, len(fix.Edits)),
+	}
+	for i, e := range fix.Edits {
+		wire.Edits[i] = textEditWire{Span: toSpanWire(e.Span), NewText: e.NewText}
+	}
 	return wire
 }
 
@@ -412,6 +412,28 @@ func TestFormatIssueJSON_WithDetails(t *testing.T) {
 	}
 }
 
+func TestFormatIssueJSON_WithFixes(t *testing.T) {
+	source := location.MustNewSourceID("test://schema.yammm")
+	issue := NewIssue(Error, E_UNKNOWN_TYPE, "unknown type").
+		WithFix("Replace with Person", TextEdit{
+			Span:    location.Span{Source: source, Start: location.Position{Line: 2, Column: 8, Byte: 20}, End: location.Position{Line: 2, Column: 13, Byte: 25}},
+			NewText: "Person",
+		}).
+		Build()
+
+	data := NewRenderer().FormatIssueJSON(issue)
+	want := `"fixes":[{"title":"Replace with Person","edits":[{"span":{"source":"test://schema.yammm",` +
+		`"start":{"line":2,"column":8,"byte":20},"end":{"line":2,"column":13,"byte":25}},"newText":"Person"}]}]`
+	if !strings.Contains(string(data), want) {
+		t.Errorf("FormatIssueJSON = %s\nwant fixes %s", data, want)
+	}
+
+	// Issues without fixes omit the field.
+	if data := NewRenderer().FormatIssueJSON(NewIssue(Error, E_SYNTAX, "e").Build()); strings.Contains(string(data), "fixes") {
+		t.Errorf("fixes should be omitted: %s", data)
+	}
+}
+
 func TestFormatIssueJSON_WithPath(t *testing.T) {
 	// WithPath(sourceName, path) - sourceName is the file, path is the JSON path
 	issue := NewIssue(Error, E_SYNTAX, "error").
@@ -527,6 +549,20 @@ func TestFormatResultJSON_WithLimit(t *testing.T) {
 	}
 }
 
+func TestFormatResultJSON_SuppressedCount(t *testing.T) {
+	c := NewCollector(0)
+	c.SetPolicy(NewSeverityPolicy().Suppress(W_UNUSED_IMPORT))
+	c.Collect(NewIssue(Warning, W_UNUSED_IMPORT, "suppressed").Build())
+
+	var parsed map[string]any
+	if err := json.Unmarshal(NewRenderer().FormatResultJSON(c.Result()), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if parsed["suppressedCount"] != float64(1) {
+		t.Errorf("suppressedCount = %v; want 1", parsed["suppressedCount"])
+	}
+}
+
 func TestFormatIssueJSON_CompleteIssue(t *testing.T) {
 	source := location.MustNewSourceID("test://complete.yammm")
 	issue := NewIssue(Error, E_TYPE_COLLISION, "complete test").
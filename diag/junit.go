@@ -0,0 +1,112 @@
+package diag
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// Wire format types for JUnit XML serialization, following the de facto
+// schema accepted by CI test reporters.
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// FormatResultJUnit returns a JUnit XML report of the issues of res.
+//
+// Issues are grouped into one test suite per source, in the order the
+// sources first appear. Each issue is a test case named by its code and
+// location. Fatal issues are reported as errors and Error issues as
+// failures; other severities are passing test cases with the rendered issue
+// as standard output. The failure text is the text rendering of the issue
+// ([Renderer.FormatIssue]), so it includes excerpts when enabled.
+func (r *Renderer) FormatResultJUnit(res Result) []byte {
+	report := junitTestSuites{Name: "yammm"}
+	suiteIndex := make(map[string]int)
+
+	for issue := range res.Issues() {
+		name := r.junitSuiteName(issue)
+		idx, ok := suiteIndex[name]
+		if !ok {
+			idx = len(report.Suites)
+			suiteIndex[name] = idx
+			report.Suites = append(report.Suites, junitTestSuite{Name: name})
+		}
+		suite := &report.Suites[idx]
+
+		var loc strings.Builder
+		r.writeLocation(&loc, issue)
+		tc := junitTestCase{
+			Name:      issue.Code().String() + " " + loc.String(),
+			ClassName: name,
+		}
+		text := r.FormatIssue(issue)
+		problem := &junitProblem{Message: r.localize(issue).Message(), Type: issue.Code().String(), Body: text}
+		switch issue.Severity() {
+		case Fatal:
+			tc.Error = problem
+			suite.Errors++
+		case Error:
+			tc.Failure = problem
+			suite.Failures++
+		default:
+			tc.SystemOut = text
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		// This should never happen with our wire types
+		panic("diag: unexpected XML marshal error: " + err.Error())
+	}
+	return append([]byte(xml.Header), append(data, '\n')...)
+}
+
+// junitSuiteName returns the name of the test suite an issue belongs to:
+// its source (relative to the module root when set), its instance
+// provenance label, or "yammm" for issues without either.
+func (r *Renderer) junitSuiteName(issue Issue) string {
+	switch {
+	case issue.HasSpan():
+		return r.displaySource(issue.Span().Source)
+	case issue.SourceName() != "":
+		return issue.SourceName()
+	default:
+		return "yammm"
+	}
+}
@@ -0,0 +1,99 @@
+package diag
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/simon-lentz/yammm/location"
+)
+
+func TestFormatResultJUnit_Empty(t *testing.T) {
+	got := string(NewRenderer().FormatResultJUnit(OK()))
+	want := xml.Header + `<testsuites name="yammm" tests="0" failures="0" errors="0"></testsuites>` + "\n"
+	if got != want {
+		t.Errorf("FormatResultJUnit(OK()) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatResultJUnit(t *testing.T) {
+	schemaSource := location.MustNewSourceID("test://schema.yammm")
+	c := NewCollectorUnlimited()
+	c.Collect(NewIssue(Error, E_TYPE_COLLISION, `type "A" already defined`).WithSpan(location.Point(schemaSource, 3, 6)).Build())
+	c.Collect(NewIssue(Warning, E_SYNTAX, "odd <spacing>").WithSpan(location.Point(schemaSource, 7, 1)).Build())
+	c.Collect(NewIssue(Fatal, E_INTERNAL, "boom").Build())
+	c.Collect(NewIssue(Error, E_TYPE_MISMATCH, "expected integer").WithPath("people.json", "$.Person[0].age").Build())
+
+	data := NewRenderer().FormatResultJUnit(c.Result())
+	if !strings.HasPrefix(string(data), xml.Header) {
+		t.Errorf("missing XML header:\n%s", data)
+	}
+
+	var report junitTestSuites
+	if err := xml.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, data)
+	}
+	if report.Tests != 4 || report.Failures != 2 || report.Errors != 1 {
+		t.Errorf("totals = %d/%d/%d; want 4 tests, 2 failures, 1 error", report.Tests, report.Failures, report.Errors)
+	}
+
+	suites := make(map[string]junitTestSuite)
+	for _, s := range report.Suites {
+		suites[s.Name] = s
+	}
+	if len(suites) != 3 {
+		t.Fatalf("suites = %v; want one per source", report.Suites)
+	}
+
+	schema := suites["test://schema.yammm"]
+	if schema.Tests != 2 || schema.Failures != 1 || schema.Errors != 0 {
+		t.Errorf("schema suite counts = %d/%d/%d; want 2/1/0", schema.Tests, schema.Failures, schema.Errors)
+	}
+	failing := schema.Cases[0]
+	if failing.Name != "E_TYPE_COLLISION test://schema.yammm:3:6" {
+		t.Errorf("test case name = %q", failing.Name)
+	}
+	if failing.ClassName != "test://schema.yammm" {
+		t.Errorf("classname = %q", failing.ClassName)
+	}
+	if failing.Failure == nil || failing.Failure.Type != "E_TYPE_COLLISION" || failing.Failure.Message != `type "A" already defined` {
+		t.Fatalf("failure = %+v", failing.Failure)
+	}
+	if !strings.Contains(failing.Failure.Body, "error[E_TYPE_COLLISION]") {
+		t.Errorf("failure body should be the text rendering, got %q", failing.Failure.Body)
+	}
+	warning := schema.Cases[1]
+	if warning.Failure != nil || warning.Error != nil {
+		t.Errorf("warnings should not fail: %+v", warning)
+	}
+	if !strings.Contains(warning.SystemOut, "odd <spacing>") {
+		t.Errorf("system-out = %q; want rendered warning", warning.SystemOut)
+	}
+
+	instance := suites["people.json"]
+	if len(instance.Cases) != 1 || instance.Cases[0].Name != "E_TYPE_MISMATCH people.json $.Person[0].age" {
+		t.Errorf("instance suite = %+v", instance)
+	}
+
+	internal := suites["yammm"]
+	if len(internal.Cases) != 1 || internal.Cases[0].Error == nil || internal.Errors != 1 {
+		t.Errorf("fatal issue should be an error test case: %+v", internal)
+	}
+}
+
+func TestFormatResultJUnit_ModuleRoot(t *testing.T) {
+	source := location.MustNewSourceID("file:///home/user/project/src/file.yammm")
+	issue := NewIssue(Error, E_SYNTAX, "error").WithSpan(location.Point(source, 5, 10)).Build()
+
+	var report junitTestSuites
+	data := NewRenderer(WithModuleRoot("file:///home/user/project")).FormatResultJUnit(resultOf(issue))
+	if err := xml.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if got := report.Suites[0].Name; got != "src/file.yammm" {
+		t.Errorf("suite name = %q; want src/file.yammm", got)
+	}
+	if got := report.Suites[0].Cases[0].Name; got != "E_SYNTAX src/file.yammm:5:10" {
+		t.Errorf("test case name = %q", got)
+	}
+}
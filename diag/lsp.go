@@ -2,6 +2,7 @@ package diag
 
 import (
 	"net/url"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/simon-lentz/yammm/location"
@@ -15,6 +16,9 @@ const (
 	LSPSeverityHint        = 4
 )
 
+// LSPCodeActionKindQuickFix is the LSP code action kind of fixes.
+const LSPCodeActionKindQuickFix = "quickfix"
+
 // LSPDiagnostic is the LSP Diagnostic structure.
 //
 // See: https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#diagnostic
@@ -54,6 +58,29 @@ type LSPLocation struct {
 	Range LSPRange `json:"range"`
 }
 
+// LSPTextEdit is the LSP TextEdit structure.
+type LSPTextEdit struct {
+	Range   LSPRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+// LSPWorkspaceEdit is the LSP WorkspaceEdit structure, using the changes
+// form: text edits keyed by document URI.
+type LSPWorkspaceEdit struct {
+	Changes map[string][]LSPTextEdit `json:"changes"`
+}
+
+// LSPCodeAction is the LSP CodeAction structure for a quick fix.
+//
+// See: https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#codeAction
+type LSPCodeAction struct {
+	Title       string           `json:"title"`
+	Kind        string           `json:"kind"`
+	Diagnostics []LSPDiagnostic  `json:"diagnostics,omitzero"`
+	IsPreferred bool             `json:"isPreferred,omitzero"`
+	Edit        LSPWorkspaceEdit `json:"edit"`
+}
+
 // LSPDiagnostic converts an Issue to an LSP Diagnostic.
 //
 // Returns nil if the issue doesn't have a valid span or if byte offset
@@ -96,7 +123,7 @@ func (r *Renderer) LSPDiagnostic(issue Issue) *LSPDiagnostic {
 		Severity: SeverityToLSP(issue.Severity()),
 		Code:     issue.Code().String(),
 		Source:   "yammm",
-		Message:  issue.Message(),
+		Message:  r.localize(issue).Message(),
 	}
 
 	// Add related information
@@ -135,24 +162,98 @@ func (r *Renderer) LSPDiagnostics(res Result) []LSPDiagnostic {
 	return diagnostics
 }
 
+// LSPCodeActions converts the fixes of an issue to LSP quick fix code
+// actions, one per fix, in fix order. The first action is marked preferred.
+//
+// Each action lists the issue's diagnostic when the issue converts to one
+// (see [Renderer.LSPDiagnostic]). A fix is skipped if any of its edits
+// cannot be converted, since applying part of a fix could corrupt the
+// document. Returns nil if the issue has no convertible fixes.
+func (r *Renderer) LSPCodeActions(issue Issue) []LSPCodeAction {
+	if len(issue.fixes) == 0 {
+		return nil
+	}
+
+	var diagnostics []LSPDiagnostic
+	if d := r.LSPDiagnostic(issue); d != nil {
+		diagnostics = []LSPDiagnostic{*d}
+	}
+
+	var actions []LSPCodeAction
+	for _, fix := range issue.fixes {
+		edit, ok := r.toLSPWorkspaceEdit(fix.Edits)
+		if !ok {
+			continue
+		}
+		actions = append(actions, LSPCodeAction{
+			Title:       fix.Title,
+			Kind:        LSPCodeActionKindQuickFix,
+			Diagnostics: diagnostics,
+			IsPreferred: len(actions) == 0,
+			Edit:        edit,
+		})
+	}
+	return actions
+}
+
+// toLSPWorkspaceEdit converts text edits to an LSP workspace edit.
+//
+// Returns false if any edit cannot be converted.
+func (r *Renderer) toLSPWorkspaceEdit(edits []TextEdit) (LSPWorkspaceEdit, bool) {
+	changes := make(map[string][]LSPTextEdit)
+	for _, e := range edits {
+		span := e.Span
+		if span.IsZero() || !span.Start.IsKnown() {
+			return LSPWorkspaceEdit{}, false
+		}
+		start, ok := r.toLSPPosition(span, span.Start)
+		if !ok {
+			return LSPWorkspaceEdit{}, false
+		}
+		end := start
+		if span.End.IsKnown() {
+			if end, ok = r.toLSPPosition(span, span.End); !ok {
+				// Unlike diagnostics, an edit range must be exact.
+				return LSPWorkspaceEdit{}, false
+			}
+		}
+		uri := sourceIDToURI(span.Source)
+		changes[uri] = append(changes[uri], LSPTextEdit{
+			Range:   LSPRange{Start: start, End: end},
+			NewText: e.NewText,
+		})
+	}
+	return LSPWorkspaceEdit{Changes: changes}, true
+}
+
 // sourceIDToURI converts a SourceID to an LSP-compatible URI.
 //
 // File-backed sources become file:// URIs; synthetic sources (test://, inline:, etc.)
 // pass through as-is since they already have URI-like schemes.
 func sourceIDToURI(source location.SourceID) string {
 	if cp, ok := source.CanonicalPath(); ok {
-		// File-backed: convert to file:// URI with proper percent-encoding.
-		// Use url.URL to correctly encode special characters like spaces.
-		u := url.URL{
-			Scheme: "file",
-			Path:   cp.String(),
-		}
-		return u.String()
+		return fileURI(cp.String())
 	}
 	// Synthetic: return as-is (already URI-like)
 	return source.String()
 }
 
+// fileURI converts a forward-slash absolute path to a file:// URI with
+// proper percent-encoding. Windows drive paths ("C:/dir") get a leading
+// slash, so the drive is part of the path rather than the host
+// ("file:///C:/dir").
+func fileURI(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	// Use url.URL to correctly encode special characters like spaces.
+	u := url.URL{
+		Scheme: "file",
+		Path:   path,
+	}
+	return u.String()
+}
+
 // SeverityToLSP converts our Severity to LSP severity value.
 func SeverityToLSP(sev Severity) int {
 	switch sev {
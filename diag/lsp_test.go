@@ -266,6 +266,72 @@ func TestLSPDiagnostics_Empty(t *testing.T) {
 	}
 }
 
+func TestLSPCodeActions(t *testing.T) {
+	provider := newMockLineIndexProvider()
+	schemaSource := location.MustNewSourceID("test://main.yammm")
+	otherSource := location.MustNewSourceID("test://other.yammm")
+	provider.AddWithIndex(schemaSource, "schema \"s\"\ntype Ä { b Persn }\n")
+	provider.AddWithIndex(otherSource, "schema \"other\"\n")
+
+	typo := location.Span{
+		Source: schemaSource,
+		Start:  location.Position{Line: 2, Column: 12, Byte: 23},
+		End:    location.Position{Line: 2, Column: 17, Byte: 28},
+	}
+	insert := location.Span{
+		Source: otherSource,
+		Start:  location.Position{Line: 2, Column: 1, Byte: 15},
+		End:    location.Position{Line: 2, Column: 1, Byte: 15},
+	}
+	issue := NewIssue(Error, E_UNKNOWN_TYPE, `unknown type "Persn"`).
+		WithSpan(typo).
+		WithFix(`Replace with "Person"`, TextEdit{Span: typo, NewText: "Person"}).
+		WithFix(`Declare type "Persn"`, TextEdit{Span: insert, NewText: "type Persn {}\n"}).
+		Build()
+
+	actions := NewRenderer(WithSourceProvider(provider)).LSPCodeActions(issue)
+	if len(actions) != 2 {
+		t.Fatalf("len(actions) = %d; want 2", len(actions))
+	}
+
+	replace := actions[0]
+	if replace.Title != `Replace with "Person"` || replace.Kind != LSPCodeActionKindQuickFix || !replace.IsPreferred {
+		t.Errorf("actions[0] = %+v", replace)
+	}
+	if len(replace.Diagnostics) != 1 || replace.Diagnostics[0].Code != "E_UNKNOWN_TYPE" {
+		t.Errorf("actions[0].Diagnostics = %+v", replace.Diagnostics)
+	}
+	edits := replace.Edit.Changes["test://main.yammm"]
+	want := LSPTextEdit{Range: LSPRange{Start: LSPPosition{Line: 1, Character: 11}, End: LSPPosition{Line: 1, Character: 16}}, NewText: "Person"}
+	if len(edits) != 1 || edits[0] != want {
+		t.Errorf("actions[0] edits = %+v; want %+v", edits, want)
+	}
+
+	declare := actions[1]
+	if declare.IsPreferred {
+		t.Error("only the first action should be preferred")
+	}
+	edits = declare.Edit.Changes["test://other.yammm"]
+	if len(edits) != 1 || edits[0].Range.Start != (LSPPosition{Line: 1}) || edits[0].Range.End != edits[0].Range.Start {
+		t.Errorf("actions[1] edits = %+v; want insertion at 1:0", edits)
+	}
+}
+
+func TestLSPCodeActions_UnconvertibleFixSkipped(t *testing.T) {
+	source := location.MustNewSourceID("test://file.yammm")
+	// No byte offsets and the default omit fallback: edits cannot be converted.
+	issue := NewIssue(Error, E_SYNTAX, "error").
+		WithFix("Fix", TextEdit{Span: location.Point(source, 1, 1), NewText: "x"}).
+		Build()
+
+	if actions := NewRenderer().LSPCodeActions(issue); actions != nil {
+		t.Errorf("LSPCodeActions = %+v; want nil", actions)
+	}
+	if actions := NewRenderer().LSPCodeActions(NewIssue(Error, E_SYNTAX, "no fixes").Build()); actions != nil {
+		t.Errorf("LSPCodeActions without fixes = %+v; want nil", actions)
+	}
+}
+
 // TestUTF16OffsetFromByte_ASCII tests UTF-16 offset computation for ASCII text.
 func TestUTF16OffsetFromByte_ASCII(t *testing.T) {
 	// ASCII: 1 byte = 1 UTF-16 code unit
@@ -617,6 +683,22 @@ func TestSourceIDToURI(t *testing.T) {
 	})
 }
 
+func TestFileURI(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/foo/bar/", "file:///foo/bar/"},
+		{"/path/with spaces/schema.yammm", "file:///path/with%20spaces/schema.yammm"},
+		{"C:/Users/project/", "file:///C:/Users/project/"},
+	}
+	for _, tt := range tests {
+		if got := fileURI(tt.path); got != tt.want {
+			t.Errorf("fileURI(%q) = %q; want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
 // TestFindLineStartByte verifies that findLineStartByte correctly locates
 // line starts in content.
 func TestFindLineStartByte(t *testing.T) {
@@ -0,0 +1,159 @@
+package diag
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/simon-lentz/yammm/location"
+)
+
+// SeverityPolicy changes the severity of issues by code, or suppresses them.
+//
+// A policy is applied by a [Collector] as issues are collected (see
+// [Collector.SetPolicy]); suppressed issues are not stored but are counted
+// in [Result.SuppressedCount]. Fatal issues are never changed.
+//
+// Configure a policy with the chaining methods before sharing it. A policy
+// that is no longer modified is safe for concurrent use. A nil policy leaves
+// every issue unchanged.
+//
+//	policy := diag.NewSeverityPolicy().
+//	    Override(diag.W_MISSING_DOCUMENTATION, diag.Info).
+//	    Suppress(diag.W_UNUSED_DATATYPE)
+type SeverityPolicy struct {
+	overrides        map[Code]Severity
+	suppressed       map[Code]bool
+	scoped           []scopedSuppression
+	protected        map[Code]bool
+	preserveFailures bool
+}
+
+// scopedSuppression suppresses codes for issues located within a span.
+type scopedSuppression struct {
+	span  location.Span
+	codes []Code
+}
+
+// NewSeverityPolicy creates an empty policy that leaves every issue unchanged.
+func NewSeverityPolicy() *SeverityPolicy {
+	return &SeverityPolicy{}
+}
+
+// Override changes the severity of issues with the given code.
+//
+// Panics if code is zero or severity is not one of Error, Warning, Info, or
+// Hint; no issue can be made Fatal by a policy.
+func (p *SeverityPolicy) Override(code Code, severity Severity) *SeverityPolicy {
+	if code.IsZero() {
+		panic("diag.SeverityPolicy.Override: zero code")
+	}
+	if severity < Error || severity > Hint {
+		panic(fmt.Sprintf("diag.SeverityPolicy.Override: invalid severity %s for %s", severity, code))
+	}
+	if p.overrides == nil {
+		p.overrides = make(map[Code]Severity)
+	}
+	p.overrides[code] = severity
+	return p
+}
+
+// Suppress drops issues with any of the given codes.
+//
+// Panics if any code is zero.
+func (p *SeverityPolicy) Suppress(codes ...Code) *SeverityPolicy {
+	for _, code := range codes {
+		if code.IsZero() {
+			panic("diag.SeverityPolicy.Suppress: zero code")
+		}
+		if p.suppressed == nil {
+			p.suppressed = make(map[Code]bool)
+		}
+		p.suppressed[code] = true
+	}
+	return p
+}
+
+// SuppressWithin drops issues with any of the given codes whose span lies
+// inside span. Issues without a span are not affected.
+//
+// Panics if span is zero or any code is zero.
+func (p *SeverityPolicy) SuppressWithin(span location.Span, codes ...Code) *SeverityPolicy {
+	if span.IsZero() {
+		panic("diag.SeverityPolicy.SuppressWithin: zero span")
+	}
+	for _, code := range codes {
+		if code.IsZero() {
+			panic("diag.SeverityPolicy.SuppressWithin: zero code")
+		}
+	}
+	p.scoped = append(p.scoped, scopedSuppression{span: span, codes: slices.Clone(codes)})
+	return p
+}
+
+// Protect makes the policy leave issues with any of the given codes
+// unchanged, whatever its other rules say. Producers use this for issues
+// they cannot continue past.
+//
+// Panics if any code is zero.
+func (p *SeverityPolicy) Protect(codes ...Code) *SeverityPolicy {
+	for _, code := range codes {
+		if code.IsZero() {
+			panic("diag.SeverityPolicy.Protect: zero code")
+		}
+		if p.protected == nil {
+			p.protected = make(map[Code]bool)
+		}
+		p.protected[code] = true
+	}
+	return p
+}
+
+// PreserveFailures makes the policy leave Error issues unchanged, so that
+// only Warning, Info, and Hint issues are suppressed or changed. Producers
+// that cannot continue past errors, such as the schema loader, use this to
+// keep a policy from hiding a failure.
+func (p *SeverityPolicy) PreserveFailures() *SeverityPolicy {
+	p.preserveFailures = true
+	return p
+}
+
+// Clone returns an independent copy of the policy. Cloning a nil policy
+// returns an empty one.
+func (p *SeverityPolicy) Clone() *SeverityPolicy {
+	if p == nil {
+		return NewSeverityPolicy()
+	}
+	return &SeverityPolicy{
+		overrides:        maps.Clone(p.overrides),
+		suppressed:       maps.Clone(p.suppressed),
+		scoped:           slices.Clone(p.scoped),
+		protected:        maps.Clone(p.protected),
+		preserveFailures: p.preserveFailures,
+	}
+}
+
+// Apply returns the issue as adjusted by the policy, or false if the policy
+// suppresses it.
+func (p *SeverityPolicy) Apply(issue Issue) (Issue, bool) {
+	if p == nil || issue.severity == Fatal || p.protected[issue.code] {
+		return issue, true
+	}
+	if p.preserveFailures && issue.severity.IsFailure() {
+		return issue, true
+	}
+	if p.suppressed[issue.code] {
+		return issue, false
+	}
+	if !issue.span.IsZero() {
+		for _, s := range p.scoped {
+			if slices.Contains(s.codes, issue.code) && s.span.ContainsSpan(issue.span) {
+				return issue, false
+			}
+		}
+	}
+	if severity, ok := p.overrides[issue.code]; ok {
+		issue.severity = severity
+	}
+	return issue, true
+}
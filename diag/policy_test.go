@@ -0,0 +1,158 @@
+package diag
+
+import (
+	"testing"
+
+	"github.com/simon-lentz/yammm/location"
+)
+
+func TestSeverityPolicy_Apply(t *testing.T) {
+	source := location.MustNewSourceID("test://schema.yammm")
+	decl := location.Range(source, 2, 1, 4, 2)
+	inside := location.Range(source, 3, 5, 3, 9)
+	outside := location.Range(source, 6, 1, 6, 4)
+
+	policy := NewSeverityPolicy().
+		Override(W_MISSING_DOCUMENTATION, Info).
+		Override(W_UNUSED_DATATYPE, Error).
+		Suppress(E_INVALID_NAME).
+		SuppressWithin(decl, W_UNUSED_IMPORT)
+
+	tests := []struct {
+		name     string
+		issue    Issue
+		keep     bool
+		severity Severity
+	}{
+		{"untouched", NewIssue(Warning, W_ENUM_VALUE_CASE, "m").Build(), true, Warning},
+		{"downgraded", NewIssue(Warning, W_MISSING_DOCUMENTATION, "m").Build(), true, Info},
+		{"upgraded", NewIssue(Warning, W_UNUSED_DATATYPE, "m").Build(), true, Error},
+		{"suppressed", NewIssue(Error, E_INVALID_NAME, "m").Build(), false, Error},
+		{"suppressed within", NewIssue(Warning, W_UNUSED_IMPORT, "m").WithSpan(inside).Build(), false, Warning},
+		{"outside scope", NewIssue(Warning, W_UNUSED_IMPORT, "m").WithSpan(outside).Build(), true, Warning},
+		{"scope needs span", NewIssue(Warning, W_UNUSED_IMPORT, "m").Build(), true, Warning},
+		{"fatal untouched", NewIssue(Fatal, E_INVALID_NAME, "m").Build(), true, Fatal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, keep := policy.Apply(tt.issue)
+			if keep != tt.keep {
+				t.Fatalf("Apply() keep = %v; want %v", keep, tt.keep)
+			}
+			if got.Severity() != tt.severity {
+				t.Errorf("Apply() severity = %v; want %v", got.Severity(), tt.severity)
+			}
+		})
+	}
+
+	var nilPolicy *SeverityPolicy
+	issue := NewIssue(Error, E_INVALID_NAME, "m").Build()
+	if got, keep := nilPolicy.Apply(issue); !keep || got.Severity() != Error {
+		t.Errorf("nil policy Apply() = %v, %v; want issue unchanged", got.Severity(), keep)
+	}
+}
+
+func TestSeverityPolicy_PreserveFailures(t *testing.T) {
+	policy := NewSeverityPolicy().
+		Suppress(E_INVALID_NAME).
+		Override(E_SYNTAX, Warning).
+		Override(W_UNUSED_IMPORT, Error).
+		PreserveFailures()
+
+	if _, keep := policy.Apply(NewIssue(Error, E_INVALID_NAME, "m").Build()); !keep {
+		t.Error("error was suppressed; want it preserved")
+	}
+	if got, _ := policy.Apply(NewIssue(Error, E_SYNTAX, "m").Build()); got.Severity() != Error {
+		t.Errorf("error severity = %v; want error", got.Severity())
+	}
+	if got, _ := policy.Apply(NewIssue(Warning, W_UNUSED_IMPORT, "m").Build()); got.Severity() != Error {
+		t.Errorf("upgraded warning severity = %v; want error", got.Severity())
+	}
+}
+
+func TestSeverityPolicy_Protect(t *testing.T) {
+	policy := NewSeverityPolicy().
+		Suppress(E_INVALID_NAME).
+		Override(E_SYNTAX, Warning).
+		Protect(E_INVALID_NAME, E_SYNTAX)
+
+	if _, keep := policy.Apply(NewIssue(Error, E_INVALID_NAME, "m").Build()); !keep {
+		t.Error("protected issue was suppressed")
+	}
+	if got, _ := policy.Apply(NewIssue(Error, E_SYNTAX, "m").Build()); got.Severity() != Error {
+		t.Errorf("protected issue severity = %v; want error", got.Severity())
+	}
+}
+
+func TestSeverityPolicy_Clone(t *testing.T) {
+	base := NewSeverityPolicy().Suppress(W_UNUSED_IMPORT)
+	clone := base.Clone().Suppress(W_UNUSED_DATATYPE)
+
+	if _, keep := base.Apply(NewIssue(Warning, W_UNUSED_DATATYPE, "m").Build()); !keep {
+		t.Error("modifying a clone changed the original policy")
+	}
+	if _, keep := clone.Apply(NewIssue(Warning, W_UNUSED_IMPORT, "m").Build()); keep {
+		t.Error("clone lost the original suppression")
+	}
+
+	var nilPolicy *SeverityPolicy
+	if nilPolicy.Clone() == nil {
+		t.Error("Clone() of nil policy = nil; want empty policy")
+	}
+}
+
+func TestSeverityPolicy_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"override zero code", func() { NewSeverityPolicy().Override(Code{}, Warning) }},
+		{"override to fatal", func() { NewSeverityPolicy().Override(E_SYNTAX, Fatal) }},
+		{"override unknown severity", func() { NewSeverityPolicy().Override(E_SYNTAX, Hint+1) }},
+		{"suppress zero code", func() { NewSeverityPolicy().Suppress(Code{}) }},
+		{"protect zero code", func() { NewSeverityPolicy().Protect(Code{}) }},
+		{"suppress within zero span", func() { NewSeverityPolicy().SuppressWithin(location.Span{}, E_SYNTAX) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
+
+func TestCollector_SetPolicy(t *testing.T) {
+	c := NewCollector(1)
+	c.SetPolicy(NewSeverityPolicy().Suppress(W_UNUSED_IMPORT).Override(E_SYNTAX, Warning))
+
+	c.Collect(NewIssue(Warning, W_UNUSED_IMPORT, "suppressed 1").Build())
+	c.Collect(NewIssue(Warning, W_UNUSED_IMPORT, "suppressed 2").Build())
+	c.Collect(NewIssue(Error, E_SYNTAX, "downgraded").Build())
+
+	// Suppressed issues do not count toward the limit.
+	if c.LimitReached() {
+		t.Error("LimitReached() = true; want false")
+	}
+	if !c.OK() {
+		t.Error("OK() = false; want true after downgrade")
+	}
+	res := c.Result()
+	if res.SuppressedCount() != 2 || c.SuppressedCount() != 2 {
+		t.Errorf("SuppressedCount() = %d; want 2", res.SuppressedCount())
+	}
+	if res.Len() != 1 || !res.HasWarnings() {
+		t.Errorf("Result = %v; want one warning", res.Messages())
+	}
+
+	// Merging carries the suppressed count along.
+	merged := NewCollector(0)
+	merged.Collect(NewIssue(Error, E_SYNTAX, "plain").Build())
+	merged.Merge(res)
+	if got := merged.Result().SuppressedCount(); got != 2 {
+		t.Errorf("merged SuppressedCount() = %d; want 2", got)
+	}
+}
@@ -55,6 +55,8 @@ type rendererConfig struct {
 	distinguishFatal    bool
 	truncationIndicator string
 	lspByteFallback     LSPByteFallback
+	locale              string
+	catalog             *Catalog
 }
 
 // RendererOption configures Renderer behavior.
@@ -134,6 +136,25 @@ func WithLSPByteFallback(mode LSPByteFallback) RendererOption {
 	}
 }
 
+// WithLocale renders issue messages and hints in locale (see
+// [Catalog.Localize]): from an issue's own localized message, such as the
+// per-locale variants of invariant messages, or from the [WithCatalog]
+// templates. Text without a translation stays as it is. The default, "",
+// renders issues unchanged.
+func WithLocale(locale string) RendererOption {
+	return func(c *rendererConfig) {
+		c.locale = locale
+	}
+}
+
+// WithCatalog sets the message templates used to render issues in the
+// [WithLocale] locale.
+func WithCatalog(catalog *Catalog) RendererOption {
+	return func(c *rendererConfig) {
+		c.catalog = catalog
+	}
+}
+
 // Renderer provides formatting for diagnostic output.
 //
 // Create with [NewRenderer] and configure with [RendererOption] functions.
@@ -146,6 +167,8 @@ type Renderer struct {
 	distinguishFatal    bool
 	truncationIndicator string
 	lspByteFallback     LSPByteFallback
+	locale              string
+	catalog             *Catalog
 }
 
 // NewRenderer creates a renderer with the given options.
@@ -168,7 +191,17 @@ func NewRenderer(opts ...RendererOption) *Renderer {
 		distinguishFatal:    cfg.distinguishFatal,
 		truncationIndicator: cfg.truncationIndicator,
 		lspByteFallback:     cfg.lspByteFallback,
+		locale:              cfg.locale,
+		catalog:             cfg.catalog,
+	}
+}
+
+// localize returns the issue as rendered in the configured locale.
+func (r *Renderer) localize(issue Issue) Issue {
+	if r.locale == "" {
+		return issue
 	}
+	return r.catalog.Localize(issue, r.locale)
 }
 
 // FormatIssue formats a single issue as text.
@@ -205,6 +238,8 @@ func (r *Renderer) FormatIssues(issues []Issue) string {
 }
 
 func (r *Renderer) formatIssueToBuilder(sb *strings.Builder, issue Issue) {
+	issue = r.localize(issue)
+
 	// Location prefix
 	r.writeLocation(sb, issue)
 
@@ -224,6 +259,12 @@ func (r *Renderer) formatIssueToBuilder(sb *strings.Builder, issue Issue) {
 		sb.WriteString(hint)
 	}
 
+	// Fix titles
+	for _, fix := range issue.fixes {
+		sb.WriteString("\n  fix: ")
+		sb.WriteString(fix.Title)
+	}
+
 	// Source excerpt
 	if r.excerpts && r.provider != nil && issue.HasSpan() {
 		r.writeExcerpt(sb, issue)
@@ -259,7 +300,17 @@ func (r *Renderer) writeLocation(sb *strings.Builder, issue Issue) {
 }
 
 func (r *Renderer) formatSpanLocation(span location.Span) string {
-	source := span.Source.String()
+	source := r.displaySource(span.Source)
+	if span.Start.IsKnown() {
+		return fmt.Sprintf("%s:%d:%d", source, span.Start.Line, span.Start.Column)
+	}
+	return source
+}
+
+// displaySource returns the display name of a source, relative to the
+// module root when one is set.
+func (r *Renderer) displaySource(id location.SourceID) string {
+	source := id.String()
 
 	// Relativize path if module root is set.
 	// Uses string manipulation rather than filepath.Rel because:
@@ -272,10 +323,6 @@ func (r *Renderer) formatSpanLocation(span location.Span) string {
 			source = rel
 		}
 	}
-
-	if span.Start.IsKnown() {
-		return fmt.Sprintf("%s:%d:%d", source, span.Start.Line, span.Start.Column)
-	}
 	return source
 }
 
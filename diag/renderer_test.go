@@ -418,6 +418,20 @@ func TestRenderer_FormatIssue_Hint(t *testing.T) {
 	}
 }
 
+func TestRenderer_FormatIssue_Fixes(t *testing.T) {
+	source := location.MustNewSourceID("test://schema.yammm")
+	issue := NewIssue(Error, E_UNKNOWN_TYPE, `unknown type "Persn"`).
+		WithHint(`did you mean "Person"?`).
+		WithFix(`Replace with "Person"`, TextEdit{Span: location.Range(source, 3, 8, 3, 13), NewText: "Person"}).
+		Build()
+
+	output := NewRenderer().FormatIssue(issue)
+	want := "\n  hint: did you mean \"Person\"?\n  fix: Replace with \"Person\""
+	if !strings.Contains(output, want) {
+		t.Errorf("output should list the fix after the hint, got: %s", output)
+	}
+}
+
 func TestRenderer_FormatIssue_Related(t *testing.T) {
 	source := location.MustNewSourceID("test://related.yammm")
 	issue := NewIssue(Error, E_TYPE_COLLISION, "type collision").
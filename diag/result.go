@@ -29,6 +29,9 @@ type Result struct {
 	limitReached bool
 	droppedCount int
 
+	// Issues suppressed by a severity policy (set by Collector.Result)
+	suppressedCount int
+
 	// Precomputed counts (set at construction time)
 	fatalCount   int
 	errorCount   int
@@ -129,6 +132,12 @@ func (r Result) DroppedCount() int {
 	return r.droppedCount
 }
 
+// SuppressedCount returns how many issues a [SeverityPolicy] suppressed.
+// Suppressed issues are not part of the result.
+func (r Result) SuppressedCount() int {
+	return r.suppressedCount
+}
+
 // Limit returns the configured issue limit (0 means unlimited).
 // Use [LimitReached] to check if the limit was actually reached.
 func (r Result) Limit() int {
@@ -0,0 +1,312 @@
+package diag
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/simon-lentz/yammm/location"
+)
+
+// SARIF constants for the 2.1.0 log format.
+//
+// See: https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// SARIFSourceRootBaseID is the uriBaseId of artifact locations made
+	// relative by [WithModuleRoot].
+	SARIFSourceRootBaseID = "SRCROOT"
+)
+
+// SARIF result levels.
+const (
+	SARIFLevelError   = "error"
+	SARIFLevelWarning = "warning"
+	SARIFLevelNote    = "note"
+)
+
+// Wire format types for SARIF serialization. Only the subset of SARIF that
+// diagnostics map onto is modeled.
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitzero"`
+	ColumnKind         string                           `json:"columnKind"`
+	Results            []sarifResult                    `json:"results"`
+	Properties         *sarifRunProperties              `json:"properties,omitzero"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID         string              `json:"id"`
+	Properties sarifRuleProperties `json:"properties"`
+}
+
+type sarifRuleProperties struct {
+	Tags []string `json:"tags"`
+}
+
+type sarifRunProperties struct {
+	Limit        int  `json:"limit"`
+	LimitReached bool `json:"limitReached"`
+	DroppedCount int  `json:"droppedCount"`
+}
+
+type sarifResult struct {
+	RuleID           string                 `json:"ruleId"`
+	RuleIndex        int                    `json:"ruleIndex"`
+	Level            string                 `json:"level"`
+	Message          sarifMessage           `json:"message"`
+	Locations        []sarifLocation        `json:"locations,omitzero"`
+	RelatedLocations []sarifLocation        `json:"relatedLocations,omitzero"`
+	Properties       *sarifResultProperties `json:"properties,omitzero"`
+}
+
+type sarifResultProperties struct {
+	Severity string       `json:"severity"`
+	Hint     string       `json:"hint,omitzero"`
+	Details  []detailWire `json:"details,omitzero"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	ID               int                    `json:"id,omitzero"`
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitzero"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitzero"`
+	Message          *sarifMessage          `json:"message,omitzero"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitzero"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitzero"`
+}
+
+// sarifRegion uses 1-based lines and 1-based UTF-16 columns; the end column
+// is exclusive, like [location.Span]. Lines and columns are never 0, so 0
+// means unknown and is omitted; the byte fields are pointers because 0 is a
+// valid offset and length.
+type sarifRegion struct {
+	StartLine   int  `json:"startLine"`
+	StartColumn int  `json:"startColumn,omitzero"`
+	EndLine     int  `json:"endLine,omitzero"`
+	EndColumn   int  `json:"endColumn,omitzero"`
+	ByteOffset  *int `json:"byteOffset,omitzero"`
+	ByteLength  *int `json:"byteLength,omitzero"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// FormatResultSARIF returns a SARIF 2.1.0 log with a single run containing
+// the issues of res.
+//
+// Each distinct [Code] becomes a rule whose ID is the code string, tagged
+// with its [CodeCategory]. Severities map to levels as described in
+// [SeverityToSARIF]; the exact severity is kept in the result properties.
+//
+// Spans become physical locations. Columns are UTF-16 code units computed
+// as for [Renderer.LSPDiagnostic]; when they cannot be computed (see
+// [WithLSPByteFallback]) the region carries lines only. With
+// [WithModuleRoot], files below the root get relative URIs against the
+// [SARIFSourceRootBaseID] base. Instance paths become logical locations and
+// related information becomes related locations.
+func (r *Renderer) FormatResultSARIF(res Result) json.RawMessage {
+	run := sarifRun{
+		Tool:       sarifTool{Driver: sarifDriver{Name: "yammm", Rules: []sarifRule{}}},
+		ColumnKind: "utf16CodeUnits",
+		Results:    []sarifResult{},
+	}
+
+	ruleIndex := make(map[string]int)
+	usesRoot := false
+	for issue := range res.Issues() {
+		code := issue.Code().String()
+		idx, ok := ruleIndex[code]
+		if !ok {
+			idx = len(run.Tool.Driver.Rules)
+			ruleIndex[code] = idx
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:         code,
+				Properties: sarifRuleProperties{Tags: []string{issue.Code().Category().String()}},
+			})
+		}
+		result, rooted := r.toSARIFResult(issue, idx)
+		usesRoot = usesRoot || rooted
+		run.Results = append(run.Results, result)
+	}
+
+	if usesRoot {
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{
+			SARIFSourceRootBaseID: {URI: fileURI(strings.TrimSuffix(r.moduleRoot, "/") + "/")},
+		}
+	}
+
+	if res.LimitReached() {
+		run.Properties = &sarifRunProperties{
+			Limit:        res.limit,
+			LimitReached: true,
+			DroppedCount: res.DroppedCount(),
+		}
+	}
+
+	log := sarifLog{Version: SARIFVersion, Schema: SARIFSchema, Runs: []sarifRun{run}}
+	//nolint:errchkjson // Wire types are safe; error check is defensive
+	data, err := json.Marshal(log)
+	if err != nil {
+		// This should never happen with our wire types
+		panic("diag: unexpected JSON marshal error: " + err.Error())
+	}
+	return data
+}
+
+// SeverityToSARIF converts our Severity to a SARIF result level.
+//
+// Fatal and Error map to "error", Warning to "warning", and Info and Hint
+// to "note".
+func SeverityToSARIF(sev Severity) string {
+	switch sev {
+	case Fatal, Error:
+		return SARIFLevelError
+	case Warning:
+		return SARIFLevelWarning
+	case Info, Hint:
+		return SARIFLevelNote
+	default:
+		return SARIFLevelError
+	}
+}
+
+// toSARIFResult converts an issue to a SARIF result. The second return value
+// reports whether a location was made relative to the module root.
+func (r *Renderer) toSARIFResult(issue Issue, ruleIndex int) (sarifResult, bool) {
+	issue = r.localize(issue)
+	result := sarifResult{
+		RuleID:    issue.Code().String(),
+		RuleIndex: ruleIndex,
+		Level:     SeverityToSARIF(issue.Severity()),
+		Message:   sarifMessage{Text: issue.Message()},
+		Properties: &sarifResultProperties{
+			Severity: issue.Severity().String(),
+			Hint:     issue.Hint(),
+		},
+	}
+	if details := issue.Details(); len(details) > 0 {
+		result.Properties.Details = make([]detailWire, len(details))
+		for i, d := range details {
+			result.Properties.Details[i] = detailWire(d)
+		}
+	}
+
+	rooted := false
+	var loc sarifLocation
+	switch {
+	case issue.HasSpan():
+		loc.PhysicalLocation, rooted = r.toSARIFPhysicalLocation(issue.Span())
+	case issue.SourceName() != "":
+		// Instance provenance label without a registered span.
+		loc.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: issue.SourceName()},
+		}
+	}
+	if path := issue.Path(); path != "" {
+		loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: path}}
+	}
+	if loc.PhysicalLocation != nil || loc.LogicalLocations != nil {
+		result.Locations = []sarifLocation{loc}
+	}
+
+	for i, rel := range issue.Related() {
+		related := sarifLocation{
+			ID:      i + 1,
+			Message: &sarifMessage{Text: rel.Message},
+		}
+		if !rel.Span.IsZero() {
+			var relRooted bool
+			related.PhysicalLocation, relRooted = r.toSARIFPhysicalLocation(rel.Span)
+			rooted = rooted || relRooted
+		}
+		result.RelatedLocations = append(result.RelatedLocations, related)
+	}
+
+	return result, rooted
+}
+
+// toSARIFPhysicalLocation converts a non-zero span to a SARIF physical
+// location. The second return value reports whether the URI was made
+// relative to the module root.
+func (r *Renderer) toSARIFPhysicalLocation(span location.Span) (*sarifPhysicalLocation, bool) {
+	artifact, rooted := r.toSARIFArtifactLocation(span.Source)
+	loc := &sarifPhysicalLocation{ArtifactLocation: artifact}
+	if !span.Start.IsKnown() {
+		return loc, rooted
+	}
+
+	region := &sarifRegion{StartLine: span.Start.Line}
+	end := span.Start
+	if span.End.IsKnown() {
+		end = span.End
+	}
+	region.EndLine = end.Line
+
+	if start, ok := r.computeUTF16Character(span, span.Start); ok {
+		region.StartColumn = start + 1
+		if endChar, ok := r.computeUTF16Character(span, end); ok {
+			region.EndColumn = endChar + 1
+		} else {
+			region.EndColumn = region.StartColumn
+		}
+	}
+
+	if span.Start.HasByte() {
+		offset := span.Start.Byte
+		region.ByteOffset = &offset
+		if end.HasByte() && end.Byte >= offset {
+			length := end.Byte - offset
+			region.ByteLength = &length
+		}
+	}
+
+	loc.Region = region
+	return loc, rooted
+}
+
+// toSARIFArtifactLocation converts a source to a SARIF artifact location.
+//
+// Files below the module root get a relative URI against the source root
+// base; other sources use the same URIs as LSP output.
+func (r *Renderer) toSARIFArtifactLocation(source location.SourceID) (sarifArtifactLocation, bool) {
+	if root := strings.TrimSuffix(r.moduleRoot, "/"); root != "" {
+		if cp, ok := source.CanonicalPath(); ok {
+			if rel, ok := strings.CutPrefix(cp.String(), root+"/"); ok {
+				u := url.URL{Path: rel}
+				return sarifArtifactLocation{URI: u.String(), URIBaseID: SARIFSourceRootBaseID}, true
+			}
+		}
+	}
+	return sarifArtifactLocation{URI: sourceIDToURI(source)}, false
+}
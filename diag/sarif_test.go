@@ -0,0 +1,311 @@
+package diag
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/simon-lentz/yammm/location"
+)
+
+// decodeSARIF unmarshals SARIF output into generic JSON for assertions.
+func decodeSARIF(t *testing.T, data []byte) map[string]any {
+	t.Helper()
+	var log map[string]any
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v\n%s", err, data)
+	}
+	return log
+}
+
+// sarifRun0 returns the single run of a decoded SARIF log.
+func sarifRun0(t *testing.T, log map[string]any) map[string]any {
+	t.Helper()
+	runs, ok := log["runs"].([]any)
+	if !ok || len(runs) != 1 {
+		t.Fatalf("runs = %v; want exactly one run", log["runs"])
+	}
+	return runs[0].(map[string]any)
+}
+
+// jsonPath walks decoded JSON by object keys and array indices.
+func jsonPath(t *testing.T, v any, path ...any) any {
+	t.Helper()
+	for _, p := range path {
+		switch key := p.(type) {
+		case string:
+			m, ok := v.(map[string]any)
+			if !ok {
+				t.Fatalf("at %q: not an object: %v", key, v)
+			}
+			v = m[key]
+		case int:
+			a, ok := v.([]any)
+			if !ok || key >= len(a) {
+				t.Fatalf("at [%d]: not an array of sufficient length: %v", key, v)
+			}
+			v = a[key]
+		}
+	}
+	return v
+}
+
+func TestFormatResultSARIF_Empty(t *testing.T) {
+	r := NewRenderer()
+	log := decodeSARIF(t, r.FormatResultSARIF(OK()))
+
+	if log["version"] != "2.1.0" {
+		t.Errorf("version = %v; want 2.1.0", log["version"])
+	}
+	if log["$schema"] != SARIFSchema {
+		t.Errorf("$schema = %v; want %s", log["$schema"], SARIFSchema)
+	}
+	run := sarifRun0(t, log)
+	if got := jsonPath(t, run, "tool", "driver", "name"); got != "yammm" {
+		t.Errorf("driver name = %v; want yammm", got)
+	}
+	if results, ok := run["results"].([]any); !ok || len(results) != 0 {
+		t.Errorf("results = %v; want empty array", run["results"])
+	}
+	if _, ok := run["originalUriBaseIds"]; ok {
+		t.Error("originalUriBaseIds should be omitted without relative locations")
+	}
+}
+
+func TestFormatResultSARIF_RulesAndLevels(t *testing.T) {
+	source := location.MustNewSourceID("test://schema.yammm")
+	c := NewCollectorUnlimited()
+	c.Collect(NewIssue(Error, E_TYPE_COLLISION, "first").WithSpan(location.Point(source, 1, 1)).Build())
+	c.Collect(NewIssue(Warning, E_SYNTAX, "second").WithSpan(location.Point(source, 2, 1)).Build())
+	c.Collect(NewIssue(Error, E_TYPE_COLLISION, "third").WithSpan(location.Point(source, 3, 1)).Build())
+	c.Collect(NewIssue(Hint, E_TYPE_MISMATCH, "fourth").WithPath("", "$.Person[0]").Build())
+
+	run := sarifRun0(t, decodeSARIF(t, NewRenderer().FormatResultSARIF(c.Result())))
+
+	rules := jsonPath(t, run, "tool", "driver", "rules").([]any)
+	ruleIDs := make([]string, len(rules))
+	for i, rule := range rules {
+		ruleIDs[i] = rule.(map[string]any)["id"].(string)
+	}
+	if want := []string{"E_TYPE_COLLISION", "E_SYNTAX", "E_TYPE_MISMATCH"}; !reflect.DeepEqual(ruleIDs, want) {
+		t.Errorf("rule IDs = %v; want %v", ruleIDs, want)
+	}
+	if got := jsonPath(t, rules[1], "properties", "tags", 0); got != "syntax" {
+		t.Errorf("E_SYNTAX tag = %v; want syntax", got)
+	}
+
+	results := run["results"].([]any)
+	tests := []struct {
+		ruleID    string
+		ruleIndex float64
+		level     string
+		severity  string
+	}{
+		{"E_TYPE_COLLISION", 0, "error", "error"},
+		{"E_SYNTAX", 1, "warning", "warning"},
+		{"E_TYPE_COLLISION", 0, "error", "error"},
+		{"E_TYPE_MISMATCH", 2, "note", "hint"},
+	}
+	if len(results) != len(tests) {
+		t.Fatalf("len(results) = %d; want %d", len(results), len(tests))
+	}
+	for i, tt := range tests {
+		res := results[i].(map[string]any)
+		if res["ruleId"] != tt.ruleID || res["ruleIndex"] != tt.ruleIndex || res["level"] != tt.level {
+			t.Errorf("results[%d] = %v/%v/%v; want %v/%v/%v", i,
+				res["ruleId"], res["ruleIndex"], res["level"], tt.ruleID, tt.ruleIndex, tt.level)
+		}
+		if got := jsonPath(t, res, "properties", "severity"); got != tt.severity {
+			t.Errorf("results[%d] severity = %v; want %v", i, got, tt.severity)
+		}
+	}
+}
+
+func TestSeverityToSARIF(t *testing.T) {
+	tests := []struct {
+		severity Severity
+		want     string
+	}{
+		{Fatal, SARIFLevelError},
+		{Error, SARIFLevelError},
+		{Warning, SARIFLevelWarning},
+		{Info, SARIFLevelNote},
+		{Hint, SARIFLevelNote},
+	}
+	for _, tt := range tests {
+		if got := SeverityToSARIF(tt.severity); got != tt.want {
+			t.Errorf("SeverityToSARIF(%v) = %q; want %q", tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestFormatResultSARIF_Region(t *testing.T) {
+	source := location.MustNewSourceID("test://emoji.yammm")
+	// Line 2: two emoji (4 bytes, 2 UTF-16 units each) precede "name".
+	content := "type A {\n\U0001F600\U0001F600name String\n}\n"
+	provider := newMockLineIndexProvider()
+	provider.AddWithIndex(source, content)
+
+	// "name" starts at byte 9+8=17 (column 3) and ends at byte 21 (column 7).
+	span := location.RangeWithBytes(source, 2, 3, 17, 2, 7, 21)
+	issue := NewIssue(Error, E_SYNTAX, "bad name").WithSpan(span).Build()
+
+	t.Run("exact UTF-16 columns and bytes", func(t *testing.T) {
+		r := NewRenderer(WithSourceProvider(provider))
+		run := sarifRun0(t, decodeSARIF(t, r.FormatResultSARIF(resultOf(issue))))
+		if run["columnKind"] != "utf16CodeUnits" {
+			t.Errorf("columnKind = %v; want utf16CodeUnits", run["columnKind"])
+		}
+		loc := jsonPath(t, run, "results", 0, "locations", 0, "physicalLocation")
+		if got := jsonPath(t, loc, "artifactLocation", "uri"); got != "test://emoji.yammm" {
+			t.Errorf("uri = %v; want test://emoji.yammm", got)
+		}
+		want := map[string]any{
+			"startLine": 2.0, "startColumn": 5.0, "endLine": 2.0, "endColumn": 9.0,
+			"byteOffset": 17.0, "byteLength": 4.0,
+		}
+		if got := jsonPath(t, loc, "region"); !reflect.DeepEqual(got, want) {
+			t.Errorf("region = %v; want %v", got, want)
+		}
+	})
+
+	t.Run("lines only without content", func(t *testing.T) {
+		r := NewRenderer()
+		run := sarifRun0(t, decodeSARIF(t, r.FormatResultSARIF(resultOf(issue))))
+		want := map[string]any{"startLine": 2.0, "endLine": 2.0, "byteOffset": 17.0, "byteLength": 4.0}
+		if got := jsonPath(t, run, "results", 0, "locations", 0, "physicalLocation", "region"); !reflect.DeepEqual(got, want) {
+			t.Errorf("region = %v; want %v", got, want)
+		}
+	})
+
+	t.Run("approximate columns", func(t *testing.T) {
+		r := NewRenderer(WithLSPByteFallback(LSPByteFallbackApproximate))
+		point := NewIssue(Error, E_SYNTAX, "bad").WithSpan(location.Point(source, 4, 6)).Build()
+		run := sarifRun0(t, decodeSARIF(t, r.FormatResultSARIF(resultOf(point))))
+		want := map[string]any{"startLine": 4.0, "startColumn": 6.0, "endLine": 4.0, "endColumn": 6.0}
+		if got := jsonPath(t, run, "results", 0, "locations", 0, "physicalLocation", "region"); !reflect.DeepEqual(got, want) {
+			t.Errorf("region = %v; want %v", got, want)
+		}
+	})
+}
+
+func TestFormatResultSARIF_LogicalAndRelatedLocations(t *testing.T) {
+	source := location.MustNewSourceID("test://schema.yammm")
+	other := location.MustNewSourceID("test://other.yammm")
+	issue := NewIssue(Error, E_TYPE_COLLISION, `type "Person" already defined`).
+		WithSpan(location.Point(source, 5, 1)).
+		WithPath("", "$.Person[2].name").
+		WithHint("rename one of the types").
+		WithDetails(TypeProp("Person", "name")...).
+		WithRelated(
+			location.RelatedInfo{Span: location.Point(other, 1, 6), Message: "previous definition here"},
+			location.RelatedInfo{Message: "no location"},
+		).
+		Build()
+
+	r := NewRenderer(WithLSPByteFallback(LSPByteFallbackApproximate))
+	res := jsonPath(t, sarifRun0(t, decodeSARIF(t, r.FormatResultSARIF(resultOf(issue)))), "results", 0)
+
+	if got := jsonPath(t, res, "locations", 0, "logicalLocations", 0, "fullyQualifiedName"); got != "$.Person[2].name" {
+		t.Errorf("logical location = %v; want $.Person[2].name", got)
+	}
+	if got := jsonPath(t, res, "properties", "hint"); got != "rename one of the types" {
+		t.Errorf("hint = %v", got)
+	}
+	if got := jsonPath(t, res, "properties", "details", 1, "value"); got != "name" {
+		t.Errorf("details[1].value = %v; want name", got)
+	}
+
+	related := res.(map[string]any)["relatedLocations"].([]any)
+	if len(related) != 2 {
+		t.Fatalf("len(relatedLocations) = %d; want 2", len(related))
+	}
+	if got := jsonPath(t, related[0], "physicalLocation", "artifactLocation", "uri"); got != "test://other.yammm" {
+		t.Errorf("related uri = %v", got)
+	}
+	if got := jsonPath(t, related[0], "message", "text"); got != "previous definition here" {
+		t.Errorf("related message = %v", got)
+	}
+	if got := jsonPath(t, related[1], "id"); got != 2.0 {
+		t.Errorf("related id = %v; want 2", got)
+	}
+	if _, ok := related[1].(map[string]any)["physicalLocation"]; ok {
+		t.Error("related info without span should have no physical location")
+	}
+}
+
+func TestFormatResultSARIF_InstanceProvenance(t *testing.T) {
+	issue := NewIssue(Error, E_TYPE_MISMATCH, "expected integer").
+		WithPath("people.json", "$.Person[0].age").
+		Build()
+
+	run := sarifRun0(t, decodeSARIF(t, NewRenderer().FormatResultSARIF(resultOf(issue))))
+	loc := jsonPath(t, run, "results", 0, "locations", 0).(map[string]any)
+	if got := jsonPath(t, loc, "physicalLocation", "artifactLocation", "uri"); got != "people.json" {
+		t.Errorf("uri = %v; want people.json", got)
+	}
+	if _, ok := loc["physicalLocation"].(map[string]any)["region"]; ok {
+		t.Error("provenance without span should have no region")
+	}
+	if got := jsonPath(t, loc, "logicalLocations", 0, "fullyQualifiedName"); got != "$.Person[0].age" {
+		t.Errorf("logical location = %v", got)
+	}
+}
+
+func TestFormatResultSARIF_ModuleRoot(t *testing.T) {
+	inside, err := location.SourceIDFromAbsolutePath("/work/my project/schemas/main.yammm")
+	if err != nil {
+		t.Skipf("skipping file-backed test: %v", err)
+	}
+	outside, err := location.SourceIDFromAbsolutePath("/elsewhere/lib.yammm")
+	if err != nil {
+		t.Skipf("skipping file-backed test: %v", err)
+	}
+	c := NewCollectorUnlimited()
+	c.Collect(NewIssue(Error, E_SYNTAX, "in").WithSpan(location.Point(inside, 1, 1)).Build())
+	c.Collect(NewIssue(Error, E_SYNTAX, "out").WithSpan(location.Point(outside, 1, 1)).Build())
+
+	run := sarifRun0(t, decodeSARIF(t, NewRenderer(WithModuleRoot("/work/my project")).FormatResultSARIF(c.Result())))
+
+	if got := jsonPath(t, run, "originalUriBaseIds", SARIFSourceRootBaseID, "uri"); got != "file:///work/my%20project/" {
+		t.Errorf("source root = %v", got)
+	}
+	want := map[string]map[string]any{
+		"in":  {"uri": "schemas/main.yammm", "uriBaseId": SARIFSourceRootBaseID},
+		"out": {"uri": "file:///elsewhere/lib.yammm"},
+	}
+	for _, res := range run["results"].([]any) {
+		msg := jsonPath(t, res, "message", "text").(string)
+		got := jsonPath(t, res, "locations", 0, "physicalLocation", "artifactLocation")
+		if !reflect.DeepEqual(got, any(want[msg])) {
+			t.Errorf("%s: artifactLocation = %v; want %v", msg, got, want[msg])
+		}
+	}
+}
+
+func TestFormatResultSARIF_LimitReached(t *testing.T) {
+	c := NewCollector(1)
+	c.Collect(NewIssue(Error, E_SYNTAX, "one").Build())
+	c.Collect(NewIssue(Error, E_SYNTAX, "two").Build())
+	res := c.Result()
+	if !res.LimitReached() {
+		t.Fatal("expected limit to be reached")
+	}
+
+	run := sarifRun0(t, decodeSARIF(t, NewRenderer().FormatResultSARIF(res)))
+	if got := jsonPath(t, run, "properties", "limitReached"); got != true {
+		t.Errorf("limitReached = %v; want true", got)
+	}
+	if got := jsonPath(t, run, "properties", "droppedCount"); got != float64(res.DroppedCount()) {
+		t.Errorf("droppedCount = %v; want %d", got, res.DroppedCount())
+	}
+}
+
+// resultOf returns a result containing the given issues.
+func resultOf(issues ...Issue) Result {
+	c := NewCollectorUnlimited()
+	for _, issue := range issues {
+		c.Collect(issue)
+	}
+	return c.Result()
+}
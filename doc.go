@@ -18,9 +18,12 @@
 //	  - schema: Type system, constraints, and schema compilation
 //	  - instance: Instance validation and constraint checking
 //	  - graph: Instance graph construction and integrity checking
+//	  - ingest: Streaming validation into a graph
 //
 //	Adapter tier:
 //	  - adapter/json: JSON parsing with location tracking
+//	  - adapter/yaml: YAML parsing with location tracking
+//	  - adapter/csv: CSV/TSV parsing and export
 //
 // # Entry Points
 //
@@ -82,6 +85,9 @@
 //   - [github.com/simon-lentz/yammm/schema/build]: Programmatic schema building
 //   - [github.com/simon-lentz/yammm/instance]: Instance validation
 //   - [github.com/simon-lentz/yammm/graph]: Instance graph management
+//   - [github.com/simon-lentz/yammm/ingest]: Streaming ingestion
 //   - [github.com/simon-lentz/yammm/adapter/json]: JSON adapter
+//   - [github.com/simon-lentz/yammm/adapter/yaml]: YAML adapter
+//   - [github.com/simon-lentz/yammm/adapter/csv]: CSV adapter
 //   - [github.com/simon-lentz/yammm/lsp]: Language Server Protocol server
 package yammm
@@ -6,7 +6,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	csvadapter "github.com/simon-lentz/yammm/adapter/csv"
 	jsonadapter "github.com/simon-lentz/yammm/adapter/json"
+	yamladapter "github.com/simon-lentz/yammm/adapter/yaml"
+	"github.com/simon-lentz/yammm/diag"
 	"github.com/simon-lentz/yammm/internal/source"
 	"github.com/simon-lentz/yammm/location"
 )
@@ -254,3 +257,105 @@ func TestAdapter_ParseObject_RecordProperties(t *testing.T) {
 	assert.Equal(t, int64(30), alice.Properties["age"], "integer property should be int64")
 	assert.Equal(t, true, alice.Properties["active"], "boolean property should match")
 }
+
+// =============================================================================
+// YAML Adapter — SPEC.md §YAML Adapter
+// =============================================================================
+
+// TestAdapter_YAML_ValidateWithLocations verifies that instances parsed from a
+// YAML stream validate against a schema, that YAML 1.1 coercions do not apply,
+// and that diagnostics point at the YAML source.
+// Source: SPEC.md, "YAML Adapter" — streams, aliases, scalar resolution
+func TestAdapter_YAML_ValidateWithLocations(t *testing.T) {
+	t.Parallel()
+	v := loadSchemaString(t, `schema "YamlPeople"
+type Person {
+    id String primary
+    country String[2, 2] required
+    zip String
+    active Boolean
+    age Integer[0, 150]
+}`, "yaml_people")
+
+	data := []byte(`Person:
+  - &base
+    id: p1
+    country: no
+    zip: 01234
+    active: true
+---
+Person:
+  - <<: *base
+    id: p2
+  - id: p3
+    country: de
+    age: 200
+`)
+	reg := source.NewRegistry()
+	sourceID := location.NewSourceID("test://people.yaml")
+	require.NoError(t, reg.Register(sourceID, data))
+	a, err := yamladapter.NewAdapter(reg, yamladapter.WithTrackLocations(true))
+	require.NoError(t, err)
+
+	parsed, result := a.ParseObject(sourceID, data)
+	require.True(t, result.OK(), "ParseObject should succeed: %v", result.Messages())
+	people := parsed["Person"]
+	require.Len(t, people, 3)
+
+	p1 := validateOne(t, v, "Person", people[0])
+	country, _ := p1.Properties().Get("country")
+	assert.Equal(t, "no", country.Unwrap(), "no stays a string")
+	validateOne(t, v, "Person", people[1])
+
+	_, failure, err := v.ValidateOne(t.Context(), "Person", people[2])
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	issue := failure.Result.IssuesSlice()[0]
+	assert.Equal(t, "$.Person[2].age", issue.Path())
+	assert.Equal(t, 11, issue.Span().Start.Line, "span points into the second document")
+}
+
+// =============================================================================
+// CSV Adapter — SPEC.md §CSV Adapter
+// =============================================================================
+
+// TestAdapter_CSV_ValidateAndRenderCell verifies that rows parsed from CSV
+// validate against a schema and that a cell which cannot be converted is
+// rendered with an excerpt pointing at the cell.
+// Source: SPEC.md, "CSV Adapter" — cell conversion and location tracking
+func TestAdapter_CSV_ValidateAndRenderCell(t *testing.T) {
+	t.Parallel()
+	s, v := loadSchemaStringRaw(t, `schema "CsvPatients"
+type Patient {
+    id String primary
+    name String required
+    age Integer[0, 150]
+    status Enum["admitted", "discharged"]
+}`, "csv_patients")
+
+	data := []byte("id,name,age,status\np1,Alice,42,admitted\np2,Bob,forty,discharged\np3,Carol,200,admitted\n")
+	reg := source.NewRegistry()
+	sourceID := location.NewSourceID("test://patients.csv")
+	require.NoError(t, reg.Register(sourceID, data))
+	a, err := csvadapter.NewAdapter(reg, csvadapter.WithTrackLocations(true))
+	require.NoError(t, err)
+
+	patients, result := a.ParseType(sourceID, s, "Patient", data)
+	require.Len(t, patients, 2, "the row with the bad cell is dropped")
+	validateOne(t, v, "Patient", patients[0])
+
+	require.False(t, result.OK())
+	issue := result.IssuesSlice()[0]
+	assert.Equal(t, diag.E_TYPE_MISMATCH, issue.Code())
+	assert.Equal(t, "$[1].age", issue.Path())
+	assert.Equal(t, 3, issue.Span().Start.Line)
+	assert.Equal(t, 8, issue.Span().Start.Column)
+	rendered := diag.NewRenderer(diag.WithSourceProvider(reg), diag.WithExcerpts(true)).FormatIssue(issue)
+	assert.Contains(t, rendered, "p2,Bob,forty,discharged")
+	assert.Contains(t, rendered, "^^^^^")
+
+	_, failure, err := v.ValidateOne(t.Context(), "Patient", patients[1])
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	assert.Equal(t, "$[2].age", failure.Result.IssuesSlice()[0].Path())
+}
@@ -356,6 +356,24 @@ type R {
 	assertInvalid(t, v, "R", raw(map[string]any{"id": "2", "birthday": "not-a-date"}), diag.E_CONSTRAINT_FAIL)
 }
 
+// TestDatatypes_DateRange verifies that Date bounds and layouts are enforced,
+// including bounds relative to today.
+// Source: SPEC.md, "Date" — "Date[min, max]" and "Date[layout, min, max]"
+func TestDatatypes_DateRange(t *testing.T) {
+	t.Parallel()
+	v := loadSchemaString(t, `schema "DtRange"
+type R {
+    id String primary
+    born Date["1900-01-01", today] required
+    due Date["02.01.2006", "01.01.2020", _]
+}`, "date_range")
+	assertValid(t, v, "R", raw(map[string]any{"id": "1", "born": "1970-05-01", "due": "15.03.2021"}))
+	assertInvalid(t, v, "R", raw(map[string]any{"id": "2", "born": "1899-12-31"}), diag.E_CONSTRAINT_FAIL)
+	assertInvalid(t, v, "R", raw(map[string]any{"id": "3", "born": "9999-01-01"}), diag.E_CONSTRAINT_FAIL)
+	assertInvalid(t, v, "R", raw(map[string]any{"id": "4", "born": "1970-05-01", "due": "2021-03-15"}), diag.E_CONSTRAINT_FAIL)
+	assertInvalid(t, v, "R", raw(map[string]any{"id": "5", "born": "1970-05-01", "due": "31.12.2019"}), diag.E_CONSTRAINT_FAIL)
+}
+
 // =============================================================================
 // UUID
 // =============================================================================
@@ -462,3 +480,38 @@ type Car {
 	assertValid(t, v, "Car", raw(map[string]any{"id": "1", "paintColor": "red"}))
 	assertInvalid(t, v, "Car", raw(map[string]any{"id": "2", "paintColor": "yellow"}), diag.E_CONSTRAINT_FAIL)
 }
+
+// =============================================================================
+// Unions
+// =============================================================================
+
+// TestDatatypes_UnionBasic verifies that a union data type accepts a value
+// matching any member and rejects values matching none.
+// Source: SPEC.md, "Union" — value must satisfy at least one member
+func TestDatatypes_UnionBasic(t *testing.T) {
+	t.Parallel()
+	v := loadSchemaString(t, `schema "UnionBasic"
+
+type Ref = String[1, 5] | Integer[0, _]
+
+type R {
+    id String primary
+    ref Ref required
+}`, "union_basic")
+	assertValid(t, v, "R", raw(map[string]any{"id": "1", "ref": "abc"}))
+	assertValid(t, v, "R", raw(map[string]any{"id": "2", "ref": int64(42)}))
+	assertInvalid(t, v, "R", raw(map[string]any{"id": "3", "ref": "toolong"}), diag.E_CONSTRAINT_FAIL)
+	assertInvalid(t, v, "R", raw(map[string]any{"id": "4", "ref": int64(-1)}), diag.E_CONSTRAINT_FAIL)
+	assertInvalid(t, v, "R", raw(map[string]any{"id": "5", "ref": true}), diag.E_TYPE_MISMATCH)
+}
+
+// TestDatatypes_UnionDuplicateMemberRejected verifies that listing the same
+// member twice is a schema error.
+// Source: SPEC.md, "Union" — members must be distinct
+func TestDatatypes_UnionDuplicateMemberRejected(t *testing.T) {
+	t.Parallel()
+	result := loadSchemaStringExpectError(t, `schema "UnionDup"
+
+type Ref = String | Integer | String`, "union_dup")
+	assertDiagHasCode(t, result, diag.E_INVALID_CONSTRAINT)
+}
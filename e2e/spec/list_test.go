@@ -1,9 +1,13 @@
 package spec_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/simon-lentz/yammm/diag"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // =============================================================================
@@ -534,3 +538,69 @@ type R {
 		"tags": []any{"a", "b", "c", "d"},
 	}), diag.E_CONSTRAINT_FAIL)
 }
+
+// =============================================================================
+// Set — Unique Elements
+// =============================================================================
+
+func TestSet_UniqueElements(t *testing.T) {
+	t.Parallel()
+	v := loadSchemaString(t, `schema "SetUnique"
+type R {
+    id String primary
+    tags Set<String>[_, 3] required
+}`, "set_unique")
+	assertValid(t, v, "R", raw(map[string]any{
+		"id":   "1",
+		"tags": []any{"b", "a", "c"},
+	}))
+	assertInvalid(t, v, "R", raw(map[string]any{
+		"id":   "2",
+		"tags": []any{"a", "b", "a"},
+	}), diag.E_CONSTRAINT_FAIL)
+	assertInvalid(t, v, "R", raw(map[string]any{
+		"id":   "3",
+		"tags": []any{"a", "b", "c", "d"},
+	}), diag.E_CONSTRAINT_FAIL)
+}
+
+func TestSet_DuplicateNamesIndices(t *testing.T) {
+	t.Parallel()
+	v := loadSchemaString(t, `schema "SetIndices"
+type R {
+    id String primary
+    scores Set<Float>
+}`, "set_indices")
+	_, failure, err := v.ValidateOne(t.Context(), "R", raw(map[string]any{
+		"id":     "1",
+		"scores": []any{1.5, int64(2), 2.0},
+	}))
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	assert.Contains(t, strings.Join(failureMessages(failure), "\n"), "set element [2] duplicates element [1]")
+}
+
+func TestSet_NarrowFromList(t *testing.T) {
+	t.Parallel()
+	_ = loadSchemaString(t, `schema "SetNarrow"
+abstract type Base {
+    id String primary
+    tags List<String>
+}
+type Child extends Base {
+    tags Set<String>
+}`, "set_narrow")
+}
+
+func TestSet_WidenToListFails(t *testing.T) {
+	t.Parallel()
+	result := loadSchemaStringExpectError(t, `schema "SetWiden"
+abstract type Base {
+    id String primary
+    tags Set<String>
+}
+type Child extends Base {
+    tags List<String>
+}`, "set_widen")
+	assertDiagHasCode(t, result, diag.E_PROPERTY_CONFLICT)
+}
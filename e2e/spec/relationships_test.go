@@ -3,8 +3,10 @@ package spec_test
 import (
 	"testing"
 
+	jsonadapter "github.com/simon-lentz/yammm/adapter/json"
 	"github.com/simon-lentz/yammm/diag"
 	"github.com/simon-lentz/yammm/graph"
+	"github.com/simon-lentz/yammm/location"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -93,10 +95,111 @@ func TestRelationships_CompositionRequiresPart(t *testing.T) {
 	assertDiagHasCode(t, result, diag.E_INVALID_COMPOSITION_TARGET)
 }
 
+// =============================================================================
+// Compositions — abstract part targets
+// =============================================================================
+
+// TestRelationships_PolymorphicComposition verifies that a composition to an
+// abstract part type accepts concrete part subtypes chosen by $type, keeps the
+// concrete type on the graph, and writes it back for round-tripping.
+// Source: SPEC.md, "Compositions" — abstract part targets.
+func TestRelationships_PolymorphicComposition(t *testing.T) {
+	t.Parallel()
+	s, v := loadSchemaRaw(t, "testdata/relationships/composition_polymorphic.yammm")
+
+	order := validateOne(t, v, "Order", loadTestData(t, "testdata/relationships/polymorphic.json", "Order")[0])
+	res := buildGraph(t, s, order)
+
+	orders := res.InstancesOf("Order")
+	require.Len(t, orders, 1)
+	payments := orders[0].Composed("PAYMENTS")
+	require.Len(t, payments, 2)
+	types := []string{payments[0].TypeName(), payments[1].TypeName()}
+	assert.ElementsMatch(t, []string{"CardPayment", "BankTransfer"}, types)
+
+	adapter, err := jsonadapter.NewAdapter(nil)
+	require.NoError(t, err)
+	out, err := adapter.MarshalObject(res)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"$type":"CardPayment"`)
+	assert.Contains(t, string(out), `"$type":"BankTransfer"`)
+
+	// The written document validates again.
+	parsed, result := adapter.ParseObject(location.NewSourceID("test://roundtrip.json"), out)
+	require.True(t, result.OK(), "parse written output: %v", result.Messages())
+	validateOne(t, v, "Order", parsed["Order"][0])
+}
+
+// TestRelationships_PolymorphicComposition_Invalid verifies type tag
+// diagnostics for children of an abstract part target.
+// Source: SPEC.md, "Compositions" — abstract part targets.
+func TestRelationships_PolymorphicComposition_Invalid(t *testing.T) {
+	t.Parallel()
+	v := loadSchema(t, "testdata/relationships/composition_polymorphic.yammm")
+
+	tests := []struct {
+		name  string
+		child map[string]any
+		code  diag.Code
+	}{
+		{"missing tag", map[string]any{"amount": 1.0, "last4": "4242"}, diag.E_MISSING_TYPE_TAG},
+		{"abstract tag", map[string]any{"$type": "Payment", "amount": 1.0}, diag.E_ABSTRACT_TYPE},
+		{"unrelated tag", map[string]any{"$type": "Order", "id": "x"}, diag.E_INVALID_TYPE_TAG},
+		{"non-string tag", map[string]any{"$type": 1, "amount": 1.0}, diag.E_INVALID_TYPE_TAG},
+		{"subtype fields checked", map[string]any{"$type": "CardPayment", "amount": 1.0, "last4": "42"}, diag.E_CONSTRAINT_FAIL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assertInvalid(t, v, "Order", raw(map[string]any{
+				"id":       "o1",
+				"payments": []any{tt.child},
+			}), tt.code)
+		})
+	}
+}
+
 // =============================================================================
 // Multiplicity — all 8 forms
 // =============================================================================
 
+// TestRelationships_PolymorphicComposition_PrimaryKeys verifies that the
+// children of an abstract part target are keyed by concrete type and primary
+// key: siblings of different types may share key values, siblings of the same
+// type may not.
+// Source: SPEC.md, "Compositions" — abstract part targets.
+func TestRelationships_PolymorphicComposition_PrimaryKeys(t *testing.T) {
+	t.Parallel()
+	s, v := loadSchemaStringRaw(t, `schema "spec_comp_polymorphic_pk"
+
+abstract part type Payment {
+    ref String primary
+}
+
+part type CardPayment extends Payment {
+    last4 String
+}
+
+part type BankTransfer extends Payment {
+    iban String
+}
+
+type Order {
+    id String primary
+    *-> PAYMENTS (many) Payment
+}`, "polymorphic_pk.yammm")
+
+	card := map[string]any{"$type": "CardPayment", "ref": "p1", "last4": "4242"}
+	transfer := map[string]any{"$type": "BankTransfer", "ref": "p1", "iban": "DE02"}
+
+	order := validateOne(t, v, "Order", raw(map[string]any{"id": "o1", "payments": []any{card, transfer}}))
+	res := buildGraph(t, s, order)
+	require.Len(t, res.InstancesOf("Order")[0].Composed("PAYMENTS"), 2)
+
+	assertInvalid(t, v, "Order", raw(map[string]any{"id": "o2", "payments": []any{card, card}}),
+		diag.E_DUPLICATE_COMPOSED_PK)
+}
+
 // TestRelationships_MultiplicityAllForms verifies that all 8 multiplicity forms
 // parse and compile successfully. Each form is checked for the expected
 // optional/many flags on the resulting relation.
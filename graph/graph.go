@@ -484,8 +484,9 @@ func (g *Graph) AddComposed(
 		return opCollector.Result(), nil
 	}
 
-	// Validate child type matches relation target
-	if child.TypeID() != rel.TargetID() {
+	// Validate child type is the relation target or one of its subtypes
+	childTyp, _ := g.lookupType(child.TypeID())
+	if child.TypeID() != rel.TargetID() && (childTyp == nil || !childTyp.IsSubTypeOf(rel.TargetID())) {
 		issue := diag.NewIssue(diag.Error, diag.E_GRAPH_INVALID_COMPOSITION,
 			fmt.Sprintf("child type %q does not match relation target %q", child.TypeName(), g.instanceTagForm(rel.TargetID()))).
 			WithDetail(diag.DetailKeyTypeName, parentType).
@@ -500,7 +501,6 @@ func (g *Graph) AddComposed(
 
 	// Check for duplicates per
 	isMany := rel.IsMany()
-	childTyp, _ := g.lookupType(child.TypeID())
 	hasPK := childTyp != nil && childTyp.HasPrimaryKey()
 
 	if !isMany {
@@ -540,10 +540,11 @@ func (g *Graph) AddComposed(
 			return opCollector.Result(), nil
 		}
 	} else if hasPK {
-		// (many) with PK: check for duplicate PK among siblings
+		// (many) with PK: check for duplicate PK among siblings of the same
+		// concrete type
 		childPKString := child.PrimaryKey().String()
 		for _, existing := range parentInst.composed[relationName] {
-			if existing.PrimaryKey().String() == childPKString {
+			if existing.TypeID() == child.TypeID() && existing.PrimaryKey().String() == childPKString {
 				// Create child Instance for duplicate record
 				childTypeName := g.instanceTagForm(child.TypeID())
 				childInst := newInstance(childTypeName, child.TypeID(), child.PrimaryKey(), child.Properties(), child.Provenance())
@@ -120,7 +120,8 @@ func (i *Instance) HasProvenance() bool {
 //
 // Returns nil if the relation does not exist or has no children.
 // The returned slice is a defensive copy; modifications do not affect
-// the graph.
+// the graph. When the composition targets a part type with subtypes, each
+// child reports its concrete type via [Instance.TypeName] and [Instance.TypeID].
 func (i *Instance) Composed(relationName string) []*Instance {
 	if i == nil || i.composed == nil {
 		return nil
@@ -0,0 +1,40 @@
+// Package ingest loads instance streams into a graph with bounded memory.
+//
+// A [Source] yields [Record] values one at a time, such as the stream
+// readers of the instance adapters. [Run] validates the records on a pool
+// of workers and adds the valid instances to a [graph.Graph] in input
+// order. At most the configured buffer of records is in flight; when the
+// graph falls behind, reading pauses (back-pressure), so multi-gigabyte
+// inputs can be loaded without holding them in memory.
+//
+// Diagnostics are reported to an issue handler as they occur rather than
+// accumulated, and a progress callback receives running [Stats] including
+// the input offset reached.
+//
+// # Basic Usage
+//
+//	adapter, _ := json.NewAdapter(nil)
+//	src := adapter.NewStreamReader(sourceID, file)
+//	g := graph.New(schema)
+//	stats, err := ingest.Run(ctx, src, instance.NewValidator(schema), g,
+//	    ingest.WithIssueHandler(func(issue diag.Issue) { log.Println(issue) }),
+//	    ingest.WithProgress(10_000, func(s ingest.Stats) {
+//	        log.Printf("%d records, %d bytes", s.Read, s.Offset)
+//	    }),
+//	)
+//	if err != nil {
+//	    // I/O error, internal error or context cancelled
+//	}
+//	checkResult, err := g.Check(ctx)
+//
+// # Cancellation
+//
+// Run stops when its context is done, when the source returns an error, or
+// when validation or the graph reports a system error. It returns the stats
+// reached so far with the error; instances already added stay in the graph.
+//
+// # Thread Safety
+//
+// Run may be called concurrently with other Runs feeding the same graph.
+// The issue handler and progress callback are called from Run's goroutine.
+package ingest
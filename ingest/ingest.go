@@ -0,0 +1,245 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/graph"
+	"github.com/simon-lentz/yammm/instance"
+)
+
+// Record is one instance read from a [Source].
+type Record struct {
+	// TypeName is the instance's type, in instance tag form.
+	TypeName string
+
+	// Raw is the instance. It is not usable if Result has errors.
+	Raw instance.RawInstance
+
+	// Result holds the issues found reading the instance, such as a syntax
+	// error or a missing type tag.
+	Result diag.Result
+
+	// Offset is the number of input bytes consumed through the end of the
+	// instance, for progress reporting.
+	Offset int64
+}
+
+// Source yields records one at a time.
+//
+// Next returns io.EOF after the last record. Any other error ends the
+// stream. Implementations should return promptly once ctx is done.
+type Source interface {
+	Next(ctx context.Context) (Record, error)
+}
+
+// Stats counts the records processed so far.
+type Stats struct {
+	Read     int64 // records read from the source
+	Unusable int64 // records that could not be read (Record.Result had errors)
+	Invalid  int64 // records that failed validation
+	Added    int64 // instances added to the graph without errors
+	Issues   int64 // issues reported to the issue handler
+	Offset   int64 // input bytes consumed through the last processed record
+}
+
+// Option configures Run.
+type Option func(*config)
+
+type config struct {
+	workers       int
+	buffer        int
+	onIssue       func(diag.Issue)
+	onProgress    func(Stats)
+	progressEvery int64
+}
+
+// WithWorkers sets the number of goroutines validating records concurrently.
+// Default is runtime.GOMAXPROCS(0). Values below 1 are treated as 1.
+func WithWorkers(n int) Option {
+	return func(c *config) {
+		c.workers = max(1, n)
+	}
+}
+
+// WithBuffer sets the maximum number of records in flight between the
+// source and the graph. When the limit is reached, reading pauses until the
+// oldest record has been added, which bounds memory regardless of input
+// size. Default is 256. Values below 1 are treated as 1.
+func WithBuffer(n int) Option {
+	return func(c *config) {
+		c.buffer = max(1, n)
+	}
+}
+
+// WithIssueHandler sets a function called with every issue as it occurs:
+// read errors, validation failures and warnings, and graph diagnostics. It
+// is called from a single goroutine, in input order.
+func WithIssueHandler(fn func(diag.Issue)) Option {
+	return func(c *config) {
+		c.onIssue = fn
+	}
+}
+
+// WithProgress sets a function called after every n processed records and
+// once more when Run finishes. It is called from the same goroutine as the
+// issue handler.
+func WithProgress(n int, fn func(Stats)) Option {
+	return func(c *config) {
+		c.progressEvery = int64(max(1, n))
+		c.onProgress = fn
+	}
+}
+
+// job is a record moving through the pipeline.
+type job struct {
+	rec     Record
+	valid   *instance.ValidInstance
+	failure *instance.ValidationFailure
+	err     error
+	done    chan struct{}
+}
+
+// Run reads every record from src, validates it with v and adds the valid
+// instances to g.
+//
+// Records are validated concurrently but added to the graph in input order,
+// so the result does not depend on scheduling. Issues are reported to the
+// issue handler as each record is processed; they are not accumulated, so
+// memory stays bounded by the buffer size. Run does not call [graph.Graph.Check].
+//
+// Run returns the final stats and the first error from the source, the
+// validator or the graph, or ctx.Err() if ctx is done first. Invalid records
+// are not errors.
+func Run(ctx context.Context, src Source, v *instance.Validator, g *graph.Graph, opts ...Option) (Stats, error) {
+	cfg := &config{
+		workers: runtime.GOMAXPROCS(0),
+		buffer:  256,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// fail records the first error and stops the pipeline.
+	var failOnce sync.Once
+	var runErr error
+	fail := func(err error) {
+		failOnce.Do(func() { runErr = err })
+		cancel()
+	}
+
+	ordered := make(chan *job, cfg.buffer)
+	work := make(chan *job, cfg.buffer)
+	var wg sync.WaitGroup
+
+	// Reader: feeds jobs in input order; blocks when the buffer is full.
+	wg.Go(func() {
+		defer close(ordered)
+		defer close(work)
+		for {
+			rec, err := src.Next(ctx)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				fail(err)
+				return
+			}
+			j := &job{rec: rec, done: make(chan struct{})}
+			select {
+			case ordered <- j:
+			case <-ctx.Done():
+				return
+			}
+			if rec.Result.HasErrors() {
+				close(j.done)
+				continue
+			}
+			select {
+			case work <- j:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	// Workers: validate concurrently.
+	for range cfg.workers {
+		wg.Go(func() {
+			for j := range work {
+				j.valid, j.failure, j.err = v.ValidateOne(ctx, j.rec.TypeName, j.rec.Raw)
+				close(j.done)
+			}
+		})
+	}
+
+	// Adder: consumes jobs in input order on this goroutine.
+	var stats Stats
+	report := func(result diag.Result) {
+		for issue := range result.Issues() {
+			stats.Issues++
+			if cfg.onIssue != nil {
+				cfg.onIssue(issue)
+			}
+		}
+	}
+	for j := range ordered {
+		select {
+		case <-j.done:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		stats.Read++
+		stats.Offset = j.rec.Offset
+		report(j.rec.Result)
+		switch {
+		case j.rec.Result.HasErrors():
+			stats.Unusable++
+		case j.err != nil:
+			fail(j.err)
+		case j.failure != nil:
+			stats.Invalid++
+			report(j.failure.Result)
+		default:
+			report(j.valid.Diagnostics())
+			result, err := g.Add(ctx, j.valid)
+			if err != nil {
+				fail(err)
+				break
+			}
+			report(result)
+			if !result.HasErrors() {
+				stats.Added++
+			}
+		}
+
+		if cfg.onProgress != nil && stats.Read%cfg.progressEvery == 0 {
+			cfg.onProgress(stats)
+		}
+	}
+
+	cancel()
+	for range ordered {
+		// Drain so the reader and workers can exit.
+	}
+	wg.Wait()
+
+	if cfg.onProgress != nil {
+		cfg.onProgress(stats)
+	}
+	if runErr != nil {
+		return stats, runErr
+	}
+	return stats, parent.Err()
+}
@@ -0,0 +1,202 @@
+package ingest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/simon-lentz/yammm/adapter/json"
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/graph"
+	"github.com/simon-lentz/yammm/ingest"
+	"github.com/simon-lentz/yammm/instance"
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/load"
+)
+
+const peopleSchema = `schema "people"
+
+type Person {
+    id String primary
+    name String required
+    age Integer
+}
+`
+
+func loadPeople(t *testing.T) *schema.Schema {
+	t.Helper()
+	s, result, err := load.LoadString(t.Context(), peopleSchema, "people")
+	require.NoError(t, err)
+	require.True(t, result.OK(), "schema errors: %v", result.Messages())
+	return s
+}
+
+// person returns a record for a Person instance.
+func person(id string, props map[string]any) ingest.Record {
+	p := map[string]any{"id": id, "name": "name-" + id}
+	for k, v := range props {
+		p[k] = v
+	}
+	return ingest.Record{TypeName: "Person", Raw: instance.RawInstance{Properties: p}}
+}
+
+// sliceSource yields records from a slice, then err (io.EOF if nil). If
+// endless, it yields fresh valid records forever instead.
+type sliceSource struct {
+	records []ingest.Record
+	err     error
+	endless bool
+	reads   atomic.Int64
+}
+
+func (s *sliceSource) Next(ctx context.Context) (ingest.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return ingest.Record{}, err
+	}
+	n := s.reads.Add(1) - 1
+	if s.endless {
+		return person(fmt.Sprintf("p%d", n), nil), nil
+	}
+	if int(n) < len(s.records) {
+		return s.records[n], nil
+	}
+	if s.err != nil {
+		return ingest.Record{}, s.err
+	}
+	return ingest.Record{}, io.EOF
+}
+
+func TestRun(t *testing.T) {
+	s := loadPeople(t)
+	unreadable := diag.NewCollectorUnlimited()
+	unreadable.Collect(diag.NewIssue(diag.Error, diag.E_ADAPTER_PARSE, "broken record").Build())
+
+	src := &sliceSource{records: []ingest.Record{
+		person("a", nil),
+		person("b", map[string]any{"age": "old"}),
+		{Result: unreadable.Result()},
+		person("c", map[string]any{"age": int64(30)}),
+		person("a", nil),
+		person("d", nil),
+	}}
+	g := graph.New(s)
+
+	var issues []string
+	var progress []ingest.Stats
+	stats, err := ingest.Run(t.Context(), src, instance.NewValidator(s), g,
+		ingest.WithWorkers(4),
+		ingest.WithBuffer(2),
+		ingest.WithIssueHandler(func(issue diag.Issue) { issues = append(issues, issue.Code().String()) }),
+		ingest.WithProgress(2, func(s ingest.Stats) { progress = append(progress, s) }),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, ingest.Stats{Read: 6, Unusable: 1, Invalid: 1, Added: 3, Issues: 3}, stats)
+	// Issues arrive in input order: the type mismatch of b, the broken
+	// record, then the duplicate a.
+	require.Len(t, issues, 3)
+	assert.Equal(t, diag.E_ADAPTER_PARSE.String(), issues[1])
+	assert.Equal(t, []int64{2, 4, 6, 6}, readCounts(progress), "every 2 records and once at the end")
+
+	result := g.Snapshot()
+	assert.Len(t, result.InstancesOf("Person"), 3)
+}
+
+func readCounts(progress []ingest.Stats) []int64 {
+	counts := make([]int64, len(progress))
+	for i, s := range progress {
+		counts[i] = s.Read
+	}
+	return counts
+}
+
+func TestRun_SourceError(t *testing.T) {
+	s := loadPeople(t)
+	boom := errors.New("connection reset")
+	src := &sliceSource{records: []ingest.Record{person("a", nil), person("b", nil)}, err: boom}
+
+	stats, err := ingest.Run(t.Context(), src, instance.NewValidator(s), graph.New(s))
+	require.ErrorIs(t, err, boom)
+	assert.LessOrEqual(t, stats.Read, int64(2))
+}
+
+func TestRun_Cancellation(t *testing.T) {
+	s := loadPeople(t)
+	src := &sliceSource{endless: true}
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	stats, err := ingest.Run(ctx, src, instance.NewValidator(s), graph.New(s),
+		ingest.WithProgress(10, func(s ingest.Stats) {
+			if s.Read >= 50 {
+				cancel()
+			}
+		}),
+	)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.GreaterOrEqual(t, stats.Read, int64(50))
+	assert.Equal(t, stats.Read, stats.Added)
+}
+
+func TestRun_BackPressure(t *testing.T) {
+	s := loadPeople(t)
+	src := &sliceSource{endless: true}
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := ingest.Run(ctx, src, instance.NewValidator(s), graph.New(s),
+			ingest.WithBuffer(3),
+			ingest.WithProgress(1, func(ingest.Stats) { <-release }),
+		)
+		done <- err
+	}()
+
+	// With the graph side blocked after one record, the reader stops once the
+	// buffer is full: one record processed, three buffered, one pending.
+	const limit = 1 + 3 + 1
+	require.Eventually(t, func() bool { return src.reads.Load() == limit }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int64(limit), src.reads.Load())
+
+	cancel()
+	close(release)
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestRun_JSONStream(t *testing.T) {
+	s := loadPeople(t)
+	input := `{"$type":"Person","id":"a","name":"Alice","age":30}
+{"$type":"Person","id":"b","name":"Bob","age":"x"}
+{"$type":"Person","id":"c",
+{"$type":"Person","id":"d","name":"Dave"}
+`
+	adapter, err := json.NewAdapter(nil)
+	require.NoError(t, err)
+	src := adapter.NewStreamReader(location.NewSourceID("test://people.ndjson"), strings.NewReader(input))
+	g := graph.New(s)
+
+	var last ingest.Stats
+	stats, err := ingest.Run(t.Context(), src, instance.NewValidator(s), g,
+		ingest.WithProgress(1, func(s ingest.Stats) { last = s }),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, ingest.Stats{Read: 4, Unusable: 1, Invalid: 1, Added: 2, Issues: 2, Offset: int64(len(input))}, stats)
+	assert.Equal(t, stats, last)
+
+	check, err := g.Check(t.Context())
+	require.NoError(t, err)
+	assert.True(t, check.OK())
+	assert.Len(t, g.Snapshot().InstancesOf("Person"), 2)
+}
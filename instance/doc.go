@@ -29,10 +29,24 @@
 //   - Edge object validation (associations and compositions)
 //   - Invariant expression evaluation
 //
+// [WithSeverityPolicy] changes the severity of validation diagnostics by
+// code. An instance whose errors are all downgraded or suppressed passes
+// validation without the offending values.
+//
 // # Thread Safety
 //
-// [Validator] is stateless and safe for concurrent use. Multiple goroutines
-// may call [Validator.Validate] simultaneously with different inputs.
+// [Validator] is safe for concurrent use. Multiple goroutines may call
+// [Validator.Validate] simultaneously with different inputs.
+// [Validator.ValidateParallel] uses this to split a large batch across
+// goroutines while keeping results in input order.
+//
+// # Validation Plans
+//
+// The per-type work that does not depend on the instance (constraint alias
+// resolution, the case-insensitive name table, relation fields, and the
+// invariant policy) is computed once per type into a plan and cached by the
+// validator. Plans are built lazily on first use; [Validator.Compile] builds
+// them all up front. Plans never change validation results.
 //
 // # Subpackages
 //
@@ -20,6 +20,14 @@ var (
 	// ErrPartTypeDirect indicates a part type was instantiated outside composition.
 	ErrPartTypeDirect = diag.E_PART_TYPE_DIRECT
 
+	// ErrMissingTypeTag indicates a composed child of an abstract part type
+	// does not name its concrete type.
+	ErrMissingTypeTag = diag.E_MISSING_TYPE_TAG
+
+	// ErrInvalidTypeTag indicates a composed child's type tag is not a string
+	// or does not name the composition target or one of its subtypes.
+	ErrInvalidTypeTag = diag.E_INVALID_TYPE_TAG
+
 	// ErrMissingRequired indicates a required property was absent.
 	ErrMissingRequired = diag.E_MISSING_REQUIRED
 
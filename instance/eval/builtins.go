@@ -406,6 +406,8 @@ func builtinLen(_ builtinEvaluator, lhs any, _ []any, _ []string, _ expr.Express
 		return int64(len(v)), nil
 	case immutable.Slice:
 		return int64(v.Len()), nil
+	case immutable.Map[string]:
+		return int64(v.Len()), nil
 	}
 
 	rv := reflect.ValueOf(lhs)
@@ -3,14 +3,18 @@ package eval
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"reflect"
 	"regexp"
 	"slices"
+	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/simon-lentz/yammm/instance/path"
+	"github.com/simon-lentz/yammm/internal/ident"
 	"github.com/simon-lentz/yammm/internal/value"
 	"github.com/simon-lentz/yammm/schema"
 )
@@ -29,6 +33,16 @@ const (
 type CheckError struct {
 	Kind CheckErrorKind
 	Msg  string
+
+	// Path locates the offending nested value (a List element, Map entry or
+	// Object field) relative to the checked value. It is the root path when
+	// the checked value itself is at fault.
+	Path path.Builder
+
+	// Suggestion is the allowed value or field name the offending input was
+	// most likely meant to be (for enum values and unknown Object fields),
+	// or "" if none is close enough.
+	Suggestion string
 }
 
 func (e *CheckError) Error() string { return e.Msg }
@@ -46,12 +60,58 @@ func constraintFail(format string, args ...any) *CheckError {
 // during value classification.
 type Checker struct {
 	registry value.Registry
+	clock    func() time.Time
+	timezone TimezonePolicy
+}
+
+// TimezonePolicy controls which UTC offsets Timestamp values may carry.
+type TimezonePolicy uint8
+
+const (
+	// TimezoneAny accepts any offset, and values without one when the
+	// layout has no zone (the default).
+	TimezoneAny TimezonePolicy = iota
+	// TimezoneRequireOffset requires an explicit UTC offset.
+	TimezoneRequireOffset
+	// TimezoneRequireUTC requires an explicit zero UTC offset.
+	TimezoneRequireUTC
+)
+
+// CheckerOption configures a Checker.
+type CheckerOption func(*Checker)
+
+// WithClock sets the clock that relative Timestamp and Date bounds (now,
+// today) are resolved against. A nil clock selects time.Now.
+func WithClock(now func() time.Time) CheckerOption {
+	return func(ch *Checker) {
+		ch.clock = now
+	}
+}
+
+// WithTimezonePolicy sets the UTC offset policy for Timestamp values.
+func WithTimezonePolicy(policy TimezonePolicy) CheckerOption {
+	return func(ch *Checker) {
+		ch.timezone = policy
+	}
 }
 
 // NewChecker creates a Checker with the given value registry.
-// A zero-value Registry falls back to built-in type detection.
-func NewChecker(reg value.Registry) *Checker {
-	return &Checker{registry: reg}
+// A zero-value Registry falls back to built-in type detection and has no
+// custom types, so values of custom constraints fail to check.
+func NewChecker(reg value.Registry, opts ...CheckerOption) *Checker {
+	ch := &Checker{registry: reg}
+	for _, opt := range opts {
+		opt(ch)
+	}
+	return ch
+}
+
+// now returns the current time of the Checker's clock.
+func (ch *Checker) now() time.Time {
+	if ch.clock == nil {
+		return time.Now()
+	}
+	return ch.clock()
 }
 
 // DefaultChecker returns a Checker using built-in type detection only.
@@ -90,9 +150,9 @@ func (ch *Checker) CheckValue(val any, c schema.Constraint) error {
 	case schema.KindBoolean:
 		return checkBoolean(val)
 	case schema.KindTimestamp:
-		return checkTimestamp(val, c)
+		return ch.checkTimestamp(val, c)
 	case schema.KindDate:
-		return checkDate(val)
+		return ch.checkDate(val, c)
 	case schema.KindUUID:
 		return checkUUID(val)
 	case schema.KindEnum:
@@ -103,6 +163,14 @@ func (ch *Checker) CheckValue(val any, c schema.Constraint) error {
 		return ch.checkVector(val, c)
 	case schema.KindList:
 		return ch.checkList(val, c)
+	case schema.KindMap:
+		return ch.checkMap(val, c)
+	case schema.KindObject:
+		return ch.checkObject(val, c)
+	case schema.KindUnion:
+		return ch.checkUnion(val, c)
+	case schema.KindCustom:
+		return ch.checkCustom(val, c)
 	case schema.KindAlias:
 		// Resolve alias and check against resolved constraint
 		alias, ok := c.(schema.AliasConstraint)
@@ -129,6 +197,9 @@ func (ch *Checker) CheckValue(val any, c schema.Constraint) error {
 //   - Boolean → bool (unchanged)
 //   - String types (String, Timestamp, Date, UUID, Enum, Pattern) → string (unchanged)
 //   - Vector → []float64
+//   - List → []any, Map and Object → map[string]any (with coerced contents)
+//   - Union → the canonical type of the first member the value matches
+//   - Custom → the custom type's canonical value (see schema.CustomType)
 //
 // Returns the coerced value and nil error on success.
 // Returns (nil, error) if coercion fails (should not happen after CheckValue).
@@ -159,6 +230,14 @@ func (ch *Checker) CoerceValue(val any, c schema.Constraint) (any, error) {
 		return ch.coerceVector(val)
 	case schema.KindList:
 		return ch.coerceList(val, c)
+	case schema.KindMap:
+		return ch.coerceMap(val, c)
+	case schema.KindObject:
+		return ch.coerceObject(val, c)
+	case schema.KindUnion:
+		return ch.coerceUnion(val, c)
+	case schema.KindCustom:
+		return ch.coerceCustom(val, c)
 	case schema.KindAlias:
 		// Resolve alias and coerce against resolved constraint
 		alias, ok := c.(schema.AliasConstraint)
@@ -405,50 +484,100 @@ func checkBoolean(val any) error {
 	return typeMismatch("expected boolean, got %T", val)
 }
 
-// checkTimestamp validates that val is a valid timestamp.
-// Accepts time.Time (always valid) or string (parsed against format).
-func checkTimestamp(val any, c schema.Constraint) error {
-	// Accept time.Time directly - always valid
-	if _, ok := val.(time.Time); ok {
-		return nil
-	}
-
-	s, ok := val.(string)
-	if !ok {
+// checkTimestamp validates that val is a valid timestamp within the
+// constraint's bounds. Accepts time.Time or a string parsed with the
+// constraint's layout (RFC 3339 by default).
+func (ch *Checker) checkTimestamp(val any, c schema.Constraint) error {
+	tc, _ := c.(schema.TimestampConstraint)
+
+	var t time.Time
+	hasZone := true
+	switch v := val.(type) {
+	case time.Time:
+		t = v
+	case string:
+		var err error
+		if tc.Format() != "" {
+			if t, err = time.Parse(tc.Format(), v); err != nil {
+				return constraintFail("invalid timestamp format: %s (expected %s)", v, tc.Format())
+			}
+			hasZone = layoutHasZone(tc.Format())
+		} else if t, err = time.Parse(time.RFC3339, v); err != nil {
+			// Also try RFC3339Nano
+			if t, err = time.Parse(time.RFC3339Nano, v); err != nil {
+				return constraintFail("invalid timestamp format: %s", v)
+			}
+		}
+	default:
 		return typeMismatch("expected timestamp string or time.Time, got %T", val)
 	}
 
-	// Check for custom format
-	tc, ok := c.(schema.TimestampConstraint)
-	if ok && tc.Format() != "" {
-		if _, err := time.Parse(tc.Format(), s); err != nil {
-			return constraintFail("invalid timestamp format: %s (expected %s)", s, tc.Format())
+	switch _, offset := t.Zone(); ch.timezone {
+	case TimezoneRequireOffset:
+		if !hasZone {
+			return constraintFail("timestamp %v has no UTC offset", val)
+		}
+	case TimezoneRequireUTC:
+		if !hasZone || offset != 0 {
+			return constraintFail("timestamp %v is not in UTC", val)
 		}
-		return nil
 	}
 
-	// Default: RFC 3339
-	if _, err := time.Parse(time.RFC3339, s); err != nil {
-		// Also try RFC3339Nano
-		if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
-			return constraintFail("invalid timestamp format: %s", s)
-		}
+	if !tc.Min().IsSet() && !tc.Max().IsSet() {
+		return nil
 	}
-	return nil
+	return checkTimeRange(t, ch.now(), tc.Min(), tc.Max(), "timestamp", tc.Layout(), val)
 }
 
-// checkDate validates that val is a valid date string (YYYY-MM-DD).
-func checkDate(val any) error {
+// checkDate validates that val is a valid date string within the
+// constraint's bounds, parsed with the constraint's layout (YYYY-MM-DD by
+// default).
+func (ch *Checker) checkDate(val any, c schema.Constraint) error {
+	dc, _ := c.(schema.DateConstraint)
 	s, ok := val.(string)
 	if !ok {
 		return typeMismatch("expected date string, got %T", val)
 	}
-	if _, err := time.Parse("2006-01-02", s); err != nil {
-		return constraintFail("invalid date format: %s (expected YYYY-MM-DD)", s)
+	d, err := time.Parse(dc.Layout(), s)
+	if err != nil {
+		if dc.Format() == "" {
+			return constraintFail("invalid date format: %s (expected YYYY-MM-DD)", s)
+		}
+		return constraintFail("invalid date format: %s (expected %s)", s, dc.Format())
+	}
+
+	if !dc.Min().IsSet() && !dc.Max().IsSet() {
+		return nil
+	}
+	// Relative date bounds count whole days from today's calendar date.
+	now := ch.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	return checkTimeRange(day, today, dc.Min(), dc.Max(), "date", dc.Layout(), val)
+}
+
+// checkTimeRange checks t against bounds resolved at now. Violations name
+// the resolved bound in layout.
+func checkTimeRange(t, now time.Time, minB, maxB schema.TimeBound, what, layout string, val any) error {
+	if minB.IsSet() {
+		if limit := minB.Resolve(now); t.Before(limit) {
+			return constraintFail("%s %v is before minimum %s", what, val, limit.Format(layout))
+		}
+	}
+	if maxB.IsSet() {
+		if limit := maxB.Resolve(now); t.After(limit) {
+			return constraintFail("%s %v is after maximum %s", what, val, limit.Format(layout))
+		}
 	}
 	return nil
 }
 
+// layoutHasZone reports whether a Go time layout includes a zone element.
+func layoutHasZone(layout string) bool {
+	return strings.Contains(layout, "Z07") || strings.Contains(layout, "-07") ||
+		strings.Contains(layout, "MST")
+}
+
 // checkUUID validates that val is a valid UUID.
 // Accepts uuid.UUID (always valid) or string (parsed as UUID).
 func checkUUID(val any) error {
@@ -483,7 +612,9 @@ func checkEnum(val any, c schema.Constraint) error {
 	if slices.Contains(allowed, s) {
 		return nil
 	}
-	return constraintFail("value %q not in enum %v", s, allowed)
+	err := constraintFail("value %q not in enum %v", s, allowed)
+	err.Suggestion, _ = ident.Suggest(s, allowed)
+	return err
 }
 
 // checkPattern validates that val matches all constraint patterns.
@@ -568,19 +699,49 @@ func (ch *Checker) checkList(val any, c schema.Constraint) error {
 	elemConstraint := lc.Element()
 	for i, elem := range slice {
 		if err := ch.CheckValue(elem, elemConstraint); err != nil {
-			if ce, ok := errors.AsType[*CheckError](err); ok {
-				return &CheckError{
-					Kind: ce.Kind,
-					Msg:  fmt.Sprintf("element [%d]: %s", i, ce.Msg),
-				}
+			return nestedError(err, fmt.Sprintf("element [%d]", i), path.Root().Index(i))
+		}
+	}
+
+	if lc.IsUnique() {
+		if first, dup, found := duplicateElement(slice); found {
+			return &CheckError{
+				Kind: KindConstraintFail,
+				Msg:  fmt.Sprintf("set element [%d] duplicates element [%d]", dup, first),
+				Path: path.Root().Index(dup),
 			}
-			return fmt.Errorf("element [%d]: %w", i, err)
 		}
 	}
 
 	return nil
 }
 
+// duplicateElement finds the earliest repeated element of slice, comparing
+// elements by canonical value order. It returns the index of the value's
+// first occurrence and of its repeat.
+func duplicateElement(slice []any) (first, dup int, found bool) {
+	order := make([]int, len(slice))
+	for i := range order {
+		order[i] = i
+	}
+	// A stable sort keeps equal elements in index order, so each run of
+	// equal elements starts with the first occurrence.
+	slices.SortStableFunc(order, func(a, b int) int {
+		return value.Compare(slice[a], slice[b])
+	})
+	start := 0
+	for k := 1; k < len(order); k++ {
+		if value.Compare(slice[order[k-1]], slice[order[k]]) != 0 {
+			start = k
+			continue
+		}
+		if k == start+1 && (!found || order[k] < dup) {
+			first, dup, found = order[start], order[k], true
+		}
+	}
+	return first, dup, found
+}
+
 // coerceList coerces each element to its canonical type.
 func (ch *Checker) coerceList(val any, c schema.Constraint) (any, error) {
 	slice, ok := toSlice(val)
@@ -606,6 +767,272 @@ func (ch *Checker) coerceList(val any, c schema.Constraint) (any, error) {
 	return result, nil
 }
 
+// nestedError prefixes an error from checking a nested value with its
+// description and, for CheckErrors, prepends its relative path.
+func nestedError(err error, desc string, at path.Builder) error {
+	if ce, ok := errors.AsType[*CheckError](err); ok {
+		return &CheckError{
+			Kind:       ce.Kind,
+			Msg:        desc + ": " + ce.Msg,
+			Path:       at.Join(ce.Path),
+			Suggestion: ce.Suggestion,
+		}
+	}
+	return fmt.Errorf("%s: %w", desc, err)
+}
+
+// checkMap validates that val is a string-keyed map whose keys and values
+// match the key and value constraints. Entries are checked in key order so
+// the reported violation is deterministic.
+func (ch *Checker) checkMap(val any, c schema.Constraint) error {
+	m, ok := toMap(val)
+	if !ok {
+		return typeMismatch("expected object for map, got %T", val)
+	}
+
+	mc, ok := c.(schema.MapConstraint)
+	if !ok {
+		return errors.New("invalid map constraint type")
+	}
+
+	// Check size bounds
+	size := int64(len(m))
+	if minLen, hasMin := mc.MinLen(); hasMin && size < minLen {
+		return constraintFail("map size %d is less than minimum %d", size, minLen)
+	}
+	if maxLen, hasMax := mc.MaxLen(); hasMax && size > maxLen {
+		return constraintFail("map size %d exceeds maximum %d", size, maxLen)
+	}
+
+	// Check each entry
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		desc := fmt.Sprintf("key %q", key)
+		if err := ch.CheckValue(key, mc.Key()); err != nil {
+			return nestedError(err, desc, path.Root().Key(key))
+		}
+		if err := ch.CheckValue(m[key], mc.Value()); err != nil {
+			return nestedError(err, desc, path.Root().Key(key))
+		}
+	}
+
+	return nil
+}
+
+// coerceMap coerces each map value to its canonical type.
+func (ch *Checker) coerceMap(val any, c schema.Constraint) (any, error) {
+	m, ok := toMap(val)
+	if !ok {
+		return nil, fmt.Errorf("expected object for map, got %T", val)
+	}
+
+	mc, ok := c.(schema.MapConstraint)
+	if !ok {
+		return nil, errors.New("invalid map constraint type")
+	}
+
+	result := make(map[string]any, len(m))
+	for key, elem := range m {
+		coerced, err := ch.CoerceValue(elem, mc.Value())
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+		result[key] = coerced
+	}
+
+	return result, nil
+}
+
+// checkObject validates that val is a map with the object's fields: required
+// fields present and non-null, every field matching its constraint, and no
+// undeclared fields. Field names are matched exactly.
+func (ch *Checker) checkObject(val any, c schema.Constraint) error {
+	m, ok := toMap(val)
+	if !ok {
+		return typeMismatch("expected object, got %T", val)
+	}
+
+	oc, ok := c.(schema.ObjectConstraint)
+	if !ok {
+		return errors.New("invalid object constraint type")
+	}
+
+	for _, f := range oc.Fields() {
+		fieldVal, present := m[f.Name()]
+		if fieldVal == nil {
+			if f.IsRequired() {
+				if present {
+					return &CheckError{
+						Kind: KindConstraintFail,
+						Msg:  fmt.Sprintf("required field %q is null", f.Name()),
+						Path: path.Root().Key(f.Name()),
+					}
+				}
+				return constraintFail("missing required field %q", f.Name())
+			}
+			continue
+		}
+		if err := ch.CheckValue(fieldVal, f.Constraint()); err != nil {
+			return nestedError(err, fmt.Sprintf("field %q", f.Name()), path.Root().Key(f.Name()))
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		if _, declared := oc.Field(key); !declared {
+			names := make([]string, 0, len(oc.Fields()))
+			for _, f := range oc.Fields() {
+				names = append(names, f.Name())
+			}
+			suggestion, _ := ident.Suggest(key, names)
+			return &CheckError{
+				Kind:       KindConstraintFail,
+				Msg:        fmt.Sprintf("unknown field %q", key),
+				Path:       path.Root().Key(key),
+				Suggestion: suggestion,
+			}
+		}
+	}
+
+	return nil
+}
+
+// coerceObject coerces each present field to its canonical type.
+func (ch *Checker) coerceObject(val any, c schema.Constraint) (any, error) {
+	m, ok := toMap(val)
+	if !ok {
+		return nil, fmt.Errorf("expected object, got %T", val)
+	}
+
+	oc, ok := c.(schema.ObjectConstraint)
+	if !ok {
+		return nil, errors.New("invalid object constraint type")
+	}
+
+	result := make(map[string]any, len(m))
+	for _, f := range oc.Fields() {
+		fieldVal, present := m[f.Name()]
+		if !present {
+			continue
+		}
+		coerced, err := ch.CoerceValue(fieldVal, f.Constraint())
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", f.Name(), err)
+		}
+		result[f.Name()] = coerced
+	}
+
+	return result, nil
+}
+
+// checkUnion validates that val matches at least one union member. On
+// failure it reports the closest member: the one whose violation lies
+// deepest in the value, preferring constraint failures over type mismatches
+// and earlier members over later ones.
+func (ch *Checker) checkUnion(val any, c schema.Constraint) error {
+	uc, ok := c.(schema.UnionConstraint)
+	if !ok {
+		return errors.New("invalid union constraint type")
+	}
+
+	var closest *CheckError
+	var closestMember schema.Constraint
+	for _, m := range uc.Members() {
+		err := ch.CheckValue(val, m)
+		if err == nil {
+			return nil
+		}
+		ce, ok := errors.AsType[*CheckError](err)
+		if !ok {
+			return err
+		}
+		if closest == nil || closerMatch(ce, closest) {
+			closest, closestMember = ce, m
+		}
+	}
+
+	if closest.Kind == KindTypeMismatch && closest.Path.IsRoot() {
+		return typeMismatch("expected %s, got %T", uc, val)
+	}
+	return &CheckError{
+		Kind:       closest.Kind,
+		Msg:        fmt.Sprintf("does not match %s; closest member %s: %s", uc, closestMember, closest.Msg),
+		Path:       closest.Path,
+		Suggestion: closest.Suggestion,
+	}
+}
+
+// closerMatch reports whether violation a indicates a closer match than b.
+func closerMatch(a, b *CheckError) bool {
+	if a.Path.Len() != b.Path.Len() {
+		return a.Path.Len() > b.Path.Len()
+	}
+	return a.Kind == KindConstraintFail && b.Kind == KindTypeMismatch
+}
+
+// coerceUnion coerces val with the first union member it matches.
+func (ch *Checker) coerceUnion(val any, c schema.Constraint) (any, error) {
+	uc, ok := c.(schema.UnionConstraint)
+	if !ok {
+		return nil, errors.New("invalid union constraint type")
+	}
+	for _, m := range uc.Members() {
+		if ch.CheckValue(val, m) == nil {
+			return ch.CoerceValue(val, m)
+		}
+	}
+	return nil, fmt.Errorf("value %T matches no member of %s", val, uc)
+}
+
+// customType returns the implementation of a custom constraint's type from
+// the Checker's registry.
+func (ch *Checker) customType(c schema.Constraint) (*schema.CustomType, error) {
+	cc, ok := c.(schema.CustomConstraint)
+	if !ok {
+		return nil, errors.New("invalid custom constraint")
+	}
+	def, ok := ch.registry.CustomTypes.Lookup(cc.Name())
+	if !ok {
+		return nil, fmt.Errorf("custom type %s is not registered", cc.Name())
+	}
+	return def, nil
+}
+
+// checkCustom validates a value against a registered Go datatype. Strings
+// are parsed; other values are accepted only if the type defines Check.
+func (ch *Checker) checkCustom(val any, c schema.Constraint) error {
+	def, err := ch.customType(c)
+	if err != nil {
+		return err
+	}
+	if s, ok := val.(string); ok {
+		if _, err := def.Parse(s); err != nil {
+			return constraintFail("invalid %s %q: %v", def.Name, s, err)
+		}
+		return nil
+	}
+	if def.Check == nil {
+		return typeMismatch("expected string for %s, got %T", def.Name, val)
+	}
+	if err := def.Check(val); err != nil {
+		return constraintFail("invalid %s: %v", def.Name, err)
+	}
+	return nil
+}
+
+// coerceCustom converts a checked value to the custom type's canonical value.
+func (ch *Checker) coerceCustom(val any, c schema.Constraint) (any, error) {
+	def, err := ch.customType(c)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := val.(string); ok {
+		return def.Parse(s)
+	}
+	if def.Coerce == nil {
+		return val, nil
+	}
+	return def.Coerce(val)
+}
+
 // toSlice converts val to []any if it's a slice.
 func toSlice(val any) ([]any, bool) {
 	if val == nil {
@@ -626,6 +1053,27 @@ func toSlice(val any) ([]any, bool) {
 	return result, true
 }
 
+// toMap converts val to map[string]any if it's a map with string keys.
+func toMap(val any) (map[string]any, bool) {
+	if val == nil {
+		return nil, false
+	}
+	if m, ok := val.(map[string]any); ok {
+		return m, true
+	}
+	// Use reflection for typed maps
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	result := make(map[string]any, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		result[iter.Key().String()] = iter.Value().Interface()
+	}
+	return result, true
+}
+
 // TypeChecker is a function that checks if a value matches a type.
 // Returns (true, "") if valid, or (false, message) with an error description.
 type TypeChecker func(val any) (bool, string)
@@ -699,22 +1147,11 @@ func IsUUID() TypeChecker {
 }
 
 // IsTimestamp returns a TypeChecker that validates timestamp values.
-// Accepts time.Time (always valid) or string (parsed as RFC3339).
+// Accepts time.Time or a string parsed as RFC3339.
 func IsTimestamp() TypeChecker {
 	return func(val any) (bool, string) {
-		// Accept time.Time directly
-		if _, ok := val.(time.Time); ok {
-			return true, ""
-		}
-
-		s, ok := val.(string)
-		if !ok {
-			return false, fmt.Sprintf("expected timestamp string or time.Time, got %T", val)
-		}
-		if _, err := time.Parse(time.RFC3339, s); err != nil {
-			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
-				return false, "invalid timestamp format: " + s
-			}
+		if err := DefaultChecker().checkTimestamp(val, schema.NewTimestampConstraint()); err != nil {
+			return false, err.Error()
 		}
 		return true, ""
 	}
@@ -723,7 +1160,7 @@ func IsTimestamp() TypeChecker {
 // IsDate returns a TypeChecker that validates date values.
 func IsDate() TypeChecker {
 	return func(val any) (bool, string) {
-		if err := checkDate(val); err != nil {
+		if err := DefaultChecker().checkDate(val, schema.NewDateConstraint()); err != nil {
 			return false, err.Error()
 		}
 		return true, ""
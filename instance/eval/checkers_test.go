@@ -1,9 +1,11 @@
 package eval_test
 
 import (
+	"errors"
 	"math"
 	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -445,6 +447,84 @@ func TestCheckValue_Date(t *testing.T) {
 	}
 }
 
+func TestCheckValue_TimeBounds(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	checker := eval.NewChecker(value.Registry{}, eval.WithClock(func() time.Time { return now }))
+	none := schema.NoTimeBound()
+	since2020 := schema.TimeBoundAt(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name    string
+		c       schema.Constraint
+		val     any
+		wantMsg string
+	}{
+		{"past ok", schema.NewTimestampConstraintBounded("", none, schema.TimeBoundRelative(0)), "2026-06-01T11:59:59Z", ""},
+		{"future fails", schema.NewTimestampConstraintBounded("", none, schema.TimeBoundRelative(0)), "2026-06-01T12:00:01Z", "is after maximum 2026-06-01T12:00:00Z"},
+		{"time.Time checked", schema.NewTimestampConstraintBounded("", none, schema.TimeBoundRelative(0)), now.Add(time.Minute), "is after maximum"},
+		{"offset compared as instant", schema.NewTimestampConstraintBounded("", none, schema.TimeBoundRelative(0)), "2026-06-01T13:30:00+02:00", ""},
+		{"fixed min", schema.NewTimestampConstraintBounded("", since2020, none), "2019-12-31T23:59:59Z", "is before minimum 2020-01-01T00:00:00Z"},
+		{"relative window", schema.NewTimestampConstraintBounded("", schema.TimeBoundRelative(-time.Hour), none), "2026-06-01T10:00:00Z", "is before minimum"},
+		{"layout drives bounds", schema.NewTimestampConstraintBounded("2006-01-02 15:04", since2020, none), "2019-06-01 08:00", "is before minimum 2020-01-01 00:00"},
+		{"date today ok", schema.NewDateConstraintBounded("", none, schema.TimeBoundRelative(0)), "2026-06-01", ""},
+		{"date tomorrow fails", schema.NewDateConstraintBounded("", none, schema.TimeBoundRelative(0)), "2026-06-02", "date 2026-06-02 is after maximum 2026-06-01"},
+		{"date window", schema.NewDateConstraintBounded("", schema.TimeBoundRelative(-30*24*time.Hour), none), "2026-05-01", "is before minimum 2026-05-02"},
+		{"date fixed min", schema.NewDateConstraintBounded("", since2020, none), "2020-01-01", ""},
+		{"date layout", schema.NewDateConstraintBounded("02.01.2006", since2020, none), "31.12.2019", "is before minimum 01.01.2020"},
+		{"date layout mismatch", schema.NewDateConstraintFormatted("02.01.2006"), "2020-01-01", "expected 02.01.2006"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checker.CheckValue(tt.val, tt.c)
+			if tt.wantMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var ce *eval.CheckError
+			require.ErrorAs(t, err, &ce)
+			assert.Equal(t, eval.KindConstraintFail, ce.Kind)
+			assert.Contains(t, ce.Msg, tt.wantMsg)
+		})
+	}
+}
+
+func TestCheckValue_TimezonePolicy(t *testing.T) {
+	utc := eval.NewChecker(value.Registry{}, eval.WithTimezonePolicy(eval.TimezoneRequireUTC))
+	offset := eval.NewChecker(value.Registry{}, eval.WithTimezonePolicy(eval.TimezoneRequireOffset))
+	rfc := schema.NewTimestampConstraint()
+	local := schema.NewTimestampConstraintFormatted("2006-01-02 15:04")
+	zoned := schema.NewTimestampConstraintFormatted("2006-01-02 15:04 -0700")
+
+	tests := []struct {
+		name    string
+		checker *eval.Checker
+		c       schema.Constraint
+		val     any
+		wantErr bool
+	}{
+		{"utc accepts Z", utc, rfc, "2024-01-15T10:30:00Z", false},
+		{"utc accepts +00:00", utc, rfc, "2024-01-15T10:30:00+00:00", false},
+		{"utc rejects offset", utc, rfc, "2024-01-15T10:30:00+02:00", true},
+		{"utc rejects zoneless layout", utc, local, "2024-01-15 10:30", true},
+		{"utc rejects non-UTC time.Time", utc, rfc, time.Date(2024, 1, 15, 10, 30, 0, 0, time.FixedZone("CET", 3600)), true},
+		{"utc accepts UTC time.Time", utc, rfc, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), false},
+		{"offset accepts offset", offset, rfc, "2024-01-15T10:30:00+02:00", false},
+		{"offset accepts zoned layout", offset, zoned, "2024-01-15 10:30 +0200", false},
+		{"offset rejects zoneless layout", offset, local, "2024-01-15 10:30", true},
+		{"default accepts zoneless layout", eval.DefaultChecker(), local, "2024-01-15 10:30", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.checker.CheckValue(tt.val, tt.c)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestCheckValue_UUID(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -495,6 +575,35 @@ func TestCheckValue_Enum(t *testing.T) {
 	}
 }
 
+func TestCheckValue_Suggestion(t *testing.T) {
+	enum := schema.NewEnumConstraint([]string{"red", "green", "blue"})
+	point := schema.NewObjectConstraint([]schema.ObjectField{
+		schema.NewObjectField("lat", schema.NewFloatConstraint(), true),
+		schema.NewObjectField("lon", schema.NewFloatConstraint(), true),
+	})
+
+	tests := []struct {
+		name       string
+		val        any
+		constraint schema.Constraint
+		want       string
+	}{
+		{"enum case", "RED", enum, "red"},
+		{"enum typo", "gren", enum, "green"},
+		{"enum nothing close", "yellow", enum, ""},
+		{"object field", map[string]any{"lat": 1.0, "lon": 2.0, "latt": 3.0}, point, "lat"},
+		{"nested in list", []any{"blue", "bleu"}, schema.NewListConstraint(enum), "blue"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ce, ok := errors.AsType[*eval.CheckError](eval.CheckValue(tt.val, tt.constraint))
+			require.True(t, ok, "expected CheckError")
+			assert.Equal(t, tt.want, ce.Suggestion)
+		})
+	}
+}
+
 func TestCheckValue_Pattern(t *testing.T) {
 	pattern := regexp.MustCompile(`^[A-Z]{2}-\d{4}$`)
 	constraint := schema.NewPatternConstraint([]*regexp.Regexp{pattern})
@@ -1538,3 +1647,277 @@ func TestCoerceValue_List(t *testing.T) {
 		assert.Contains(t, err.Error(), "element [0]")
 	})
 }
+
+func TestCheckValue_Custom(t *testing.T) {
+	ticketType := schema.CustomType{
+		Name: "Ticket",
+		Parse: func(s string) (any, error) {
+			if !regexp.MustCompile(`^[A-Za-z]+-\d+$`).MatchString(s) {
+				return nil, assert.AnError
+			}
+			return s, nil
+		},
+		Check: func(v any) error {
+			if _, ok := v.(int64); !ok {
+				return assert.AnError
+			}
+			return nil
+		},
+	}
+	codeType := schema.CustomType{
+		Name:  "Code",
+		Parse: func(s string) (any, error) { return s, nil },
+	}
+	types := schema.NewCustomTypeRegistry()
+	types.MustRegister(ticketType)
+	types.MustRegister(codeType)
+	checker := eval.NewChecker(value.Registry{CustomTypes: types})
+	ticket := schema.NewCustomConstraint(&ticketType)
+	stringsOnly := schema.NewCustomConstraint(&codeType)
+
+	tests := []struct {
+		name       string
+		val        any
+		constraint schema.Constraint
+		wantErr    bool
+	}{
+		{"valid_string", "abc-12", ticket, false},
+		{"invalid_string", "abc", ticket, true},
+		{"checked_non_string", int64(7), ticket, false},
+		{"rejected_non_string", 1.5, ticket, true},
+		{"non_string_without_check", 42, stringsOnly, true},
+		{"via_alias", "abc-12", schema.NewAliasConstraint("Key", ticket), false},
+		{"list_element", []any{"a-1", "b"}, schema.NewListConstraint(ticket), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checker.CheckValue(tt.val, tt.constraint)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	err := checker.CheckValue("abc", ticket)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid Ticket "abc"`)
+
+	// Types are resolved through the Checker's registry, not the constraint.
+	err = eval.CheckValue("abc-12", ticket)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "custom type Ticket is not registered")
+}
+
+func TestCoerceValue_Custom(t *testing.T) {
+	upperType := schema.CustomType{
+		Name:  "Upper",
+		Parse: func(s string) (any, error) { return strings.ToUpper(s), nil },
+	}
+	types := schema.NewCustomTypeRegistry()
+	types.MustRegister(upperType)
+	checker := eval.NewChecker(value.Registry{CustomTypes: types})
+	upper := schema.NewCustomConstraint(&upperType)
+
+	got, err := checker.CoerceValue("abc", upper)
+	require.NoError(t, err)
+	assert.Equal(t, "ABC", got)
+
+	got, err = checker.CoerceValue(int64(3), upper)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got, "non-string values are unchanged without Coerce")
+}
+
+func TestCheckValue_Map(t *testing.T) {
+	scores := schema.NewMapConstraintBounded(
+		schema.NewEnumConstraint([]string{"math", "art"}),
+		schema.NewIntegerConstraintBounded(0, true, 0, false),
+		-1, 2,
+	)
+
+	tests := []struct {
+		name     string
+		val      any
+		wantKind eval.CheckErrorKind
+		wantPath string
+		wantMsg  string
+	}{
+		{"valid", map[string]any{"math": 3, "art": int64(1)}, 0, "", ""},
+		{"typed map", map[string]int{"math": 3}, 0, "", ""},
+		{"not a map", []any{1}, eval.KindTypeMismatch, "$", "expected object for map"},
+		{"integer keys", map[int]any{1: 1}, eval.KindTypeMismatch, "$", "expected object for map"},
+		{"too many entries", map[string]any{"math": 1, "art": 1, "x": 1}, eval.KindConstraintFail, "$", "map size 3 exceeds maximum 2"},
+		{"bad key", map[string]any{"music": 1}, eval.KindConstraintFail, "$.music", `key "music": `},
+		{"bad value", map[string]any{"art": -1}, eval.KindConstraintFail, "$.art", `key "art": integer -1`},
+		{"wrong value type", map[string]any{"art": "x"}, eval.KindTypeMismatch, "$.art", `key "art": `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := eval.CheckValue(tt.val, scores)
+			if tt.wantMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			ce, ok := errors.AsType[*eval.CheckError](err)
+			require.True(t, ok, "expected CheckError, got %v", err)
+			assert.Equal(t, tt.wantKind, ce.Kind)
+			assert.Equal(t, tt.wantPath, ce.Path.String())
+			assert.Contains(t, ce.Msg, tt.wantMsg)
+		})
+	}
+}
+
+func TestCheckValue_Set(t *testing.T) {
+	tags := schema.NewSetConstraintBounded(schema.NewStringConstraint(), -1, 3)
+	numbers := schema.NewSetConstraint(schema.NewFloatConstraint())
+	pairs := schema.NewSetConstraint(schema.NewListConstraint(schema.NewIntegerConstraint()))
+
+	tests := []struct {
+		name     string
+		c        schema.Constraint
+		val      any
+		wantKind eval.CheckErrorKind
+		wantPath string
+		wantMsg  string
+	}{
+		{"unique", tags, []any{"b", "a"}, 0, "", ""},
+		{"empty", tags, []any{}, 0, "", ""},
+		{"duplicate", tags, []any{"a", "b", "a"}, eval.KindConstraintFail, "$[2]", "set element [2] duplicates element [0]"},
+		{"earliest duplicate reported", numbers, []any{2.0, 1.0, 1.0, 2.0}, eval.KindConstraintFail, "$[2]", "set element [2] duplicates element [1]"},
+		{"too many", tags, []any{"a", "b", "c", "d"}, eval.KindConstraintFail, "$", "list length 4 exceeds maximum 3"},
+		{"numbers compare by value", numbers, []any{int64(1), 2.5, float64(1)}, eval.KindConstraintFail, "$[2]", "duplicates element [0]"},
+		{"nested lists compare by value", pairs, []any{[]any{1, 2}, []any{2, 1}, []any{int64(1), int64(2)}}, eval.KindConstraintFail, "$[2]", "duplicates element [0]"},
+		{"element checked first", tags, []any{"a", 1, "a"}, eval.KindTypeMismatch, "$[1]", "element [1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := eval.CheckValue(tt.val, tt.c)
+			if tt.wantMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			ce, ok := errors.AsType[*eval.CheckError](err)
+			require.True(t, ok, "expected CheckError, got %v", err)
+			assert.Equal(t, tt.wantKind, ce.Kind)
+			assert.Equal(t, tt.wantPath, ce.Path.String())
+			assert.Contains(t, ce.Msg, tt.wantMsg)
+		})
+	}
+}
+
+func TestCheckValue_Object(t *testing.T) {
+	point := schema.NewObjectConstraint([]schema.ObjectField{
+		schema.NewObjectField("lat", schema.NewFloatConstraintBounded(-90, true, 90, true), true),
+		schema.NewObjectField("lon", schema.NewFloatConstraint(), true),
+		schema.NewObjectField("label", schema.NewStringConstraint(), false),
+	})
+	route := schema.NewListConstraint(point)
+
+	tests := []struct {
+		name       string
+		val        any
+		constraint schema.Constraint
+		wantPath   string
+		wantMsg    string
+	}{
+		{"valid", map[string]any{"lat": 1.5, "lon": 2}, point, "", ""},
+		{"optional null", map[string]any{"lat": 1.5, "lon": 2, "label": nil}, point, "", ""},
+		{"missing required", map[string]any{"lat": 1.5}, point, "$", `missing required field "lon"`},
+		{"null required", map[string]any{"lat": 1.5, "lon": nil}, point, "$.lon", `required field "lon" is null`},
+		{"unknown field", map[string]any{"lat": 1.5, "lon": 2, "alt": 3}, point, "$.alt", `unknown field "alt"`},
+		{"field violation", map[string]any{"lat": 91, "lon": 2}, point, "$.lat", `field "lat": `},
+		{"nested in list", []any{map[string]any{"lat": 1, "lon": 2}, map[string]any{"lat": 1, "lon": "x"}}, route,
+			"$[1].lon", `element [1]: field "lon": `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := eval.CheckValue(tt.val, tt.constraint)
+			if tt.wantMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			ce, ok := errors.AsType[*eval.CheckError](err)
+			require.True(t, ok, "expected CheckError, got %v", err)
+			assert.Equal(t, tt.wantPath, ce.Path.String())
+			assert.Contains(t, ce.Msg, tt.wantMsg)
+		})
+	}
+}
+
+func TestCoerceValue_Structured(t *testing.T) {
+	point := schema.NewObjectConstraint([]schema.ObjectField{
+		schema.NewObjectField("lat", schema.NewFloatConstraint(), true),
+		schema.NewObjectField("count", schema.NewIntegerConstraint(), false),
+	})
+	byName := schema.NewMapConstraint(schema.NewStringConstraint(), point)
+
+	got, err := eval.CoerceValue(map[string]any{
+		"home": map[string]any{"lat": 1, "count": 2.0},
+	}, byName)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"home": map[string]any{"lat": float64(1), "count": int64(2)},
+	}, got)
+}
+
+func TestCheckValue_Union(t *testing.T) {
+	card := schema.NewAliasConstraint("Card", schema.NewObjectConstraint([]schema.ObjectField{
+		schema.NewObjectField("number", schema.NewPatternConstraint([]*regexp.Regexp{regexp.MustCompile(`^\d{16}$`)}), true),
+	}))
+	bank := schema.NewAliasConstraint("Bank", schema.NewObjectConstraint([]schema.ObjectField{
+		schema.NewObjectField("iban", schema.NewStringConstraint(), true),
+		schema.NewObjectField("bic", schema.NewStringConstraintBounded(8, 11), false),
+	}))
+	id := schema.NewUnionConstraint(schema.NewStringConstraintBounded(1, 8), schema.NewIntegerConstraint())
+	payment := schema.NewUnionConstraint(card, bank)
+
+	tests := []struct {
+		name       string
+		val        any
+		constraint schema.Constraint
+		wantKind   eval.CheckErrorKind
+		wantPath   string
+		wantMsg    string
+	}{
+		{"first member", "abc", id, 0, "", ""},
+		{"second member", 42, id, 0, "", ""},
+		{"no member type", true, id, eval.KindTypeMismatch, "$", "expected String[1, 8] | Integer, got bool"},
+		{"closest by kind", "abcdefghij", id, eval.KindConstraintFail, "$",
+			"does not match String[1, 8] | Integer; closest member String[1, 8]: "},
+		{"object member", map[string]any{"iban": "DE00"}, payment, 0, "", ""},
+		{"closest by depth", map[string]any{"iban": "DE00", "bic": "X"}, payment, eval.KindConstraintFail, "$.bic",
+			`closest member Bank: field "bic": `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := eval.CheckValue(tt.val, tt.constraint)
+			if tt.wantMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			ce, ok := errors.AsType[*eval.CheckError](err)
+			require.True(t, ok, "expected CheckError, got %v", err)
+			assert.Equal(t, tt.wantKind, ce.Kind)
+			assert.Equal(t, tt.wantPath, ce.Path.String())
+			assert.Contains(t, ce.Msg, tt.wantMsg)
+		})
+	}
+}
+
+func TestCoerceValue_Union(t *testing.T) {
+	c := schema.NewUnionConstraint(schema.NewStringConstraint(), schema.NewIntegerConstraint())
+
+	got, err := eval.CoerceValue(int32(7), c)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), got)
+
+	got, err = eval.CoerceValue("7", c)
+	require.NoError(t, err)
+	assert.Equal(t, "7", got)
+}
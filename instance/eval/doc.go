@@ -39,13 +39,30 @@
 //   - Control flow: then, lest, with
 //   - Pattern matching: match
 //
+// # User-Defined Functions
+//
+// Domain functions are registered in a [FunctionRegistry] (or individually
+// with [WithBuiltin]) and passed to [NewEvaluator] via [WithFunctions]:
+//
+//	fns := eval.NewFunctionRegistry()
+//	fns.MustRegister("Luhn", 0, 0, 0, func(call eval.Call) (any, error) {
+//	    s, _ := call.Receiver.(string)
+//	    return luhnValid(s), nil
+//	})
+//	evaluator := eval.NewEvaluator(eval.WithFunctions(fns))
+//
+// They are called like the builtins (`card -> Luhn`) and cannot shadow them.
+// instance.WithFunctions passes a registry to the validator, and
+// load.WithBuiltins(fns.Builtins()) makes schema loading warn about calls
+// to functions that are neither built in nor registered.
+//
 // # Configuration
 //
-// The evaluator accepts minimal configuration via [NewEvaluator] options.
-// Currently only [WithLogger] is available for debug observability. The
-// evaluator's behavior is primarily determined by the schema's expression
-// definitions rather than runtime configuration. Future options may include
-// custom function registration or error recovery strategies.
+// The evaluator accepts minimal configuration via [NewEvaluator] options:
+// [WithLogger] for debug observability and [WithFunctions] / [WithBuiltin]
+// for user-defined functions. The evaluator's behavior is primarily
+// determined by the schema's expression definitions rather than runtime
+// configuration.
 //
 // # Thread Safety
 //
@@ -81,7 +81,12 @@ func (e *Evaluator) evaluate(expression expr.Expression, scope Scope) (any, erro
 // evalSExpr evaluates an S-expression.
 func (e *Evaluator) evalSExpr(sexpr expr.SExpr, scope Scope) (any, error) {
 	op := sexpr.Op()
-	children := sexpr.Children()
+	// Operands are only read, so slice them directly rather than through
+	// Children, which copies on every evaluation.
+	var children []expr.Expression
+	if len(sexpr) > 1 {
+		children = sexpr[1:len(sexpr):len(sexpr)]
+	}
 
 	trace.Debug(context.Background(), e.cfg.logger, "evaluating s-expression",
 		slog.String("op", op),
@@ -108,7 +113,7 @@ func (e *Evaluator) evalSExpr(sexpr expr.SExpr, scope Scope) (any, error) {
 	}
 
 	// Check if it's a builtin
-	if def, ok := lookupBuiltin(strings.ToLower(op)); ok {
+	if def, ok := e.lookupFunction(strings.ToLower(op)); ok {
 		return e.evalBuiltin(def, children, scope)
 	}
 
@@ -305,7 +310,7 @@ func (e *Evaluator) evalMember(children []expr.Expression, scope Scope) (any, er
 	}
 
 	// Check if it's a builtin method call (no extra args/body needed)
-	if def, found := lookupBuiltin(strings.ToLower(memberName)); found {
+	if def, found := e.lookupFunction(strings.ToLower(memberName)); found {
 		// Use unified validation - validates that no args/params/body are required
 		return e.callBuiltin(def, obj, nil, nil, nil, scope)
 	}
@@ -316,7 +321,7 @@ func (e *Evaluator) evalMember(children []expr.Expression, scope Scope) (any, er
 
 func (e *Evaluator) evalMethodCall(receiver any, methodName string, rest []expr.Expression, scope Scope) (any, error) {
 	// Look up the method as a builtin
-	def, ok := lookupBuiltin(strings.ToLower(methodName))
+	def, ok := e.lookupFunction(strings.ToLower(methodName))
 	if !ok {
 		return nil, fmt.Errorf("unknown method: %s", methodName)
 	}
@@ -439,6 +444,19 @@ func (e *Evaluator) accessIndex(obj, idx any) (any, error) {
 		return nil, nil //nolint:nilnil // indexing nil returns nil
 	}
 
+	// Handle string keys on Map and Object values (exact match)
+	if key, ok := idx.(string); ok {
+		switch m := obj.(type) {
+		case map[string]any:
+			return m[key], nil
+		case immutable.Map[string]:
+			if val, exists := m.Get(key); exists {
+				return val.Unwrap(), nil
+			}
+			return nil, nil //nolint:nilnil // missing key returns nil
+		}
+	}
+
 	// Get index as int64
 	i, ok := value.GetInt64(idx)
 	if !ok {
@@ -453,6 +471,14 @@ func (e *Evaluator) accessIndex(obj, idx any) (any, error) {
 		return slice[int(i)], nil
 	}
 
+	// Handle immutable.Slice (List-typed property values)
+	if slice, ok := obj.(immutable.Slice); ok {
+		if i < 0 || i >= int64(slice.Len()) {
+			return nil, nil //nolint:nilnil // out of bounds returns nil
+		}
+		return slice.Get(int(i)).Unwrap(), nil
+	}
+
 	// Handle string - index by rune, not byte (per SPEC)
 	// SPEC line 687 states string length is "counted in runes, not bytes"
 	if s, ok := obj.(string); ok {
@@ -480,6 +506,15 @@ func (e *Evaluator) evalList(children []expr.Expression, scope Scope) (any, erro
 
 // --- Builtins ---
 
+// lookupFunction returns the builtin or user-defined function for a
+// lowercase name. Builtins cannot be shadowed.
+func (e *Evaluator) lookupFunction(name string) (builtinDef, bool) {
+	if def, ok := lookupBuiltin(name); ok {
+		return def, true
+	}
+	return e.cfg.functions.lookup(name)
+}
+
 // callBuiltin validates builtin constraints and invokes the function.
 // This is the single internal helper that enforces minArgs, maxArgs, maxParams,
 // and acceptBody requirements for ALL builtin call paths (function-style,
@@ -711,6 +746,11 @@ func (e *Evaluator) equal(args []any) (any, error) {
 	if len(args) != 2 {
 		return nil, errors.New("== requires 2 operands")
 	}
+	if args[0] == nil || args[1] == nil {
+		// nil equals only nil, including for Map and Object values,
+		// which have no ordering.
+		return args[0] == nil && args[1] == nil, nil
+	}
 	cmp, err := value.ValueOrder(args[0], args[1])
 	if err != nil {
 		return nil, fmt.Errorf("== comparison error: %w", err)
@@ -722,6 +762,11 @@ func (e *Evaluator) notEqual(args []any) (any, error) {
 	if len(args) != 2 {
 		return nil, errors.New("!= requires 2 operands")
 	}
+	if args[0] == nil || args[1] == nil {
+		// nil equals only nil, including for Map and Object values,
+		// which have no ordering.
+		return (args[0] == nil) != (args[1] == nil), nil
+	}
 	cmp, err := value.ValueOrder(args[0], args[1])
 	if err != nil {
 		return nil, fmt.Errorf("!= comparison error: %w", err)
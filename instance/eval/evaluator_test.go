@@ -2266,3 +2266,32 @@ func TestEvaluator_ArithmeticErrors(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestEvaluator_StructuredValueAccess(t *testing.T) {
+	ev := eval.NewEvaluator()
+	scope := eval.PropertyScopeFromMap(map[string]any{
+		"location": map[string]any{"lat": 52.5, "lon": 13.4},
+		"scores":   map[string]any{"math": int64(3)},
+		"stops":    []any{map[string]any{"name": "home"}},
+	})
+
+	tests := []struct {
+		src  string
+		want any
+	}{
+		{"location.lat", 52.5},
+		{`scores["math"]`, int64(3)},
+		{`scores["art"]`, nil},
+		{`stops[0].name`, "home"},
+		{"scores -> Len", int64(1)},
+		{"location != nil", true},
+		{"location == nil", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got, err := ev.Evaluate(compileExpr(t, tt.src), scope)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
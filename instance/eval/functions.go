@@ -0,0 +1,180 @@
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/simon-lentz/yammm/schema/expr"
+)
+
+// Function is the implementation of a user-defined builtin function.
+//
+// It is invoked for method-style calls such as `cardNumber -> Luhn` or
+// `code -> InCodeList("iso3166")`. Argument and lambda counts have already
+// been validated against the registration when the function runs.
+type Function func(call Call) (any, error)
+
+// Call describes a single invocation of a user-defined [Function].
+type Call struct {
+	// Name is the registered function name.
+	Name string
+
+	// Receiver is the value to the left of the arrow (nil if absent).
+	// List-typed properties arrive as immutable.Slice; use
+	// [Call.ReceiverSlice] to iterate collections uniformly.
+	Receiver any
+
+	// Args are the evaluated positional arguments.
+	Args []any
+
+	params []string
+	body   expr.Expression
+	scope  Scope
+	ev     builtinEvaluator
+}
+
+// HasBody reports whether the call was given a lambda body.
+func (c Call) HasBody() bool {
+	return c.body != nil
+}
+
+// ReceiverSlice returns the receiver as a slice, accepting the same
+// collection shapes as the collection builtins. A nil receiver is empty.
+func (c Call) ReceiverSlice() ([]any, error) {
+	return asSlice(c.Name, c.Receiver)
+}
+
+// Apply evaluates the lambda body with its parameters bound to values.
+// Parameters the caller did not name are bound as $0, $1, ... like the
+// collection builtins. Returns an error if the call has no body.
+func (c Call) Apply(values ...any) (any, error) {
+	if c.body == nil {
+		return nil, fmt.Errorf("%s requires a lambda expression", c.Name)
+	}
+	scope := c.scope
+	for i, v := range values {
+		name := strconv.Itoa(i)
+		if i < len(c.params) {
+			name = c.params[i]
+		}
+		scope = scope.WithVar(name, v)
+	}
+	return c.ev.evaluate(c.body, scope)
+}
+
+// functionName matches names that can appear in a method-style call.
+var functionName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// reservedOps are word operators that share the function-call namespace.
+var reservedOps = []string{"p", "in"}
+
+// FunctionRegistry holds user-defined builtin functions for an [Evaluator].
+//
+// Thread Safety: FunctionRegistry is not safe for concurrent mutation. Build
+// the registry during initialization, then pass it to [WithFunctions]; the
+// evaluator copies the definitions, so later changes do not affect it.
+type FunctionRegistry struct {
+	defs map[string]builtinDef // keyed by lowercase name
+}
+
+// NewFunctionRegistry creates an empty function registry.
+func NewFunctionRegistry() *FunctionRegistry {
+	return &FunctionRegistry{defs: make(map[string]builtinDef)}
+}
+
+// Register adds a user-defined function.
+//
+// minArgs and maxArgs bound the number of positional arguments (maxArgs -1
+// means unlimited). params is the maximum number of lambda parameters; a
+// function with params > 0 requires a lambda body, which it evaluates with
+// [Call.Apply].
+//
+// Names are matched case-insensitively, like the built-in functions.
+// Returns an error if the name is not an identifier, collides with a
+// built-in function or operator, or is already registered.
+func (r *FunctionRegistry) Register(name string, minArgs, maxArgs, params int, fn Function) error {
+	if r == nil {
+		return errors.New("nil registry")
+	}
+	if !functionName.MatchString(name) {
+		return fmt.Errorf("invalid function name %q", name)
+	}
+	key := strings.ToLower(name)
+	if _, exists := lookupBuiltin(key); exists || slices.Contains(reservedOps, key) {
+		return fmt.Errorf("function %q is a builtin", name)
+	}
+	if _, exists := r.defs[key]; exists {
+		return fmt.Errorf("function already registered: %q", name)
+	}
+	if fn == nil {
+		return fmt.Errorf("function %q has no implementation", name)
+	}
+	if minArgs < 0 || (maxArgs >= 0 && maxArgs < minArgs) || params < 0 {
+		return fmt.Errorf("function %q has invalid arity", name)
+	}
+
+	r.defs[key] = builtinDef{
+		name:       name,
+		minArgs:    minArgs,
+		maxArgs:    maxArgs,
+		maxParams:  params,
+		acceptBody: params > 0,
+		fn: func(ev builtinEvaluator, lhs any, args []any, params []string, body expr.Expression, scope Scope) (any, error) {
+			return fn(Call{
+				Name:     name,
+				Receiver: lhs,
+				Args:     args,
+				params:   params,
+				body:     body,
+				scope:    scope,
+				ev:       ev,
+			})
+		},
+	}
+	return nil
+}
+
+// MustRegister adds a user-defined function.
+// Panics if [FunctionRegistry.Register] would return an error.
+func (r *FunctionRegistry) MustRegister(name string, minArgs, maxArgs, params int, fn Function) {
+	if err := r.Register(name, minArgs, maxArgs, params, fn); err != nil {
+		panic(err)
+	}
+}
+
+// Names returns the registered function names in sorted order.
+func (r *FunctionRegistry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.defs))
+	for _, def := range r.defs {
+		names = append(names, def.name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Builtins returns an [expr.BuiltinRegistry] containing the default builtins
+// and every registered function, for load-time validation of function names
+// (see load.WithBuiltins).
+func (r *FunctionRegistry) Builtins() *expr.BuiltinRegistry {
+	reg := expr.NewBuiltinRegistry()
+	for _, name := range r.Names() {
+		reg.MustRegister(name)
+	}
+	return reg
+}
+
+// lookup returns the registered definition for a lowercase name.
+func (r *FunctionRegistry) lookup(name string) (builtinDef, bool) {
+	if r == nil {
+		return builtinDef{}, false
+	}
+	def, ok := r.defs[name]
+	return def, ok
+}
@@ -0,0 +1,168 @@
+package eval_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/instance/eval"
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema/expr"
+)
+
+func compileExpr(t *testing.T, src string) expr.Expression {
+	t.Helper()
+	collector := diag.NewCollector(0)
+	e := expr.CompileString(src, collector, location.MustNewSourceID("test://functions.yammm"))
+	require.False(t, collector.HasErrors(), "compile %q: %v", src, collector.Result().Messages())
+	return e
+}
+
+// luhn reports whether the receiver is a digit string passing the Luhn check.
+func luhn(call eval.Call) (any, error) {
+	s, ok := call.Receiver.(string)
+	if !ok {
+		return nil, errors.New("Luhn expects a string")
+	}
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if d < 0 || d > 9 {
+			return false, nil
+		}
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0, nil
+}
+
+func TestFunctionRegistry_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	fns := eval.NewFunctionRegistry()
+	fns.MustRegister("Luhn", 0, 0, 0, luhn)
+	fns.MustRegister("InCodeList", 1, 1, 0, func(call eval.Call) (any, error) {
+		list, _ := call.Args[0].(string)
+		return list == "iso3166" && call.Receiver == "DE", nil
+	})
+	fns.MustRegister("CountWhere", 0, 0, 1, func(call eval.Call) (any, error) {
+		items, err := call.ReceiverSlice()
+		if err != nil {
+			return nil, err
+		}
+		n := int64(0)
+		for _, item := range items {
+			ok, err := call.Apply(item)
+			if err != nil {
+				return nil, err
+			}
+			if ok == true {
+				n++
+			}
+		}
+		return n, nil
+	})
+
+	ev := eval.NewEvaluator(eval.WithFunctions(fns))
+	scope := eval.PropertyScopeFromMap(map[string]any{
+		"card":    "79927398713",
+		"country": "DE",
+		"items":   []any{int64(1), int64(5), int64(7)},
+	})
+
+	tests := []struct {
+		name string
+		src  string
+		want any
+	}{
+		{"no_args", `card -> Luhn`, true},
+		{"case_insensitive", `card -> luhn`, true},
+		{"with_args", `country -> InCodeList("iso3166")`, true},
+		{"lambda", `items -> CountWhere |$i| { $i > 2 }`, int64(2)},
+		{"lambda_default_param", `items -> CountWhere { $0 > 6 }`, int64(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ev.Evaluate(compileExpr(t, tt.src), scope)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := ev.Evaluate(compileExpr(t, `country -> InCodeList`), scope)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "InCodeList requires at least 1 arguments")
+
+	_, err = ev.Evaluate(compileExpr(t, `items -> CountWhere`), scope)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CountWhere requires a lambda expression")
+
+	_, err = eval.NewEvaluator().Evaluate(compileExpr(t, `card -> Luhn`), scope)
+	require.Error(t, err, "functions are scoped to the evaluator they were given to")
+}
+
+func TestWithBuiltin(t *testing.T) {
+	t.Parallel()
+
+	ev := eval.NewEvaluator(eval.WithBuiltin("Shout", 0, 0, 0, func(call eval.Call) (any, error) {
+		s, _ := call.Receiver.(string)
+		return strings.ToUpper(s) + "!", nil
+	}))
+
+	got, err := ev.Evaluate(compileExpr(t, `name -> Shout`), eval.PropertyScopeFromMap(map[string]any{"name": "hi"}))
+	require.NoError(t, err)
+	assert.Equal(t, "HI!", got)
+}
+
+func TestFunctionRegistry_Register_Errors(t *testing.T) {
+	t.Parallel()
+
+	fns := eval.NewFunctionRegistry()
+	require.NoError(t, fns.Register("Luhn", 0, 0, 0, luhn))
+
+	tests := []struct {
+		name    string
+		fname   string
+		min     int
+		max     int
+		fn      eval.Function
+		wantErr string
+	}{
+		{"invalid_name", "is-weekday", 0, 0, luhn, "invalid function name"},
+		{"builtin", "len", 0, 0, luhn, "is a builtin"},
+		{"operator", "in", 0, 0, luhn, "is a builtin"},
+		{"duplicate", "LUHN", 0, 0, luhn, "already registered"},
+		{"nil_fn", "Nothing", 0, 0, nil, "no implementation"},
+		{"bad_arity", "Bad", 2, 1, luhn, "invalid arity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := fns.Register(tt.fname, tt.min, tt.max, 0, tt.fn)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	assert.Equal(t, []string{"Luhn"}, fns.Names())
+	assert.True(t, fns.Builtins().Has("Luhn"))
+	assert.True(t, fns.Builtins().Has("Len"))
+
+	assert.Panics(t, func() {
+		eval.NewEvaluator(eval.WithFunctions(fns), eval.WithBuiltin("Luhn", 0, 0, 0, luhn))
+	})
+}
@@ -1,13 +1,17 @@
 package eval
 
-import "log/slog"
+import (
+	"fmt"
+	"log/slog"
+)
 
 // EvalOption configures the Evaluator.
 type EvalOption func(*evalConfig)
 
 // evalConfig holds evaluator configuration.
 type evalConfig struct {
-	logger *slog.Logger
+	logger    *slog.Logger
+	functions *FunctionRegistry
 }
 
 // WithLogger sets the logger for debug output during evaluation.
@@ -18,6 +22,43 @@ func WithLogger(logger *slog.Logger) EvalOption {
 	}
 }
 
+// WithFunctions makes the user-defined functions in reg available to
+// expressions. The definitions are copied when the option is applied.
+//
+// Panics if a function is already registered by another option.
+func WithFunctions(reg *FunctionRegistry) EvalOption {
+	return func(c *evalConfig) {
+		if reg == nil {
+			return
+		}
+		for key, def := range reg.defs {
+			if _, exists := c.functions.lookup(key); exists {
+				panic(fmt.Sprintf("eval.WithFunctions: function already registered: %q", def.name))
+			}
+			c.ensureFunctions().defs[key] = def
+		}
+	}
+}
+
+// WithBuiltin registers a single user-defined function; see
+// [FunctionRegistry.Register] for the meaning of the arguments.
+//
+// Panics if the registration is invalid.
+func WithBuiltin(name string, minArgs, maxArgs, params int, fn Function) EvalOption {
+	return func(c *evalConfig) {
+		c.ensureFunctions().MustRegister(name, minArgs, maxArgs, params, fn)
+	}
+}
+
+// ensureFunctions returns the config's own function registry, creating it
+// on first use so options never mutate a caller's registry.
+func (c *evalConfig) ensureFunctions() *FunctionRegistry {
+	if c.functions == nil {
+		c.functions = NewFunctionRegistry()
+	}
+	return c.functions
+}
+
 // applyOptions applies the given options to a config.
 func applyOptions(opts []EvalOption) *evalConfig {
 	cfg := &evalConfig{}
@@ -2,8 +2,12 @@ package instance
 
 import (
 	"log/slog"
+	"time"
 
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/instance/eval"
 	"github.com/simon-lentz/yammm/internal/value"
+	"github.com/simon-lentz/yammm/schema"
 )
 
 // ValidatorOption configures the Validator.
@@ -16,6 +20,19 @@ type validatorConfig struct {
 	allowUnknownFields   bool
 	maxIssuesPerInstance int
 	valueRegistry        value.Registry
+	customTypes          *schema.CustomTypeRegistry
+	invariantPolicy      map[string]invariantOverride
+	functions            *eval.FunctionRegistry
+	typeField            string
+	clock                func() time.Time
+	timezonePolicy       eval.TimezonePolicy
+	severityPolicy       *diag.SeverityPolicy
+}
+
+// invariantOverride is a per-run adjustment to a named invariant.
+type invariantOverride struct {
+	disabled bool
+	severity diag.Severity
 }
 
 // defaultConfig returns the default validator configuration.
@@ -24,6 +41,7 @@ func defaultConfig() *validatorConfig {
 		strictPropertyNames:  false,
 		allowUnknownFields:   false,
 		maxIssuesPerInstance: 100,
+		typeField:            "$type",
 	}
 }
 
@@ -66,6 +84,35 @@ func WithMaxIssuesPerInstance(max int) ValidatorOption {
 	}
 }
 
+// WithTypeField sets the field that names the concrete type of a composed
+// child, used when a composition targets a part type with subtypes.
+// Default is "$type", matching the JSON adapter's default. An empty field
+// is ignored.
+func WithTypeField(field string) ValidatorOption {
+	return func(c *validatorConfig) {
+		if field != "" {
+			c.typeField = field
+		}
+	}
+}
+
+// WithClock sets the clock that relative Timestamp and Date bounds (now,
+// today) are resolved against. Default is time.Now; tests can inject a
+// fixed clock for reproducible results.
+func WithClock(now func() time.Time) ValidatorOption {
+	return func(c *validatorConfig) {
+		c.clock = now
+	}
+}
+
+// WithTimezonePolicy controls which UTC offsets Timestamp values may carry.
+// Default is eval.TimezoneAny.
+func WithTimezonePolicy(policy eval.TimezonePolicy) ValidatorOption {
+	return func(c *validatorConfig) {
+		c.timezonePolicy = policy
+	}
+}
+
 // WithValueRegistry sets a custom value registry for type classification.
 // This enables recognition of custom Go types (e.g., `type MyInt int64`)
 // during constraint checking.
@@ -82,6 +129,82 @@ func WithValueRegistry(reg value.Registry) ValidatorOption {
 	}
 }
 
+// WithCustomTypes sets the Go datatypes that properties of custom types are
+// checked and coerced with. Pass the registry the schema was loaded with
+// (see load.WithCustomTypes); a value of a custom type missing from it fails
+// validation. It takes precedence over the CustomTypes of a registry set with
+// WithValueRegistry.
+func WithCustomTypes(r *schema.CustomTypeRegistry) ValidatorOption {
+	return func(c *validatorConfig) {
+		c.customTypes = r
+	}
+}
+
+// WithDisabledInvariants skips evaluation of the named invariants.
+//
+// Names are invariant IDs (see schema.Invariant.ID), optionally qualified by
+// type as "Type.id" to affect only that type. A qualified name takes
+// precedence over an unqualified one. This is intended for loading known-bad
+// legacy data while it is cleaned up.
+func WithDisabledInvariants(names ...string) ValidatorOption {
+	return func(c *validatorConfig) {
+		for _, name := range names {
+			c.setInvariantOverride(name, invariantOverride{disabled: true})
+		}
+	}
+}
+
+// WithInvariantSeverity reports failures of the named invariant at severity
+// instead of its declared severity. Downgrading to diag.Warning or below
+// lets instances that violate it pass validation with the issue attached.
+//
+// Names are matched as in WithDisabledInvariants.
+func WithInvariantSeverity(name string, severity diag.Severity) ValidatorOption {
+	return func(c *validatorConfig) {
+		c.setInvariantOverride(name, invariantOverride{severity: severity})
+	}
+}
+
+// WithSeverityPolicy changes the severity of validation diagnostics by code,
+// or suppresses them. Suppressed issues are counted in
+// diag.Result.SuppressedCount.
+//
+// Downgrading an error below diag.Error lets the instance pass validation
+// without the offending value: a property that fails its type check is left
+// out, just as if it had been absent. Missing primary keys and failures that
+// leave no type to validate against (unknown, abstract, or part types) are
+// always reported unchanged.
+func WithSeverityPolicy(p *diag.SeverityPolicy) ValidatorOption {
+	return func(c *validatorConfig) {
+		if p == nil {
+			c.severityPolicy = nil
+			return
+		}
+		c.severityPolicy = p.Clone().Protect(ErrMissingPrimaryKey)
+	}
+}
+
+// setInvariantOverride records an override, replacing any earlier one for
+// the same name.
+func (c *validatorConfig) setInvariantOverride(name string, o invariantOverride) {
+	if c.invariantPolicy == nil {
+		c.invariantPolicy = make(map[string]invariantOverride)
+	}
+	c.invariantPolicy[name] = o
+}
+
+// invariantOverride returns the override for an invariant of typeName.
+func (c *validatorConfig) invariantOverride(typeName, id string) (invariantOverride, bool) {
+	if len(c.invariantPolicy) == 0 {
+		return invariantOverride{}, false
+	}
+	if o, ok := c.invariantPolicy[typeName+"."+id]; ok {
+		return o, true
+	}
+	o, ok := c.invariantPolicy[id]
+	return o, ok
+}
+
 // RecommendedValidatorOptions returns the recommended default options
 // for new projects. These options prioritize correctness and early error
 // detection over permissiveness.
@@ -105,3 +228,12 @@ func applyOptions(opts []ValidatorOption) *validatorConfig {
 	}
 	return cfg
 }
+
+// WithFunctions makes user-defined functions available to invariant
+// expressions. Invariants calling a function that is neither built in nor
+// registered fail with E_EVAL_ERROR at validation time.
+func WithFunctions(reg *eval.FunctionRegistry) ValidatorOption {
+	return func(c *validatorConfig) {
+		c.functions = reg
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/simon-lentz/yammm/diag"
 	"github.com/simon-lentz/yammm/instance"
 	"github.com/simon-lentz/yammm/instance/path"
 	"github.com/simon-lentz/yammm/internal/value"
@@ -205,3 +206,48 @@ func TestWithValueRegistry(t *testing.T) {
 	assert.Nil(t, failure)
 	require.NotNil(t, valid)
 }
+
+func TestWithSeverityPolicy(t *testing.T) {
+	personType := schema.NewType("Person", location.SourceID{}, location.Span{}, "", false, false)
+	idProp := schema.NewProperty("id", location.Span{}, "", schema.NewIntegerConstraint(), schema.DataTypeRef{}, false, true, schema.DeclaringScope{})
+	ageProp := schema.NewProperty("age", location.Span{}, "", schema.NewIntegerConstraint(), schema.DataTypeRef{}, false, false, schema.DeclaringScope{})
+	personType.SetProperties([]*schema.Property{idProp, ageProp})
+	personType.SetAllProperties([]*schema.Property{idProp, ageProp})
+	personType.SetPrimaryKeys([]*schema.Property{idProp})
+	personType.Seal()
+
+	s := schema.NewSchema("test", location.SourceID{}, location.Span{}, "")
+	s.SetTypes([]*schema.Type{personType})
+	s.Seal()
+
+	policy := diag.NewSeverityPolicy().
+		Override(instance.ErrTypeMismatch, diag.Warning).
+		Suppress(instance.ErrUnknownField, instance.ErrMissingRequired, instance.ErrMissingPrimaryKey)
+	validator := instance.NewValidator(s, instance.WithSeverityPolicy(policy))
+
+	t.Run("downgraded_value_is_dropped", func(t *testing.T) {
+		raw := instance.RawInstance{
+			Properties: map[string]any{"id": int64(1), "age": "old", "legacy": true},
+		}
+		valid, failure, err := validator.ValidateOne(t.Context(), "Person", raw)
+		require.NoError(t, err)
+		require.Nil(t, failure)
+		require.NotNil(t, valid)
+
+		_, hasAge := valid.Property("age")
+		assert.False(t, hasAge, "a value that failed its check is left out")
+		diags := valid.Diagnostics()
+		require.Equal(t, 1, diags.Len())
+		assert.Equal(t, diag.Warning, diags.IssuesSlice()[0].Severity())
+		assert.Equal(t, 1, diags.SuppressedCount())
+	})
+
+	t.Run("missing_primary_key_still_fails", func(t *testing.T) {
+		raw := instance.RawInstance{Properties: map[string]any{"age": int64(3)}}
+		valid, failure, err := validator.ValidateOne(t.Context(), "Person", raw)
+		require.NoError(t, err)
+		assert.Nil(t, valid)
+		require.NotNil(t, failure)
+		assert.True(t, failure.Result.HasErrors())
+	})
+}
@@ -89,6 +89,21 @@ func (b Builder) PK(fields ...PKField) Builder {
 	return b.append(sb.String())
 }
 
+// Join appends the segments of rel, a path relative to b, to the path.
+//
+// Example:
+//
+//	path.Root().Key("tags").Join(path.Root().Index(2)).String() // returns "$.tags[2]"
+func (b Builder) Join(rel Builder) Builder {
+	if len(rel.segments) == 0 {
+		return b
+	}
+	child := Builder{segments: make([]string, 0, len(b.segments)+len(rel.segments))}
+	child.segments = append(child.segments, b.segments...)
+	child.segments = append(child.segments, rel.segments...)
+	return child
+}
+
 // String returns the canonical path string.
 //
 // The path always starts with "$" (the root symbol), followed by all segments.
@@ -297,6 +297,15 @@ func TestBuilder_Immutability(t *testing.T) {
 	assert.Equal(t, "$.a[0]", grandchild.String())
 }
 
+func TestBuilder_Join(t *testing.T) {
+	base := Root().Key("tags")
+
+	assert.Equal(t, "$.tags", base.Join(Root()).String())
+	assert.Equal(t, "$.tags[2].name", base.Join(Root().Index(2).Key("name")).String())
+	assert.Equal(t, `$.tags["a b"]`, base.Join(Root().Key("a b")).String())
+	assert.Equal(t, "$.tags", base.String(), "Join must not modify the receiver")
+}
+
 func TestBuilder_Navigation(t *testing.T) {
 	t.Run("Parent", func(t *testing.T) {
 		b := Root().Key("a").Key("b").Index(0)
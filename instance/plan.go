@@ -0,0 +1,192 @@
+package instance
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/expr"
+)
+
+// typePlan is the validation program for one type: everything about the
+// type and the validator configuration that does not depend on the instance,
+// computed once and reused for every instance of the type.
+//
+// Plans are immutable after construction and shared between goroutines.
+type typePlan struct {
+	typ *schema.Type
+
+	props []propPlan
+
+	// folded maps lower-cased property names to schema names for
+	// case-insensitive matching; nil with WithStrictPropertyNames(true).
+	folded map[string]string
+
+	// relationFields holds the instance field names of all relations, and
+	// their lower-cased forms unless property names are strict.
+	relationFields map[string]bool
+
+	invariants []invariantPlan
+}
+
+// propPlan is a property with its constraint resolved through aliases.
+type propPlan struct {
+	prop       *schema.Property
+	constraint schema.Constraint
+	coerce     bool // whether CoerceValue can change a value of this constraint
+}
+
+// invariantPlan is an invariant with the invariant policy applied.
+// Disabled invariants have no plan.
+type invariantPlan struct {
+	inv      *schema.Invariant
+	expr     expr.Expression
+	severity diag.Severity
+}
+
+// planCache holds the plans of a Validator, built on first use.
+type planCache struct {
+	byType sync.Map // *schema.Type -> *typePlan
+	byName sync.Map // type name as passed to Validate -> *typePlan
+}
+
+// Compile builds the validation plans of every type of the schema and its
+// imports up front.
+//
+// Plans are otherwise built the first time a type is validated and cached,
+// so Compile is not required; it moves that cost out of the first requests
+// of a long-running service. Compile does not change validation results.
+func (v *Validator) Compile() {
+	if v == nil {
+		return
+	}
+	for name, typ := range v.schema.Types() {
+		v.plans.byName.Store(name, v.planFor(typ))
+	}
+	for imp := range v.schema.Imports() {
+		if imp.Schema() == nil {
+			continue
+		}
+		for name, typ := range imp.Schema().Types() {
+			v.plans.byName.Store(imp.Alias()+"."+name, v.planFor(typ))
+		}
+	}
+}
+
+// planByName returns the plan of the type named typeName, resolving and
+// caching it on first use. Unknown names are not cached.
+func (v *Validator) planByName(typeName string) (*typePlan, error) {
+	if p, ok := v.plans.byName.Load(typeName); ok {
+		return p.(*typePlan), nil
+	}
+	typ, err := v.resolveType(typeName)
+	if err != nil {
+		return nil, err
+	}
+	p := v.planFor(typ)
+	v.plans.byName.Store(typeName, p)
+	return p, nil
+}
+
+// planFor returns the plan of typ, building and caching it on first use.
+func (v *Validator) planFor(typ *schema.Type) *typePlan {
+	if p, ok := v.plans.byType.Load(typ); ok {
+		return p.(*typePlan)
+	}
+	p, _ := v.plans.byType.LoadOrStore(typ, v.buildPlan(typ))
+	return p.(*typePlan)
+}
+
+// fieldNames returns the instance field names of the type's properties and
+// relations, the candidates for suggestions on unknown fields.
+func (p *typePlan) fieldNames() []string {
+	names := make([]string, 0, len(p.props))
+	for _, pp := range p.props {
+		names = append(names, pp.prop.Name())
+	}
+	for rel := range p.typ.AllAssociations() {
+		names = append(names, rel.FieldName())
+	}
+	for rel := range p.typ.AllCompositions() {
+		names = append(names, rel.FieldName())
+	}
+	return names
+}
+
+// buildPlan computes the plan of typ under the validator's configuration.
+func (v *Validator) buildPlan(typ *schema.Type) *typePlan {
+	p := &typePlan{
+		typ:            typ,
+		relationFields: make(map[string]bool),
+	}
+
+	for prop := range typ.AllProperties() {
+		c := unwrapAlias(prop.Constraint())
+		p.props = append(p.props, propPlan{prop: prop, constraint: c, coerce: needsCoercion(c)})
+	}
+	if !v.cfg.strictPropertyNames {
+		p.folded = make(map[string]string, len(p.props))
+		for _, pp := range p.props {
+			p.folded[strings.ToLower(pp.prop.Name())] = pp.prop.Name()
+		}
+	}
+
+	// Instance data uses Relation.FieldName() (e.g., "works_at"), not the DSL
+	// relation name (e.g., "WORKS_AT").
+	addRelation := func(rel *schema.Relation) {
+		p.relationFields[rel.FieldName()] = true
+		if !v.cfg.strictPropertyNames {
+			p.relationFields[strings.ToLower(rel.FieldName())] = true
+		}
+	}
+	for rel := range typ.AllAssociations() {
+		addRelation(rel)
+	}
+	for rel := range typ.AllCompositions() {
+		addRelation(rel)
+	}
+
+	for inv := range typ.AllInvariants() {
+		if inv.Expression() == nil {
+			continue
+		}
+		severity := inv.Severity()
+		if o, ok := v.cfg.invariantOverride(typ.Name(), inv.ID()); ok {
+			if o.disabled {
+				continue
+			}
+			severity = o.severity
+		}
+		p.invariants = append(p.invariants, invariantPlan{inv: inv, expr: inv.Expression(), severity: severity})
+	}
+
+	return p
+}
+
+// unwrapAlias follows resolved aliases to the underlying constraint. Checking
+// and coercing against it gives the same results as against the alias.
+func unwrapAlias(c schema.Constraint) schema.Constraint {
+	for {
+		alias, ok := c.(schema.AliasConstraint)
+		if !ok || alias.Resolved() == nil {
+			return c
+		}
+		c = alias.Resolved()
+	}
+}
+
+// needsCoercion reports whether CoerceValue may return a value other than
+// its input for constraint c. Values of the other kinds are already canonical.
+func needsCoercion(c schema.Constraint) bool {
+	if c == nil {
+		return true
+	}
+	switch c.Kind() {
+	case schema.KindString, schema.KindBoolean, schema.KindTimestamp, schema.KindDate,
+		schema.KindUUID, schema.KindEnum, schema.KindPattern:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,189 @@
+package instance_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/instance"
+	"github.com/simon-lentz/yammm/schema/load"
+)
+
+// validationOutput renders everything a caller can observe of a batch
+// validation, for comparing validators.
+func validationOutput(t *testing.T, validator *instance.Validator, typeName string, raws []instance.RawInstance) []string {
+	t.Helper()
+	valid, failures, err := validator.Validate(t.Context(), typeName, raws)
+	require.NoError(t, err)
+	var out []string
+	for _, v := range valid {
+		out = append(out, fmt.Sprintf("valid %s %v %v", v.PrimaryKey(), v.Properties(), v.Diagnostics().Messages()))
+	}
+	for _, f := range failures {
+		for issue := range f.Result.Issues() {
+			out = append(out, fmt.Sprintf("failure %s %s %s %v", issue.Code(), issue.Path(), issue.Message(), issue.Details()))
+		}
+	}
+	return out
+}
+
+func TestCompile_SameResults(t *testing.T) {
+	s := loadClinicalTrials(t)
+	batch := clinicalBatch(30, 4)
+	// Mixed-case and unknown fields exercise the case-fold tables.
+	for i, raw := range batch["Patient"] {
+		switch i % 3 {
+		case 0:
+			raw.Properties["First_Name"] = raw.Properties["first_name"]
+			delete(raw.Properties, "first_name")
+		case 1:
+			raw.Properties["LAST_NAME"] = "Byron"
+			raw.Properties["nickname"] = "Ada"
+		}
+	}
+
+	optionSets := map[string][]instance.ValidatorOption{
+		"default":            nil,
+		"strict names":       {instance.WithStrictPropertyNames(true)},
+		"allow unknown":      {instance.WithAllowUnknownFields(true)},
+		"disabled invariant": {instance.WithDisabledInvariants("Enrollment.withdrawal_requires_date")},
+		"downgraded":         {instance.WithInvariantSeverity("withdrawal_requires_reason", diag.Warning)},
+	}
+	for name, opts := range optionSets {
+		t.Run(name, func(t *testing.T) {
+			lazy := instance.NewValidator(s, opts...)
+			compiled := instance.NewValidator(s, opts...)
+			compiled.Compile()
+			for typeName, raws := range batch {
+				want := validationOutput(t, lazy, typeName, raws)
+				assert.Equal(t, want, validationOutput(t, compiled, typeName, raws), typeName)
+				assert.Equal(t, want, validationOutput(t, lazy, typeName, raws), "%s: cached plans", typeName)
+			}
+		})
+	}
+}
+
+func TestCompile_CaseFoldMatching(t *testing.T) {
+	s := loadClinicalTrials(t)
+	validator := instance.NewValidator(s)
+	validator.Compile()
+	base := clinicalBatch(1, 0)["Patient"][0].Properties
+
+	t.Run("exact match wins over folded duplicate", func(t *testing.T) {
+		props := cloneProps(base)
+		props["FIRST_NAME"] = "Grace"
+		_, failure, err := validator.ValidateOne(t.Context(), "Patient", instance.RawInstance{Properties: props})
+		require.NoError(t, err)
+		require.NotNil(t, failure)
+		require.Equal(t, 1, failure.Result.Len())
+		assert.Equal(t, `unknown field "FIRST_NAME"`, failure.Result.IssuesSlice()[0].Message())
+	})
+
+	t.Run("two folded inputs collide", func(t *testing.T) {
+		props := cloneProps(base)
+		delete(props, "first_name")
+		props["First_Name"] = "Ada"
+		props["FIRST_NAME"] = "Grace"
+		_, failure, err := validator.ValidateOne(t.Context(), "Patient", instance.RawInstance{Properties: props})
+		require.NoError(t, err)
+		require.NotNil(t, failure)
+		codes := make([]diag.Code, 0, failure.Result.Len())
+		for issue := range failure.Result.Issues() {
+			codes = append(codes, issue.Code())
+		}
+		assert.Contains(t, codes, instance.ErrCaseFoldCollision)
+	})
+
+	t.Run("relation fields match case-insensitively", func(t *testing.T) {
+		props := cloneProps(base)
+		props["CONTACT"] = props["contact"]
+		delete(props, "contact")
+		valid, failure, err := validator.ValidateOne(t.Context(), "Patient", instance.RawInstance{Properties: props})
+		require.NoError(t, err)
+		require.Nil(t, failure, "%v", failure)
+		assert.NotNil(t, valid)
+	})
+}
+
+func cloneProps(props map[string]any) map[string]any {
+	clone := make(map[string]any, len(props))
+	for k, v := range props {
+		clone[k] = v
+	}
+	return clone
+}
+
+func TestCompile_ImportedTypes(t *testing.T) {
+	sources := map[string][]byte{
+		"main.yammm": []byte(`schema "main"
+import "./shared" as shared
+type Order {
+    id String primary
+}
+`),
+		"shared.yammm": []byte(`schema "shared"
+type Customer {
+    id String primary
+    name String required
+}
+`),
+	}
+	s, result, err := load.LoadSourcesWithEntry(t.Context(), sources, "main.yammm", t.TempDir())
+	require.NoError(t, err)
+	require.True(t, result.OK(), "%v", result.Messages())
+
+	validator := instance.NewValidator(s)
+	validator.Compile()
+
+	valid, failure, err := validator.ValidateOne(t.Context(), "shared.Customer",
+		instance.RawInstance{Properties: map[string]any{"id": "c1", "name": "Ada"}})
+	require.NoError(t, err)
+	require.Nil(t, failure)
+	assert.Equal(t, "shared.Customer", valid.TypeName())
+
+	_, failure, err = validator.ValidateOne(t.Context(), "shared.Nobody", instance.RawInstance{})
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	assert.Equal(t, instance.ErrTypeNotFound, failure.Result.IssuesSlice()[0].Code())
+}
+
+func TestCompile_Concurrent(t *testing.T) {
+	s := loadClinicalTrials(t)
+	validator := instance.NewValidator(s)
+	batch := clinicalBatch(20, 5)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for typeName, raws := range batch {
+				_, _, err := validator.Validate(t.Context(), typeName, raws)
+				assert.NoError(t, err)
+			}
+		})
+	}
+	wg.Go(validator.Compile)
+	wg.Wait()
+}
+
+// BenchmarkValidate_Compiled measures steady-state validation of a compiled
+// validator on the clinical trials example.
+func BenchmarkValidate_Compiled(b *testing.B) {
+	s := loadClinicalTrials(b)
+	validator := instance.NewValidator(s)
+	validator.Compile()
+	batch := clinicalBatch(1000, 10)
+	ctx := b.Context()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		for typeName, raws := range batch {
+			if _, _, err := validator.Validate(ctx, typeName, raws); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"iter"
 	"slices"
 
+	"github.com/simon-lentz/yammm/diag"
 	"github.com/simon-lentz/yammm/immutable"
 	"github.com/simon-lentz/yammm/schema"
 )
@@ -20,6 +21,10 @@ type ValidInstance struct {
 	edges      map[string]*ValidEdgeData
 	composed   map[string]immutable.Value
 	provenance *Provenance
+
+	// diagnostics holds non-failing issues (warnings, info) reported
+	// during validation.
+	diagnostics diag.Result
 }
 
 // NewValidInstance creates a new ValidInstance.
@@ -96,6 +101,14 @@ func (v *ValidInstance) Provenance() *Provenance {
 	return v.provenance
 }
 
+// Diagnostics returns the non-failing issues reported while validating the
+// instance, such as `!warn` invariant failures. It is empty (OK) when
+// validation produced no issues. Issues suppressed by WithSeverityPolicy are
+// counted in its SuppressedCount.
+func (v *ValidInstance) Diagnostics() diag.Result {
+	return v.diagnostics
+}
+
 // HasProvenance reports whether provenance is available.
 func (v *ValidInstance) HasProvenance() bool {
 	return v.provenance != nil
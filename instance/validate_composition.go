@@ -3,6 +3,7 @@ package instance
 import (
 	"context"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
@@ -42,6 +43,45 @@ func pkPathFromInstance(basePath path.Builder, child *ValidInstance, childType *
 	return basePath.PK(fields...)
 }
 
+// composedChildType selects the type a composed child is validated against.
+// A child may name the composition target or one of its subtypes in the type
+// field (see WithTypeField); the field is required when the target is
+// abstract. The returned RawInstance has the type field removed.
+func (v *Validator) composedChildType(
+	targetName string,
+	target *schema.Type,
+	raw RawInstance,
+) (string, *schema.Type, RawInstance, *ValidationFailure) {
+	field := v.cfg.typeField
+	tag, hasTag := raw.Properties[field]
+	if !hasTag {
+		if target.IsAbstract() {
+			failure := NewValidationFailure(raw, createErrorResult(ErrMissingTypeTag,
+				fmt.Sprintf("missing %s field naming a concrete subtype of abstract part type %q", field, targetName),
+				raw.Provenance))
+			return "", nil, raw, &failure
+		}
+		return targetName, target, raw, nil
+	}
+
+	name, ok := tag.(string)
+	if !ok {
+		failure := NewValidationFailure(raw, createErrorResult(ErrInvalidTypeTag,
+			fmt.Sprintf("%s must be a string, got %s", field, kindOf(tag)), raw.Provenance))
+		return "", nil, raw, &failure
+	}
+	typ, err := v.resolveType(name)
+	if err != nil || (typ.ID() != target.ID() && !typ.IsSubTypeOf(target.ID())) {
+		failure := NewValidationFailure(raw, createErrorResult(ErrInvalidTypeTag,
+			fmt.Sprintf("%s %q is not %q or one of its subtypes", field, name, targetName), raw.Provenance))
+		return "", nil, raw, &failure
+	}
+
+	props := maps.Clone(raw.Properties)
+	delete(props, field)
+	return name, typ, RawInstance{Properties: props, Provenance: raw.Provenance}, nil
+}
+
 // validateCompositions validates all composition relations for an instance.
 // Returns a map of relation name -> composed children as immutable.Value.
 func (v *Validator) validateCompositions(
@@ -234,17 +274,27 @@ func (v *Validator) validateComposition(
 			collector.Collect(augmented)
 		}
 	}
+	for _, child := range validChildren {
+		for issue := range child.Diagnostics().Issues() {
+			collector.Collect(diag.FromIssue(issue).WithDetails(relationDetails...).Build())
+		}
+	}
 
 	// Check for duplicate PKs among children - only for types that have PKs.
 	// PK-less composed children use structural position (array index) for identity,
 	// so no duplicate check is needed for them.
+	// Children of an abstract target may have different concrete types; each
+	// is keyed by its concrete type and that type's primary key, so siblings
+	// of different types may share key values.
 	if len(validChildren) > 0 {
-		childType, found := v.schema.ResolveType(rel.Target())
-		if found && childType.HasPrimaryKey() {
-			seenPKs := make(map[string]int) // pk string -> first occurrence index
-			for i, child := range validChildren {
+		type childKey struct{ typeName, pk string }
+		seenPKs := make(map[childKey]int) // (type, pk) -> first occurrence index
+		for i, child := range validChildren {
+			childType, err := v.resolveType(child.TypeName())
+			if err == nil && childType.HasPrimaryKey() {
 				pkStr := child.PrimaryKey().String()
-				if firstIdx, exists := seenPKs[pkStr]; exists {
+				key := childKey{typeName: child.TypeName(), pk: pkStr}
+				if firstIdx, exists := seenPKs[key]; exists {
 					issue := diag.NewIssue(
 						diag.Error,
 						ErrDuplicateComposedPK,
@@ -255,7 +305,7 @@ func (v *Validator) validateComposition(
 					withProvenance(issue, prov, pkPathFromInstance(basePath, child, childType, i).String())
 					collector.Collect(issue.Build())
 				} else {
-					seenPKs[pkStr] = i
+					seenPKs[key] = i
 				}
 			}
 		}
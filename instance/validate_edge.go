@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
 	"slices"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/simon-lentz/yammm/immutable"
 	"github.com/simon-lentz/yammm/instance/eval"
 	"github.com/simon-lentz/yammm/instance/path"
+	"github.com/simon-lentz/yammm/internal/ident"
 	"github.com/simon-lentz/yammm/schema"
 )
 
@@ -287,7 +289,9 @@ func (v *Validator) validateEdgeTarget(
 				diag.Error,
 				code,
 				fmt.Sprintf("FK field %q: %s", fkFieldName, err.Error()),
-			).WithDetails(diag.RelationField(rel.Name(), fkFieldName)...)
+			).WithDetails(diag.RelationField(rel.Name(), fkFieldName)...).
+				WithDetail(diag.DetailKeyDetail, err.Error()).
+				WithSuggestion(checkErrorSuggestion(err))
 			withProvenance(issue, prov, targetPath.Key(fkFieldName).String())
 			targetCollector.Collect(issue.Build())
 			continue
@@ -314,6 +318,15 @@ func (v *Validator) validateEdgeTarget(
 			"missing FK field(s): "+expectedStr,
 		).WithDetail(diag.DetailKeyRelationName, rel.Name()).
 			WithDetail(diag.DetailKeyExpected, expectedStr)
+		// A misspelled FK field is the likeliest cause; point at it.
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			if fkField, ok := ident.Suggest(key, allExpectedFKFields); ok {
+				issue = issue.WithDetail(diag.DetailKeyField, key).
+					WithSuggestion(fkField).
+					WithHint(fmt.Sprintf("field %q looks like a misspelling of %q", key, fkField))
+				break
+			}
+		}
 		withProvenance(issue, prov, targetPath.String())
 		targetCollector.Collect(issue.Build())
 
@@ -416,11 +429,17 @@ func (v *Validator) validateEdgeTarget(
 
 		if !isProp {
 			if !v.cfg.allowUnknownFields {
+				candidates := slices.Clone(allExpectedFKFields)
+				for p := range rel.Properties() {
+					candidates = append(candidates, p.Name())
+				}
+				suggestion, _ := ident.Suggest(fieldName, candidates)
 				issue := diag.NewIssue(
 					diag.Error,
 					ErrUnknownEdgeField,
 					fmt.Sprintf("unknown field in edge object: %q", fieldName),
-				).WithDetails(diag.RelationField(rel.Name(), fieldName)...)
+				).WithDetails(diag.RelationField(rel.Name(), fieldName)...).
+					WithSuggestion(suggestion)
 				withProvenance(issue, prov, targetPath.Key(fieldName).String())
 				targetCollector.Collect(issue.Build())
 			}
@@ -438,8 +457,10 @@ func (v *Validator) validateEdgeTarget(
 				code,
 				fmt.Sprintf("edge property %q: %s", prop.Name(), err.Error()),
 			).WithDetail(diag.DetailKeyRelationName, rel.Name()).
-				WithDetail(diag.DetailKeyPropertyName, prop.Name())
-			withProvenance(issue, prov, targetPath.Key(fieldName).String())
+				WithDetail(diag.DetailKeyPropertyName, prop.Name()).
+				WithDetail(diag.DetailKeyDetail, err.Error()).
+				WithSuggestion(checkErrorSuggestion(err))
+			withProvenance(issue, prov, checkErrorPath(targetPath.Key(fieldName), err))
 			targetCollector.Collect(issue.Build())
 			continue
 		}
@@ -295,6 +295,42 @@ func TestValidateEdges_UnknownField(t *testing.T) {
 	assert.Contains(t, failure.Error(), "unknown field")
 }
 
+func TestValidateEdges_Suggestions(t *testing.T) {
+	targetType := makeAssociationTarget("Company")
+	since := makeProp("since", schema.NewIntegerConstraint(), true, false)
+	personType := makeTypeWithAssociation("Person", targetType, "employer", true, false, []*schema.Property{since})
+
+	s := schema.NewSchema("test", location.SourceID{}, location.Span{}, "")
+	s.SetTypes([]*schema.Type{personType, targetType})
+	s.Seal()
+	validator := instance.NewValidator(s)
+
+	tests := []struct {
+		name       string
+		edge       map[string]any
+		code       diag.Code
+		suggestion string
+	}{
+		{"edge property", map[string]any{"_target_id": int64(42), "snce": int64(2020)}, instance.ErrUnknownEdgeField, "since"},
+		{"target field", map[string]any{"_target_id": int64(42), "target_id": int64(42)}, instance.ErrUnknownEdgeField, "_target_id"},
+		{"misspelled target field", map[string]any{"_taget_id": int64(42)}, instance.ErrMissingFKTarget, "_target_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := instance.RawInstance{Properties: map[string]any{"id": int64(1), "employer": tt.edge}}
+			_, failure, err := validator.ValidateOne(t.Context(), "Person", raw)
+			require.NoError(t, err)
+			require.NotNil(t, failure)
+
+			issues := failure.Result.IssuesSlice()
+			require.Len(t, issues, 1)
+			assert.Equal(t, tt.code, issues[0].Code())
+			assert.NotEmpty(t, issues[0].Hint())
+			assert.Contains(t, issues[0].Details(), diag.Detail{Key: diag.DetailKeySuggestion, Value: tt.suggestion})
+		})
+	}
+}
+
 func TestValidateEdges_UnknownField_Allowed(t *testing.T) {
 	targetType := makeAssociationTarget("Company")
 	personType := makeTypeWithAssociation("Person", targetType, "employer", true, false, nil)
@@ -0,0 +1,89 @@
+package instance
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ValidateParallel validates a batch of raw instances of the given type using
+// up to workers goroutines. A workers value below 1 uses runtime.GOMAXPROCS(0).
+//
+// The results are the same as [Validator.Validate] would return for the same
+// input: valid and failures are in input order regardless of scheduling, and
+// each instance keeps its own issue limit (see WithMaxIssuesPerInstance).
+//
+// On a system error or cancellation, the remaining work is abandoned and the
+// results for the instances before the first unfinished or erroring one are
+// returned with the error, as Validate would have produced them.
+func (v *Validator) ValidateParallel(ctx context.Context, typeName string, raws []RawInstance, workers int) ([]*ValidInstance, []ValidationFailure, error) {
+	if v == nil {
+		return nil, nil, &InternalError{Kind: KindNilValidator, Cause: ErrNilValidator}
+	}
+	if ctx == nil {
+		panic("instance.ValidateParallel: nil context")
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(raws))
+	if workers <= 1 {
+		return v.Validate(ctx, typeName, raws)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err //nolint:wrapcheck // spec: return ctx.Err() directly for cancellation
+	}
+
+	// Each slot is written by exactly one worker; wg.Wait orders the writes
+	// before the reads below.
+	type slot struct {
+		valid   *ValidInstance
+		failure *ValidationFailure
+		err     error
+		done    bool
+	}
+	slots := make([]slot, len(raws))
+
+	// Workers claim indices in increasing order and finish every instance
+	// they claim, so when one fails, all earlier instances are complete.
+	var next atomic.Int64
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for !stop.Load() && ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= len(raws) {
+					return
+				}
+				s := &slots[i]
+				s.valid, s.failure, s.err = v.ValidateOne(ctx, typeName, raws[i])
+				s.done = true
+				if s.err != nil {
+					stop.Store(true)
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	var valid []*ValidInstance
+	var failures []ValidationFailure
+	for i := range slots {
+		s := &slots[i]
+		if !s.done {
+			// Abandoned because ctx was cancelled.
+			return valid, failures, ctx.Err() //nolint:wrapcheck // spec: return ctx.Err() directly for cancellation
+		}
+		if s.err != nil {
+			return valid, failures, s.err
+		}
+		if s.valid != nil {
+			valid = append(valid, s.valid)
+		} else if s.failure != nil {
+			failures = append(failures, *s.failure)
+		}
+	}
+	return valid, failures, nil
+}
@@ -0,0 +1,207 @@
+package instance_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/simon-lentz/yammm/instance"
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/load"
+)
+
+// loadClinicalTrials loads the clinical trials example schema.
+func loadClinicalTrials(tb testing.TB) *schema.Schema {
+	tb.Helper()
+	s, result, err := load.Load(tb.Context(), "../examples/clinical_trials.yammm")
+	require.NoError(tb, err)
+	require.True(tb, result.OK(), "schema errors: %v", result.Messages())
+	return s
+}
+
+func uuid(kind, i int) string {
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", kind, i)
+}
+
+// clinicalBatch generates n instances of each benchmarked clinical trials
+// type. Every invalidEvery-th instance of a type is invalid (0 for none).
+func clinicalBatch(n, invalidEvery int) map[string][]instance.RawInstance {
+	audit := func(p map[string]any) map[string]any {
+		p["created_at"] = "2024-03-01T09:30:00Z"
+		p["created_by"] = "loader"
+		return p
+	}
+	batch := make(map[string][]instance.RawInstance)
+	for i := range n {
+		trial := audit(map[string]any{
+			"nct_id": fmt.Sprintf("NCT%08d", i), "internal_id": uuid(1, i),
+			"title": "Study of compound", "phase": "Phase 2", "status": "Recruiting",
+			"indication": "Hypertension", "investigational_product": "XR-17",
+			"sponsor": "Acme Pharma", "sponsor_type": "Industry", "protocol_version": "1.0",
+			"minimum_age_years": int64(18), "maximum_age_years": int64(65),
+			"sex_eligibility": "All", "healthy_volunteers": false, "target_enrollment": int64(120),
+			"start_date": "2024-01-15", "completion_date": "2026-01-15",
+			"primary_endpoint": "Change in systolic blood pressure",
+		})
+		patient := audit(map[string]any{
+			"patient_id": uuid(2, i), "first_name": "Ada", "last_name": "Lovelace",
+			"date_of_birth": "1980-12-10", "sex": "Female", "weight_kg": 61.5, "active": true,
+			"contact": []any{map[string]any{"email": "ada@example.org", "country": "GB"}},
+		})
+		site := audit(map[string]any{
+			"site_id": uuid(3, i), "site_number": fmt.Sprintf("S-%d", i),
+			"facility_name": "General Hospital", "investigator_count": int64(3),
+			"contact": []any{map[string]any{"phone": "+12025551234", "country": "US", "city": "Springfield"}},
+		})
+		enrollment := audit(map[string]any{
+			"enrollment_id": uuid(4, i), "status": "Randomized",
+			"screen_date": "2024-02-01", "enrollment_date": "2024-02-10",
+			"randomization_date": "2024-02-12", "randomization_code": "R-7", "arm": "A",
+			"patient": map[string]any{"_target_patient_id": uuid(2, i)},
+			"trial":   map[string]any{"_target_nct_id": fmt.Sprintf("NCT%08d", i)},
+			"site":    map[string]any{"_target_site_id": uuid(3, i)},
+			"adverse_events": []any{map[string]any{
+				"ae_term": "Headache", "onset_date": "2024-03-01", "severity": "Grade 1",
+				"relatedness": "Possible", "serious": false, "outcome": "Recovered",
+			}},
+		})
+		if invalidEvery > 0 && i%invalidEvery == 0 {
+			trial["phase"] = "Phase 9"
+			patient["weight_kg"] = 0.5
+			site["contact"] = []any{map[string]any{"country": "USA"}}
+			enrollment["status"] = "Withdrawn"
+		}
+		batch["ClinicalTrial"] = append(batch["ClinicalTrial"], instance.RawInstance{Properties: trial})
+		batch["Patient"] = append(batch["Patient"], instance.RawInstance{Properties: patient})
+		batch["Site"] = append(batch["Site"], instance.RawInstance{Properties: site})
+		batch["Enrollment"] = append(batch["Enrollment"], instance.RawInstance{Properties: enrollment})
+	}
+	return batch
+}
+
+func validKeys(valid []*instance.ValidInstance) []string {
+	keys := make([]string, len(valid))
+	for i, v := range valid {
+		keys[i] = v.PrimaryKey().String()
+	}
+	return keys
+}
+
+func failureMessages(failures []instance.ValidationFailure) [][]string {
+	msgs := make([][]string, len(failures))
+	for i, f := range failures {
+		msgs[i] = f.Result.Messages()
+	}
+	return msgs
+}
+
+func TestValidateParallel_MatchesValidate(t *testing.T) {
+	s := loadClinicalTrials(t)
+	batch := clinicalBatch(200, 7)
+
+	for _, opts := range [][]instance.ValidatorOption{nil, {instance.WithMaxIssuesPerInstance(1)}} {
+		validator := instance.NewValidator(s, opts...)
+		for typeName, raws := range batch {
+			wantValid, wantFailures, err := validator.Validate(t.Context(), typeName, raws)
+			require.NoError(t, err)
+			require.NotEmpty(t, wantValid, "%s: the batch should contain valid instances", typeName)
+			require.NotEmpty(t, wantFailures, "%s: the batch should contain failures", typeName)
+
+			for _, workers := range []int{0, 1, 2, 8, 500} {
+				valid, failures, err := validator.ValidateParallel(t.Context(), typeName, raws, workers)
+				require.NoError(t, err)
+				assert.Equal(t, validKeys(wantValid), validKeys(valid), "%s with %d workers", typeName, workers)
+				assert.Equal(t, failureMessages(wantFailures), failureMessages(failures), "%s with %d workers", typeName, workers)
+			}
+		}
+	}
+}
+
+func TestValidateParallel_IssueLimit(t *testing.T) {
+	s := loadClinicalTrials(t)
+	validator := instance.NewValidator(s, instance.WithMaxIssuesPerInstance(2))
+	raws := make([]instance.RawInstance, 50)
+	for i := range raws {
+		// Missing every required property.
+		raws[i] = instance.RawInstance{Properties: map[string]any{"nct_id": fmt.Sprintf("NCT%08d", i)}}
+	}
+
+	valid, failures, err := validator.ValidateParallel(t.Context(), "ClinicalTrial", raws, 4)
+	require.NoError(t, err)
+	assert.Empty(t, valid)
+	require.Len(t, failures, len(raws))
+	for _, f := range failures {
+		assert.LessOrEqual(t, f.Result.Len(), 2)
+	}
+}
+
+func TestValidateParallel_EdgeCases(t *testing.T) {
+	s := loadClinicalTrials(t)
+	validator := instance.NewValidator(s)
+
+	t.Run("nil input", func(t *testing.T) {
+		valid, failures, err := validator.ValidateParallel(t.Context(), "Patient", nil, 4)
+		require.NoError(t, err)
+		assert.Nil(t, valid)
+		assert.Nil(t, failures)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		valid, failures, err := validator.ValidateParallel(t.Context(), "Patient", []instance.RawInstance{}, 4)
+		require.NoError(t, err)
+		assert.Empty(t, valid)
+		assert.Nil(t, failures)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		_, _, err := validator.ValidateParallel(ctx, "Patient", clinicalBatch(10, 0)["Patient"], 4)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("nil validator", func(t *testing.T) {
+		var nilValidator *instance.Validator
+		_, _, err := nilValidator.ValidateParallel(t.Context(), "Patient", nil, 4)
+		assert.ErrorIs(t, err, instance.ErrNilValidator)
+	})
+}
+
+// BenchmarkValidate_ClinicalTrials validates a multi-type batch of the
+// clinical trials example sequentially and with increasing worker counts.
+func BenchmarkValidate_ClinicalTrials(b *testing.B) {
+	s := loadClinicalTrials(b)
+	validator := instance.NewValidator(s)
+	batch := clinicalBatch(1000, 10)
+	ctx := b.Context()
+
+	run := func(b *testing.B, validate func(typeName string, raws []instance.RawInstance) error) {
+		b.Helper()
+		b.ReportAllocs()
+		for b.Loop() {
+			for typeName, raws := range batch {
+				if err := validate(typeName, raws); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		run(b, func(typeName string, raws []instance.RawInstance) error {
+			_, _, err := validator.Validate(ctx, typeName, raws)
+			return err
+		})
+	})
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("parallel-%d", workers), func(b *testing.B) {
+			run(b, func(typeName string, raws []instance.RawInstance) error {
+				_, _, err := validator.ValidateParallel(ctx, typeName, raws, workers)
+				return err
+			})
+		})
+	}
+}
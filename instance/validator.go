@@ -12,6 +12,7 @@ import (
 	"github.com/simon-lentz/yammm/immutable"
 	"github.com/simon-lentz/yammm/instance/eval"
 	"github.com/simon-lentz/yammm/instance/path"
+	"github.com/simon-lentz/yammm/internal/ident"
 	"github.com/simon-lentz/yammm/location"
 	"github.com/simon-lentz/yammm/schema"
 )
@@ -29,6 +30,7 @@ type Validator struct {
 	cfg       *validatorConfig
 	evaluator *eval.Evaluator
 	checker   *eval.Checker
+	plans     planCache
 }
 
 // NewValidator creates a new Validator for the given schema.
@@ -38,11 +40,17 @@ func NewValidator(s *schema.Schema, opts ...ValidatorOption) *Validator {
 		panic("instance.NewValidator: nil schema")
 	}
 	cfg := applyOptions(opts)
+	reg := cfg.valueRegistry
+	if cfg.customTypes != nil {
+		reg.CustomTypes = cfg.customTypes
+	}
+	checker := eval.NewChecker(reg,
+		eval.WithClock(cfg.clock), eval.WithTimezonePolicy(cfg.timezonePolicy))
 	return &Validator{
 		schema:    s,
 		cfg:       cfg,
-		evaluator: eval.NewEvaluator(),
-		checker:   eval.NewChecker(cfg.valueRegistry),
+		evaluator: eval.NewEvaluator(eval.WithFunctions(cfg.functions)),
+		checker:   checker,
 	}
 }
 
@@ -105,6 +113,9 @@ func (v *Validator) Validate(ctx context.Context, typeName string, raws []RawIns
 //   - (valid, nil, nil) on success
 //   - (nil, failure, nil) on validation failure
 //   - (nil, nil, err) on system error
+//
+// Non-failing diagnostics, such as `!warn` invariant failures, do not prevent
+// success; they are available from ValidInstance.Diagnostics.
 func (v *Validator) ValidateOne(ctx context.Context, typeName string, raw RawInstance) (*ValidInstance, *ValidationFailure, error) {
 	if v == nil {
 		return nil, nil, &InternalError{Kind: KindNilValidator, Cause: ErrNilValidator}
@@ -117,13 +128,13 @@ func (v *Validator) ValidateOne(ctx context.Context, typeName string, raw RawIns
 	}
 
 	// Resolve type
-	typ, err := v.resolveType(typeName)
+	plan, err := v.planByName(typeName)
 	if err != nil {
 		return nil, v.typeResolutionFailure(raw, err), nil
 	}
 
 	// Validate the instance, passing the resolved type to avoid redundant resolution.
-	return v.validateInstance(ctx, typeName, typ, raw)
+	return v.validateInstance(ctx, typeName, plan.typ, raw)
 }
 
 // ValidateForComposition validates instances as composed children.
@@ -182,7 +193,13 @@ func (v *Validator) ValidateForComposition(ctx context.Context, parentType, rela
 			return valid, failures, err //nolint:wrapcheck // spec: return ctx.Err() directly for cancellation
 		}
 
-		instance, failure, err := v.validateComposedInstance(ctx, targetTypeName, targetType, raws[i], true)
+		childTypeName, childType, raw, failure := v.composedChildType(targetTypeName, targetType, raws[i])
+		if failure != nil {
+			failures = append(failures, *failure)
+			continue
+		}
+
+		instance, failure, err := v.validateComposedInstance(ctx, childTypeName, childType, raw, true)
 		if err != nil {
 			return valid, failures, err
 		}
@@ -201,6 +218,10 @@ func (v *Validator) ValidateForComposition(ctx context.Context, parentType, rela
 type ValidationError struct {
 	Code    diag.Code
 	Message string
+
+	// Suggestion is the in-scope name the input was most likely meant to
+	// be, or "" if none is close enough.
+	Suggestion string
 }
 
 func (e *ValidationError) Error() string {
@@ -222,20 +243,50 @@ func (v *Validator) resolveType(typeName string) (*schema.Type, error) {
 	ref := parseTypeRef(typeName)
 	typ, found := v.schema.ResolveType(ref)
 	if !found {
+		suggestion, _ := ident.Suggest(typeName, v.typeNames())
 		return nil, &ValidationError{
-			Code:    ErrTypeNotFound,
-			Message: fmt.Sprintf("type %q not found", typeName),
+			Code:       ErrTypeNotFound,
+			Message:    fmt.Sprintf("type %q not found", typeName),
+			Suggestion: suggestion,
 		}
 	}
 	return typ, nil
 }
 
+// typeNames returns the names instances may use to refer to types: local
+// type names, and imported type names qualified with their alias.
+func (v *Validator) typeNames() []string {
+	names := v.schema.TypeNames()
+	for imp := range v.schema.Imports() {
+		if imp.Schema() == nil {
+			continue
+		}
+		for _, name := range imp.Schema().TypeNames() {
+			names = append(names, imp.Alias()+"."+name)
+		}
+	}
+	return names
+}
+
 // typeResolutionFailure creates a validation failure for type resolution errors.
 func (v *Validator) typeResolutionFailure(raw RawInstance, err error) *ValidationFailure {
-	failure := NewValidationFailure(raw, createErrorResult(ErrTypeNotFound, err.Error(), raw.Provenance))
+	failure := NewValidationFailure(raw, typeNotFoundResult(err, raw.Provenance))
 	return &failure
 }
 
+// typeNotFoundResult creates a result holding a single ErrTypeNotFound
+// error for err, carrying the suggestion of a [ValidationError].
+func typeNotFoundResult(err error, prov *Provenance) diag.Result {
+	issue := diag.NewIssue(diag.Error, ErrTypeNotFound, err.Error())
+	if verr, ok := errors.AsType[*ValidationError](err); ok {
+		issue = issue.WithSuggestion(verr.Suggestion)
+	}
+	withProvenance(issue, prov, provenancePath(prov))
+	collector := diag.NewCollectorUnlimited()
+	collector.Collect(issue.Build())
+	return collector.Result()
+}
+
 // compositionResolutionFailure creates a ValidationFailure for composition resolution errors.
 // When raws is empty, uses nil provenance.
 func (v *Validator) compositionResolutionFailure(parentType, relationName string, raws []RawInstance) *ValidationFailure {
@@ -265,7 +316,7 @@ func (v *Validator) typeResolutionFailureForBatch(err error, raws []RawInstance)
 
 	failure := NewValidationFailure(
 		RawInstance{Provenance: prov},
-		createErrorResult(ErrTypeNotFound, err.Error(), prov),
+		typeNotFoundResult(err, prov),
 	)
 	return &failure
 }
@@ -303,18 +354,21 @@ func (v *Validator) validateComposedInstance(ctx context.Context, typeName strin
 // that will be stored in the resulting ValidInstance.
 func (v *Validator) validateProperties(ctx context.Context, typ *schema.Type, canonicalName string, raw RawInstance) (*ValidInstance, *ValidationFailure, error) {
 	collector := diag.NewCollector(v.cfg.maxIssuesPerInstance)
+	collector.SetPolicy(v.cfg.severityPolicy)
+	plan := v.planFor(typ)
 
 	// Build property name mapping (input name → schema name)
-	propMapping := v.buildPropertyMapping(typ, raw.Properties, collector, raw.Provenance)
+	propMapping := v.buildPropertyMapping(plan, raw.Properties, collector, raw.Provenance)
 
 	// Check for unknown fields
 	if !v.cfg.allowUnknownFields {
-		v.checkUnknownFields(typ, raw.Properties, propMapping, collector, raw.Provenance)
+		v.checkUnknownFields(plan, raw.Properties, propMapping, collector, raw.Provenance)
 	}
 
 	// Validate each property and build the validated properties map
-	validatedProps := make(map[string]any)
-	for prop := range typ.AllProperties() {
+	validatedProps := make(map[string]any, len(plan.props))
+	for i := range plan.props {
+		prop := plan.props[i].prop
 		inputName, hasInput := propMapping[prop.Name()]
 
 		// Get the raw value
@@ -341,7 +395,7 @@ func (v *Validator) validateProperties(ctx context.Context, typ *schema.Type, ca
 		}
 
 		// Validate property type
-		if err := v.checkValueWithRecovery(rawValue, prop.Constraint()); err != nil {
+		if err := v.checkValueWithRecovery(rawValue, plan.props[i].constraint); err != nil {
 			// Check if this is an internal error from panic recovery
 			if internalErr, ok := errors.AsType[*InternalError](err); ok {
 				return nil, nil, internalErr
@@ -354,18 +408,26 @@ func (v *Validator) validateProperties(ctx context.Context, typ *schema.Type, ca
 				diag.Error,
 				code,
 				fmt.Sprintf("property %q: %s", prop.Name(), err.Error()),
-			).WithDetails(diag.TypeProp(typ.Name(), prop.Name())...)
+			).WithDetails(diag.TypeProp(typ.Name(), prop.Name())...).
+				WithDetail(diag.DetailKeyDetail, err.Error()).
+				WithSuggestion(checkErrorSuggestion(err))
 			// Include input field name when it differs from schema property name (for debugging).
 			if inputName != prop.Name() {
 				issue.WithDetail(diag.DetailKeyField, inputName)
 			}
-			withProvenance(issue, raw.Provenance, provenancePathForProperty(raw.Provenance, prop.Name()))
+			withProvenance(issue, raw.Provenance, checkErrorPath(propertyPath(raw.Provenance, prop.Name()), err))
 			collector.Collect(issue.Build())
 			continue
 		}
 
+		// Store values that are already canonical as they are
+		if !plan.props[i].coerce {
+			validatedProps[prop.Name()] = rawValue
+			continue
+		}
+
 		// Coerce to canonical type (int64, float64, []float64)
-		coercedValue, err := v.coerceValueWithRecovery(rawValue, prop.Constraint())
+		coercedValue, err := v.coerceValueWithRecovery(rawValue, plan.props[i].constraint)
 		if err != nil {
 			// Check if this is an internal error from panic recovery
 			if internalErr, ok := errors.AsType[*InternalError](err); ok {
@@ -376,7 +438,9 @@ func (v *Validator) validateProperties(ctx context.Context, typ *schema.Type, ca
 				diag.Error,
 				ErrTypeMismatch,
 				fmt.Sprintf("property %q: coercion error: %s", prop.Name(), err.Error()),
-			).WithDetails(diag.TypeProp(typ.Name(), prop.Name())...)
+			).WithDetails(diag.TypeProp(typ.Name(), prop.Name())...).
+				WithDetail(diag.DetailKeyDetail, err.Error()).
+				WithSuggestion(checkErrorSuggestion(err))
 			// Include input field name when it differs from schema property name (for debugging).
 			if inputName != prop.Name() {
 				issue.WithDetail(diag.DetailKeyField, inputName)
@@ -402,7 +466,7 @@ func (v *Validator) validateProperties(ctx context.Context, typ *schema.Type, ca
 	}
 
 	// Evaluate invariants
-	if err := v.evaluateInvariants(ctx, typ, validatedProps, collector, raw.Provenance); err != nil {
+	if err := v.evaluateInvariants(ctx, plan, validatedProps, collector, raw.Provenance); err != nil {
 		return nil, nil, err
 	}
 
@@ -451,6 +515,9 @@ func (v *Validator) validateProperties(ctx context.Context, typ *schema.Type, ca
 		composed,
 		raw.Provenance,
 	)
+	if collector.Len() > 0 || collector.SuppressedCount() > 0 {
+		validInstance.diagnostics = collector.Result()
+	}
 
 	return validInstance, nil, nil
 }
@@ -468,49 +535,45 @@ func (v *Validator) validateProperties(ctx context.Context, typ *schema.Type, ca
 // When multiple input fields case-fold to the same schema property (e.g., both "Name"
 // and "name"), an E_CASE_FOLD_COLLISION error is emitted and neither is mapped.
 //
-// Complexity: O(N+M) where N is input property count and M is schema property count,
-// using CanonicalPropertyMap() for O(1) case-insensitive lookups.
-func (v *Validator) buildPropertyMapping(typ *schema.Type, props map[string]any, collector *diag.Collector, prov *Provenance) map[string]string {
+// Complexity: O(N) where N is input property count, using the plan's
+// precomputed case-fold table for O(1) case-insensitive lookups.
+func (v *Validator) buildPropertyMapping(plan *typePlan, props map[string]any, collector *diag.Collector, prov *Provenance) map[string]string {
 	// Early return for empty input - no properties to map
 	if len(props) == 0 {
 		return make(map[string]string)
 	}
 
-	mapping := make(map[string]string)
-
-	// Build canonical map once for O(1) case-insensitive lookups (O(M) construction)
-	var canonicalMap map[string]string
-	if !v.cfg.strictPropertyNames {
-		canonicalMap = typ.CanonicalPropertyMap()
-	}
-
-	// Track schema properties claimed by exact matches
-	exactMatches := make(map[string]bool)
+	typ := plan.typ
+	mapping := make(map[string]string, len(props))
 
-	// First pass: exact matches only (deterministic, highest priority)
+	// First pass: exact matches only (deterministic, highest priority).
+	// After this pass, mapping holds exactly the exact matches, keyed by
+	// their shared input and schema name.
 	for inputName := range props {
 		if _, found := typ.Property(inputName); found {
 			mapping[inputName] = inputName
-			exactMatches[inputName] = true
 		}
 	}
 
 	// Second pass: case-fold matches (only if no exact match exists)
-	if !v.cfg.strictPropertyNames {
+	if plan.folded != nil && len(mapping) < len(props) {
 		// Track case-fold collisions: schema property -> input names that fold to it
-		foldedInputs := make(map[string][]string)
+		var foldedInputs map[string][]string
 
 		for inputName := range props {
 			// Skip if this input was an exact match
-			if exactMatches[inputName] {
+			if _, exact := mapping[inputName]; exact {
 				continue
 			}
 
-			if schemaName, found := canonicalMap[strings.ToLower(inputName)]; found {
+			if schemaName, found := plan.folded[strings.ToLower(inputName)]; found {
 				// Skip if this schema property already has an exact match
-				if exactMatches[schemaName] {
+				if _, exact := mapping[schemaName]; exact {
 					continue
 				}
+				if foldedInputs == nil {
+					foldedInputs = make(map[string][]string)
+				}
 				foldedInputs[schemaName] = append(foldedInputs[schemaName], inputName)
 			}
 		}
@@ -549,49 +612,32 @@ func (v *Validator) buildPropertyMapping(typ *schema.Type, props map[string]any,
 }
 
 // checkUnknownFields reports diagnostics for unknown fields in the input.
-func (v *Validator) checkUnknownFields(typ *schema.Type, props map[string]any, mapping map[string]string, collector *diag.Collector, prov *Provenance) {
-	// Build reverse mapping to check which input names were matched as properties
-	matched := make(map[string]bool)
-	for _, inputName := range mapping {
-		matched[inputName] = true
-	}
-
-	// Build set of valid relation field names (normalized JSON names).
-	// Instance data uses Relation.FieldName() (e.g., "works_at"), not the DSL
-	// relation name (e.g., "WORKS_AT").
-	relationFields := make(map[string]bool)
-	for rel := range typ.AllAssociations() {
-		relationFields[rel.FieldName()] = true
-		if !v.cfg.strictPropertyNames {
-			relationFields[strings.ToLower(rel.FieldName())] = true
-		}
-	}
-	for rel := range typ.AllCompositions() {
-		relationFields[rel.FieldName()] = true
-		if !v.cfg.strictPropertyNames {
-			relationFields[strings.ToLower(rel.FieldName())] = true
-		}
-	}
-
+func (v *Validator) checkUnknownFields(plan *typePlan, props map[string]any, mapping map[string]string, collector *diag.Collector, prov *Provenance) {
 	for inputName := range props {
-		if matched[inputName] {
+		// Matched as a property, exactly or by case folding
+		if mapping[inputName] == inputName {
 			continue
 		}
-
-		// Check if it's a relation field name (which would be handled separately)
 		checkName := inputName
-		if !v.cfg.strictPropertyNames {
+		if plan.folded != nil {
 			checkName = strings.ToLower(inputName)
+			if schemaName, ok := plan.folded[checkName]; ok && mapping[schemaName] == inputName {
+				continue
+			}
 		}
-		if relationFields[checkName] {
+
+		// Check if it's a relation field name (which would be handled separately)
+		if plan.relationFields[checkName] {
 			continue
 		}
 
+		suggestion, _ := ident.Suggest(inputName, plan.fieldNames())
 		issue := diag.NewIssue(
 			diag.Error,
 			ErrUnknownField,
 			fmt.Sprintf("unknown field %q", inputName),
-		).WithDetails(diag.TypeField(typ.Name(), inputName)...)
+		).WithDetails(diag.TypeField(plan.typ.Name(), inputName)...).
+			WithSuggestion(suggestion)
 		withProvenance(issue, prov, provenancePathForProperty(prov, inputName))
 		collector.Collect(issue.Build())
 	}
@@ -599,35 +645,41 @@ func (v *Validator) checkUnknownFields(typ *schema.Type, props map[string]any, m
 
 // evaluateInvariants evaluates all type invariants against the validated properties.
 //
+// Failures are reported at the invariant's severity, adjusted by any
+// WithDisabledInvariants/WithInvariantSeverity policy; only failing severities
+// (Error and Fatal) reject the instance.
+//
 // Invariants are evaluated independently - a failure in one invariant does not
 // prevent evaluation of subsequent invariants. All failures are collected before
 // returning, enabling comprehensive error reporting in a single validation pass.
 //
 // This approach trades off "fail-fast" behavior for diagnostic completeness.
 // Users see all invariant violations at once rather than fixing them one at a time.
-func (v *Validator) evaluateInvariants(ctx context.Context, typ *schema.Type, props map[string]any, collector *diag.Collector, prov *Provenance) (err error) {
+func (v *Validator) evaluateInvariants(ctx context.Context, plan *typePlan, props map[string]any, collector *diag.Collector, prov *Provenance) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = wrapPanicValue(r, KindInvariantPanic)
 		}
 	}()
 
+	if len(plan.invariants) == 0 {
+		return nil
+	}
+	typ := plan.typ
 	scope := eval.PropertyScopeFromMap(props).WithSelf(props)
 
-	for inv := range typ.AllInvariants() {
+	for _, ip := range plan.invariants {
 		if err := ctx.Err(); err != nil {
 			return err //nolint:wrapcheck // spec: return ctx.Err() directly for cancellation
 		}
 
-		expr := inv.Expression()
-		if expr == nil {
-			continue
-		}
-
-		result, err := v.evaluator.EvaluateBool(expr, scope) //nolint:contextcheck // Evaluator API doesn't accept context
+		inv, severity := ip.inv, ip.severity
+		result, err := v.evaluator.EvaluateBool(ip.expr, scope) //nolint:contextcheck // Evaluator API doesn't accept context
 		if err != nil {
+			// An invariant that only warns cannot reject the instance by
+			// failing to evaluate either.
 			issue := diag.NewIssue(
-				diag.Error,
+				severity,
 				ErrEvalError,
 				"invariant evaluation error: "+err.Error(),
 			).WithDetail(diag.DetailKeyTypeName, typ.Name())
@@ -642,10 +694,17 @@ func (v *Validator) evaluateInvariants(ctx context.Context, typ *schema.Type, pr
 				msg = "invariant failed"
 			}
 			issue := diag.NewIssue(
-				diag.Error,
+				severity,
 				ErrInvariantFail,
 				msg,
 			).WithDetail(diag.DetailKeyTypeName, typ.Name())
+			if inv.HasExplicitID() {
+				issue.WithDetail(diag.DetailKeyId, inv.ID())
+			}
+			for _, locale := range inv.MessageLocales() {
+				localized, _ := inv.LocalizedMessage(locale)
+				issue.WithLocalizedMessage(locale, localized)
+			}
 			withProvenance(issue, prov, provenancePath(prov))
 			collector.Collect(issue.Build())
 		}
@@ -713,11 +772,34 @@ func provenancePath(prov *Provenance) string {
 
 // provenancePathForProperty returns the path string for a property.
 func provenancePathForProperty(prov *Provenance, propName string) string {
+	return propertyPath(prov, propName).String()
+}
+
+// propertyPath returns the path of a property.
+func propertyPath(prov *Provenance, propName string) path.Builder {
 	if prov == nil {
 		// Use path.Root().Key() to produce canonical path syntax
-		return path.Root().Key(propName).String()
+		return path.Root().Key(propName)
+	}
+	return prov.AtKey(propName).Path()
+}
+
+// checkErrorPath returns the path string of a failed value check: base,
+// extended to the offending element, entry or field of a nested value.
+func checkErrorPath(base path.Builder, err error) string {
+	if checkErr, ok := errors.AsType[*eval.CheckError](err); ok {
+		base = base.Join(checkErr.Path)
+	}
+	return base.String()
+}
+
+// checkErrorSuggestion returns the suggestion carried by a failed value
+// check, or "" if there is none.
+func checkErrorSuggestion(err error) string {
+	if checkErr, ok := errors.AsType[*eval.CheckError](err); ok {
+		return checkErr.Suggestion
 	}
-	return prov.AtKey(propName).Path().String()
+	return ""
 }
 
 // checkValueWithRecovery calls the Checker's CheckValue with panic recovery.
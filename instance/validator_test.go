@@ -4,15 +4,19 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/simon-lentz/yammm/diag"
 	"github.com/simon-lentz/yammm/instance"
+	"github.com/simon-lentz/yammm/instance/eval"
 	"github.com/simon-lentz/yammm/instance/path"
 	"github.com/simon-lentz/yammm/location"
 	"github.com/simon-lentz/yammm/schema"
 	"github.com/simon-lentz/yammm/schema/expr"
+	"github.com/simon-lentz/yammm/schema/load"
 )
 
 // --- Test Helpers ---
@@ -251,6 +255,45 @@ func TestValidator_ValidateOne_UnknownField(t *testing.T) {
 	assert.Contains(t, failure.Error(), "unknown")
 }
 
+func TestValidator_ValidateOne_Suggestions(t *testing.T) {
+	personType := makeType("Person", false, false,
+		makeProp("id", schema.NewIntegerConstraint(), false, true),
+		makeProp("name", schema.NewStringConstraint(), true, false),
+		makeProp("status", schema.NewEnumConstraint([]string{"active", "retired"}), true, false),
+	)
+	validator := instance.NewValidator(makeTestSchema(personType))
+
+	tests := []struct {
+		name       string
+		typeName   string
+		props      map[string]any
+		code       diag.Code
+		suggestion string
+	}{
+		{"type", "Persn", map[string]any{"id": int64(1)}, instance.ErrTypeNotFound, "Person"},
+		{"field", "Person", map[string]any{"id": int64(1), "nmae": "Alice"}, instance.ErrUnknownField, "name"},
+		{"enum value", "Person", map[string]any{"id": int64(1), "status": "Retird"}, instance.ErrConstraintFail, "retired"},
+		{"nothing close", "Person", map[string]any{"id": int64(1), "nickname": "Al"}, instance.ErrUnknownField, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, failure, err := validator.ValidateOne(t.Context(), tt.typeName, instance.RawInstance{Properties: tt.props})
+			require.NoError(t, err)
+			require.NotNil(t, failure)
+
+			issues := failure.Result.IssuesSlice()
+			require.Len(t, issues, 1)
+			assert.Equal(t, tt.code, issues[0].Code())
+			if tt.suggestion == "" {
+				assert.Empty(t, issues[0].Hint())
+				return
+			}
+			assert.Equal(t, `did you mean "`+tt.suggestion+`"?`, issues[0].Hint())
+			assert.Contains(t, issues[0].Details(), diag.Detail{Key: diag.DetailKeySuggestion, Value: tt.suggestion})
+		})
+	}
+}
+
 func TestValidator_ValidateOne_AllowUnknownFields(t *testing.T) {
 	personType := makeType("Person", false, false,
 		makeProp("id", schema.NewIntegerConstraint(), false, true),
@@ -818,6 +861,174 @@ func TestValidator_ValidateOne_InvariantNilExpression(t *testing.T) {
 	require.NotNil(t, valid)
 }
 
+// makeTypeWithSeverityInvariant creates a Person type whose "age >= 0"
+// invariant has the given ID and severity.
+func makeTypeWithSeverityInvariant(id string, severity diag.Severity) *schema.Type {
+	inv := schema.NewInvariant("age must be non-negative", expr.SExpr{
+		expr.Op(">="),
+		expr.SExpr{expr.Op("$"), expr.NewLiteral("age")},
+		expr.NewLiteral(int64(0)),
+	}, location.Span{}, "")
+	inv.SetID(id)
+	inv.SetSeverity(severity)
+	return makeAgeTypeWithInvariant(inv)
+}
+
+// makeAgeTypeWithInvariant creates a Person type with an integer age and
+// the given invariant.
+func makeAgeTypeWithInvariant(inv *schema.Invariant) *schema.Type {
+	idProp := makeProp("id", schema.NewIntegerConstraint(), false, true)
+	ageProp := makeProp("age", schema.NewIntegerConstraint(), false, false)
+	t := schema.NewType("Person", location.SourceID{}, location.Span{}, "", false, false)
+	t.SetProperties([]*schema.Property{idProp, ageProp})
+	t.SetAllProperties([]*schema.Property{idProp, ageProp})
+	t.SetPrimaryKeys([]*schema.Property{idProp})
+	t.SetInvariants([]*schema.Invariant{inv})
+	t.SetAllInvariants([]*schema.Invariant{inv})
+	t.Seal()
+	return t
+}
+
+func TestValidator_ValidateOne_InvariantSeverity(t *testing.T) {
+	t.Parallel()
+
+	raw := instance.RawInstance{Properties: map[string]any{"id": int64(1), "age": int64(-5)}}
+
+	tests := []struct {
+		name     string
+		severity diag.Severity
+		opts     []instance.ValidatorOption
+		wantFail bool
+		wantSev  diag.Severity // severity of the reported issue; ignored when wantNone
+		wantNone bool
+	}{
+		{name: "declared error rejects", severity: diag.Error, wantFail: true, wantSev: diag.Error},
+		{name: "declared warning passes", severity: diag.Warning, wantSev: diag.Warning},
+		{name: "declared info passes", severity: diag.Info, wantSev: diag.Info},
+		{
+			name: "downgraded by id", severity: diag.Error,
+			opts:    []instance.ValidatorOption{instance.WithInvariantSeverity("non_negative_age", diag.Warning)},
+			wantSev: diag.Warning,
+		},
+		{
+			name: "qualified name wins", severity: diag.Error,
+			opts: []instance.ValidatorOption{
+				instance.WithInvariantSeverity("non_negative_age", diag.Info),
+				instance.WithInvariantSeverity("Person.non_negative_age", diag.Warning),
+			},
+			wantSev: diag.Warning,
+		},
+		{
+			name: "upgraded warning rejects", severity: diag.Warning,
+			opts:     []instance.ValidatorOption{instance.WithInvariantSeverity("non_negative_age", diag.Error)},
+			wantFail: true, wantSev: diag.Error,
+		},
+		{
+			name: "disabled", severity: diag.Error,
+			opts:     []instance.ValidatorOption{instance.WithDisabledInvariants("Person.non_negative_age")},
+			wantNone: true,
+		},
+		{
+			name: "other type unaffected", severity: diag.Error,
+			opts:     []instance.ValidatorOption{instance.WithDisabledInvariants("Company.non_negative_age")},
+			wantFail: true, wantSev: diag.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := makeTestSchema(makeTypeWithSeverityInvariant("non_negative_age", tt.severity))
+			validator := instance.NewValidator(s, tt.opts...)
+
+			valid, failure, err := validator.ValidateOne(context.Background(), "Person", raw)
+			require.NoError(t, err)
+
+			var result diag.Result
+			if tt.wantFail {
+				require.NotNil(t, failure)
+				assert.Nil(t, valid)
+				result = failure.Result
+			} else {
+				require.Nil(t, failure, "unexpected failure")
+				require.NotNil(t, valid)
+				result = valid.Diagnostics()
+			}
+
+			if tt.wantNone {
+				assert.Equal(t, 0, result.Len())
+				return
+			}
+			issues := result.IssuesSlice()
+			require.Len(t, issues, 1)
+			assert.Equal(t, diag.E_INVARIANT_FAIL, issues[0].Code())
+			assert.Equal(t, tt.wantSev, issues[0].Severity())
+			assert.Contains(t, issues[0].Details(), diag.Detail{Key: diag.DetailKeyId, Value: "non_negative_age"})
+		})
+	}
+}
+
+func TestValidator_ValidateOne_InvariantEvalErrorSeverity(t *testing.T) {
+	t.Parallel()
+
+	raw := instance.RawInstance{Properties: map[string]any{"id": int64(1), "age": int64(0)}}
+
+	tests := []struct {
+		name     string
+		severity diag.Severity
+		wantFail bool
+	}{
+		{name: "error rejects", severity: diag.Error, wantFail: true},
+		{name: "warning passes", severity: diag.Warning},
+		{name: "info passes", severity: diag.Info},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			// 1 / age divides by zero.
+			inv := schema.NewInvariant("age must divide", expr.SExpr{
+				expr.Op(">"),
+				expr.SExpr{expr.Op("/"), expr.NewLiteral(int64(1)), expr.SExpr{expr.Op("$"), expr.NewLiteral("age")}},
+				expr.NewLiteral(int64(0)),
+			}, location.Span{}, "")
+			inv.SetSeverity(tt.severity)
+
+			validator := instance.NewValidator(makeTestSchema(makeAgeTypeWithInvariant(inv)))
+			valid, failure, err := validator.ValidateOne(context.Background(), "Person", raw)
+			require.NoError(t, err)
+
+			var result diag.Result
+			if tt.wantFail {
+				require.NotNil(t, failure)
+				result = failure.Result
+			} else {
+				require.Nil(t, failure, "unexpected failure")
+				result = valid.Diagnostics()
+			}
+			issues := result.IssuesSlice()
+			require.Len(t, issues, 1)
+			assert.Equal(t, diag.E_EVAL_ERROR, issues[0].Code())
+			assert.Equal(t, tt.severity, issues[0].Severity())
+		})
+	}
+}
+
+func TestValidator_ValidateOne_InvariantImplicitID(t *testing.T) {
+	t.Parallel()
+
+	// Without a declared ID, the message identifies the invariant.
+	s := makeTestSchema(makeTypeWithSeverityInvariant("", diag.Error))
+	validator := instance.NewValidator(s, instance.WithDisabledInvariants("age must be non-negative"))
+
+	raw := instance.RawInstance{Properties: map[string]any{"id": int64(1), "age": int64(-5)}}
+	valid, failure, err := validator.ValidateOne(context.Background(), "Person", raw)
+	require.NoError(t, err)
+	assert.Nil(t, failure)
+	require.NotNil(t, valid)
+	assert.True(t, valid.Diagnostics().OK())
+}
+
 // --- P1.1 Property Path Uses Schema Name Tests ---
 
 func TestValidator_PropertyPath_UsesSchemaName(t *testing.T) {
@@ -1012,3 +1223,161 @@ func TestNewValidator_NilSchemaPanics(t *testing.T) {
 	}()
 	instance.NewValidator(nil)
 }
+
+func TestValidator_ValidateOne_WithFunctions(t *testing.T) {
+	t.Parallel()
+
+	// ! "even id" id -> IsEven
+	inv := schema.NewInvariant("even id", expr.SExpr{
+		expr.Op("IsEven"),
+		expr.SExpr{expr.Op("p"), expr.NewLiteral("id")},
+		expr.NewLiteral([]expr.Expression{}),
+		expr.NewLiteral([]string{}),
+		expr.NewLiteral(nil),
+	}, location.Span{}, "")
+	idProp := makeProp("id", schema.NewIntegerConstraint(), false, true)
+	typ := schema.NewType("Ticket", location.SourceID{}, location.Span{}, "", false, false)
+	typ.SetProperties([]*schema.Property{idProp})
+	typ.SetAllProperties([]*schema.Property{idProp})
+	typ.SetPrimaryKeys([]*schema.Property{idProp})
+	typ.SetInvariants([]*schema.Invariant{inv})
+	typ.SetAllInvariants([]*schema.Invariant{inv})
+	typ.Seal()
+	s := makeTestSchema(typ)
+
+	fns := eval.NewFunctionRegistry()
+	fns.MustRegister("IsEven", 0, 0, 0, func(call eval.Call) (any, error) {
+		n, _ := call.Receiver.(int64)
+		return n%2 == 0, nil
+	})
+	validator := instance.NewValidator(s, instance.WithFunctions(fns))
+
+	valid, failure, err := validator.ValidateOne(t.Context(), "Ticket",
+		instance.RawInstance{Properties: map[string]any{"id": int64(2)}})
+	require.NoError(t, err)
+	require.Nil(t, failure)
+	require.NotNil(t, valid)
+
+	_, failure, err = validator.ValidateOne(t.Context(), "Ticket",
+		instance.RawInstance{Properties: map[string]any{"id": int64(3)}})
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	assert.Equal(t, diag.E_INVARIANT_FAIL, failure.Result.IssuesSlice()[0].Code())
+
+	// Without the registry the call cannot be evaluated.
+	_, failure, err = instance.NewValidator(s).ValidateOne(t.Context(), "Ticket",
+		instance.RawInstance{Properties: map[string]any{"id": int64(2)}})
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	assert.Equal(t, diag.E_EVAL_ERROR, failure.Result.IssuesSlice()[0].Code())
+}
+
+func TestValidator_ValidateOne_WithClock(t *testing.T) {
+	t.Parallel()
+
+	idProp := makeProp("id", schema.NewStringConstraint(), false, true)
+	atProp := makeProp("at", schema.NewTimestampConstraintBounded("",
+		schema.NoTimeBound(), schema.TimeBoundRelative(0)), false, false)
+	typ := schema.NewType("Event", location.SourceID{}, location.Span{}, "", false, false)
+	typ.SetProperties([]*schema.Property{idProp, atProp})
+	typ.SetAllProperties([]*schema.Property{idProp, atProp})
+	typ.SetPrimaryKeys([]*schema.Property{idProp})
+	typ.Seal()
+	s := makeTestSchema(typ)
+
+	raw := instance.RawInstance{Properties: map[string]any{"id": "e1", "at": "2030-01-01T00:00:00Z"}}
+	future := func() time.Time { return time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC) }
+	past := func() time.Time { return time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	_, failure, err := instance.NewValidator(s, instance.WithClock(future)).ValidateOne(t.Context(), "Event", raw)
+	require.NoError(t, err)
+	assert.Nil(t, failure)
+
+	_, failure, err = instance.NewValidator(s, instance.WithClock(past)).ValidateOne(t.Context(), "Event", raw)
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	assert.Equal(t, diag.E_CONSTRAINT_FAIL, failure.Result.IssuesSlice()[0].Code())
+
+	_, failure, err = instance.NewValidator(s, instance.WithClock(future),
+		instance.WithTimezonePolicy(eval.TimezoneRequireUTC)).ValidateOne(t.Context(), "Event",
+		instance.RawInstance{Properties: map[string]any{"id": "e2", "at": "2030-01-01T01:00:00+01:00"}})
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	assert.Contains(t, failure.Result.Messages()[0], "not in UTC")
+}
+
+func TestValidator_ValidateOne_StructuredPaths(t *testing.T) {
+	t.Parallel()
+
+	point := schema.NewObjectConstraint([]schema.ObjectField{
+		schema.NewObjectField("lat", schema.NewFloatConstraintBounded(-90, true, 90, true), true),
+		schema.NewObjectField("lon", schema.NewFloatConstraint(), true),
+	})
+	idProp := makeProp("id", schema.NewStringConstraint(), false, true)
+	stopsProp := makeProp("stops", schema.NewMapConstraint(schema.NewStringConstraint(), point), true, false)
+	typ := schema.NewType("Route", location.SourceID{}, location.Span{}, "", false, false)
+	typ.SetProperties([]*schema.Property{idProp, stopsProp})
+	typ.SetAllProperties([]*schema.Property{idProp, stopsProp})
+	typ.SetPrimaryKeys([]*schema.Property{idProp})
+	typ.Seal()
+	validator := instance.NewValidator(makeTestSchema(typ))
+
+	valid, failure, err := validator.ValidateOne(t.Context(), "Route", instance.RawInstance{
+		Properties: map[string]any{
+			"id":    "r1",
+			"stops": map[string]any{"home": map[string]any{"lat": 52, "lon": 13.4}},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, failure)
+	stops, ok := valid.Property("stops")
+	require.True(t, ok)
+	assert.False(t, stops.IsNil())
+
+	_, failure, err = validator.ValidateOne(t.Context(), "Route", instance.RawInstance{
+		Properties: map[string]any{
+			"id":    "r2",
+			"stops": map[string]any{"work place": map[string]any{"lat": 91, "lon": 0}},
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	issue := failure.Result.IssuesSlice()[0]
+	assert.Equal(t, diag.E_CONSTRAINT_FAIL, issue.Code())
+	assert.Equal(t, `$.stops["work place"].lat`, issue.Path())
+	assert.Contains(t, issue.Message(), `property "stops": key "work place": field "lat": `)
+}
+
+func TestValidator_LocalizedInvariantMessage(t *testing.T) {
+	sources := map[string][]byte{
+		"main.yammm": []byte(`schema "main"
+type Person {
+    id String primary
+    age Integer
+    ! "must be an adult" @de "muss volljährig sein" @pt_BR "deve ser adulto" age >= 18
+}
+`),
+	}
+	s, result, err := load.LoadSourcesWithEntry(t.Context(), sources, "main.yammm", t.TempDir())
+	require.NoError(t, err)
+	require.True(t, result.OK(), "%v", result.Messages())
+
+	validator := instance.NewValidator(s)
+	_, failure, err := validator.ValidateOne(t.Context(), "Person",
+		instance.RawInstance{Properties: map[string]any{"id": "p1", "age": int64(12)}})
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+
+	issue := failure.Result.IssuesSlice()[0]
+	assert.Equal(t, diag.E_INVARIANT_FAIL, issue.Code())
+	assert.Equal(t, "must be an adult", issue.Message())
+
+	assert.Equal(t, []string{"de", "pt-br"}, issue.MessageLocales())
+	for _, d := range issue.Details() {
+		assert.NotEqual(t, "muss volljährig sein", d.Value, "localized messages are not details")
+	}
+
+	german := diag.NewRenderer(diag.WithLocale("de-CH")).FormatIssue(issue)
+	assert.Contains(t, german, "muss volljährig sein")
+	assert.NotContains(t, german, "must be an adult")
+}
@@ -70,6 +70,8 @@ datatypeKeyword
   ;
 // Invariants attach to types with a user-facing message and an expression; message is presented
 // when the invariant evaluates to false during runtime validation.
+// Severity/identifier modifiers between '!' and the message (`!warn adult "..."`) are moved
+// off the default channel by ModifierLexer (modifiers.go) and never reach this rule.
 invariant: DOC_COMMENT? EXCLAMATION message=STRING constraint=expr ;
 
 expr
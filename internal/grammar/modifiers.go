@@ -0,0 +1,159 @@
+package grammar
+
+import "github.com/antlr4-go/antlr/v4"
+
+// InvariantModifierChannel is the token channel carrying invariant modifiers.
+//
+// The generated grammar expects a message string directly after the '!' that
+// opens an invariant. Severity keywords and identifiers written between the
+// two (`!warn email_format "..."`) are moved to this channel by
+// [ModifierLexer] so the parser never sees them; listeners recover them with
+// CommonTokenStream.GetHiddenTokensToRight on the '!' token.
+const InvariantModifierChannel = 2
+
+// MessageVariantChannel is the token channel carrying the localized variants
+// of an invariant message.
+//
+// The generated grammar expects the invariant's expression directly after its
+// message. Variants written between the two, an '@', a locale word and a
+// string each (`! "too young" @de "zu jung" age >= 18`), are moved to this
+// channel by [ModifierLexer]; listeners recover them with
+// CommonTokenStream.GetHiddenTokensToRight on the message token.
+const MessageVariantChannel = 5
+
+// maxInvariantModifiers is the number of lowercase words accepted between '!'
+// and the message: an optional severity keyword followed by an optional
+// identifier.
+const maxInvariantModifiers = 2
+
+// ModifierLexer wraps the generated lexer and routes invariant modifiers to
+// [InvariantModifierChannel], localized invariant messages to
+// [MessageVariantChannel], structured and union datatype bodies to
+// [StructuredTypeChannel], and the 'part' of `abstract part type` to
+// [TypeModifierChannel].
+//
+// A run of one or two lowercase words is treated as a modifier only when it
+// directly follows '!' and is directly followed by a string literal, so
+// logical negation inside expressions (`!active`) is unaffected.
+type ModifierLexer struct {
+	*YammmGrammarLexer
+	pending []antlr.Token
+
+	// Type-position tracking for structured datatypes.
+	prev       [2]int // types of the last two default-channel tokens
+	braceDepth int
+
+	// Replay mode (see NewReplayLexer).
+	replay bool
+	eof    antlr.Token
+}
+
+// NewModifierLexer creates a lexer for input that recognizes invariant
+// modifiers.
+func NewModifierLexer(input antlr.CharStream) *ModifierLexer {
+	return &ModifierLexer{YammmGrammarLexer: NewYammmGrammarLexer(input)}
+}
+
+// NextToken returns the next token, reclassifying invariant modifiers,
+// abstract part types, and structured and union datatypes.
+func (l *ModifierLexer) NextToken() antlr.Token {
+	tok := l.next()
+	switch tok.GetTokenType() {
+	case YammmGrammarLexerEXCLAMATION:
+		l.markModifiers()
+	case structuredTokens().abstract:
+		l.markAbstractPart()
+	default:
+		if union, ok := l.markUnion(tok); ok {
+			tok = union
+		} else {
+			tok = l.markStructured(tok)
+		}
+	}
+	l.track(tok)
+	return tok
+}
+
+// next pops a buffered token or reads a new one.
+func (l *ModifierLexer) next() antlr.Token {
+	if len(l.pending) > 0 {
+		tok := l.pending[0]
+		l.pending = l.pending[1:]
+		return tok
+	}
+	return l.read()
+}
+
+// peek returns the buffered token at index i, reading ahead as needed.
+func (l *ModifierLexer) peek(i int) antlr.Token {
+	for i >= len(l.pending) {
+		l.pending = append(l.pending, l.read())
+	}
+	return l.pending[i]
+}
+
+// read returns the next token from the underlying lexer, or EOF once a
+// replayed token sequence is exhausted.
+func (l *ModifierLexer) read() antlr.Token {
+	if l.replay {
+		return l.eof
+	}
+	return l.YammmGrammarLexer.NextToken()
+}
+
+// markModifiers inspects the tokens following a '!' and moves any modifier
+// words to InvariantModifierChannel and any message variants after the
+// message to MessageVariantChannel.
+func (l *ModifierLexer) markModifiers() {
+	var words []int // indexes into l.pending
+	for i := 0; ; i++ {
+		tok := l.peek(i)
+		if tok.GetChannel() != antlr.TokenDefaultChannel {
+			continue
+		}
+		switch tok.GetTokenType() {
+		case YammmGrammarLexerLC_WORD:
+			if len(words) == maxInvariantModifiers {
+				return
+			}
+			words = append(words, i)
+			continue
+		case YammmGrammarLexerSTRING:
+			for _, idx := range words {
+				l.pending[idx] = l.onModifierChannel(l.pending[idx])
+			}
+			l.markMessageVariants(i + 1)
+		}
+		return
+	}
+}
+
+// markMessageVariants moves each run of '@', a lowercase locale word and a
+// string, starting at buffered index from, to MessageVariantChannel. An '@'
+// cannot start or continue an expression, so the runs are unambiguous.
+func (l *ModifierLexer) markMessageVariants(from int) {
+	want := []int{YammmGrammarLexerAT, YammmGrammarLexerLC_WORD, YammmGrammarLexerSTRING}
+	for {
+		var run []int // indexes into l.pending
+		for i := from; len(run) < len(want); i++ {
+			tok := l.peek(i)
+			if tok.GetChannel() != antlr.TokenDefaultChannel {
+				continue
+			}
+			if tok.GetTokenType() != want[len(run)] {
+				return
+			}
+			run = append(run, i)
+		}
+		for _, idx := range run {
+			tok := l.pending[idx]
+			l.pending[idx] = l.recreate(tok, tok.GetTokenType(), MessageVariantChannel)
+		}
+		from = run[len(run)-1] + 1
+	}
+}
+
+// onModifierChannel returns a copy of tok on InvariantModifierChannel.
+func (l *ModifierLexer) onModifierChannel(tok antlr.Token) antlr.Token {
+	return l.recreate(tok, tok.GetTokenType(), InvariantModifierChannel)
+}
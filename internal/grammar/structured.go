@@ -0,0 +1,337 @@
+package grammar
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/antlr4-go/antlr/v4"
+)
+
+// StructuredTypeChannel is the token channel carrying the bodies of
+// structured and union datatypes and the parameters of ranged Timestamp and
+// Date types.
+//
+// The generated grammar has no productions for `Map<K, V>[min, max]`,
+// `Object { name Type required, ... }` or `A | B`. [ModifierLexer] retypes
+// the Map or Object word (or the first token of a union) as the 'Date'
+// keyword, so the parser accepts it wherever a built-in type is allowed, and
+// moves the tokens of the type's body to this channel. Likewise the grammar
+// accepts only `Timestamp["layout"]` and a bare `Date`; any other bracketed
+// parameters of the two keywords are moved to this channel. Listeners recover the body with
+// CommonTokenStream.GetHiddenTokensToRight on the retyped head token and
+// parse its parts with a lexer from [NewReplayLexer].
+const StructuredTypeChannel = 3
+
+// TypeModifierChannel is the token channel carrying the 'part' modifier of
+// an abstract part type.
+//
+// The generated grammar accepts either 'abstract' or 'part' before 'type',
+// not both. [ModifierLexer] moves the 'part' of `abstract part type` to this
+// channel; listeners recover it with CommonTokenStream.GetHiddenTokensToRight
+// on the 'abstract' token.
+const TypeModifierChannel = 4
+
+// Structured datatype keywords recognized by [ModifierLexer].
+const (
+	MapKeyword    = "Map"
+	ObjectKeyword = "Object"
+
+	// SetKeyword introduces a set (`Set<T>[min, max]`), a List whose
+	// elements are unique. [ModifierLexer] retypes it as the 'List' keyword;
+	// listeners tell the two apart by the token text.
+	SetKeyword = "Set"
+)
+
+// literalTokenType returns the token type of a grammar literal such as 'Date'.
+func literalTokenType(literal string) int {
+	YammmGrammarLexerInit()
+	return slices.Index(YammmGrammarLexerLexerStaticData.LiteralNames, "'"+literal+"'")
+}
+
+// lexedType returns the type of the first token lexed from text.
+func lexedType(text string) int {
+	lexer := NewYammmGrammarLexer(antlr.NewInputStream(text))
+	lexer.RemoveErrorListeners()
+	return lexer.NextToken().GetTokenType()
+}
+
+// keywordTypes holds keyword token types used by structured type detection.
+type keywordTypes struct {
+	date, list int
+	timestamp  int
+	typ, part  int
+	abstract   int
+	importKw   int
+	lcKeywords []int
+}
+
+// structuredTokens returns the keyword token types, looked up once from the
+// generated literal names.
+var structuredTokens = sync.OnceValue(func() (t keywordTypes) {
+	t.date = literalTokenType("Date")
+	t.list = literalTokenType("List")
+	t.timestamp = literalTokenType("Timestamp")
+	t.typ = literalTokenType("type")
+	t.part = literalTokenType("part")
+	t.abstract = literalTokenType("abstract")
+	t.importKw = literalTokenType("import")
+	for _, kw := range []string{
+		"schema", "type", "datatype", "required", "primary", "extends",
+		"includes", "abstract", "one", "many", "import",
+	} {
+		t.lcKeywords = append(t.lcKeywords, literalTokenType(kw))
+	}
+	return t
+})
+
+// NewReplayLexer returns a token source that replays toks, taken from
+// [StructuredTypeChannel], on the default channel followed by EOF. Hidden
+// tokens keep their channel. A retyped structured or union head regains its
+// lexed type, and structured types nested in toks are recognized again, so a
+// structured body can be parsed with any grammar rule.
+func NewReplayLexer(toks []antlr.Token) *ModifierLexer {
+	l := NewModifierLexer(antlr.NewInputStream(""))
+	l.replay = true
+	for _, tok := range toks {
+		channel, tokenType := tok.GetChannel(), tok.GetTokenType()
+		if channel == StructuredTypeChannel {
+			channel = antlr.TokenDefaultChannel
+		}
+		if tokenType == structuredTokens().date && tok.GetText() != "Date" {
+			tokenType = lexedType(tok.GetText())
+		}
+		l.pending = append(l.pending, l.recreate(tok, tokenType, channel))
+	}
+	if n := len(toks); n > 0 {
+		last := toks[n-1]
+		l.eof = l.GetTokenFactory().Create(last.GetSource(), antlr.TokenEOF, "<EOF>",
+			antlr.TokenDefaultChannel, last.GetStop()+1, last.GetStop(),
+			last.GetLine(), last.GetColumn()+len(last.GetText()))
+	} else {
+		l.eof = l.YammmGrammarLexer.NextToken()
+	}
+	return l
+}
+
+// isPropertyNameToken reports whether a token of type tt can name a property
+// (LC_WORD or an lc_keyword).
+func isPropertyNameToken(tt int) bool {
+	return tt == YammmGrammarLexerLC_WORD || slices.Contains(structuredTokens().lcKeywords, tt)
+}
+
+// track records a default-channel token for type-position detection.
+func (l *ModifierLexer) track(tok antlr.Token) {
+	if tok.GetChannel() != antlr.TokenDefaultChannel {
+		return
+	}
+	switch tok.GetTokenType() {
+	case YammmGrammarLexerLBRACE:
+		l.braceDepth++
+	case YammmGrammarLexerRBRACE:
+		l.braceDepth = max(0, l.braceDepth-1)
+	}
+	l.prev[1] = l.prev[0]
+	l.prev[0] = tok.GetTokenType()
+}
+
+// inTypePosition reports whether the next token may be a datatype: after
+// the '=' of a datatype declaration, as a List element, or after a property
+// name inside a body.
+func (l *ModifierLexer) inTypePosition() bool {
+	switch {
+	case l.replay:
+		return true
+	case l.prev[0] == YammmGrammarLexerEQUALS:
+		return true
+	case l.prev[0] == YammmGrammarLexerLT && l.prev[1] == structuredTokens().list:
+		return true
+	default:
+		return l.braceDepth > 0 && isPropertyNameToken(l.prev[0])
+	}
+}
+
+// markUnion recognizes a union datatype (`type Id = String | Integer`). tok
+// is the first token after the '=' of a datatype declaration; if the
+// declaration's right-hand side contains a top-level '|', tok is retyped as
+// the 'Date' keyword and the rest of the right-hand side is moved to
+// StructuredTypeChannel. Listeners detect the union by the '|' in the body.
+func (l *ModifierLexer) markUnion(tok antlr.Token) (antlr.Token, bool) {
+	if l.replay || l.braceDepth > 0 || l.prev[0] != YammmGrammarLexerEQUALS ||
+		tok.GetChannel() != antlr.TokenDefaultChannel {
+		return tok, false
+	}
+
+	kw := structuredTokens()
+	depth, end, union := 0, -1, false
+	for i := 0; end < 0; i++ {
+		p := l.peek(i)
+		if p.GetChannel() != antlr.TokenDefaultChannel {
+			continue
+		}
+		switch tt := p.GetTokenType(); {
+		case tt == antlr.TokenEOF:
+			end = i
+		case depth > 0:
+			depth += groupDelta(tt)
+		case tt == YammmGrammarLexerPIPE:
+			union = true
+		case tt == YammmGrammarLexerDOC_COMMENT || tt == kw.typ || tt == kw.abstract ||
+			tt == kw.part || tt == kw.importKw:
+			end = i
+		default:
+			depth += max(0, groupDelta(tt))
+		}
+	}
+	if !union {
+		return tok, false
+	}
+
+	for i := range end {
+		if p := l.pending[i]; p.GetChannel() == antlr.TokenDefaultChannel {
+			l.pending[i] = l.recreate(p, p.GetTokenType(), StructuredTypeChannel)
+		}
+	}
+	return l.recreate(tok, kw.date, antlr.TokenDefaultChannel), true
+}
+
+// groupDelta returns +1 for an opening bracket, -1 for a closing bracket and
+// 0 for any other token type.
+func groupDelta(tt int) int {
+	switch tt {
+	case YammmGrammarLexerLT, YammmGrammarLexerLBRACE, YammmGrammarLexerLBRACK, YammmGrammarLexerLPAR:
+		return 1
+	case YammmGrammarLexerGT, YammmGrammarLexerRBRACE, YammmGrammarLexerRBRACK, YammmGrammarLexerRPAR:
+		return -1
+	}
+	return 0
+}
+
+// markAbstractPart moves a 'part' directly following 'abstract' to
+// TypeModifierChannel.
+func (l *ModifierLexer) markAbstractPart() {
+	for i := 0; ; i++ {
+		p := l.peek(i)
+		if p.GetChannel() != antlr.TokenDefaultChannel {
+			continue
+		}
+		if p.GetTokenType() == structuredTokens().part {
+			l.pending[i] = l.recreate(p, p.GetTokenType(), TypeModifierChannel)
+		}
+		return
+	}
+}
+
+// markStructured retypes a Map or Object head and moves its body to
+// StructuredTypeChannel, and retypes a Set head as 'List'. tok is returned
+// unchanged if it does not start a structured type or its body is
+// unterminated.
+func (l *ModifierLexer) markStructured(tok antlr.Token) antlr.Token {
+	if tok.GetChannel() != antlr.TokenDefaultChannel || !l.inTypePosition() {
+		return tok
+	}
+	switch tok.GetTokenType() {
+	case structuredTokens().timestamp, structuredTokens().date:
+		l.markTimeRange(tok)
+		return tok
+	case YammmGrammarLexerUC_WORD:
+	default:
+		return tok
+	}
+
+	var end int
+	switch tok.GetText() {
+	case SetKeyword:
+		if l.scanGroup(0, YammmGrammarLexerLT, YammmGrammarLexerGT) < 0 {
+			return tok
+		}
+		return l.recreate(tok, structuredTokens().list, antlr.TokenDefaultChannel)
+	case MapKeyword:
+		end = l.scanGroup(0, YammmGrammarLexerLT, YammmGrammarLexerGT)
+		if end >= 0 {
+			if bounds := l.scanGroup(end+1, YammmGrammarLexerLBRACK, YammmGrammarLexerRBRACK); bounds >= 0 {
+				end = bounds
+			}
+		}
+	case ObjectKeyword:
+		end = l.scanGroup(0, YammmGrammarLexerLBRACE, YammmGrammarLexerRBRACE)
+	default:
+		return tok
+	}
+	if end < 0 {
+		return tok
+	}
+
+	for i := 0; i <= end; i++ {
+		if p := l.pending[i]; p.GetChannel() == antlr.TokenDefaultChannel {
+			l.pending[i] = l.recreate(p, p.GetTokenType(), StructuredTypeChannel)
+		}
+	}
+	return l.recreate(tok, structuredTokens().date, antlr.TokenDefaultChannel)
+}
+
+// markTimeRange moves the bracketed parameters following a Timestamp or Date
+// keyword to StructuredTypeChannel, unless they are the `["layout"]` form
+// the grammar accepts for Timestamp. The keyword keeps its type.
+func (l *ModifierLexer) markTimeRange(tok antlr.Token) {
+	end := l.scanGroup(0, YammmGrammarLexerLBRACK, YammmGrammarLexerRBRACK)
+	if end < 0 {
+		return
+	}
+	var params []antlr.Token
+	for i := 0; i <= end; i++ {
+		if p := l.pending[i]; p.GetChannel() == antlr.TokenDefaultChannel {
+			params = append(params, p)
+		}
+	}
+	if tok.GetTokenType() == structuredTokens().timestamp && len(params) == 3 &&
+		params[1].GetTokenType() == YammmGrammarLexerSTRING {
+		return
+	}
+	for i := 0; i <= end; i++ {
+		if p := l.pending[i]; p.GetChannel() == antlr.TokenDefaultChannel {
+			l.pending[i] = l.recreate(p, p.GetTokenType(), StructuredTypeChannel)
+		}
+	}
+}
+
+// scanGroup finds the balanced open/close group whose opening token is the
+// first default-channel token at or after pending index from. It returns the
+// pending index of the closing token, or -1 if there is no such group.
+func (l *ModifierLexer) scanGroup(from, open, closing int) int {
+	depth := 0
+	for i := from; ; i++ {
+		tok := l.peek(i)
+		if tok.GetTokenType() == antlr.TokenEOF {
+			return -1
+		}
+		if tok.GetChannel() != antlr.TokenDefaultChannel {
+			continue
+		}
+		if depth == 0 && tok.GetTokenType() != open {
+			return -1
+		}
+		switch tok.GetTokenType() {
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+}
+
+// recreate returns a copy of tok with the given type and channel.
+func (l *ModifierLexer) recreate(tok antlr.Token, tokenType, channel int) antlr.Token {
+	return l.GetTokenFactory().Create(
+		tok.GetSource(),
+		tokenType,
+		tok.GetText(),
+		channel,
+		tok.GetStart(),
+		tok.GetStop(),
+		tok.GetLine(),
+		tok.GetColumn(),
+	)
+}
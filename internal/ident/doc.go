@@ -29,6 +29,13 @@
 //	http_server -> httpServer  (ToLowerCamel)
 //	HTTPServer  -> HTTPServer  (Capitalize preserves acronyms)
 //
+// # Suggestions
+//
+// [Suggest] picks the closest candidate for a misspelled identifier, for
+// "did you mean" hints on unknown names:
+//
+//	Suggest("Persn", []string{"Person", "Place"}) = "Person", true
+//
 // # Thread Safety
 //
 // All functions in this package are stateless and safe for concurrent use.
@@ -0,0 +1,68 @@
+package ident
+
+import "strings"
+
+// Suggest returns the candidate that name was most likely meant to be, for
+// "did you mean" hints on unknown identifiers.
+//
+// Candidates are compared case-insensitively by edit distance, where
+// inserting, deleting, or replacing a rune or swapping two adjacent runes
+// each cost one. A candidate differing only in case always wins; otherwise
+// a candidate qualifies when its distance is at most a third of the length
+// of name (and at least one). Ties are broken by case-sensitive distance,
+// then by the candidate that sorts first, so the result does not depend on
+// candidate order. Candidates equal to name are ignored.
+//
+// Returns false when no candidate is close enough.
+func Suggest(name string, candidates []string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	folded := []rune(strings.ToLower(name))
+	maxDist := max(1, len(folded)/3)
+
+	var best string
+	var bestDist, bestExact int
+	for _, c := range candidates {
+		if c == name || c == "" {
+			continue
+		}
+		d := editDistance(folded, []rune(strings.ToLower(c)))
+		if d > maxDist {
+			continue
+		}
+		exact := editDistance([]rune(name), []rune(c))
+		if best == "" || d < bestDist ||
+			(d == bestDist && (exact < bestExact || (exact == bestExact && c < best))) {
+			best, bestDist, bestExact = c, d, exact
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b: the Levenshtein distance extended with transpositions of adjacent runes.
+func editDistance(a, b []rune) int {
+	// Three rolling rows: two rows back (for transpositions), previous, current.
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,50 @@
+package ident_test
+
+import (
+	"testing"
+
+	"github.com/simon-lentz/yammm/internal/ident"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		candidates []string
+		want       string
+		wantOK     bool
+	}{
+		{name: "single typo", input: "Persn", candidates: []string{"Person", "Place"}, want: "Person", wantOK: true},
+		{name: "case only", input: "person", candidates: []string{"Persons", "Person"}, want: "Person", wantOK: true},
+		{name: "transposition", input: "nmae", candidates: []string{"name", "age"}, want: "name", wantOK: true},
+		{name: "missing prefix", input: "target_id", candidates: []string{"_target_id", "since"}, want: "_target_id", wantOK: true},
+		{name: "too far", input: "xyz", candidates: []string{"name", "age"}, wantOK: false},
+		{name: "exact match ignored", input: "name", candidates: []string{"name"}, wantOK: false},
+		{name: "no candidates", input: "name", wantOK: false},
+		{name: "empty input", input: "", candidates: []string{"a"}, wantOK: false},
+		{name: "unicode", input: "größe", candidates: []string{"grösse", "größer"}, want: "größer", wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ident.Suggest(tt.input, tt.candidates)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSuggest_Deterministic(t *testing.T) {
+	// "colr" is one edit from each candidate; the first in sort order wins
+	// regardless of candidate order.
+	forward := []string{"color", "colt", "cola"}
+	backward := []string{"cola", "colt", "color"}
+	a, _ := ident.Suggest("colr", forward)
+	b, _ := ident.Suggest("colr", backward)
+	assert.Equal(t, "cola", a)
+	assert.Equal(t, a, b)
+
+	// Case-sensitive distance breaks ties between case-insensitive equals.
+	got, _ := ident.Suggest("NAME", []string{"name", "Name", "NAMe"})
+	assert.Equal(t, "NAMe", got)
+}
@@ -3,6 +3,8 @@ package value
 import (
 	"encoding/json"
 	"reflect"
+
+	"github.com/simon-lentz/yammm/schema"
 )
 
 // Kind identifies the semantic type of a runtime value.
@@ -43,7 +45,8 @@ func (k Kind) String() string {
 	}
 }
 
-// Registry allows custom type recognition via reflect.Type hooks.
+// Registry allows custom type recognition via reflect.Type hooks, and holds
+// the Go datatypes that custom constraints are checked against.
 //
 // The Registry hook is designed for Phase 3 (v2/instance/eval) integration.
 // When instance validation is implemented, the evaluator will populate
@@ -54,6 +57,10 @@ type Registry struct {
 	// Returns UnspecifiedKind if the type is not recognized.
 	// If nil, ClassifyWithRegistry falls back to built-in detection.
 	BaseKindOfReflectType func(reflect.Type) Kind
+
+	// CustomTypes resolves schema.CustomConstraint names to their Go
+	// implementations. If nil, values of custom types fail to check.
+	CustomTypes *schema.CustomTypeRegistry
 }
 
 // Classify normalizes a runtime value into a Kind and possibly transformed value.
@@ -204,6 +204,27 @@ func Less(left, right any) (bool, error) {
 	return cmp < 0, nil
 }
 
+// Compare is a total order over arbitrary values for sorting and duplicate detection, such as
+// keeping Set elements unique and in a stable order. Values supported by ValueOrder compare
+// canonically (so int64(1) equals float64(1)); unsupported values such as time.Time sort after
+// them, by type name and then by their formatted text.
+func Compare(left, right any) int {
+	if cmp, err := ValueOrder(left, right); err == nil {
+		return cmp
+	}
+	leftOK, rightOK := TypeStrata(left) != InvalidStrata, TypeStrata(right) != InvalidStrata
+	switch {
+	case leftOK && !rightOK:
+		return -1
+	case !leftOK && rightOK:
+		return 1
+	}
+	if cmp := strings.Compare(fmt.Sprintf("%T", left), fmt.Sprintf("%T", right)); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(fmt.Sprint(left), fmt.Sprint(right))
+}
+
 // GetInt64 extracts an int64 from any integer type.
 // Returns (value, true) if the input is any signed or unsigned integer type.
 // Returns (0, false) if the input is not an integer type or if unsigned values
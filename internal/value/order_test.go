@@ -613,6 +613,37 @@ func TestLess(t *testing.T) {
 	})
 }
 
+func TestCompare(t *testing.T) {
+	t.Run("matches ValueOrder for supported values", func(t *testing.T) {
+		assertEqual(t, 0, value.Compare(int64(1), float64(1)))
+		assertEqual(t, -1, value.Compare("a", "b"))
+		assertEqual(t, 1, value.Compare(int64(2), uint64(1)))
+		assertEqual(t, -1, value.Compare(nil, false))
+	})
+
+	t.Run("unsupported values sort last", func(t *testing.T) {
+		assertEqual(t, -1, value.Compare("z", struct{}{}))
+		assertEqual(t, 1, value.Compare(map[string]any{}, int64(0)))
+	})
+
+	t.Run("unsupported values order by type then text", func(t *testing.T) {
+		type a struct{ N int }
+		type b struct{ N int }
+		assertEqual(t, -1, value.Compare(a{N: 9}, b{N: 1}))
+		assertEqual(t, -1, value.Compare(a{N: 1}, a{N: 2}))
+		assertEqual(t, 0, value.Compare(a{N: 1}, a{N: 1}))
+	})
+
+	t.Run("sorts mixed values", func(t *testing.T) {
+		values := []any{struct{}{}, "b", int64(2), "a", float64(1)}
+		slices.SortStableFunc(values, value.Compare)
+		assertEqual(t, any(float64(1)), values[0])
+		assertEqual(t, any(int64(2)), values[1])
+		assertEqual(t, any("a"), values[2])
+		assertEqual(t, any("b"), values[3])
+	})
+}
+
 func TestGetInt64(t *testing.T) {
 	t.Run("signed integers", func(t *testing.T) {
 		tests := []struct {
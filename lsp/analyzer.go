@@ -74,6 +74,11 @@ type URIDiagnostic struct {
 
 	// Diagnostic is the LSP-formatted diagnostic with 0-based positions.
 	Diagnostic protocol.Diagnostic
+
+	// CodeActions are quick fixes converted from the issue's fixes, each
+	// listing Diagnostic as the diagnostic it resolves. Edit URIs use
+	// canonical paths like URI.
+	CodeActions []protocol.CodeAction
 }
 
 // SymbolIndexAt returns the symbol index for the given source ID.
@@ -382,6 +387,7 @@ func (a *Analyzer) convertDiagnostics(result diag.Result, sources *source.Regist
 		var severity int
 		var code, message string
 		var relatedInfo []protocol.DiagnosticRelatedInformation
+		var actions []diag.LSPCodeAction
 
 		if span.IsZero() {
 			// Span-less issues (e.g., file not found, I/O errors) are attached
@@ -422,19 +428,22 @@ func (a *Analyzer) convertDiagnostics(result diag.Result, sources *source.Regist
 			code = lspDiag.Code
 			message = lspDiag.Message
 			relatedInfo = a.convertRelatedInfo(lspDiag.RelatedInformation)
+			actions = renderer.LSPCodeActions(issue)
 		}
 
 		source := "yammm"
+		lspDiag := protocol.Diagnostic{
+			Range:              diagRange,
+			Severity:           a.convertSeverity(severity),
+			Code:               &protocol.IntegerOrString{Value: code},
+			Source:             &source,
+			Message:            message,
+			RelatedInformation: relatedInfo,
+		}
 		uriDiags = append(uriDiags, URIDiagnostic{
-			URI: uri,
-			Diagnostic: protocol.Diagnostic{
-				Range:              diagRange,
-				Severity:           a.convertSeverity(severity),
-				Code:               &protocol.IntegerOrString{Value: code},
-				Source:             &source,
-				Message:            message,
-				RelatedInformation: relatedInfo,
-			},
+			URI:         uri,
+			Diagnostic:  lspDiag,
+			CodeActions: a.convertCodeActions(actions, lspDiag),
 		})
 	}
 
@@ -505,6 +514,51 @@ func (a *Analyzer) convertRelatedInfo(related []diag.LSPRelatedInfo) []protocol.
 	return result
 }
 
+// convertCodeActions converts diag.LSPCodeAction to protocol.CodeAction.
+// Each action resolves the given diagnostic.
+func (a *Analyzer) convertCodeActions(actions []diag.LSPCodeAction, resolves protocol.Diagnostic) []protocol.CodeAction {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	result := make([]protocol.CodeAction, 0, len(actions))
+	for _, action := range actions {
+		changes := make(map[protocol.DocumentUri][]protocol.TextEdit, len(action.Edit.Changes))
+		for uri, edits := range action.Edit.Changes {
+			// Same double-encoding guard as convertRelatedInfo.
+			if !hasURIScheme(uri) {
+				uri = PathToURI(uri)
+			}
+			for _, e := range edits {
+				changes[uri] = append(changes[uri], protocol.TextEdit{
+					Range: protocol.Range{
+						Start: protocol.Position{
+							Line:      toUInteger(e.Range.Start.Line),
+							Character: toUInteger(e.Range.Start.Character),
+						},
+						End: protocol.Position{
+							Line:      toUInteger(e.Range.End.Line),
+							Character: toUInteger(e.Range.End.Character),
+						},
+					},
+					NewText: e.NewText,
+				})
+			}
+		}
+
+		kind := protocol.CodeActionKind(action.Kind)
+		preferred := action.IsPreferred
+		result = append(result, protocol.CodeAction{
+			Title:       action.Title,
+			Kind:        &kind,
+			Diagnostics: []protocol.Diagnostic{resolves},
+			IsPreferred: &preferred,
+			Edit:        &protocol.WorkspaceEdit{Changes: changes},
+		})
+	}
+	return result
+}
+
 // hasURIScheme reports whether s appears to have a URI scheme prefix.
 // It checks for the common "scheme://" pattern used by hierarchical URIs
 // like file:// and http://. This is used to avoid double-encoding URIs
@@ -15,6 +15,8 @@ import (
 	"time"
 
 	"github.com/simon-lentz/yammm/lsp"
+	"github.com/simon-lentz/yammm/schema/datatypes"
+	"github.com/simon-lentz/yammm/schema/lint"
 )
 
 var version = "dev"
@@ -40,6 +42,27 @@ func isCleanShutdown(err error) bool {
 	return false
 }
 
+// newLinter builds the linter for the -lint and -lint-disable flags.
+// It returns nil when linting is off.
+//
+//nolint:nilnil // nil linter means "linting disabled"
+func newLinter(enabled bool, disabled string) (*lint.Linter, error) {
+	if !enabled {
+		return nil, nil
+	}
+	var names []string
+	for name := range strings.SplitSeq(disabled, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	linter, err := lint.New(lint.WithDisabledRules(names...))
+	if err != nil {
+		return nil, fmt.Errorf("-lint-disable: %w", err)
+	}
+	return linter, nil
+}
+
 func main() {
 	if err := run(os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "yammm-lsp: %v\n", err)
@@ -55,6 +78,9 @@ func run(args []string) error {
 		logLevel   = fs.String("log-level", "info", "log level: error|warn|info|debug|trace")
 		logFile    = fs.String("log-file", "", "log file path (empty to log to stderr)")
 		moduleRoot = fs.String("module-root", "", "override module root for import resolution")
+		lintOn     = fs.Bool("lint", true, "report schema lint findings")
+		lintOff    = fs.String("lint-disable", "", "comma-separated lint rules to disable")
+		dataConfig = fs.String("data-config", "", "JSON file binding instance data files to schemas, for live data statistics")
 		showVer    = fs.Bool("version", false, "print version and exit")
 		_          = fs.Bool("stdio", false, "use stdio transport (default, accepted for VS Code compatibility)")
 	)
@@ -83,6 +109,18 @@ func run(args []string) error {
 		return nil
 	}
 
+	linter, err := newLinter(*lintOn, *lintOff)
+	if err != nil {
+		return err
+	}
+
+	var bindings []lsp.DataBinding
+	if *dataConfig != "" {
+		if bindings, err = lsp.LoadDataBindings(*dataConfig); err != nil {
+			return fmt.Errorf("-data-config: %w", err)
+		}
+	}
+
 	// Set up logging
 	logger, cleanup, err := setupLogger(*logLevel, *logFile)
 	if err != nil {
@@ -130,7 +168,10 @@ func run(args []string) error {
 
 	// Create and configure server
 	cfg := lsp.Config{
-		ModuleRoot: canonicalModuleRoot,
+		ModuleRoot:   canonicalModuleRoot,
+		Linter:       linter,
+		DataBindings: bindings,
+		CustomTypes:  datatypes.NewRegistry(),
 	}
 
 	server := lsp.NewServer(logger, cfg)
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/simon-lentz/yammm/schema/lint"
 )
 
 func TestRun_VersionFlag(t *testing.T) {
@@ -58,6 +60,40 @@ func TestRun_InvalidLogLevel(t *testing.T) {
 	}
 }
 
+func TestRun_UnknownLintRule(t *testing.T) {
+	err := run([]string{"--lint-disable", "no-such-rule"})
+	if !errors.Is(err, lint.ErrUnknownRule) {
+		t.Errorf("run(--lint-disable no-such-rule) = %v; want ErrUnknownRule", err)
+	}
+}
+
+func TestRun_MissingDataConfig(t *testing.T) {
+	err := run([]string{"--data-config", filepath.Join(t.TempDir(), "missing.json")})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("run(--data-config missing.json) = %v; want ErrNotExist", err)
+	}
+}
+
+func TestNewLinter(t *testing.T) {
+	linter, err := newLinter(false, "")
+	if err != nil || linter != nil {
+		t.Errorf("newLinter(false) = %v, %v; want nil linter", linter, err)
+	}
+
+	linter, err = newLinter(true, " missing-documentation, enum-value-case ,")
+	if err != nil {
+		t.Fatalf("newLinter failed: %v", err)
+	}
+	if got, want := len(linter.Rules()), len(lint.Rules())-2; got != want {
+		t.Errorf("enabled rules = %d; want %d", got, want)
+	}
+	for _, r := range linter.Rules() {
+		if r.Name == lint.RuleMissingDocumentation || r.Name == lint.RuleEnumValueCase {
+			t.Errorf("rule %s should be disabled", r.Name)
+		}
+	}
+}
+
 func TestSetupLogger_ValidLevels(t *testing.T) {
 	levels := []string{"error", "warn", "info", "debug", "trace"}
 	for _, level := range levels {
@@ -0,0 +1,401 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	jsonadapter "github.com/simon-lentz/yammm/adapter/json"
+	yamladapter "github.com/simon-lentz/yammm/adapter/yaml"
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/graph"
+	"github.com/simon-lentz/yammm/immutable"
+	"github.com/simon-lentz/yammm/instance"
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema"
+)
+
+// maxDistinctValues bounds the distinct values counted per property.
+// Properties with more values, such as keys, are reported as having more
+// than this many.
+const maxDistinctValues = 100
+
+// DataBinding binds instance data files to a schema file. While the schema
+// is open, the server validates the data against it and shows instance
+// statistics in code lenses and hovers.
+type DataBinding struct {
+	// Schema is the path of the schema file.
+	Schema string `json:"schema"`
+
+	// Data lists data file paths or glob patterns. Files ending in .json
+	// or .jsonc are read with the JSON adapter, files ending in .yaml or
+	// .yml with the YAML adapter; both expect instances grouped by type
+	// name.
+	Data []string `json:"data"`
+}
+
+// LoadDataBindings reads the data bindings of a workspace config file:
+//
+//	{
+//	  "bindings": [
+//	    {"schema": "schemas/orders.yammm", "data": ["data/*.json"]}
+//	  ]
+//	}
+//
+// Relative paths resolve against the directory of the config file.
+func LoadDataBindings(path string) ([]DataBinding, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read data config: %w", err)
+	}
+	var config struct {
+		Bindings []DataBinding `json:"bindings"`
+	}
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("parse data config %s: %w", path, err)
+	}
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("resolve data config directory: %w", err)
+	}
+	abs := func(p string) string {
+		if filepath.IsAbs(p) {
+			return filepath.Clean(p)
+		}
+		return filepath.Join(dir, filepath.FromSlash(p))
+	}
+	for i, b := range config.Bindings {
+		if b.Schema == "" {
+			return nil, fmt.Errorf("data config %s: binding %d has no schema", path, i)
+		}
+		config.Bindings[i].Schema = abs(b.Schema)
+		for j, p := range b.Data {
+			config.Bindings[i].Data[j] = abs(p)
+		}
+	}
+	return config.Bindings, nil
+}
+
+// dataFiles returns the files matching the binding's data patterns, sorted
+// and without duplicates.
+func (b DataBinding) dataFiles() []string {
+	seen := make(map[string]bool)
+	for _, pattern := range b.Data {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue // malformed pattern
+		}
+		for _, m := range matches {
+			seen[m] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// dataFingerprint identifies the state of data files by their paths,
+// sizes and modification times. Statistics are recomputed when it changes.
+func dataFingerprint(files []string) string {
+	var b strings.Builder
+	for _, file := range files {
+		b.WriteString(file)
+		if info, err := os.Stat(file); err == nil {
+			fmt.Fprintf(&b, ":%d:%d", info.Size(), info.ModTime().UnixNano())
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// dataStats holds statistics of the instance data bound to a schema.
+type dataStats struct {
+	files      int // data files matched by the binding
+	unreadable int // files that could not be read or parsed completely
+
+	// types holds per-type statistics keyed by instance tag (the type name
+	// for local types).
+	types map[string]*typeStats
+}
+
+// typeStats holds statistics of the instances of one type.
+type typeStats struct {
+	instances int // instances in the data
+	failing   int // instances rejected by validation or the graph
+
+	// properties holds value statistics of the instances in the graph by
+	// property.
+	properties map[string]*valueStats
+
+	// violations counts the instances violating each invariant, keyed by
+	// invariantKey.
+	violations map[string]int
+}
+
+// valueStats holds the distribution of a property's values.
+type valueStats struct {
+	set      int            // instances with a value
+	counts   map[string]int // occurrences by formatted value
+	overflow bool           // more than maxDistinctValues distinct values
+}
+
+// typ returns the statistics of a type, creating them if needed.
+func (d *dataStats) typ(name string) *typeStats {
+	ts, ok := d.types[name]
+	if !ok {
+		ts = &typeStats{properties: make(map[string]*valueStats), violations: make(map[string]int)}
+		d.types[name] = ts
+	}
+	return ts
+}
+
+// instances returns the total number of instances.
+func (d *dataStats) instances() (total, failing int) {
+	for _, ts := range d.types {
+		total += ts.instances
+		failing += ts.failing
+	}
+	return total, failing
+}
+
+// countViolations counts the invariant failures in result.
+func (ts *typeStats) countViolations(result diag.Result) {
+	for issue := range result.Issues() {
+		if issue.Code() == instance.ErrInvariantFail {
+			ts.violations[issueInvariantKey(issue)]++
+		}
+	}
+}
+
+// sample adds the property values of a valid instance.
+func (ts *typeStats) sample(inst *instance.ValidInstance) {
+	for name, value := range inst.Properties().Range() {
+		if value.IsNil() {
+			continue
+		}
+		vs, ok := ts.properties[name]
+		if !ok {
+			vs = &valueStats{counts: make(map[string]int)}
+			ts.properties[name] = vs
+		}
+		vs.set++
+		text := formatSampleValue(value)
+		if _, seen := vs.counts[text]; !seen && len(vs.counts) >= maxDistinctValues {
+			vs.overflow = true
+			continue
+		}
+		vs.counts[text]++
+	}
+}
+
+// top returns up to n values in order of decreasing frequency, ties broken
+// by value.
+func (vs *valueStats) top(n int) []string {
+	values := slices.Collect(maps.Keys(vs.counts))
+	slices.SortFunc(values, func(a, b string) int {
+		if vs.counts[a] != vs.counts[b] {
+			return vs.counts[b] - vs.counts[a]
+		}
+		return strings.Compare(a, b)
+	})
+	return values[:min(n, len(values))]
+}
+
+// invariantKey identifies an invariant in violation counts: its explicit
+// ID, or its message.
+func invariantKey(inv *schema.Invariant) string {
+	if inv.HasExplicitID() {
+		return "id:" + inv.ID()
+	}
+	return "msg:" + inv.Name()
+}
+
+// issueInvariantKey returns the invariantKey of the invariant an
+// E_INVARIANT_FAIL issue reports.
+func issueInvariantKey(issue diag.Issue) string {
+	for _, d := range issue.Details() {
+		if d.Key == diag.DetailKeyId {
+			return "id:" + d.Value
+		}
+	}
+	return "msg:" + issue.Message()
+}
+
+// formatSampleValue formats a property value for display.
+func formatSampleValue(v immutable.Value) string {
+	if s, ok := v.String(); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v.Unwrap())
+}
+
+// computeDataStats reads the data files, validates their instances against
+// s with instance.Validator, using the custom types, and loads the valid ones into a graph.Graph.
+// Instances are failing if validation rejects them, the graph rejects them
+// as duplicates, or a required association is unresolved.
+func computeDataStats(ctx context.Context, s *schema.Schema, files []string, customTypes *schema.CustomTypeRegistry, logger *slog.Logger) *dataStats {
+	stats := &dataStats{files: len(files), types: make(map[string]*typeStats)}
+
+	raws := make(map[string][]instance.RawInstance)
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return stats
+		}
+		byType, ok := readDataFile(file, logger)
+		if !ok {
+			stats.unreadable++
+		}
+		for name, rs := range byType {
+			raws[name] = append(raws[name], rs...)
+		}
+	}
+
+	validator := instance.NewValidator(s, instance.WithCustomTypes(customTypes))
+	g := graph.New(s)
+	for _, name := range slices.Sorted(maps.Keys(raws)) {
+		ts := stats.typ(name)
+		ts.instances += len(raws[name])
+		valid, failures, err := validator.Validate(ctx, name, raws[name])
+		if err != nil {
+			logger.Debug("data validation stopped", slog.String("type", name), slog.String("error", err.Error()))
+			return stats
+		}
+		ts.failing += len(failures)
+		for _, f := range failures {
+			ts.countViolations(f.Result)
+		}
+		for _, inst := range valid {
+			ts.countViolations(inst.Diagnostics())
+			result, err := g.Add(ctx, inst)
+			if err != nil {
+				logger.Debug("data graph stopped", slog.String("error", err.Error()))
+				return stats
+			}
+			if result.HasErrors() {
+				ts.failing++ // duplicate primary key
+				continue
+			}
+			ts.sample(inst)
+		}
+	}
+
+	if _, err := g.Check(ctx); err != nil {
+		return stats
+	}
+	unresolved := make(map[*graph.Instance]bool)
+	for _, edge := range g.Snapshot().Unresolved() {
+		if edge.Required && !unresolved[edge.Source] {
+			unresolved[edge.Source] = true
+			stats.typ(edge.Source.TypeName()).failing++
+		}
+	}
+	return stats
+}
+
+// readDataFile reads the instances of a data file, grouped by type name.
+// Returns false if the file could not be read or parsed completely.
+func readDataFile(path string, logger *slog.Logger) (map[string][]instance.RawInstance, bool) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		logger.Debug("failed to read data file", slog.String("path", path), slog.String("error", err.Error()))
+		return nil, false
+	}
+	sourceID, err := location.SourceIDFromAbsolutePath(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var byType map[string][]instance.RawInstance
+	var result diag.Result
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".jsonc":
+		adapter, err := jsonadapter.NewAdapter(nil)
+		if err != nil {
+			return nil, false
+		}
+		byType, result = adapter.ParseObject(sourceID, content)
+	case ".yaml", ".yml":
+		adapter, err := yamladapter.NewAdapter(nil)
+		if err != nil {
+			return nil, false
+		}
+		byType, result = adapter.ParseObject(sourceID, content)
+	default:
+		logger.Debug("unsupported data file format", slog.String("path", path))
+		return nil, false
+	}
+	return byType, !result.HasErrors()
+}
+
+// dataStatsEntry holds the statistics computed for one schema and data
+// state. done is closed when stats is set. cancel stops the computation
+// once the entry is replaced by a newer state.
+type dataStatsEntry struct {
+	schema      *schema.Schema
+	fingerprint string
+	cancel      context.CancelFunc
+	done        chan struct{}
+	stats       *dataStats
+}
+
+// dataBinding returns the binding of the schema at the canonical path.
+func (w *Workspace) dataBinding(path string) (DataBinding, bool) {
+	for _, b := range w.config.DataBindings {
+		if b.Schema == path {
+			return b, true
+		}
+	}
+	return DataBinding{}, false
+}
+
+// dataStatsFor returns the statistics of the data bound to the schema at
+// the canonical path, validated against s. They are computed once per
+// schema and data state; concurrent callers wait for the computation, and a
+// new state cancels the computation for the one it replaces. If s is nil,
+// for a schema with errors, the last statistics are returned. Returns nil
+// if no data is bound to the schema or the computation was cancelled.
+func (w *Workspace) dataStatsFor(path string, s *schema.Schema) *dataStats {
+	binding, ok := w.dataBinding(path)
+	if !ok {
+		return nil
+	}
+
+	w.dataMu.Lock()
+	entry := w.dataStats[path]
+	if s == nil {
+		w.dataMu.Unlock()
+		if entry == nil {
+			return nil
+		}
+		<-entry.done
+		return entry.stats
+	}
+	files := binding.dataFiles()
+	fingerprint := dataFingerprint(files)
+	if entry != nil && entry.schema == s && entry.fingerprint == fingerprint {
+		w.dataMu.Unlock()
+		<-entry.done
+		return entry.stats
+	}
+	if entry != nil {
+		entry.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	entry = &dataStatsEntry{schema: s, fingerprint: fingerprint, cancel: cancel, done: make(chan struct{})}
+	w.dataStats[path] = entry
+	w.dataMu.Unlock()
+
+	defer close(entry.done)
+	stats := computeDataStats(ctx, s, files, w.config.CustomTypes, w.logger)
+	if ctx.Err() != nil {
+		return nil // superseded; partial statistics are not kept
+	}
+	entry.stats = stats
+	return stats
+}
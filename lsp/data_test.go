@@ -0,0 +1,199 @@
+package lsp
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/simon-lentz/yammm/schema/load"
+)
+
+// shopSchema is a schema with a required association, an invariant and a
+// property with a small set of values, for data statistics tests.
+const shopSchema = `schema "shop"
+
+type Customer {
+	id String primary
+	tier Enum["gold", "silver"] required
+	age Integer required
+	nickname String
+	! "must be an adult" age >= 18
+}
+
+type Order {
+	id String primary
+	--> buyer (one) Customer
+}
+`
+
+// shopData has five customers, one of them a minor and one a duplicate,
+// and two orders, one of them for a missing customer.
+const shopData = `{
+	"Customer": [
+		{"id": "c1", "tier": "gold", "age": 40, "nickname": "Al"},
+		{"id": "c2", "tier": "silver", "age": 30},
+		{"id": "c3", "tier": "gold", "age": 12},
+		{"id": "c4", "tier": "gold", "age": 20},
+		{"id": "c1", "tier": "silver", "age": 50}
+	],
+	"Order": [
+		{"id": "o1", "buyer": {"_target_id": "c1"}},
+		{"id": "o2", "buyer": {"_target_id": "c9"}}
+	]
+}`
+
+func TestLoadDataBindings(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+	config := `{"bindings": [{"schema": "schemas/shop.yammm", "data": ["data/*.json", "/abs/orders.yaml"]}]}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	bindings, err := LoadDataBindings(path)
+	if err != nil {
+		t.Fatalf("LoadDataBindings() error = %v", err)
+	}
+	if len(bindings) != 1 {
+		t.Fatalf("LoadDataBindings() = %+v; want one binding", bindings)
+	}
+	b := bindings[0]
+	if want := filepath.Join(dir, "schemas", "shop.yammm"); b.Schema != want {
+		t.Errorf("Schema = %q; want %q", b.Schema, want)
+	}
+	want := []string{filepath.Join(dir, "data", "*.json"), filepath.Clean("/abs/orders.yaml")}
+	if len(b.Data) != 2 || b.Data[0] != want[0] || b.Data[1] != want[1] {
+		t.Errorf("Data = %q; want %q", b.Data, want)
+	}
+}
+
+func TestLoadDataBindings_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, config := range map[string]string{
+		"syntax.json":    `{"bindings": [`,
+		"noschema.json":  `{"bindings": [{"data": ["a.json"]}]}`,
+		"wrongtype.json": `{"bindings": {"schema": "a.yammm"}}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if _, err := LoadDataBindings(path); err == nil {
+			t.Errorf("LoadDataBindings(%s) succeeded; want error", name)
+		}
+	}
+}
+
+func TestComputeDataStats(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"shop.json": shopData,
+		"bad.json":  `{"Customer": [`,
+		"notes.txt": "not data",
+	}
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		paths = append(paths, path)
+	}
+
+	s, result, err := load.LoadString(t.Context(), shopSchema, "shop.yammm")
+	if err != nil || result.HasErrors() {
+		t.Fatalf("LoadString() error = %v, %v", err, result)
+	}
+
+	stats := computeDataStats(t.Context(), s, paths, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if stats.files != 3 || stats.unreadable != 2 {
+		t.Errorf("files, unreadable = %d, %d; want 3, 2", stats.files, stats.unreadable)
+	}
+
+	customers := stats.types["Customer"]
+	if customers == nil {
+		t.Fatal("no Customer statistics")
+	}
+	// c3 violates the invariant; the second c1 is a duplicate.
+	if customers.instances != 5 || customers.failing != 2 {
+		t.Errorf("Customer instances, failing = %d, %d; want 5, 2", customers.instances, customers.failing)
+	}
+	if n := customers.violations["msg:must be an adult"]; n != 1 {
+		t.Errorf("invariant violations = %d; want 1", n)
+	}
+	// Value statistics cover the instances in the graph.
+	tier := customers.properties["tier"]
+	if tier == nil || tier.set != 3 || tier.counts[`"gold"`] != 2 || tier.counts[`"silver"`] != 1 {
+		t.Errorf("tier statistics = %+v; want 2 gold and 1 silver", tier)
+	}
+	if nickname := customers.properties["nickname"]; nickname == nil || nickname.set != 1 {
+		t.Errorf("nickname statistics = %+v; want 1 set", nickname)
+	}
+
+	// o2 refers to a missing customer.
+	if orders := stats.types["Order"]; orders == nil || orders.instances != 2 || orders.failing != 1 {
+		t.Errorf("Order statistics = %+v; want 2 instances, 1 failing", orders)
+	}
+}
+
+func TestValueStatsTop(t *testing.T) {
+	t.Parallel()
+
+	vs := &valueStats{counts: map[string]int{`"b"`: 2, `"a"`: 2, `"c"`: 5, `"d"`: 1}}
+	got := vs.top(3)
+	want := []string{`"c"`, `"a"`, `"b"`}
+	if len(got) != len(want) {
+		t.Fatalf("top(3) = %q; want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("top(3)[%d] = %q; want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDataStatsFor_CancelsReplaced(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "shop.yammm")
+	dataPath := filepath.Join(dir, "shop.json")
+	if err := os.WriteFile(dataPath, []byte(shopData), 0o600); err != nil {
+		t.Fatalf("failed to write data: %v", err)
+	}
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{
+		DataBindings: []DataBinding{{Schema: schemaPath, Data: []string{dataPath}}},
+	})
+	w := s.workspace
+
+	old, _, err := load.LoadString(t.Context(), shopSchema, "shop.yammm")
+	if err != nil {
+		t.Fatalf("LoadString() error = %v", err)
+	}
+	cancelled := false
+	stale := &dataStatsEntry{schema: old, cancel: func() { cancelled = true }, done: make(chan struct{})}
+	w.dataStats[schemaPath] = stale
+
+	current, _, err := load.LoadString(t.Context(), shopSchema, "shop.yammm")
+	if err != nil {
+		t.Fatalf("LoadString() error = %v", err)
+	}
+	stats := w.dataStatsFor(schemaPath, current)
+	if !cancelled {
+		t.Error("replaced computation was not cancelled")
+	}
+	if stats == nil || stats.types["Customer"] == nil {
+		t.Fatalf("stats = %+v; want Customer statistics", stats)
+	}
+	if got := w.dataStatsFor(schemaPath, nil); got != stats {
+		t.Errorf("dataStatsFor(nil) = %p; want the current statistics %p", got, stats)
+	}
+}
@@ -2,12 +2,28 @@
 // and YAMMM code blocks embedded in Markdown documents.
 //
 // The LSP server provides IDE features including:
-//   - Real-time diagnostics (parse errors, semantic errors, import issues)
+//   - Real-time diagnostics (parse errors, semantic errors, import issues,
+//     and lint findings when [Config.Linter] is set), honoring
+//     yammm:ignore comments
+//   - Pull diagnostics for clients that request them (LSP 3.17
+//     textDocument/diagnostic), and workspace diagnostics for schema files
+//     that are not open (workspace/diagnostic)
+//   - Quick fix code actions for diagnostics that carry fixes
 //   - Go-to-definition for types, properties, and imports
 //   - Hover information with documentation and constraints
-//   - Completion for keywords, types, and snippets
+//   - Completion for keywords, types, and snippets, import paths of
+//     schema files under the module root, and types from schemas not yet
+//     imported, which adds the import
 //   - Document symbols for outline and breadcrumbs
-//   - Formatting with canonical style (tabs, LF)
+//   - Folding ranges for type bodies, relation bodies, multi-line enum lists,
+//     and doc comments, and selection ranges from the parse tree
+//   - Document links from import paths to the imported files
+//   - Formatting with canonical style (tabs, LF) of documents, ranges, and
+//     blocks as their closing brace is typed, with configurable column
+//     alignment, enum trailing commas, declaration spacing, and import order
+//   - Live instance statistics from bound data: code lenses with instance
+//     and failure counts, and hovers with value samples and invariant
+//     violations
 //
 // The server communicates via JSON-RPC 2.0 over stdio and implements
 // LSP 3.16. It leverages the existing schema/load package for analysis
@@ -16,10 +32,12 @@
 // # Markdown Embedded Blocks
 //
 // YAMMM code blocks in Markdown files (.md, .markdown) receive diagnostics,
-// hover, completion, go-to-definition, and document symbols support. Each
-// code block is analyzed in isolation as an independent schema. Imports are
-// not supported in markdown blocks and produce an E_IMPORT_NOT_ALLOWED
-// diagnostic. Formatting is intentionally disabled for markdown files.
+// hover, completion, go-to-definition, document symbols, folding, selection
+// range, and document link support. Each code block is analyzed in
+// isolation as an independent schema. Imports are not supported in markdown
+// blocks and produce an E_IMPORT_NOT_ALLOWED diagnostic, though their paths
+// still link to the files they name, resolved relative to the markdown file.
+// Formatting is intentionally disabled for markdown files.
 //
 // # Architecture
 //
@@ -27,7 +45,8 @@
 //   - Server: Main LSP server handling protocol lifecycle
 //   - Workspace: Manages open documents, overlays, and analysis snapshots
 //   - Analyzer: Wraps schema/load for import-aware analysis
-//   - Feature providers: Definition, hover, completion, symbols, formatting
+//   - Feature providers: Definition, hover, completion, symbols, formatting,
+//     code actions, folding, selection ranges, document links, code lenses
 //
 // # Usage
 //
@@ -41,11 +60,40 @@
 //
 //	yammm-lsp --log-level debug --log-file /tmp/yammm-lsp.log
 //
+// Lint findings are reported by default. To turn off individual rules or
+// linting entirely:
+//
+//	yammm-lsp --lint-disable missing-documentation,enum-value-case
+//	yammm-lsp --lint=false
+//
+// To see how a schema fits real data, bind instance data files to it in a
+// workspace config file (see [LoadDataBindings]):
+//
+//	yammm-lsp --data-config yammm-data.json
+//
+// While a bound schema is open and free of errors, the server validates the
+// data against it in the background with instance.Validator and loads it
+// into a graph.Graph. Types show lenses with instance and failure counts,
+// properties show their most frequent values on hover, and invariants show
+// how many instances violate them. Statistics are recomputed when the
+// schema is reanalyzed or a data file changes.
+//
+// Formatting options are read from the nearest .yammm-format.json in the
+// directory of a document or its parents, up to the module root, and can
+// be overridden per request through the LSP formatting options (see
+// [FormatOptions]):
+//
+//	{"alignColumns": "types", "sortImports": "path"}
+//
 // # Limitations
 //
 // The server implements LSP 3.16, which does not support position encoding
 // negotiation (added in LSP 3.17). UTF-16 encoding is assumed for all
 // character positions. The glsp library does not yet support LSP 3.17.
+// The 3.17 pull diagnostic requests are handled outside glsp: the server
+// dispatches them itself and adds the diagnosticProvider capability for
+// clients that declare textDocument.diagnostic support. Those clients
+// receive no publishDiagnostics notifications.
 //
 // Documents must be opened (via textDocument/didOpen) before most LSP features
 // work for that document. Specifically, hover, definition, completion, and
@@ -40,13 +40,19 @@ const (
 	spacingNewline
 )
 
-// formatTokenStream applies parse-tree-assisted token-stream formatting.
+// formatTokenStream applies parse-tree-assisted token-stream formatting
+// with the default options.
 // Returns an error if lexing/parsing fails so callers can fall back.
 func formatTokenStream(text string) (string, error) {
+	return formatTokenStreamOptions(text, DefaultFormatOptions())
+}
+
+// formatTokenStreamOptions is formatTokenStream with configurable options.
+func formatTokenStreamOptions(text string, opts FormatOptions) (string, error) {
 	normalized := strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(text)
 
 	input := antlr.NewInputStream(normalized)
-	lexer := grammar.NewYammmGrammarLexer(input)
+	lexer := grammar.NewModifierLexer(input)
 	parseErrs := &parseErrorListener{DefaultErrorListener: &antlr.DefaultErrorListener{}}
 	lexer.RemoveErrorListeners()
 	lexer.AddErrorListener(parseErrs)
@@ -142,7 +148,19 @@ func formatTokenStream(text string) (string, error) {
 		writeText(&out, pendingWS, &lineStart)
 	}
 
-	return finalizeFormattedText(alignColumns(wrapLongLines(collapseBlankLines(out.String())))), nil
+	formatted := collapseBlankLines(out.String())
+	if opts.SortImports != ImportOrderNone {
+		formatted = sortImports(formatted, opts.SortImports)
+	}
+	formatted = wrapLongLines(formatted)
+	if !opts.EnumTrailingCommas {
+		formatted = removeEnumTrailingCommas(formatted)
+	}
+	formatted = alignColumnsMode(formatted, opts.AlignColumns)
+	if opts.BlankLinesBetweenDeclarations > 0 {
+		formatted = spaceDeclarations(formatted, opts.BlankLinesBetweenDeclarations)
+	}
+	return finalizeFormattedText(formatted), nil
 }
 
 type invariantRangeCollector struct {
@@ -262,6 +280,18 @@ func declarationSpacingAction(prev antlr.Token, curr antlr.Token) spacingAction
 	prevType := prev.GetTokenType()
 	currType := curr.GetTokenType()
 
+	// Braces of inline Object types stay on the line unless the source
+	// breaks them: `Object { lat Float required, lon Float required }`.
+	if isStructuredBrace(prev, grammar.YammmGrammarLexerLBRACE) || isStructuredBrace(curr, grammar.YammmGrammarLexerRBRACE) {
+		return spacingSpace
+	}
+	if isStructuredBrace(prev, grammar.YammmGrammarLexerRBRACE) {
+		if currType == grammar.YammmGrammarLexerCOMMA || currType == grammar.YammmGrammarLexerGT {
+			return spacingNone
+		}
+		return spacingSpace
+	}
+
 	if currType == grammar.YammmGrammarLexerRBRACE {
 		return spacingNewline
 	}
@@ -284,6 +314,15 @@ func declarationSpacingAction(prev antlr.Token, curr antlr.Token) spacingAction
 	if prevType == grammar.YammmGrammarLexerEXCLAMATION && currType == grammar.YammmGrammarLexerSTRING {
 		return spacingSpace
 	}
+	if prevType == grammar.YammmGrammarLexerEXCLAMATION && curr.GetChannel() == grammar.InvariantModifierChannel {
+		if isInvariantSeverityKeyword(curr.GetText()) {
+			return spacingNone // !warn
+		}
+		return spacingSpace // ! identifier
+	}
+	if prevType == grammar.YammmGrammarLexerAT && prev.GetChannel() == grammar.MessageVariantChannel {
+		return spacingNone // @de
+	}
 	if prevType == grammar.YammmGrammarLexerASSOC || prevType == grammar.YammmGrammarLexerCOMP {
 		return spacingSpace
 	}
@@ -305,7 +344,7 @@ func declarationSpacingAction(prev antlr.Token, curr antlr.Token) spacingAction
 	if currType == grammar.YammmGrammarLexerCOMMA {
 		return spacingNone
 	}
-	if prevType == grammar.YammmGrammarLexerMINUS {
+	if prevType == grammar.YammmGrammarLexerMINUS && !isClockOffsetSign(prev) {
 		return spacingNone
 	}
 	if prevType == grammar.YammmGrammarLexerLPAR || currType == grammar.YammmGrammarLexerRPAR {
@@ -355,7 +394,7 @@ func isKeywordWithRequiredSpaceAfter(text string) bool {
 
 func isConstraintBracketLeft(text string) bool {
 	switch text {
-	case "Integer", "Float", "String", "Enum", "Pattern", "Timestamp", "Vector", "List":
+	case "Integer", "Float", "String", "Enum", "Pattern", "Timestamp", "Date", "Vector", "List":
 		return true
 	default:
 		return false
@@ -363,9 +402,28 @@ func isConstraintBracketLeft(text string) bool {
 }
 
 // isListAngleBracketLeft returns true if the token text can precede a `<`
-// in List type syntax. Currently only "List" itself uses angle brackets.
+// in type syntax: List, Set and Map use angle brackets.
 func isListAngleBracketLeft(text string) bool {
-	return text == "List"
+	return text == "List" || text == grammar.SetKeyword || text == grammar.MapKeyword
+}
+
+// isStructuredBrace reports whether tok is a brace of type tokenType that
+// belongs to an inline Object type body.
+func isStructuredBrace(tok antlr.Token, tokenType int) bool {
+	return tok.GetChannel() == grammar.StructuredTypeChannel && tok.GetTokenType() == tokenType
+}
+
+// isClockOffsetSign reports whether tok is the '-' of a relative time bound
+// (`now - "24h"`, `today - 30`), which is spaced like a binary operator
+// rather than a negative number's sign.
+func isClockOffsetSign(tok antlr.Token) bool {
+	input := tok.GetInputStream()
+	if input == nil || tok.GetChannel() != grammar.StructuredTypeChannel {
+		return false
+	}
+	start := tok.GetStart()
+	before := strings.TrimRight(input.GetTextFromInterval(antlr.NewInterval(max(0, start-8), start-1)), " \t")
+	return strings.HasSuffix(before, "now") || strings.HasSuffix(before, "today")
 }
 
 func isCommentToken(tokenType int) bool {
@@ -676,10 +734,18 @@ type alignableLine struct {
 
 // alignColumns pads the name column within alignment groups to produce
 // columnar output. Groups are contiguous runs of the same member kind,
-// broken by blank lines, comment-only lines, non-alignable lines, or kind changes.
+// broken by blank lines, comment-only lines, non-alignable lines, kind changes,
+// or indentation changes (fields of a multi-line Object type align separately).
 func alignColumns(text string) string {
-	if text == "" {
-		return ""
+	return alignColumnsMode(text, AlignNames)
+}
+
+// alignColumnsMode is alignColumns with a FormatOptions.AlignColumns mode.
+// AlignTypes also pads the type column of properties, and AlignNone leaves
+// the single spaces of the token stream.
+func alignColumnsMode(text string, mode string) string {
+	if text == "" || mode == AlignNone {
+		return text
 	}
 	lines := strings.Split(text, "\n")
 	result := make([]string, 0, len(lines))
@@ -690,7 +756,7 @@ func alignColumns(text string) string {
 		line := lines[i]
 
 		if isMultilineStart(line) {
-			result = flushAlignGroup(result, group)
+			result = flushAlignGroup(result, group, mode)
 			group = nil
 			result, i = emitMultilineConstruct(result, lines, i)
 			continue
@@ -698,22 +764,22 @@ func alignColumns(text string) string {
 
 		parsed, ok := parseAlignableLine(line)
 		if !ok {
-			result = flushAlignGroup(result, group)
+			result = flushAlignGroup(result, group, mode)
 			group = nil
 			result = append(result, line)
 			i++
 			continue
 		}
 
-		if len(group) > 0 && group[0].kind != parsed.kind {
-			result = flushAlignGroup(result, group)
+		if len(group) > 0 && (group[0].kind != parsed.kind || group[0].indent != parsed.indent) {
+			result = flushAlignGroup(result, group, mode)
 			group = nil
 		}
 
 		group = append(group, parsed)
 		i++
 	}
-	result = flushAlignGroup(result, group)
+	result = flushAlignGroup(result, group, mode)
 	return strings.Join(result, "\n")
 }
 
@@ -843,8 +909,9 @@ func findInlineComment(s string) int {
 }
 
 // flushAlignGroup pads names to a common width and rebuilds each line.
+// With AlignTypes, property types are padded to a common width too.
 // Groups of 0 or 1 members pass through unchanged.
-func flushAlignGroup(result []string, group []alignableLine) []string {
+func flushAlignGroup(result []string, group []alignableLine, mode string) []string {
 	if len(group) <= 1 {
 		for _, al := range group {
 			result = append(result, al.raw)
@@ -859,6 +926,19 @@ func flushAlignGroup(result []string, group []alignableLine) []string {
 		}
 	}
 
+	// With AlignTypes, split property types from their modifiers.
+	types := make([]string, len(group))
+	mods := make([]string, len(group))
+	maxTypeWidth := 0
+	for i, al := range group {
+		types[i] = al.rest
+		if mode != AlignTypes || al.kind != memberProperty {
+			continue
+		}
+		types[i], mods[i] = splitPropertyType(al.rest)
+		maxTypeWidth = max(maxTypeWidth, len(types[i]))
+	}
+
 	type rebuiltLine struct {
 		content string
 		comment string
@@ -876,7 +956,12 @@ func flushAlignGroup(result []string, group []alignableLine) []string {
 			b.WriteString(al.name)
 			b.WriteString(strings.Repeat(" ", maxNameWidth-len(al.name)))
 			b.WriteByte(' ')
-			b.WriteString(al.rest)
+			b.WriteString(types[i])
+			if mods[i] != "" {
+				b.WriteString(strings.Repeat(" ", maxTypeWidth-len(types[i])))
+				b.WriteByte(' ')
+				b.WriteString(mods[i])
+			}
 
 		case memberRelationship:
 			b.WriteString(al.arrow)
@@ -1966,3 +2051,179 @@ func collectMultilineConstruct(lines []string, startIdx int) ([]string, int) {
 	}
 	return collected, i
 }
+
+// isInvariantSeverityKeyword reports whether text is an invariant severity
+// modifier (`!warn`, `!info`, `!error`).
+func isInvariantSeverityKeyword(text string) bool {
+	switch text {
+	case "error", "warn", "info":
+		return true
+	}
+	return false
+}
+
+// =============================================================================
+// Configurable Passes (FormatOptions)
+// =============================================================================
+
+// splitPropertyType splits the rest of a property line after its name into
+// the type and the modifiers that follow it. Spaces inside brackets,
+// angle brackets and strings belong to the type.
+func splitPropertyType(rest string) (typ, mods string) {
+	depth := 0
+	inString := false
+	for i := 0; i < len(rest); i++ {
+		ch := rest[i]
+		if inString {
+			if ch == '\\' && i+1 < len(rest) {
+				i++
+				continue
+			}
+			if ch == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch ch {
+		case '"':
+			inString = true
+		case '[', '<', '(':
+			depth++
+		case ']', '>', ')':
+			depth--
+		case ' ':
+			if depth <= 0 {
+				return rest[:i], strings.TrimLeft(rest[i:], " ")
+			}
+		}
+	}
+	return rest, ""
+}
+
+// sortImports sorts each run of consecutive import lines by path or alias.
+// Comments and blank lines end a run, so a comment stays with the imports
+// around it.
+func sortImports(text string, order string) string {
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); {
+		if !strings.HasPrefix(lines[i], "import ") {
+			i++
+			continue
+		}
+		j := i
+		for j < len(lines) && strings.HasPrefix(lines[j], "import ") {
+			j++
+		}
+		slices.SortStableFunc(lines[i:j], func(a, b string) int {
+			return cmp.Compare(importSortKey(a, order), importSortKey(b, order))
+		})
+		i = j
+	}
+	return strings.Join(lines, "\n")
+}
+
+// importSortKey returns the path or alias of an import line. An import
+// without "as" sorts by the alias derived from its path.
+func importSortKey(line string, order string) string {
+	rest := strings.TrimPrefix(line, "import ")
+	path := rest
+	if strings.HasPrefix(rest, `"`) {
+		if end := strings.IndexByte(rest[1:], '"'); end >= 0 {
+			path = rest[1 : end+1]
+			rest = rest[end+2:]
+		}
+	}
+	if order == ImportOrderPath {
+		return path
+	}
+	if idx := findInlineComment(rest); idx >= 0 {
+		rest = rest[:idx]
+	}
+	if alias, ok := strings.CutPrefix(strings.TrimSpace(rest), "as "); ok {
+		return strings.TrimSpace(alias)
+	}
+	return aliasBase(path)
+}
+
+// removeEnumTrailingCommas removes the comma after the last value of enum
+// lists wrapped over several lines.
+func removeEnumTrailingCommas(text string) string {
+	lines := strings.Split(text, "\n")
+	result := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); {
+		if !isMultilineEnumStart(lines[i]) && !isMultilineDatatypeAliasEnumStart(lines[i]) {
+			result = append(result, lines[i])
+			i++
+			continue
+		}
+		collected, next := collectMultilineConstruct(lines, i)
+		if n := len(collected); n > 2 && strings.HasPrefix(strings.TrimSpace(collected[n-1]), "]") {
+			collected[n-2] = strings.TrimSuffix(collected[n-2], ",")
+		}
+		result = append(result, collected...)
+		i = next
+	}
+	return strings.Join(result, "\n")
+}
+
+// spaceDeclarations puts exactly n blank lines before each top-level type
+// declaration after the first, and before its doc comment. Consecutive
+// single-line aliases are left as they are.
+func spaceDeclarations(text string, n int) string {
+	lines := strings.Split(text, "\n")
+	classes := classifyLines(lines)
+	result := make([]string, 0, len(lines))
+	seenDecl := false
+
+	for i, line := range lines {
+		if classes[i] != lineContent || !isTopLevelDeclaration(line) {
+			result = append(result, line)
+			continue
+		}
+		first := !seenDecl
+		seenDecl = true
+		if first || (i > 0 && classes[i-1] == lineContent && isSingleLineAlias(line) && isSingleLineAlias(lines[i-1])) {
+			result = append(result, line)
+			continue
+		}
+
+		// The doc comment attached to the declaration is already in result;
+		// replace the blank lines before it.
+		commentStart := i
+		for commentStart > 0 && classes[commentStart-1] == lineComment {
+			commentStart--
+		}
+		block := slices.Clone(result[len(result)-(i-commentStart):])
+		result = result[:len(result)-len(block)]
+		for len(result) > 0 && result[len(result)-1] == "" {
+			result = result[:len(result)-1]
+		}
+		for range n {
+			result = append(result, "")
+		}
+		result = append(result, block...)
+		result = append(result, line)
+	}
+	return strings.Join(result, "\n")
+}
+
+// isTopLevelDeclaration reports whether an unindented line starts a type
+// or datatype alias declaration.
+func isTopLevelDeclaration(line string) bool {
+	for _, prefix := range []string{"type ", "abstract ", "part "} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSingleLineAlias reports whether a line is a complete datatype alias.
+func isSingleLineAlias(line string) bool {
+	content := line
+	if idx := findInlineComment(line); idx >= 0 {
+		content = line[:idx]
+	}
+	return strings.HasPrefix(content, "type ") && strings.Contains(content, " = ") &&
+		!strings.Contains(content, "{") && !isMultilineStart(content)
+}
@@ -0,0 +1,166 @@
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// FormatConfigFile is the name of the project formatter config file. The
+// nearest one in the directory of a document or its parents, up to the
+// module root, applies to it.
+const FormatConfigFile = ".yammm-format.json"
+
+// Column alignment modes for [FormatOptions.AlignColumns].
+const (
+	AlignNames = "names" // pad property, relation and alias names
+	AlignTypes = "types" // also pad property types, so modifiers line up
+	AlignNone  = "none"  // single spaces between columns
+)
+
+// Import orders for [FormatOptions.SortImports].
+const (
+	ImportOrderNone  = "none"  // keep imports as written
+	ImportOrderPath  = "path"  // sort by import path
+	ImportOrderAlias = "alias" // sort by alias, explicit or derived
+)
+
+// FormatOptions are the configurable parts of formatting. Everything else
+// (tabs for indentation, token spacing, line wrapping) is canonical.
+//
+// Options are read from the project config file (see [FormatConfigFile]),
+// then from the options of a formatting request, which take precedence.
+// Both use the JSON names of the fields:
+//
+//	{
+//	  "alignColumns": "types",
+//	  "enumTrailingCommas": false,
+//	  "blankLinesBetweenDeclarations": 1,
+//	  "sortImports": "alias"
+//	}
+type FormatOptions struct {
+	// AlignColumns is how consecutive members are aligned: AlignNames,
+	// AlignTypes or AlignNone.
+	AlignColumns string `json:"alignColumns"`
+
+	// EnumTrailingCommas keeps a comma after the last value of enum lists
+	// wrapped over several lines. Single-line lists never have one.
+	EnumTrailingCommas bool `json:"enumTrailingCommas"`
+
+	// BlankLinesBetweenDeclarations, if positive, is the number of blank
+	// lines before each top-level declaration after the first, and its doc
+	// comment. Runs of single-line aliases stay together. Zero keeps the
+	// blank lines as written, at most one.
+	BlankLinesBetweenDeclarations int `json:"blankLinesBetweenDeclarations"`
+
+	// SortImports is the order of each run of consecutive imports:
+	// ImportOrderNone, ImportOrderPath or ImportOrderAlias.
+	SortImports string `json:"sortImports"`
+}
+
+// maxBlankLines bounds FormatOptions.BlankLinesBetweenDeclarations.
+const maxBlankLines = 3
+
+// DefaultFormatOptions returns the options used when none are configured.
+func DefaultFormatOptions() FormatOptions {
+	return FormatOptions{
+		AlignColumns:       AlignNames,
+		EnumTrailingCommas: true,
+		SortImports:        ImportOrderNone,
+	}
+}
+
+// validate reports the first invalid option.
+func (o FormatOptions) validate() error {
+	switch o.AlignColumns {
+	case AlignNames, AlignTypes, AlignNone:
+	default:
+		return fmt.Errorf("alignColumns: unknown mode %q", o.AlignColumns)
+	}
+	switch o.SortImports {
+	case ImportOrderNone, ImportOrderPath, ImportOrderAlias:
+	default:
+		return fmt.Errorf("sortImports: unknown order %q", o.SortImports)
+	}
+	if o.BlankLinesBetweenDeclarations < 0 || o.BlankLinesBetweenDeclarations > maxBlankLines {
+		return fmt.Errorf("blankLinesBetweenDeclarations: %d is not between 0 and %d",
+			o.BlankLinesBetweenDeclarations, maxBlankLines)
+	}
+	return nil
+}
+
+// LoadFormatOptions reads a formatter config file. Options it does not set
+// keep their defaults.
+func LoadFormatOptions(path string) (FormatOptions, error) {
+	opts := DefaultFormatOptions()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return opts, fmt.Errorf("read format config: %w", err)
+	}
+	if err := json.Unmarshal(content, &opts); err != nil {
+		return DefaultFormatOptions(), fmt.Errorf("parse format config %s: %w", path, err)
+	}
+	if err := opts.validate(); err != nil {
+		return DefaultFormatOptions(), fmt.Errorf("format config %s: %w", path, err)
+	}
+	return opts, nil
+}
+
+// withFormattingOptions returns the options overridden by the properties
+// of a formatting request. The standard properties (tabSize, insertSpaces
+// and so on) are ignored; indentation is always tabs. If a property has an
+// invalid value, the options are returned unchanged with an error.
+func (o FormatOptions) withFormattingOptions(options protocol.FormattingOptions) (FormatOptions, error) {
+	opts := o
+	for key, value := range options {
+		var ok bool
+		switch key {
+		case "alignColumns":
+			opts.AlignColumns, ok = value.(string)
+		case "enumTrailingCommas":
+			opts.EnumTrailingCommas, ok = value.(bool)
+		case "blankLinesBetweenDeclarations":
+			var n float64
+			n, ok = value.(float64)
+			opts.BlankLinesBetweenDeclarations = int(n)
+			ok = ok && float64(opts.BlankLinesBetweenDeclarations) == n
+		case "sortImports":
+			opts.SortImports, ok = value.(string)
+		default:
+			continue
+		}
+		if !ok {
+			return o, fmt.Errorf("%s: invalid value %v", key, value)
+		}
+	}
+	if err := opts.validate(); err != nil {
+		return o, err
+	}
+	return opts, nil
+}
+
+// formatOptions returns the options from the formatter config file nearest
+// to the schema file at path, searching up to its module root, or the
+// defaults if there is none or it is invalid.
+func (w *Workspace) formatOptions(path string) FormatOptions {
+	root := w.findModuleRoot(path)
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		config := filepath.Join(dir, FormatConfigFile)
+		opts, err := LoadFormatOptions(config)
+		if err == nil {
+			return opts
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			w.logger.Warn("ignoring format config", slog.String("error", err.Error()))
+			return DefaultFormatOptions()
+		}
+		if dir == root || filepath.Dir(dir) == dir {
+			return DefaultFormatOptions()
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package lsp
+
+import (
+	"slices"
+
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// textDocumentCodeAction handles textDocument/codeAction requests.
+// It returns the quick fixes of diagnostics that overlap the requested range.
+// Fixes are offered for .yammm files only; markdown code blocks are analyzed
+// as fragments and do not get code actions.
+func (s *Server) textDocumentCodeAction(_ *glsp.Context, params *protocol.CodeActionParams) (any, error) {
+	uri := params.TextDocument.URI
+
+	s.logger.Debug("codeAction request", "uri", uri)
+
+	actions := []protocol.CodeAction{}
+	if isMarkdownURI(uri) || !wantsQuickFix(params.Context.Only) {
+		return actions, nil
+	}
+
+	snapshot := s.workspace.LatestSnapshot(uri)
+	if snapshot == nil {
+		return actions, nil
+	}
+
+	for _, d := range snapshot.LSPDiagnostics {
+		if len(d.CodeActions) == 0 || !rangesOverlap(d.Diagnostic.Range, params.Range) {
+			continue
+		}
+		// Diagnostics carry canonical URIs; the client knows the document by
+		// the URI it opened.
+		if s.workspace.RemapPathToURI(d.URI) != uri {
+			continue
+		}
+		for _, action := range d.CodeActions {
+			actions = append(actions, s.remapCodeAction(action))
+		}
+	}
+	return actions, nil
+}
+
+// wantsQuickFix reports whether a code action request filtered by only
+// accepts quick fixes. An empty filter accepts all kinds.
+func wantsQuickFix(only []protocol.CodeActionKind) bool {
+	if len(only) == 0 {
+		return true
+	}
+	return slices.Contains(only, protocol.CodeActionKindQuickFix) ||
+		slices.Contains(only, protocol.CodeActionKindEmpty)
+}
+
+// rangesOverlap reports whether two ranges share at least one position.
+// Ranges that only touch overlap, so a cursor placed right after a
+// diagnostic still gets its fixes.
+func rangesOverlap(a, b protocol.Range) bool {
+	return !lspPositionBefore(a.End, b.Start) && !lspPositionBefore(b.End, a.Start)
+}
+
+// lspPositionBefore reports whether p is strictly before q.
+func lspPositionBefore(p, q protocol.Position) bool {
+	return p.Line < q.Line || (p.Line == q.Line && p.Character < q.Character)
+}
+
+// remapCodeAction returns a copy of action whose edit URIs are remapped to
+// the URIs of open documents.
+func (s *Server) remapCodeAction(action protocol.CodeAction) protocol.CodeAction {
+	if action.Edit == nil {
+		return action
+	}
+	changes := make(map[protocol.DocumentUri][]protocol.TextEdit, len(action.Edit.Changes))
+	for uri, edits := range action.Edit.Changes {
+		remapped := s.workspace.RemapPathToURI(uri)
+		changes[remapped] = append(changes[remapped], edits...)
+	}
+	action.Edit = &protocol.WorkspaceEdit{Changes: changes}
+	return action
+}
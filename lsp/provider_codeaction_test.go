@@ -0,0 +1,92 @@
+package lsp
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+
+	"github.com/simon-lentz/yammm/schema/lint"
+)
+
+func TestTextDocumentCodeAction_LintFix(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if resolved, err := filepath.EvalSymlinks(tmpDir); err == nil {
+		tmpDir = resolved
+	}
+	content := `schema "main"
+
+import "./extra" as extra
+
+type Order {
+	id String primary
+}
+`
+	files := map[string]string{
+		"main.yammm":  content,
+		"extra.yammm": "schema \"extra\"\n\ntype Extra {\n\tid String primary\n}\n",
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(text), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	linter, err := lint.New(lint.WithRules(lint.RuleUnusedImport))
+	if err != nil {
+		t.Fatalf("lint.New failed: %v", err)
+	}
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{ModuleRoot: tmpDir, Linter: linter})
+	uri := PathToURI(filepath.Join(tmpDir, "main.yammm"))
+	s.workspace.DocumentOpened(uri, 1, content)
+	s.workspace.AnalyzeAndPublish(nil, t.Context(), uri)
+
+	request := func(r protocol.Range, only ...protocol.CodeActionKind) []protocol.CodeAction {
+		t.Helper()
+		result, err := s.textDocumentCodeAction(nil, &protocol.CodeActionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Range:        r,
+			Context:      protocol.CodeActionContext{Only: only},
+		})
+		if err != nil {
+			t.Fatalf("codeAction failed: %v", err)
+		}
+		return result.([]protocol.CodeAction)
+	}
+	cursor := func(line, char protocol.UInteger) protocol.Range {
+		p := protocol.Position{Line: line, Character: char}
+		return protocol.Range{Start: p, End: p}
+	}
+
+	actions := request(cursor(2, 5))
+	if len(actions) != 1 {
+		t.Fatalf("actions = %+v; want one fix", actions)
+	}
+	action := actions[0]
+	if action.Title != "Remove unused import" || *action.Kind != protocol.CodeActionKindQuickFix || !*action.IsPreferred {
+		t.Errorf("action = %+v", action)
+	}
+	if len(action.Diagnostics) != 1 || action.Diagnostics[0].Code.Value != "W_UNUSED_IMPORT" {
+		t.Errorf("action diagnostics = %+v", action.Diagnostics)
+	}
+	edits := action.Edit.Changes[uri]
+	want := protocol.TextEdit{Range: protocol.Range{
+		Start: protocol.Position{Line: 2, Character: 0},
+		End:   protocol.Position{Line: 3, Character: 0},
+	}}
+	if len(edits) != 1 || edits[0] != want {
+		t.Errorf("edits = %+v; want %+v", edits, want)
+	}
+
+	if got := request(cursor(5, 1)); len(got) != 0 {
+		t.Errorf("actions away from the diagnostic = %+v; want none", got)
+	}
+	if got := request(cursor(2, 5), protocol.CodeActionKindRefactor); len(got) != 0 {
+		t.Errorf("actions filtered to refactorings = %+v; want none", got)
+	}
+}
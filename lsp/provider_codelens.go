@@ -0,0 +1,166 @@
+package lsp
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+
+	"github.com/simon-lentz/yammm/schema"
+)
+
+// maxSampleValues is the number of most frequent values a property hover
+// lists.
+const maxSampleValues = 5
+
+// textDocumentCodeLens handles textDocument/codeLens requests. Schemas with
+// bound instance data (see [Config.DataBindings]) get a lens summarizing
+// the data on the schema declaration, and one with instance and failure
+// counts on each type. Other documents have no lenses.
+func (s *Server) textDocumentCodeLens(_ *glsp.Context, params *protocol.CodeLensParams) ([]protocol.CodeLens, error) {
+	uri := params.TextDocument.URI
+
+	s.logger.Debug("codeLens request", "uri", uri)
+
+	if isMarkdownURI(uri) {
+		return nil, nil
+	}
+	snapshot := s.workspace.LatestSnapshot(uri)
+	if snapshot == nil {
+		return nil, nil
+	}
+	stats := s.snapshotDataStats(snapshot)
+	if stats == nil {
+		return nil, nil
+	}
+	idx := snapshot.SymbolIndexAt(snapshot.EntrySourceID)
+	if idx == nil {
+		return nil, nil
+	}
+
+	var lenses []protocol.CodeLens
+	for _, sym := range idx.Symbols {
+		var title string
+		switch sym.Kind {
+		case SymbolSchema:
+			total, failing := stats.instances()
+			title = fmt.Sprintf("%s · %s", countNoun(stats.files, "data file"), instanceSummary(total, failing))
+			if stats.unreadable > 0 {
+				title += fmt.Sprintf(" · %d unreadable", stats.unreadable)
+			}
+		case SymbolType:
+			ts := stats.types[sym.Name]
+			if ts == nil {
+				title = "no instances"
+			} else {
+				title = instanceSummary(ts.instances, ts.failing)
+			}
+		default:
+			continue
+		}
+		start, _, ok := SpanToLSPRange(snapshot.Sources, sym.Range, s.workspace.PositionEncoding())
+		if !ok {
+			continue
+		}
+		pos := protocol.Position{Line: toUInteger(start[0]), Character: toUInteger(start[1])}
+		lenses = append(lenses, protocol.CodeLens{
+			Range:   protocol.Range{Start: pos, End: pos},
+			Command: &protocol.Command{Title: title},
+		})
+	}
+	return lenses, nil
+}
+
+// snapshotDataStats returns the statistics of the data bound to a
+// snapshot's entry schema, or nil if no data is bound to it. A snapshot
+// with errors shows the statistics of the last valid schema.
+func (s *Server) snapshotDataStats(snapshot *Snapshot) *dataStats {
+	cp, ok := snapshot.EntrySourceID.CanonicalPath()
+	if !ok {
+		return nil
+	}
+	sch := snapshot.Schema
+	if snapshot.Result.HasErrors() {
+		sch = nil
+	}
+	return s.workspace.dataStatsFor(filepath.FromSlash(cp.String()), sch)
+}
+
+// dataHover returns the hover section on bound instance data for a type,
+// property or invariant declared in the snapshot's entry schema, or "" if
+// there is none.
+func (s *Server) dataHover(sym *Symbol, snapshot *Snapshot) string {
+	if sym.SourceID != snapshot.EntrySourceID {
+		return ""
+	}
+	switch sym.Kind {
+	case SymbolType, SymbolProperty, SymbolInvariant:
+	default:
+		return ""
+	}
+	stats := s.snapshotDataStats(snapshot)
+	if stats == nil {
+		return ""
+	}
+
+	typeName := sym.ParentName
+	if sym.Kind == SymbolType {
+		typeName = sym.Name
+	}
+	ts := stats.types[typeName]
+	if ts == nil {
+		return "\n---\n\n**Data** · no instances\n"
+	}
+
+	var b strings.Builder
+	b.WriteString("\n---\n\n**Data** · ")
+	switch data := sym.Data.(type) {
+	case *schema.Type:
+		b.WriteString(instanceSummary(ts.instances, ts.failing))
+		b.WriteString("\n")
+	case *schema.Property:
+		vs := ts.properties[data.Name()]
+		if vs == nil {
+			fmt.Fprintf(&b, "set in none of %s\n", countNoun(ts.instances, "instance"))
+			break
+		}
+		fmt.Fprintf(&b, "set in %d of %s\n\n", vs.set, countNoun(ts.instances, "instance"))
+		top := vs.top(maxSampleValues)
+		for _, v := range top {
+			fmt.Fprintf(&b, "- `%s` × %d\n", v, vs.counts[v])
+		}
+		switch {
+		case vs.overflow:
+			fmt.Fprintf(&b, "- more than %d distinct values\n", maxDistinctValues)
+		case len(vs.counts) > len(top):
+			fmt.Fprintf(&b, "- %s more\n", countNoun(len(vs.counts)-len(top), "value"))
+		}
+	case *schema.Invariant:
+		if n := ts.violations[invariantKey(data)]; n > 0 {
+			fmt.Fprintf(&b, "violated by %d of %s\n", n, countNoun(ts.instances, "instance"))
+		} else {
+			fmt.Fprintf(&b, "holds for all %s\n", countNoun(ts.instances, "instance"))
+		}
+	default:
+		return ""
+	}
+	return b.String()
+}
+
+// instanceSummary describes an instance count and how many fail.
+func instanceSummary(total, failing int) string {
+	if failing == 0 {
+		return countNoun(total, "instance")
+	}
+	return fmt.Sprintf("%s · %d failing", countNoun(total, "instance"), failing)
+}
+
+// countNoun formats a count with a noun, pluralized with "s".
+func countNoun(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
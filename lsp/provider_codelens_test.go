@@ -0,0 +1,151 @@
+package lsp
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// newDataServer returns a server with shopData bound to shopSchema, with
+// the schema open and analyzed.
+func newDataServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	writeSchemaFiles(t, dir, map[string]string{
+		"shop.yammm":         shopSchema,
+		"data/shop.json":     shopData,
+		"data/more.json":     `{"Customer": [{"id": "c5", "tier": "gold", "age": 70}]}`,
+		"data/ignored.jsonl": `{}`,
+	})
+	path := filepath.Join(dir, "shop.yammm")
+
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{
+		ModuleRoot:   dir,
+		DataBindings: []DataBinding{{Schema: path, Data: []string{filepath.Join(dir, "data", "*.json")}}},
+	})
+	uri := PathToURI(path)
+	s.workspace.DocumentOpened(uri, 1, shopSchema)
+	s.workspace.AnalyzeAndPublish(nil, t.Context(), uri)
+	return s, uri
+}
+
+func TestTextDocumentCodeLens(t *testing.T) {
+	t.Parallel()
+
+	s, uri := newDataServer(t)
+	lenses, err := s.textDocumentCodeLens(nil, &protocol.CodeLensParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+	})
+	if err != nil {
+		t.Fatalf("codeLens failed: %v", err)
+	}
+
+	want := []struct {
+		line  protocol.UInteger
+		title string
+	}{
+		{0, "2 data files · 8 instances · 3 failing"},
+		{2, "6 instances · 2 failing"},
+		{10, "2 instances · 1 failing"},
+	}
+	if len(lenses) != len(want) {
+		t.Fatalf("lenses = %+v; want %d lenses", lenses, len(want))
+	}
+	for i, w := range want {
+		if lenses[i].Range.Start.Line != w.line {
+			t.Errorf("lenses[%d] line = %d; want %d", i, lenses[i].Range.Start.Line, w.line)
+		}
+		if lenses[i].Command == nil || lenses[i].Command.Title != w.title {
+			t.Errorf("lenses[%d].Command = %+v; want title %q", i, lenses[i].Command, w.title)
+		}
+	}
+}
+
+func TestTextDocumentCodeLens_Unbound(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{ModuleRoot: dir})
+	uri := PathToURI(filepath.Join(dir, "shop.yammm"))
+	s.workspace.DocumentOpened(uri, 1, shopSchema)
+	s.workspace.AnalyzeAndPublish(nil, t.Context(), uri)
+
+	lenses, err := s.textDocumentCodeLens(nil, &protocol.CodeLensParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+	})
+	if err != nil || lenses != nil {
+		t.Errorf("codeLens = %+v, %v; want no lenses", lenses, err)
+	}
+}
+
+func TestHover_DataStatistics(t *testing.T) {
+	t.Parallel()
+
+	s, uri := newDataServer(t)
+	tests := []struct {
+		name      string
+		line      int
+		char      int
+		wantParts []string
+	}{
+		{"property", 4, 2, []string{"**Data** · set in 4 of 6 instances", "- `\"gold\"` × 3", "- `\"silver\"` × 1"}},
+		{"invariant", 7, 5, []string{"**Data** · violated by 1 of 6 instances"}},
+		{"type", 2, 6, []string{"**Data** · 6 instances · 2 failing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hover, err := s.textDocumentHover(nil, &protocol.HoverParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+					Position:     protocol.Position{Line: toUInteger(tt.line), Character: toUInteger(tt.char)},
+				},
+			})
+			if err != nil || hover == nil {
+				t.Fatalf("hover = %v, %v; want hover", hover, err)
+			}
+			content, ok := hover.Contents.(protocol.MarkupContent)
+			if !ok {
+				t.Fatalf("hover contents = %T; want MarkupContent", hover.Contents)
+			}
+			for _, part := range tt.wantParts {
+				if !strings.Contains(content.Value, part) {
+					t.Errorf("hover = %q; want it to contain %q", content.Value, part)
+				}
+			}
+		})
+	}
+}
+
+func TestDataStatsFor_DataChange(t *testing.T) {
+	t.Parallel()
+
+	s, uri := newDataServer(t)
+	snapshot := s.workspace.LatestSnapshot(uri)
+	path, _ := URIToPath(uri)
+	before := s.workspace.dataStatsFor(path, snapshot.Schema)
+	if again := s.workspace.dataStatsFor(path, snapshot.Schema); again != before {
+		t.Error("statistics recomputed without a change")
+	}
+
+	more := filepath.Join(filepath.Dir(path), "data", "more.json")
+	if err := os.WriteFile(more, []byte(`{"Customer": []}`), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", more, err)
+	}
+	after := s.workspace.dataStatsFor(path, snapshot.Schema)
+	if after == before || after.types["Customer"].instances != 5 {
+		t.Errorf("statistics after change = %+v; want 5 customers", after.types["Customer"])
+	}
+
+	// A schema with errors keeps the last statistics.
+	if kept := s.workspace.dataStatsFor(path, nil); kept != after {
+		t.Error("statistics for a schema with errors were not the last ones")
+	}
+}
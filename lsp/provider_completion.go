@@ -33,7 +33,10 @@ var builtinTypes = []string{
 	"Float",
 	"Integer",
 	"List",
+	"Map",
+	"Object",
 	"Pattern",
+	"Set",
 	"String",
 	"Timestamp",
 	"UUID",
@@ -142,12 +145,19 @@ func (s *Server) completionAtPosition(snapshot *Snapshot, doc *DocumentSnapshot,
 		items = s.typeBodyCompletions()
 	case ContextExtends:
 		items = s.typeCompletions(snapshot, doc.SourceID)
+		items = append(items, s.autoImportCompletions(doc, false)...)
 	case ContextPropertyType:
 		items = s.propertyTypeCompletions(snapshot, doc.SourceID)
+		items = append(items, s.autoImportCompletions(doc, true)...)
 	case ContextRelationTarget:
 		items = s.typeCompletions(snapshot, doc.SourceID)
+		items = append(items, s.autoImportCompletions(doc, false)...)
 	case ContextImportPath:
-		items = s.importCompletions()
+		if paths, ok := s.importPathCompletions(doc, line, char, byteOffset); ok {
+			items = paths
+		} else {
+			items = s.importCompletions()
+		}
 	default:
 		items = s.topLevelCompletions()
 	}
@@ -684,10 +694,35 @@ func (s *Server) typeBodyCompletions() []protocol.CompletionItem {
 			Detail:   new("Built-in type"),
 		})
 	}
+	items = append(items, s.customTypeCompletions("2_")...)
 
 	return items
 }
 
+// customTypeCompletions returns completions for the Go custom datatypes of
+// the server configuration.
+func (s *Server) customTypeCompletions(sortPrefix string) []protocol.CompletionItem {
+	var items []protocol.CompletionItem
+	for _, ct := range s.config.CustomTypes.Types() {
+		sortText := sortPrefix + ct.Name
+		kind := protocol.CompletionItemKindTypeParameter
+		item := protocol.CompletionItem{
+			Label:    ct.Name,
+			Kind:     &kind,
+			SortText: &sortText,
+			Detail:   new("Custom type"),
+		}
+		if ct.Description != "" {
+			item.Documentation = protocol.MarkupContent{
+				Kind:  protocol.MarkupKindMarkdown,
+				Value: ct.Description,
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 // typeCompletions returns type name completions.
 // sourceID should be the canonical (symlink-resolved) SourceID from the document.
 func (s *Server) typeCompletions(snapshot *Snapshot, sourceID location.SourceID) []protocol.CompletionItem {
@@ -755,6 +790,7 @@ func (s *Server) propertyTypeCompletions(snapshot *Snapshot, sourceID location.S
 			Detail:   new("Built-in type"),
 		})
 	}
+	items = append(items, s.customTypeCompletions("0_")...)
 
 	if snapshot == nil || snapshot.Schema == nil {
 		return items
@@ -0,0 +1,393 @@
+package lsp
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/antlr4-go/antlr/v4"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+
+	"github.com/simon-lentz/yammm/internal/grammar"
+	"github.com/simon-lentz/yammm/schema/load"
+)
+
+// maxSchemaFiles bounds the number of schema files scanned under a module
+// root, so completion stays fast in large trees.
+const maxSchemaFiles = 1000
+
+// schemaDecls holds the top-level declaration names of a schema file.
+type schemaDecls struct {
+	modTime   time.Time
+	size      int64
+	types     []string
+	dataTypes []string
+}
+
+// importPathCompletions returns completions for the path of an import whose
+// opening quote precedes the cursor: schema files relative to the document
+// ("./common/parts") and relative to the module root ("common/parts"), with
+// the .yammm extension left off. Returns false if the cursor is not inside
+// a quoted path.
+//
+// line and char are the cursor's LSP position and byteOffset its byte
+// offset in the line.
+func (s *Server) importPathCompletions(doc *DocumentSnapshot, line, char, byteOffset int) ([]protocol.CompletionItem, bool) {
+	lines := strings.Split(doc.Text, "\n")
+	if line < 0 || line >= len(lines) {
+		return nil, false
+	}
+	lineText := lines[line]
+	byteOffset = min(byteOffset, len(lineText))
+	quote := strings.IndexAny(lineText[:byteOffset], `"'`)
+	if quote < 0 {
+		return nil, false
+	}
+	if isMarkdownURI(doc.URI) {
+		return nil, true // markdown blocks cannot import
+	}
+	path, ok := documentPath(doc.URI)
+	if !ok {
+		return nil, true
+	}
+	moduleRoot := s.workspace.findModuleRoot(path)
+
+	editRange := protocol.Range{
+		Start: protocol.Position{Line: toUInteger(line), Character: toUInteger(s.lineCharacter(lineText, quote+1))},
+		End:   protocol.Position{Line: toUInteger(line), Character: toUInteger(char)},
+	}
+	item := func(label, detail, sortText string) protocol.CompletionItem {
+		kind := protocol.CompletionItemKindFile
+		return protocol.CompletionItem{
+			Label:    label,
+			Kind:     &kind,
+			Detail:   &detail,
+			SortText: &sortText,
+			TextEdit: protocol.TextEdit{Range: editRange, NewText: label},
+		}
+	}
+
+	var items []protocol.CompletionItem
+	for _, file := range findSchemaFiles(moduleRoot) {
+		if file == path {
+			continue
+		}
+		items = append(items, item(relativeImportPath(path, file), "Schema file", "0_"))
+		if rel, err := filepath.Rel(moduleRoot, file); err == nil {
+			items = append(items, item(strings.TrimSuffix(filepath.ToSlash(rel), ".yammm"), "Module path", "1_"))
+		}
+	}
+	for i := range items {
+		*items[i].SortText += items[i].Label
+	}
+	return items, true
+}
+
+// autoImportCompletions returns qualified completions for the types, or
+// with dataTypes the datatypes, of schema files under the module root that
+// the document does not import yet. Accepting one adds the import with an
+// explicit alias.
+func (s *Server) autoImportCompletions(doc *DocumentSnapshot, dataTypes bool) []protocol.CompletionItem {
+	if isMarkdownURI(doc.URI) {
+		return nil // markdown blocks cannot import
+	}
+	path, ok := documentPath(doc.URI)
+	if !ok {
+		return nil
+	}
+	moduleRoot := s.workspace.findModuleRoot(path)
+
+	tree := parseSyntaxTree(doc.Text, s.workspace.PositionEncoding())
+	anchor, hasImports, ok := importAnchor(tree)
+	if !ok {
+		return nil
+	}
+	imported, aliases := existingImports(tree, path, moduleRoot)
+
+	var items []protocol.CompletionItem
+	for _, file := range findSchemaFiles(moduleRoot) {
+		if file == path || imported[file] {
+			continue
+		}
+		decls, ok := s.workspace.schemaDeclarations(file)
+		if !ok {
+			continue
+		}
+		names := decls.types
+		if dataTypes {
+			names = decls.dataTypes
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		importPath := relativeImportPath(path, file)
+		alias := uniqueAlias(importPath, aliases)
+		edit := tree.importEdit(anchor, hasImports, "import "+strconv.Quote(importPath)+" as "+alias)
+		for _, name := range names {
+			label := alias + "." + name
+			sortText := "3_" + label
+			kind := protocol.CompletionItemKindClass
+			if dataTypes {
+				kind = protocol.CompletionItemKindTypeParameter
+			}
+			items = append(items, protocol.CompletionItem{
+				Label:               label,
+				Kind:                &kind,
+				SortText:            &sortText,
+				Detail:              new("Auto-import from " + importPath),
+				AdditionalTextEdits: []protocol.TextEdit{edit},
+			})
+		}
+	}
+	return items
+}
+
+// lineCharacter converts a byte offset in a line to an LSP character
+// offset in the negotiated encoding.
+func (s *Server) lineCharacter(lineText string, byteOffset int) int {
+	if s.workspace.PositionEncoding() == PositionEncodingUTF8 {
+		return byteOffset
+	}
+	return ByteToUTF16Offset([]byte(lineText), 0, byteOffset)
+}
+
+// documentPath returns the canonical file path of a document URI, as used
+// for analysis.
+func documentPath(uri string) (string, bool) {
+	path, err := URIToPath(uri)
+	if err != nil {
+		return "", false
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = filepath.Clean(resolved)
+	}
+	return path, true
+}
+
+// relativeImportPath returns the relative import path ("./parts",
+// "../common/types") from the schema at from to the schema file at to.
+func relativeImportPath(from, to string) string {
+	rel, err := filepath.Rel(filepath.Dir(from), to)
+	if err != nil {
+		return filepath.ToSlash(to)
+	}
+	rel = strings.TrimSuffix(filepath.ToSlash(rel), ".yammm")
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
+// findSchemaFiles returns the canonical paths of the .yammm files under
+// root in lexical order, skipping hidden directories. At most
+// maxSchemaFiles paths are returned.
+func findSchemaFiles(root string) []string {
+	var files []string
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error { //nolint:errcheck // best-effort scan
+		if err != nil {
+			return nil //nolint:nilerr // skip unreadable entries
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".yammm" {
+			return nil
+		}
+		files = append(files, path)
+		if len(files) >= maxSchemaFiles {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return files
+}
+
+// schemaDeclarations returns the type and datatype names declared by the
+// schema file at path. Open documents are read from their current text;
+// other files from disk, cached until they change.
+func (w *Workspace) schemaDeclarations(path string) (schemaDecls, bool) {
+	if text, ok := w.openDocumentText(path); ok {
+		return declarationNames(text), true
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return schemaDecls{}, false
+	}
+	w.declMu.Lock()
+	cached, ok := w.declCache[path]
+	w.declMu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached, true
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return schemaDecls{}, false
+	}
+	decls := declarationNames(string(content))
+	decls.modTime, decls.size = info.ModTime(), info.Size()
+	w.declMu.Lock()
+	w.declCache[path] = decls
+	w.declMu.Unlock()
+	return decls, true
+}
+
+// openDocumentText returns the text of the open document for a canonical
+// file path.
+func (w *Workspace) openDocumentText(path string) (string, bool) {
+	key := filepath.ToSlash(path)
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, doc := range w.open {
+		if doc.SourceID.String() == key {
+			return doc.Text, true
+		}
+	}
+	return "", false
+}
+
+// declarationNames returns the type and datatype names declared in text,
+// sorted.
+func declarationNames(text string) schemaDecls {
+	var decls schemaDecls
+	walkSyntax(parseSyntaxTree(text, PositionEncodingUTF16).root, func(node antlr.Tree) bool {
+		switch n := node.(type) {
+		case *grammar.TypeContext:
+			if name := n.Type_name(); name != nil {
+				decls.types = append(decls.types, name.GetText())
+			}
+			return false
+		case *grammar.DatatypeContext:
+			if name := n.Type_name(); name != nil {
+				decls.dataTypes = append(decls.dataTypes, name.GetText())
+			}
+			return false
+		}
+		return true
+	})
+	slices.Sort(decls.types)
+	slices.Sort(decls.dataTypes)
+	return decls
+}
+
+// existingImports returns the files the document's imports resolve to and
+// the aliases they use. Explicit aliases are taken as written; derived ones
+// as the last path segment.
+func existingImports(tree *syntaxTree, path, moduleRoot string) (files, aliases map[string]bool) {
+	files, aliases = make(map[string]bool), make(map[string]bool)
+	walkSyntax(tree.root, func(node antlr.Tree) bool {
+		imp, ok := node.(*grammar.Import_declContext)
+		if !ok {
+			_, isSchema := node.(*grammar.SchemaContext)
+			return isSchema
+		}
+		importPath, ok := importDeclPath(imp)
+		if !ok {
+			return false
+		}
+		if a := imp.GetAlias(); a != nil {
+			aliases[a.GetText()] = true
+		} else {
+			aliases[aliasBase(importPath)] = true
+		}
+		if target, err := load.ResolveImport(path, importPath, moduleRoot); err == nil {
+			files[target] = true
+		}
+		return false
+	})
+	return files, aliases
+}
+
+// importAnchor returns the token after which a new import goes: the last
+// import, or the schema declaration if there are none. Returns false if the
+// document has no schema declaration.
+func importAnchor(tree *syntaxTree) (antlr.Token, bool, bool) {
+	var anchor antlr.Token
+	hasImports := false
+	for i := range tree.root.GetChildCount() {
+		switch n := tree.root.GetChild(i).(type) {
+		case *grammar.Schema_nameContext:
+			if tok := n.GetStop(); isRealToken(tok) {
+				anchor = tok
+			}
+		case *grammar.Import_declContext:
+			if tok := n.GetStop(); isRealToken(tok) {
+				anchor, hasImports = tok, true
+			}
+		}
+	}
+	return anchor, hasImports, anchor != nil
+}
+
+// importEdit returns the edit inserting decl on its own line after anchor.
+// The first import is separated from the schema declaration by a blank
+// line.
+func (t *syntaxTree) importEdit(anchor antlr.Token, hasImports bool, decl string) protocol.TextEdit {
+	text := decl + "\n"
+	if !hasImports {
+		text = "\n" + text
+	}
+	line := tokenEnd(anchor).line
+	if line+1 < len(t.lines) {
+		pos := protocol.Position{Line: toUInteger(line + 1)}
+		return protocol.TextEdit{Range: protocol.Range{Start: pos, End: pos}, NewText: text}
+	}
+	// The anchor is on the last line, which has no newline to insert after.
+	pos := t.lspPosition(textPos{line: line, col: utf8.RuneCountInString(t.lines[line])})
+	return protocol.TextEdit{Range: protocol.Range{Start: pos, End: pos}, NewText: "\n" + strings.TrimSuffix(text, "\n")}
+}
+
+// uniqueAlias returns an alias for importPath that is a valid identifier,
+// not a keyword, and not in used, which it is added to.
+func uniqueAlias(importPath string, used map[string]bool) string {
+	base := aliasBase(importPath)
+	alias := base
+	for n := 2; used[alias] || !isAliasName(alias); n++ {
+		alias = base + strconv.Itoa(n)
+	}
+	used[alias] = true
+	return alias
+}
+
+// aliasBase returns the identifier derived from the last segment of an
+// import path, as the loader derives default aliases: other characters
+// become underscores, and a name not starting with a letter gets an "n"
+// prefix.
+func aliasBase(importPath string) string {
+	base := strings.TrimRight(importPath, "/")
+	base = strings.TrimSuffix(base[strings.LastIndex(base, "/")+1:], ".yammm")
+	var sb strings.Builder
+	for _, r := range base {
+		if isLetter(r) || isDigit(r) || r == '_' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+	alias := sb.String()
+	if alias == "" || !isLetter(rune(alias[0])) {
+		alias = "n" + alias
+	}
+	return alias
+}
+
+// isAliasName reports whether name lexes as a single identifier token, so
+// it can qualify type references. Keywords lex as their own tokens.
+func isAliasName(name string) bool {
+	lexer := grammar.NewYammmGrammarLexer(antlr.NewInputStream(name))
+	lexer.RemoveErrorListeners()
+	tok := lexer.NextToken()
+	tt := tok.GetTokenType()
+	return (tt == grammar.YammmGrammarLexerLC_WORD || tt == grammar.YammmGrammarLexerUC_WORD) &&
+		tok.GetText() == name
+}
@@ -0,0 +1,230 @@
+package lsp
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// writeSchemaFiles writes files relative to root, creating directories.
+func writeSchemaFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, text := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("mkdir failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+}
+
+// completionItems requests completion at a position and fails the test if
+// the result is not a list of items.
+func completionItems(t *testing.T, s *Server, uri string, line, char protocol.UInteger) []protocol.CompletionItem {
+	t.Helper()
+	result, err := s.textDocumentCompletion(nil, &protocol.CompletionParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     protocol.Position{Line: line, Character: char},
+		},
+	})
+	if err != nil {
+		t.Fatalf("completion failed: %v", err)
+	}
+	items, ok := result.([]protocol.CompletionItem)
+	if !ok {
+		t.Fatalf("completion result = %T; want []protocol.CompletionItem", result)
+	}
+	return items
+}
+
+func findCompletion(items []protocol.CompletionItem, label string) *protocol.CompletionItem {
+	for i := range items {
+		if items[i].Label == label {
+			return &items[i]
+		}
+	}
+	return nil
+}
+
+func TestCompletion_ImportPaths(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if resolved, err := filepath.EvalSymlinks(tmpDir); err == nil {
+		tmpDir = resolved
+	}
+	writeSchemaFiles(t, tmpDir, map[string]string{
+		"common/parts.yammm":   `schema "parts"`,
+		"common/notes.txt":     "not a schema",
+		".hidden/skip.yammm":   `schema "skip"`,
+		"models/orders.yammm":  `schema "orders"`,
+		"models/sibling.yammm": `schema "sibling"`,
+	})
+
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{ModuleRoot: tmpDir})
+	uri := PathToURI(filepath.Join(tmpDir, "models", "orders.yammm"))
+	s.workspace.DocumentOpened(uri, 1, "schema \"orders\"\n\nimport \"../co\n")
+
+	items := completionItems(t, s, uri, 2, 12)
+	var labels []string
+	for _, item := range items {
+		labels = append(labels, item.Label)
+	}
+	// Relative paths sort before module paths; the document itself, hidden
+	// directories and other files are left out.
+	want := []string{"../common/parts", "./sibling", "common/parts", "models/sibling"}
+	if len(labels) != len(want) {
+		t.Fatalf("labels = %q; want %q", labels, want)
+	}
+	for i := range want {
+		if labels[i] != want[i] {
+			t.Errorf("labels[%d] = %q; want %q", i, labels[i], want[i])
+		}
+	}
+
+	// The edit replaces the typed part of the path.
+	edit, ok := items[0].TextEdit.(protocol.TextEdit)
+	if !ok {
+		t.Fatalf("TextEdit = %T; want protocol.TextEdit", items[0].TextEdit)
+	}
+	wantRange := protocol.Range{
+		Start: protocol.Position{Line: 2, Character: 8},
+		End:   protocol.Position{Line: 2, Character: 12},
+	}
+	if edit.Range != wantRange || edit.NewText != "../common/parts" {
+		t.Errorf("TextEdit = %+v; want %q over %+v", edit, "../common/parts", wantRange)
+	}
+}
+
+func TestCompletion_ImportKeywordWithoutQuote(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{ModuleRoot: tmpDir})
+	uri := PathToURI(filepath.Join(tmpDir, "main.yammm"))
+	s.workspace.DocumentOpened(uri, 1, "schema \"main\"\n\nimport \n")
+
+	items := completionItems(t, s, uri, 2, 7)
+	if len(items) != 1 || items[0].Label != "import" {
+		t.Errorf("items = %+v; want the import snippet", items)
+	}
+}
+
+func TestCompletion_AutoImport(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if resolved, err := filepath.EvalSymlinks(tmpDir); err == nil {
+		tmpDir = resolved
+	}
+	writeSchemaFiles(t, tmpDir, map[string]string{
+		"common/parts.yammm": "schema \"parts\"\n\ntype Wheel { id String primary }\ntype Bolt { id String primary }\n" +
+			"type Money = Float[0, _]\n",
+		"common/money.yammm": "schema \"money\"\n\ntype Amount = Float[0, _]\n",
+		// Named after a keyword, so the derived alias gets a suffix.
+		"types/type.yammm": "schema \"type\"\n\ntype Tag { id String primary }\n",
+	})
+
+	content := "schema \"main\"\n\nimport \"./common/money\" as money\n\n" +
+		"type Car {\n\t--> wheels (many)\n\tprice \n}\n"
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{ModuleRoot: tmpDir})
+	uri := PathToURI(filepath.Join(tmpDir, "main.yammm"))
+	s.workspace.DocumentOpened(uri, 1, content)
+
+	items := completionItems(t, s, uri, 5, 19)
+	wheel := findCompletion(items, "parts.Wheel")
+	if wheel == nil {
+		t.Fatalf("no parts.Wheel in %+v", items)
+	}
+	wantEdit := protocol.TextEdit{
+		Range: protocol.Range{
+			Start: protocol.Position{Line: 3},
+			End:   protocol.Position{Line: 3},
+		},
+		NewText: "import \"./common/parts\" as parts\n",
+	}
+	if len(wheel.AdditionalTextEdits) != 1 || wheel.AdditionalTextEdits[0] != wantEdit {
+		t.Errorf("AdditionalTextEdits = %+v; want %+v", wheel.AdditionalTextEdits, wantEdit)
+	}
+	if findCompletion(items, "type2.Tag") == nil {
+		t.Errorf("no type2.Tag in %+v", items)
+	}
+	// Datatypes are not relation targets, and money is already imported.
+	for _, label := range []string{"parts.Money", "money.Amount"} {
+		if findCompletion(items, label) != nil {
+			t.Errorf("unexpected completion %q", label)
+		}
+	}
+
+	items = completionItems(t, s, uri, 6, 7)
+	if findCompletion(items, "parts.Money") == nil {
+		t.Errorf("no parts.Money in property type completions")
+	}
+	if findCompletion(items, "parts.Wheel") != nil {
+		t.Errorf("unexpected type parts.Wheel in property type completions")
+	}
+}
+
+func TestCompletion_AutoImportFirstImport(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if resolved, err := filepath.EvalSymlinks(tmpDir); err == nil {
+		tmpDir = resolved
+	}
+	writeSchemaFiles(t, tmpDir, map[string]string{
+		"parts.yammm": "schema \"parts\"\n\ntype Wheel { id String primary }\n",
+	})
+
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{ModuleRoot: tmpDir})
+	uri := PathToURI(filepath.Join(tmpDir, "main.yammm"))
+	s.workspace.DocumentOpened(uri, 1, "schema \"main\"\n\ntype Car extends \n")
+
+	wheel := findCompletion(completionItems(t, s, uri, 2, 17), "parts.Wheel")
+	if wheel == nil {
+		t.Fatal("no parts.Wheel completion")
+	}
+	// The first import is separated from the schema declaration.
+	want := protocol.TextEdit{
+		Range: protocol.Range{
+			Start: protocol.Position{Line: 1},
+			End:   protocol.Position{Line: 1},
+		},
+		NewText: "\nimport \"./parts\" as parts\n",
+	}
+	if len(wheel.AdditionalTextEdits) != 1 || wheel.AdditionalTextEdits[0] != want {
+		t.Errorf("AdditionalTextEdits = %+v; want %+v", wheel.AdditionalTextEdits, want)
+	}
+}
+
+func TestSchemaDeclarations_Cache(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "parts.yammm")
+	writeSchemaFiles(t, tmpDir, map[string]string{
+		"parts.yammm": "schema \"parts\"\n\ntype Wheel { id String primary }\n",
+	})
+
+	w := NewWorkspace(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{ModuleRoot: tmpDir})
+	decls, ok := w.schemaDeclarations(path)
+	if !ok || len(decls.types) != 1 || decls.types[0] != "Wheel" {
+		t.Fatalf("schemaDeclarations() = %+v, %v; want type Wheel", decls, ok)
+	}
+
+	// A change in size invalidates the cached entry.
+	writeSchemaFiles(t, tmpDir, map[string]string{
+		"parts.yammm": "schema \"parts\"\n\ntype Wheel { id String primary }\ntype Axle { id String primary }\n",
+	})
+	decls, ok = w.schemaDeclarations(path)
+	if !ok || len(decls.types) != 2 || decls.types[0] != "Axle" {
+		t.Errorf("schemaDeclarations() after change = %+v, %v; want types Axle, Wheel", decls, ok)
+	}
+}
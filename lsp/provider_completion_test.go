@@ -13,6 +13,7 @@ import (
 
 	"github.com/simon-lentz/yammm/location"
 	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/datatypes"
 )
 
 // textToDoc creates a minimal DocumentSnapshot for testing detectCompletionContext.
@@ -1549,3 +1550,27 @@ func TestComputeByteOffsetFromText_UTF8Mode_MultiByteChars(t *testing.T) {
 		}
 	})
 }
+
+func TestPropertyTypeCompletions_CustomTypes(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{config: Config{CustomTypes: datatypes.NewRegistry()}}
+	items := s.propertyTypeCompletions(nil, location.SourceID{})
+
+	var email *protocol.CompletionItem
+	for i := range items {
+		if items[i].Label == "Email" {
+			email = &items[i]
+		}
+	}
+	if email == nil {
+		t.Fatal("missing Email custom type")
+	}
+	if email.Detail == nil || *email.Detail != "Custom type" {
+		t.Errorf("Detail = %v, want Custom type", email.Detail)
+	}
+	doc, ok := email.Documentation.(protocol.MarkupContent)
+	if !ok || !strings.Contains(doc.Value, "email address") {
+		t.Errorf("Documentation = %v, want custom type description", email.Documentation)
+	}
+}
@@ -0,0 +1,226 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// Pull diagnostics are part of LSP 3.17, which glsp does not implement.
+// The methods and types below follow the 3.17 specification; the
+// requests are dispatched by pullHandler.
+const (
+	methodTextDocumentDiagnostic = "textDocument/diagnostic"
+	methodWorkspaceDiagnostic    = "workspace/diagnostic"
+)
+
+// Document diagnostic report kinds.
+const (
+	reportKindFull      = "full"
+	reportKindUnchanged = "unchanged"
+)
+
+// diagnosticOptions is the diagnosticProvider server capability.
+type diagnosticOptions struct {
+	Identifier            string `json:"identifier,omitempty"`
+	InterFileDependencies bool   `json:"interFileDependencies"`
+	WorkspaceDiagnostics  bool   `json:"workspaceDiagnostics"`
+}
+
+// serverCapabilities extends the 3.16 server capabilities with the
+// diagnosticProvider capability.
+type serverCapabilities struct {
+	protocol.ServerCapabilities
+	DiagnosticProvider *diagnosticOptions `json:"diagnosticProvider,omitempty"`
+}
+
+// initializeResult is the initialize result for clients that pull
+// diagnostics.
+type initializeResult struct {
+	Capabilities serverCapabilities                   `json:"capabilities"`
+	ServerInfo   *protocol.InitializeResultServerInfo `json:"serverInfo,omitempty"`
+}
+
+// documentDiagnosticParams are the parameters of a textDocument/diagnostic
+// request.
+type documentDiagnosticParams struct {
+	TextDocument     protocol.TextDocumentIdentifier `json:"textDocument"`
+	Identifier       *string                         `json:"identifier,omitempty"`
+	PreviousResultID *string                         `json:"previousResultId,omitempty"`
+}
+
+// documentDiagnosticReport is a full or unchanged diagnostic report. Items
+// are omitted from unchanged reports.
+type documentDiagnosticReport struct {
+	Kind     string                `json:"kind"`
+	ResultID string                `json:"resultId,omitempty"`
+	Items    []protocol.Diagnostic `json:"items,omitempty"`
+}
+
+// previousResultID is a result ID the client holds for a document.
+type previousResultID struct {
+	URI   protocol.DocumentUri `json:"uri"`
+	Value string               `json:"value"`
+}
+
+// workspaceDiagnosticParams are the parameters of a workspace/diagnostic
+// request.
+type workspaceDiagnosticParams struct {
+	Identifier        *string            `json:"identifier,omitempty"`
+	PreviousResultIDs []previousResultID `json:"previousResultIds"`
+}
+
+// workspaceDocumentDiagnosticReport is a document report in a workspace
+// diagnostic report. Version is null because the document is not open.
+type workspaceDocumentDiagnosticReport struct {
+	documentDiagnosticReport
+	URI     protocol.DocumentUri `json:"uri"`
+	Version *protocol.Integer    `json:"version"`
+}
+
+// workspaceDiagnosticReport is the result of a workspace/diagnostic
+// request.
+type workspaceDiagnosticReport struct {
+	Items []workspaceDocumentDiagnosticReport `json:"items"`
+}
+
+// newDiagnosticReport returns an unchanged report if the client holds the
+// result ID of items, and a full report otherwise.
+func newDiagnosticReport(items []protocol.Diagnostic, resultID, previous string) documentDiagnosticReport {
+	if resultID != "" && resultID == previous {
+		return documentDiagnosticReport{Kind: reportKindUnchanged, ResultID: resultID}
+	}
+	if items == nil {
+		items = []protocol.Diagnostic{}
+	}
+	return documentDiagnosticReport{Kind: reportKindFull, ResultID: resultID, Items: items}
+}
+
+// pullHandler dispatches the 3.17 diagnostic requests and passes the others
+// to the 3.16 handler. On initialize it records whether the client pulls
+// diagnostics, which glsp's InitializeParams cannot express.
+type pullHandler struct {
+	*protocol.Handler
+	server *Server
+}
+
+// Handle implements glsp.Handler.
+func (h pullHandler) Handle(ctx *glsp.Context) (r any, validMethod bool, validParams bool, err error) {
+	switch ctx.Method {
+	case protocol.MethodInitialize:
+		h.server.workspace.SetPullDiagnostics(clientPullsDiagnostics(ctx.Params))
+
+	case methodTextDocumentDiagnostic:
+		if !h.IsInitialized() {
+			break
+		}
+		var params documentDiagnosticParams
+		if err = json.Unmarshal(ctx.Params, &params); err == nil {
+			r, err = h.server.textDocumentDiagnostic(ctx, &params)
+			return r, true, true, err
+		}
+		return nil, true, false, err
+
+	case methodWorkspaceDiagnostic:
+		if !h.IsInitialized() {
+			break
+		}
+		var params workspaceDiagnosticParams
+		if err = json.Unmarshal(ctx.Params, &params); err == nil {
+			r, err = h.server.workspaceDiagnostic(ctx, &params)
+			return r, true, true, err
+		}
+		return nil, true, false, err
+	}
+	return h.Handler.Handle(ctx)
+}
+
+// clientPullsDiagnostics reports whether initialize parameters declare the
+// textDocument.diagnostic client capability.
+func clientPullsDiagnostics(raw json.RawMessage) bool {
+	var params struct {
+		Capabilities struct {
+			TextDocument struct {
+				Diagnostic *json.RawMessage `json:"diagnostic"`
+			} `json:"textDocument"`
+		} `json:"capabilities"`
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return false
+	}
+	return params.Capabilities.TextDocument.Diagnostic != nil
+}
+
+// textDocumentDiagnostic handles textDocument/diagnostic requests. The
+// document is analyzed before the report is made, so it reflects the
+// current content of the document and of the open documents it imports.
+// Closed schema files are reported from the last workspace analysis.
+func (s *Server) textDocumentDiagnostic(_ *glsp.Context, params *documentDiagnosticParams) (any, error) {
+	uri := params.TextDocument.URI
+	previous := ""
+	if params.PreviousResultID != nil {
+		previous = *params.PreviousResultID
+	}
+
+	s.logger.Debug("diagnostic request", slog.String("uri", uri))
+
+	var items []protocol.Diagnostic
+	switch {
+	case isMarkdownURI(uri):
+		s.workspace.AnalyzeMarkdownAndPublish(nil, context.Background(), uri)
+		if snap := s.workspace.GetMarkdownDocumentSnapshot(uri); snap != nil {
+			items = s.workspace.markdownDiagnostics(snap)
+		}
+	case s.workspace.GetDocumentSnapshot(uri) != nil:
+		s.workspace.AnalyzeAndPublish(nil, context.Background(), uri)
+		items = s.workspace.snapshotDiagnostics(uri)
+	default:
+		items = s.workspace.closedFileDiagnostics(uri)
+	}
+	return newDiagnosticReport(items, diagnosticsResultID(items), previous), nil
+}
+
+// workspaceDiagnostic handles workspace/diagnostic requests. It analyzes
+// the schema files in the workspace that are not open and reports each,
+// as unchanged if the client holds its current result ID. Files the client
+// holds results for that no longer exist get an empty report, so their
+// diagnostics are cleared.
+func (s *Server) workspaceDiagnostic(_ *glsp.Context, params *workspaceDiagnosticParams) (any, error) {
+	s.workspace.AnalyzeWorkspace(context.Background())
+
+	previous := make(map[string]string, len(params.PreviousResultIDs))
+	for _, p := range params.PreviousResultIDs {
+		previous[p.URI] = p.Value
+	}
+
+	report := workspaceDiagnosticReport{Items: []workspaceDocumentDiagnosticReport{}}
+	reported := make(map[string]bool)
+	for _, fd := range s.workspace.workspaceFileDiagnostics() {
+		reported[fd.uri] = true
+		report.Items = append(report.Items, workspaceDocumentDiagnosticReport{
+			documentDiagnosticReport: newDiagnosticReport(fd.items, fd.resultID, previous[fd.uri]),
+			URI:                      fd.uri,
+		})
+	}
+	for _, p := range params.PreviousResultIDs {
+		if reported[p.URI] || !isYammmURI(p.URI) {
+			continue
+		}
+		path, err := URIToPath(p.URI)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			reported[p.URI] = true
+			report.Items = append(report.Items, workspaceDocumentDiagnosticReport{
+				documentDiagnosticReport: newDiagnosticReport(nil, "", ""),
+				URI:                      p.URI,
+			})
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,191 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// newPullServer returns a server for a temporary workspace with the given
+// schema files, whose client pulls diagnostics.
+func newPullServer(t *testing.T, files map[string]string) (*Server, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	writeSchemaFiles(t, dir, files)
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{})
+	s.workspace.AddRoot(PathToURI(dir))
+	s.workspace.SetPullDiagnostics(true)
+	return s, dir
+}
+
+// workspaceReports requests workspace diagnostics and returns the reports
+// by URI.
+func workspaceReports(t *testing.T, s *Server, previous []previousResultID) map[string]workspaceDocumentDiagnosticReport {
+	t.Helper()
+	result, err := s.workspaceDiagnostic(nil, &workspaceDiagnosticParams{PreviousResultIDs: previous})
+	if err != nil {
+		t.Fatalf("workspaceDiagnostic failed: %v", err)
+	}
+	report, ok := result.(workspaceDiagnosticReport)
+	if !ok {
+		t.Fatalf("workspaceDiagnostic = %T; want workspaceDiagnosticReport", result)
+	}
+	byURI := make(map[string]workspaceDocumentDiagnosticReport, len(report.Items))
+	for _, item := range report.Items {
+		byURI[item.URI] = item
+	}
+	return byURI
+}
+
+const (
+	partsSchema  = "schema \"parts\"\n\ntype Wheel {\n\tid String primary\n}\n"
+	carSchema    = "schema \"car\"\n\nimport \"./parts\" as parts\n\ntype Car {\n\tid String primary\n\t--> WHEELS (many) parts.Wheel\n}\n"
+	brokenSchema = "schema \"broken\"\n\ntype Broken extends Missing {\n\tid String primary\n}\n"
+)
+
+func TestTextDocumentDiagnostic(t *testing.T) {
+	t.Parallel()
+
+	s, dir := newPullServer(t, map[string]string{"broken.yammm": brokenSchema})
+	uri := PathToURI(filepath.Join(dir, "broken.yammm"))
+	s.workspace.DocumentOpened(uri, 1, brokenSchema)
+
+	result, err := s.textDocumentDiagnostic(nil, &documentDiagnosticParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+	})
+	if err != nil {
+		t.Fatalf("textDocumentDiagnostic failed: %v", err)
+	}
+	full := result.(documentDiagnosticReport)
+	if full.Kind != reportKindFull || len(full.Items) == 0 || full.ResultID == "" {
+		t.Fatalf("report = %+v; want a full report with diagnostics", full)
+	}
+
+	result, _ = s.textDocumentDiagnostic(nil, &documentDiagnosticParams{
+		TextDocument:     protocol.TextDocumentIdentifier{URI: uri},
+		PreviousResultID: &full.ResultID,
+	})
+	if unchanged := result.(documentDiagnosticReport); unchanged.Kind != reportKindUnchanged || unchanged.ResultID != full.ResultID {
+		t.Errorf("report = %+v; want unchanged %q", unchanged, full.ResultID)
+	}
+
+	// Fixing the document changes the report.
+	s.workspace.DocumentChanged(uri, 2, partsSchema)
+	result, _ = s.textDocumentDiagnostic(nil, &documentDiagnosticParams{
+		TextDocument:     protocol.TextDocumentIdentifier{URI: uri},
+		PreviousResultID: &full.ResultID,
+	})
+	if fixed := result.(documentDiagnosticReport); fixed.Kind != reportKindFull || len(fixed.Items) != 0 {
+		t.Errorf("report = %+v; want a full report without diagnostics", fixed)
+	}
+}
+
+func TestWorkspaceDiagnostic(t *testing.T) {
+	t.Parallel()
+
+	s, dir := newPullServer(t, map[string]string{
+		"broken.yammm":      brokenSchema,
+		"car.yammm":         carSchema,
+		"parts.yammm":       partsSchema,
+		"open.yammm":        brokenSchema,
+		".hidden/bad.yammm": brokenSchema,
+	})
+	openURI := PathToURI(filepath.Join(dir, "open.yammm"))
+	s.workspace.DocumentOpened(openURI, 1, brokenSchema)
+
+	reports := workspaceReports(t, s, nil)
+	if len(reports) != 3 {
+		t.Fatalf("reports = %+v; want broken, car and parts", reports)
+	}
+	if _, ok := reports[openURI]; ok {
+		t.Error("open document was reported")
+	}
+	brokenURI := PathToURI(filepath.Join(dir, "broken.yammm"))
+	if r := reports[brokenURI]; r.Kind != reportKindFull || len(r.Items) == 0 || r.Version != nil {
+		t.Errorf("broken report = %+v; want a full report with diagnostics", r)
+	}
+	carURI := PathToURI(filepath.Join(dir, "car.yammm"))
+	if r := reports[carURI]; r.Kind != reportKindFull || len(r.Items) != 0 {
+		t.Errorf("car report = %+v; want a full report without diagnostics", r)
+	}
+
+	// Reports the client holds are unchanged.
+	var previous []previousResultID
+	for uri, r := range reports {
+		previous = append(previous, previousResultID{URI: uri, Value: r.ResultID})
+	}
+	for uri, r := range workspaceReports(t, s, previous) {
+		if r.Kind != reportKindUnchanged {
+			t.Errorf("%s report = %+v; want unchanged", uri, r)
+		}
+	}
+
+	// Removing a type from an imported file changes its importer's report,
+	// and a deleted file's report is cleared.
+	partsPath := filepath.Join(dir, "parts.yammm")
+	if err := os.WriteFile(partsPath, []byte("schema \"parts\"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write parts: %v", err)
+	}
+	s.workspace.FileChanged(nil, PathToURI(partsPath), protocol.FileChangeTypeChanged)
+	if err := os.Remove(filepath.Join(dir, "broken.yammm")); err != nil {
+		t.Fatalf("failed to remove broken: %v", err)
+	}
+
+	reports = workspaceReports(t, s, previous)
+	if r := reports[carURI]; r.Kind != reportKindFull || len(r.Items) == 0 {
+		t.Errorf("car report = %+v; want a full report with diagnostics", r)
+	}
+	if r := reports[brokenURI]; r.Kind != reportKindFull || len(r.Items) != 0 {
+		t.Errorf("deleted file report = %+v; want an empty full report", r)
+	}
+}
+
+func TestPullHandler_Initialize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		params   string
+		wantPull bool
+	}{
+		{"pull", `{"capabilities": {"textDocument": {"diagnostic": {"dynamicRegistration": false}}}}`, true},
+		{"push", `{"capabilities": {"textDocument": {"publishDiagnostics": {}}}}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{})
+			h := pullHandler{Handler: s.Handler(), server: s}
+			result, validMethod, validParams, err := h.Handle(&glsp.Context{
+				Method: protocol.MethodInitialize,
+				Params: json.RawMessage(tt.params),
+			})
+			if err != nil || !validMethod || !validParams {
+				t.Fatalf("initialize = %v, %v, %v", validMethod, validParams, err)
+			}
+			if got := s.workspace.PullDiagnostics(); got != tt.wantPull {
+				t.Errorf("PullDiagnostics() = %v; want %v", got, tt.wantPull)
+			}
+
+			data, err := json.Marshal(result)
+			if err != nil {
+				t.Fatalf("failed to marshal result: %v", err)
+			}
+			if got := strings.Contains(string(data), `"diagnosticProvider":{`); got != tt.wantPull {
+				t.Errorf("result = %s; want diagnosticProvider %v", data, tt.wantPull)
+			}
+			if !strings.Contains(string(data), `"hoverProvider":true`) {
+				t.Errorf("result = %s; want the 3.16 capabilities", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package lsp
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+
+	"github.com/simon-lentz/yammm/internal/grammar"
+	"github.com/simon-lentz/yammm/schema/load"
+)
+
+// textDocumentDocumentLink handles textDocument/documentLink requests.
+// Every import path that resolves to a file links to it. Paths are resolved
+// with load.ResolveImport against the module root used for analysis, so a
+// link exists exactly when the loader would find the file.
+func (s *Server) textDocumentDocumentLink(_ *glsp.Context, params *protocol.DocumentLinkParams) ([]protocol.DocumentLink, error) {
+	uri := params.TextDocument.URI
+
+	s.logger.Debug("documentLink request", "uri", uri)
+
+	path, err := URIToPath(uri)
+	if err != nil {
+		return nil, nil
+	}
+	// Canonicalize as analysis does, so the module root matches.
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = filepath.Clean(resolved)
+	}
+
+	if mdSnap := s.workspace.GetMarkdownDocumentSnapshot(uri); mdSnap != nil {
+		return s.markdownDocumentLinks(mdSnap, path), nil
+	}
+
+	doc := s.workspace.GetDocumentSnapshot(uri)
+	if doc == nil {
+		return nil, nil
+	}
+
+	return s.documentLinks(parseSyntaxTree(doc.Text, s.workspace.PositionEncoding()), path), nil
+}
+
+// markdownDocumentLinks returns the import links of all code blocks in a
+// markdown file. Imports in blocks resolve relative to the markdown file.
+func (s *Server) markdownDocumentLinks(mdSnap *MarkdownDocumentSnapshot, path string) []protocol.DocumentLink {
+	var all []protocol.DocumentLink
+	for i, block := range mdSnap.Blocks {
+		tree := parseSyntaxTree(block.Content, s.workspace.PositionEncoding())
+		for _, link := range s.documentLinks(tree, path) {
+			link.Range = remapBlockRange(mdSnap, i, link.Range)
+			all = append(all, link)
+		}
+	}
+	return all
+}
+
+// documentLinks returns a link for each import in the document at path
+// whose target resolves. The link covers the path without its quotes.
+func (s *Server) documentLinks(tree *syntaxTree, path string) []protocol.DocumentLink {
+	moduleRoot := s.workspace.findModuleRoot(path)
+
+	var links []protocol.DocumentLink
+	walkSyntax(tree.root, func(node antlr.Tree) bool {
+		imp, ok := node.(*grammar.Import_declContext)
+		if !ok {
+			_, isSchema := node.(*grammar.SchemaContext)
+			return isSchema // imports are direct children of the schema
+		}
+		importPath, ok := importDeclPath(imp)
+		if !ok {
+			return false
+		}
+		target, err := load.ResolveImport(path, importPath, moduleRoot)
+		if err != nil {
+			s.logger.Debug("import link not resolved", "import", importPath, "error", err)
+			return false
+		}
+
+		// Exclude the quotes, which are single characters on the same line.
+		start, end := tokenStart(imp.GetPath()), tokenEnd(imp.GetPath())
+		start.col++
+		end.col--
+		targetURI := s.workspace.RemapPathToURI(target)
+		links = append(links, protocol.DocumentLink{
+			Range:  tree.lspRange(start, end),
+			Target: &targetURI,
+		})
+		return false
+	})
+	return links
+}
+
+// importDeclPath returns the unquoted path of an import declaration.
+func importDeclPath(imp *grammar.Import_declContext) (string, bool) {
+	tok := imp.GetPath()
+	if !isRealToken(tok) {
+		return "", false
+	}
+	// Strings may be single or double quoted; unquote as the parser does.
+	text := tok.GetText()
+	if len(text) < 2 {
+		return "", false
+	}
+	importPath, err := strconv.Unquote(`"` + text[1:len(text)-1] + `"`)
+	if err != nil || importPath == "" {
+		return "", false
+	}
+	return importPath, true
+}
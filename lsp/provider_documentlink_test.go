@@ -0,0 +1,114 @@
+package lsp
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestTextDocumentDocumentLink(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if resolved, err := filepath.EvalSymlinks(tmpDir); err == nil {
+		tmpDir = resolved
+	}
+	moduleRoot := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(filepath.Join(moduleRoot, "common"), 0o750); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	files := map[string]string{
+		filepath.Join(tmpDir, "outside.yammm"):             `schema "outside"`,
+		filepath.Join(moduleRoot, "common", "parts.yammm"): `schema "parts"`,
+		filepath.Join(moduleRoot, "common", "money.yammm"): `schema "money"`,
+	}
+	for path, text := range files {
+		if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	content := `schema "main"
+
+import "./common/parts" as parts
+import 'common/money' as money
+import "./missing" as missing
+import "../outside" as outside
+`
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{ModuleRoot: moduleRoot})
+	uri := PathToURI(filepath.Join(moduleRoot, "main.yammm"))
+	s.workspace.DocumentOpened(uri, 1, content)
+
+	links, err := s.textDocumentDocumentLink(nil, &protocol.DocumentLinkParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+	})
+	if err != nil {
+		t.Fatalf("documentLink failed: %v", err)
+	}
+
+	// Missing files and paths escaping the module root get no link.
+	want := []struct {
+		line, start, end protocol.UInteger
+		target           string
+	}{
+		{2, 8, 22, PathToURI(filepath.Join(moduleRoot, "common", "parts.yammm"))},
+		{3, 8, 20, PathToURI(filepath.Join(moduleRoot, "common", "money.yammm"))},
+	}
+	if len(links) != len(want) {
+		t.Fatalf("links = %+v; want %d links", links, len(want))
+	}
+	for i, w := range want {
+		r := protocol.Range{
+			Start: protocol.Position{Line: w.line, Character: w.start},
+			End:   protocol.Position{Line: w.line, Character: w.end},
+		}
+		if links[i].Range != r {
+			t.Errorf("links[%d].Range = %+v; want %+v", i, links[i].Range, r)
+		}
+		if links[i].Target == nil || *links[i].Target != w.target {
+			t.Errorf("links[%d].Target = %v; want %s", i, links[i].Target, w.target)
+		}
+	}
+}
+
+func TestTextDocumentDocumentLink_Markdown(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if resolved, err := filepath.EvalSymlinks(tmpDir); err == nil {
+		tmpDir = resolved
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "parts.yammm"), []byte(`schema "parts"`), 0o600); err != nil {
+		t.Fatalf("failed to write parts.yammm: %v", err)
+	}
+
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{ModuleRoot: tmpDir})
+	uri := PathToURI(filepath.Join(tmpDir, "README.md"))
+	content := "# Doc\n\n```yammm\nschema \"doc\"\nimport \"./parts\" as parts\n```\n"
+	s.workspace.MarkdownDocumentOpened(uri, 1, content)
+	s.workspace.AnalyzeMarkdownAndPublish(nil, t.Context(), uri)
+
+	links, err := s.textDocumentDocumentLink(nil, &protocol.DocumentLinkParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+	})
+	if err != nil {
+		t.Fatalf("documentLink failed: %v", err)
+	}
+	if len(links) != 1 {
+		t.Fatalf("links = %+v; want one link", links)
+	}
+	want := protocol.Range{
+		Start: protocol.Position{Line: 4, Character: 8},
+		End:   protocol.Position{Line: 4, Character: 15},
+	}
+	if links[0].Range != want {
+		t.Errorf("link range = %+v; want %+v", links[0].Range, want)
+	}
+	if target := PathToURI(filepath.Join(tmpDir, "parts.yammm")); links[0].Target == nil || *links[0].Target != target {
+		t.Errorf("link target = %v; want %s", links[0].Target, target)
+	}
+}
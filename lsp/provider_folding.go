@@ -0,0 +1,119 @@
+package lsp
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+
+	"github.com/simon-lentz/yammm/internal/grammar"
+)
+
+// textDocumentFoldingRange handles textDocument/foldingRange requests.
+// It folds type bodies, relation bodies, enum lists spanning several lines,
+// and multi-line doc comments.
+func (s *Server) textDocumentFoldingRange(_ *glsp.Context, params *protocol.FoldingRangeParams) ([]protocol.FoldingRange, error) {
+	uri := params.TextDocument.URI
+
+	s.logger.Debug("foldingRange request", "uri", uri)
+
+	if mdSnap := s.workspace.GetMarkdownDocumentSnapshot(uri); mdSnap != nil {
+		return s.markdownFoldingRanges(mdSnap), nil
+	}
+
+	doc := s.workspace.GetDocumentSnapshot(uri)
+	if doc == nil {
+		return nil, nil
+	}
+
+	return foldingRanges(parseSyntaxTree(doc.Text, s.workspace.PositionEncoding())), nil
+}
+
+// markdownFoldingRanges returns the folding ranges of all code blocks in a
+// markdown file, in markdown coordinates.
+func (s *Server) markdownFoldingRanges(mdSnap *MarkdownDocumentSnapshot) []protocol.FoldingRange {
+	var all []protocol.FoldingRange
+	for i, block := range mdSnap.Blocks {
+		tree := parseSyntaxTree(block.Content, s.workspace.PositionEncoding())
+		for _, fr := range foldingRanges(tree) {
+			if int(fr.StartLine) < block.PrefixLines {
+				continue
+			}
+			startLine, _ := mdSnap.BlockPositionToMarkdown(i, int(fr.StartLine), 0)
+			endLine, _ := mdSnap.BlockPositionToMarkdown(i, int(fr.EndLine), 0)
+			fr.StartLine = toUInteger(startLine)
+			fr.EndLine = toUInteger(endLine)
+			all = append(all, fr)
+		}
+	}
+	return all
+}
+
+// foldingRanges returns the folding ranges of a document, ordered by start
+// line. Ranges are whole lines, so they suit clients that only fold lines.
+func foldingRanges(tree *syntaxTree) []protocol.FoldingRange {
+	var ranges []protocol.FoldingRange
+
+	walkSyntax(tree.root, func(node antlr.Tree) bool {
+		var open, closing antlr.TerminalNode
+		switch n := node.(type) {
+		case *grammar.TypeContext:
+			open, closing = n.LBRACE(), n.RBRACE()
+		case *grammar.AssociationContext:
+			open, closing = n.LBRACE(), n.RBRACE()
+		case *grammar.EnumTContext:
+			open, closing = n.LBRACK(), n.RBRACK()
+		case *grammar.InvariantContext:
+			return false // expressions hold no foldable structure
+		default:
+			return true
+		}
+		if fr, ok := tree.bracketFold(open, closing); ok {
+			ranges = append(ranges, fr)
+		}
+		return true
+	})
+
+	comment := string(protocol.FoldingRangeKindComment)
+	for _, tok := range tree.tokens {
+		if tok.GetTokenType() != grammar.YammmGrammarLexerDOC_COMMENT {
+			continue
+		}
+		start, end := tokenStart(tok), tokenEnd(tok)
+		if end.line > start.line {
+			ranges = append(ranges, protocol.FoldingRange{
+				StartLine: toUInteger(start.line),
+				EndLine:   toUInteger(end.line),
+				Kind:      &comment,
+			})
+		}
+	}
+
+	slices.SortStableFunc(ranges, func(a, b protocol.FoldingRange) int {
+		return cmp.Compare(a.StartLine, b.StartLine)
+	})
+	return ranges
+}
+
+// bracketFold returns the range folding the lines between a pair of
+// brackets. A closing bracket that starts its line stays visible, so
+// "type A {" folds down to the line before "}".
+func (t *syntaxTree) bracketFold(open, closing antlr.TerminalNode) (protocol.FoldingRange, bool) {
+	if !isRealTerminal(open) || !isRealTerminal(closing) {
+		return protocol.FoldingRange{}, false
+	}
+	startLine := tokenStart(open.GetSymbol()).line
+	endLine := tokenStart(closing.GetSymbol()).line
+	if t.startsLine(closing.GetSymbol()) {
+		endLine--
+	}
+	if endLine <= startLine {
+		return protocol.FoldingRange{}, false
+	}
+	return protocol.FoldingRange{
+		StartLine: toUInteger(startLine),
+		EndLine:   toUInteger(endLine),
+	}, true
+}
@@ -0,0 +1,104 @@
+package lsp
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// foldLines renders folding ranges as start-end line pairs with the kind,
+// for compact comparison.
+func foldLines(ranges []protocol.FoldingRange) [][3]any {
+	out := make([][3]any, len(ranges))
+	for i, fr := range ranges {
+		kind := ""
+		if fr.Kind != nil {
+			kind = *fr.Kind
+		}
+		out[i] = [3]any{int(fr.StartLine), int(fr.EndLine), kind}
+	}
+	return out
+}
+
+func TestFoldingRanges(t *testing.T) {
+	t.Parallel()
+
+	text := `schema "test"
+
+/* A person.
+   Persons are people. */
+type Person {
+	id String primary
+	status Enum[
+		"active",
+		"inactive"]
+	--> employer (one) Company {
+		since Date
+		role String
+	}
+	! "ok" status != nil
+}
+
+type Company { id String primary }
+
+type Level = Enum["low", "high"]
+
+type Color = Enum[
+	"red",
+	"green",
+]
+`
+	got := foldLines(foldingRanges(parseSyntaxTree(text, PositionEncodingUTF16)))
+	want := [][3]any{
+		{2, 3, "comment"},
+		{4, 13, ""},
+		{6, 8, ""},
+		{9, 11, ""},
+		{20, 22, ""},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("foldingRanges() = %v; want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("foldingRanges()[%d] = %v; want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestFoldingRanges_SyntaxError(t *testing.T) {
+	t.Parallel()
+
+	// The recovered tree still has the first type; the unterminated second
+	// type has no closing brace to fold to.
+	text := "schema \"test\"\n\ntype A {\n\tid String primary\n}\n\ntype B {\n\tname String\n"
+	got := foldLines(foldingRanges(parseSyntaxTree(text, PositionEncodingUTF16)))
+	if len(got) != 1 || got[0] != [3]any{2, 3, ""} {
+		t.Errorf("foldingRanges() = %v; want [[2 3 ]]", got)
+	}
+}
+
+func TestTextDocumentFoldingRange_Markdown(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{ModuleRoot: tmpDir})
+	uri := PathToURI(filepath.Join(tmpDir, "README.md"))
+	content := "# Doc\n\n```yammm\ntype Person {\n\tid String primary\n}\n```\n"
+	s.workspace.MarkdownDocumentOpened(uri, 1, content)
+	s.workspace.AnalyzeMarkdownAndPublish(nil, t.Context(), uri)
+
+	ranges, err := s.textDocumentFoldingRange(nil, &protocol.FoldingRangeParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+	})
+	if err != nil {
+		t.Fatalf("foldingRange failed: %v", err)
+	}
+	got := foldLines(ranges)
+	if len(got) != 1 || got[0] != [3]any{3, 4, ""} {
+		t.Errorf("markdown foldingRange = %v; want [[3 4 ]]", got)
+	}
+}
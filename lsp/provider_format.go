@@ -12,9 +12,10 @@ import (
 )
 
 // textDocumentFormatting handles textDocument/formatting requests.
-// params.Options (FormattingOptions) is intentionally ignored: yammm formatting
-// is canonical (like gofmt) — tabs for indentation, trailing whitespace trimmed,
-// final newline enforced. All style decisions are hardcoded.
+// Indentation is canonical (like gofmt) — tabs for indentation, trailing
+// whitespace trimmed, final newline enforced — so tabSize and insertSpaces
+// in params.Options are ignored. The configurable options (see
+// [FormatOptions]) come from the project config file and params.Options.
 func (s *Server) textDocumentFormatting(_ *glsp.Context, params *protocol.DocumentFormattingParams) ([]protocol.TextEdit, error) {
 	uri := params.TextDocument.URI
 
@@ -32,6 +33,134 @@ func (s *Server) textDocumentFormatting(_ *glsp.Context, params *protocol.Docume
 		return nil, nil
 	}
 
+	formatted, ok := s.formatText(doc, params.Options)
+
+	// If no changes, return empty edits
+	if !ok || formatted == doc.Text {
+		return []protocol.TextEdit{}, nil
+	}
+
+	// Return a single edit that replaces the entire document
+	lines := strings.Split(doc.Text, "\n")
+	lastLine := len(lines) - 1
+
+	return []protocol.TextEdit{
+		{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 0, Character: 0},
+				End: protocol.Position{
+					Line:      toUInteger(lastLine),
+					Character: toUInteger(s.lineLength(lines[lastLine])),
+				},
+			},
+			NewText: formatted,
+		},
+	}, nil
+}
+
+// textDocumentRangeFormatting handles textDocument/rangeFormatting requests.
+// The whole document is formatted, and the changed lines that overlap the
+// range are edited. Lines outside the range are left as they are, even if
+// alignment with them changes.
+func (s *Server) textDocumentRangeFormatting(_ *glsp.Context, params *protocol.DocumentRangeFormattingParams) ([]protocol.TextEdit, error) {
+	uri := params.TextDocument.URI
+
+	if isMarkdownURI(uri) {
+		return []protocol.TextEdit{}, nil
+	}
+
+	s.logger.Debug("range formatting request",
+		"uri", uri,
+	)
+
+	doc := s.workspace.GetDocumentSnapshot(uri)
+	if doc == nil {
+		return nil, nil
+	}
+
+	// A range ending at the start of a line does not include that line.
+	startLine := int(params.Range.Start.Line)
+	endLine := int(params.Range.End.Line)
+	if params.Range.End.Character == 0 && endLine > startLine {
+		endLine--
+	}
+	return s.formatLines(doc, params.Options, startLine, endLine), nil
+}
+
+// textDocumentOnTypeFormatting handles textDocument/onTypeFormatting
+// requests. Typing "}" formats the block it closes, like a range
+// formatting request from the line of the matching "{". Typing a newline
+// trims the trailing whitespace of the line before and converts leading
+// spaces to tabs on both lines, without reformatting: the document is
+// often incomplete while typing.
+func (s *Server) textDocumentOnTypeFormatting(_ *glsp.Context, params *protocol.DocumentOnTypeFormattingParams) ([]protocol.TextEdit, error) {
+	uri := params.TextDocument.URI
+
+	if isMarkdownURI(uri) {
+		return []protocol.TextEdit{}, nil
+	}
+
+	s.logger.Debug("on-type formatting request",
+		"uri", uri,
+		"ch", params.Ch,
+	)
+
+	doc := s.workspace.GetDocumentSnapshot(uri)
+	if doc == nil {
+		return nil, nil
+	}
+
+	line := int(params.Position.Line)
+	switch params.Ch {
+	case "}":
+		depths, _ := ComputeBraceDepths(doc.Text)
+		if line >= len(depths) {
+			return []protocol.TextEdit{}, nil
+		}
+		// The block starts after the last line that ends outside it.
+		start := line
+		for start > 0 && depths[start-1] > depths[line] {
+			start--
+		}
+		return s.formatLines(doc, params.Options, start, line), nil
+
+	case "\n":
+		if line < 1 {
+			return []protocol.TextEdit{}, nil
+		}
+		lines := strings.Split(strings.ReplaceAll(doc.Text, "\r\n", "\n"), "\n")
+		if line >= len(lines) {
+			return []protocol.TextEdit{}, nil
+		}
+		_, inComment := ComputeBraceDepths(doc.Text)
+		edits := []protocol.TextEdit{}
+		for _, i := range []int{line - 1, line} {
+			want := normalizeIndentation(lines[i])
+			if i < line {
+				want = normalizeIndentation(strings.TrimRight(lines[i], " \t"))
+			}
+			// Leave the interior of block comments alone.
+			if lines[i] == want || (i > 0 && inComment[i-1]) {
+				continue
+			}
+			edits = append(edits, protocol.TextEdit{
+				Range: protocol.Range{
+					Start: protocol.Position{Line: toUInteger(i), Character: 0},
+					End:   protocol.Position{Line: toUInteger(i), Character: toUInteger(s.lineLength(lines[i]))},
+				},
+				NewText: want,
+			})
+		}
+		return edits, nil
+	}
+	return []protocol.TextEdit{}, nil
+}
+
+// formatText formats a document with the options that apply to it. It
+// reports false if the document has syntax errors or cannot be loaded.
+func (s *Server) formatText(doc *DocumentSnapshot, options protocol.FormattingOptions) (string, bool) {
+	uri := doc.URI
+
 	// Check for syntax errors only - semantic errors like unresolved imports
 	// should not prevent formatting. This ensures we don't corrupt files with
 	// syntax errors while still allowing formatting for files with imports.
@@ -42,7 +171,7 @@ func (s *Server) textDocumentFormatting(_ *glsp.Context, params *protocol.Docume
 			"uri", uri,
 			"error", err,
 		)
-		return []protocol.TextEdit{}, nil
+		return "", false
 	}
 
 	// Only skip formatting if there are syntax errors (not semantic errors)
@@ -50,12 +179,24 @@ func (s *Server) textDocumentFormatting(_ *glsp.Context, params *protocol.Docume
 		s.logger.Debug("formatting skipped due to syntax errors",
 			"uri", uri,
 		)
-		return []protocol.TextEdit{}, nil
+		return "", false
+	}
+
+	opts := DefaultFormatOptions()
+	if path, err := URIToPath(uri); err == nil {
+		opts = s.workspace.formatOptions(path)
+	}
+	opts, err = opts.withFormattingOptions(options)
+	if err != nil {
+		s.logger.Debug("ignoring formatting options",
+			"uri", uri,
+			"error", err,
+		)
 	}
 
 	// Format the document with parse-aware token spacing. Fall back to the
 	// conservative line-by-line formatter if internal formatting fails.
-	formatted, formatErr := formatTokenStream(doc.Text)
+	formatted, formatErr := formatTokenStreamOptions(doc.Text, opts)
 	if formatErr != nil {
 		s.logger.Debug("token-stream formatting failed, falling back",
 			"uri", uri,
@@ -63,46 +204,146 @@ func (s *Server) textDocumentFormatting(_ *glsp.Context, params *protocol.Docume
 		)
 		formatted = formatDocument(doc.Text)
 	}
+	return formatted, true
+}
+
+// formatLines returns the edits formatting a document that touch the lines
+// from startLine to endLine. Each edit replaces whole lines.
+func (s *Server) formatLines(doc *DocumentSnapshot, options protocol.FormattingOptions, startLine, endLine int) []protocol.TextEdit {
+	formatted, ok := s.formatText(doc, options)
+	if !ok || formatted == doc.Text {
+		return []protocol.TextEdit{}
+	}
 
-	// If no changes, return empty edits
-	if formatted == doc.Text {
-		return []protocol.TextEdit{}, nil
+	oldLines := strings.SplitAfter(doc.Text, "\n")
+	if oldLines[len(oldLines)-1] == "" {
+		oldLines = oldLines[:len(oldLines)-1]
+	}
+	newLines := strings.SplitAfter(formatted, "\n")
+	if newLines[len(newLines)-1] == "" {
+		newLines = newLines[:len(newLines)-1]
 	}
 
-	// Return a single edit that replaces the entire document
-	lines := strings.Split(doc.Text, "\n")
-	lastLine := len(lines) - 1
-	lastLineContent := []byte(lines[lastLine])
+	edits := []protocol.TextEdit{}
+	for _, h := range diffLines(oldLines, newLines) {
+		// Insertions touch the line they are inserted before.
+		last := max(h.oldStart, h.oldEnd-1)
+		if last < startLine || h.oldStart > endLine {
+			continue
+		}
+		end := protocol.Position{Line: toUInteger(h.oldEnd)}
+		if h.oldEnd == len(oldLines) && !strings.HasSuffix(oldLines[h.oldEnd-1], "\n") {
+			// The last line has no newline to end the range after.
+			end = protocol.Position{
+				Line:      toUInteger(h.oldEnd - 1),
+				Character: toUInteger(s.lineLength(oldLines[h.oldEnd-1])),
+			}
+		}
+		edits = append(edits, protocol.TextEdit{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: toUInteger(h.oldStart)},
+				End:   end,
+			},
+			NewText: strings.Join(newLines[h.newStart:h.newEnd], ""),
+		})
+	}
+	return edits
+}
 
-	// Convert byte length based on negotiated position encoding.
-	// UTF-8: character offset IS byte offset (no conversion needed)
-	// UTF-16: convert byte offset to UTF-16 code units for non-ASCII safety
-	var lastChar int
+// lineLength returns the length of a line in the negotiated position
+// encoding.
+func (s *Server) lineLength(line string) int {
 	switch s.workspace.PositionEncoding() {
 	case PositionEncodingUTF8:
 		// UTF-8: character offset is byte offset
-		lastChar = len(lastLineContent)
+		return len(line)
 	case PositionEncodingUTF16:
 		fallthrough
 	default:
 		// UTF-16 (default): convert byte offset to UTF-16 code units
 		// ByteToUTF16Offset(content, lineStart, targetByte) - pass 0 as lineStart
 		// since we're converting just the line content
-		lastChar = ByteToUTF16Offset(lastLineContent, 0, len(lastLineContent))
+		return ByteToUTF16Offset([]byte(line), 0, len(line))
 	}
+}
 
-	return []protocol.TextEdit{
-		{
-			Range: protocol.Range{
-				Start: protocol.Position{Line: 0, Character: 0},
-				End: protocol.Position{
-					Line:      toUInteger(lastLine),
-					Character: toUInteger(lastChar),
-				},
-			},
-			NewText: formatted,
-		},
-	}, nil
+// lineHunk replaces the old lines [oldStart, oldEnd) with the new lines
+// [newStart, newEnd).
+type lineHunk struct {
+	oldStart, oldEnd int
+	newStart, newEnd int
+}
+
+// maxDiffCells bounds the table of the longest common subsequence between
+// the differing parts of two texts. Larger differences are one hunk.
+const maxDiffCells = 1 << 22
+
+// diffLines returns the hunks that turn the old lines into the new ones,
+// from a longest common subsequence of lines.
+func diffLines(oldLines, newLines []string) []lineHunk {
+	// Trim the common prefix and suffix.
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+	a := oldLines[prefix : len(oldLines)-suffix]
+	b := newLines[prefix : len(newLines)-suffix]
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	if len(a)*len(b) > maxDiffCells || len(a) == 0 || len(b) == 0 {
+		return []lineHunk{{prefix, prefix + len(a), prefix, prefix + len(b)}}
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var hunks []lineHunk
+	var open *lineHunk
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		if i < len(a) && j < len(b) && a[i] == b[j] {
+			if open != nil {
+				hunks = append(hunks, *open)
+				open = nil
+			}
+			i++
+			j++
+			continue
+		}
+		if open == nil {
+			open = &lineHunk{prefix + i, prefix + i, prefix + j, prefix + j}
+		}
+		if j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]) {
+			i++
+			open.oldEnd = prefix + i
+		} else {
+			j++
+			open.newEnd = prefix + j
+		}
+	}
+	if open != nil {
+		hunks = append(hunks, *open)
+	}
+	return hunks
 }
 
 // formatDocument applies canonical formatting rules to a YAMMM document.
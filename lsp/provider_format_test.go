@@ -1249,6 +1249,51 @@ type T {
 	}
 }
 
+func TestFormatTokenStream_StructuredTypes(t *testing.T) {
+	t.Parallel()
+
+	input := `schema "test"
+
+type Point = Object {lat Float required,lon Float required}
+
+type T {
+	scores Map <String, Integer[0, _]> [1, 2] required
+	nested Object {
+	a String
+	b Integer required
+	}
+}
+`
+	expected := `schema "test"
+
+type Point = Object { lat Float required, lon Float required }
+
+type T {
+	scores Map<String, Integer[0, _]>[1, 2] required
+	nested Object {
+		a String
+		b Integer required
+	}
+}
+`
+	result, err := formatTokenStream(input)
+	if err != nil {
+		t.Fatalf("formatTokenStream returned error: %v", err)
+	}
+	if result != expected {
+		t.Errorf("formatTokenStream() =\n%q\nwant:\n%q", result, expected)
+	}
+
+	// Formatting is idempotent.
+	again, err := formatTokenStream(result)
+	if err != nil {
+		t.Fatalf("second formatTokenStream returned error: %v", err)
+	}
+	if again != result {
+		t.Errorf("formatting is not idempotent:\n%q\nthen:\n%q", result, again)
+	}
+}
+
 func TestFormatTokenStream_DOCCommentNewlineAfter(t *testing.T) {
 	t.Parallel()
 
@@ -2659,3 +2704,386 @@ type T {
 		t.Errorf("full pipeline should be idempotent:\nfirst:\n%q\nsecond:\n%q", result, second)
 	}
 }
+
+func TestFormatTokenStream_InvariantModifiers(t *testing.T) {
+	t.Parallel()
+
+	input := "schema \"test\"\n\ntype Person {\n\tage Integer\n\t!  warn   adult \"should be an adult\" age >= 18\n\t!   adult_id \"id only\" age > 0\n}\n"
+	expected := "schema \"test\"\n\ntype Person {\n\tage Integer\n\t!warn adult \"should be an adult\" age >= 18\n\t! adult_id \"id only\" age > 0\n}\n"
+
+	got, err := formatTokenStream(input)
+	if err != nil {
+		t.Fatalf("formatTokenStream returned error: %v", err)
+	}
+	if got != expected {
+		t.Errorf("formatTokenStream() =\n%q\nwant:\n%q", got, expected)
+	}
+}
+
+func TestFormatTokenStream_InvariantMessageVariants(t *testing.T) {
+	t.Parallel()
+
+	input := "schema \"test\"\n\ntype Person {\n\tage Integer\n\t! \"too young\"   @ de   \"zu jung\"@pt_BR \"jovem\" age >= 18\n}\n"
+	expected := "schema \"test\"\n\ntype Person {\n\tage Integer\n\t! \"too young\" @de \"zu jung\" @pt_BR \"jovem\" age >= 18\n}\n"
+
+	got, err := formatTokenStream(input)
+	if err != nil {
+		t.Fatalf("formatTokenStream returned error: %v", err)
+	}
+	if got != expected {
+		t.Errorf("formatTokenStream() =\n%q\nwant:\n%q", got, expected)
+	}
+}
+
+func TestFormatTokenStream_TimeRanges(t *testing.T) {
+	t.Parallel()
+
+	input := `schema "test"
+
+type Past = Timestamp [_,now-"24h"]
+
+type T {
+	born Date ["1900-01-01",today]
+	at Timestamp["2006-01-02 15:04", _, now]
+}
+`
+	expected := `schema "test"
+
+type Past = Timestamp[_, now - "24h"]
+
+type T {
+	born Date["1900-01-01", today]
+	at   Timestamp["2006-01-02 15:04", _, now]
+}
+`
+	result, err := formatTokenStream(input)
+	if err != nil {
+		t.Fatalf("formatTokenStream returned error: %v", err)
+	}
+	if result != expected {
+		t.Errorf("formatTokenStream mismatch\ngot:\n%s\nwant:\n%s", result, expected)
+	}
+}
+
+func TestFormatTokenStream_SetTypes(t *testing.T) {
+	t.Parallel()
+
+	input := `schema "test"
+
+type Tags = Set < String > [ 1,5 ]
+
+type T {
+	tags Set<String>
+	groups Map<String, Set <Integer>>
+}
+`
+	expected := `schema "test"
+
+type Tags = Set<String>[1, 5]
+
+type T {
+	tags   Set<String>
+	groups Map<String, Set<Integer>>
+}
+`
+	result, err := formatTokenStream(input)
+	if err != nil {
+		t.Fatalf("formatTokenStream returned error: %v", err)
+	}
+	if result != expected {
+		t.Errorf("formatTokenStream mismatch\ngot:\n%s\nwant:\n%s", result, expected)
+	}
+}
+
+func TestFormatTokenStreamOptions(t *testing.T) {
+	t.Parallel()
+
+	input := `schema "test"
+
+import "./zeta" as a
+import "./alpha"
+import "./mid" as m
+
+type Contact {
+	phone String required
+	address String[1, 500]
+	country String primary
+}
+type Code = String
+type Count = Integer
+/* A widget. */
+type Widget {
+	size Enum["small", "medium", "large", "extra large", "extra extra large", "gigantic", "colossal"]
+}
+`
+	tests := []struct {
+		name      string
+		opts      FormatOptions
+		wantParts []string
+	}{
+		{
+			name: "defaults",
+			opts: DefaultFormatOptions(),
+			wantParts: []string{
+				"import \"./zeta\" as a\nimport \"./alpha\"\nimport \"./mid\" as m\n",
+				"\tphone   String required\n\taddress String[1, 500]\n\tcountry String primary\n",
+				"}\ntype Code  = String\ntype Count = Integer\n/* A widget. */\ntype Widget {",
+				"\t\t\"colossal\",\n\t]\n",
+			},
+		},
+		{
+			name: "align types",
+			opts: FormatOptions{AlignColumns: AlignTypes, EnumTrailingCommas: true, SortImports: ImportOrderNone},
+			wantParts: []string{
+				"\tphone   String         required\n\taddress String[1, 500]\n\tcountry String         primary\n",
+			},
+		},
+		{
+			name: "align none",
+			opts: FormatOptions{AlignColumns: AlignNone, EnumTrailingCommas: true, SortImports: ImportOrderNone},
+			wantParts: []string{
+				"\tphone String required\n\taddress String[1, 500]\n\tcountry String primary\n",
+			},
+		},
+		{
+			name: "no enum trailing commas",
+			opts: FormatOptions{AlignColumns: AlignNames, SortImports: ImportOrderNone},
+			wantParts: []string{
+				"\t\t\"colossal\"\n\t]\n",
+			},
+		},
+		{
+			name: "blank lines between declarations",
+			opts: FormatOptions{AlignColumns: AlignNames, EnumTrailingCommas: true, BlankLinesBetweenDeclarations: 2, SortImports: ImportOrderNone},
+			wantParts: []string{
+				"}\n\n\ntype Code  = String\ntype Count = Integer\n\n\n/* A widget. */\ntype Widget {",
+			},
+		},
+		{
+			name: "sort imports by path",
+			opts: FormatOptions{AlignColumns: AlignNames, EnumTrailingCommas: true, SortImports: ImportOrderPath},
+			wantParts: []string{
+				"import \"./alpha\"\nimport \"./mid\" as m\nimport \"./zeta\" as a\n",
+			},
+		},
+		{
+			name: "sort imports by alias",
+			opts: FormatOptions{AlignColumns: AlignNames, EnumTrailingCommas: true, SortImports: ImportOrderAlias},
+			wantParts: []string{
+				"import \"./zeta\" as a\nimport \"./alpha\"\nimport \"./mid\" as m\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := formatTokenStreamOptions(input, tt.opts)
+			if err != nil {
+				t.Fatalf("formatTokenStreamOptions returned error: %v", err)
+			}
+			for _, part := range tt.wantParts {
+				if !strings.Contains(result, part) {
+					t.Errorf("result does not contain %q\ngot:\n%s", part, result)
+				}
+			}
+			again, err := formatTokenStreamOptions(result, tt.opts)
+			if err != nil || again != result {
+				t.Errorf("formatting is not idempotent\nfirst:\n%s\nsecond:\n%s", result, again)
+			}
+		})
+	}
+}
+
+func TestLoadFormatOptions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configs := map[string]string{
+		"valid.json":   `{"alignColumns": "types", "blankLinesBetweenDeclarations": 1}`,
+		"syntax.json":  `{"alignColumns": `,
+		"mode.json":    `{"alignColumns": "left"}`,
+		"order.json":   `{"sortImports": "random"}`,
+		"blanks.json":  `{"blankLinesBetweenDeclarations": 9}`,
+		"wrongty.json": `{"enumTrailingCommas": "no"}`,
+	}
+	for name, content := range configs {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	opts, err := LoadFormatOptions(filepath.Join(dir, "valid.json"))
+	if err != nil {
+		t.Fatalf("LoadFormatOptions() error = %v", err)
+	}
+	want := DefaultFormatOptions()
+	want.AlignColumns = AlignTypes
+	want.BlankLinesBetweenDeclarations = 1
+	if opts != want {
+		t.Errorf("LoadFormatOptions() = %+v; want %+v", opts, want)
+	}
+
+	for name := range configs {
+		if name == "valid.json" {
+			continue
+		}
+		if _, err := LoadFormatOptions(filepath.Join(dir, name)); err == nil {
+			t.Errorf("LoadFormatOptions(%s) succeeded; want error", name)
+		}
+	}
+}
+
+func TestFormatOptions_WithFormattingOptions(t *testing.T) {
+	t.Parallel()
+
+	base := DefaultFormatOptions()
+	opts, err := base.withFormattingOptions(protocol.FormattingOptions{
+		protocol.FormattingOptionTabSize:      float64(4),
+		protocol.FormattingOptionInsertSpaces: true,
+		"alignColumns":                        "none",
+		"enumTrailingCommas":                  false,
+		"blankLinesBetweenDeclarations":       float64(2),
+		"sortImports":                         "path",
+	})
+	if err != nil {
+		t.Fatalf("withFormattingOptions() error = %v", err)
+	}
+	want := FormatOptions{AlignColumns: AlignNone, BlankLinesBetweenDeclarations: 2, SortImports: ImportOrderPath}
+	if opts != want {
+		t.Errorf("withFormattingOptions() = %+v; want %+v", opts, want)
+	}
+
+	for _, options := range []protocol.FormattingOptions{
+		{"alignColumns": true},
+		{"alignColumns": "left"},
+		{"blankLinesBetweenDeclarations": 1.5},
+	} {
+		got, err := base.withFormattingOptions(options)
+		if err == nil || got != base {
+			t.Errorf("withFormattingOptions(%v) = %+v, %v; want the base options and an error", options, got, err)
+		}
+	}
+}
+
+// newFormatServer returns a server with a document open in a temporary
+// module, and the URI of the document.
+func newFormatServer(t *testing.T, files map[string]string, content string) (*Server, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	writeSchemaFiles(t, tmpDir, files)
+	filePath := filepath.Join(tmpDir, "sub", "test.yammm")
+	writeSchemaFiles(t, tmpDir, map[string]string{"sub/test.yammm": content})
+
+	server := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{ModuleRoot: tmpDir})
+	uri := PathToURI(filePath)
+	server.workspace.DocumentOpened(uri, 1, content)
+	return server, uri
+}
+
+func TestFormatting_ProjectConfig(t *testing.T) {
+	t.Parallel()
+
+	content := "schema \"test\"\n\ntype A {\n\tname String required\n\tid String[1, 10] primary\n}\n"
+	server, uri := newFormatServer(t, map[string]string{
+		FormatConfigFile: `{"alignColumns": "types"}`,
+	}, content)
+
+	edits, err := server.textDocumentFormatting(nil, &protocol.DocumentFormattingParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+	})
+	if err != nil || len(edits) != 1 {
+		t.Fatalf("textDocumentFormatting() = %v, %v; want one edit", edits, err)
+	}
+	want := "\tname String        required\n\tid   String[1, 10] primary\n"
+	if !strings.Contains(edits[0].NewText, want) {
+		t.Errorf("formatted text:\n%s\nwant it to contain:\n%s", edits[0].NewText, want)
+	}
+
+	// Request options take precedence over the config file.
+	edits, err = server.textDocumentFormatting(nil, &protocol.DocumentFormattingParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		Options:      protocol.FormattingOptions{"alignColumns": "none"},
+	})
+	if err != nil || len(edits) != 0 {
+		t.Errorf("textDocumentFormatting() = %v, %v; want no edits", edits, err)
+	}
+}
+
+func TestTextDocumentRangeFormatting(t *testing.T) {
+	t.Parallel()
+
+	content := "schema \"test\"\n\ntype   A {\n\tname String\n}\n\ntype   B {\n\tname   String  required\n\tid String primary\n}"
+	server, uri := newFormatServer(t, nil, content)
+
+	edits, err := server.textDocumentRangeFormatting(nil, &protocol.DocumentRangeFormattingParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		Range: protocol.Range{
+			Start: protocol.Position{Line: 6, Character: 0},
+			End:   protocol.Position{Line: 10, Character: 0},
+		},
+	})
+	if err != nil {
+		t.Fatalf("textDocumentRangeFormatting failed: %v", err)
+	}
+	// The last line has no newline, so the edit ends at its end.
+	want := protocol.TextEdit{
+		Range: protocol.Range{
+			Start: protocol.Position{Line: 6, Character: 0},
+			End:   protocol.Position{Line: 9, Character: 1},
+		},
+		NewText: "type B {\n\tname String required\n\tid   String primary\n}\n",
+	}
+	if len(edits) != 1 || edits[0] != want {
+		t.Fatalf("edits = %+v; want %+v", edits, want)
+	}
+
+	// A range outside the changed lines has no edits.
+	edits, err = server.textDocumentRangeFormatting(nil, &protocol.DocumentRangeFormattingParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		Range: protocol.Range{
+			Start: protocol.Position{Line: 0, Character: 0},
+			End:   protocol.Position{Line: 1, Character: 0},
+		},
+	})
+	if err != nil || len(edits) != 0 {
+		t.Errorf("edits = %+v, %v; want none", edits, err)
+	}
+}
+
+func TestTextDocumentOnTypeFormatting(t *testing.T) {
+	t.Parallel()
+
+	content := "schema \"test\"\n\ntype   A {\n\tname String\n}\n\ntype B {\n    name   String  \n    \n}\n"
+	server, uri := newFormatServer(t, nil, content)
+
+	// Closing brace formats the block it closes.
+	edits, err := server.textDocumentOnTypeFormatting(nil, &protocol.DocumentOnTypeFormattingParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     protocol.Position{Line: 9, Character: 1},
+		},
+		Ch: "}",
+	})
+	if err != nil {
+		t.Fatalf("textDocumentOnTypeFormatting failed: %v", err)
+	}
+	if len(edits) != 1 || edits[0].Range.Start.Line != 7 || edits[0].NewText != "\tname String\n" {
+		t.Errorf("edits = %+v; want the body of B formatted", edits)
+	}
+
+	// Newline tidies the line before and the new line.
+	edits, err = server.textDocumentOnTypeFormatting(nil, &protocol.DocumentOnTypeFormattingParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     protocol.Position{Line: 8, Character: 4},
+		},
+		Ch: "\n",
+	})
+	if err != nil {
+		t.Fatalf("textDocumentOnTypeFormatting failed: %v", err)
+	}
+	if len(edits) != 2 || edits[0].NewText != "\tname   String" || edits[1].NewText != "\t" {
+		t.Errorf("edits = %+v; want lines 7 and 8 tidied", edits)
+	}
+}
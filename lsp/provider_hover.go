@@ -115,6 +115,12 @@ func (s *Server) hoverAtPosition(snapshot *Snapshot, doc *DocumentSnapshot, line
 		if targetSym != nil {
 			return s.buildHoverForSymbolWithRange(targetSym, snapshot, &ref.Span)
 		}
+		// Unresolved unqualified datatype references may name a Go custom type.
+		if ref.Kind == RefDataType && ref.Qualifier == "" {
+			if ct, ok := s.config.CustomTypes.Lookup(ref.TargetName); ok {
+				return s.buildHover(hoverForCustomType(ct), snapshot, ref.Span), nil
+			}
+		}
 	}
 
 	sym := idx.SymbolAtPosition(internalPos)
@@ -159,12 +165,7 @@ func (s *Server) buildHoverForSymbolWithRange(sym *Symbol, snapshot *Snapshot, o
 	if content == "" {
 		return nil, nil
 	}
-
-	// Always use Markdown: all hover renderers emit Markdown formatting (bold, backticks,
-	// fenced blocks, etc.). All mainstream LSP clients support Markdown. Capability
-	// negotiation was removed because returning Markdown content with Kind=PlainText
-	// is strictly worse than declaring Markdown—clients would display literal ** and ```.
-	contentKind := protocol.MarkupKindMarkdown
+	content += s.dataHover(sym, snapshot)
 
 	// Use override range if provided (e.g., when hovering a reference),
 	// otherwise use the symbol's own selection span.
@@ -173,6 +174,17 @@ func (s *Server) buildHoverForSymbolWithRange(sym *Symbol, snapshot *Snapshot, o
 		rangeSpan = *overrideRange
 	}
 
+	return s.buildHover(content, snapshot, rangeSpan), nil
+}
+
+// buildHover wraps Markdown hover content with the LSP range for rangeSpan.
+func (s *Server) buildHover(content string, snapshot *Snapshot, rangeSpan location.Span) *protocol.Hover {
+	// Always use Markdown: all hover renderers emit Markdown formatting (bold, backticks,
+	// fenced blocks, etc.). All mainstream LSP clients support Markdown. Capability
+	// negotiation was removed because returning Markdown content with Kind=PlainText
+	// is strictly worse than declaring Markdown—clients would display literal ** and ```.
+	contentKind := protocol.MarkupKindMarkdown
+
 	// Use proper UTF-16 conversion for the hover range
 	start, end, ok := SpanToLSPRange(snapshot.Sources, rangeSpan, s.workspace.PositionEncoding())
 	if !ok {
@@ -192,7 +204,7 @@ func (s *Server) buildHoverForSymbolWithRange(sym *Symbol, snapshot *Snapshot, o
 					Character: toUInteger(rangeSpan.End.Column - 1),
 				},
 			},
-		}, nil
+		}
 	}
 
 	return &protocol.Hover{
@@ -204,7 +216,7 @@ func (s *Server) buildHoverForSymbolWithRange(sym *Symbol, snapshot *Snapshot, o
 			Start: protocol.Position{Line: toUInteger(start[0]), Character: toUInteger(start[1])},
 			End:   protocol.Position{Line: toUInteger(end[0]), Character: toUInteger(end[1])},
 		},
-	}, nil
+	}
 }
 
 // hoverForSchema generates hover content for a schema symbol.
@@ -341,6 +353,22 @@ func (s *Server) hoverForDataType(sym *Symbol) string {
 	return b.String()
 }
 
+// hoverForCustomType generates hover content for a registered Go custom type.
+func hoverForCustomType(ct *schema.CustomType) string {
+	var b strings.Builder
+	b.WriteString("**custom type** `")
+	b.WriteString(ct.Name)
+	b.WriteString("`\n")
+
+	if ct.Description != "" {
+		b.WriteString("\n")
+		b.WriteString(ct.Description)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
 // hoverForProperty generates hover content for a property symbol.
 func (s *Server) hoverForProperty(sym *Symbol) string {
 	p, ok := sym.Data.(*schema.Property)
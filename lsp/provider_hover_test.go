@@ -453,3 +453,19 @@ func TestBuildHoverForSymbolWithRange_AcceptsOverrideParameter(t *testing.T) {
 	_ = hoverWithoutOverride
 	_ = hoverWithOverride
 }
+
+func TestHoverForCustomType(t *testing.T) {
+	t.Parallel()
+
+	result := hoverForCustomType(&schema.CustomType{
+		Name:        "Email",
+		Description: "An email address.",
+	})
+
+	if !strings.Contains(result, "**custom type** `Email`") {
+		t.Errorf("hover should contain custom type header, got %q", result)
+	}
+	if !strings.Contains(result, "An email address.") {
+		t.Error("hover should contain description")
+	}
+}
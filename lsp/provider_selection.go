@@ -0,0 +1,127 @@
+package lsp
+
+import (
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// textDocumentSelectionRange handles textDocument/selectionRange requests.
+// For each position it returns the chain of parse tree nodes enclosing it,
+// from the innermost token out to the whole schema, which editors use to
+// expand and shrink the selection.
+func (s *Server) textDocumentSelectionRange(_ *glsp.Context, params *protocol.SelectionRangeParams) ([]protocol.SelectionRange, error) {
+	uri := params.TextDocument.URI
+
+	s.logger.Debug("selectionRange request", "uri", uri, "positions", len(params.Positions))
+
+	if mdSnap := s.workspace.GetMarkdownDocumentSnapshot(uri); mdSnap != nil {
+		return s.markdownSelectionRanges(mdSnap, params.Positions), nil
+	}
+
+	doc := s.workspace.GetDocumentSnapshot(uri)
+	if doc == nil {
+		return nil, nil
+	}
+
+	tree := parseSyntaxTree(doc.Text, s.workspace.PositionEncoding())
+	result := make([]protocol.SelectionRange, len(params.Positions))
+	for i, pos := range params.Positions {
+		result[i] = chainToSelectionRange(pos, selectionChain(tree, pos))
+	}
+	return result, nil
+}
+
+// markdownSelectionRanges returns selection ranges for positions in a
+// markdown file. Ranges stop at the code block: the synthetic schema
+// declaration of snippet blocks is not part of the document. Positions
+// outside all blocks get an empty range.
+func (s *Server) markdownSelectionRanges(mdSnap *MarkdownDocumentSnapshot, positions []protocol.Position) []protocol.SelectionRange {
+	trees := make(map[int]*syntaxTree)
+	result := make([]protocol.SelectionRange, len(positions))
+	for i, pos := range positions {
+		blockPos := mdSnap.MarkdownPositionToBlock(int(pos.Line), int(pos.Character))
+		if blockPos == nil {
+			result[i] = protocol.SelectionRange{Range: protocol.Range{Start: pos, End: pos}}
+			continue
+		}
+		block := mdSnap.Blocks[blockPos.BlockIndex]
+		tree := trees[blockPos.BlockIndex]
+		if tree == nil {
+			tree = parseSyntaxTree(block.Content, s.workspace.PositionEncoding())
+			trees[blockPos.BlockIndex] = tree
+		}
+
+		local := protocol.Position{Line: toUInteger(blockPos.LocalLine), Character: toUInteger(blockPos.LocalChar)}
+		var chain []protocol.Range
+		for _, r := range selectionChain(tree, local) {
+			if int(r.Start.Line) < block.PrefixLines {
+				break
+			}
+			chain = append(chain, remapBlockRange(mdSnap, blockPos.BlockIndex, r))
+		}
+		result[i] = chainToSelectionRange(pos, chain)
+	}
+	return result
+}
+
+// remapBlockRange converts a range from block-local to markdown coordinates.
+func remapBlockRange(mdSnap *MarkdownDocumentSnapshot, blockIndex int, r protocol.Range) protocol.Range {
+	startLine, startChar := mdSnap.BlockPositionToMarkdown(blockIndex, int(r.Start.Line), int(r.Start.Character))
+	endLine, endChar := mdSnap.BlockPositionToMarkdown(blockIndex, int(r.End.Line), int(r.End.Character))
+	return protocol.Range{
+		Start: protocol.Position{Line: toUInteger(startLine), Character: toUInteger(startChar)},
+		End:   protocol.Position{Line: toUInteger(endLine), Character: toUInteger(endChar)},
+	}
+}
+
+// selectionChain returns the ranges of the parse tree nodes containing pos,
+// innermost first. Nodes covering the same text as their child are
+// collapsed into one range.
+func selectionChain(tree *syntaxTree, pos protocol.Position) []protocol.Range {
+	target := tree.textPosition(pos)
+
+	// Descend from the root into the first child containing the position;
+	// a position between two tokens belongs to the one it ends.
+	var path []antlr.Tree
+	var node antlr.Tree = tree.root
+	for node != nil {
+		if _, _, ok := nodeBounds(node); ok {
+			path = append(path, node)
+		}
+		var next antlr.Tree
+		for i := range node.GetChildCount() {
+			child := node.GetChild(i)
+			start, end, ok := nodeBounds(child)
+			if ok && !target.before(start) && !end.before(target) {
+				next = child
+				break
+			}
+		}
+		node = next
+	}
+
+	chain := make([]protocol.Range, 0, len(path))
+	for i := len(path) - 1; i >= 0; i-- {
+		start, end, _ := nodeBounds(path[i])
+		r := tree.lspRange(start, end)
+		if n := len(chain); n > 0 && chain[n-1] == r {
+			continue
+		}
+		chain = append(chain, r)
+	}
+	return chain
+}
+
+// chainToSelectionRange links ranges, innermost first, into a selection
+// range. An empty chain yields an empty range at pos.
+func chainToSelectionRange(pos protocol.Position, chain []protocol.Range) protocol.SelectionRange {
+	if len(chain) == 0 {
+		return protocol.SelectionRange{Range: protocol.Range{Start: pos, End: pos}}
+	}
+	var parent *protocol.SelectionRange
+	for i := len(chain) - 1; i > 0; i-- {
+		parent = &protocol.SelectionRange{Range: chain[i], Parent: parent}
+	}
+	return protocol.SelectionRange{Range: chain[0], Parent: parent}
+}
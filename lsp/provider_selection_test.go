@@ -0,0 +1,120 @@
+package lsp
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// selectionTexts returns the text of each range in a selection chain,
+// innermost first. Ranges must lie on lines of text.
+func selectionTexts(t *testing.T, lines []string, sel protocol.SelectionRange) []string {
+	t.Helper()
+	var texts []string
+	for r := &sel; r != nil; r = r.Parent {
+		start, end := r.Range.Start, r.Range.End
+		if start.Line == end.Line {
+			texts = append(texts, lines[start.Line][start.Character:end.Character])
+			continue
+		}
+		text := lines[start.Line][start.Character:]
+		for l := start.Line + 1; l < end.Line; l++ {
+			text += "\n" + lines[l]
+		}
+		texts = append(texts, text+"\n"+lines[end.Line][:end.Character])
+	}
+	return texts
+}
+
+func TestSelectionChain(t *testing.T) {
+	t.Parallel()
+
+	text := "schema \"test\"\n\ntype Person {\n\tage Integer[0, 150] required\n}\n"
+	lines := []string{"schema \"test\"", "", "type Person {", "\tage Integer[0, 150] required", "}", ""}
+	tree := parseSyntaxTree(text, PositionEncodingUTF16)
+
+	// Cursor on "150".
+	sel := chainToSelectionRange(protocol.Position{Line: 3, Character: 17},
+		selectionChain(tree, protocol.Position{Line: 3, Character: 17}))
+	got := selectionTexts(t, lines, sel)
+	want := []string{
+		"150",
+		"Integer[0, 150]",
+		"age Integer[0, 150] required",
+		"type Person {\n\tage Integer[0, 150] required\n}",
+		"schema \"test\"\n\ntype Person {\n\tage Integer[0, 150] required\n}",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("selection = %q; want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("selection[%d] = %q; want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSelectionChain_UTF16(t *testing.T) {
+	t.Parallel()
+
+	// The doc comment holds a character outside the BMP, which takes two
+	// UTF-16 code units.
+	text := "schema \"test\"\n\ntype A {\n\t/* 🚀 */ name String\n}\n"
+	tree := parseSyntaxTree(text, PositionEncodingUTF16)
+
+	chain := selectionChain(tree, protocol.Position{Line: 3, Character: 11})
+	if len(chain) == 0 {
+		t.Fatal("selectionChain() returned no ranges")
+	}
+	want := protocol.Range{
+		Start: protocol.Position{Line: 3, Character: 10},
+		End:   protocol.Position{Line: 3, Character: 14},
+	}
+	if chain[0] != want {
+		t.Errorf("innermost range = %+v; want %+v (the name token)", chain[0], want)
+	}
+}
+
+func TestTextDocumentSelectionRange_Markdown(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), Config{ModuleRoot: tmpDir})
+	uri := PathToURI(filepath.Join(tmpDir, "README.md"))
+	content := "# Doc\n\n```yammm\ntype Person {\n\tid String primary\n}\n```\n"
+	s.workspace.MarkdownDocumentOpened(uri, 1, content)
+	s.workspace.AnalyzeMarkdownAndPublish(nil, t.Context(), uri)
+
+	outside := protocol.Position{Line: 0, Character: 2}
+	ranges, err := s.textDocumentSelectionRange(nil, &protocol.SelectionRangeParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		Positions:    []protocol.Position{{Line: 4, Character: 2}, outside},
+	})
+	if err != nil {
+		t.Fatalf("selectionRange failed: %v", err)
+	}
+	if len(ranges) != 2 {
+		t.Fatalf("selectionRange returned %d ranges; want 2", len(ranges))
+	}
+
+	// The snippet's synthetic schema declaration is cut off, so the
+	// outermost range is the type.
+	lines := []string{"# Doc", "", "```yammm", "type Person {", "\tid String primary", "}", "```", ""}
+	got := selectionTexts(t, lines, ranges[0])
+	want := []string{"id", "id String primary", "type Person {\n\tid String primary\n}"}
+	if len(got) != len(want) {
+		t.Fatalf("selection = %q; want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("selection[%d] = %q; want %q", i, got[i], want[i])
+		}
+	}
+
+	if ranges[1].Range != (protocol.Range{Start: outside, End: outside}) || ranges[1].Parent != nil {
+		t.Errorf("selection outside blocks = %+v; want empty range", ranges[1])
+	}
+}
@@ -20,6 +20,9 @@ import (
 	protocol "github.com/tliron/glsp/protocol_3_16"
 	"github.com/tliron/glsp/server"
 
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/lint"
+
 	_ "github.com/tliron/commonlog/simple" // required backend for glsp
 )
 
@@ -57,6 +60,22 @@ const (
 type Config struct {
 	// ModuleRoot overrides the computed module root for import resolution.
 	ModuleRoot string
+
+	// Linter, if set, lints every analyzed .yammm file and its imports and
+	// publishes the findings with the other diagnostics. Markdown code
+	// blocks are fragments and are not linted.
+	Linter *lint.Linter
+
+	// DataBindings bind instance data files to schemas. The data bound to
+	// an open schema is validated against it in the background, and code
+	// lenses and hovers show instance counts, failures, value samples and
+	// invariant violations. See [LoadDataBindings].
+	DataBindings []DataBinding
+
+	// CustomTypes are the Go datatypes available to schemas, as with
+	// [load.WithCustomTypes]. They are offered in completion and described
+	// in hovers, and bound data is validated with them.
+	CustomTypes *schema.CustomTypeRegistry
 }
 
 // Server is the YAMMM language server. It handles both standalone .yammm
@@ -106,18 +125,25 @@ func NewServer(logger *slog.Logger, cfg Config) *Server {
 		TextDocumentDidClose:  s.textDocumentDidClose,
 
 		// Language Features (Phases 2-6) - stubs for now
-		TextDocumentDefinition:     s.textDocumentDefinition,
-		TextDocumentHover:          s.textDocumentHover,
-		TextDocumentCompletion:     s.textDocumentCompletion,
-		TextDocumentDocumentSymbol: s.textDocumentDocumentSymbol,
-		TextDocumentFormatting:     s.textDocumentFormatting,
+		TextDocumentDefinition:       s.textDocumentDefinition,
+		TextDocumentHover:            s.textDocumentHover,
+		TextDocumentCompletion:       s.textDocumentCompletion,
+		TextDocumentDocumentSymbol:   s.textDocumentDocumentSymbol,
+		TextDocumentFormatting:       s.textDocumentFormatting,
+		TextDocumentRangeFormatting:  s.textDocumentRangeFormatting,
+		TextDocumentOnTypeFormatting: s.textDocumentOnTypeFormatting,
+		TextDocumentCodeAction:       s.textDocumentCodeAction,
+		TextDocumentFoldingRange:     s.textDocumentFoldingRange,
+		TextDocumentSelectionRange:   s.textDocumentSelectionRange,
+		TextDocumentDocumentLink:     s.textDocumentDocumentLink,
+		TextDocumentCodeLens:         s.textDocumentCodeLens,
 
 		// Workspace
 		WorkspaceDidChangeWatchedFiles:     s.workspaceDidChangeWatchedFiles,
 		WorkspaceDidChangeWorkspaceFolders: s.workspaceDidChangeWorkspaceFolders,
 	}
 
-	s.server = server.NewServer(&s.handler, serverName, false)
+	s.server = server.NewServer(pullHandler{Handler: &s.handler, server: s}, serverName, false)
 
 	return s
 }
@@ -210,19 +236,51 @@ func (s *Server) initialize(ctx *glsp.Context, params *protocol.InitializeParams
 		TriggerCharacters: []string{".", " "},
 	}
 
+	// Format the block a closing brace ends, and tidy the line a newline
+	// ends.
+	capabilities.DocumentOnTypeFormattingProvider = &protocol.DocumentOnTypeFormattingOptions{
+		FirstTriggerCharacter: "}",
+		MoreTriggerCharacter:  []string{"\n"},
+	}
+
 	version := "dev"
+	serverInfo := &protocol.InitializeResultServerInfo{
+		Name:    serverName,
+		Version: &version,
+	}
+
+	// Clients that pull diagnostics get the 3.17 diagnosticProvider
+	// capability, which glsp's ServerCapabilities lacks (see pullHandler).
+	if s.workspace.PullDiagnostics() {
+		s.logger.Info("client pulls diagnostics")
+		return initializeResult{
+			Capabilities: serverCapabilities{
+				ServerCapabilities: capabilities,
+				DiagnosticProvider: &diagnosticOptions{
+					Identifier:            serverName,
+					InterFileDependencies: true,
+					WorkspaceDiagnostics:  true,
+				},
+			},
+			ServerInfo: serverInfo,
+		}, nil
+	}
+
 	return protocol.InitializeResult{
 		Capabilities: capabilities,
-		ServerInfo: &protocol.InitializeResultServerInfo{
-			Name:    serverName,
-			Version: &version,
-		},
+		ServerInfo:   serverInfo,
 	}, nil
 }
 
 // initialized handles the initialized notification.
 func (s *Server) initialized(ctx *glsp.Context, params *protocol.InitializedParams) error {
 	s.logger.Info("server initialized")
+
+	// Analyze the workspace ahead of the client's first workspace
+	// diagnostic request.
+	if s.workspace.PullDiagnostics() {
+		go s.workspace.AnalyzeWorkspace(context.Background())
+	}
 	return nil
 }
 
@@ -0,0 +1,186 @@
+package lsp
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antlr4-go/antlr/v4"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+
+	"github.com/simon-lentz/yammm/internal/grammar"
+)
+
+// syntaxTree is the parse tree of a document together with its text.
+//
+// Structural features (folding, selection ranges, document links) parse the
+// current text on demand instead of reading analysis snapshots: they need
+// the concrete syntax, which snapshots do not keep, and they should follow
+// unsaved edits without waiting for the debounced analysis. Syntax errors
+// are ignored; features work on the tree ANTLR recovers.
+type syntaxTree struct {
+	root   grammar.ISchemaContext
+	tokens []antlr.Token // all tokens, on every channel
+	lines  []string
+	enc    PositionEncoding
+}
+
+// textPos is a 0-based line and rune column, the coordinates ANTLR tokens
+// carry.
+type textPos struct {
+	line int
+	col  int
+}
+
+// before reports whether p is strictly before q.
+func (p textPos) before(q textPos) bool {
+	return p.line < q.line || (p.line == q.line && p.col < q.col)
+}
+
+// parseSyntaxTree parses text and keeps the tree and its tokens. Positions
+// are converted to LSP positions in the given encoding.
+func parseSyntaxTree(text string, enc PositionEncoding) *syntaxTree {
+	lexer := grammar.NewModifierLexer(antlr.NewInputStream(text))
+	lexer.RemoveErrorListeners()
+
+	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	parser := grammar.NewYammmGrammarParser(stream)
+	parser.RemoveErrorListeners()
+
+	root := parser.Schema()
+	stream.Fill()
+
+	return &syntaxTree{
+		root:   root,
+		tokens: stream.GetAllTokens(),
+		lines:  strings.Split(text, "\n"),
+		enc:    enc,
+	}
+}
+
+// walkSyntax calls fn for node and its descendants in document order,
+// skipping the children of nodes for which fn returns false.
+func walkSyntax(node antlr.Tree, fn func(antlr.Tree) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+	for i := range node.GetChildCount() {
+		walkSyntax(node.GetChild(i), fn)
+	}
+}
+
+// tokenStart returns the position of the first character of tok.
+func tokenStart(tok antlr.Token) textPos {
+	return textPos{line: tok.GetLine() - 1, col: tok.GetColumn()}
+}
+
+// tokenEnd returns the position just past the last character of tok. The
+// EOF token is empty.
+func tokenEnd(tok antlr.Token) textPos {
+	start := tokenStart(tok)
+	if tok.GetTokenType() == antlr.TokenEOF {
+		return start
+	}
+	text := tok.GetText()
+	if idx := strings.LastIndexByte(text, '\n'); idx >= 0 {
+		return textPos{
+			line: start.line + strings.Count(text, "\n"),
+			col:  utf8.RuneCountInString(text[idx+1:]),
+		}
+	}
+	return textPos{line: start.line, col: start.col + utf8.RuneCountInString(text)}
+}
+
+// isRealToken reports whether tok is part of the text. Tokens conjured by
+// error recovery ("<missing '}'>") have no index.
+func isRealToken(tok antlr.Token) bool {
+	return tok != nil && tok.GetTokenIndex() >= 0 && tok.GetStart() >= 0
+}
+
+// isRealTerminal reports whether node is present and stands for a real
+// token.
+func isRealTerminal(node antlr.TerminalNode) bool {
+	return node != nil && isRealToken(node.GetSymbol())
+}
+
+// nodeBounds returns the text covered by a parse tree node. Returns false
+// for empty rules and tokens conjured by error recovery.
+func nodeBounds(node antlr.Tree) (start, end textPos, ok bool) {
+	switch n := node.(type) {
+	case antlr.TerminalNode:
+		tok := n.GetSymbol()
+		if !isRealToken(tok) || tok.GetTokenType() == antlr.TokenEOF {
+			return textPos{}, textPos{}, false
+		}
+		return tokenStart(tok), tokenEnd(tok), true
+	case antlr.ParserRuleContext:
+		first, last := n.GetStart(), n.GetStop()
+		if !isRealToken(first) || !isRealToken(last) ||
+			last.GetTokenIndex() < first.GetTokenIndex() ||
+			first.GetTokenType() == antlr.TokenEOF {
+			return textPos{}, textPos{}, false
+		}
+		return tokenStart(first), tokenEnd(last), true
+	default:
+		return textPos{}, textPos{}, false
+	}
+}
+
+// startsLine reports whether tok is the first non-blank text on its line.
+func (t *syntaxTree) startsLine(tok antlr.Token) bool {
+	pos := tokenStart(tok)
+	if pos.line >= len(t.lines) {
+		return false
+	}
+	line := t.lines[pos.line]
+	prefix := line[:runeByteOffset(line, pos.col)]
+	return strings.TrimSpace(prefix) == ""
+}
+
+// lspPosition converts a text position to an LSP position.
+func (t *syntaxTree) lspPosition(p textPos) protocol.Position {
+	char := 0
+	if p.line < len(t.lines) {
+		line := t.lines[p.line]
+		offset := runeByteOffset(line, p.col)
+		if t.enc == PositionEncodingUTF8 {
+			char = offset
+		} else {
+			char = ByteToUTF16Offset([]byte(line), 0, offset)
+		}
+	}
+	return protocol.Position{Line: toUInteger(p.line), Character: toUInteger(char)}
+}
+
+// lspRange converts text bounds to an LSP range.
+func (t *syntaxTree) lspRange(start, end textPos) protocol.Range {
+	return protocol.Range{Start: t.lspPosition(start), End: t.lspPosition(end)}
+}
+
+// textPosition converts an LSP position to a text position, clamping the
+// character to the end of its line.
+func (t *syntaxTree) textPosition(pos protocol.Position) textPos {
+	line := int(pos.Line)
+	if line >= len(t.lines) {
+		return textPos{line: line}
+	}
+	content := []byte(t.lines[line])
+	var offset int
+	if t.enc == PositionEncodingUTF8 {
+		offset = min(int(pos.Character), len(content))
+	} else {
+		offset = utf16CharToByteOffset(content, 0, int(pos.Character))
+	}
+	return textPos{line: line, col: utf8.RuneCount(content[:offset])}
+}
+
+// runeByteOffset returns the byte offset of the rune at index col in s,
+// or len(s) if s is shorter.
+func runeByteOffset(s string, col int) int {
+	for i := range s {
+		if col == 0 {
+			return i
+		}
+		col--
+	}
+	return len(s)
+}
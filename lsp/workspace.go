@@ -207,6 +207,10 @@ type Workspace struct {
 	// Position encoding negotiated with client
 	posEncoding PositionEncoding
 
+	// Whether the client pulls diagnostics (LSP 3.17) instead of receiving
+	// publishDiagnostics notifications
+	pullDiagnostics bool
+
 	// Forward dependencies: entry URI -> set of imported URIs
 	importsByEntry map[string]map[string]struct{}
 
@@ -226,6 +230,23 @@ type Workspace struct {
 
 	// Analyzer for schema loading
 	analyzer *Analyzer
+
+	// Declarations of schema files on disk, keyed by canonical path, for
+	// auto-import completion. Entries are reused while the file's
+	// modification time and size are unchanged.
+	declCache map[string]schemaDecls
+	declMu    sync.Mutex
+
+	// Statistics of the instance data bound to schemas, keyed by canonical
+	// schema path.
+	dataStats map[string]*dataStatsEntry
+	dataMu    sync.Mutex
+
+	// Diagnostics of schema files that are not open, keyed by canonical
+	// path, from full-workspace analysis. passMu serializes passes.
+	fileDiags   map[string]*fileDiagnostics
+	fileDiagsMu sync.Mutex
+	passMu      sync.Mutex
 }
 
 // NewWorkspace creates a new workspace.
@@ -246,6 +267,14 @@ func NewWorkspace(logger *slog.Logger, cfg Config) *Workspace {
 		}
 	}
 
+	// Canonicalize bound schema paths to match analyzed document paths.
+	cfg.DataBindings = slices.Clone(cfg.DataBindings)
+	for i, b := range cfg.DataBindings {
+		if resolved, err := filepath.EvalSymlinks(b.Schema); err == nil {
+			cfg.DataBindings[i].Schema = filepath.Clean(resolved)
+		}
+	}
+
 	return &Workspace{
 		logger:           logger.With(slog.String("component", "workspace")),
 		config:           cfg,
@@ -259,6 +288,9 @@ func NewWorkspace(logger *slog.Logger, cfg Config) *Workspace {
 		debounces:        make(map[string]*debounceEntry),
 		publishedByEntry: make(map[string]map[string]struct{}),
 		analyzer:         NewAnalyzer(logger), // Pass base logger; analyzer adds its own component
+		declCache:        make(map[string]schemaDecls),
+		dataStats:        make(map[string]*dataStatsEntry),
+		fileDiags:        make(map[string]*fileDiagnostics),
 	}
 }
 
@@ -347,6 +379,21 @@ func (w *Workspace) PositionEncoding() PositionEncoding {
 	return w.posEncoding
 }
 
+// SetPullDiagnostics sets whether the client pulls diagnostics. While it
+// does, analysis results are not published.
+func (w *Workspace) SetPullDiagnostics(pull bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pullDiagnostics = pull
+}
+
+// PullDiagnostics reports whether the client pulls diagnostics.
+func (w *Workspace) PullDiagnostics() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.pullDiagnostics
+}
+
 // DocumentOpened handles a document being opened.
 func (w *Workspace) DocumentOpened(uri string, version int, text string) {
 	w.mu.Lock()
@@ -557,8 +604,10 @@ func (w *Workspace) AnalyzeAndPublish(notify Notifier, analyzeCtx context.Contex
 		overlays[d.SourceID.String()] = []byte(d.Text)
 	}
 
-	// Capture version before releasing lock
+	// Capture version and config before releasing lock
 	entryVersion := doc.Version
+	linter := w.config.Linter
+	customTypes := w.config.CustomTypes
 	w.mu.RUnlock()
 
 	// Find module root for this document (after releasing lock to avoid deadlock)
@@ -582,7 +631,11 @@ func (w *Workspace) AnalyzeAndPublish(notify Notifier, analyzeCtx context.Contex
 
 	// Perform analysis with cancellable context.
 	// Use canonical path to ensure consistent SourceID creation.
-	snapshot, err := w.analyzer.Analyze(analyzeCtx, canonicalPath, overlays, moduleRoot)
+	opts := []load.Option{load.WithCustomTypes(customTypes)}
+	if linter != nil {
+		opts = append(opts, load.WithLinter(linter))
+	}
+	snapshot, err := w.analyzer.Analyze(analyzeCtx, canonicalPath, overlays, moduleRoot, opts...)
 
 	// Check if context was cancelled - abort silently
 	if analyzeCtx.Err() != nil {
@@ -630,6 +683,16 @@ func (w *Workspace) AnalyzeAndPublish(notify Notifier, analyzeCtx context.Contex
 	w.snapshots[uri] = snapshot
 	w.mu.Unlock()
 
+	// Files importing this document see its unsaved content, so their
+	// workspace diagnostics are out of date.
+	w.invalidateImporters(PathToURI(canonicalPath))
+
+	// Validate bound data in the background, so statistics are ready when
+	// the client asks for code lenses.
+	if _, bound := w.dataBinding(canonicalPath); bound && snapshot.Schema != nil && !snapshot.Result.HasErrors() {
+		go w.dataStatsFor(canonicalPath, snapshot.Schema)
+	}
+
 	// Update dependency tracking for file watching
 	w.UpdateDependencies(uri, snapshot.ImportedPaths)
 
@@ -653,6 +716,9 @@ func (w *Workspace) publishSnapshotDiagnostics(notify Notifier, entryURI string,
 	if notify == nil {
 		return // No-op in test context without transport
 	}
+	if w.PullDiagnostics() {
+		return // The client pulls diagnostics instead
+	}
 
 	// Phase 1: Compute publication plan under lock
 	diagsByURI, staleURIs := w.computePublicationPlan(entryURI, snapshot)
@@ -687,6 +753,32 @@ func (w *Workspace) computePublicationPlan(entryURI string, snapshot *Snapshot)
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	diagsByURI = w.groupDiagnosticsLocked(snapshot)
+
+	// Find stale URIs (published by THIS entry before but not now)
+	// Per-entry tracking: only clear URIs from this entry's previous publication
+	currentURIs := make(map[string]struct{}, len(diagsByURI))
+	for uri := range diagsByURI {
+		currentURIs[uri] = struct{}{}
+	}
+	previousURIs := w.publishedByEntry[entryURI]
+	staleURIs = make([]string, 0)
+	for uri := range previousURIs {
+		if _, ok := currentURIs[uri]; !ok {
+			staleURIs = append(staleURIs, uri)
+		}
+	}
+
+	// Update published URIs tracking for this entry only
+	w.publishedByEntry[entryURI] = currentURIs
+
+	return diagsByURI, staleURIs
+}
+
+// groupDiagnosticsLocked groups a snapshot's diagnostics by URI, remapping
+// canonical URIs to the URIs of open documents. The caller must hold the
+// write lock.
+func (w *Workspace) groupDiagnosticsLocked(snapshot *Snapshot) map[string][]protocol.Diagnostic {
 	// Build map from canonical paths to open document URIs.
 	// The loader resolves symlinks, so diagnostics have canonical paths.
 	// We need to map them back to the URIs the client used to open documents.
@@ -696,11 +788,8 @@ func (w *Workspace) computePublicationPlan(entryURI string, snapshot *Snapshot)
 	}
 	canonicalToDocURI := w.canonicalToURI
 
-	// Collect current URIs with diagnostics
-	currentURIs := make(map[string]struct{})
-
 	// Group diagnostics by URI, remapping to open document URIs where applicable
-	diagsByURI = make(map[string][]protocol.Diagnostic)
+	diagsByURI := make(map[string][]protocol.Diagnostic)
 
 	for _, lspDiag := range snapshot.LSPDiagnostics {
 		// Remap the diagnostic URI to the open document URI if available
@@ -723,23 +812,8 @@ func (w *Workspace) computePublicationPlan(entryURI string, snapshot *Snapshot)
 		}
 
 		diagsByURI[pubURI] = append(diagsByURI[pubURI], diag)
-		currentURIs[pubURI] = struct{}{}
 	}
-
-	// Find stale URIs (published by THIS entry before but not now)
-	// Per-entry tracking: only clear URIs from this entry's previous publication
-	previousURIs := w.publishedByEntry[entryURI]
-	staleURIs = make([]string, 0)
-	for uri := range previousURIs {
-		if _, ok := currentURIs[uri]; !ok {
-			staleURIs = append(staleURIs, uri)
-		}
-	}
-
-	// Update published URIs tracking for this entry only
-	w.publishedByEntry[entryURI] = currentURIs
-
-	return diagsByURI, staleURIs
+	return diagsByURI
 }
 
 // publishDiagnostics publishes diagnostics for a URI.
@@ -763,7 +837,8 @@ func (w *Workspace) publishDiagnostics(notify Notifier, uri string, diagnostics
 }
 
 // FileChanged handles a watched file change notification.
-// It triggers reanalysis of any open documents that import the changed file.
+// It triggers reanalysis of any open documents that import the changed file
+// and marks the workspace diagnostics of its importers stale.
 //
 // The incoming URI is canonicalized before lookup to handle symlink and path
 // variations between what VS Code reports and what we store internally.
@@ -786,6 +861,8 @@ func (w *Workspace) FileChanged(ctx *glsp.Context, uri string, changeType protoc
 		}
 	}
 
+	w.invalidateImporters(canonicalURI)
+
 	w.mu.RLock()
 	// If this file is a dependency of open documents, reanalyze them
 	deps := make(map[string]struct{})
@@ -1371,7 +1448,8 @@ func (w *Workspace) AnalyzeMarkdownAndPublish(notify Notifier, analyzeCtx contex
 		overlays := map[string][]byte{
 			virtualPath: []byte(block.Content),
 		}
-		snapshot, err := w.analyzer.Analyze(analyzeCtx, virtualPath, overlays, "", load.WithDisallowImports())
+		snapshot, err := w.analyzer.Analyze(analyzeCtx, virtualPath, overlays, "",
+			load.WithDisallowImports(), load.WithCustomTypes(w.config.CustomTypes))
 		if err != nil {
 			w.logger.Warn("markdown block analysis failed",
 				slog.String("uri", uri), slog.Int("block", i), slog.String("error", err.Error()))
@@ -1413,10 +1491,26 @@ func (w *Workspace) AnalyzeMarkdownAndPublish(notify Notifier, analyzeCtx contex
 // publishMarkdownDiagnostics collects diagnostics from all block snapshots,
 // remaps positions from block-local to markdown coordinates, and publishes.
 func (w *Workspace) publishMarkdownDiagnostics(notify Notifier, snap *MarkdownDocumentSnapshot) {
-	if notify == nil {
+	if notify == nil || w.PullDiagnostics() {
 		return
 	}
 
+	allDiagnostics := w.markdownDiagnostics(snap)
+
+	// Update publishedByEntry tracking
+	w.mu.Lock()
+	w.publishedByEntry[snap.URI] = map[string]struct{}{snap.URI: {}}
+	w.mu.Unlock()
+
+	notify(protocol.ServerTextDocumentPublishDiagnostics, protocol.PublishDiagnosticsParams{
+		URI:         snap.URI,
+		Diagnostics: allDiagnostics,
+	})
+}
+
+// markdownDiagnostics collects diagnostics from all block snapshots and
+// remaps positions from block-local to markdown coordinates.
+func (w *Workspace) markdownDiagnostics(snap *MarkdownDocumentSnapshot) []protocol.Diagnostic {
 	var allDiagnostics []protocol.Diagnostic
 
 	for i, snapshot := range snap.Snapshots {
@@ -1499,16 +1593,8 @@ func (w *Workspace) publishMarkdownDiagnostics(notify Notifier, snap *MarkdownDo
 		}
 	}
 
-	// Update publishedByEntry tracking
-	w.mu.Lock()
-	w.publishedByEntry[snap.URI] = map[string]struct{}{snap.URI: {}}
-	w.mu.Unlock()
-
 	if allDiagnostics == nil {
 		allDiagnostics = []protocol.Diagnostic{}
 	}
-	notify(protocol.ServerTextDocumentPublishDiagnostics, protocol.PublishDiagnosticsParams{
-		URI:         snap.URI,
-		Diagnostics: allDiagnostics,
-	})
+	return allDiagnostics
 }
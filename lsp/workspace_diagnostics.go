@@ -0,0 +1,239 @@
+package lsp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/lint"
+	"github.com/simon-lentz/yammm/schema/load"
+)
+
+// fileDiagnostics holds the diagnostics of a schema file that is not open,
+// from full-workspace analysis. The entry is reused until the file changes
+// on disk or it is marked stale because a file it imports changed.
+type fileDiagnostics struct {
+	uri      string
+	modTime  time.Time
+	size     int64
+	stale    bool
+	resultID string
+	items    []protocol.Diagnostic
+}
+
+// diagnosticsResultID returns a result ID identifying a list of
+// diagnostics, so a client holding the same ID has the same diagnostics.
+func diagnosticsResultID(items []protocol.Diagnostic) string {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// workspaceSchemaRoots returns the directories searched for schema files
+// in full-workspace analysis: the workspace folders, or the configured
+// module root if there are none.
+func (w *Workspace) workspaceSchemaRoots() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if len(w.roots) > 0 {
+		return slices.Clone(w.roots)
+	}
+	if w.config.ModuleRoot != "" {
+		return []string{w.config.ModuleRoot}
+	}
+	return nil
+}
+
+// AnalyzeWorkspace analyzes every .yammm file under the workspace roots
+// that is not open, and records its diagnostics for workspace/diagnostic.
+// Open documents are analyzed as they change and are skipped. Files whose
+// recorded diagnostics are still current are not analyzed again. Imports
+// are recorded with UpdateDependencies, so a change to an imported file
+// marks its importers stale (see FileChanged).
+//
+// Passes are serialized; a call waits for a pass in progress.
+func (w *Workspace) AnalyzeWorkspace(ctx context.Context) {
+	w.passMu.Lock()
+	defer w.passMu.Unlock()
+
+	// Collect overlays and the files they shadow, as AnalyzeAndPublish does.
+	w.mu.RLock()
+	overlays := make(map[string][]byte, len(w.open))
+	for _, d := range w.open {
+		overlays[d.SourceID.String()] = []byte(d.Text)
+	}
+	linter := w.config.Linter
+	customTypes := w.config.CustomTypes
+	w.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, root := range w.workspaceSchemaRoots() {
+		for _, path := range findSchemaFiles(root) {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			if _, open := overlays[filepath.ToSlash(path)]; open {
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			w.analyzeFile(ctx, path, overlays, linter, customTypes)
+		}
+	}
+
+	// Forget files that were removed or opened.
+	var removed []string
+	w.fileDiagsMu.Lock()
+	for path, fd := range w.fileDiags {
+		if _, open := overlays[filepath.ToSlash(path)]; open || !seen[path] {
+			delete(w.fileDiags, path)
+			removed = append(removed, fd.uri)
+		}
+	}
+	w.fileDiagsMu.Unlock()
+	for _, uri := range removed {
+		w.UpdateDependencies(uri, nil)
+	}
+}
+
+// analyzeFile records the diagnostics of the schema file at the canonical
+// path, unless the recorded ones are current.
+func (w *Workspace) analyzeFile(ctx context.Context, path string, overlays map[string][]byte, linter *lint.Linter, customTypes *schema.CustomTypeRegistry) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	w.fileDiagsMu.Lock()
+	fd := w.fileDiags[path]
+	current := fd != nil && !fd.stale && fd.modTime.Equal(info.ModTime()) && fd.size == info.Size()
+	w.fileDiagsMu.Unlock()
+	if current {
+		return
+	}
+
+	// The analyzer loads the entry from its sources, so the file is read
+	// into a copy of the overlays.
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	sourceID, err := location.SourceIDFromAbsolutePath(path)
+	if err != nil {
+		return
+	}
+	sources := maps.Clone(overlays)
+	sources[sourceID.String()] = text
+
+	opts := []load.Option{load.WithCustomTypes(customTypes)}
+	if linter != nil {
+		opts = append(opts, load.WithLinter(linter))
+	}
+	snapshot, err := w.analyzer.Analyze(ctx, path, sources, w.findModuleRoot(path), opts...)
+	if ctx.Err() != nil {
+		return
+	}
+	if snapshot == nil {
+		w.logger.Debug("workspace analysis failed", slog.String("path", path), slog.Any("error", err))
+		return
+	}
+
+	uri := PathToURI(path)
+	w.mu.Lock()
+	items := w.groupDiagnosticsLocked(snapshot)[uri]
+	w.mu.Unlock()
+	if items == nil {
+		items = []protocol.Diagnostic{}
+	}
+
+	w.fileDiagsMu.Lock()
+	w.fileDiags[path] = &fileDiagnostics{
+		uri:      uri,
+		modTime:  info.ModTime(),
+		size:     info.Size(),
+		resultID: diagnosticsResultID(items),
+		items:    items,
+	}
+	w.fileDiagsMu.Unlock()
+	w.UpdateDependencies(uri, snapshot.ImportedPaths)
+}
+
+// invalidateImporters marks the recorded diagnostics of the files that
+// import the file at uri, and of the file itself, stale.
+func (w *Workspace) invalidateImporters(uri string) {
+	w.mu.RLock()
+	uris := map[string]struct{}{uri: {}}
+	for entry := range w.reverseDeps[uri] {
+		uris[entry] = struct{}{}
+	}
+	w.mu.RUnlock()
+
+	w.fileDiagsMu.Lock()
+	defer w.fileDiagsMu.Unlock()
+	for _, fd := range w.fileDiags {
+		if _, ok := uris[fd.uri]; ok {
+			fd.stale = true
+		}
+	}
+}
+
+// workspaceFileDiagnostics returns the recorded diagnostics of files that
+// are not open, sorted by URI.
+func (w *Workspace) workspaceFileDiagnostics() []fileDiagnostics {
+	w.fileDiagsMu.Lock()
+	defer w.fileDiagsMu.Unlock()
+	all := make([]fileDiagnostics, 0, len(w.fileDiags))
+	for _, fd := range w.fileDiags {
+		all = append(all, *fd)
+	}
+	slices.SortFunc(all, func(a, b fileDiagnostics) int {
+		switch {
+		case a.uri < b.uri:
+			return -1
+		case a.uri > b.uri:
+			return 1
+		}
+		return 0
+	})
+	return all
+}
+
+// snapshotDiagnostics returns the diagnostics the latest snapshot of an
+// open document has for the document itself.
+func (w *Workspace) snapshotDiagnostics(uri string) []protocol.Diagnostic {
+	snapshot := w.LatestSnapshot(uri)
+	if snapshot == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.groupDiagnosticsLocked(snapshot)[uri]
+}
+
+// closedFileDiagnostics returns the recorded diagnostics of a file that is
+// not open, or nil if workspace analysis has not seen it.
+func (w *Workspace) closedFileDiagnostics(uri string) []protocol.Diagnostic {
+	w.fileDiagsMu.Lock()
+	defer w.fileDiagsMu.Unlock()
+	for _, fd := range w.fileDiags {
+		if fd.uri == uri {
+			return fd.items
+		}
+	}
+	return nil
+}
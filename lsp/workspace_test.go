@@ -9,7 +9,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/simon-lentz/yammm/diag"
 	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema/lint"
 	protocol "github.com/tliron/glsp/protocol_3_16"
 )
 
@@ -2420,3 +2422,106 @@ func TestRemapPathToURI_NonexistentPathWithDotDot(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyzeAndPublish_Linter(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if resolved, err := filepath.EvalSymlinks(tmpDir); err == nil {
+		tmpDir = resolved
+	}
+	path := filepath.Join(tmpDir, "main.yammm")
+	content := `schema "test"
+
+type Company {
+	id String primary
+}
+
+type Person {
+	id String primary
+	--> works_at (one) Company
+}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	uri := PathToURI(path)
+
+	codes := func(cfg Config) []string {
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+		ws := NewWorkspace(logger, cfg)
+		ws.DocumentOpened(uri, 1, content)
+		ws.AnalyzeAndPublish(nil, t.Context(), uri)
+		snapshot := ws.LatestSnapshot(uri)
+		if snapshot == nil {
+			t.Fatal("no snapshot after analysis")
+		}
+		var got []string
+		for _, d := range snapshot.LSPDiagnostics {
+			if d.Diagnostic.Code != nil {
+				got = append(got, d.Diagnostic.Code.Value.(string))
+			}
+		}
+		return got
+	}
+
+	if got := codes(Config{ModuleRoot: tmpDir}); len(got) != 0 {
+		t.Errorf("without a linter, diagnostics = %v; want none", got)
+	}
+
+	linter, err := lint.New(lint.WithRules(lint.RuleRelationNameCase))
+	if err != nil {
+		t.Fatalf("lint.New failed: %v", err)
+	}
+	got := codes(Config{ModuleRoot: tmpDir, Linter: linter})
+	if len(got) != 1 || got[0] != diag.W_RELATION_NAME_CASE.String() {
+		t.Errorf("with a linter, diagnostics = %v; want [%s]", got, diag.W_RELATION_NAME_CASE)
+	}
+}
+
+func TestAnalyzeAndPublish_IgnoreComment(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if resolved, err := filepath.EvalSymlinks(tmpDir); err == nil {
+		tmpDir = resolved
+	}
+	path := filepath.Join(tmpDir, "main.yammm")
+	content := `schema "test"
+
+type Company {
+	id String primary
+}
+
+type Person {
+	id String primary
+	// yammm:ignore W_RELATION_NAME_CASE legacy name
+	--> works_at (one) Company
+	--> owns (many) Company
+}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	uri := PathToURI(path)
+
+	linter, err := lint.New(lint.WithRules(lint.RuleRelationNameCase))
+	if err != nil {
+		t.Fatalf("lint.New failed: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	ws := NewWorkspace(logger, Config{ModuleRoot: tmpDir, Linter: linter})
+	ws.DocumentOpened(uri, 1, content)
+	ws.AnalyzeAndPublish(nil, t.Context(), uri)
+	snapshot := ws.LatestSnapshot(uri)
+	if snapshot == nil {
+		t.Fatal("no snapshot after analysis")
+	}
+
+	if len(snapshot.LSPDiagnostics) != 1 {
+		t.Fatalf("diagnostics = %+v; want only the unsuppressed relation", snapshot.LSPDiagnostics)
+	}
+	if line := snapshot.LSPDiagnostics[0].Diagnostic.Range.Start.Line; line != 10 {
+		t.Errorf("diagnostic on line %d; want 10 (owns)", line)
+	}
+}
@@ -0,0 +1,37 @@
+// Package migrate rewrites instance data written against one schema revision
+// so that it conforms to the next revision.
+//
+// A migration is described declaratively by a [Spec]: type renames, property
+// renames, property moves, value maps for enum changes, expression-based
+// transforms evaluated with instance/eval, and property drops. A [Migrator]
+// applies the spec to [instance.RawInstance] sets or whole adapter/json
+// documents, then re-validates every rewritten instance against the new
+// schema. Instances that cannot be rewritten or that fail re-validation are
+// reported as [Failure] values whose diagnostics carry the original
+// provenance.
+//
+// # Basic Usage
+//
+//	spec := migrate.Spec{
+//	    Renames:   []migrate.PropertyRename{{Type: "Person", From: "surname", To: "last_name"}},
+//	    ValueMaps: []migrate.ValueMap{{Type: "Person", Property: "status",
+//	        Values: map[string]any{"banned": "suspended"}}},
+//	    Transforms: []migrate.Transform{{Type: "Person", Property: "age",
+//	        Expr: `$value -> Default(0)`}},
+//	}
+//	m, result := migrate.New(oldSchema, newSchema, spec)
+//	if m == nil {
+//	    return result // invalid spec
+//	}
+//	doc, out, err := m.MigrateJSON(ctx, adapter, sourceID, data)
+//	for _, f := range out.Failures {
+//	    fmt.Println(f.Result)
+//	}
+//
+// Pair a migration with [github.com/simon-lentz/yammm/schema/diff] to check
+// which data-breaking changes a spec must address.
+//
+// # Thread Safety
+//
+// A Migrator is immutable after construction and safe for concurrent use.
+package migrate
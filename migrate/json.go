@@ -0,0 +1,60 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	jsonadapter "github.com/simon-lentz/yammm/adapter/json"
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/location"
+)
+
+// MigrateJSON rewrites a JSON document of the form {"TypeName": [...]} (see
+// [jsonadapter.Adapter.ParseObject]) and returns the migrated document.
+//
+// The output contains only instances that validate against the new schema,
+// grouped under their new type names with keys in sorted order. Callers
+// should not replace the source document while Output.Failures is non-empty.
+// Parse diagnostics are included in Output.Result; if parsing produced
+// errors, no document is returned.
+//
+// Enable location tracking on the adapter to get line/column provenance in
+// failure diagnostics.
+func (m *Migrator) MigrateJSON(ctx context.Context, adapter *jsonadapter.Adapter, source location.SourceID, data []byte) ([]byte, Output, error) {
+	sets, parseResult := adapter.ParseObject(source, data)
+	if parseResult.HasErrors() {
+		return nil, Output{Result: parseResult}, nil
+	}
+
+	out, err := m.MigrateSet(ctx, sets)
+	if err != nil {
+		return nil, Output{}, err
+	}
+	if parseResult.Len() > 0 {
+		collector := diag.NewCollectorUnlimited()
+		collector.Merge(parseResult)
+		collector.Merge(out.Result)
+		out.Result = collector.Result()
+	}
+
+	doc := make(map[string][]map[string]any, len(out.Instances))
+	for typeName, raws := range out.Instances {
+		objs := make([]map[string]any, len(raws))
+		for i, raw := range raws {
+			objs[i] = raw.Properties
+		}
+		doc[typeName] = objs
+	}
+
+	var encoded []byte
+	if m.cfg.indent != "" {
+		encoded, err = json.MarshalIndent(doc, "", m.cfg.indent)
+	} else {
+		encoded, err = json.Marshal(doc)
+	}
+	if err != nil {
+		return nil, Output{}, fmt.Errorf("encode migrated document: %w", err)
+	}
+	return encoded, out, nil
+}
@@ -0,0 +1,568 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/instance"
+	"github.com/simon-lentz/yammm/instance/eval"
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/expr"
+)
+
+// Option configures a Migrator.
+type Option func(*config)
+
+// config holds Migrator configuration.
+type config struct {
+	validatorOpts []instance.ValidatorOption
+	indent        string
+}
+
+// WithValidatorOptions configures the validator used to re-validate migrated
+// instances against the new schema.
+func WithValidatorOptions(opts ...instance.ValidatorOption) Option {
+	return func(c *config) {
+		c.validatorOpts = append(c.validatorOpts, opts...)
+	}
+}
+
+// WithIndent sets the indentation used by MigrateJSON output.
+// Empty (the default) produces compact JSON.
+func WithIndent(indent string) Option {
+	return func(c *config) {
+		c.indent = indent
+	}
+}
+
+// Failure is an instance that could not be migrated.
+type Failure struct {
+	// TypeName is the instance's type name in the old schema.
+	TypeName string
+
+	// Original is the instance as supplied to the migrator.
+	Original instance.RawInstance
+
+	// Migrated is the rewritten instance that failed re-validation.
+	// Properties is nil when a migration step failed before re-validation.
+	Migrated instance.RawInstance
+
+	// Result explains the failure. Issues carry the original provenance.
+	Result diag.Result
+}
+
+// Output is the outcome of a migration run.
+type Output struct {
+	// Instances holds the migrated instances that validate against the new
+	// schema, keyed by new type name.
+	Instances map[string][]instance.RawInstance
+
+	// Failures lists unmigratable instances in input order.
+	Failures []Failure
+
+	// Result aggregates the diagnostics of every failure, plus any input
+	// parse diagnostics from MigrateJSON.
+	Result diag.Result
+}
+
+// Migrator rewrites instance data from an old schema revision to a new one.
+//
+// Migrator is immutable after construction and safe for concurrent use.
+type Migrator struct {
+	from       *schema.Schema
+	to         *schema.Schema
+	spec       Spec
+	cfg        *config
+	typeMap    map[string]string
+	moves      []parsedMove
+	transforms []compiledTransform
+	evaluator  *eval.Evaluator
+	validator  *instance.Validator
+}
+
+// parsedMove pairs a Move with its parsed paths.
+type parsedMove struct {
+	Move
+	from []pathSegment
+	to   []pathSegment
+}
+
+// compiledTransform pairs a Transform with its compiled expression.
+type compiledTransform struct {
+	Transform
+	expr expr.Expression
+}
+
+// New creates a Migrator for the given schema revisions and spec.
+//
+// The spec is checked against the schemas: every type it names must exist in
+// the old schema, type rename targets must exist in the new schema, and
+// transform expressions must compile. Problems are reported as
+// E_INVALID_MIGRATION and New returns a nil Migrator.
+//
+// Panics if either schema is nil.
+func New(from, to *schema.Schema, spec Spec, opts ...Option) (*Migrator, diag.Result) {
+	if from == nil || to == nil {
+		panic("migrate.New: nil schema")
+	}
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	m := &Migrator{
+		from:      from,
+		to:        to,
+		spec:      spec,
+		cfg:       cfg,
+		typeMap:   make(map[string]string, len(spec.Types)),
+		evaluator: eval.NewEvaluator(),
+		validator: instance.NewValidator(to, cfg.validatorOpts...),
+	}
+
+	collector := diag.NewCollectorUnlimited()
+	m.checkSpec(collector)
+	if collector.HasErrors() {
+		return nil, collector.Result()
+	}
+	return m, collector.Result()
+}
+
+// TargetType returns the new-schema type name for an old-schema type name.
+func (m *Migrator) TargetType(typeName string) string {
+	if to, ok := m.typeMap[typeName]; ok {
+		return to
+	}
+	return typeName
+}
+
+// checkSpec validates the spec against both schemas and compiles transforms.
+func (m *Migrator) checkSpec(collector *diag.Collector) {
+	invalid := func(step string, index int, msg string) {
+		collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_MIGRATION, msg).
+			WithDetail(diag.DetailKeyContext, fmt.Sprintf("%s[%d]", step, index)).Build())
+	}
+	requireOldType := func(step string, index int, typeName string) {
+		if _, ok := m.from.Type(typeName); !ok {
+			invalid(step, index, fmt.Sprintf("type %q not found in old schema %q", typeName, m.from.Name()))
+		}
+	}
+	requireField := func(step string, index int, name, field string) {
+		if field == "" {
+			invalid(step, index, fmt.Sprintf("%s is required", name))
+		}
+	}
+
+	for i, t := range m.spec.Types {
+		requireOldType("types", i, t.From)
+		if _, ok := m.to.Type(t.To); !ok {
+			invalid("types", i, fmt.Sprintf("type %q not found in new schema %q", t.To, m.to.Name()))
+		}
+		if prev, dup := m.typeMap[t.From]; dup {
+			invalid("types", i, fmt.Sprintf("type %q is already renamed to %q", t.From, prev))
+			continue
+		}
+		m.typeMap[t.From] = t.To
+	}
+	for i, r := range m.spec.Renames {
+		requireOldType("renames", i, r.Type)
+		requireField("renames", i, "from", r.From)
+		requireField("renames", i, "to", r.To)
+	}
+	for i, mv := range m.spec.Moves {
+		requireOldType("moves", i, mv.Type)
+		requireField("moves", i, "from", mv.From)
+		requireField("moves", i, "to", mv.To)
+		from, fromErr := parsePath(mv.From)
+		to, toErr := parsePath(mv.To)
+		if err := errors.Join(fromErr, toErr); err != nil {
+			invalid("moves", i, fmt.Sprintf("invalid move path: %s", err))
+			continue
+		}
+		m.moves = append(m.moves, parsedMove{Move: mv, from: from, to: to})
+	}
+	for i, vm := range m.spec.ValueMaps {
+		requireOldType("valueMaps", i, vm.Type)
+		requireField("valueMaps", i, "property", vm.Property)
+	}
+	for i, d := range m.spec.Drops {
+		requireOldType("drops", i, d.Type)
+		requireField("drops", i, "property", d.Property)
+	}
+	for i, t := range m.spec.Transforms {
+		requireOldType("transforms", i, t.Type)
+		requireField("transforms", i, "property", t.Property)
+		exprCollector := diag.NewCollector(1)
+		compiled := expr.CompileString(t.Expr, exprCollector, location.NewSourceID(fmt.Sprintf("migration://transforms/%d", i)))
+		if compiled == nil || exprCollector.HasErrors() {
+			msg := fmt.Sprintf("transform expression %q does not compile", t.Expr)
+			for issue := range exprCollector.Result().Issues() {
+				msg += ": " + issue.Message()
+			}
+			invalid("transforms", i, msg)
+			continue
+		}
+		m.transforms = append(m.transforms, compiledTransform{Transform: t, expr: compiled})
+	}
+}
+
+// Migrate rewrites instances of an old-schema type and re-validates them
+// against the new schema.
+//
+// The input instances are not modified. Migrated instances keep their
+// original provenance, so diagnostics for unmigratable records point at the
+// source data. Returns an error only if ctx is cancelled.
+func (m *Migrator) Migrate(ctx context.Context, typeName string, raws []instance.RawInstance) (Output, error) {
+	return m.MigrateSet(ctx, map[string][]instance.RawInstance{typeName: raws})
+}
+
+// MigrateSet rewrites a set of instances keyed by old-schema type name, as
+// produced by the adapter Parse* functions. Types are processed in sorted
+// order so output is deterministic.
+func (m *Migrator) MigrateSet(ctx context.Context, sets map[string][]instance.RawInstance) (Output, error) {
+	out := Output{Instances: make(map[string][]instance.RawInstance)}
+	collector := diag.NewCollectorUnlimited()
+
+	for _, typeName := range slices.Sorted(maps.Keys(sets)) {
+		newType := m.TargetType(typeName)
+		for _, raw := range sets[typeName] {
+			if err := ctx.Err(); err != nil {
+				return Output{}, err
+			}
+			migrated, failure, err := m.migrateOne(ctx, typeName, newType, raw)
+			if err != nil {
+				return Output{}, err
+			}
+			if failure != nil {
+				out.Failures = append(out.Failures, *failure)
+				collector.Merge(failure.Result)
+				continue
+			}
+			out.Instances[newType] = append(out.Instances[newType], migrated)
+		}
+	}
+
+	out.Result = collector.Result()
+	return out, nil
+}
+
+// migrateOne rewrites and re-validates a single instance.
+func (m *Migrator) migrateOne(ctx context.Context, typeName, newType string, raw instance.RawInstance) (instance.RawInstance, *Failure, error) {
+	props, _ := deepCopy(raw.Properties).(map[string]any)
+	if props == nil {
+		props = make(map[string]any)
+	}
+	if issue := m.applySteps(typeName, props, raw.Provenance); issue != nil {
+		return instance.RawInstance{}, &Failure{
+			TypeName: typeName,
+			Original: raw,
+			Result:   resultOf(*issue),
+		}, nil
+	}
+
+	migrated := instance.RawInstance{Properties: props, Provenance: raw.Provenance}
+	_, vf, err := m.validator.ValidateOne(ctx, newType, migrated)
+	if err != nil {
+		return instance.RawInstance{}, nil, err
+	}
+	if vf != nil {
+		return instance.RawInstance{}, &Failure{
+			TypeName: typeName,
+			Original: raw,
+			Migrated: migrated,
+			Result:   vf.Result,
+		}, nil
+	}
+	return migrated, nil, nil
+}
+
+// applySteps applies the spec to props in place. Composed children are
+// migrated first, using the old schema to locate composition fields.
+func (m *Migrator) applySteps(typeName string, props map[string]any, prov *instance.Provenance) *diag.Issue {
+	if typ, ok := m.from.Type(typeName); ok {
+		for _, rel := range typ.AllCompositionsSlice() {
+			key, ok := lookupKey(props, rel.FieldName())
+			if !ok {
+				continue
+			}
+			childType := rel.Target().String()
+			switch child := props[key].(type) {
+			case map[string]any:
+				if issue := m.applySteps(childType, child, prov.AtKey(key)); issue != nil {
+					return issue
+				}
+			case []any:
+				for i, elem := range child {
+					if elemMap, ok := elem.(map[string]any); ok {
+						if issue := m.applySteps(childType, elemMap, prov.AtKey(key).AtIndex(i)); issue != nil {
+							return issue
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for _, r := range m.spec.Renames {
+		if r.Type != typeName {
+			continue
+		}
+		key, ok := lookupKey(props, r.From)
+		if !ok {
+			continue
+		}
+		if existing, taken := lookupKey(props, r.To); taken && existing != key {
+			return failIssue(prov, typeName, r.To, fmt.Sprintf("cannot rename %q to %q: field %q already exists", key, r.To, existing))
+		}
+		val := props[key]
+		delete(props, key)
+		props[r.To] = val
+	}
+
+	for _, mv := range m.moves {
+		if mv.Type != typeName {
+			continue
+		}
+		val, ok := takePath(props, mv.from)
+		if !ok {
+			continue
+		}
+		if err := putPath(props, mv.to, val); err != nil {
+			return failIssue(prov, typeName, mv.To, fmt.Sprintf("cannot move %q to %q: %s", mv.From, mv.To, err))
+		}
+	}
+
+	for _, vm := range m.spec.ValueMaps {
+		if vm.Type != typeName {
+			continue
+		}
+		key, ok := lookupKey(props, vm.Property)
+		if !ok || props[key] == nil {
+			continue
+		}
+		if repl, ok := vm.Values[fmt.Sprint(props[key])]; ok {
+			props[key] = repl
+		}
+	}
+
+	for _, t := range m.transforms {
+		if t.Type != typeName {
+			continue
+		}
+		key, ok := lookupKey(props, t.Property)
+		if !ok {
+			key = t.Property
+		}
+		scope := eval.PropertyScopeFromMap(props).WithVar("value", props[key])
+		val, err := m.evaluator.Evaluate(t.expr, scope)
+		if err != nil {
+			return failIssue(prov, typeName, t.Property, fmt.Sprintf("transform of %q failed: %s", t.Property, err))
+		}
+		if val == nil {
+			delete(props, key)
+		} else {
+			props[key] = val
+		}
+	}
+
+	for _, d := range m.spec.Drops {
+		if d.Type != typeName {
+			continue
+		}
+		if key, ok := lookupKey(props, d.Property); ok {
+			delete(props, key)
+		}
+	}
+	return nil
+}
+
+// failIssue builds an E_MIGRATION_FAIL issue located at the instance field.
+func failIssue(prov *instance.Provenance, typeName, field, msg string) *diag.Issue {
+	b := diag.NewIssue(diag.Error, diag.E_MIGRATION_FAIL, msg).
+		WithPath(prov.SourceName(), prov.AtKey(field).Path().String()).
+		WithDetails(diag.TypeProp(typeName, field)...)
+	if span := prov.Span(); !span.IsZero() {
+		b = b.WithSpan(span)
+	}
+	issue := b.Build()
+	return &issue
+}
+
+// resultOf wraps a single issue in a Result.
+func resultOf(issue diag.Issue) diag.Result {
+	collector := diag.NewCollector(1)
+	collector.Collect(issue)
+	return collector.Result()
+}
+
+// lookupKey finds a field by exact name, falling back to a case-insensitive
+// match to mirror the validator's default property-name handling.
+func lookupKey(props map[string]any, name string) (string, bool) {
+	if _, ok := props[name]; ok {
+		return name, true
+	}
+	for _, k := range slices.Sorted(maps.Keys(props)) {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// pathSegment is one step of a Move path: a field name with an optional
+// list index ("address[0]").
+type pathSegment struct {
+	field string
+	index int // -1 when the segment has no index
+}
+
+// parsePath splits a dot-separated Move path into segments.
+func parsePath(p string) ([]pathSegment, error) {
+	parts := strings.Split(p, ".")
+	segments := make([]pathSegment, len(parts))
+	for i, part := range parts {
+		seg := pathSegment{field: part, index: -1}
+		if open := strings.IndexByte(part, '['); open >= 0 {
+			if !strings.HasSuffix(part, "]") {
+				return nil, fmt.Errorf("invalid segment %q", part)
+			}
+			n, err := strconv.Atoi(part[open+1 : len(part)-1])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid index in segment %q", part)
+			}
+			seg = pathSegment{field: part[:open], index: n}
+		}
+		if seg.field == "" {
+			return nil, fmt.Errorf("empty field in path %q", p)
+		}
+		segments[i] = seg
+	}
+	return segments, nil
+}
+
+// takePath removes and returns the value at a field path.
+func takePath(props map[string]any, segments []pathSegment) (any, bool) {
+	cur := props
+	for i, seg := range segments {
+		key, ok := lookupKey(cur, seg.field)
+		if !ok {
+			return nil, false
+		}
+		last := i == len(segments)-1
+		val := cur[key]
+		if seg.index >= 0 {
+			list, ok := val.([]any)
+			if !ok || seg.index >= len(list) {
+				return nil, false
+			}
+			if last {
+				elem := list[seg.index]
+				cur[key] = slices.Delete(list, seg.index, seg.index+1)
+				return elem, true
+			}
+			val = list[seg.index]
+		} else if last {
+			delete(cur, key)
+			return val, true
+		}
+		next, ok := val.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		cur = next
+	}
+	return nil, false
+}
+
+// putPath stores a value at a field path, creating intermediate objects and
+// list elements. An index may address an existing element or append one.
+func putPath(props map[string]any, segments []pathSegment, val any) error {
+	cur := props
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		key, ok := lookupKey(cur, seg.field)
+		if !ok {
+			key = seg.field
+		}
+
+		if seg.index < 0 {
+			if last {
+				if ok {
+					return fmt.Errorf("field %q already exists", seg.field)
+				}
+				cur[key] = val
+				return nil
+			}
+			if !ok {
+				cur[key] = make(map[string]any)
+			}
+			next, isMap := cur[key].(map[string]any)
+			if !isMap {
+				return fmt.Errorf("field %q is not an object", key)
+			}
+			cur = next
+			continue
+		}
+
+		var list []any
+		if ok {
+			existing, isList := cur[key].([]any)
+			if !isList {
+				return fmt.Errorf("field %q is not a list", key)
+			}
+			list = existing
+		}
+		switch {
+		case seg.index < len(list):
+			if last {
+				return fmt.Errorf("element %s[%d] already exists", key, seg.index)
+			}
+		case seg.index == len(list):
+			var elem any = make(map[string]any)
+			if last {
+				elem = val
+			}
+			list = append(list, elem)
+			cur[key] = list
+			if last {
+				return nil
+			}
+		default:
+			return fmt.Errorf("index %d out of range for %q", seg.index, key)
+		}
+		next, isMap := list[seg.index].(map[string]any)
+		if !isMap {
+			return fmt.Errorf("element %s[%d] is not an object", key, seg.index)
+		}
+		cur = next
+	}
+	return nil
+}
+
+// deepCopy copies the JSON-like structure of v so steps never mutate the
+// caller's instances.
+func deepCopy(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, elem := range val {
+			out[k] = deepCopy(elem)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, elem := range val {
+			out[i] = deepCopy(elem)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,342 @@
+package migrate_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	jsonadapter "github.com/simon-lentz/yammm/adapter/json"
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/instance"
+	"github.com/simon-lentz/yammm/instance/path"
+	"github.com/simon-lentz/yammm/internal/source"
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/migrate"
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/load"
+)
+
+const oldSchemaSrc = `schema "people"
+type Person {
+	id String primary
+	surname String required
+	first String
+	status Enum["active", "inactive", "banned"]
+	street String
+	legacy String
+	age Integer
+}`
+
+const newSchemaSrc = `schema "people"
+type Member {
+	id String primary
+	last_name String required
+	display String
+	status Enum["active", "inactive", "suspended"]
+	age Integer required
+	*-> ADDRESS Address
+}
+part type Address {
+	street String required
+}`
+
+func mustLoad(t *testing.T, src, name string) *schema.Schema {
+	t.Helper()
+	s, result, err := load.LoadString(t.Context(), src, name)
+	require.NoError(t, err)
+	require.True(t, result.OK(), "schema errors: %v", result.Messages())
+	return s
+}
+
+func fullSpec() migrate.Spec {
+	return migrate.Spec{
+		Types:   []migrate.TypeRename{{From: "Person", To: "Member"}},
+		Renames: []migrate.PropertyRename{{Type: "Person", From: "surname", To: "last_name"}},
+		Moves:   []migrate.Move{{Type: "Person", From: "street", To: "address[0].street"}},
+		ValueMaps: []migrate.ValueMap{{
+			Type: "Person", Property: "status",
+			Values: map[string]any{"banned": "suspended"},
+		}},
+		Transforms: []migrate.Transform{
+			{Type: "Person", Property: "display", Expr: `first + " " + last_name`},
+			{Type: "Person", Property: "age", Expr: `$value -> Default(0)`},
+		},
+		Drops: []migrate.Drop{
+			{Type: "Person", Property: "legacy"},
+			{Type: "Person", Property: "first"},
+		},
+	}
+}
+
+func newMigrator(t *testing.T, spec migrate.Spec, opts ...migrate.Option) *migrate.Migrator {
+	t.Helper()
+	m, result := migrate.New(mustLoad(t, oldSchemaSrc, "old.yammm"), mustLoad(t, newSchemaSrc, "new.yammm"), spec, opts...)
+	require.NotNil(t, m, "spec errors: %v", result.Messages())
+	return m
+}
+
+func TestMigrate_AppliesAllSteps(t *testing.T) {
+	t.Parallel()
+
+	m := newMigrator(t, fullSpec())
+	raws := []instance.RawInstance{{Properties: map[string]any{
+		"id":      "p1",
+		"surname": "Lovelace",
+		"first":   "Ada",
+		"status":  "banned",
+		"street":  "1 Analytical Way",
+		"legacy":  "x",
+	}}}
+
+	out, err := m.Migrate(t.Context(), "Person", raws)
+	require.NoError(t, err)
+	require.Empty(t, out.Failures, "unexpected failures: %v", out.Result.Messages())
+	assert.True(t, out.Result.OK())
+
+	require.Len(t, out.Instances["Member"], 1)
+	assert.Equal(t, map[string]any{
+		"id":        "p1",
+		"last_name": "Lovelace",
+		"display":   "Ada Lovelace",
+		"status":    "suspended",
+		"age":       int64(0),
+		"address":   []any{map[string]any{"street": "1 Analytical Way"}},
+	}, out.Instances["Member"][0].Properties)
+	assert.Equal(t, "Member", m.TargetType("Person"))
+	assert.Equal(t, "Other", m.TargetType("Other"))
+}
+
+func TestMigrate_DoesNotMutateInput(t *testing.T) {
+	t.Parallel()
+
+	m := newMigrator(t, fullSpec())
+	props := map[string]any{"id": "p1", "surname": "X", "first": "Y", "street": "Z"}
+	_, err := m.Migrate(t.Context(), "Person", []instance.RawInstance{{Properties: props}})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"id": "p1", "surname": "X", "first": "Y", "street": "Z"}, props)
+}
+
+func TestMigrate_RevalidationFailure(t *testing.T) {
+	t.Parallel()
+
+	spec := fullSpec()
+	spec.ValueMaps = nil // "banned" is no longer a valid status
+	m := newMigrator(t, spec)
+
+	raws := []instance.RawInstance{
+		{Properties: map[string]any{"id": "ok", "surname": "A", "first": "B", "status": "active"}},
+		{
+			Properties: map[string]any{"id": "bad", "surname": "C", "first": "D", "status": "banned"},
+			Provenance: instance.NewProvenance("people.json", path.Root().Key("Person").Index(1), location.Span{}),
+		},
+	}
+	out, err := m.Migrate(t.Context(), "Person", raws)
+	require.NoError(t, err)
+
+	require.Len(t, out.Instances["Member"], 1)
+	assert.Equal(t, "ok", out.Instances["Member"][0].Properties["id"])
+
+	require.Len(t, out.Failures, 1)
+	f := out.Failures[0]
+	assert.Equal(t, "Person", f.TypeName)
+	assert.Equal(t, "banned", f.Original.Properties["status"])
+	assert.Equal(t, "D C", f.Migrated.Properties["display"])
+	assert.True(t, f.Result.HasErrors())
+	assert.True(t, out.Result.HasErrors())
+
+	var constraintIssue diag.Issue
+	for issue := range f.Result.Issues() {
+		if issue.Code() == diag.E_CONSTRAINT_FAIL {
+			constraintIssue = issue
+		}
+	}
+	require.False(t, constraintIssue.Code().IsZero(), "expected E_CONSTRAINT_FAIL: %v", f.Result.Messages())
+	assert.Equal(t, "people.json", constraintIssue.SourceName())
+	assert.Contains(t, constraintIssue.Path(), "$.Person[1]")
+}
+
+func TestMigrate_StepFailure(t *testing.T) {
+	t.Parallel()
+
+	t.Run("transform error", func(t *testing.T) {
+		t.Parallel()
+		spec := fullSpec()
+		spec.Transforms = append(spec.Transforms, migrate.Transform{Type: "Person", Property: "age", Expr: `$missing + 1`})
+		m := newMigrator(t, spec)
+
+		out, err := m.Migrate(t.Context(), "Person", []instance.RawInstance{
+			{Properties: map[string]any{"id": "p1", "surname": "A", "first": "B"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, out.Failures, 1)
+		assert.Nil(t, out.Failures[0].Migrated.Properties)
+
+		issues := out.Failures[0].Result.IssuesSlice()
+		require.Len(t, issues, 1)
+		assert.Equal(t, diag.E_MIGRATION_FAIL, issues[0].Code())
+		assert.Contains(t, issues[0].Message(), "undefined variable")
+		assert.Equal(t, "$.age", issues[0].Path())
+	})
+
+	t.Run("move target occupied", func(t *testing.T) {
+		t.Parallel()
+		spec := migrate.Spec{Moves: []migrate.Move{{Type: "Person", From: "first", To: "surname"}}}
+		m, result := migrate.New(mustLoad(t, oldSchemaSrc, "old.yammm"), mustLoad(t, oldSchemaSrc, "same.yammm"), spec)
+		require.NotNil(t, m, result.Messages())
+
+		out, err := m.Migrate(t.Context(), "Person", []instance.RawInstance{
+			{Properties: map[string]any{"id": "p1", "surname": "A", "first": "B"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, out.Failures, 1)
+		assert.Contains(t, out.Failures[0].Result.IssuesSlice()[0].Message(), "already exists")
+	})
+
+	t.Run("rename target occupied with different case", func(t *testing.T) {
+		t.Parallel()
+		spec := migrate.Spec{Renames: []migrate.PropertyRename{{Type: "Person", From: "first", To: "Surname"}}}
+		m, result := migrate.New(mustLoad(t, oldSchemaSrc, "old.yammm"), mustLoad(t, oldSchemaSrc, "same.yammm"), spec)
+		require.NotNil(t, m, result.Messages())
+
+		out, err := m.Migrate(t.Context(), "Person", []instance.RawInstance{
+			{Properties: map[string]any{"id": "p1", "surname": "A", "first": "B"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, out.Failures, 1)
+		assert.Contains(t, out.Failures[0].Result.IssuesSlice()[0].Message(), `field "surname" already exists`)
+	})
+}
+
+func TestMigrate_MoveListElement(t *testing.T) {
+	t.Parallel()
+
+	const src = `schema "tags"
+type Item {
+	id String primary
+	tags List<String>
+	first String
+}`
+	spec := migrate.Spec{Moves: []migrate.Move{{Type: "Item", From: "tags[0]", To: "first"}}}
+	m, result := migrate.New(mustLoad(t, src, "old.yammm"), mustLoad(t, src, "new.yammm"), spec)
+	require.NotNil(t, m, result.Messages())
+
+	out, err := m.Migrate(t.Context(), "Item", []instance.RawInstance{
+		{Properties: map[string]any{"id": "i1", "tags": []any{"a", "b", "c"}}},
+	})
+	require.NoError(t, err)
+	require.Empty(t, out.Failures, "unexpected failures: %v", out.Result.Messages())
+	assert.Equal(t, map[string]any{
+		"id":    "i1",
+		"tags":  []any{"b", "c"},
+		"first": "a",
+	}, out.Instances["Item"][0].Properties)
+}
+
+func TestNew_InvalidSpec(t *testing.T) {
+	t.Parallel()
+
+	oldS := mustLoad(t, oldSchemaSrc, "old.yammm")
+	newS := mustLoad(t, newSchemaSrc, "new.yammm")
+
+	tests := []struct {
+		name    string
+		spec    migrate.Spec
+		context string
+	}{
+		{"unknown old type", migrate.Spec{Drops: []migrate.Drop{{Type: "Nobody", Property: "x"}}}, "drops[0]"},
+		{"unknown new type", migrate.Spec{Types: []migrate.TypeRename{{From: "Person", To: "Ghost"}}}, "types[0]"},
+		{"missing field", migrate.Spec{Renames: []migrate.PropertyRename{{Type: "Person", From: "surname"}}}, "renames[0]"},
+		{"bad expression", migrate.Spec{Transforms: []migrate.Transform{{Type: "Person", Property: "age", Expr: `1 +`}}}, "transforms[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m, result := migrate.New(oldS, newS, tt.spec)
+			assert.Nil(t, m)
+			require.True(t, result.HasErrors())
+			issue := result.IssuesSlice()[0]
+			assert.Equal(t, diag.E_INVALID_MIGRATION, issue.Code())
+			var ctx string
+			for _, d := range issue.Details() {
+				if d.Key == diag.DetailKeyContext {
+					ctx = d.Value
+				}
+			}
+			assert.Equal(t, tt.context, ctx)
+		})
+	}
+}
+
+func TestNew_NilSchemaPanics(t *testing.T) {
+	t.Parallel()
+
+	s := mustLoad(t, oldSchemaSrc, "old.yammm")
+	assert.Panics(t, func() { migrate.New(nil, s, migrate.Spec{}) })
+	assert.Panics(t, func() { migrate.New(s, nil, migrate.Spec{}) })
+}
+
+func TestMigrateJSON(t *testing.T) {
+	t.Parallel()
+
+	m := newMigrator(t, fullSpec(), migrate.WithIndent("  "))
+	sourceID := location.MustNewSourceID("test://people.json")
+	data := []byte(`{
+  "Person": [
+    {"id": "p1", "surname": "Hopper", "first": "Grace", "status": "active"},
+    {"id": "p2", "surname": "Liskov", "first": "Barbara", "status": "retired"}
+  ]
+}`)
+	reg := source.NewRegistry()
+	require.NoError(t, reg.Register(sourceID, data))
+	adapter, err := jsonadapter.NewAdapter(reg, jsonadapter.WithTrackLocations(true))
+	require.NoError(t, err)
+
+	doc, out, err := m.MigrateJSON(t.Context(), adapter, sourceID, data)
+	require.NoError(t, err)
+
+	var decoded map[string][]map[string]any
+	require.NoError(t, json.Unmarshal(doc, &decoded))
+	require.Len(t, decoded["Member"], 1)
+	assert.Equal(t, "Grace Hopper", decoded["Member"][0]["display"])
+	assert.NotContains(t, decoded, "Person")
+	assert.Contains(t, string(doc), "\n  ", "WithIndent should pretty-print")
+
+	require.Len(t, out.Failures, 1)
+	failure := out.Failures[0]
+	assert.Equal(t, "p2", failure.Original.Properties["id"])
+	var located bool
+	for issue := range failure.Result.Issues() {
+		if span := issue.Span(); span.Source == sourceID && span.End.Line == 4 {
+			located = true
+		}
+	}
+	assert.True(t, located, "failure should point into line 4 of the source: %v", failure.Result.Messages())
+}
+
+func TestMigrateJSON_ParseError(t *testing.T) {
+	t.Parallel()
+
+	m := newMigrator(t, fullSpec())
+	adapter, err := jsonadapter.NewAdapter(nil)
+	require.NoError(t, err)
+
+	doc, out, err := m.MigrateJSON(t.Context(), adapter, location.MustNewSourceID("test://bad.json"), []byte(`[`))
+	require.NoError(t, err)
+	assert.Nil(t, doc)
+	assert.True(t, out.Result.HasErrors())
+}
+
+func TestMigrate_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	m := newMigrator(t, fullSpec())
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := m.Migrate(ctx, "Person", []instance.RawInstance{{Properties: map[string]any{"id": "p1"}}})
+	require.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package migrate
+
+// Spec is a declarative description of how instance data written against an
+// old schema revision is rewritten for a new revision.
+//
+// All type names in a Spec refer to the OLD schema; property names and paths
+// refer to the instance fields as they exist when the step runs. For each
+// instance, steps are applied in this order, and in declaration order within
+// each kind:
+//
+//  1. Types (the instance is re-tagged with the new type name)
+//  2. Renames
+//  3. Moves
+//  4. ValueMaps
+//  5. Transforms
+//  6. Drops
+//
+// Composed children are migrated as instances of their part type before the
+// steps of their parent run.
+//
+// Spec has JSON tags so it can be stored alongside the schema revisions it
+// connects.
+type Spec struct {
+	Types      []TypeRename     `json:"types,omitzero"`
+	Renames    []PropertyRename `json:"renames,omitzero"`
+	Moves      []Move           `json:"moves,omitzero"`
+	ValueMaps  []ValueMap       `json:"valueMaps,omitzero"`
+	Transforms []Transform      `json:"transforms,omitzero"`
+	Drops      []Drop           `json:"drops,omitzero"`
+}
+
+// TypeRename maps instances of an old type to a differently named new type.
+type TypeRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// PropertyRename renames a field of every instance of Type. Relation fields
+// are renamed the same way as properties.
+type PropertyRename struct {
+	Type string `json:"type"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Move relocates a value within an instance. From and To are dot-separated
+// field paths whose segments may carry a list index, e.g. moving "street" to
+// "address[0].street" places it in the first composed Address child.
+// Intermediate objects and list elements are created as needed (an index may
+// address an existing element or append exactly one) and are left in place
+// when emptied. A missing source is a no-op; an occupied target fails the
+// instance.
+type Move struct {
+	Type string `json:"type"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ValueMap replaces values of a property, typically to follow an Enum change.
+// Values is keyed by the old value's string form; values without an entry
+// are left unchanged and re-validation reports any that remain invalid.
+type ValueMap struct {
+	Type     string         `json:"type"`
+	Property string         `json:"property"`
+	Values   map[string]any `json:"values"`
+}
+
+// Transform computes a property from an expression in the invariant
+// expression language. The expression sees the instance's current fields as
+// properties and the target property's current value as $value. A nil result
+// removes the property.
+//
+// Example: {Type: "Person", Property: "full_name", Expr: `first + " " + last`}
+// or {Type: "Person", Property: "age", Expr: `$value -> Default(0)`}.
+type Transform struct {
+	Type     string `json:"type"`
+	Property string `json:"property"`
+	Expr     string `json:"expr"`
+}
+
+// Drop removes a field from every instance of Type.
+type Drop struct {
+	Type     string `json:"type"`
+	Property string `json:"property"`
+}
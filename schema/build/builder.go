@@ -44,6 +44,7 @@ type Builder struct {
 	dataTypes      []*parse.DataTypeDecl
 	documentation  string
 	registry       *schema.Registry
+	customTypes    *schema.CustomTypeRegistry
 	issueLimit     int
 	importResolver ImportResolver
 }
@@ -105,6 +106,13 @@ func (b *Builder) WithRegistry(r *schema.Registry) *Builder {
 	return b
 }
 
+// WithCustomTypes makes the Go datatypes in the registry available to
+// datatype references, like built-in types.
+func (b *Builder) WithCustomTypes(r *schema.CustomTypeRegistry) *Builder {
+	b.customTypes = r
+	return b
+}
+
 // WithIssueLimit sets the maximum number of diagnostics to collect.
 //
 // Default is 100. Use 0 for unlimited (not recommended for large schemas).
@@ -260,7 +268,7 @@ func (b *Builder) Build() (*schema.Schema, diag.Result) {
 	}
 
 	// Complete the schema with resolved imports
-	s := complete.Complete(model, sourceID, collector, registry, resolvedImports)
+	s := complete.Complete(model, sourceID, collector, registry, resolvedImports, b.customTypes)
 	if s == nil {
 		return nil, collector.Result()
 	}
@@ -372,6 +380,13 @@ func (b *Builder) validateInput(collector *diag.Collector) bool {
 					WithDetail(diag.DetailKeyName, inv.Name).Build())
 				hasErrors = true
 			}
+			if inv.Severity > diag.Hint {
+				collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_INVARIANT,
+					fmt.Sprintf("invariant %q in type %q has invalid severity %d", inv.Name, t.name, inv.Severity)).
+					WithDetail(diag.DetailKeyTypeName, t.name).
+					WithDetail(diag.DetailKeyName, inv.Name).Build())
+				hasErrors = true
+			}
 		}
 	}
 
@@ -673,8 +688,20 @@ func (t *TypeBuilder) WithTypeDocumentation(doc string) *TypeBuilder {
 //	// Or construct directly:
 //	ageExpr := expr.SExpr{expr.Op(">"), expr.SExpr{expr.Op("$"), expr.NewLiteral("age")}, expr.NewLiteral(int64(0))}
 func (t *TypeBuilder) WithInvariant(name string, e expr.Expression, doc string) *TypeBuilder {
+	return t.WithNamedInvariant("", diag.Error, name, e, doc)
+}
+
+// WithNamedInvariant adds an invariant with a stable identifier and a failure
+// severity, the programmatic form of `!warn id "name" expr`.
+//
+// An empty id leaves the invariant identified by its name. Severities less
+// severe than diag.Error report failures without rejecting the instance;
+// diag.Fatal is treated as diag.Error.
+func (t *TypeBuilder) WithNamedInvariant(id string, severity diag.Severity, name string, e expr.Expression, doc string) *TypeBuilder {
 	t.state.invariants = append(t.state.invariants, &parse.InvariantDecl{
 		Name:          name,
+		ID:            id,
+		Severity:      severity,
 		Expr:          e,
 		Documentation: doc,
 		Span:          location.Span{}, // Synthetic - no source location
@@ -6,6 +6,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ConstraintKind identifies the kind of constraint.
@@ -22,8 +23,12 @@ const (
 	KindEnum
 	KindPattern
 	KindVector
-	KindList  // ordered collection with element constraint
-	KindAlias // reference to DataType
+	KindList   // ordered collection with element constraint
+	KindAlias  // reference to DataType
+	KindCustom // registered Go datatype (see CustomType)
+	KindMap    // string-keyed map with key/value constraints
+	KindObject // inline record with named fields
+	KindUnion  // value matching any of several member constraints
 )
 
 // String returns the name of the constraint kind.
@@ -53,6 +58,14 @@ func (k ConstraintKind) String() string {
 		return "List"
 	case KindAlias:
 		return "Alias"
+	case KindCustom:
+		return "Custom"
+	case KindMap:
+		return "Map"
+	case KindObject:
+		return "Object"
+	case KindUnion:
+		return "Union"
 	default:
 		return fmt.Sprintf("ConstraintKind(%d)", k)
 	}
@@ -76,11 +89,18 @@ type Constraint interface {
 	// would also be accepted by this constraint (child's valid set is a subset
 	// of parent's valid set).
 	//
-	// For bounds-based constraints (String, Integer, Float): child min >= parent min
-	// and child max <= parent max.
+	// For bounds-based constraints (String, Integer, Float, Timestamp, Date):
+	// child min >= parent min and child max <= parent max. Timestamp and Date
+	// formats must match, and fixed and relative bounds do not narrow each other.
 	// For EnumConstraint: child values must be a subset of parent values.
-	// For parameterless or non-narrowable constraints (Boolean, Date, UUID,
-	// Timestamp, Pattern, Vector): delegates to Equal (no narrowing supported).
+	// For List and Map: element/key/value constraints must narrow and length
+	// bounds must tighten; a List may narrow to a Set, but not the reverse.
+	// For Object: same field names, each field narrows, and optional fields
+	// may become required (never the reverse).
+	// For Union: every member of the child (or the child itself, if it is not
+	// a union) must narrow some member of the parent.
+	// For parameterless or non-narrowable constraints (Boolean, UUID, Pattern,
+	// Vector, Custom): delegates to Equal (no narrowing supported).
 	// For AliasConstraint: resolves alias chain first, then delegates.
 	NarrowsTo(child Constraint) bool
 
@@ -315,9 +335,124 @@ func (c BooleanConstraint) NarrowsTo(child Constraint) bool { return c.Equal(chi
 
 func (BooleanConstraint) IsResolved() bool { return true }
 
-// TimestampConstraint constrains timestamp values with an optional format.
+// Default layouts for Timestamp and Date values without a declared format.
+const (
+	DefaultTimestampLayout = time.RFC3339
+	DefaultDateLayout      = "2006-01-02"
+)
+
+// TimeBound is one end of a Timestamp or Date range. A bound is absent, a
+// fixed instant, or relative to the validation clock ("now" for Timestamp,
+// "today" for Date) shifted by an offset.
+type TimeBound struct {
+	at       time.Time
+	offset   time.Duration
+	set      bool
+	relative bool
+}
+
+// NoTimeBound returns an absent bound.
+func NoTimeBound() TimeBound {
+	return TimeBound{}
+}
+
+// TimeBoundAt returns a bound at the fixed instant t.
+func TimeBoundAt(t time.Time) TimeBound {
+	return TimeBound{at: t, set: true}
+}
+
+// TimeBoundRelative returns a bound at the validation clock's current time
+// plus offset. For Date constraints the offset is a whole number of days.
+func TimeBoundRelative(offset time.Duration) TimeBound {
+	return TimeBound{offset: offset, set: true, relative: true}
+}
+
+// IsSet reports whether the bound is present.
+func (b TimeBound) IsSet() bool { return b.set }
+
+// IsRelative reports whether the bound is relative to the validation clock.
+func (b TimeBound) IsRelative() bool { return b.relative }
+
+// Time returns the instant of a fixed bound.
+func (b TimeBound) Time() time.Time { return b.at }
+
+// Offset returns the offset of a relative bound from the validation clock.
+func (b TimeBound) Offset() time.Duration { return b.offset }
+
+// Resolve returns the instant the bound denotes when the validation clock
+// reads now.
+func (b TimeBound) Resolve(now time.Time) time.Time {
+	if b.relative {
+		return now.Add(b.offset)
+	}
+	return b.at
+}
+
+func (b TimeBound) equal(o TimeBound) bool {
+	return b.set == o.set && b.relative == o.relative && b.offset == o.offset && b.at.Equal(o.at)
+}
+
+// narrows reports whether child lies within parent on the lower (min) or
+// upper (max) side. Fixed and relative bounds are not comparable.
+func (b TimeBound) narrows(child TimeBound, lower bool) bool {
+	switch {
+	case !b.set:
+		return true
+	case !child.set || b.relative != child.relative:
+		return false
+	case b.relative && lower:
+		return child.offset >= b.offset
+	case b.relative:
+		return child.offset <= b.offset
+	case lower:
+		return !child.at.Before(b.at)
+	default:
+		return !child.at.After(b.at)
+	}
+}
+
+// format renders the bound in DSL syntax. Fixed bounds use layout; relative
+// bounds are written from clock with the offset in days if days is set.
+func (b TimeBound) format(layout, clock string, days bool) string {
+	switch {
+	case !b.set:
+		return "_"
+	case !b.relative:
+		return strconv.Quote(b.at.Format(layout))
+	case b.offset == 0:
+		return clock
+	}
+	sign, offset := "+", b.offset
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	if days {
+		return fmt.Sprintf("%s %s %d", clock, sign, offset/(24*time.Hour))
+	}
+	return fmt.Sprintf("%s %s %q", clock, sign, offset.String())
+}
+
+// timeRangeString renders the bracketed parameters of a Timestamp or Date
+// type, or "" if it has none.
+func timeRangeString(format, layout string, minB, maxB TimeBound, clock string, days bool) string {
+	var params []string
+	if format != "" {
+		params = append(params, strconv.Quote(format))
+	}
+	if minB.set || maxB.set {
+		params = append(params, minB.format(layout, clock, days), maxB.format(layout, clock, days))
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+// TimestampConstraint constrains timestamp values with an optional format
+// and optional min/max bounds.
 type TimestampConstraint struct {
-	format string
+	format   string
+	min, max TimeBound
 }
 
 // NewTimestampConstraint creates a TimestampConstraint with no format.
@@ -330,45 +465,104 @@ func NewTimestampConstraintFormatted(format string) TimestampConstraint {
 	return TimestampConstraint{format: format}
 }
 
+// NewTimestampConstraintBounded creates a TimestampConstraint with a Go time
+// format ("" for RFC 3339) and the given bounds. Use [NoTimeBound] to leave
+// a side open.
+func NewTimestampConstraintBounded(format string, minBound, maxBound TimeBound) TimestampConstraint {
+	return TimestampConstraint{format: format, min: minBound, max: maxBound}
+}
+
 func (TimestampConstraint) Kind() ConstraintKind { return KindTimestamp }
 func (TimestampConstraint) constraint()          {}
 
 func (c TimestampConstraint) Format() string { return c.format }
+func (c TimestampConstraint) Min() TimeBound { return c.min }
+func (c TimestampConstraint) Max() TimeBound { return c.max }
 
-func (c TimestampConstraint) String() string {
+// Layout returns the Go time layout values are parsed with: the declared
+// format, or [DefaultTimestampLayout].
+func (c TimestampConstraint) Layout() string {
 	if c.format == "" {
-		return "Timestamp"
+		return DefaultTimestampLayout
 	}
-	return fmt.Sprintf("Timestamp[%q]", c.format)
+	return c.format
+}
+
+func (c TimestampConstraint) String() string {
+	return "Timestamp" + timeRangeString(c.format, c.Layout(), c.min, c.max, "now", false)
 }
 
 func (c TimestampConstraint) Equal(other Constraint) bool {
 	o, ok := resolveAlias(other).(TimestampConstraint)
-	return ok && c.format == o.format
+	return ok && c.format == o.format && c.min.equal(o.min) && c.max.equal(o.max)
 }
 
-func (c TimestampConstraint) NarrowsTo(child Constraint) bool { return c.Equal(child) }
+func (c TimestampConstraint) NarrowsTo(child Constraint) bool {
+	o, ok := resolveAlias(child).(TimestampConstraint)
+	if !ok || c.format != o.format {
+		return false
+	}
+	return c.min.narrows(o.min, true) && c.max.narrows(o.max, false)
+}
 
 func (TimestampConstraint) IsResolved() bool { return true }
 
-// DateConstraint constrains date values. It has no parameters.
-type DateConstraint struct{}
+// DateConstraint constrains date values with an optional format and
+// optional min/max bounds.
+type DateConstraint struct {
+	format   string
+	min, max TimeBound
+}
 
 // NewDateConstraint creates a DateConstraint.
 func NewDateConstraint() DateConstraint {
 	return DateConstraint{}
 }
 
+// NewDateConstraintFormatted creates a DateConstraint with a Go time format.
+func NewDateConstraintFormatted(format string) DateConstraint {
+	return DateConstraint{format: format}
+}
+
+// NewDateConstraintBounded creates a DateConstraint with a Go time format
+// ("" for YYYY-MM-DD) and the given bounds. Fixed bounds are dates at
+// midnight UTC; relative bounds are offsets from today in whole days.
+func NewDateConstraintBounded(format string, minBound, maxBound TimeBound) DateConstraint {
+	return DateConstraint{format: format, min: minBound, max: maxBound}
+}
+
 func (DateConstraint) Kind() ConstraintKind { return KindDate }
 func (DateConstraint) constraint()          {}
-func (DateConstraint) String() string       { return "Date" }
+
+func (c DateConstraint) Format() string { return c.format }
+func (c DateConstraint) Min() TimeBound { return c.min }
+func (c DateConstraint) Max() TimeBound { return c.max }
+
+// Layout returns the Go time layout values are parsed with: the declared
+// format, or [DefaultDateLayout].
+func (c DateConstraint) Layout() string {
+	if c.format == "" {
+		return DefaultDateLayout
+	}
+	return c.format
+}
+
+func (c DateConstraint) String() string {
+	return "Date" + timeRangeString(c.format, c.Layout(), c.min, c.max, "today", true)
+}
 
 func (c DateConstraint) Equal(other Constraint) bool {
-	_, ok := resolveAlias(other).(DateConstraint)
-	return ok
+	o, ok := resolveAlias(other).(DateConstraint)
+	return ok && c.format == o.format && c.min.equal(o.min) && c.max.equal(o.max)
 }
 
-func (c DateConstraint) NarrowsTo(child Constraint) bool { return c.Equal(child) }
+func (c DateConstraint) NarrowsTo(child Constraint) bool {
+	o, ok := resolveAlias(child).(DateConstraint)
+	if !ok || c.format != o.format {
+		return false
+	}
+	return c.min.narrows(o.min, true) && c.max.narrows(o.max, false)
+}
 
 func (DateConstraint) IsResolved() bool { return true }
 
@@ -565,12 +759,14 @@ func (c VectorConstraint) NarrowsTo(child Constraint) bool { return c.Equal(chil
 func (VectorConstraint) IsResolved() bool { return true }
 
 // ListConstraint constrains list values with an element type and optional min/max length.
+// A set is a ListConstraint whose elements must be unique.
 type ListConstraint struct {
 	element Constraint
 	minLen  int64
 	maxLen  int64
 	hasMin  bool
 	hasMax  bool
+	unique  bool
 }
 
 // NewListConstraint creates a ListConstraint with no length bounds.
@@ -593,6 +789,20 @@ func NewListConstraintBounded(element Constraint, minLen, maxLen int64) ListCons
 	return c
 }
 
+// NewSetConstraint creates a set: a ListConstraint with unique elements and
+// no length bounds.
+func NewSetConstraint(element Constraint) ListConstraint {
+	return ListConstraint{element: element, unique: true}
+}
+
+// NewSetConstraintBounded creates a set with the given length bounds.
+// Pass -1 for minLen or maxLen to indicate no bound.
+func NewSetConstraintBounded(element Constraint, minLen, maxLen int64) ListConstraint {
+	c := NewListConstraintBounded(element, minLen, maxLen)
+	c.unique = true
+	return c
+}
+
 func (ListConstraint) Kind() ConstraintKind { return KindList }
 func (ListConstraint) constraint()          {}
 
@@ -605,27 +815,15 @@ func (c ListConstraint) MinLen() (int64, bool) { return c.minLen, c.hasMin }
 // MaxLen returns the maximum list length and whether a maximum is set.
 func (c ListConstraint) MaxLen() (int64, bool) { return c.maxLen, c.hasMax }
 
+// IsUnique reports whether elements must be unique (a Set).
+func (c ListConstraint) IsUnique() bool { return c.unique }
+
 func (c ListConstraint) String() string {
-	var sb strings.Builder
-	sb.WriteString("List<")
-	sb.WriteString(c.element.String())
-	sb.WriteString(">")
-	if c.hasMin || c.hasMax {
-		sb.WriteString("[")
-		if c.hasMin {
-			sb.WriteString(strconv.FormatInt(c.minLen, 10))
-		} else {
-			sb.WriteString("_")
-		}
-		sb.WriteString(", ")
-		if c.hasMax {
-			sb.WriteString(strconv.FormatInt(c.maxLen, 10))
-		} else {
-			sb.WriteString("_")
-		}
-		sb.WriteString("]")
+	name := "List<"
+	if c.unique {
+		name = "Set<"
 	}
-	return sb.String()
+	return name + c.element.String() + ">" + boundsString(c.minLen, c.maxLen, c.hasMin, c.hasMax)
 }
 
 func (c ListConstraint) Equal(other Constraint) bool {
@@ -633,7 +831,8 @@ func (c ListConstraint) Equal(other Constraint) bool {
 	if !ok {
 		return false
 	}
-	if c.hasMin != o.hasMin || c.hasMax != o.hasMax || c.minLen != o.minLen || c.maxLen != o.maxLen {
+	if c.hasMin != o.hasMin || c.hasMax != o.hasMax || c.minLen != o.minLen || c.maxLen != o.maxLen ||
+		c.unique != o.unique {
 		return false
 	}
 	return c.element.Equal(o.element)
@@ -644,6 +843,10 @@ func (c ListConstraint) NarrowsTo(child Constraint) bool {
 	if !ok {
 		return false
 	}
+	// A list may narrow to a set, never the reverse.
+	if c.unique && !o.unique {
+		return false
+	}
 	// Element must narrow.
 	if !c.element.NarrowsTo(o.element) {
 		return false
@@ -663,6 +866,278 @@ func (c ListConstraint) IsResolved() bool {
 	return c.element.IsResolved()
 }
 
+// boundsString formats optional length bounds as "[min, max]" ("" if unbounded).
+func boundsString(minLen, maxLen int64, hasMin, hasMax bool) string {
+	if !hasMin && !hasMax {
+		return ""
+	}
+	minStr, maxStr := "_", "_"
+	if hasMin {
+		minStr = strconv.FormatInt(minLen, 10)
+	}
+	if hasMax {
+		maxStr = strconv.FormatInt(maxLen, 10)
+	}
+	return "[" + minStr + ", " + maxStr + "]"
+}
+
+// MapConstraint constrains string-keyed map values (JSON objects with
+// arbitrary keys) with key and value constraints and optional size bounds.
+type MapConstraint struct {
+	key    Constraint
+	value  Constraint
+	minLen int64
+	maxLen int64
+	hasMin bool
+	hasMax bool
+}
+
+// NewMapConstraint creates a MapConstraint with no size bounds.
+func NewMapConstraint(key, value Constraint) MapConstraint {
+	return MapConstraint{key: key, value: value}
+}
+
+// NewMapConstraintBounded creates a MapConstraint with the given size bounds.
+// Pass -1 for minLen or maxLen to indicate no bound.
+func NewMapConstraintBounded(key, value Constraint, minLen, maxLen int64) MapConstraint {
+	c := MapConstraint{key: key, value: value}
+	if minLen >= 0 {
+		c.minLen = minLen
+		c.hasMin = true
+	}
+	if maxLen >= 0 {
+		c.maxLen = maxLen
+		c.hasMax = true
+	}
+	return c
+}
+
+func (MapConstraint) Kind() ConstraintKind { return KindMap }
+func (MapConstraint) constraint()          {}
+
+// Key returns the key constraint. Keys are always strings; the constraint
+// further restricts them (e.g., Enum, Pattern, String[1, 32]).
+func (c MapConstraint) Key() Constraint { return c.key }
+
+// Value returns the value constraint.
+func (c MapConstraint) Value() Constraint { return c.value }
+
+// MinLen returns the minimum number of entries and whether a minimum is set.
+func (c MapConstraint) MinLen() (int64, bool) { return c.minLen, c.hasMin }
+
+// MaxLen returns the maximum number of entries and whether a maximum is set.
+func (c MapConstraint) MaxLen() (int64, bool) { return c.maxLen, c.hasMax }
+
+func (c MapConstraint) String() string {
+	return "Map<" + c.key.String() + ", " + c.value.String() + ">" +
+		boundsString(c.minLen, c.maxLen, c.hasMin, c.hasMax)
+}
+
+func (c MapConstraint) Equal(other Constraint) bool {
+	o, ok := resolveAlias(other).(MapConstraint)
+	if !ok {
+		return false
+	}
+	if c.hasMin != o.hasMin || c.hasMax != o.hasMax || c.minLen != o.minLen || c.maxLen != o.maxLen {
+		return false
+	}
+	return c.key.Equal(o.key) && c.value.Equal(o.value)
+}
+
+func (c MapConstraint) NarrowsTo(child Constraint) bool {
+	o, ok := resolveAlias(child).(MapConstraint)
+	if !ok {
+		return false
+	}
+	if !c.key.NarrowsTo(o.key) || !c.value.NarrowsTo(o.value) {
+		return false
+	}
+	// Child min must be >= parent min (if parent has a min).
+	if c.hasMin && (!o.hasMin || o.minLen < c.minLen) {
+		return false
+	}
+	// Child max must be <= parent max (if parent has a max).
+	if c.hasMax && (!o.hasMax || o.maxLen > c.maxLen) {
+		return false
+	}
+	return true
+}
+
+func (c MapConstraint) IsResolved() bool {
+	return c.key.IsResolved() && c.value.IsResolved()
+}
+
+// ObjectField is a named field of an [ObjectConstraint].
+type ObjectField struct {
+	name       string
+	constraint Constraint
+	required   bool
+}
+
+// NewObjectField creates an object field.
+func NewObjectField(name string, constraint Constraint, required bool) ObjectField {
+	return ObjectField{name: name, constraint: constraint, required: required}
+}
+
+// Name returns the field name (matched exactly against value keys).
+func (f ObjectField) Name() string { return f.name }
+
+// Constraint returns the field's value constraint.
+func (f ObjectField) Constraint() Constraint { return f.constraint }
+
+// IsRequired reports whether the field must be present and non-null.
+func (f ObjectField) IsRequired() bool { return f.required }
+
+// ObjectConstraint constrains inline record values: JSON objects with a fixed
+// set of named fields. Unlike a part type it has no identity, primary key or
+// graph node; it is a structured value of its property.
+type ObjectConstraint struct {
+	fields []ObjectField
+}
+
+// NewObjectConstraint creates an ObjectConstraint with fields in declaration order.
+func NewObjectConstraint(fields []ObjectField) ObjectConstraint {
+	return ObjectConstraint{fields: slices.Clone(fields)}
+}
+
+func (ObjectConstraint) Kind() ConstraintKind { return KindObject }
+func (ObjectConstraint) constraint()          {}
+
+// Fields returns the fields in declaration order.
+func (c ObjectConstraint) Fields() []ObjectField { return slices.Clone(c.fields) }
+
+// Field returns the field with the given name.
+func (c ObjectConstraint) Field(name string) (ObjectField, bool) {
+	for _, f := range c.fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return ObjectField{}, false
+}
+
+func (c ObjectConstraint) String() string {
+	if len(c.fields) == 0 {
+		return "Object {}"
+	}
+	var sb strings.Builder
+	sb.WriteString("Object { ")
+	for i, f := range c.fields {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(f.name)
+		sb.WriteString(" ")
+		sb.WriteString(f.constraint.String())
+		if f.required {
+			sb.WriteString(" required")
+		}
+	}
+	sb.WriteString(" }")
+	return sb.String()
+}
+
+// Equal reports whether both objects declare the same fields (in any order)
+// with equal constraints and requiredness.
+func (c ObjectConstraint) Equal(other Constraint) bool {
+	o, ok := resolveAlias(other).(ObjectConstraint)
+	if !ok || len(c.fields) != len(o.fields) {
+		return false
+	}
+	for _, f := range c.fields {
+		of, ok := o.Field(f.name)
+		if !ok || f.required != of.required || !f.constraint.Equal(of.constraint) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c ObjectConstraint) NarrowsTo(child Constraint) bool {
+	o, ok := resolveAlias(child).(ObjectConstraint)
+	if !ok || len(c.fields) != len(o.fields) {
+		return false
+	}
+	for _, f := range c.fields {
+		of, ok := o.Field(f.name)
+		if !ok || (f.required && !of.required) || !f.constraint.NarrowsTo(of.constraint) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c ObjectConstraint) IsResolved() bool {
+	for _, f := range c.fields {
+		if !f.constraint.IsResolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// UnionConstraint accepts a value that satisfies any of its members
+// (`String | Integer`). Members are checked in declaration order; the first
+// member the value satisfies determines its canonical form.
+type UnionConstraint struct {
+	members []Constraint
+}
+
+// NewUnionConstraint creates a UnionConstraint with members in declaration order.
+func NewUnionConstraint(members ...Constraint) UnionConstraint {
+	return UnionConstraint{members: slices.Clone(members)}
+}
+
+func (UnionConstraint) Kind() ConstraintKind { return KindUnion }
+func (UnionConstraint) constraint()          {}
+
+// Members returns the member constraints in declaration order.
+func (c UnionConstraint) Members() []Constraint { return slices.Clone(c.members) }
+
+func (c UnionConstraint) String() string {
+	parts := make([]string, len(c.members))
+	for i, m := range c.members {
+		parts[i] = m.String()
+	}
+	return strings.Join(parts, " | ")
+}
+
+// Equal reports whether both unions have the same members, in any order.
+func (c UnionConstraint) Equal(other Constraint) bool {
+	o, ok := resolveAlias(other).(UnionConstraint)
+	if !ok || len(c.members) != len(o.members) {
+		return false
+	}
+	for _, m := range c.members {
+		if !slices.ContainsFunc(o.members, m.Equal) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c UnionConstraint) NarrowsTo(child Constraint) bool {
+	children := []Constraint{child}
+	if o, ok := resolveAlias(child).(UnionConstraint); ok {
+		children = o.members
+	}
+	for _, ch := range children {
+		if !slices.ContainsFunc(c.members, func(m Constraint) bool { return m.NarrowsTo(ch) }) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c UnionConstraint) IsResolved() bool {
+	for _, m := range c.members {
+		if !m.IsResolved() {
+			return false
+		}
+	}
+	return true
+}
+
 // AliasConstraint represents a reference to a named DataType.
 // The underlying constraint is resolved for equality comparisons.
 type AliasConstraint struct {
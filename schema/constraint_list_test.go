@@ -245,3 +245,21 @@ func TestListConstraint_Accessors(t *testing.T) {
 		assert.False(t, hasMax)
 	})
 }
+
+func TestSetConstraint(t *testing.T) {
+	t.Parallel()
+	set := schema.NewSetConstraint(schema.NewStringConstraint())
+	list := schema.NewListConstraint(schema.NewStringConstraint())
+
+	assert.Equal(t, schema.KindList, set.Kind())
+	assert.True(t, set.IsUnique())
+	assert.False(t, list.IsUnique())
+	assert.Equal(t, "Set<String>", set.String())
+	assert.Equal(t, "Set<String>[1, 5]", schema.NewSetConstraintBounded(schema.NewStringConstraint(), 1, 5).String())
+
+	assert.True(t, set.Equal(schema.NewSetConstraint(schema.NewStringConstraint())))
+	assert.False(t, set.Equal(list), "set and list differ")
+	assert.True(t, list.NarrowsTo(set), "list narrows to set")
+	assert.False(t, set.NarrowsTo(list), "set does not widen to list")
+	assert.True(t, set.NarrowsTo(schema.NewSetConstraintBounded(schema.NewStringConstraintBounded(1, 8), 0, 3)))
+}
@@ -0,0 +1,182 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func point(latRequired bool) schema.ObjectConstraint {
+	return schema.NewObjectConstraint([]schema.ObjectField{
+		schema.NewObjectField("lat", schema.NewFloatConstraint(), latRequired),
+		schema.NewObjectField("lon", schema.NewFloatConstraint(), true),
+	})
+}
+
+func TestMapConstraint_String(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		c    schema.MapConstraint
+		want string
+	}{
+		{
+			name: "bare",
+			c:    schema.NewMapConstraint(schema.NewStringConstraint(), schema.NewIntegerConstraint()),
+			want: "Map<String, Integer>",
+		},
+		{
+			name: "bounded",
+			c:    schema.NewMapConstraintBounded(schema.NewStringConstraint(), schema.NewIntegerConstraint(), 1, -1),
+			want: "Map<String, Integer>[1, _]",
+		},
+		{
+			name: "nested value",
+			c:    schema.NewMapConstraint(schema.NewStringConstraint(), schema.NewListConstraint(schema.NewStringConstraint())),
+			want: "Map<String, List<String>>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, schema.KindMap, tt.c.Kind())
+			assert.Equal(t, tt.want, tt.c.String())
+		})
+	}
+}
+
+func TestMapConstraint_NarrowsTo(t *testing.T) {
+	t.Parallel()
+	str := schema.NewStringConstraint()
+	tests := []struct {
+		name   string
+		parent schema.Constraint
+		child  schema.Constraint
+		want   bool
+	}{
+		{
+			name:   "same",
+			parent: schema.NewMapConstraint(str, schema.NewIntegerConstraint()),
+			child:  schema.NewMapConstraint(str, schema.NewIntegerConstraint()),
+			want:   true,
+		},
+		{
+			name:   "key narrows",
+			parent: schema.NewMapConstraint(str, schema.NewIntegerConstraint()),
+			child:  schema.NewMapConstraint(schema.NewStringConstraintBounded(1, 8), schema.NewIntegerConstraint()),
+			want:   true,
+		},
+		{
+			name:   "value widens fails",
+			parent: schema.NewMapConstraint(str, schema.NewIntegerConstraintBounded(0, true, 0, false)),
+			child:  schema.NewMapConstraint(str, schema.NewIntegerConstraint()),
+			want:   false,
+		},
+		{
+			name:   "size bounds tighten",
+			parent: schema.NewMapConstraintBounded(str, str, -1, 10),
+			child:  schema.NewMapConstraintBounded(str, str, 1, 5),
+			want:   true,
+		},
+		{
+			name:   "size bounds widen fails",
+			parent: schema.NewMapConstraintBounded(str, str, -1, 10),
+			child:  schema.NewMapConstraint(str, str),
+			want:   false,
+		},
+		{
+			name:   "list child fails",
+			parent: schema.NewMapConstraint(str, str),
+			child:  schema.NewListConstraint(str),
+			want:   false,
+		},
+		{
+			name:   "alias child",
+			parent: schema.NewMapConstraint(str, str),
+			child:  schema.NewAliasConstraint("Labels", schema.NewMapConstraint(str, str)),
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.parent.NarrowsTo(tt.child))
+		})
+	}
+}
+
+func TestObjectConstraint_String(t *testing.T) {
+	t.Parallel()
+	c := point(true)
+	assert.Equal(t, schema.KindObject, c.Kind())
+	assert.Equal(t, "Object { lat Float required, lon Float required }", c.String())
+	assert.Equal(t, "Object {}", schema.NewObjectConstraint(nil).String())
+}
+
+func TestObjectConstraint_Field(t *testing.T) {
+	t.Parallel()
+	c := point(false)
+
+	f, ok := c.Field("lat")
+	assert.True(t, ok)
+	assert.Equal(t, "lat", f.Name())
+	assert.False(t, f.IsRequired())
+	assert.Equal(t, schema.KindFloat, f.Constraint().Kind())
+
+	_, ok = c.Field("Lat")
+	assert.False(t, ok, "field names are matched exactly")
+	assert.Len(t, c.Fields(), 2)
+}
+
+func TestObjectConstraint_EqualAndNarrowsTo(t *testing.T) {
+	t.Parallel()
+	reordered := schema.NewObjectConstraint([]schema.ObjectField{
+		schema.NewObjectField("lon", schema.NewFloatConstraint(), true),
+		schema.NewObjectField("lat", schema.NewFloatConstraint(), true),
+	})
+	narrowed := schema.NewObjectConstraint([]schema.ObjectField{
+		schema.NewObjectField("lat", schema.NewFloatConstraintBounded(-90, true, 90, true), true),
+		schema.NewObjectField("lon", schema.NewFloatConstraint(), true),
+	})
+	extra := schema.NewObjectConstraint([]schema.ObjectField{
+		schema.NewObjectField("lat", schema.NewFloatConstraint(), true),
+		schema.NewObjectField("lon", schema.NewFloatConstraint(), true),
+		schema.NewObjectField("alt", schema.NewFloatConstraint(), false),
+	})
+
+	tests := []struct {
+		name   string
+		parent schema.Constraint
+		child  schema.Constraint
+		equal  bool
+		narrow bool
+	}{
+		{"same", point(true), point(true), true, true},
+		{"field order ignored", point(true), reordered, true, true},
+		{"optional to required", point(false), point(true), false, true},
+		{"required to optional fails", point(true), point(false), false, false},
+		{"field narrows", point(true), narrowed, false, true},
+		{"extra field fails", point(true), extra, false, false},
+		{"alias child", point(true), schema.NewAliasConstraint("Point", point(true)), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.equal, tt.parent.Equal(tt.child), "Equal")
+			assert.Equal(t, tt.narrow, tt.parent.NarrowsTo(tt.child), "NarrowsTo")
+		})
+	}
+}
+
+func TestStructuredConstraint_IsResolved(t *testing.T) {
+	t.Parallel()
+	unresolved := schema.NewAliasConstraint("Unknown", nil)
+
+	assert.True(t, point(true).IsResolved())
+	assert.False(t, schema.NewObjectConstraint([]schema.ObjectField{
+		schema.NewObjectField("a", unresolved, false),
+	}).IsResolved())
+	assert.True(t, schema.NewMapConstraint(schema.NewStringConstraint(), schema.NewIntegerConstraint()).IsResolved())
+	assert.False(t, schema.NewMapConstraint(schema.NewStringConstraint(), unresolved).IsResolved())
+}
@@ -0,0 +1,98 @@
+package schema_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func day(year int, month time.Month, d int) schema.TimeBound {
+	return schema.TimeBoundAt(time.Date(year, month, d, 0, 0, 0, 0, time.UTC))
+}
+
+func TestTimeConstraint_String(t *testing.T) {
+	t.Parallel()
+	none := schema.NoTimeBound()
+	tests := []struct {
+		name string
+		c    schema.Constraint
+		want string
+	}{
+		{"timestamp upper now", schema.NewTimestampConstraintBounded("", none, schema.TimeBoundRelative(0)), "Timestamp[_, now]"},
+		{"timestamp fixed", schema.NewTimestampConstraintBounded("", day(2020, 1, 1), none), `Timestamp["2020-01-01T00:00:00Z", _]`},
+		{"timestamp layout and offset", schema.NewTimestampConstraintBounded("2006-01-02", none, schema.TimeBoundRelative(-time.Hour)), `Timestamp["2006-01-02", _, now - "1h0m0s"]`},
+		{"date plain", schema.NewDateConstraint(), "Date"},
+		{"date layout", schema.NewDateConstraintFormatted("02.01.2006"), `Date["02.01.2006"]`},
+		{"date layout bound", schema.NewDateConstraintBounded("02.01.2006", day(2000, 3, 4), none), `Date["02.01.2006", "04.03.2000", _]`},
+		{"date relative days", schema.NewDateConstraintBounded("", schema.TimeBoundRelative(0), schema.TimeBoundRelative(30*24*time.Hour)), "Date[today, today + 30]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.c.String())
+		})
+	}
+}
+
+func TestTimeConstraint_Layout(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, time.RFC3339, schema.NewTimestampConstraint().Layout())
+	assert.Equal(t, "2006-01-02 15:04", schema.NewTimestampConstraintFormatted("2006-01-02 15:04").Layout())
+	assert.Equal(t, "2006-01-02", schema.NewDateConstraint().Layout())
+	assert.Equal(t, "02.01.2006", schema.NewDateConstraintFormatted("02.01.2006").Layout())
+}
+
+func TestTimeBound_Resolve(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	fixed := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, fixed, schema.TimeBoundAt(fixed).Resolve(now))
+	assert.Equal(t, now, schema.TimeBoundRelative(0).Resolve(now))
+	assert.Equal(t, now.Add(-time.Hour), schema.TimeBoundRelative(-time.Hour).Resolve(now))
+	assert.False(t, schema.NoTimeBound().IsSet())
+}
+
+func TestTimeConstraint_EqualAndNarrowsTo(t *testing.T) {
+	t.Parallel()
+	none := schema.NoTimeBound()
+	now := schema.TimeBoundRelative(0)
+	ts := func(minB, maxB schema.TimeBound) schema.Constraint {
+		return schema.NewTimestampConstraintBounded("", minB, maxB)
+	}
+	date := func(minB, maxB schema.TimeBound) schema.Constraint {
+		return schema.NewDateConstraintBounded("", minB, maxB)
+	}
+
+	tests := []struct {
+		name   string
+		parent schema.Constraint
+		child  schema.Constraint
+		equal  bool
+		narrow bool
+	}{
+		{"unbounded same", schema.NewTimestampConstraint(), ts(none, none), true, true},
+		{"add upper bound", schema.NewTimestampConstraint(), ts(none, now), false, true},
+		{"drop upper bound fails", ts(none, now), schema.NewTimestampConstraint(), false, false},
+		{"raise fixed min", ts(day(2000, 1, 1), none), ts(day(2010, 1, 1), none), false, true},
+		{"lower fixed min fails", ts(day(2000, 1, 1), none), ts(day(1990, 1, 1), none), false, false},
+		{"earlier relative max", ts(none, now), ts(none, schema.TimeBoundRelative(-time.Hour)), false, true},
+		{"later relative max fails", ts(none, now), ts(none, schema.TimeBoundRelative(time.Hour)), false, false},
+		{"fixed for relative fails", ts(none, now), ts(none, day(2000, 1, 1)), false, false},
+		{"layout change fails", schema.NewTimestampConstraint(), schema.NewTimestampConstraintFormatted("2006-01-02"), false, false},
+		{"date tighten", date(day(1900, 1, 1), now), date(day(1950, 1, 1), schema.TimeBoundRelative(-24*time.Hour)), false, true},
+		{"date widen fails", date(day(1950, 1, 1), none), date(day(1900, 1, 1), none), false, false},
+		{"date same", date(day(1900, 1, 1), now), date(day(1900, 1, 1), now), true, true},
+		{"date vs timestamp fails", schema.NewDateConstraint(), schema.NewTimestampConstraint(), false, false},
+		{"alias child", ts(none, now), schema.NewAliasConstraint("Past", ts(none, now)), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.equal, tt.parent.Equal(tt.child), "Equal")
+			assert.Equal(t, tt.narrow, tt.parent.NarrowsTo(tt.child), "NarrowsTo")
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnionConstraint_String(t *testing.T) {
+	t.Parallel()
+	c := schema.NewUnionConstraint(
+		schema.NewStringConstraintBounded(1, 36),
+		schema.NewIntegerConstraint(),
+		schema.NewAliasConstraint("Card", nil),
+	)
+	assert.Equal(t, schema.KindUnion, c.Kind())
+	assert.Equal(t, "Union", c.Kind().String())
+	assert.Equal(t, "String[1, 36] | Integer | Card", c.String())
+	assert.Len(t, c.Members(), 3)
+}
+
+func TestUnionConstraint_EqualAndNarrowsTo(t *testing.T) {
+	t.Parallel()
+	str := schema.NewStringConstraint()
+	integer := schema.NewIntegerConstraint()
+	id := schema.NewUnionConstraint(str, integer)
+
+	tests := []struct {
+		name   string
+		parent schema.Constraint
+		child  schema.Constraint
+		equal  bool
+		narrow bool
+	}{
+		{"same", id, schema.NewUnionConstraint(str, integer), true, true},
+		{"member order ignored", id, schema.NewUnionConstraint(integer, str), true, true},
+		{"single member child", id, schema.NewStringConstraintBounded(1, 8), false, true},
+		{"fewer members", id, schema.NewUnionConstraint(integer), false, true},
+		{"narrowed member", id, schema.NewUnionConstraint(schema.NewStringConstraintBounded(1, 8), integer), false, true},
+		{"extra member fails", id, schema.NewUnionConstraint(str, integer, schema.NewBooleanConstraint()), false, false},
+		{"unrelated child fails", id, schema.NewBooleanConstraint(), false, false},
+		{"alias child", id, schema.NewAliasConstraint("Id", id), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.equal, tt.parent.Equal(tt.child), "Equal")
+			assert.Equal(t, tt.narrow, tt.parent.NarrowsTo(tt.child), "NarrowsTo")
+		})
+	}
+}
+
+func TestUnionConstraint_IsResolved(t *testing.T) {
+	t.Parallel()
+	assert.True(t, schema.NewUnionConstraint(schema.NewStringConstraint(), schema.NewIntegerConstraint()).IsResolved())
+	assert.False(t, schema.NewUnionConstraint(schema.NewStringConstraint(), schema.NewAliasConstraint("Unknown", nil)).IsResolved())
+}
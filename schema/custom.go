@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// CustomType is a named datatype implemented in Go.
+//
+// Once registered in a [CustomTypeRegistry] passed to the schema loader, the
+// name can be used in .yammm files like a built-in type (`email Email
+// required`). Properties declared with it carry a [CustomConstraint], which
+// instance validation checks and coerces through the functions below, as
+// found in the validator's registry.
+//
+// Canonical values (the results of Parse and Coerce) must be plain scalar
+// values (string, int64, float64, bool) so validated instances stay immutable
+// and JSON-serializable.
+type CustomType struct {
+	// Name is the type name used in schemas. It must start with an uppercase
+	// letter and must not collide with a built-in type keyword.
+	Name string
+
+	// Description is shown in LSP hover and completion. Markdown is allowed.
+	Description string
+
+	// Parse converts the textual form of a value into its canonical value,
+	// or reports why the text is not a valid value. Required.
+	Parse func(s string) (any, error)
+
+	// Check validates a non-string value (e.g., a netip.Addr supplied by Go
+	// code). If nil, only strings are accepted.
+	Check func(v any) error
+
+	// Coerce converts a checked non-string value to its canonical value.
+	// If nil, non-string values are stored unchanged.
+	Coerce func(v any) (any, error)
+}
+
+// customTypeName matches valid custom type names (the DSL's UC_WORD).
+var customTypeName = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*$`)
+
+// builtinTypeNames are the built-in type keywords a custom type cannot shadow.
+var builtinTypeNames = []string{
+	"Boolean", "Date", "Enum", "Float", "Integer", "List", "Map",
+	"Object", "Pattern", "Set", "String", "Timestamp", "UUID", "Vector",
+}
+
+// CustomTypeRegistry holds the custom datatypes available to a schema load
+// (see load.WithCustomTypes) or an instance validator (see
+// instance.WithCustomTypes). Different loads and validators may use
+// different registries.
+//
+// Schema datatypes take precedence: a `type Email = ...` declaration in a
+// schema shadows a registered Email for that schema.
+//
+// Thread Safety: CustomTypeRegistry is not safe for concurrent mutation.
+// Build it before use; concurrent lookups are safe.
+type CustomTypeRegistry struct {
+	types map[string]*CustomType
+}
+
+// NewCustomTypeRegistry creates an empty custom type registry.
+func NewCustomTypeRegistry() *CustomTypeRegistry {
+	return &CustomTypeRegistry{types: make(map[string]*CustomType)}
+}
+
+// Register adds a custom type.
+//
+// Returns an error if the name is invalid, names a built-in type, or is
+// already registered, or if Parse is nil.
+func (r *CustomTypeRegistry) Register(ct CustomType) error {
+	if r == nil {
+		return errors.New("nil registry")
+	}
+	if !customTypeName.MatchString(ct.Name) {
+		return fmt.Errorf("invalid type name %q", ct.Name)
+	}
+	if slices.Contains(builtinTypeNames, ct.Name) {
+		return fmt.Errorf("%q is a built-in type", ct.Name)
+	}
+	if ct.Parse == nil {
+		return errors.New("Parse is required")
+	}
+	if _, dup := r.types[ct.Name]; dup {
+		return fmt.Errorf("custom type already registered: %q", ct.Name)
+	}
+	r.types[ct.Name] = &ct
+	return nil
+}
+
+// MustRegister adds a custom type.
+//
+// Panics if [CustomTypeRegistry.Register] would return an error.
+func (r *CustomTypeRegistry) MustRegister(ct CustomType) {
+	if err := r.Register(ct); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the registered custom type with the given name.
+// A nil registry has no types.
+func (r *CustomTypeRegistry) Lookup(name string) (*CustomType, bool) {
+	if r == nil {
+		return nil, false
+	}
+	ct, ok := r.types[name]
+	return ct, ok
+}
+
+// Types returns all registered custom types, sorted by name.
+func (r *CustomTypeRegistry) Types() []*CustomType {
+	if r == nil {
+		return nil
+	}
+	out := make([]*CustomType, 0, len(r.types))
+	for _, ct := range r.types {
+		out = append(out, ct)
+	}
+	slices.SortFunc(out, func(a, b *CustomType) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
+
+// CustomConstraint constrains values to a registered [CustomType]. Values
+// are checked against the type of the same name in the validator's registry.
+type CustomConstraint struct {
+	def *CustomType
+}
+
+// NewCustomConstraint creates a CustomConstraint for the given custom type.
+func NewCustomConstraint(def *CustomType) CustomConstraint {
+	return CustomConstraint{def: def}
+}
+
+func (CustomConstraint) Kind() ConstraintKind { return KindCustom }
+func (CustomConstraint) constraint()          {}
+
+// Type returns the custom type definition.
+func (c CustomConstraint) Type() *CustomType { return c.def }
+
+// Name returns the custom type name.
+func (c CustomConstraint) Name() string {
+	if c.def == nil {
+		return ""
+	}
+	return c.def.Name
+}
+
+func (c CustomConstraint) String() string { return c.Name() }
+
+func (c CustomConstraint) Equal(other Constraint) bool {
+	o, ok := resolveAlias(other).(CustomConstraint)
+	return ok && c.Name() == o.Name()
+}
+
+func (c CustomConstraint) NarrowsTo(child Constraint) bool {
+	o, ok := resolveAlias(child).(CustomConstraint)
+	return ok && c.Name() == o.Name()
+}
+
+func (CustomConstraint) IsResolved() bool { return true }
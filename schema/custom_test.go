@@ -0,0 +1,111 @@
+package schema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/simon-lentz/yammm/schema"
+)
+
+var ticketType = schema.CustomType{
+	Name:        "Ticket",
+	Description: "A ticket key such as `ABC-123`.",
+	Parse: func(s string) (any, error) {
+		if !strings.Contains(s, "-") {
+			return nil, errors.New("missing dash")
+		}
+		return strings.ToUpper(s), nil
+	},
+}
+
+func TestCustomTypeRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := schema.NewCustomTypeRegistry()
+	require.NoError(t, r.Register(ticketType))
+	r.MustRegister(schema.CustomType{Name: "Code", Parse: ticketType.Parse})
+
+	ct, ok := r.Lookup("Ticket")
+	require.True(t, ok)
+	assert.Equal(t, "Ticket", ct.Name)
+
+	names := make([]string, 0)
+	for _, c := range r.Types() {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"Code", "Ticket"}, names)
+
+	_, ok = r.Lookup("Missing")
+	assert.False(t, ok)
+
+	// Registries are independent.
+	_, ok = schema.NewCustomTypeRegistry().Lookup("Ticket")
+	assert.False(t, ok)
+
+	var nilRegistry *schema.CustomTypeRegistry
+	_, ok = nilRegistry.Lookup("Ticket")
+	assert.False(t, ok)
+	assert.Empty(t, nilRegistry.Types())
+}
+
+func TestCustomTypeRegistry_Invalid(t *testing.T) {
+	t.Parallel()
+
+	parse := func(s string) (any, error) { return s, nil }
+
+	tests := []struct {
+		name string
+		ct   schema.CustomType
+		want string
+	}{
+		{"lowercase", schema.CustomType{Name: "email", Parse: parse}, "invalid type name"},
+		{"empty", schema.CustomType{Parse: parse}, "invalid type name"},
+		{"builtin", schema.CustomType{Name: "UUID", Parse: parse}, "built-in type"},
+		{"nil_parse", schema.CustomType{Name: "NoParse"}, "Parse is required"},
+		{"duplicate", schema.CustomType{Name: "Ticket", Parse: parse}, "already registered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := schema.NewCustomTypeRegistry()
+			r.MustRegister(ticketType)
+			err := r.Register(tt.ct)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+
+	assert.Panics(t, func() { schema.NewCustomTypeRegistry().MustRegister(schema.CustomType{Name: "NoParse"}) })
+}
+
+func TestCustomConstraint(t *testing.T) {
+	t.Parallel()
+
+	ct := &ticketType
+	c := schema.NewCustomConstraint(ct)
+
+	assert.Equal(t, schema.KindCustom, c.Kind())
+	assert.Equal(t, "Custom", c.Kind().String())
+	assert.Equal(t, "Ticket", c.String())
+	assert.Same(t, ct, c.Type())
+	assert.True(t, c.IsResolved())
+
+	same := schema.NewCustomConstraint(ct)
+	other := schema.NewCustomConstraint(&schema.CustomType{Name: "Other"})
+	assert.True(t, c.Equal(same))
+	assert.True(t, c.NarrowsTo(same))
+	assert.False(t, c.Equal(other))
+	assert.False(t, c.Equal(schema.NewStringConstraint()))
+	assert.False(t, c.NarrowsTo(schema.NewStringConstraint()))
+
+	// Aliases of the same custom type compare through their resolution.
+	alias := schema.NewAliasConstraint("Key", same)
+	assert.True(t, c.Equal(alias))
+	assert.True(t, c.NarrowsTo(alias))
+	assert.False(t, c.Equal(schema.NewAliasConstraint("Other", other)))
+}
@@ -0,0 +1,113 @@
+package datatypes
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+
+	"github.com/simon-lentz/yammm/schema"
+)
+
+// Email accepts a bare email address such as "alice@example.com".
+var Email = schema.CustomType{
+	Name:        "Email",
+	Description: "An email address such as `alice@example.com` (RFC 5322 addr-spec, no display name).",
+	Parse:       parseEmail,
+}
+
+// URL accepts an absolute URL with a scheme and host.
+var URL = schema.CustomType{
+	Name:        "URL",
+	Description: "An absolute URL with scheme and host, such as `https://example.com/docs`.",
+	Parse:       parseURL,
+}
+
+// IPAddress accepts an IPv4 or IPv6 address.
+var IPAddress = schema.CustomType{
+	Name:        "IPAddress",
+	Description: "An IPv4 or IPv6 address such as `192.0.2.1` or `2001:db8::1`, stored in canonical form.",
+	Parse:       parseIPAddress,
+	Check:       checkIPAddress,
+	Coerce:      coerceIPAddress,
+}
+
+// Semver accepts a Semantic Versioning 2.0.0 version.
+var Semver = schema.CustomType{
+	Name:        "Semver",
+	Description: "A [Semantic Versioning 2.0.0](https://semver.org) version such as `1.4.0-rc.1`.",
+	Parse:       parseSemver,
+}
+
+// NewRegistry returns a registry holding the datatypes of this package.
+// Callers may register their own types in it as well.
+func NewRegistry() *schema.CustomTypeRegistry {
+	r := schema.NewCustomTypeRegistry()
+	r.MustRegister(Email)
+	r.MustRegister(URL)
+	r.MustRegister(IPAddress)
+	r.MustRegister(Semver)
+	return r
+}
+
+func parseEmail(s string) (any, error) {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return nil, errors.New("not a valid email address")
+	}
+	if addr.Name != "" || addr.Address != s {
+		return nil, errors.New("display names and angle brackets are not allowed")
+	}
+	return s, nil
+}
+
+func parseURL(s string) (any, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, errors.New("not a valid URL")
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, errors.New("URL must be absolute with scheme and host")
+	}
+	return u.String(), nil
+}
+
+func parseIPAddress(s string) (any, error) {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return nil, errors.New("not a valid IP address")
+	}
+	return addr.String(), nil
+}
+
+func checkIPAddress(v any) error {
+	addr, ok := v.(netip.Addr)
+	if !ok {
+		return fmt.Errorf("expected string or netip.Addr, got %T", v)
+	}
+	if !addr.IsValid() {
+		return errors.New("zero netip.Addr")
+	}
+	return nil
+}
+
+func coerceIPAddress(v any) (any, error) {
+	if err := checkIPAddress(v); err != nil {
+		return nil, err
+	}
+	return v.(netip.Addr).String(), nil
+}
+
+// semverPattern is the official regular expression from semver.org.
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+func parseSemver(s string) (any, error) {
+	if !semverPattern.MatchString(s) {
+		return nil, errors.New("not a semantic version (MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD])")
+	}
+	return s, nil
+}
@@ -0,0 +1,193 @@
+package datatypes_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/simon-lentz/yammm/instance"
+	"github.com/simon-lentz/yammm/instance/eval"
+	"github.com/simon-lentz/yammm/internal/value"
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/datatypes"
+	"github.com/simon-lentz/yammm/schema/load"
+)
+
+func TestNewRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := datatypes.NewRegistry()
+	for _, name := range []string{"Email", "URL", "IPAddress", "Semver"} {
+		ct, ok := r.Lookup(name)
+		require.True(t, ok, name)
+		assert.NotEmpty(t, ct.Description, name)
+	}
+}
+
+func TestDatatypes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ct   schema.CustomType
+		val  any
+		want any // canonical value; nil means invalid
+	}{
+		{"email", datatypes.Email, "alice@example.com", "alice@example.com"},
+		{"email_display_name", datatypes.Email, "Alice <alice@example.com>", nil},
+		{"email_missing_at", datatypes.Email, "alice.example.com", nil},
+		{"url", datatypes.URL, "https://example.com/a b", "https://example.com/a%20b"},
+		{"url_relative", datatypes.URL, "/docs", nil},
+		{"url_no_host", datatypes.URL, "mailto:alice@example.com", nil},
+		{"ipv4", datatypes.IPAddress, "192.0.2.1", "192.0.2.1"},
+		{"ipv6_canonical", datatypes.IPAddress, "2001:DB8:0::1", "2001:db8::1"},
+		{"ip_netip", datatypes.IPAddress, netip.MustParseAddr("::1"), "::1"},
+		{"ip_invalid", datatypes.IPAddress, "300.1.1.1", nil},
+		{"ip_zero_netip", datatypes.IPAddress, netip.Addr{}, nil},
+		{"semver", datatypes.Semver, "1.4.0-rc.1+build.5", "1.4.0-rc.1+build.5"},
+		{"semver_v_prefix", datatypes.Semver, "v1.4.0", nil},
+		{"semver_leading_zero", datatypes.Semver, "01.4.0", nil},
+		{"semver_non_string", datatypes.Semver, int64(1), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := schema.NewCustomConstraint(&tt.ct)
+			checker := eval.NewChecker(value.Registry{CustomTypes: datatypes.NewRegistry()})
+			err := checker.CheckValue(tt.val, c)
+			if tt.want == nil {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			got, err := checker.CoerceValue(tt.val, c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	source := `schema "servers"
+
+type Server {
+	name String primary
+	contact Email required
+	address IPAddress required
+	mirrors List<URL>
+}`
+	types := datatypes.NewRegistry()
+	s, result, err := load.LoadString(t.Context(), source, "servers.yammm", load.WithCustomTypes(types))
+	require.NoError(t, err)
+	require.True(t, result.OK(), "unexpected errors: %v", result.Messages())
+
+	server, ok := s.Type("Server")
+	require.True(t, ok)
+	contact, ok := server.Property("contact")
+	require.True(t, ok)
+	assert.Equal(t, schema.KindCustom, contact.Constraint().Kind())
+
+	v := instance.NewValidator(s, instance.WithCustomTypes(types))
+	valid, failure, err := v.ValidateOne(t.Context(), "Server", instance.RawInstance{
+		Properties: map[string]any{
+			"name":    "edge-1",
+			"contact": "ops@example.com",
+			"address": "2001:DB8::1",
+			"mirrors": []any{"https://mirror.example.com"},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, failure)
+	addr, ok := valid.Property("address")
+	require.True(t, ok)
+	got, _ := addr.String()
+	assert.Equal(t, "2001:db8::1", got)
+
+	_, failure, err = v.ValidateOne(t.Context(), "Server", instance.RawInstance{
+		Properties: map[string]any{
+			"name":    "edge-2",
+			"contact": "not-an-email",
+			"address": "10.0.0.1",
+			"mirrors": []any{"relative/path"},
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	assert.Equal(t, 2, failure.Result.Len())
+	assert.Contains(t, failure.Result.String(), `invalid Email "not-an-email"`)
+}
+
+func TestLocalDatatypeShadowsCustomType(t *testing.T) {
+	t.Parallel()
+
+	source := `schema "shadow"
+
+type Email = String[1, 10]
+
+type User {
+	email Email required
+}`
+	s, result, err := load.LoadString(t.Context(), source, "shadow.yammm", load.WithCustomTypes(datatypes.NewRegistry()))
+	require.NoError(t, err)
+	require.True(t, result.OK(), "unexpected errors: %v", result.Messages())
+
+	user, ok := s.Type("User")
+	require.True(t, ok)
+	email, ok := user.Property("email")
+	require.True(t, ok)
+	alias, ok := email.Constraint().(schema.AliasConstraint)
+	require.True(t, ok)
+	assert.Equal(t, schema.KindString, alias.Resolved().Kind())
+}
+
+func TestCustomTypesAreScoped(t *testing.T) {
+	t.Parallel()
+
+	source := `schema "contacts"
+
+type Contact {
+	id String primary
+	email Email required
+}`
+	s, result, err := load.LoadString(t.Context(), source, "contacts.yammm")
+	require.NoError(t, err)
+	require.True(t, result.OK())
+	contact, ok := s.Type("Contact")
+	require.True(t, ok)
+	email, ok := contact.Property("email")
+	require.True(t, ok)
+	assert.NotEqual(t, schema.KindCustom, email.Constraint().Kind(), "Email is not a custom type without a registry")
+
+	s, result, err = load.LoadString(t.Context(), source, "contacts.yammm", load.WithCustomTypes(datatypes.NewRegistry()))
+	require.NoError(t, err)
+	require.True(t, result.OK(), "unexpected errors: %v", result.Messages())
+
+	// A validator checks custom types with its own registry.
+	lenient := schema.NewCustomTypeRegistry()
+	lenient.MustRegister(schema.CustomType{
+		Name:  "Email",
+		Parse: func(s string) (any, error) { return s, nil },
+	})
+	raw := instance.RawInstance{Properties: map[string]any{"id": "c1", "email": "not-an-email"}}
+
+	_, failure, err := instance.NewValidator(s, instance.WithCustomTypes(datatypes.NewRegistry())).
+		ValidateOne(t.Context(), "Contact", raw)
+	require.NoError(t, err)
+	assert.NotNil(t, failure)
+
+	_, failure, err = instance.NewValidator(s, instance.WithCustomTypes(lenient)).
+		ValidateOne(t.Context(), "Contact", raw)
+	require.NoError(t, err)
+	assert.Nil(t, failure)
+
+	_, failure, err = instance.NewValidator(s).ValidateOne(t.Context(), "Contact", raw)
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	assert.Contains(t, failure.Result.String(), "custom type Email is not registered")
+}
@@ -0,0 +1,30 @@
+// Package datatypes provides commonly needed custom datatypes for yammm
+// schemas, implemented in Go as [schema.CustomType] values.
+//
+// [NewRegistry] returns a registry holding them. Pass it both to the schema
+// loader and to the instance validator:
+//
+//	types := datatypes.NewRegistry()
+//	s, result, err := load.Load(ctx, path, load.WithCustomTypes(types))
+//	...
+//	v := instance.NewValidator(s, instance.WithCustomTypes(types))
+//
+// The schema can then use them like built-in types:
+//
+//	type Server {
+//	    contact Email required
+//	    homepage URL
+//	    address IPAddress required
+//	    version Semver
+//	}
+//
+// # Provided Types
+//
+//   - Email: a bare RFC 5322 address ("alice@example.com"), without display name
+//   - URL: an absolute URL with scheme and host; stored in normalized form
+//   - IPAddress: an IPv4 or IPv6 address; stored in canonical form ("::1")
+//   - Semver: a Semantic Versioning 2.0.0 version ("1.4.0-rc.1+build.5")
+//
+// All canonical values are strings. IPAddress additionally accepts
+// [netip.Addr] values supplied by Go callers.
+package datatypes
@@ -0,0 +1,658 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/expr"
+)
+
+// ErrNilSchema is returned when Compare is called with a nil schema.
+var ErrNilSchema = errors.New("cannot compare nil schema")
+
+// Detail keys attached to every change issue.
+const (
+	// DetailKeyChange is the change kind: "added", "removed", or "changed".
+	DetailKeyChange = "change"
+
+	// DetailKeyElement is the changed element kind: "type", "property",
+	// "relation", "datatype", or "invariant".
+	DetailKeyElement = "element"
+
+	// DetailKeyCompatibility is the classification (see Compatibility.String).
+	DetailKeyCompatibility = "compatibility"
+
+	// DetailKeyOld is the previous value of a changed aspect.
+	DetailKeyOld = "old"
+
+	// DetailKeyNew is the new value of a changed aspect.
+	DetailKeyNew = "new"
+)
+
+// Change kinds reported under DetailKeyChange.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Element kinds reported under DetailKeyElement.
+const (
+	ElementType      = "type"
+	ElementProperty  = "property"
+	ElementRelation  = "relation"
+	ElementDataType  = "datatype"
+	ElementInvariant = "invariant"
+)
+
+// Compatibility classifies the impact of a schema change. Values are ordered
+// by increasing impact, so the larger of two classifications is the more
+// severe one.
+type Compatibility uint8
+
+const (
+	// Compatible changes keep existing data valid and existing names resolvable.
+	Compatible Compatibility = iota
+
+	// DataBreaking changes may reject data valid under the old revision.
+	DataBreaking
+
+	// APIBreaking changes remove or reshape names consumers depend on.
+	APIBreaking
+)
+
+// String returns the classification label used in diagnostic details.
+func (c Compatibility) String() string {
+	switch c {
+	case Compatible:
+		return "compatible"
+	case DataBreaking:
+		return "data-breaking"
+	case APIBreaking:
+		return "api-breaking"
+	default:
+		return "unknown"
+	}
+}
+
+// Code returns the diagnostic code used for changes of this classification.
+func (c Compatibility) Code() diag.Code {
+	switch c {
+	case DataBreaking:
+		return diag.E_DATA_BREAKING_CHANGE
+	case APIBreaking:
+		return diag.E_API_BREAKING_CHANGE
+	default:
+		return diag.W_COMPATIBLE_CHANGE
+	}
+}
+
+// Severity returns the diagnostic severity used for changes of this
+// classification.
+func (c Compatibility) Severity() diag.Severity {
+	switch c {
+	case DataBreaking, APIBreaking:
+		return diag.Error
+	default:
+		return diag.Info
+	}
+}
+
+// Overall returns the most severe classification among the change issues in
+// result. A result without change issues is Compatible.
+func Overall(result diag.Result) Compatibility {
+	overall := Compatible
+	for issue := range result.Issues() {
+		switch issue.Code() {
+		case diag.E_API_BREAKING_CHANGE:
+			return APIBreaking
+		case diag.E_DATA_BREAKING_CHANGE:
+			overall = DataBreaking
+		}
+	}
+	return overall
+}
+
+// Compare reports the differences between two revisions of a schema.
+//
+// Only local types and data types are compared; imported schemas are not
+// traversed. Types, data types, properties, relations, and invariants are
+// matched by name, so a rename is reported as a removal plus an addition.
+//
+// Returns ErrNilSchema if either schema is nil. An empty result means the
+// revisions are structurally identical (documentation and spans are ignored).
+func Compare(from, to *schema.Schema) (diag.Result, error) {
+	if from == nil || to == nil {
+		return diag.OK(), ErrNilSchema
+	}
+	d := &differ{
+		from:      from,
+		to:        to,
+		collector: diag.NewCollector(0),
+	}
+	d.compareDataTypes()
+	d.compareTypes()
+	return d.collector.Result(), nil
+}
+
+// differ holds the state for a single Compare call.
+type differ struct {
+	from      *schema.Schema
+	to        *schema.Schema
+	collector *diag.Collector
+}
+
+// change describes a single reported difference.
+type change struct {
+	kind    string
+	element string
+	compat  Compatibility
+	path    string // dotted element path, e.g. "Person.age"
+	message string
+	oldSpan location.Span
+	newSpan location.Span
+	details []diag.Detail
+}
+
+// report converts a change into a diagnostic issue.
+//
+// Removed elements are located in the old revision; added and changed
+// elements in the new one. Changed elements relate back to the old
+// declaration when it has a span.
+func (d *differ) report(c change) {
+	span, sourceName := c.newSpan, d.to.Name()
+	if c.kind == ChangeRemoved {
+		span, sourceName = c.oldSpan, d.from.Name()
+	}
+	b := diag.NewIssue(c.compat.Severity(), c.compat.Code(), c.message).
+		WithPath(sourceName, c.path).
+		WithDetail(DetailKeyChange, c.kind).
+		WithDetail(DetailKeyElement, c.element).
+		WithDetail(DetailKeyCompatibility, c.compat.String()).
+		WithDetails(c.details...)
+	if !span.IsZero() {
+		b = b.WithSpan(span)
+	}
+	if c.kind == ChangeChanged && !c.oldSpan.IsZero() {
+		b = b.WithRelated(location.RelatedInfo{
+			Span:    c.oldSpan,
+			Message: "previous declaration",
+		})
+	}
+	d.collector.Collect(b.Build())
+}
+
+// compareDataTypes reports added, removed, and changed data type aliases.
+func (d *differ) compareDataTypes() {
+	for _, name := range unionNames(d.from.DataTypeNames(), d.to.DataTypeNames()) {
+		oldDT, inOld := d.from.DataType(name)
+		newDT, inNew := d.to.DataType(name)
+		details := []diag.Detail{{Key: diag.DetailKeyName, Value: name}}
+		switch {
+		case !inNew:
+			d.report(change{
+				kind: ChangeRemoved, element: ElementDataType, compat: APIBreaking, path: name,
+				message: fmt.Sprintf("datatype %q removed", name),
+				oldSpan: oldDT.Span(), details: details,
+			})
+		case !inOld:
+			d.report(change{
+				kind: ChangeAdded, element: ElementDataType, compat: Compatible, path: name,
+				message: fmt.Sprintf("datatype %q added", name),
+				newSpan: newDT.Span(), details: details,
+			})
+		default:
+			compat, changed := classifyConstraint(oldDT.Constraint(), newDT.Constraint())
+			if !changed {
+				continue
+			}
+			d.report(change{
+				kind: ChangeChanged, element: ElementDataType, compat: compat, path: name,
+				message: fmt.Sprintf("datatype %q changed from %s to %s (%s)",
+					name, constraintString(oldDT.Constraint()), constraintString(newDT.Constraint()), compat),
+				oldSpan: oldDT.Span(), newSpan: newDT.Span(),
+				details: append(details, oldNew(constraintString(oldDT.Constraint()), constraintString(newDT.Constraint()))...),
+			})
+		}
+	}
+}
+
+// compareTypes reports added, removed, and changed local types.
+func (d *differ) compareTypes() {
+	for _, name := range unionNames(d.from.TypeNames(), d.to.TypeNames()) {
+		oldT, inOld := d.from.Type(name)
+		newT, inNew := d.to.Type(name)
+		details := []diag.Detail{{Key: diag.DetailKeyTypeName, Value: name}}
+		switch {
+		case !inNew:
+			d.report(change{
+				kind: ChangeRemoved, element: ElementType, compat: APIBreaking, path: name,
+				message: fmt.Sprintf("type %q removed", name),
+				oldSpan: oldT.Span(), details: details,
+			})
+		case !inOld:
+			d.report(change{
+				kind: ChangeAdded, element: ElementType, compat: Compatible, path: name,
+				message: fmt.Sprintf("type %q added", name),
+				newSpan: newT.Span(), details: details,
+			})
+		default:
+			d.compareType(oldT, newT)
+		}
+	}
+}
+
+// compareType reports changes to the modifiers, supertypes, and effective
+// members of a type present in both revisions.
+func (d *differ) compareType(oldT, newT *schema.Type) {
+	name := newT.Name()
+	typeChange := func(compat Compatibility, msg string, oldVal, newVal string) {
+		d.report(change{
+			kind: ChangeChanged, element: ElementType, compat: compat, path: name,
+			message: msg, oldSpan: oldT.Span(), newSpan: newT.Span(),
+			details: append([]diag.Detail{{Key: diag.DetailKeyTypeName, Value: name}}, oldNew(oldVal, newVal)...),
+		})
+	}
+
+	if oldT.IsAbstract() != newT.IsAbstract() {
+		if newT.IsAbstract() {
+			typeChange(DataBreaking, fmt.Sprintf("type %q became abstract", name), "concrete", "abstract")
+		} else {
+			typeChange(Compatible, fmt.Sprintf("type %q is no longer abstract", name), "abstract", "concrete")
+		}
+	}
+	if oldT.IsPart() != newT.IsPart() {
+		if newT.IsPart() {
+			typeChange(APIBreaking, fmt.Sprintf("type %q became a part type", name), "entity", "part")
+		} else {
+			typeChange(APIBreaking, fmt.Sprintf("type %q is no longer a part type", name), "part", "entity")
+		}
+	}
+
+	oldSupers := typeRefNames(oldT.InheritsSlice())
+	newSupers := typeRefNames(newT.InheritsSlice())
+	for _, super := range unionNames(oldSupers, newSupers) {
+		switch {
+		case !slices.Contains(newSupers, super):
+			typeChange(APIBreaking, fmt.Sprintf("type %q no longer extends %s", name, super), super, "")
+		case !slices.Contains(oldSupers, super):
+			typeChange(Compatible, fmt.Sprintf("type %q now extends %s", name, super), "", super)
+		}
+	}
+
+	d.compareProperties(name, "", oldT.AllPropertiesSlice(), newT.AllPropertiesSlice())
+	d.compareRelations(name, oldT, newT)
+	d.compareInvariants(name, oldT.AllInvariantsSlice(), newT.AllInvariantsSlice())
+}
+
+// compareProperties reports changes between two property sets. relName is
+// empty for type properties and names the owning relation for edge
+// properties.
+func (d *differ) compareProperties(typeName, relName string, oldProps, newProps []*schema.Property) {
+	oldByName := indexBy(oldProps, (*schema.Property).Name)
+	newByName := indexBy(newProps, (*schema.Property).Name)
+
+	owner := fmt.Sprintf("type %q", typeName)
+	prefix := typeName + "."
+	details := func(prop string) []diag.Detail {
+		if relName == "" {
+			return diag.TypeProp(typeName, prop)
+		}
+		return []diag.Detail{
+			{Key: diag.DetailKeyTypeName, Value: typeName},
+			{Key: diag.DetailKeyRelationName, Value: relName},
+			{Key: diag.DetailKeyPropertyName, Value: prop},
+		}
+	}
+	if relName != "" {
+		owner = fmt.Sprintf("relation %q of type %q", relName, typeName)
+		prefix = typeName + "." + relName + "."
+	}
+
+	for _, name := range unionNames(mapKeys(oldByName), mapKeys(newByName)) {
+		oldP, inOld := oldByName[name]
+		newP, inNew := newByName[name]
+		path := prefix + name
+		switch {
+		case !inNew:
+			d.report(change{
+				kind: ChangeRemoved, element: ElementProperty, compat: APIBreaking, path: path,
+				message: fmt.Sprintf("property %q removed from %s", name, owner),
+				oldSpan: oldP.Span(), details: details(name),
+			})
+		case !inOld:
+			compat, modifier := Compatible, "optional"
+			if newP.IsRequired() {
+				compat, modifier = DataBreaking, "required"
+			}
+			d.report(change{
+				kind: ChangeAdded, element: ElementProperty, compat: compat, path: path,
+				message: fmt.Sprintf("%s property %q added to %s", modifier, name, owner),
+				newSpan: newP.Span(), details: details(name),
+			})
+		default:
+			propChange := func(compat Compatibility, msg, oldVal, newVal string) {
+				d.report(change{
+					kind: ChangeChanged, element: ElementProperty, compat: compat, path: path,
+					message: msg, oldSpan: oldP.Span(), newSpan: newP.Span(),
+					details: append(details(name), oldNew(oldVal, newVal)...),
+				})
+			}
+			if oldP.IsPrimaryKey() != newP.IsPrimaryKey() {
+				oldVal, newVal := "primary", "non-primary"
+				verb := "is no longer"
+				if newP.IsPrimaryKey() {
+					oldVal, newVal = newVal, oldVal
+					verb = "became"
+				}
+				propChange(APIBreaking, fmt.Sprintf("property %q of %s %s a primary key", name, owner, verb), oldVal, newVal)
+			}
+			if oldP.IsOptional() != newP.IsOptional() {
+				if newP.IsRequired() {
+					propChange(DataBreaking, fmt.Sprintf("property %q of %s became required", name, owner), "optional", "required")
+				} else {
+					propChange(Compatible, fmt.Sprintf("property %q of %s became optional", name, owner), "required", "optional")
+				}
+			}
+			if compat, changed := classifyConstraint(oldP.Constraint(), newP.Constraint()); changed {
+				oldC, newC := constraintString(oldP.Constraint()), constraintString(newP.Constraint())
+				propChange(compat, fmt.Sprintf("property %q of %s changed from %s to %s (%s)",
+					name, owner, oldC, newC, compat), oldC, newC)
+			}
+		}
+	}
+}
+
+// compareRelations reports changes between the effective associations and
+// compositions of a type.
+func (d *differ) compareRelations(typeName string, oldT, newT *schema.Type) {
+	oldByName := indexBy(append(oldT.AllAssociationsSlice(), oldT.AllCompositionsSlice()...), (*schema.Relation).Name)
+	newByName := indexBy(append(newT.AllAssociationsSlice(), newT.AllCompositionsSlice()...), (*schema.Relation).Name)
+
+	for _, name := range unionNames(mapKeys(oldByName), mapKeys(newByName)) {
+		oldR, inOld := oldByName[name]
+		newR, inNew := newByName[name]
+		path := typeName + "." + name
+		details := []diag.Detail{
+			{Key: diag.DetailKeyTypeName, Value: typeName},
+			{Key: diag.DetailKeyRelationName, Value: name},
+		}
+		switch {
+		case !inNew:
+			d.report(change{
+				kind: ChangeRemoved, element: ElementRelation, compat: APIBreaking, path: path,
+				message: fmt.Sprintf("%s %q removed from type %q", oldR.Kind(), name, typeName),
+				oldSpan: oldR.Span(), details: details,
+			})
+		case !inOld:
+			compat, modifier := Compatible, "optional"
+			if !newR.IsOptional() {
+				compat, modifier = DataBreaking, "required"
+			}
+			d.report(change{
+				kind: ChangeAdded, element: ElementRelation, compat: compat, path: path,
+				message: fmt.Sprintf("%s %s %q added to type %q", modifier, newR.Kind(), name, typeName),
+				newSpan: newR.Span(), details: details,
+			})
+		default:
+			relChange := func(compat Compatibility, msg, oldVal, newVal string) {
+				d.report(change{
+					kind: ChangeChanged, element: ElementRelation, compat: compat, path: path,
+					message: msg, oldSpan: oldR.Span(), newSpan: newR.Span(),
+					details: append(slices.Clone(details), oldNew(oldVal, newVal)...),
+				})
+			}
+			if oldR.Kind() != newR.Kind() {
+				relChange(APIBreaking, fmt.Sprintf("relation %q of type %q changed from %s to %s",
+					name, typeName, oldR.Kind(), newR.Kind()), oldR.Kind().String(), newR.Kind().String())
+			}
+			if oldTarget, newTarget := oldR.Target().String(), newR.Target().String(); oldTarget != newTarget {
+				relChange(APIBreaking, fmt.Sprintf("relation %q of type %q changed target from %s to %s",
+					name, typeName, oldTarget, newTarget), oldTarget, newTarget)
+			}
+			if oldR.IsMany() != newR.IsMany() {
+				relChange(APIBreaking, fmt.Sprintf("relation %q of type %q changed cardinality from %s to %s",
+					name, typeName, cardinality(oldR.IsMany()), cardinality(newR.IsMany())),
+					cardinality(oldR.IsMany()), cardinality(newR.IsMany()))
+			}
+			if oldR.IsOptional() != newR.IsOptional() {
+				if newR.IsOptional() {
+					relChange(Compatible, fmt.Sprintf("relation %q of type %q became optional", name, typeName), "required", "optional")
+				} else {
+					relChange(DataBreaking, fmt.Sprintf("relation %q of type %q became required", name, typeName), "optional", "required")
+				}
+			}
+			if oldR.Backref() != newR.Backref() {
+				relChange(APIBreaking, fmt.Sprintf("relation %q of type %q changed reverse name from %q to %q",
+					name, typeName, oldR.Backref(), newR.Backref()), oldR.Backref(), newR.Backref())
+			}
+			oldRevOpt, oldRevMany := oldR.ReverseMultiplicity()
+			newRevOpt, newRevMany := newR.ReverseMultiplicity()
+			if oldRevOpt != newRevOpt || oldRevMany != newRevMany {
+				// Reverse multiplicity is parsed but not enforced, so
+				// changing it cannot reject data.
+				relChange(Compatible, fmt.Sprintf("relation %q of type %q changed reverse multiplicity", name, typeName),
+					multiplicity(oldRevOpt, oldRevMany), multiplicity(newRevOpt, newRevMany))
+			}
+			if oldR.IsAssociation() && newR.IsAssociation() {
+				d.compareProperties(typeName, name, oldR.PropertiesSlice(), newR.PropertiesSlice())
+			}
+		}
+	}
+}
+
+// compareInvariants reports changes between the effective invariants of a
+// type. Invariants are matched by ID (their declared identifier, or their
+// user-facing message when none is declared).
+//
+// Only invariants with a failing severity can reject data, so adding or
+// tightening a `!warn` or `!info` invariant is compatible, while raising one
+// to an error is data-breaking.
+func (d *differ) compareInvariants(typeName string, oldInvs, newInvs []*schema.Invariant) {
+	oldByID := indexBy(oldInvs, (*schema.Invariant).ID)
+	newByID := indexBy(newInvs, (*schema.Invariant).ID)
+
+	for _, id := range unionNames(mapKeys(oldByID), mapKeys(newByID)) {
+		oldI, inOld := oldByID[id]
+		newI, inNew := newByID[id]
+		path := typeName + ".!" + id
+		details := []diag.Detail{
+			{Key: diag.DetailKeyTypeName, Value: typeName},
+			{Key: diag.DetailKeyName, Value: id},
+		}
+		switch {
+		case !inNew:
+			d.report(change{
+				kind: ChangeRemoved, element: ElementInvariant, compat: Compatible, path: path,
+				message: fmt.Sprintf("invariant %q removed from type %q", id, typeName),
+				oldSpan: oldI.Span(), details: details,
+			})
+		case !inOld:
+			d.report(change{
+				kind: ChangeAdded, element: ElementInvariant, compat: rejects(newI), path: path,
+				message: fmt.Sprintf("invariant %q added to type %q", id, typeName),
+				newSpan: newI.Span(), details: details,
+			})
+		case !exprEqual(oldI.Expression(), newI.Expression()):
+			d.report(change{
+				kind: ChangeChanged, element: ElementInvariant, compat: rejects(newI), path: path,
+				message: fmt.Sprintf("invariant %q of type %q changed expression", id, typeName),
+				oldSpan: oldI.Span(), newSpan: newI.Span(), details: details,
+			})
+		case oldI.Severity() != newI.Severity():
+			compat := Compatible
+			if newI.Severity().IsFailure() && !oldI.Severity().IsFailure() {
+				compat = DataBreaking
+			}
+			d.report(change{
+				kind: ChangeChanged, element: ElementInvariant, compat: compat, path: path,
+				message: fmt.Sprintf("invariant %q of type %q changed severity", id, typeName),
+				oldSpan: oldI.Span(), newSpan: newI.Span(),
+				details: append(details, oldNew(oldI.Severity().String(), newI.Severity().String())...),
+			})
+		case oldI.Name() != newI.Name():
+			d.report(change{
+				kind: ChangeChanged, element: ElementInvariant, compat: Compatible, path: path,
+				message: fmt.Sprintf("invariant %q of type %q changed message", id, typeName),
+				oldSpan: oldI.Span(), newSpan: newI.Span(),
+				details: append(details, oldNew(oldI.Name(), newI.Name())...),
+			})
+		}
+	}
+}
+
+// rejects classifies a new or changed invariant: data-breaking when its
+// failures reject instances, compatible otherwise.
+func rejects(inv *schema.Invariant) Compatibility {
+	if inv.Severity().IsFailure() {
+		return DataBreaking
+	}
+	return Compatible
+}
+
+// classifyConstraint reports whether a constraint changed and, if so, how.
+//
+// Equal constraints are unchanged. A change of terminal constraint kind is
+// API-breaking. Otherwise, a new constraint that accepts every value the old
+// one accepted (new.NarrowsTo(old)) is compatible; anything else may reject
+// previously valid data.
+func classifyConstraint(oldC, newC schema.Constraint) (Compatibility, bool) {
+	if oldC == nil || newC == nil {
+		return APIBreaking, oldC != newC
+	}
+	if oldC.Equal(newC) {
+		return Compatible, false
+	}
+	if terminal(oldC).Kind() != terminal(newC).Kind() {
+		return APIBreaking, true
+	}
+	if newC.NarrowsTo(oldC) {
+		return Compatible, true
+	}
+	return DataBreaking, true
+}
+
+// maxAliasDepth bounds alias chain unwrapping in terminal.
+const maxAliasDepth = 64
+
+// terminal unwraps alias constraints to the underlying constraint.
+// Unresolved or cyclic chains return the last alias reached.
+func terminal(c schema.Constraint) schema.Constraint {
+	for range maxAliasDepth {
+		ac, ok := c.(schema.AliasConstraint)
+		if !ok || ac.Resolved() == nil {
+			return c
+		}
+		c = ac.Resolved()
+	}
+	return c
+}
+
+// constraintString renders a constraint for messages, expanding aliases to
+// their underlying constraint.
+func constraintString(c schema.Constraint) string {
+	if c == nil {
+		return "<none>"
+	}
+	if ac, ok := c.(schema.AliasConstraint); ok {
+		if t := terminal(ac); t != schema.Constraint(ac) {
+			return ac.DataTypeName() + " (" + t.String() + ")"
+		}
+	}
+	return c.String()
+}
+
+// exprEqual reports whether two compiled expressions are structurally equal.
+func exprEqual(a, b expr.Expression) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.Op() != b.Op() || !literalEqual(a.Literal(), b.Literal()) {
+		return false
+	}
+	ac, bc := a.Children(), b.Children()
+	if len(ac) != len(bc) {
+		return false
+	}
+	for i := range ac {
+		if !exprEqual(ac[i], bc[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// literalEqual compares literal values, treating regular expressions as
+// equal when their source patterns match.
+func literalEqual(a, b any) bool {
+	if ra, ok := a.(*regexp.Regexp); ok {
+		rb, ok := b.(*regexp.Regexp)
+		return ok && ra.String() == rb.String()
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// oldNew returns the old/new detail pair, omitting empty values.
+func oldNew(oldVal, newVal string) []diag.Detail {
+	var details []diag.Detail
+	if oldVal != "" {
+		details = append(details, diag.Detail{Key: DetailKeyOld, Value: oldVal})
+	}
+	if newVal != "" {
+		details = append(details, diag.Detail{Key: DetailKeyNew, Value: newVal})
+	}
+	return details
+}
+
+func cardinality(many bool) string {
+	if many {
+		return "many"
+	}
+	return "one"
+}
+
+func multiplicity(optional, many bool) string {
+	if optional {
+		return "optional " + cardinality(many)
+	}
+	return "required " + cardinality(many)
+}
+
+func typeRefNames(refs []schema.TypeRef) []string {
+	names := make([]string, len(refs))
+	for i, ref := range refs {
+		names[i] = ref.String()
+	}
+	return names
+}
+
+// indexBy builds a name index. Later entries win, matching the override
+// order of effective member slices.
+func indexBy[T any](items []T, key func(T) string) map[string]T {
+	m := make(map[string]T, len(items))
+	for _, item := range items {
+		m[key(item)] = item
+	}
+	return m
+}
+
+func mapKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// unionNames returns the sorted, de-duplicated union of two name lists.
+func unionNames(a, b []string) []string {
+	names := slices.Concat(a, b)
+	slices.Sort(names)
+	return slices.Compact(names)
+}
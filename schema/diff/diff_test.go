@@ -0,0 +1,384 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/diff"
+	"github.com/simon-lentz/yammm/schema/load"
+)
+
+func mustLoad(t *testing.T, source, name string) *schema.Schema {
+	t.Helper()
+	s, result, err := load.LoadString(t.Context(), source, name)
+	require.NoError(t, err)
+	require.False(t, result.HasErrors(), "unexpected errors: %v", result.Messages())
+	require.NotNil(t, s)
+	return s
+}
+
+// detailMap flattens issue details for assertions.
+func detailMap(issue diag.Issue) map[string]string {
+	m := make(map[string]string)
+	for _, d := range issue.Details() {
+		m[d.Key] = d.Value
+	}
+	return m
+}
+
+// findChange returns the single issue whose path matches.
+func findChange(t *testing.T, result diag.Result, path string) diag.Issue {
+	t.Helper()
+	var found []diag.Issue
+	for issue := range result.Issues() {
+		if issue.Path() == path {
+			found = append(found, issue)
+		}
+	}
+	require.Len(t, found, 1, "expected exactly one change at %q; got %v", path, result.Messages())
+	return found[0]
+}
+
+func TestCompare_Identical(t *testing.T) {
+	t.Parallel()
+
+	src := `schema "s"
+type Money = Float[0, _]
+type Person {
+	id String primary
+	name String[1, 50] required
+	balance Money
+	! "name_set" name != nil
+}`
+	result, err := diff.Compare(mustLoad(t, src, "old.yammm"), mustLoad(t, src, "new.yammm"))
+
+	require.NoError(t, err)
+	assert.True(t, result.OK(), "identical schemas should produce no changes: %v", result.Messages())
+	assert.Equal(t, 0, result.Len())
+	assert.Equal(t, diff.Compatible, diff.Overall(result))
+}
+
+func TestCompare_NilSchema(t *testing.T) {
+	t.Parallel()
+
+	s := mustLoad(t, `schema "s" type A { id String primary }`, "a.yammm")
+
+	_, err := diff.Compare(nil, s)
+	require.ErrorIs(t, err, diff.ErrNilSchema)
+	_, err = diff.Compare(s, nil)
+	require.ErrorIs(t, err, diff.ErrNilSchema)
+}
+
+func TestCompare_PropertyChanges(t *testing.T) {
+	t.Parallel()
+
+	oldSrc := `schema "s"
+type Person {
+	id String primary
+	name String[1, 50] required
+	nick String[1, 20]
+	age Integer[0, 150]
+	status Enum["active", "inactive", "banned"]
+	legacy String
+	kind String
+	email String required
+}`
+	newSrc := `schema "s"
+type Person {
+	id String primary
+	name String[1, 100] required
+	nick String[1, 10]
+	age Integer[0, 150] required
+	status Enum["active", "inactive"]
+	kind Integer
+	email String
+	notes String
+	country String required
+}`
+	result, err := diff.Compare(mustLoad(t, oldSrc, "old.yammm"), mustLoad(t, newSrc, "new.yammm"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		path   string
+		change string
+		compat diff.Compatibility
+	}{
+		{"Person.name", diff.ChangeChanged, diff.Compatible},     // widened max
+		{"Person.nick", diff.ChangeChanged, diff.DataBreaking},   // narrowed max
+		{"Person.age", diff.ChangeChanged, diff.DataBreaking},    // optional -> required
+		{"Person.status", diff.ChangeChanged, diff.DataBreaking}, // enum value removed
+		{"Person.legacy", diff.ChangeRemoved, diff.APIBreaking},
+		{"Person.kind", diff.ChangeChanged, diff.APIBreaking}, // constraint kind changed
+		{"Person.email", diff.ChangeChanged, diff.Compatible}, // required -> optional
+		{"Person.notes", diff.ChangeAdded, diff.Compatible},
+		{"Person.country", diff.ChangeAdded, diff.DataBreaking},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			issue := findChange(t, result, tt.path)
+			details := detailMap(issue)
+			assert.Equal(t, tt.change, details[diff.DetailKeyChange])
+			assert.Equal(t, diff.ElementProperty, details[diff.DetailKeyElement])
+			assert.Equal(t, tt.compat.String(), details[diff.DetailKeyCompatibility])
+			assert.Equal(t, tt.compat.Code(), issue.Code())
+			assert.Equal(t, tt.compat.Severity(), issue.Severity())
+			assert.Equal(t, "Person", details[diag.DetailKeyTypeName])
+		})
+	}
+
+	assert.Equal(t, diff.APIBreaking, diff.Overall(result))
+}
+
+func TestCompare_ConstraintChangeRecordsOldAndNew(t *testing.T) {
+	t.Parallel()
+
+	oldS := mustLoad(t, `schema "s" type A { id String primary
+	n Integer[0, 10] }`, "old.yammm")
+	newS := mustLoad(t, `schema "s" type A { id String primary
+	n Integer[0, 5] }`, "new.yammm")
+
+	result, err := diff.Compare(oldS, newS)
+	require.NoError(t, err)
+
+	issue := findChange(t, result, "A.n")
+	details := detailMap(issue)
+	assert.Equal(t, "Integer[0, 10]", details[diff.DetailKeyOld])
+	assert.Equal(t, "Integer[0, 5]", details[diff.DetailKeyNew])
+	assert.Equal(t, newS.SourceID(), issue.Span().Source)
+	require.Len(t, issue.Related(), 1)
+	assert.Equal(t, oldS.SourceID(), issue.Related()[0].Span.Source)
+}
+
+func TestCompare_TypeChanges(t *testing.T) {
+	t.Parallel()
+
+	oldSrc := `schema "s"
+abstract type Base { id String primary }
+type Kept extends Base { x String }
+type Gone { id String primary }
+type Concrete { id String primary }
+part type Piece { label String }`
+	newSrc := `schema "s"
+abstract type Base { id String primary }
+type Kept { id String primary
+	x String }
+type Fresh { id String primary }
+abstract type Concrete { id String primary }
+type Piece { id String primary
+	label String }`
+
+	oldS, newS := mustLoad(t, oldSrc, "old.yammm"), mustLoad(t, newSrc, "new.yammm")
+	result, err := diff.Compare(oldS, newS)
+	require.NoError(t, err)
+
+	gone := findChange(t, result, "Gone")
+	assert.Equal(t, diag.E_API_BREAKING_CHANGE, gone.Code())
+	assert.Equal(t, oldS.SourceID(), gone.Span().Source, "removals point at the old revision")
+
+	fresh := findChange(t, result, "Fresh")
+	assert.Equal(t, diag.W_COMPATIBLE_CHANGE, fresh.Code())
+	assert.Equal(t, newS.SourceID(), fresh.Span().Source)
+
+	var keptMsgs, concreteMsgs, pieceCodes []string
+	for issue := range result.Issues() {
+		switch issue.Path() {
+		case "Kept":
+			keptMsgs = append(keptMsgs, issue.Message())
+			assert.Equal(t, diag.E_API_BREAKING_CHANGE, issue.Code())
+		case "Concrete":
+			concreteMsgs = append(concreteMsgs, issue.Message())
+			assert.Equal(t, diag.E_DATA_BREAKING_CHANGE, issue.Code())
+		case "Piece":
+			pieceCodes = append(pieceCodes, issue.Code().String())
+		}
+	}
+	assert.Equal(t, []string{`type "Kept" no longer extends Base`}, keptMsgs)
+	assert.Equal(t, []string{`type "Concrete" became abstract`}, concreteMsgs)
+	assert.Equal(t, []string{"E_API_BREAKING_CHANGE"}, pieceCodes)
+}
+
+func TestCompare_RelationChanges(t *testing.T) {
+	t.Parallel()
+
+	oldSrc := `schema "s"
+type Person { id String primary }
+type Org { id String primary }
+type Account {
+	id String primary
+	--> OWNER (one) Person
+	--> MEMBERS (many) Person
+	--> SPONSOR Person
+	--> AUDITOR Person { since Date }
+	--> OLD Person
+}`
+	newSrc := `schema "s"
+type Person { id String primary }
+type Org { id String primary }
+type Account {
+	id String primary
+	--> OWNER (one) Org
+	--> MEMBERS Person
+	--> SPONSOR (one) Person
+	--> AUDITOR Person { since Date required }
+	--> EXTRA Person
+}`
+	result, err := diff.Compare(mustLoad(t, oldSrc, "old.yammm"), mustLoad(t, newSrc, "new.yammm"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		path    string
+		element string
+		change  string
+		compat  diff.Compatibility
+	}{
+		{"Account.OWNER", diff.ElementRelation, diff.ChangeChanged, diff.APIBreaking},   // target
+		{"Account.MEMBERS", diff.ElementRelation, diff.ChangeChanged, diff.APIBreaking}, // cardinality
+		{"Account.SPONSOR", diff.ElementRelation, diff.ChangeChanged, diff.DataBreaking},
+		{"Account.AUDITOR.since", diff.ElementProperty, diff.ChangeChanged, diff.DataBreaking},
+		{"Account.OLD", diff.ElementRelation, diff.ChangeRemoved, diff.APIBreaking},
+		{"Account.EXTRA", diff.ElementRelation, diff.ChangeAdded, diff.Compatible},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			details := detailMap(findChange(t, result, tt.path))
+			assert.Equal(t, tt.element, details[diff.DetailKeyElement])
+			assert.Equal(t, tt.change, details[diff.DetailKeyChange])
+			assert.Equal(t, tt.compat.String(), details[diff.DetailKeyCompatibility])
+		})
+	}
+}
+
+func TestCompare_DataTypeChanges(t *testing.T) {
+	t.Parallel()
+
+	oldSrc := `schema "s"
+type Code = String[1, 8]
+type Level = Enum["low", "high"]
+type Dropped = Integer
+type A { id String primary
+	code Code }`
+	newSrc := `schema "s"
+type Code = String[1, 4]
+type Level = Enum["low", "mid", "high"]
+type Added = Float
+type A { id String primary
+	code Code }`
+
+	result, err := diff.Compare(mustLoad(t, oldSrc, "old.yammm"), mustLoad(t, newSrc, "new.yammm"))
+	require.NoError(t, err)
+
+	code := detailMap(findChange(t, result, "Code"))
+	assert.Equal(t, diff.ElementDataType, code[diff.DetailKeyElement])
+	assert.Equal(t, diff.DataBreaking.String(), code[diff.DetailKeyCompatibility])
+
+	level := detailMap(findChange(t, result, "Level"))
+	assert.Equal(t, diff.Compatible.String(), level[diff.DetailKeyCompatibility])
+
+	assert.Equal(t, diag.E_API_BREAKING_CHANGE, findChange(t, result, "Dropped").Code())
+	assert.Equal(t, diag.W_COMPATIBLE_CHANGE, findChange(t, result, "Added").Code())
+
+	// The alias narrowing propagates to properties that use it.
+	prop := detailMap(findChange(t, result, "A.code"))
+	assert.Equal(t, diff.DataBreaking.String(), prop[diff.DetailKeyCompatibility])
+}
+
+func TestCompare_InvariantChanges(t *testing.T) {
+	t.Parallel()
+
+	oldSrc := `schema "s"
+type A {
+	id String primary
+	n Integer
+	! "kept" n != nil
+	! "edited" n > 0
+	! "dropped" n < 100
+}`
+	newSrc := `schema "s"
+type A {
+	id String primary
+	n Integer
+	! "kept" n != nil
+	! "edited" n > 1
+	! "added" n < 50
+}`
+	result, err := diff.Compare(mustLoad(t, oldSrc, "old.yammm"), mustLoad(t, newSrc, "new.yammm"))
+	require.NoError(t, err)
+
+	assert.Equal(t, diag.E_DATA_BREAKING_CHANGE, findChange(t, result, "A.!edited").Code())
+	assert.Equal(t, diag.E_DATA_BREAKING_CHANGE, findChange(t, result, "A.!added").Code())
+	assert.Equal(t, diag.W_COMPATIBLE_CHANGE, findChange(t, result, "A.!dropped").Code())
+	assert.Equal(t, 3, result.Len(), "unchanged invariant must not be reported: %v", result.Messages())
+	assert.Equal(t, diff.DataBreaking, diff.Overall(result))
+	assert.True(t, result.HasErrors(), "data-breaking changes fail the result")
+}
+
+func TestCompare_InvariantSeverityAndID(t *testing.T) {
+	t.Parallel()
+
+	oldSrc := `schema "s"
+type A {
+	id String primary
+	n Integer
+	!warn raised "n is small" n > 10
+	! lowered "n is large" n < 100
+	! renamed "old message" n != 0
+}`
+	newSrc := `schema "s"
+type A {
+	id String primary
+	n Integer
+	! raised "n is small" n > 10
+	!warn lowered "n is large" n < 100
+	! renamed "new message" n != 0
+	!info advisory "n is odd" n % 2 == 0
+}`
+	result, err := diff.Compare(mustLoad(t, oldSrc, "old.yammm"), mustLoad(t, newSrc, "new.yammm"))
+	require.NoError(t, err)
+
+	raised := findChange(t, result, "A.!raised")
+	assert.Equal(t, diag.E_DATA_BREAKING_CHANGE, raised.Code())
+	assert.Equal(t, "warning", detailMap(raised)[diff.DetailKeyOld])
+	assert.Equal(t, "error", detailMap(raised)[diff.DetailKeyNew])
+
+	assert.Equal(t, diag.W_COMPATIBLE_CHANGE, findChange(t, result, "A.!lowered").Code())
+	renamed := findChange(t, result, "A.!renamed")
+	assert.Equal(t, diag.W_COMPATIBLE_CHANGE, renamed.Code())
+	assert.Equal(t, "new message", detailMap(renamed)[diff.DetailKeyNew])
+	assert.Equal(t, diag.W_COMPATIBLE_CHANGE, findChange(t, result, "A.!advisory").Code(),
+		"a non-failing invariant cannot reject data")
+	assert.Equal(t, 4, result.Len(), result.Messages())
+}
+
+func TestCompare_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	oldS := mustLoad(t, `schema "s"
+type A { id String primary a String b String c String }
+type B { id String primary }`, "old.yammm")
+	newS := mustLoad(t, `schema "s"
+type A { id String primary d String e String }
+type C { id String primary }`, "new.yammm")
+
+	first, err := diff.Compare(oldS, newS)
+	require.NoError(t, err)
+	for range 5 {
+		again, err := diff.Compare(oldS, newS)
+		require.NoError(t, err)
+		assert.Equal(t, first.Messages(), again.Messages())
+	}
+}
+
+func TestCompatibility_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "compatible", diff.Compatible.String())
+	assert.Equal(t, "data-breaking", diff.DataBreaking.String())
+	assert.Equal(t, "api-breaking", diff.APIBreaking.String())
+	assert.Equal(t, "unknown", diff.Compatibility(99).String())
+}
@@ -0,0 +1,59 @@
+// Package diff compares two revisions of a schema and classifies each change
+// by its impact on existing data and on consumers of the schema.
+//
+// # Basic Usage
+//
+//	result, err := diff.Compare(oldSchema, newSchema)
+//	if err != nil {
+//	    return err
+//	}
+//	switch diff.Overall(result) {
+//	case diff.APIBreaking:
+//	    // names that consumers depend on were removed or reshaped
+//	case diff.DataBreaking:
+//	    // previously valid data may now be rejected
+//	}
+//
+// # Classification
+//
+// Every reported change carries one of three classifications:
+//
+//   - Compatible: data valid under the old revision stays valid and every
+//     existing name still resolves (e.g., optional property added, bounds
+//     widened, invariant removed).
+//   - DataBreaking: names are preserved but previously valid data may now be
+//     rejected (e.g., required property added, bounds narrowed, enum value
+//     removed, invariant added or changed).
+//   - APIBreaking: a name consumers depend on was removed or its shape
+//     changed (e.g., type or property removed, constraint kind changed,
+//     relation target or cardinality changed, primary key changed).
+//
+// Constraint changes are classified with [schema.Constraint.Equal] and
+// [schema.Constraint.NarrowsTo]: a new constraint that narrows the old one is
+// data-breaking, one that widens it is compatible, and a change of constraint
+// kind is API-breaking.
+//
+// # Output
+//
+// Changes are reported as a [diag.Result]. The classification determines the
+// issue code and severity:
+//
+//	Compatible   → W_COMPATIBLE_CHANGE    (Info)
+//	DataBreaking → E_DATA_BREAKING_CHANGE (Error)
+//	APIBreaking  → E_API_BREAKING_CHANGE  (Error)
+//
+// A result therefore fails if any change may reject existing data or break
+// consumers. Use [Overall] to tell the two breaking classes apart, e.g. to
+// accept data-breaking changes that come with a migration.
+//
+// Each issue carries structured details: [DetailKeyChange] (added, removed,
+// changed), [DetailKeyElement] (type, property, relation, datatype,
+// invariant), [DetailKeyCompatibility], and the affected type, property, or
+// relation names. Issues for added and changed elements point at the new
+// revision; issues for removed elements point at the old revision. Changed
+// elements also carry a related location for the old declaration.
+//
+// Properties, relations, and invariants are compared on the effective
+// (inherited plus declared) members of each type, so a change to a supertype
+// is reported for each local subtype whose instances it affects.
+package diff
@@ -73,7 +73,7 @@ func defaultBuiltinNames() map[string]struct{} {
 // This registry is used for:
 //   - Documentation and tooling (listing available functions)
 //   - IDE completions (suggesting function names)
-//   - Future eval-time validation hooks (ValidateBuiltins)
+//   - Load-time validation of called function names (Unknown)
 //
 // Note: Expression compilation does NOT validate function names against this
 // registry. Unknown functions compile successfully into the AST; validation
@@ -200,3 +200,52 @@ func (r *BuiltinRegistry) Clone() *BuiltinRegistry {
 	}
 	return &BuiltinRegistry{names: names}
 }
+
+// Unknown returns the names of functions called in e that are not in the
+// registry, in order of first appearance and without duplicates. Names are
+// compared case-insensitively, matching the evaluator's lookup.
+func (r *BuiltinRegistry) Unknown(e Expression) []string {
+	if r == nil {
+		return nil
+	}
+	r.ensureInit()
+	known := make(map[string]bool, len(r.names))
+	for name := range r.names {
+		known[strings.ToLower(name)] = true
+	}
+	var unknown []string
+	walkCalls(e, func(name string) {
+		key := strings.ToLower(name)
+		if !known[key] {
+			known[key] = true // report once
+			unknown = append(unknown, name)
+		}
+	})
+	return unknown
+}
+
+// walkCalls invokes visit for the name of every function call in e.
+// Calls have the shape SExpr{Op(name), lhs, args, params, body} produced by
+// Visitor.VisitFcall; operators never carry args and params literals.
+func walkCalls(e Expression, visit func(name string)) {
+	switch ex := e.(type) {
+	case SExpr:
+		children := ex.Children()
+		if len(children) == 4 {
+			_, isArgs := ArgsLiteral(children[1])
+			_, isParams := ParamsLiteral(children[2])
+			if isArgs && isParams {
+				visit(ex.Op())
+			}
+		}
+		for _, child := range children {
+			walkCalls(child, visit)
+		}
+	case *Literal:
+		if args, ok := ArgsLiteral(ex); ok {
+			for _, arg := range args {
+				walkCalls(arg, visit)
+			}
+		}
+	}
+}
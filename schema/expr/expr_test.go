@@ -1057,3 +1057,39 @@ func TestIsNilLiteral_EdgeCases(t *testing.T) {
 	assert.False(t, expr.IsNilLiteral(expr.DatatypeLiteral("String")))
 	assert.False(t, expr.IsNilLiteral(expr.SExpr{expr.Op("+"), expr.NewLiteral(1)}))
 }
+
+func TestBuiltinRegistry_Unknown(t *testing.T) {
+	compile := func(src string) expr.Expression {
+		collector := diag.NewCollector(0)
+		e := expr.CompileString(src, collector, location.MustNewSourceID("test://unknown.yammm"))
+		require.False(t, collector.HasErrors(), "compile %q: %v", src, collector.Result().Messages())
+		return e
+	}
+
+	r := expr.NewBuiltinRegistry()
+	r.MustRegister("Luhn")
+
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{"builtin", `name -> Len > 0`, nil},
+		{"builtin_any_case", `name -> upper == "A"`, nil},
+		{"registered", `card -> luhn`, nil},
+		{"unknown", `date -> IsWeekday`, []string{"IsWeekday"}},
+		{"nested_in_args", `code -> Default(code -> Normalize) != ""`, []string{"Normalize"}},
+		{"nested_in_body", `items -> All |$i| { $i -> IsValid }`, []string{"IsValid"}},
+		{"reported_once", `a -> Foo && b -> foo`, []string{"Foo"}},
+		{"operators_only", `a in [1, 2] && !b`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.Unknown(compile(tt.src)))
+		})
+	}
+
+	var nilReg *expr.BuiltinRegistry
+	assert.Nil(t, nilReg.Unknown(compile(`a -> Foo`)))
+}
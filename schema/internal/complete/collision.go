@@ -197,6 +197,31 @@ func (c *completer) validateRelationTargets() bool {
 		ok = false
 	}
 
+	if !c.validatePartSubtypes() {
+		ok = false
+	}
+
+	return ok
+}
+
+// validatePartSubtypes checks that types extending an abstract part type are
+// part types themselves, so every concrete subtype can be composed.
+func (c *completer) validatePartSubtypes() bool {
+	ok := true
+	for _, t := range c.schema.TypesSlice() {
+		if t.IsPart() {
+			continue
+		}
+		for _, ref := range t.InheritsSlice() {
+			super := c.resolveTypeRef(ref)
+			if super != nil && super.IsAbstract() && super.IsPart() {
+				c.errorf(t.Span(), diag.E_INVALID_COMPOSITION_TARGET,
+					"type %q extends abstract part type %q and must be declared part",
+					t.Name(), super.Name())
+				ok = false
+			}
+		}
+	}
 	return ok
 }
 
@@ -241,7 +266,7 @@ func (c *completer) validateRelationTarget(_ *schema.Type, r *schema.Relation, k
 		}
 
 		// Target not found
-		c.errorf(r.Span(), diag.E_UNKNOWN_TYPE,
+		c.unknownTypef(r.Span(), r.Target(),
 			"type %q referenced in %s %q does not exist",
 			r.Target().String(), kind, r.Name())
 		return false
@@ -253,7 +278,9 @@ func (c *completer) validateRelationTarget(_ *schema.Type, r *schema.Relation, k
 	return true
 }
 
-// validateCompositionTarget checks that a composition target is a concrete part type.
+// validateCompositionTarget checks that a composition target is a part type.
+// The target may be abstract, in which case children are instances of its
+// concrete subtypes.
 // NOTE: When the target is a cross-schema ref and registry is nil, the IsPart and
 // IsAbstract checks are deferred. These constraints should be re-validated when
 // the schema is linked with a registry that can resolve cross-schema references.
@@ -265,7 +292,7 @@ func (c *completer) validateCompositionTarget(t *schema.Type, r *schema.Relation
 			return true
 		}
 
-		c.errorf(r.Span(), diag.E_UNKNOWN_TYPE,
+		c.unknownTypef(r.Span(), r.Target(),
 			"type %q referenced in composition %q does not exist",
 			r.Target().String(), r.Name())
 		return false
@@ -279,13 +306,8 @@ func (c *completer) validateCompositionTarget(t *schema.Type, r *schema.Relation
 		return false
 	}
 
-	// Composition targets cannot be abstract
-	if target.IsAbstract() {
-		c.errorf(r.Span(), diag.E_INVALID_COMPOSITION_TARGET,
-			"composition %q in type %q must reference a concrete type, but %q is abstract",
-			r.Name(), t.Name(), target.Name())
-		return false
-	}
+	// An abstract part target is polymorphic: children are instances of its
+	// concrete part subtypes, selected by type tag at validation time.
 
 	// Resolve the semantic identity
 	r.SetTargetID(target.ID())
@@ -2,6 +2,8 @@ package complete
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -38,13 +40,16 @@ type ResolvedImports map[string]location.SourceID
 // Errors are collected in the provided collector. Returns nil if completion
 // fails with fatal errors. The registry is optional; when nil, cross-schema
 // references are deferred. The resolvedImports map provides pre-resolved
-// import alias to SourceID mappings from the loader.
+// import alias to SourceID mappings from the loader. Datatype names that are
+// not declared by a schema resolve to the types in customTypes, which may be
+// nil.
 func Complete(
 	model *parse.Model,
 	sourceID location.SourceID,
 	collector *diag.Collector,
 	registry Registry,
 	resolvedImports ResolvedImports,
+	customTypes *schema.CustomTypeRegistry,
 ) *schema.Schema {
 	if model == nil {
 		collector.Collect(diag.NewIssue(diag.Error, diag.E_INTERNAL, "no model to complete").Build())
@@ -57,6 +62,7 @@ func Complete(
 		collector:       collector,
 		registry:        registry,
 		resolvedImports: resolvedImports,
+		customTypes:     customTypes,
 		typeIndex:       make(map[string]*schema.Type),
 		dataIndex:       make(map[string]*schema.DataType),
 	}
@@ -71,6 +77,7 @@ type completer struct {
 	collector       *diag.Collector
 	registry        Registry
 	resolvedImports ResolvedImports
+	customTypes     *schema.CustomTypeRegistry
 	schema          *schema.Schema
 	typeIndex       map[string]*schema.Type
 	dataIndex       map[string]*schema.DataType
@@ -108,6 +115,11 @@ func (c *completer) complete() *schema.Schema {
 		return nil
 	}
 
+	// Phase 3c: Validate Map key and Set element types (must be after alias resolution)
+	if !c.validateContainerTypes() {
+		return nil
+	}
+
 	// Phase 4: Detect inheritance cycles
 	if !c.detectCycles() {
 		return nil
@@ -208,7 +220,7 @@ func (c *completer) indexTypes() bool {
 		t.SetCompositions(comps)
 
 		// Convert and set invariants
-		invariants := c.convertInvariants(td.Invariants)
+		invariants := c.convertInvariants(td.Invariants, td.Name)
 		t.SetInvariants(invariants)
 
 		c.typeIndex[td.Name] = t
@@ -467,8 +479,10 @@ func (c *completer) convertRelations(decls []*parse.RelationDecl, ownerType stri
 }
 
 // convertInvariants converts parse.InvariantDecl to schema.Invariant.
-func (c *completer) convertInvariants(decls []*parse.InvariantDecl) []*schema.Invariant {
+// Reports E_INVALID_INVARIANT when two invariants of a type share an ID.
+func (c *completer) convertInvariants(decls []*parse.InvariantDecl, ownerType string) []*schema.Invariant {
 	invs := make([]*schema.Invariant, 0, len(decls))
+	seen := make(map[string]*parse.InvariantDecl, len(decls))
 
 	for _, id := range decls {
 		if id == nil {
@@ -476,6 +490,25 @@ func (c *completer) convertInvariants(decls []*parse.InvariantDecl) []*schema.In
 		}
 
 		inv := schema.NewInvariant(id.Name, id.Expr, id.Span, id.Documentation)
+		inv.SetID(id.ID)
+		inv.SetLocalizedMessages(id.Messages)
+		if id.Severity != diag.Fatal {
+			inv.SetSeverity(id.Severity)
+		}
+
+		if existing, ok := seen[inv.ID()]; ok && (id.ID != "" || existing.ID != "") {
+			c.collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_INVARIANT,
+				fmt.Sprintf("invariant id %q is defined multiple times in type %q", inv.ID(), ownerType)).
+				WithSpan(id.Span).
+				WithDetail(diag.DetailKeyTypeName, ownerType).
+				WithDetail(diag.DetailKeyName, inv.ID()).
+				WithRelated(location.RelatedInfo{
+					Span:    existing.Span,
+					Message: "first defined here",
+				}).Build())
+			continue
+		}
+		seen[inv.ID()] = id
 		invs = append(invs, inv)
 	}
 
@@ -499,10 +532,10 @@ func (c *completer) resolveAliasConstraints() bool {
 		}
 	}
 
-	// Resolve List element aliases in DataTypes
+	// Resolve aliases nested in List, Map, Object and Union DataTypes
 	for _, dt := range c.schema.DataTypesSlice() {
-		if _, isList := dt.Constraint().(schema.ListConstraint); isList {
-			resolved, success := c.resolveListElementAliases(dt.Constraint(), dt.Span())
+		if hasNestedConstraints(dt.Constraint()) {
+			resolved, success := c.resolveNestedAliases(dt.Constraint(), dt.Span(), make(map[string]bool))
 			if !success {
 				ok = false
 				continue
@@ -525,11 +558,11 @@ func (c *completer) resolveAliasConstraints() bool {
 		}
 	}
 
-	// Resolve List element aliases in Properties
+	// Resolve aliases nested in List, Map and Object properties
 	for _, t := range c.schema.TypesSlice() {
 		for _, p := range t.PropertiesSlice() {
-			if _, isList := p.Constraint().(schema.ListConstraint); isList {
-				resolved, success := c.resolveListElementAliases(p.Constraint(), p.Span())
+			if hasNestedConstraints(p.Constraint()) {
+				resolved, success := c.resolveNestedAliases(p.Constraint(), p.Span(), make(map[string]bool))
 				if !success {
 					ok = false
 					continue
@@ -545,6 +578,8 @@ func (c *completer) resolveAliasConstraints() bool {
 // resolveAliasChain resolves a datatype name to its underlying constraint.
 // The visited map tracks seen names for cycle detection.
 // Returns the resolved AliasConstraint and success status.
+// An unqualified name with no schema datatype resolves to a registered
+// schema.CustomType, returned as a CustomConstraint.
 // If the datatype is not found, returns the original unresolved alias (not an error).
 // This allows for forward references and type-as-property patterns.
 func (c *completer) resolveAliasChain(dataTypeName string, span location.Span, visited map[string]bool) (schema.Constraint, bool) {
@@ -587,6 +622,12 @@ func (c *completer) resolveAliasChain(dataTypeName string, span location.Span, v
 	}
 
 	if !found {
+		// Not a schema datatype: fall back to a registered Go datatype.
+		if qualifier == "" {
+			if ct, ok := c.customTypes.Lookup(name); ok {
+				return schema.NewCustomConstraint(ct), true
+			}
+		}
 		// Datatype not found - leave unresolved
 		// This might be a type reference pattern or forward reference
 		return schema.NewAliasConstraint(dataTypeName, nil), true
@@ -603,47 +644,111 @@ func (c *completer) resolveAliasChain(dataTypeName string, span location.Span, v
 		return schema.NewAliasConstraint(dataTypeName, resolved), true
 	}
 
+	// Resolve aliases nested in the underlying constraint, so the result does
+	// not depend on the order in which datatypes are completed.
+	if hasNestedConstraints(underlying) {
+		resolved, ok := c.resolveNestedAliases(underlying, dt.Span(), visited)
+		if !ok {
+			return nil, false
+		}
+		underlying = resolved
+	}
+
 	// Otherwise, return new alias with underlying as resolved
 	return schema.NewAliasConstraint(dataTypeName, underlying), true
 }
 
-// resolveListElementAliases recursively resolves alias constraints inside
-// ListConstraint elements. Returns the constraint with aliases resolved,
-// or the original if no resolution was needed.
-func (c *completer) resolveListElementAliases(constraint schema.Constraint, span location.Span) (schema.Constraint, bool) {
-	lc, ok := constraint.(schema.ListConstraint)
-	if !ok {
-		return constraint, true
+// hasNestedConstraints reports whether constraint is a List, Map, Object or Union,
+// whose nested constraints may contain aliases.
+func hasNestedConstraints(constraint schema.Constraint) bool {
+	switch constraint.(type) {
+	case schema.ListConstraint, schema.MapConstraint, schema.ObjectConstraint, schema.UnionConstraint:
+		return true
+	default:
+		return false
 	}
+}
+
+// resolveNestedAliases recursively resolves alias constraints inside List
+// elements, Map keys and values, Object fields and Union members. Returns the constraint
+// with aliases resolved, or the original if no resolution was needed.
+func (c *completer) resolveNestedAliases(constraint schema.Constraint, span location.Span, visited map[string]bool) (schema.Constraint, bool) {
+	switch nc := constraint.(type) {
+	case schema.ListConstraint:
+		elem, ok := c.resolveNestedAlias(nc.Element(), span, visited)
+		if !ok {
+			return constraint, false
+		}
+		minLen, maxLen := bounds(nc.MinLen, nc.MaxLen)
+		if nc.IsUnique() {
+			return schema.NewSetConstraintBounded(elem, minLen, maxLen), true
+		}
+		return schema.NewListConstraintBounded(elem, minLen, maxLen), true
 
-	elem := lc.Element()
+	case schema.MapConstraint:
+		key, ok := c.resolveNestedAlias(nc.Key(), span, visited)
+		if !ok {
+			return constraint, false
+		}
+		value, ok := c.resolveNestedAlias(nc.Value(), span, visited)
+		if !ok {
+			return constraint, false
+		}
+		minLen, maxLen := bounds(nc.MinLen, nc.MaxLen)
+		return schema.NewMapConstraintBounded(key, value, minLen, maxLen), true
+
+	case schema.ObjectConstraint:
+		fields := nc.Fields()
+		for i, f := range fields {
+			fc, ok := c.resolveNestedAlias(f.Constraint(), span, visited)
+			if !ok {
+				return constraint, false
+			}
+			fields[i] = schema.NewObjectField(f.Name(), fc, f.IsRequired())
+		}
+		return schema.NewObjectConstraint(fields), true
 
-	// Recurse into nested lists first
-	resolved, ok := c.resolveListElementAliases(elem, span)
+	case schema.UnionConstraint:
+		members := nc.Members()
+		for i, m := range members {
+			mc, ok := c.resolveNestedAlias(m, span, visited)
+			if !ok {
+				return constraint, false
+			}
+			members[i] = mc
+		}
+		return schema.NewUnionConstraint(members...), true
+
+	default:
+		return constraint, true
+	}
+}
+
+// resolveNestedAlias resolves a nested constraint: its own nested aliases
+// first, then the constraint itself if it is an unresolved alias.
+func (c *completer) resolveNestedAlias(constraint schema.Constraint, span location.Span, visited map[string]bool) (schema.Constraint, bool) {
+	resolved, ok := c.resolveNestedAliases(constraint, span, visited)
 	if !ok {
 		return constraint, false
 	}
-	elem = resolved
-
-	// Resolve alias if element is an alias
-	if alias, isAlias := elem.(schema.AliasConstraint); isAlias && !alias.IsResolved() {
-		resolvedAlias, success := c.resolveAliasChain(alias.DataTypeName(), span, make(map[string]bool))
-		if !success {
-			return constraint, false
-		}
-		elem = resolvedAlias
+	if alias, isAlias := resolved.(schema.AliasConstraint); isAlias && !alias.IsResolved() {
+		return c.resolveAliasChain(alias.DataTypeName(), span, maps.Clone(visited))
 	}
+	return resolved, true
+}
 
-	// Rebuild with resolved element
-	minLen, hasMin := lc.MinLen()
-	maxLen, hasMax := lc.MaxLen()
+// bounds converts optional length bounds to the -1-for-absent form taken by
+// the bounded constraint constructors.
+func bounds(minFn, maxFn func() (int64, bool)) (minLen, maxLen int64) {
+	minLen, hasMin := minFn()
+	maxLen, hasMax := maxFn()
 	if !hasMin {
 		minLen = -1
 	}
 	if !hasMax {
 		maxLen = -1
 	}
-	return schema.NewListConstraintBounded(elem, minLen, maxLen), true
+	return minLen, maxLen
 }
 
 // parseQualifiedName splits a qualified name into qualifier and local name.
@@ -682,6 +787,121 @@ func (c *completer) validateRelationProperties() bool {
 	return ok
 }
 
+// validateContainerTypes checks the key types of Maps and the element types
+// of Sets, including those nested in List, Map, Object and Union
+// constraints. Keys are JSON object keys, so only String, Enum, Pattern and
+// UUID (or aliases of them) apply; set elements must have a canonical order
+// for duplicate detection, which rules out Map and Object.
+// Returns false if any invalid key or element types are found.
+func (c *completer) validateContainerTypes() bool {
+	ok := true
+	check := func(constraint schema.Constraint, span location.Span) {
+		walkNested(constraint, func(nested schema.Constraint) {
+			switch nc := nested.(type) {
+			case schema.MapConstraint:
+				if !isMapKeyAllowed(nc.Key()) {
+					c.errorf(span, diag.E_INVALID_CONSTRAINT,
+						"map key type must be String, Enum, Pattern or UUID, got %s", nc.Key())
+					ok = false
+				}
+			case schema.ListConstraint:
+				if nc.IsUnique() && !isSetElementAllowed(nc.Element()) {
+					c.errorf(span, diag.E_INVALID_CONSTRAINT,
+						"set element type must not be a Map or Object, got %s", nc.Element())
+					ok = false
+				}
+			}
+		})
+	}
+
+	for _, dt := range c.schema.DataTypesSlice() {
+		check(dt.Constraint(), dt.Span())
+	}
+	for _, t := range c.schema.TypesSlice() {
+		for _, p := range t.PropertiesSlice() {
+			check(p.Constraint(), p.Span())
+		}
+		for rel := range t.Associations() {
+			for _, p := range rel.PropertiesSlice() {
+				check(p.Constraint(), p.Span())
+			}
+		}
+	}
+	return ok
+}
+
+// walkNested calls visit for constraint and every constraint nested in it.
+// Aliases are not followed; aliased datatypes are checked where declared.
+func walkNested(constraint schema.Constraint, visit func(schema.Constraint)) {
+	visit(constraint)
+	switch nc := constraint.(type) {
+	case schema.ListConstraint:
+		walkNested(nc.Element(), visit)
+	case schema.MapConstraint:
+		walkNested(nc.Key(), visit)
+		walkNested(nc.Value(), visit)
+	case schema.ObjectConstraint:
+		for _, f := range nc.Fields() {
+			walkNested(f.Constraint(), visit)
+		}
+	case schema.UnionConstraint:
+		for _, m := range nc.Members() {
+			walkNested(m, visit)
+		}
+	}
+}
+
+// isSetElementAllowed reports whether a constraint can constrain set
+// elements. Aliases are unwrapped; unresolved aliases are left to link time.
+func isSetElementAllowed(constraint schema.Constraint) bool {
+	allowed := true
+	walkNested(resolvedAlias(constraint), func(nested schema.Constraint) {
+		switch resolvedAlias(nested).Kind() {
+		case schema.KindMap, schema.KindObject:
+			allowed = false
+		}
+	})
+	return allowed
+}
+
+// resolvedAlias unwraps resolved aliases. Unresolved aliases are returned
+// as-is.
+func resolvedAlias(constraint schema.Constraint) schema.Constraint {
+	for {
+		alias, ok := constraint.(schema.AliasConstraint)
+		if !ok || alias.Resolved() == nil {
+			return constraint
+		}
+		constraint = alias.Resolved()
+	}
+}
+
+// isMapKeyAllowed reports whether a constraint can constrain map keys.
+// Aliases are unwrapped; unresolved aliases are left to link time.
+func isMapKeyAllowed(constraint schema.Constraint) bool {
+	for {
+		alias, ok := constraint.(schema.AliasConstraint)
+		if !ok {
+			break
+		}
+		if alias.Resolved() == nil {
+			return true
+		}
+		constraint = alias.Resolved()
+	}
+	switch constraint.Kind() {
+	case schema.KindString, schema.KindEnum, schema.KindPattern, schema.KindUUID:
+		return true
+	case schema.KindUnion:
+		union, _ := constraint.(schema.UnionConstraint)
+		return !slices.ContainsFunc(union.Members(), func(m schema.Constraint) bool {
+			return !isMapKeyAllowed(m)
+		})
+	default:
+		return false
+	}
+}
+
 // isVectorConstraint checks if a constraint is or resolves to a Vector type.
 // Unwraps alias constraints to check the underlying type.
 func isVectorConstraint(constraint schema.Constraint) bool {
@@ -26,7 +26,7 @@ func TestComplete_EmptySchema(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "empty.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.Equal(t, "test", s.Name())
@@ -52,7 +52,7 @@ func TestComplete_SingleType(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "person.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	require.False(t, collector.HasErrors())
@@ -78,7 +78,7 @@ func TestComplete_DuplicateType(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "dup.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -106,7 +106,7 @@ func TestComplete_InheritanceCycle(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "cycle.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -143,7 +143,7 @@ func TestComplete_SimpleInheritance(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "inherit.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	require.False(t, collector.HasErrors())
@@ -200,7 +200,7 @@ func TestComplete_CaseCollision(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "case.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -225,7 +225,7 @@ func TestComplete_ReservedPrefix(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "reserved.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -245,7 +245,7 @@ func TestComplete_InvalidImportAlias(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "alias.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -255,7 +255,7 @@ func TestComplete_NilModel(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "nil.yammm")
 
-	s := complete.Complete(nil, srcID, collector, nil, nil)
+	s := complete.Complete(nil, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -300,7 +300,7 @@ func TestComplete_DiamondInheritance(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "diamond.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	require.False(t, collector.HasErrors())
@@ -348,7 +348,7 @@ func TestComplete_ForwardReferenceInheritance(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "forward.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	require.False(t, collector.HasErrors())
@@ -436,7 +436,7 @@ func TestComplete_DeepChainForwardReference(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "deep_chain.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	require.False(t, collector.HasErrors())
@@ -501,14 +501,14 @@ func TestComplete_CompositionTarget_MustBePart(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "comp_non_part.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors(), "composition to non-part type should error")
 }
 
-func TestComplete_CompositionTarget_CannotBeAbstract(t *testing.T) {
-	// Composition targeting an abstract part type should fail
+func TestComplete_CompositionTarget_AbstractPart(t *testing.T) {
+	// Composition targeting an abstract part type is polymorphic
 	model := &parse.Model{
 		Name: "test",
 		Types: []*parse.TypeDecl{
@@ -517,6 +517,11 @@ func TestComplete_CompositionTarget_CannotBeAbstract(t *testing.T) {
 				IsPart:     true,
 				IsAbstract: true,
 			},
+			{
+				Name:     "ConcretePart",
+				IsPart:   true,
+				Inherits: []*parse.TypeRef{{Name: "AbstractPart"}},
+			},
 			{
 				Name: "Container",
 				Relations: []*parse.RelationDecl{
@@ -533,10 +538,41 @@ func TestComplete_CompositionTarget_CannotBeAbstract(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "comp_abstract.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
+
+	require.NotNil(t, s, "unexpected errors: %v", collector.Result().Messages())
+	concrete, ok := s.Type("ConcretePart")
+	require.True(t, ok)
+	abstract, ok := s.Type("AbstractPart")
+	require.True(t, ok)
+	assert.True(t, concrete.IsSubTypeOf(abstract.ID()))
+}
+
+func TestComplete_AbstractPartSubtype_MustBePart(t *testing.T) {
+	// A type extending an abstract part type must itself be a part
+	model := &parse.Model{
+		Name: "test",
+		Types: []*parse.TypeDecl{
+			{
+				Name:       "AbstractPart",
+				IsPart:     true,
+				IsAbstract: true,
+			},
+			{
+				Name:     "Entity",
+				Inherits: []*parse.TypeRef{{Name: "AbstractPart"}},
+			},
+		},
+	}
+
+	collector := diag.NewCollector(0)
+	srcID := sourceID(t, "abstract_part_subtype.yammm")
+
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
-	assert.True(t, collector.HasErrors(), "composition to abstract part should error")
+	require.True(t, collector.HasErrors())
+	assert.Contains(t, collector.Result().Messages()[0], `type "Entity" extends abstract part type "AbstractPart" and must be declared part`)
 }
 
 func TestComplete_CompositionTarget_Valid(t *testing.T) {
@@ -564,7 +600,7 @@ func TestComplete_CompositionTarget_Valid(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "comp_valid.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -614,7 +650,7 @@ func TestComplete_CrossSchemaInheritance_WithRegistry(t *testing.T) {
 		},
 	}
 	baseCollector := diag.NewCollector(0)
-	baseSchema := complete.Complete(baseModel, baseSourceID, baseCollector, nil, nil)
+	baseSchema := complete.Complete(baseModel, baseSourceID, baseCollector, nil, nil, nil)
 	require.NotNil(t, baseSchema)
 	require.False(t, baseCollector.HasErrors())
 
@@ -657,7 +693,7 @@ func TestComplete_CrossSchemaInheritance_WithRegistry(t *testing.T) {
 	}
 
 	derivedCollector := diag.NewCollector(0)
-	derivedSchema := complete.Complete(derivedModel, derivedSourceID, derivedCollector, registry, resolvedImports)
+	derivedSchema := complete.Complete(derivedModel, derivedSourceID, derivedCollector, registry, resolvedImports, nil)
 
 	require.NotNil(t, derivedSchema, "cross-schema inheritance should succeed with registry")
 	assert.False(t, derivedCollector.HasErrors())
@@ -707,7 +743,7 @@ func TestComplete_CrossSchemaInheritance_DeferredWithoutRegistry(t *testing.T) {
 
 	// With nil registry, cross-schema references are deferred to linking phase
 	// The Complete function should not error for qualified refs when registry is nil
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	// Note: This behavior depends on implementation - if cross-schema refs
 	// without registry are deferred, this should succeed. If they error
@@ -737,7 +773,7 @@ func TestComplete_DataType_Simple(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "datatype.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -759,7 +795,7 @@ func TestComplete_DataType_Duplicate(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "dup_datatype.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -778,7 +814,7 @@ func TestComplete_DataType_Multiple(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "multi_datatype.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -797,7 +833,7 @@ func TestComplete_DataType_NilSkipped(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "nil_datatype.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -827,7 +863,7 @@ func TestComplete_Invariant_Single(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "invariant.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -861,7 +897,7 @@ func TestComplete_Invariant_Multiple(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "multi_invariant.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -890,7 +926,7 @@ func TestComplete_Invariant_NilSkipped(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "nil_invariant.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -933,7 +969,7 @@ func TestComplete_RelationNormalizationCollision_Associations(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "rel_collision.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -967,7 +1003,7 @@ func TestComplete_RelationNormalizationCollision_AssociationAndComposition(t *te
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "rel_collision_mixed.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -1001,7 +1037,7 @@ func TestComplete_PropertyRelationCollision_Association(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "prop_rel_collision.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -1031,7 +1067,7 @@ func TestComplete_PropertyRelationCollision_Composition(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "prop_comp_collision.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -1066,7 +1102,7 @@ func TestComplete_Association_ValidTarget(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "valid_assoc.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -1094,7 +1130,7 @@ func TestComplete_Composition_ValidTarget(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "valid_comp.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -1126,7 +1162,7 @@ func TestComplete_AssociationTarget_Valid(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "assoc_valid.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -1152,7 +1188,7 @@ func TestComplete_AssociationTarget_UnknownType(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "assoc_unknown.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -1182,7 +1218,7 @@ func TestComplete_AssociationTarget_CrossSchema_DeferredWithoutRegistry(t *testi
 	srcID := sourceID(t, "assoc_cross.yammm")
 
 	// Without registry, cross-schema refs are deferred
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	// Should not error - deferred to linking phase
 	if s != nil {
@@ -1228,7 +1264,7 @@ func TestComplete_RelationInheritance_Associations(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "rel_inherit.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -1279,7 +1315,7 @@ func TestComplete_RelationInheritance_Compositions(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "comp_inherit.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -1337,7 +1373,7 @@ func TestComplete_RelationInheritance_ConflictingDifferentOptional(t *testing.T)
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "rel_conflict.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -1358,7 +1394,7 @@ func TestComplete_Import_InvalidAlias_StartsWithNumber(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "import_invalid.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -1376,7 +1412,7 @@ func TestComplete_Import_DuplicateAlias(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "import_dup.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -1402,7 +1438,7 @@ func TestComplete_Import_DuplicateSourceID(t *testing.T) {
 		"common": commonSourceID, // Same SourceID!
 	}
 
-	s := complete.Complete(model, srcID, collector, nil, resolvedImports)
+	s := complete.Complete(model, srcID, collector, nil, resolvedImports, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -1428,7 +1464,7 @@ func TestComplete_Import_CollidesWithLocalType(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "import_collision.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -1445,7 +1481,7 @@ func TestComplete_Import_NilSkipped(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "import_nil.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -1465,7 +1501,7 @@ func TestComplete_Import_MissingResolution(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "import_missing_res.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, resolvedImports)
+	s := complete.Complete(model, srcID, collector, nil, resolvedImports, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -1500,7 +1536,7 @@ func TestComplete_Association_WithEdgeProperties(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "edge_props.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -1546,7 +1582,7 @@ func TestComplete_Association_EdgePropertyNilSkipped(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "edge_props_nil.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -1572,7 +1608,7 @@ func TestComplete_UnknownTypeInExtends(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "unknown_extends.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors())
@@ -1590,7 +1626,7 @@ func TestComplete_NilTypeDecl_Skipped(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "nil_type.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -1614,7 +1650,7 @@ func TestComplete_NilPropertyDecl_Skipped(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "nil_prop.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -1646,7 +1682,7 @@ func TestComplete_NilRelationDecl_Skipped(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "nil_rel.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -1670,7 +1706,7 @@ func TestComplete_NilInheritsRef_Skipped(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "nil_inherit.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s)
 	assert.False(t, collector.HasErrors())
@@ -1703,7 +1739,7 @@ func TestComplete_PartType_CannotDeclareAssociation(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "part_assoc.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors(), "part type declaring association should error")
@@ -1741,7 +1777,7 @@ func TestComplete_Association_CannotTargetPartType(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "assoc_part_target.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s)
 	assert.True(t, collector.HasErrors(), "association targeting part type should error")
@@ -58,7 +58,7 @@ func TestInvariantInheritance_SingleParent(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "inv_single_parent.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile")
 	require.False(t, collector.HasErrors(), "no errors expected")
@@ -130,7 +130,7 @@ func TestInvariantInheritance_ChildOverride(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "inv_child_override.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile")
 	require.False(t, collector.HasErrors(), "no errors expected")
@@ -223,7 +223,7 @@ func TestInvariantInheritance_Diamond(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "inv_diamond.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile")
 	require.False(t, collector.HasErrors(), "no errors expected")
@@ -1,9 +1,12 @@
 package complete
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/internal/ident"
+	"github.com/simon-lentz/yammm/location"
 	"github.com/simon-lentz/yammm/schema"
 )
 
@@ -162,7 +165,7 @@ func (c *completer) completeTypes() bool {
 				// Only emit error for local refs; cross-schema refs are deferred
 				// when registry is nil (they will be validated when registry is available).
 				if ref.Qualifier() == "" {
-					c.errorf(t.Span(), diag.E_UNKNOWN_TYPE,
+					c.unknownTypef(t.Span(), ref,
 						"unknown type %q in extends clause of type %q", ref.Name(), t.Name())
 					ok = false
 				}
@@ -297,6 +300,59 @@ func (c *completer) resolveTypeRef(ref schema.TypeRef) *schema.Type {
 	return t
 }
 
+// typeSuggestion returns the type in scope that an unresolved reference was
+// most likely meant to name: a local type for an unqualified reference, or a
+// type of the aliased schema for a qualified one. Returns "" if no type is
+// close enough.
+func (c *completer) typeSuggestion(ref schema.TypeRef) string {
+	if ref.Qualifier() == "" {
+		candidates := make([]string, 0, len(c.typeIndex))
+		for name := range c.typeIndex {
+			candidates = append(candidates, name)
+		}
+		suggestion, _ := ident.Suggest(ref.Name(), candidates)
+		return suggestion
+	}
+
+	if c.registry == nil {
+		return ""
+	}
+	imp, ok := c.schema.ImportByAlias(ref.Qualifier())
+	if !ok {
+		return ""
+	}
+	importedSchema, ok := c.registry.LookupBySourceID(imp.ResolvedSourceID())
+	if !ok {
+		return ""
+	}
+	var candidates []string
+	for _, t := range importedSchema.TypesSlice() {
+		candidates = append(candidates, t.Name())
+	}
+	if suggestion, ok := ident.Suggest(ref.Name(), candidates); ok {
+		return ref.Qualifier() + "." + suggestion
+	}
+	return ""
+}
+
+// unknownTypef reports E_UNKNOWN_TYPE at span for an unresolved reference,
+// suggesting the closest type in scope along with a fix that replaces the
+// reference.
+func (c *completer) unknownTypef(span location.Span, ref schema.TypeRef, format string, args ...any) {
+	issue := diag.NewIssue(diag.Error, diag.E_UNKNOWN_TYPE, fmt.Sprintf(format, args...))
+	if !span.IsZero() {
+		issue = issue.WithSpan(span)
+	}
+	if suggestion := c.typeSuggestion(ref); suggestion != "" {
+		issue = issue.WithSuggestion(suggestion)
+		if !ref.Span().IsZero() {
+			issue = issue.WithFix(fmt.Sprintf("Replace with %q", suggestion),
+				diag.TextEdit{Span: ref.Span(), NewText: suggestion})
+		}
+	}
+	c.collector.Collect(issue.Build())
+}
+
 // resolveTypeID resolves a TypeID to a Type.
 func (c *completer) resolveTypeID(id schema.TypeID) *schema.Type {
 	if id.SchemaPath() == c.sourceID {
@@ -382,12 +438,12 @@ func (c *completer) mergeProperties(t *schema.Type, supers []schema.ResolvedType
 
 // mergeInvariants merges own invariants with inherited invariants.
 // Own invariants come first, then inherited (left-to-right supertype order).
-// Deduplication by name: keep-first (child can override parent's invariant by name).
+// Deduplication by ID: keep-first (child can override parent's invariant by ID).
 func (c *completer) mergeInvariants(t *schema.Type, supers []schema.ResolvedTypeRef) []*schema.Invariant {
 	result := t.InvariantsSlice()
 	seen := make(map[string]bool)
 	for _, inv := range result {
-		seen[inv.Name()] = true
+		seen[inv.ID()] = true
 	}
 
 	for _, superRef := range supers {
@@ -397,10 +453,10 @@ func (c *completer) mergeInvariants(t *schema.Type, supers []schema.ResolvedType
 		}
 
 		for _, inv := range superType.AllInvariantsSlice() {
-			if seen[inv.Name()] {
+			if seen[inv.ID()] {
 				continue
 			}
-			seen[inv.Name()] = true
+			seen[inv.ID()] = true
 			result = append(result, inv)
 		}
 	}
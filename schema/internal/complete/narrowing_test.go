@@ -49,7 +49,7 @@ func TestNarrowing_ValidConstraintNarrowing(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "narrow_valid.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile with valid narrowing")
 	require.False(t, collector.HasErrors(), "valid narrowing should not produce errors")
@@ -109,7 +109,7 @@ func TestNarrowing_ValidModifierOverride(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "narrow_modifier.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile with optional->required narrowing")
 	require.False(t, collector.HasErrors(), "optional->required narrowing should not produce errors")
@@ -159,7 +159,7 @@ func TestNarrowing_WideningRejected(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "narrow_widening.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s, "widening should cause schema completion to fail")
 	assert.True(t, collector.HasErrors(), "widening should produce E_PROPERTY_CONFLICT")
@@ -206,7 +206,7 @@ func TestNarrowing_RequiredToOptionalRejected(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "narrow_req_opt.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	assert.Nil(t, s, "required->optional widening should cause schema completion to fail")
 	assert.True(t, collector.HasErrors(), "required->optional should produce E_PROPERTY_CONFLICT")
@@ -253,7 +253,7 @@ func TestNarrowing_EnumSubset(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "narrow_enum.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile with enum subset narrowing")
 	require.False(t, collector.HasErrors(), "enum subset narrowing should not produce errors")
@@ -401,6 +401,16 @@ func (c *completer) walkMemberAccess(children []expr.Expression, scope *staticSc
 		}
 	}
 
+	// Check if LHS is an inline Object value -- validate member against its fields
+	if oc, isObject := c.objectConstraintOf(children[0], scope, ownerType); isObject {
+		if !objectHasField(oc, memberName) {
+			c.errorf(inv.Span(), diag.E_UNKNOWN_PROPERTY,
+				"unknown field %q on %s in invariant %q on type %q",
+				memberName, oc, inv.Name(), ownerType.Name())
+			ok = false
+		}
+	}
+
 	// Walk any remaining children (method call args, body, etc.)
 	for i := 2; i < len(children); i++ {
 		if !c.walkExpr(children[i], scope, ownerType, inv) {
@@ -411,6 +421,90 @@ func (c *completer) walkMemberAccess(children []expr.Expression, scope *staticSc
 	return ok
 }
 
+// objectConstraintOf returns the Object constraint of an expression that
+// statically denotes an inline Object value: a property (`location`,
+// `$self.location`) or a field of another such value (`shape.origin`).
+func (c *completer) objectConstraintOf(e expr.Expression, scope *staticScope, ownerType *schema.Type) (schema.ObjectConstraint, bool) {
+	sexpr, ok := e.(expr.SExpr)
+	if !ok {
+		return schema.ObjectConstraint{}, false
+	}
+	children := sexpr.Children()
+
+	var constraint schema.Constraint
+	switch {
+	case sexpr.Op() == "p" && len(children) == 1:
+		name, ok := expr.StringLiteral(children[0])
+		if !ok {
+			return schema.ObjectConstraint{}, false
+		}
+		if _, isVar := scope.vars[strings.ToLower(name)]; isVar {
+			return schema.ObjectConstraint{}, false
+		}
+		constraint = propertyConstraint(ownerType, name)
+
+	case sexpr.Op() == "." && len(children) == 2:
+		lit, isLit := children[1].(*expr.Literal)
+		if !isLit {
+			return schema.ObjectConstraint{}, false
+		}
+		member, ok := lit.Val.(string)
+		if !ok {
+			return schema.ObjectConstraint{}, false
+		}
+		if c.isVarExpr(children[0], "self") {
+			constraint = propertyConstraint(ownerType, member)
+			break
+		}
+		parent, ok := c.objectConstraintOf(children[0], scope, ownerType)
+		if !ok {
+			return schema.ObjectConstraint{}, false
+		}
+		for _, f := range parent.Fields() {
+			if strings.EqualFold(f.Name(), member) {
+				constraint = f.Constraint()
+				break
+			}
+		}
+	}
+
+	oc, ok := unwrapAlias(constraint).(schema.ObjectConstraint)
+	return oc, ok
+}
+
+// propertyConstraint returns the constraint of a property of t (own or
+// inherited, matched case-insensitively), or nil if there is none.
+func propertyConstraint(t *schema.Type, name string) schema.Constraint {
+	for _, p := range t.AllPropertiesSlice() {
+		if strings.EqualFold(p.Name(), name) {
+			return p.Constraint()
+		}
+	}
+	return nil
+}
+
+// objectHasField reports whether oc declares a field matching name
+// case-insensitively, as member access does at evaluation time.
+func objectHasField(oc schema.ObjectConstraint, name string) bool {
+	for _, f := range oc.Fields() {
+		if strings.EqualFold(f.Name(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// unwrapAlias follows resolved alias constraints to the underlying constraint.
+func unwrapAlias(constraint schema.Constraint) schema.Constraint {
+	for {
+		alias, ok := constraint.(schema.AliasConstraint)
+		if !ok || alias.Resolved() == nil {
+			return constraint
+		}
+		constraint = alias.Resolved()
+	}
+}
+
 // isVarExpr checks if an expression is a variable reference with the given name.
 func (c *completer) isVarExpr(e expr.Expression, name string) bool {
 	sexpr, ok := e.(expr.SExpr)
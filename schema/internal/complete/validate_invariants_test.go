@@ -47,7 +47,7 @@ func TestValidateInvariant_ValidProperty(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "valid_prop.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile")
 	assert.False(t, collector.HasErrors(), "no errors expected for valid property reference")
@@ -87,7 +87,7 @@ func TestValidateInvariant_UnknownProperty(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "unknown_prop.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.Nil(t, s, "schema should fail to compile")
 	require.True(t, collector.HasErrors())
@@ -171,7 +171,7 @@ func TestValidateInvariant_LambdaValidProperty(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "lambda_valid.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile")
 	assert.False(t, collector.HasErrors(), "no errors expected for valid lambda property")
@@ -239,7 +239,7 @@ func TestValidateInvariant_LambdaUnknownProperty(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "lambda_bad.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.Nil(t, s, "schema should fail to compile")
 	require.True(t, collector.HasErrors())
@@ -293,7 +293,7 @@ func TestValidateInvariant_SelfDotProperty(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "self_dot_prop.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile")
 	assert.False(t, collector.HasErrors(), "no errors expected for $self.name reference")
@@ -337,7 +337,7 @@ func TestValidateInvariant_SelfDotUnknownProperty(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "self_dot_unknown.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.Nil(t, s, "schema should fail to compile")
 	require.True(t, collector.HasErrors())
@@ -398,7 +398,7 @@ func TestValidateInvariant_InheritedProperty(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "inherited_prop.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile")
 	assert.False(t, collector.HasErrors(), "no errors expected for inherited property reference")
@@ -438,7 +438,7 @@ func TestValidateInvariant_CaseInsensitive(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "case_insensitive.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile")
 	assert.False(t, collector.HasErrors(), "no errors expected for case-insensitive property reference")
@@ -500,7 +500,7 @@ func TestValidateInvariant_RelationNameValid(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "relation_name.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile")
 	assert.False(t, collector.HasErrors(), "no errors expected for relation name reference in invariant")
@@ -571,7 +571,7 @@ func TestValidateInvariant_ReduceBuiltin(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "reduce_builtin.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile")
 	assert.False(t, collector.HasErrors(), "no errors expected for valid Reduce builtin")
@@ -639,7 +639,7 @@ func TestValidateInvariant_ReduceUnknownProperty(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "reduce_bad.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.Nil(t, s, "schema should fail to compile")
 	require.True(t, collector.HasErrors())
@@ -700,7 +700,7 @@ func TestValidateInvariant_ThenBuiltin(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "then_builtin.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile")
 	assert.False(t, collector.HasErrors(), "no errors expected for valid Then builtin")
@@ -785,7 +785,7 @@ func TestValidateInvariant_NestedCollectionBuiltins(t *testing.T) {
 	collector := diag.NewCollector(0)
 	srcID := sourceID(t, "nested_builtins.yammm")
 
-	s := complete.Complete(model, srcID, collector, nil, nil)
+	s := complete.Complete(model, srcID, collector, nil, nil, nil)
 
 	require.NotNil(t, s, "schema should compile")
 	assert.False(t, collector.HasErrors(), "no errors expected for nested collection builtins")
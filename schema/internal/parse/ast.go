@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"github.com/simon-lentz/yammm/diag"
 	"github.com/simon-lentz/yammm/location"
 	"github.com/simon-lentz/yammm/schema"
 	"github.com/simon-lentz/yammm/schema/expr"
@@ -126,7 +127,10 @@ type RelationDecl struct {
 // evaluate it without re-parsing.
 type InvariantDecl struct {
 	Name          string
-	Expr          expr.Expression // Compiled expression (nil indicates parse failure)
+	ID            string            // Explicit identifier (empty when not declared)
+	Severity      diag.Severity     // Failure severity; the zero value (diag.Fatal) means diag.Error
+	Expr          expr.Expression   // Compiled expression (nil indicates parse failure)
+	Messages      map[string]string // Localized messages by normalized locale (nil when none)
 	Documentation string
 	Span          location.Span
 }
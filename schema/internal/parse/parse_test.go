@@ -2,6 +2,7 @@ package parse_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -355,6 +356,140 @@ type Person {
 	assert.True(t, result.OK(), "expected no errors, got: %v", result)
 }
 
+func TestParser_InvariantModifiers(t *testing.T) {
+	schemaSource := `schema "test"
+
+type Person {
+	age Integer
+	email String
+	active Boolean
+	! "Age must be positive" age > 0
+	!warn "Email should be set" email != ""
+	!info email_domain "Email should be corporate" email =~ /@corp\.example$/
+	! active_flag "Must be active" !active
+}`
+
+	reg := source.NewRegistry()
+	sourceID := registerSource(t, reg, schemaSource, "test.yammm")
+	collector := diag.NewCollector(0)
+
+	parser := parse.NewParser(sourceID, collector, reg, reg)
+	model := parser.Parse([]byte(schemaSource))
+
+	result := collector.Result()
+	require.True(t, result.OK(), "expected no errors, got: %v", result)
+	require.Len(t, model.Types, 1)
+	invs := model.Types[0].Invariants
+	require.Len(t, invs, 4)
+
+	tests := []struct {
+		name     string
+		id       string
+		severity diag.Severity
+	}{
+		{"Age must be positive", "", diag.Error},
+		{"Email should be set", "", diag.Warning},
+		{"Email should be corporate", "email_domain", diag.Info},
+		{"Must be active", "active_flag", diag.Error},
+	}
+	for i, tt := range tests {
+		assert.Equal(t, tt.name, invs[i].Name)
+		assert.Equal(t, tt.id, invs[i].ID)
+		assert.Equal(t, tt.severity, invs[i].Severity)
+		assert.NotNil(t, invs[i].Expr)
+	}
+	assert.Equal(t, "!", invs[3].Expr.Op(), "negation inside expression must not be read as a modifier")
+}
+
+func TestParser_InvariantMessageVariants(t *testing.T) {
+	schemaSource := `schema "test"
+
+type Person {
+	age Integer
+	!warn adult "Must be an adult" @de "Muss volljährig sein" @pt_BR 'Deve ser adulto' age >= 18
+	! "No variants" age < 150
+}`
+
+	reg := source.NewRegistry()
+	sourceID := registerSource(t, reg, schemaSource, "test.yammm")
+	collector := diag.NewCollector(0)
+
+	parser := parse.NewParser(sourceID, collector, reg, reg)
+	model := parser.Parse([]byte(schemaSource))
+
+	result := collector.Result()
+	require.True(t, result.OK(), "expected no errors, got: %v", result)
+	invs := model.Types[0].Invariants
+	require.Len(t, invs, 2)
+
+	assert.Equal(t, "Must be an adult", invs[0].Name)
+	assert.Equal(t, "adult", invs[0].ID)
+	assert.Equal(t, diag.Warning, invs[0].Severity)
+	assert.Equal(t, map[string]string{"de": "Muss volljährig sein", "pt-br": "Deve ser adulto"}, invs[0].Messages)
+	assert.Equal(t, ">=", invs[0].Expr.Op())
+	assert.Nil(t, invs[1].Messages)
+}
+
+func TestParser_InvariantMessageVariants_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		decl string
+		want string
+	}{
+		{"duplicate locale", `! "msg" @de "a" @de "b" age > 0`, `duplicate invariant message for locale "de"`},
+		{"missing message", `! "msg" @de age > 0`, ""},
+		{"missing locale", `! "msg" @ "a" age > 0`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaSource := "schema \"test\"\n\ntype Person {\n\tage Integer\n\t" + tt.decl + "\n}"
+
+			reg := source.NewRegistry()
+			sourceID := registerSource(t, reg, schemaSource, "test.yammm")
+			collector := diag.NewCollector(0)
+
+			parser := parse.NewParser(sourceID, collector, reg, reg)
+			_ = parser.Parse([]byte(schemaSource))
+
+			result := collector.Result()
+			require.False(t, result.OK(), "expected syntax error")
+			if tt.want != "" {
+				assert.Contains(t, result.Messages()[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_InvariantModifiers_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		decl string
+		want string
+	}{
+		{"unknown severity", `!fatal age_check "msg" age > 0`, "unknown invariant severity"},
+		{"reserved identifier", `!warn info "msg" age > 0`, "reserved severity keyword"},
+		{"too many modifiers", `!warn a b "msg" age > 0`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaSource := "schema \"test\"\n\ntype Person {\n\tage Integer\n\t" + tt.decl + "\n}"
+
+			reg := source.NewRegistry()
+			sourceID := registerSource(t, reg, schemaSource, "test.yammm")
+			collector := diag.NewCollector(0)
+
+			parser := parse.NewParser(sourceID, collector, reg, reg)
+			_ = parser.Parse([]byte(schemaSource))
+
+			result := collector.Result()
+			require.False(t, result.OK(), "expected syntax error")
+			if tt.want != "" {
+				assert.Contains(t, result.Messages()[0], tt.want)
+			}
+		})
+	}
+}
+
 func TestParser_EnumConstraint(t *testing.T) {
 	schemaSource := `schema "test"
 
@@ -380,6 +515,297 @@ type Order {
 	assert.True(t, result.OK(), "expected no errors, got: %v", result)
 }
 
+func TestParser_StructuredTypes(t *testing.T) {
+	schemaSource := `schema "test"
+
+type Point = Object { lat Float required, lon Float required }
+
+type Place {
+	name String primary
+	scores Map<Enum["a", "b"], Integer[0, _]>[1, 5] required
+	location Point
+	address Object {
+		/* Street line. */
+		street String required
+		tags Map<String, List<String>>
+	}
+	stops List<Object { at Timestamp required }>
+	opened Date
+}`
+
+	reg := source.NewRegistry()
+	sourceID := registerSource(t, reg, schemaSource, "test.yammm")
+	collector := diag.NewCollector(0)
+
+	parser := parse.NewParser(sourceID, collector, reg, reg)
+	model := parser.Parse([]byte(schemaSource))
+
+	result := collector.Result()
+	require.True(t, result.OK(), "expected no errors, got: %v", result)
+	require.Len(t, model.DataTypes, 1)
+	assert.Equal(t, "Object { lat Float required, lon Float required }", model.DataTypes[0].Constraint.String())
+
+	require.Len(t, model.Types, 1)
+	props := model.Types[0].Properties
+	require.Len(t, props, 6)
+
+	tests := []struct {
+		name     string
+		want     string
+		optional bool
+	}{
+		{"scores", `Map<Enum["a", "b"], Integer[0, _]>[1, 5]`, false},
+		{"location", "Point", true},
+		{"address", "Object { street String required, tags Map<String, List<String>> }", true},
+		{"stops", "List<Object { at Timestamp required }>", true},
+		{"opened", "Date", true},
+	}
+	for i, tt := range tests {
+		prop := props[i+1]
+		assert.Equal(t, tt.name, prop.Name)
+		assert.Equal(t, tt.want, prop.Constraint.String())
+		assert.Equal(t, tt.optional, prop.Optional)
+	}
+}
+
+func TestParser_StructuredTypes_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		decl string
+		want string
+	}{
+		{"map without value", "m Map<String>", "map type requires key and value types"},
+		{"map empty value", "m Map<String, >", "missing map value type"},
+		{"map bad bounds", "m Map<String, Integer>[1]", "map size bounds must have the form [min, max]"},
+		{"map negative bound", "m Map<String, Integer>[-1, 2]", "map size bounds must have the form [min, max]"},
+		{"map inverted bounds", "m Map<String, Integer>[5, 2]", "map size bounds inverted"},
+		{"map trailing tokens", "m Map<String Integer, Float>", "unexpected"},
+		{"empty object", "o Object {}", "object type must declare at least one field"},
+		{"duplicate field", "o Object { a Integer, a String }", `object field "a" is defined multiple times`},
+		{"field without type", "o Object { a }", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaSource := "schema \"test\"\n\ntype Thing {\n\t" + tt.decl + "\n}"
+
+			reg := source.NewRegistry()
+			sourceID := registerSource(t, reg, schemaSource, "test.yammm")
+			collector := diag.NewCollector(0)
+
+			parser := parse.NewParser(sourceID, collector, reg, reg)
+			_ = parser.Parse([]byte(schemaSource))
+
+			result := collector.Result()
+			require.False(t, result.OK(), "expected error")
+			if tt.want != "" {
+				assert.Contains(t, result.Messages()[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_UnionTypes(t *testing.T) {
+	schemaSource := `schema "test"
+
+type Id = String[1, 36] | Integer
+type Payment = Card | Object { iban String required } | Map<String, Integer>
+/* Flags may be named or numbered. */
+type Flag = Enum["on", "off"] | Integer[0, 1]
+
+abstract part type Tender {
+	amount Float required
+}
+
+part type Cash extends Tender {}
+
+type Order {
+	id Id
+	*-> TENDER (one) Tender
+}`
+
+	reg := source.NewRegistry()
+	sourceID := registerSource(t, reg, schemaSource, "test.yammm")
+	collector := diag.NewCollector(0)
+
+	parser := parse.NewParser(sourceID, collector, reg, reg)
+	model := parser.Parse([]byte(schemaSource))
+
+	result := collector.Result()
+	require.True(t, result.OK(), "expected no errors, got: %v", result)
+	require.Len(t, model.DataTypes, 3)
+	assert.Equal(t, "String[1, 36] | Integer", model.DataTypes[0].Constraint.String())
+	assert.Equal(t, "Card | Object { iban String required } | Map<String, Integer>", model.DataTypes[1].Constraint.String())
+	assert.Equal(t, "Flags may be named or numbered.", model.DataTypes[2].Documentation)
+
+	require.Len(t, model.Types, 3)
+	tender := model.Types[0]
+	assert.True(t, tender.IsAbstract)
+	assert.True(t, tender.IsPart, "abstract part type is both abstract and part")
+	assert.False(t, model.Types[1].IsAbstract)
+	assert.True(t, model.Types[1].IsPart)
+}
+
+func TestParser_UnionTypes_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		decl string
+		want string
+	}{
+		{"empty member", "type U = String | | Integer", "missing union member type"},
+		{"trailing pipe", "type U = String |", "missing union member type"},
+		{"repeated member", "type U = String | Integer | String", "union member String is listed more than once"},
+		{"bad member", "type U = String | Integer Float", "unexpected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaSource := "schema \"test\"\n\n" + tt.decl + "\n"
+
+			reg := source.NewRegistry()
+			sourceID := registerSource(t, reg, schemaSource, "test.yammm")
+			collector := diag.NewCollector(0)
+
+			parser := parse.NewParser(sourceID, collector, reg, reg)
+			_ = parser.Parse([]byte(schemaSource))
+
+			result := collector.Result()
+			require.False(t, result.OK(), "expected error")
+			assert.Contains(t, result.Messages()[0], tt.want)
+		})
+	}
+}
+
+func TestParser_TimeRanges(t *testing.T) {
+	schemaSource := `schema "test"
+
+type Past = Timestamp[_, now]
+type Window = Timestamp["2006-01-02 15:04", "2020-01-01 00:00", now + "24h"]
+type Birth = Date["1900-01-01", today - 1]
+type EU = Date["02.01.2006"]
+type Either = Date["2000-01-01", _] | Timestamp[_, now]
+
+type Event {
+	at Timestamp["2000-01-01T00:00:00Z", _] required
+	days List<Date[today, _]>
+	plain Timestamp["2006-01-02"]
+}`
+
+	reg := source.NewRegistry()
+	sourceID := registerSource(t, reg, schemaSource, "test.yammm")
+	collector := diag.NewCollector(0)
+
+	parser := parse.NewParser(sourceID, collector, reg, reg)
+	model := parser.Parse([]byte(schemaSource))
+
+	result := collector.Result()
+	require.True(t, result.OK(), "expected no errors, got: %v", result)
+	require.Len(t, model.DataTypes, 5)
+	assert.Equal(t, "Timestamp[_, now]", model.DataTypes[0].Constraint.String())
+	assert.Equal(t, `Timestamp["2006-01-02 15:04", "2020-01-01 00:00", now + "24h0m0s"]`, model.DataTypes[1].Constraint.String())
+	assert.Equal(t, `Date["1900-01-01", today - 1]`, model.DataTypes[2].Constraint.String())
+	assert.Equal(t, `Date["02.01.2006"]`, model.DataTypes[3].Constraint.String())
+	assert.Equal(t, `Date["2000-01-01", _] | Timestamp[_, now]`, model.DataTypes[4].Constraint.String())
+
+	birth, ok := model.DataTypes[2].Constraint.(schema.DateConstraint)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), birth.Min().Time())
+	assert.True(t, birth.Max().IsRelative())
+	assert.Equal(t, -24*time.Hour, birth.Max().Offset())
+
+	require.Len(t, model.Types, 1)
+	props := model.Types[0].Properties
+	require.Len(t, props, 3)
+	assert.Equal(t, `Timestamp["2000-01-01T00:00:00Z", _]`, props[0].Constraint.String())
+	assert.Equal(t, "List<Date[today, _]>", props[1].Constraint.String())
+	assert.Equal(t, `Timestamp["2006-01-02"]`, props[2].Constraint.String())
+}
+
+func TestParser_TimeRanges_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		decl string
+		want string
+	}{
+		{"unparsable bound", `type T = Date[_, "2020-13-01"]`, "invalid Date bound"},
+		{"bound not in layout", `type T = Timestamp["2006-01-02", "2020-01-01T00:00:00Z", _]`, "invalid Timestamp bound"},
+		{"inverted fixed", `type T = Date["2020-01-01", "2019-01-01"]`, "Date bounds inverted"},
+		{"inverted relative", `type T = Timestamp[now, now - "1h"]`, "Timestamp bounds inverted"},
+		{"wrong clock", `type T = Date[now, _]`, "Date bound must be _, a date string or today"},
+		{"bad duration", `type T = Timestamp[_, now + "1 day"]`, "invalid Timestamp bound offset"},
+		{"days as string", `type T = Date[_, today + "24h"]`, "offset must be a number of days"},
+		{"layout not string", `type T = Date[_]`, "Date layout must be a string"},
+		{"too many", `type T = Date["2006-01-02", _, _, _]`, "parameters must have the form"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaSource := "schema \"test\"\n\n" + tt.decl + "\n"
+
+			reg := source.NewRegistry()
+			sourceID := registerSource(t, reg, schemaSource, "test.yammm")
+			collector := diag.NewCollector(0)
+
+			parser := parse.NewParser(sourceID, collector, reg, reg)
+			_ = parser.Parse([]byte(schemaSource))
+
+			result := collector.Result()
+			require.False(t, result.OK(), "expected error")
+			assert.Contains(t, result.Messages()[0], tt.want)
+		})
+	}
+}
+
+func TestParser_SetTypes(t *testing.T) {
+	schemaSource := `schema "test"
+
+type Tags = Set<String[1, 20]>[_, 10]
+type Lookup = Map<String, Set<Integer>>
+type Codes = Set<String> | Integer
+
+type Item {
+	tags Tags
+	labels Set<String>[1, 5] required
+	matrix List<Set<Integer>>
+	name String
+}`
+
+	reg := source.NewRegistry()
+	sourceID := registerSource(t, reg, schemaSource, "test.yammm")
+	collector := diag.NewCollector(0)
+
+	parser := parse.NewParser(sourceID, collector, reg, reg)
+	model := parser.Parse([]byte(schemaSource))
+
+	result := collector.Result()
+	require.True(t, result.OK(), "expected no errors, got: %v", result)
+	require.Len(t, model.DataTypes, 3)
+	assert.Equal(t, "Set<String[1, 20]>[_, 10]", model.DataTypes[0].Constraint.String())
+	assert.Equal(t, "Map<String, Set<Integer>>", model.DataTypes[1].Constraint.String())
+	assert.Equal(t, "Set<String> | Integer", model.DataTypes[2].Constraint.String())
+
+	require.Len(t, model.Types, 1)
+	props := model.Types[0].Properties
+	require.Len(t, props, 4)
+	labels, ok := props[1].Constraint.(schema.ListConstraint)
+	require.True(t, ok)
+	assert.True(t, labels.IsUnique())
+	assert.Equal(t, "Set<String>[1, 5]", labels.String())
+	assert.Equal(t, "List<Set<Integer>>", props[2].Constraint.String())
+}
+
+func TestParser_SetTypes_Invalid(t *testing.T) {
+	schemaSource := "schema \"test\"\n\ntype T = Set<String>[5, 1]\n"
+
+	reg := source.NewRegistry()
+	sourceID := registerSource(t, reg, schemaSource, "test.yammm")
+	collector := diag.NewCollector(0)
+
+	parser := parse.NewParser(sourceID, collector, reg, reg)
+	_ = parser.Parse([]byte(schemaSource))
+
+	result := collector.Result()
+	require.False(t, result.OK(), "expected error")
+	assert.Contains(t, result.Messages()[0], "set length bounds inverted")
+}
+
 func TestParser_SyntaxError(t *testing.T) {
 	schemaSource := `schema "test"
 
@@ -41,7 +41,7 @@ func NewParser(
 // Parse errors are collected in the provided collector.
 func (p *Parser) Parse(source []byte) *Model {
 	input := antlr.NewInputStream(string(source))
-	lexer := grammar.NewYammmGrammarLexer(input)
+	lexer := grammar.NewModifierLexer(input)
 	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
 	parser := grammar.NewYammmGrammarParser(stream)
 
@@ -62,6 +62,7 @@ func (p *Parser) Parse(source []byte) *Model {
 		sourceID:  p.sourceID,
 		collector: p.collector,
 		spans:     p.spans,
+		tokens:    stream,
 	}
 	antlr.ParseTreeWalkerDefault.Walk(listener, tree)
 
@@ -107,6 +108,7 @@ type astBuilder struct {
 	sourceID  location.SourceID
 	collector *diag.Collector
 	spans     *SpanBuilder
+	tokens    *antlr.CommonTokenStream
 
 	// Current parsing state
 	model          *Model
@@ -212,13 +214,22 @@ func (b *astBuilder) EnterType(ctx *grammar.TypeContext) {
 		Name:          typeName,
 		NameSpan:      nameSpan,
 		IsAbstract:    ctx.GetIs_abstract() != nil,
-		IsPart:        ctx.GetIs_part() != nil,
+		IsPart:        ctx.GetIs_part() != nil || b.isAbstractPart(ctx.GetIs_abstract()),
 		Documentation: doc,
 		Span:          b.spans.FromContext(ctx),
 	}
 	b.currentProps = nil
 }
 
+// isAbstractPart reports whether the 'abstract' token is followed by a
+// 'part' modifier (see grammar.TypeModifierChannel).
+func (b *astBuilder) isAbstractPart(abstract antlr.Token) bool {
+	if abstract == nil {
+		return false
+	}
+	return len(b.tokens.GetHiddenTokensToRight(abstract.GetTokenIndex(), grammar.TypeModifierChannel)) > 0
+}
+
 // ExitType is called when exiting the type production.
 func (b *astBuilder) ExitType(_ *grammar.TypeContext) {
 	if b.model != nil && b.currentType != nil {
@@ -474,15 +485,109 @@ func (b *astBuilder) ExitInvariant(ctx *grammar.InvariantContext) {
 		doc = stripDelimiters(ctx.DOC_COMMENT().GetText())
 	}
 
+	severity, id, ok := b.invariantModifiers(ctx)
+	if !ok {
+		return
+	}
+	messages, ok := b.messageVariants(msgToken)
+	if !ok {
+		return
+	}
+
 	inv := &InvariantDecl{
 		Name:          name,
+		ID:            id,
+		Severity:      severity,
 		Expr:          compiledExpr,
+		Messages:      messages,
 		Documentation: doc,
 		Span:          b.spans.FromContext(ctx),
 	}
 	b.currentType.Invariants = append(b.currentType.Invariants, inv)
 }
 
+// invariantSeverities maps invariant severity keywords to severities.
+var invariantSeverities = map[string]diag.Severity{
+	"error": diag.Error,
+	"warn":  diag.Warning,
+	"info":  diag.Info,
+}
+
+// invariantModifiers returns the severity and identifier written between an
+// invariant's '!' and its message (see grammar.ModifierLexer). A single word
+// is a severity keyword if it names one, otherwise an identifier; with two
+// words the first must be a severity keyword. Returns ok=false after
+// reporting an invalid modifier.
+func (b *astBuilder) invariantModifiers(ctx *grammar.InvariantContext) (diag.Severity, string, bool) {
+	excl := ctx.EXCLAMATION()
+	if excl == nil || b.tokens == nil {
+		return diag.Error, "", true
+	}
+	words := b.tokens.GetHiddenTokensToRight(excl.GetSymbol().GetTokenIndex(), grammar.InvariantModifierChannel)
+
+	severity, id := diag.Error, ""
+	switch len(words) {
+	case 0:
+	case 1:
+		if sev, isSeverity := invariantSeverities[words[0].GetText()]; isSeverity {
+			severity = sev
+		} else {
+			id = words[0].GetText()
+		}
+	default:
+		sev, isSeverity := invariantSeverities[words[0].GetText()]
+		if !isSeverity {
+			b.collector.Collect(diag.NewIssue(diag.Error, diag.E_SYNTAX,
+				fmt.Sprintf("unknown invariant severity %q (expected error, warn, or info)", words[0].GetText())).
+				WithSpan(b.spans.FromToken(words[0])).Build())
+			return diag.Error, "", false
+		}
+		severity, id = sev, words[1].GetText()
+	}
+	if _, reserved := invariantSeverities[id]; reserved {
+		b.collector.Collect(diag.NewIssue(diag.Error, diag.E_SYNTAX,
+			fmt.Sprintf("invariant identifier %q is a reserved severity keyword", id)).
+			WithSpan(b.spans.FromToken(words[1])).Build())
+		return diag.Error, "", false
+	}
+	return severity, id, true
+}
+
+// messageVariants returns the localized messages written after an invariant's
+// message (`@de "..."`, see grammar.MessageVariantChannel), keyed by
+// normalized locale. Returns ok=false after reporting an invalid variant.
+func (b *astBuilder) messageVariants(msg antlr.Token) (map[string]string, bool) {
+	// Tokens conjured by error recovery have no index.
+	if b.tokens == nil || msg.GetTokenIndex() < 0 {
+		return nil, true
+	}
+	toks := b.tokens.GetHiddenTokensToRight(msg.GetTokenIndex(), grammar.MessageVariantChannel)
+
+	var messages map[string]string
+	for i := 0; i+2 < len(toks); i += 3 {
+		word, text := toks[i+1], toks[i+2]
+		locale := diag.NormalizeLocale(word.GetText())
+		message, err := unquoteString(text.GetText())
+		if err != nil {
+			b.collector.Collect(diag.NewIssue(diag.Error, diag.E_SYNTAX,
+				fmt.Sprintf("invalid invariant message: %v", err)).
+				WithSpan(b.spans.FromToken(text)).Build())
+			return nil, false
+		}
+		if _, dup := messages[locale]; dup {
+			b.collector.Collect(diag.NewIssue(diag.Error, diag.E_SYNTAX,
+				fmt.Sprintf("duplicate invariant message for locale %q", locale)).
+				WithSpan(b.spans.FromTokens(toks[i], text)).Build())
+			return nil, false
+		}
+		if messages == nil {
+			messages = make(map[string]string)
+		}
+		messages[locale] = message
+	}
+	return messages, true
+}
+
 // --- Constraint builders ---
 
 // boundSpan returns a span covering a bound value, including the optional
@@ -719,6 +824,11 @@ func (b *astBuilder) ExitPatternT(ctx *grammar.PatternTContext) {
 }
 
 func (b *astBuilder) ExitTimestampT(ctx *grammar.TimestampTContext) {
+	// Ranged timestamps arrive with their parameters hidden (see temporal.go).
+	if body := b.structuredBody(ctx.GetStart()); len(body) > 0 {
+		b.currentDT = b.buildTimeRange(ctx.GetStart(), body, timestampKind)
+		return
+	}
 	if ctx.GetFormat() == nil {
 		b.currentDT = schema.NewTimestampConstraint()
 	} else {
@@ -734,8 +844,28 @@ func (b *astBuilder) ExitTimestampT(ctx *grammar.TimestampTContext) {
 	}
 }
 
-func (b *astBuilder) ExitDateT(_ *grammar.DateTContext) {
-	b.currentDT = schema.NewDateConstraint()
+func (b *astBuilder) ExitDateT(ctx *grammar.DateTContext) {
+	// Structured and union datatypes arrive as retyped 'Date' tokens (see
+	// structured.go); ranged dates with their parameters hidden (see
+	// temporal.go).
+	head := ctx.GetStart()
+	body := b.structuredBody(head)
+	if members, pipes := unionMembers(head, body); members != nil {
+		b.currentDT = b.buildUnion(members, pipes)
+		return
+	}
+	switch head.GetText() {
+	case grammar.MapKeyword:
+		b.currentDT = b.buildMap(head)
+	case grammar.ObjectKeyword:
+		b.currentDT = b.buildObject(head)
+	default:
+		if len(body) > 0 {
+			b.currentDT = b.buildTimeRange(head, body, dateKind)
+			return
+		}
+		b.currentDT = schema.NewDateConstraint()
+	}
 }
 
 func (b *astBuilder) ExitUuidT(_ *grammar.UuidTContext) {
@@ -793,9 +923,17 @@ func (b *astBuilder) ExitListT(ctx *grammar.ListTContext) {
 	// and stored in b.currentDT (post-order traversal).
 	elementConstraint := b.currentDT
 
+	// Sets arrive as 'List' tokens with their own text (see
+	// grammar.SetKeyword).
+	what := "list"
+	unique := ctx.GetStart().GetText() == grammar.SetKeyword
+	if unique {
+		what = "set"
+	}
+
 	if elementConstraint == nil {
 		b.collector.Collect(diag.NewIssue(diag.Error, diag.E_SYNTAX,
-			"list type missing element type").WithSpan(b.spans.FromContext(ctx)).Build())
+			what+" type missing element type").WithSpan(b.spans.FromContext(ctx)).Build())
 		return
 	}
 
@@ -807,12 +945,12 @@ func (b *astBuilder) ExitListT(ctx *grammar.ListTContext) {
 		switch {
 		case err != nil:
 			b.collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_CONSTRAINT,
-				fmt.Sprintf("invalid list length bound: %v", err)).
+				fmt.Sprintf("invalid %s length bound: %v", what, err)).
 				WithSpan(b.spans.FromToken(minToken)).Build())
 			parseErr = true
 		case v < 0:
 			b.collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_CONSTRAINT,
-				fmt.Sprintf("list minimum length cannot be negative: %d", v)).
+				fmt.Sprintf("%s minimum length cannot be negative: %d", what, v)).
 				WithSpan(b.spans.FromToken(minToken)).Build())
 			parseErr = true
 		default:
@@ -824,12 +962,12 @@ func (b *astBuilder) ExitListT(ctx *grammar.ListTContext) {
 		switch {
 		case err != nil:
 			b.collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_CONSTRAINT,
-				fmt.Sprintf("invalid list length bound: %v", err)).
+				fmt.Sprintf("invalid %s length bound: %v", what, err)).
 				WithSpan(b.spans.FromToken(maxToken)).Build())
 			parseErr = true
 		case v < 0:
 			b.collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_CONSTRAINT,
-				fmt.Sprintf("list maximum length cannot be negative: %d", v)).
+				fmt.Sprintf("%s maximum length cannot be negative: %d", what, v)).
 				WithSpan(b.spans.FromToken(maxToken)).Build())
 			parseErr = true
 		default:
@@ -840,10 +978,14 @@ func (b *astBuilder) ExitListT(ctx *grammar.ListTContext) {
 	// Validate min <= max when both are present
 	if !parseErr && minLen >= 0 && maxLen >= 0 && minLen > maxLen {
 		b.collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_CONSTRAINT,
-			fmt.Sprintf("list length bounds inverted: min %d > max %d", minLen, maxLen)).
+			fmt.Sprintf("%s length bounds inverted: min %d > max %d", what, minLen, maxLen)).
 			WithSpan(b.spans.FromContext(ctx)).Build())
 	}
 
+	if unique {
+		b.currentDT = schema.NewSetConstraintBounded(elementConstraint, minLen, maxLen)
+		return
+	}
 	b.currentDT = schema.NewListConstraintBounded(elementConstraint, minLen, maxLen)
 }
 
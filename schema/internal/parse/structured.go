@@ -0,0 +1,276 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/antlr4-go/antlr/v4"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/internal/grammar"
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema"
+)
+
+// Structured datatypes (Map and Object) and unions are not part of the
+// generated grammar. grammar.ModifierLexer parses their head as a dateT and
+// moves the body to grammar.StructuredTypeChannel; the builders below split
+// the body and parse each part with a nested parser over the replayed tokens.
+
+// structuredBody returns the body tokens following a structured type head.
+func (b *astBuilder) structuredBody(head antlr.Token) []antlr.Token {
+	return b.tokens.GetHiddenTokensToRight(head.GetTokenIndex(), grammar.StructuredTypeChannel)
+}
+
+// unionMembers splits a union type at its top-level '|' tokens. The first
+// member starts with head. Returns nil if body is not the tail of a union.
+func unionMembers(head antlr.Token, body []antlr.Token) (members [][]antlr.Token, pipes []antlr.Token) {
+	toks := append([]antlr.Token{head}, body...)
+	depth, start := 0, 0
+	for i, tok := range toks {
+		switch tok.GetTokenType() {
+		case grammar.YammmGrammarLexerLT, grammar.YammmGrammarLexerLBRACE, grammar.YammmGrammarLexerLBRACK:
+			depth++
+		case grammar.YammmGrammarLexerGT, grammar.YammmGrammarLexerRBRACE, grammar.YammmGrammarLexerRBRACK:
+			depth--
+		case grammar.YammmGrammarLexerPIPE:
+			if depth == 0 {
+				members = append(members, toks[start:i])
+				pipes = append(pipes, tok)
+				start = i + 1
+			}
+		}
+	}
+	if len(pipes) == 0 {
+		return nil, nil
+	}
+	return append(members, toks[start:]), pipes
+}
+
+// buildUnion builds a UnionConstraint from `A | B | ...`.
+func (b *astBuilder) buildUnion(members [][]antlr.Token, pipes []antlr.Token) schema.Constraint {
+	result := make([]schema.Constraint, 0, len(members))
+	seen := make(map[string]bool, len(members))
+	ok := true
+	for i, toks := range members {
+		// An empty member is reported at the '|' next to it.
+		end := pipes[min(i, len(pipes)-1)]
+		c, _ := b.parseNestedType(toks, end, "union member")
+		if c == nil {
+			ok = false
+			continue
+		}
+		if seen[c.String()] {
+			b.collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_CONSTRAINT,
+				fmt.Sprintf("union member %s is listed more than once", c)).
+				WithSpan(b.spans.FromTokens(toks[0], toks[len(toks)-1])).Build())
+			ok = false
+			continue
+		}
+		seen[c.String()] = true
+		result = append(result, c)
+	}
+	if !ok {
+		return nil
+	}
+	return schema.NewUnionConstraint(result...)
+}
+
+// buildMap builds a MapConstraint from `Map<K, V>[min, max]`.
+func (b *astBuilder) buildMap(head antlr.Token) schema.Constraint {
+	body := b.structuredBody(head)
+	if len(body) == 0 {
+		b.structuredError(head, head, "map type requires key and value types: Map<K, V>")
+		return nil
+	}
+
+	// body is '<' K ',' V '>' followed by optional size bounds.
+	depth := 0
+	comma, closing := -1, -1
+	for i := 1; i < len(body) && closing < 0; i++ {
+		switch body[i].GetTokenType() {
+		case grammar.YammmGrammarLexerLT, grammar.YammmGrammarLexerLBRACE, grammar.YammmGrammarLexerLBRACK:
+			depth++
+		case grammar.YammmGrammarLexerGT, grammar.YammmGrammarLexerRBRACE, grammar.YammmGrammarLexerRBRACK:
+			if depth == 0 {
+				closing = i
+				continue
+			}
+			depth--
+		case grammar.YammmGrammarLexerCOMMA:
+			if depth == 0 && comma < 0 {
+				comma = i
+			}
+		}
+	}
+	if comma < 0 || closing < 0 {
+		b.structuredError(head, body[len(body)-1], "map type requires key and value types: Map<K, V>")
+		return nil
+	}
+	key, value, bounds := body[1:comma], body[comma+1:closing], body[closing+1:]
+
+	keyDT, _ := b.parseNestedType(key, body[comma], "map key")
+	valueDT, valueRef := b.parseNestedType(value, body[closing], "map value")
+	minLen, maxLen, ok := b.mapBounds(bounds)
+	if keyDT == nil || valueDT == nil || !ok {
+		return nil
+	}
+	if minLen >= 0 && maxLen >= 0 && minLen > maxLen {
+		b.collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_CONSTRAINT,
+			fmt.Sprintf("map size bounds inverted: min %d > max %d", minLen, maxLen)).
+			WithSpan(b.spans.FromTokens(head, body[len(body)-1])).Build())
+	}
+
+	// Like List elements, an aliased value type is the property's data type
+	// reference for navigation.
+	b.currentDTRef = valueRef
+	return schema.NewMapConstraintBounded(keyDT, valueDT, minLen, maxLen)
+}
+
+// mapBounds parses the optional `[min, max]` size bounds of a map type.
+// Returns -1 for absent bounds and false if the bounds are malformed.
+func (b *astBuilder) mapBounds(toks []antlr.Token) (minLen, maxLen int64, ok bool) {
+	if len(toks) == 0 {
+		return -1, -1, true
+	}
+	if len(toks) != 5 ||
+		toks[0].GetTokenType() != grammar.YammmGrammarLexerLBRACK ||
+		toks[2].GetTokenType() != grammar.YammmGrammarLexerCOMMA ||
+		toks[4].GetTokenType() != grammar.YammmGrammarLexerRBRACK {
+		b.structuredError(toks[0], toks[len(toks)-1], "map size bounds must have the form [min, max]")
+		return 0, 0, false
+	}
+
+	ok = true
+	bound := func(tok antlr.Token) int64 {
+		switch tok.GetTokenType() {
+		case grammar.YammmGrammarLexerUSCORE:
+			return -1
+		case grammar.YammmGrammarLexerINTEGER:
+			v, err := strconv.ParseInt(tok.GetText(), 10, 64)
+			if err == nil {
+				return v
+			}
+			b.collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_CONSTRAINT,
+				fmt.Sprintf("invalid map size bound: %v", err)).
+				WithSpan(b.spans.FromToken(tok)).Build())
+		default:
+			b.structuredError(tok, tok, "map size bound must be a non-negative integer or _")
+		}
+		ok = false
+		return -1
+	}
+	minLen, maxLen = bound(toks[1]), bound(toks[3])
+	return minLen, maxLen, ok
+}
+
+// buildObject builds an ObjectConstraint from `Object { name Type required, ... }`.
+// Fields are separated by commas or newlines.
+func (b *astBuilder) buildObject(head antlr.Token) schema.Constraint {
+	body := b.structuredBody(head)
+	if len(body) < 2 {
+		b.structuredError(head, head, "object type requires a field list: Object { ... }")
+		return nil
+	}
+
+	// Drop the field separators; nested bodies keep theirs.
+	var fields []antlr.Token
+	depth := 0
+	for _, tok := range body[1 : len(body)-1] {
+		switch tok.GetTokenType() {
+		case grammar.YammmGrammarLexerLT, grammar.YammmGrammarLexerLBRACE, grammar.YammmGrammarLexerLBRACK:
+			depth++
+		case grammar.YammmGrammarLexerGT, grammar.YammmGrammarLexerRBRACE, grammar.YammmGrammarLexerRBRACK:
+			depth--
+		case grammar.YammmGrammarLexerCOMMA:
+			if depth == 0 {
+				continue
+			}
+		}
+		fields = append(fields, tok)
+	}
+	if len(fields) == 0 {
+		b.structuredError(head, body[len(body)-1], "object type must declare at least one field")
+		return nil
+	}
+
+	nested := b.parseNested(fields, func(p *grammar.YammmGrammarParser) antlr.ParserRuleContext {
+		return p.Relation_body()
+	})
+
+	result := make([]schema.ObjectField, 0, len(nested.currentProps))
+	seen := make(map[string]*PropertyDecl, len(nested.currentProps))
+	ok := true
+	for _, pd := range nested.currentProps {
+		if pd.Constraint == nil {
+			ok = false
+			continue
+		}
+		if first, dup := seen[pd.Name]; dup {
+			b.collector.Collect(diag.NewIssue(diag.Error, diag.E_DUPLICATE_PROPERTY,
+				fmt.Sprintf("object field %q is defined multiple times", pd.Name)).
+				WithSpan(pd.Span).
+				WithRelated(location.RelatedInfo{Span: first.Span, Message: "first defined here"}).
+				Build())
+			ok = false
+			continue
+		}
+		seen[pd.Name] = pd
+		result = append(result, schema.NewObjectField(pd.Name, pd.Constraint, !pd.Optional))
+	}
+	if !ok || len(result) == 0 {
+		return nil
+	}
+	return schema.NewObjectConstraint(result)
+}
+
+// parseNestedType parses toks as a single data type reference. end is the
+// token following toks, used to place the error when toks is empty.
+func (b *astBuilder) parseNestedType(toks []antlr.Token, end antlr.Token, what string) (schema.Constraint, schema.DataTypeRef) {
+	if len(toks) == 0 {
+		b.structuredError(end, end, "missing "+what+" type")
+		return nil, schema.DataTypeRef{}
+	}
+	nested := b.parseNested(toks, func(p *grammar.YammmGrammarParser) antlr.ParserRuleContext {
+		return p.Data_type_ref()
+	})
+	return nested.currentDT, nested.currentDTRef
+}
+
+// parseNested parses replayed tokens with rule and walks the result with a
+// fresh builder sharing this builder's diagnostics. Tokens left over after
+// the rule are reported as a syntax error.
+func (b *astBuilder) parseNested(
+	toks []antlr.Token,
+	rule func(*grammar.YammmGrammarParser) antlr.ParserRuleContext,
+) *astBuilder {
+	stream := antlr.NewCommonTokenStream(grammar.NewReplayLexer(toks), antlr.TokenDefaultChannel)
+	p := grammar.NewYammmGrammarParser(stream)
+	p.RemoveErrorListeners()
+	p.AddErrorListener(&errorListener{
+		collector: b.collector,
+		sourceID:  b.sourceID,
+		spans:     b.spans,
+	})
+
+	tree := rule(p)
+	if extra := stream.LT(1); extra.GetTokenType() != antlr.TokenEOF {
+		b.structuredError(extra, extra, fmt.Sprintf("unexpected %q in type", extra.GetText()))
+	}
+
+	nested := &astBuilder{
+		parser:    b.parser,
+		sourceID:  b.sourceID,
+		collector: b.collector,
+		spans:     b.spans,
+		tokens:    stream,
+	}
+	antlr.ParseTreeWalkerDefault.Walk(nested, tree)
+	return nested
+}
+
+// structuredError reports a syntax error spanning start..stop.
+func (b *astBuilder) structuredError(start, stop antlr.Token, msg string) {
+	b.collector.Collect(diag.NewIssue(diag.Error, diag.E_SYNTAX, msg).
+		WithSpan(b.spans.FromTokens(start, stop)).Build())
+}
@@ -0,0 +1,209 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/antlr4-go/antlr/v4"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/internal/grammar"
+	"github.com/simon-lentz/yammm/schema"
+)
+
+// Ranged Timestamp and Date types (`Timestamp[min, max]`,
+// `Date["layout", min, max]`) are not part of the generated grammar either.
+// grammar.ModifierLexer moves their bracketed parameters to
+// grammar.StructuredTypeChannel; the builder below parses them by hand.
+
+// Relative bound keywords for Timestamp and Date ranges.
+const (
+	nowKeyword   = "now"
+	todayKeyword = "today"
+)
+
+// timeKind describes the Timestamp or Date type being built.
+type timeKind struct {
+	name   string // type keyword, for messages
+	clock  string // relative bound keyword
+	layout string // default layout
+	date   bool   // offsets are whole days
+}
+
+var (
+	timestampKind = timeKind{name: "Timestamp", clock: nowKeyword, layout: schema.DefaultTimestampLayout}
+	dateKind      = timeKind{name: "Date", clock: todayKeyword, layout: schema.DefaultDateLayout, date: true}
+)
+
+// buildTimeRange builds a Timestamp or Date constraint from the parameters
+// following head: `[layout]`, `[min, max]` or `[layout, min, max]`.
+func (b *astBuilder) buildTimeRange(head antlr.Token, body []antlr.Token, kind timeKind) schema.Constraint {
+	// body is '[' params ']'.
+	var args [][]antlr.Token
+	start := 1
+	for i := 1; i < len(body); i++ {
+		if tt := body[i].GetTokenType(); tt == grammar.YammmGrammarLexerCOMMA || tt == grammar.YammmGrammarLexerRBRACK {
+			args = append(args, body[start:i])
+			start = i + 1
+		}
+	}
+	if len(args) == 0 || len(args) > 3 {
+		b.structuredError(head, body[len(body)-1],
+			fmt.Sprintf("%s parameters must have the form [layout], [min, max] or [layout, min, max]", kind.name))
+		return nil
+	}
+
+	format := ""
+	if len(args) != 2 {
+		var ok bool
+		if format, ok = b.timeLayout(args[0], body[0], kind); !ok {
+			return nil
+		}
+		args = args[1:]
+	}
+	layout := kind.layout
+	if format != "" {
+		layout = format
+	}
+
+	minB, maxB := schema.NoTimeBound(), schema.NoTimeBound()
+	if len(args) == 2 {
+		var minOK, maxOK bool
+		minB, minOK = b.timeBound(args[0], body[0], kind, layout)
+		maxB, maxOK = b.timeBound(args[1], body[len(body)-1], kind, layout)
+		if !minOK || !maxOK {
+			return nil
+		}
+		if inverted(minB, maxB) {
+			b.collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_CONSTRAINT,
+				fmt.Sprintf("%s bounds inverted: min is after max", kind.name)).
+				WithSpan(b.spans.FromTokens(head, body[len(body)-1])).Build())
+			return nil
+		}
+	}
+
+	if kind.date {
+		return schema.NewDateConstraintBounded(format, minB, maxB)
+	}
+	return schema.NewTimestampConstraintBounded(format, minB, maxB)
+}
+
+// timeLayout parses the layout parameter of a Timestamp or Date type.
+func (b *astBuilder) timeLayout(toks []antlr.Token, end antlr.Token, kind timeKind) (string, bool) {
+	if len(toks) != 1 || toks[0].GetTokenType() != grammar.YammmGrammarLexerSTRING {
+		b.structuredError(firstOr(toks, end), lastOr(toks, end), kind.name+" layout must be a string")
+		return "", false
+	}
+	layout, err := unquoteString(toks[0].GetText())
+	if err != nil {
+		b.structuredError(toks[0], toks[0], fmt.Sprintf("invalid %s layout: %v", kind.name, err))
+		return "", false
+	}
+	return layout, true
+}
+
+// timeBound parses one bound of a Timestamp or Date range: `_`, a string in
+// layout, or the clock keyword optionally shifted by an offset (a duration
+// string for Timestamp, a number of days for Date).
+func (b *astBuilder) timeBound(toks []antlr.Token, end antlr.Token, kind timeKind, layout string) (schema.TimeBound, bool) {
+	switch {
+	case len(toks) == 1 && toks[0].GetTokenType() == grammar.YammmGrammarLexerUSCORE:
+		return schema.NoTimeBound(), true
+	case len(toks) == 1 && toks[0].GetTokenType() == grammar.YammmGrammarLexerSTRING:
+		text, err := unquoteString(toks[0].GetText())
+		if err == nil {
+			var t time.Time
+			if t, err = time.Parse(layout, text); err == nil {
+				return schema.TimeBoundAt(t), true
+			}
+		}
+		b.collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_CONSTRAINT,
+			fmt.Sprintf("invalid %s bound %s: %v", kind.name, toks[0].GetText(), err)).
+			WithSpan(b.spans.FromToken(toks[0])).Build())
+		return schema.TimeBound{}, false
+	case len(toks) > 0 && toks[0].GetTokenType() == grammar.YammmGrammarLexerLC_WORD && toks[0].GetText() == kind.clock:
+		if len(toks) == 1 {
+			return schema.TimeBoundRelative(0), true
+		}
+		if offset, ok := b.timeOffset(toks[1:], kind); ok {
+			return schema.TimeBoundRelative(offset), true
+		}
+		return schema.TimeBound{}, false
+	}
+	what := "a date string"
+	if !kind.date {
+		what = "a timestamp string"
+	}
+	b.structuredError(firstOr(toks, end), lastOr(toks, end),
+		fmt.Sprintf("%s bound must be _, %s or %s", kind.name, what, kind.clock))
+	return schema.TimeBound{}, false
+}
+
+// timeOffset parses the `+ offset` or `- offset` following a clock keyword.
+func (b *astBuilder) timeOffset(toks []antlr.Token, kind timeKind) (time.Duration, bool) {
+	sign := time.Duration(0)
+	if len(toks) == 2 {
+		switch toks[0].GetTokenType() {
+		case grammar.YammmGrammarLexerPLUS:
+			sign = 1
+		case grammar.YammmGrammarLexerMINUS:
+			sign = -1
+		}
+	}
+
+	var offset time.Duration
+	var err error
+	switch {
+	case sign == 0:
+		err = fmt.Errorf("expected %s + offset or %s - offset", kind.clock, kind.clock)
+	case kind.date && toks[1].GetTokenType() == grammar.YammmGrammarLexerINTEGER:
+		var days int64
+		if days, err = strconv.ParseInt(toks[1].GetText(), 10, 32); err == nil {
+			offset = time.Duration(days) * 24 * time.Hour
+		}
+	case kind.date:
+		err = fmt.Errorf("%s offset must be a number of days", kind.clock)
+	case toks[1].GetTokenType() == grammar.YammmGrammarLexerSTRING:
+		var text string
+		if text, err = unquoteString(toks[1].GetText()); err == nil {
+			offset, err = time.ParseDuration(text)
+		}
+	default:
+		err = fmt.Errorf("%s offset must be a duration string such as \"24h\"", kind.clock)
+	}
+	if err != nil {
+		b.collector.Collect(diag.NewIssue(diag.Error, diag.E_INVALID_CONSTRAINT,
+			fmt.Sprintf("invalid %s bound offset: %v", kind.name, err)).
+			WithSpan(b.spans.FromTokens(toks[0], toks[len(toks)-1])).Build())
+		return 0, false
+	}
+	return sign * offset, true
+}
+
+// inverted reports whether comparable bounds have min after max.
+func inverted(minB, maxB schema.TimeBound) bool {
+	if !minB.IsSet() || !maxB.IsSet() || minB.IsRelative() != maxB.IsRelative() {
+		return false
+	}
+	if minB.IsRelative() {
+		return minB.Offset() > maxB.Offset()
+	}
+	return minB.Time().After(maxB.Time())
+}
+
+// firstOr returns the first token of toks, or def if toks is empty.
+func firstOr(toks []antlr.Token, def antlr.Token) antlr.Token {
+	if len(toks) == 0 {
+		return def
+	}
+	return toks[0]
+}
+
+// lastOr returns the last token of toks, or def if toks is empty.
+func lastOr(toks []antlr.Token, def antlr.Token) antlr.Token {
+	if len(toks) == 0 {
+		return def
+	}
+	return toks[len(toks)-1]
+}
@@ -1,6 +1,10 @@
 package schema
 
 import (
+	"maps"
+	"slices"
+
+	"github.com/simon-lentz/yammm/diag"
 	"github.com/simon-lentz/yammm/location"
 	"github.com/simon-lentz/yammm/schema/expr"
 )
@@ -8,11 +12,18 @@ import (
 // Invariant represents a constraint expression attached to a type.
 // Invariants are validated at runtime; the expression is compiled at schema
 // load time and evaluated at instance validation time.
+//
+// An invariant declared with `!warn` or `!info` reports failures at that
+// severity instead of rejecting the instance.
 type Invariant struct {
-	name string          // user-facing message shown when invariant fails
-	expr expr.Expression // compiled expression
-	span location.Span   // source location
-	doc  string          // documentation comment
+	name     string          // user-facing message shown when invariant fails
+	id       string          // explicit identifier (empty when not declared)
+	severity diag.Severity   // failure severity
+	expr     expr.Expression // compiled expression
+	span     location.Span   // source location
+	doc      string          // documentation comment
+
+	messages map[string]string // localized messages by normalized locale
 }
 
 // NewInvariant creates a new Invariant.
@@ -22,12 +33,39 @@ type Invariant struct {
 //   - Advanced use cases like building schemas programmatically via Builder
 //
 // Most users should load schemas from .yammm files using the load package.
+//
+// The invariant has no explicit identifier and Error severity; see SetID and
+// SetSeverity.
 func NewInvariant(name string, e expr.Expression, span location.Span, doc string) *Invariant {
 	return &Invariant{
-		name: name,
-		expr: e,
-		span: span,
-		doc:  doc,
+		name:     name,
+		severity: diag.Error,
+		expr:     e,
+		span:     span,
+		doc:      doc,
+	}
+}
+
+// SetID sets the explicit identifier (called during completion).
+func (i *Invariant) SetID(id string) {
+	i.id = id
+}
+
+// SetSeverity sets the failure severity (called during completion).
+func (i *Invariant) SetSeverity(severity diag.Severity) {
+	i.severity = severity
+}
+
+// SetLocalizedMessages sets the localized variants of the message, keyed by
+// locale (called during completion). Locales are normalized with
+// diag.NormalizeLocale.
+func (i *Invariant) SetLocalizedMessages(messages map[string]string) {
+	i.messages = nil
+	for locale, msg := range messages {
+		if i.messages == nil {
+			i.messages = make(map[string]string, len(messages))
+		}
+		i.messages[diag.NormalizeLocale(locale)] = msg
 	}
 }
 
@@ -37,6 +75,45 @@ func (i *Invariant) Name() string {
 	return i.name
 }
 
+// LocalizedMessage returns the variant of the message for locale, written
+// after the message in the schema (`! "too young" @de "zu jung" ...`).
+// The locale must match exactly after normalization; renderers fall back
+// from regional locales to their language (see diag.WithLocale).
+func (i *Invariant) LocalizedMessage(locale string) (string, bool) {
+	msg, ok := i.messages[diag.NormalizeLocale(locale)]
+	return msg, ok
+}
+
+// MessageLocales returns the normalized locales the message has variants
+// for, in lexicographic order.
+func (i *Invariant) MessageLocales() []string {
+	return slices.Sorted(maps.Keys(i.messages))
+}
+
+// ID returns the stable identifier of this invariant: the identifier written
+// after the severity keyword (`!warn email_format "..."`), or the message when
+// none was declared. Declare an identifier when the message may change.
+//
+// IDs are unique among a type's invariants; a subtype invariant with the same
+// ID as an inherited one overrides it.
+func (i *Invariant) ID() string {
+	if i.id != "" {
+		return i.id
+	}
+	return i.name
+}
+
+// HasExplicitID reports whether the invariant declares an identifier.
+func (i *Invariant) HasExplicitID() bool {
+	return i.id != ""
+}
+
+// Severity returns the severity of the diagnostic reported when the invariant
+// fails. Only Error (the default) and Fatal failures reject the instance.
+func (i *Invariant) Severity() diag.Severity {
+	return i.severity
+}
+
 // Expression returns the compiled expression for this invariant.
 func (i *Invariant) Expression() expr.Expression {
 	return i.expr
@@ -0,0 +1,45 @@
+// Package lint checks loaded schemas for style and hygiene problems that are
+// not errors: unused declarations, naming conventions, and constraints that
+// are likely to be mistakes.
+//
+// # Basic Usage
+//
+//	linter, err := lint.New(lint.WithDisabledRules(lint.RuleMissingDocumentation))
+//	if err != nil {
+//	    return err
+//	}
+//	result := linter.Lint(s)
+//	for issue := range result.Issues() {
+//	    fmt.Println(issue)
+//	}
+//
+// To lint every schema as it loads, including imports, pass the linter to
+// the loader with load.WithLinter.
+//
+// # Rules
+//
+// Each rule reports findings with its own warning code:
+//
+//	unused-import             W_UNUSED_IMPORT             (Warning)
+//	unused-datatype           W_UNUSED_DATATYPE           (Warning)
+//	abstract-without-subtypes W_ABSTRACT_WITHOUT_SUBTYPES (Info)
+//	missing-documentation     W_MISSING_DOCUMENTATION     (Hint)
+//	relation-name-case        W_RELATION_NAME_CASE        (Warning)
+//	enum-value-case           W_ENUM_VALUE_CASE           (Info)
+//	unanchored-pattern        W_UNANCHORED_PATTERN        (Warning)
+//
+// [Rules] lists the rules with their descriptions. Every finding carries the
+// rule name under [DetailKeyRule]. Unused import findings also carry a fix
+// that removes the import line.
+//
+// # Configuration
+//
+// All rules run by default. [WithRules] restricts the linter to a set of
+// rules, [WithDisabledRules] turns rules off, and [WithRuleSeverity] changes
+// the severity of a rule's findings. Severities are limited to Warning, Info,
+// and Hint, so linting never makes a schema fail to load.
+//
+// # Thread Safety
+//
+// A [Linter] is immutable after [New] and may be shared across goroutines.
+package lint
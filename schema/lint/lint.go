@@ -0,0 +1,513 @@
+package lint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema"
+)
+
+// ErrUnknownRule is returned by New when an option names a rule that does
+// not exist.
+var ErrUnknownRule = errors.New("unknown lint rule")
+
+// ErrInvalidSeverity is returned by New when WithRuleSeverity is given a
+// failing severity. Lint findings never make a schema fail to load.
+var ErrInvalidSeverity = errors.New("lint severity must be warning, info or hint")
+
+// DetailKeyRule is the name of the rule that produced a finding.
+const DetailKeyRule = "rule"
+
+// Rule names.
+const (
+	RuleUnusedImport            = "unused-import"
+	RuleUnusedDataType          = "unused-datatype"
+	RuleAbstractWithoutSubtypes = "abstract-without-subtypes"
+	RuleMissingDocumentation    = "missing-documentation"
+	RuleRelationNameCase        = "relation-name-case"
+	RuleEnumValueCase           = "enum-value-case"
+	RuleUnanchoredPattern       = "unanchored-pattern"
+)
+
+// Rule describes a lint rule.
+type Rule struct {
+	// Name identifies the rule in options, e.g. "unused-import".
+	Name string
+
+	// Code is the diagnostic code of the rule's findings.
+	Code diag.Code
+
+	// Severity is the severity of the rule's findings.
+	Severity diag.Severity
+
+	// Description is a one-line summary of what the rule reports.
+	Description string
+
+	check func(*run, Rule)
+}
+
+// rules is the registry of all rules, in reporting order.
+var rules = []Rule{
+	{
+		Name: RuleUnusedImport, Code: diag.W_UNUSED_IMPORT, Severity: diag.Warning,
+		Description: "import whose alias is never referenced",
+		check:       (*run).unusedImports,
+	},
+	{
+		Name: RuleUnusedDataType, Code: diag.W_UNUSED_DATATYPE, Severity: diag.Warning,
+		Description: "datatype alias not referenced by any type or datatype of the schema",
+		check:       (*run).unusedDataTypes,
+	},
+	{
+		Name: RuleAbstractWithoutSubtypes, Code: diag.W_ABSTRACT_WITHOUT_SUBTYPES, Severity: diag.Info,
+		Description: "abstract type that no type of the schema extends",
+		check:       (*run).abstractWithoutSubtypes,
+	},
+	{
+		Name: RuleMissingDocumentation, Code: diag.W_MISSING_DOCUMENTATION, Severity: diag.Hint,
+		Description: "type other than a part type without a doc comment",
+		check:       (*run).missingDocumentation,
+	},
+	{
+		Name: RuleRelationNameCase, Code: diag.W_RELATION_NAME_CASE, Severity: diag.Warning,
+		Description: "relation or reverse relation name that is not UPPER_SNAKE_CASE",
+		check:       (*run).relationNameCase,
+	},
+	{
+		Name: RuleEnumValueCase, Code: diag.W_ENUM_VALUE_CASE, Severity: diag.Info,
+		Description: "enum mixing values that start lower-case with values that start upper-case",
+		check:       (*run).enumValueCase,
+	},
+	{
+		Name: RuleUnanchoredPattern, Code: diag.W_UNANCHORED_PATTERN, Severity: diag.Warning,
+		Description: "Pattern regex without a leading ^ or a trailing $",
+		check:       (*run).unanchoredPatterns,
+	},
+}
+
+// Rules returns all rules with their default configuration.
+func Rules() []Rule {
+	return slices.Clone(rules)
+}
+
+// Option configures a Linter.
+type Option func(*config)
+
+type config struct {
+	only       []string
+	disabled   []string
+	severities map[string]diag.Severity
+}
+
+// WithRules restricts the linter to the named rules. Later calls add to the
+// set. Without WithRules, all rules run.
+func WithRules(names ...string) Option {
+	return func(c *config) {
+		c.only = append(c.only, names...)
+	}
+}
+
+// WithDisabledRules turns off the named rules. Disabling takes precedence
+// over WithRules.
+func WithDisabledRules(names ...string) Option {
+	return func(c *config) {
+		c.disabled = append(c.disabled, names...)
+	}
+}
+
+// WithRuleSeverity overrides the severity of a rule's findings. The
+// severity must be Warning, Info, or Hint.
+func WithRuleSeverity(name string, severity diag.Severity) Option {
+	return func(c *config) {
+		if c.severities == nil {
+			c.severities = make(map[string]diag.Severity)
+		}
+		c.severities[name] = severity
+	}
+}
+
+// Linter checks schemas against a configured set of rules.
+//
+// A Linter is immutable after New and safe for concurrent use.
+type Linter struct {
+	rules []Rule
+}
+
+// New creates a linter. All rules are enabled at their default severity
+// unless options say otherwise.
+//
+// Returns an error wrapping ErrUnknownRule if an option names an unknown
+// rule, or ErrInvalidSeverity if a severity override is Error or Fatal.
+func New(opts ...Option) (*Linter, error) {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	known := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		known[r.Name] = true
+	}
+	for _, name := range slices.Concat(cfg.only, cfg.disabled, mapKeys(cfg.severities)) {
+		if !known[name] {
+			return nil, fmt.Errorf("%w %q", ErrUnknownRule, name)
+		}
+	}
+	for _, name := range mapKeys(cfg.severities) {
+		if sev := cfg.severities[name]; sev.IsMoreSevereThan(diag.Warning) || diag.Hint.IsMoreSevereThan(sev) {
+			return nil, fmt.Errorf("%w: rule %q: %s", ErrInvalidSeverity, name, sev)
+		}
+	}
+
+	l := &Linter{}
+	for _, r := range rules {
+		if len(cfg.only) > 0 && !slices.Contains(cfg.only, r.Name) {
+			continue
+		}
+		if slices.Contains(cfg.disabled, r.Name) {
+			continue
+		}
+		if sev, ok := cfg.severities[r.Name]; ok {
+			r.Severity = sev
+		}
+		l.rules = append(l.rules, r)
+	}
+	return l, nil
+}
+
+// Rules returns the rules this linter runs, with their effective severity.
+func (l *Linter) Rules() []Rule {
+	return slices.Clone(l.rules)
+}
+
+// Lint checks a schema and reports findings as a diag.Result.
+//
+// Only the schema's own declarations are checked; imported schemas are not
+// traversed. Usage-based rules (unused datatypes, abstract types without
+// subtypes) only see references from within the schema, so library schemas
+// meant to be imported may want to disable them.
+//
+// A nil linter or schema yields an empty result.
+func (l *Linter) Lint(s *schema.Schema) diag.Result {
+	if l == nil || s == nil {
+		return diag.OK()
+	}
+	r := &run{schema: s, collector: diag.NewCollector(0)}
+	for _, rule := range l.rules {
+		rule.check(r, rule)
+	}
+	return r.collector.Result()
+}
+
+// run holds the state for a single Lint call.
+type run struct {
+	schema    *schema.Schema
+	collector *diag.Collector
+	refs      *references // computed on first use
+}
+
+// report records a finding of rule at span.
+func (r *run) report(rule Rule, span location.Span, message string, details ...diag.Detail) {
+	r.collector.Collect(r.issue(rule, span, message, details...).Build())
+}
+
+// issue starts building a finding of rule at span.
+func (r *run) issue(rule Rule, span location.Span, message string, details ...diag.Detail) *diag.IssueBuilder {
+	b := diag.NewIssue(rule.Severity, rule.Code, message).
+		WithDetail(DetailKeyRule, rule.Name).
+		WithDetails(details...)
+	if !span.IsZero() {
+		b = b.WithSpan(span)
+	}
+	return b
+}
+
+// references records the import aliases and local datatypes a schema refers to.
+type references struct {
+	imports   map[string]bool
+	dataTypes map[string]bool
+}
+
+// references returns the import aliases and local datatype names referenced
+// from the schema's types and datatypes.
+func (r *run) references() *references {
+	if r.refs != nil {
+		return r.refs
+	}
+	refs := &references{imports: make(map[string]bool), dataTypes: make(map[string]bool)}
+	r.refs = refs
+	typeRef := func(ref schema.TypeRef) {
+		if ref.Qualifier() != "" {
+			refs.imports[ref.Qualifier()] = true
+		}
+	}
+	props := func(props []*schema.Property) {
+		for _, p := range props {
+			refs.constraint(p.Constraint())
+		}
+	}
+	for _, t := range r.schema.TypesSlice() {
+		for _, ref := range t.InheritsSlice() {
+			typeRef(ref)
+		}
+		props(t.PropertiesSlice())
+		for _, rel := range slices.Concat(t.AssociationsSlice(), t.CompositionsSlice()) {
+			typeRef(rel.Target())
+			props(rel.PropertiesSlice())
+		}
+	}
+	for _, dt := range r.schema.DataTypesSlice() {
+		refs.constraint(dt.Constraint())
+	}
+	return refs
+}
+
+// constraint records the references of c and its nested constraints.
+// Aliases of local datatypes are followed, since their resolved chain names
+// further local datatypes; qualified aliases name another schema's datatypes
+// and are not followed.
+func (refs *references) constraint(c schema.Constraint) {
+	walkConstraint(c, func(c schema.Constraint) bool {
+		alias, ok := c.(schema.AliasConstraint)
+		if !ok {
+			return true
+		}
+		if qualifier, _, found := strings.Cut(alias.DataTypeName(), "."); found {
+			refs.imports[qualifier] = true
+			return false
+		}
+		refs.dataTypes[alias.DataTypeName()] = true
+		if alias.Resolved() != nil {
+			refs.constraint(alias.Resolved())
+		}
+		return false
+	})
+}
+
+// walkConstraint calls visit for c and, while visit returns true, for the
+// constraints nested in lists, maps, objects, and unions.
+func walkConstraint(c schema.Constraint, visit func(schema.Constraint) bool) {
+	if c == nil || !visit(c) {
+		return
+	}
+	switch c := c.(type) {
+	case schema.ListConstraint:
+		walkConstraint(c.Element(), visit)
+	case schema.MapConstraint:
+		walkConstraint(c.Key(), visit)
+		walkConstraint(c.Value(), visit)
+	case schema.ObjectConstraint:
+		for _, f := range c.Fields() {
+			walkConstraint(f.Constraint(), visit)
+		}
+	case schema.UnionConstraint:
+		for _, m := range c.Members() {
+			walkConstraint(m, visit)
+		}
+	}
+}
+
+func (r *run) unusedImports(rule Rule) {
+	refs := r.references()
+	for _, imp := range r.schema.ImportsSlice() {
+		if refs.imports[imp.Alias()] {
+			continue
+		}
+		b := r.issue(rule, imp.Span(),
+			fmt.Sprintf("import %q as %s is never used", imp.Path(), imp.Alias()),
+			diag.Detail{Key: diag.DetailKeyImportPath, Value: imp.Path()},
+			diag.Detail{Key: diag.DetailKeyAlias, Value: imp.Alias()})
+		if span := r.lineRemovalSpan(imp.Span()); !span.IsZero() {
+			b = b.WithFix("Remove unused import", diag.TextEdit{Span: span})
+		}
+		r.collector.Collect(b.Build())
+	}
+}
+
+// lineRemovalSpan returns the span that removes the declaration at span:
+// its whole line, including the line break, when nothing else is on the
+// line, or the declaration alone otherwise. It returns a zero span when the
+// declaration's byte offsets are unknown.
+func (r *run) lineRemovalSpan(span location.Span) location.Span {
+	if span.IsZero() || !span.Start.HasByte() || !span.End.HasByte() {
+		return location.Span{}
+	}
+	sources := r.schema.Sources()
+	content, ok := sources.ContentBySource(span.Source)
+	if !ok || span.End.Byte > len(content) || span.Start.Byte > span.End.Byte {
+		return span
+	}
+
+	lineStart := bytes.LastIndexByte(content[:span.Start.Byte], '\n') + 1
+	lineEnd := len(content)
+	if nl := bytes.IndexByte(content[span.End.Byte:], '\n'); nl >= 0 {
+		lineEnd = span.End.Byte + nl + 1
+	}
+	if len(bytes.TrimSpace(content[lineStart:span.Start.Byte])) > 0 ||
+		len(bytes.TrimSpace(content[span.End.Byte:lineEnd])) > 0 {
+		return span
+	}
+	start := sources.PositionAt(span.Source, lineStart)
+	end := sources.PositionAt(span.Source, lineEnd)
+	if start.IsZero() || end.IsZero() {
+		return span
+	}
+	return location.Span{Source: span.Source, Start: start, End: end}
+}
+
+func (r *run) unusedDataTypes(rule Rule) {
+	refs := r.references()
+	for _, dt := range r.schema.DataTypesSlice() {
+		if refs.dataTypes[dt.Name()] {
+			continue
+		}
+		r.report(rule, dt.Span(),
+			fmt.Sprintf("datatype %q is never used", dt.Name()),
+			diag.Detail{Key: diag.DetailKeyName, Value: dt.Name()})
+	}
+}
+
+func (r *run) abstractWithoutSubtypes(rule Rule) {
+	for _, t := range r.schema.TypesSlice() {
+		if !t.IsAbstract() || len(t.SubTypesSlice()) > 0 {
+			continue
+		}
+		r.report(rule, typeSpan(t),
+			fmt.Sprintf("abstract type %q has no subtypes", t.Name()),
+			diag.Detail{Key: diag.DetailKeyTypeName, Value: t.Name()})
+	}
+}
+
+func (r *run) missingDocumentation(rule Rule) {
+	for _, t := range r.schema.TypesSlice() {
+		if t.IsPart() || strings.TrimSpace(t.Documentation()) != "" {
+			continue
+		}
+		r.report(rule, typeSpan(t),
+			fmt.Sprintf("type %q has no documentation comment", t.Name()),
+			diag.Detail{Key: diag.DetailKeyTypeName, Value: t.Name()})
+	}
+}
+
+// upperSnake matches UPPER_SNAKE_CASE names.
+var upperSnake = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+func (r *run) relationNameCase(rule Rule) {
+	for _, t := range r.schema.TypesSlice() {
+		for _, rel := range slices.Concat(t.AssociationsSlice(), t.CompositionsSlice()) {
+			for _, name := range []string{rel.Name(), rel.Backref()} {
+				if name == "" || upperSnake.MatchString(name) {
+					continue
+				}
+				r.report(rule, rel.Span(),
+					fmt.Sprintf("relation name %q on type %q should be UPPER_SNAKE_CASE", name, t.Name()),
+					diag.Detail{Key: diag.DetailKeyTypeName, Value: t.Name()},
+					diag.Detail{Key: diag.DetailKeyRelationName, Value: name})
+			}
+		}
+	}
+}
+
+func (r *run) enumValueCase(rule Rule) {
+	r.inlineConstraints(func(c schema.Constraint, span location.Span, owner string) {
+		enum, ok := c.(schema.EnumConstraint)
+		if !ok {
+			return
+		}
+		var lower, upper string
+		for _, v := range enum.Values() {
+			first, _ := utf8.DecodeRuneInString(v)
+			switch {
+			case unicode.IsLower(first) && lower == "":
+				lower = v
+			case unicode.IsUpper(first) && upper == "":
+				upper = v
+			}
+		}
+		if lower == "" || upper == "" {
+			return
+		}
+		r.report(rule, span,
+			fmt.Sprintf("enum of %s mixes lower-case value %q with capitalized value %q", owner, lower, upper),
+			diag.Detail{Key: diag.DetailKeyName, Value: owner})
+	})
+}
+
+// regexFlags matches a leading flag group such as (?i).
+var regexFlags = regexp.MustCompile(`^\(\?[a-zA-Z]+\)`)
+
+func (r *run) unanchoredPatterns(rule Rule) {
+	r.inlineConstraints(func(c schema.Constraint, span location.Span, owner string) {
+		pattern, ok := c.(schema.PatternConstraint)
+		if !ok {
+			return
+		}
+		for _, p := range pattern.Patterns() {
+			body := regexFlags.ReplaceAllString(p, "")
+			start := strings.HasPrefix(body, "^") || strings.HasPrefix(body, `\A`)
+			end := (strings.HasSuffix(body, "$") && !strings.HasSuffix(body, `\$`)) || strings.HasSuffix(body, `\z`)
+			if start && end {
+				continue
+			}
+			r.report(rule, span,
+				fmt.Sprintf("pattern %q of %s is not anchored with ^ and $; it matches any string containing a match", p, owner),
+				diag.Detail{Key: diag.DetailKeyName, Value: owner},
+				diag.Detail{Key: diag.DetailKeyDetail, Value: p})
+		}
+	})
+}
+
+// inlineConstraints calls visit for every constraint written out in the
+// schema: datatype definitions and property constraints, including nested
+// ones. Constraints reached through an alias are reported at the datatype,
+// so aliases are not followed. owner describes the declaration.
+func (r *run) inlineConstraints(visit func(c schema.Constraint, span location.Span, owner string)) {
+	walk := func(c schema.Constraint, span location.Span, owner string) {
+		walkConstraint(c, func(c schema.Constraint) bool {
+			if _, ok := c.(schema.AliasConstraint); ok {
+				return false
+			}
+			visit(c, span, owner)
+			return true
+		})
+	}
+	for _, dt := range r.schema.DataTypesSlice() {
+		walk(dt.Constraint(), dt.Span(), fmt.Sprintf("datatype %q", dt.Name()))
+	}
+	for _, t := range r.schema.TypesSlice() {
+		for _, p := range t.PropertiesSlice() {
+			walk(p.Constraint(), p.Span(), fmt.Sprintf("property %s.%s", t.Name(), p.Name()))
+		}
+		for _, rel := range slices.Concat(t.AssociationsSlice(), t.CompositionsSlice()) {
+			for _, p := range rel.PropertiesSlice() {
+				walk(p.Constraint(), p.Span(), fmt.Sprintf("property %s.%s.%s", t.Name(), rel.Name(), p.Name()))
+			}
+		}
+	}
+}
+
+// typeSpan returns the span of a type's name, or of the whole declaration
+// when the name span is unknown.
+func typeSpan(t *schema.Type) location.Span {
+	if span := t.NameSpan(); !span.IsZero() {
+		return span
+	}
+	return t.Span()
+}
+
+func mapKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
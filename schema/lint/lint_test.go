@@ -0,0 +1,246 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/lint"
+	"github.com/simon-lentz/yammm/schema/load"
+)
+
+func mustLoad(t *testing.T, source string) *schema.Schema {
+	t.Helper()
+	s, result, err := load.LoadString(t.Context(), source, "test.yammm")
+	require.NoError(t, err)
+	require.True(t, result.OK(), "unexpected issues: %v", result.Messages())
+	return s
+}
+
+// lintRule runs a single rule over source.
+func lintRule(t *testing.T, rule, source string) []diag.Issue {
+	t.Helper()
+	linter, err := lint.New(lint.WithRules(rule))
+	require.NoError(t, err)
+	var issues []diag.Issue
+	for issue := range linter.Lint(mustLoad(t, source)).Issues() {
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+func detail(issue diag.Issue, key string) string {
+	for _, d := range issue.Details() {
+		if d.Key == key {
+			return d.Value
+		}
+	}
+	return ""
+}
+
+func TestUnusedImport(t *testing.T) {
+	sources := map[string][]byte{
+		"main.yammm": []byte(`schema "main"
+import "./money" as used
+import "./customer" as typed
+import "./extra" as unused
+type Order {
+    id String primary
+    amount used.Money
+    --> CUSTOMER (one) typed.Customer
+}
+`),
+		"money.yammm": []byte(`schema "money"
+type Money = Float[0, _]
+`),
+		"customer.yammm": []byte(`schema "customer"
+type Customer {
+    id String primary
+}
+`),
+		"extra.yammm": []byte(`schema "extra"
+type Extra {
+    id String primary
+}
+`),
+	}
+	s, result, err := load.LoadSourcesWithEntry(t.Context(), sources, "main.yammm", t.TempDir())
+	require.NoError(t, err)
+	require.True(t, result.OK(), "%v", result.Messages())
+
+	linter, err := lint.New(lint.WithRules(lint.RuleUnusedImport))
+	require.NoError(t, err)
+	var found []diag.Issue
+	for issue := range linter.Lint(s).Issues() {
+		found = append(found, issue)
+	}
+	require.Len(t, found, 1)
+	assert.Equal(t, diag.W_UNUSED_IMPORT, found[0].Code())
+	assert.Equal(t, diag.Warning, found[0].Severity())
+	assert.Equal(t, "unused", detail(found[0], diag.DetailKeyAlias))
+	assert.Equal(t, lint.RuleUnusedImport, detail(found[0], lint.DetailKeyRule))
+	assert.True(t, found[0].HasSpan())
+
+	// The fix deletes the whole import line.
+	fixes := found[0].Fixes()
+	require.Len(t, fixes, 1)
+	assert.Equal(t, "Remove unused import", fixes[0].Title)
+	require.Len(t, fixes[0].Edits, 1)
+	edit := fixes[0].Edits[0]
+	assert.Empty(t, edit.NewText)
+	main := string(sources["main.yammm"])
+	assert.Equal(t, "import \"./extra\" as unused\n", main[edit.Span.Start.Byte:edit.Span.End.Byte])
+	assert.Equal(t, 1, edit.Span.End.Column)
+}
+
+func TestUnusedDataType(t *testing.T) {
+	issues := lintRule(t, lint.RuleUnusedDataType, `schema "test"
+type Code = String[1, 10]
+type Codes = List<Code>
+type Amount = Float[0, _]
+type Orphan = Integer
+type Item {
+    id String primary
+    codes Codes
+    price Map<String, Amount>
+}
+`)
+	require.Len(t, issues, 1)
+	assert.Equal(t, diag.W_UNUSED_DATATYPE, issues[0].Code())
+	assert.Equal(t, "Orphan", detail(issues[0], diag.DetailKeyName))
+}
+
+func TestAbstractWithoutSubtypes(t *testing.T) {
+	issues := lintRule(t, lint.RuleAbstractWithoutSubtypes, `schema "test"
+abstract type Named {
+    name String
+}
+abstract type Lonely {
+    note String
+}
+type Person extends Named {
+    id String primary
+}
+`)
+	require.Len(t, issues, 1)
+	assert.Equal(t, diag.W_ABSTRACT_WITHOUT_SUBTYPES, issues[0].Code())
+	assert.Equal(t, diag.Info, issues[0].Severity())
+	assert.Equal(t, "Lonely", detail(issues[0], diag.DetailKeyTypeName))
+}
+
+func TestMissingDocumentation(t *testing.T) {
+	issues := lintRule(t, lint.RuleMissingDocumentation, `schema "test"
+/* A documented type. */
+type Documented {
+    id String primary
+}
+type Undocumented {
+    id String primary
+}
+part type Address {
+    city String
+}
+`)
+	require.Len(t, issues, 1)
+	assert.Equal(t, diag.W_MISSING_DOCUMENTATION, issues[0].Code())
+	assert.Equal(t, diag.Hint, issues[0].Severity())
+	assert.Equal(t, "Undocumented", detail(issues[0], diag.DetailKeyTypeName))
+}
+
+func TestRelationNameCase(t *testing.T) {
+	issues := lintRule(t, lint.RuleRelationNameCase, `schema "test"
+type Company {
+    id String primary
+}
+type Person {
+    id String primary
+    --> WORKS_AT (one) Company / EMPLOYEES (many)
+    --> Manages (many) Company
+    --> OWNS (many) Company / owners (many)
+}
+`)
+	require.Len(t, issues, 2)
+	assert.Equal(t, "Manages", detail(issues[0], diag.DetailKeyRelationName))
+	assert.Equal(t, "owners", detail(issues[1], diag.DetailKeyRelationName))
+	for _, issue := range issues {
+		assert.Equal(t, diag.W_RELATION_NAME_CASE, issue.Code())
+		assert.Equal(t, "Person", detail(issue, diag.DetailKeyTypeName))
+	}
+}
+
+func TestEnumValueCase(t *testing.T) {
+	issues := lintRule(t, lint.RuleEnumValueCase, `schema "test"
+type Status = Enum["active", "Inactive"]
+type Phase = Enum["N/A", "Phase 1"]
+type Item {
+    id String primary
+    status Status
+    color Enum["red", "green"]
+    size List<Enum["small", "Large"]>
+}
+`)
+	require.Len(t, issues, 2)
+	assert.Equal(t, `datatype "Status"`, detail(issues[0], diag.DetailKeyName))
+	assert.Equal(t, "property Item.size", detail(issues[1], diag.DetailKeyName))
+}
+
+func TestUnanchoredPattern(t *testing.T) {
+	issues := lintRule(t, lint.RuleUnanchoredPattern, `schema "test"
+type Digits = Pattern["^[0-9]+$"]
+type Loose = Pattern["[0-9]+"]
+type Item {
+    id String primary
+    code Digits
+    folded Pattern["(?i)^abc$"]
+    prefix Pattern["^abc"]
+    whole Pattern["\\Aabc\\z"]
+}
+`)
+	require.Len(t, issues, 2)
+	assert.Equal(t, "[0-9]+", detail(issues[0], diag.DetailKeyDetail))
+	assert.Equal(t, "^abc", detail(issues[1], diag.DetailKeyDetail))
+	for _, issue := range issues {
+		assert.Equal(t, diag.W_UNANCHORED_PATTERN, issue.Code())
+		assert.True(t, issue.HasSpan())
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	all, err := lint.New()
+	require.NoError(t, err)
+	require.Len(t, all.Rules(), len(lint.Rules()))
+	for i, rule := range lint.Rules() {
+		assert.Equal(t, rule.Name, all.Rules()[i].Name)
+		assert.Equal(t, rule.Severity, all.Rules()[i].Severity)
+	}
+
+	l, err := lint.New(
+		lint.WithRules(lint.RuleUnusedImport, lint.RuleMissingDocumentation),
+		lint.WithDisabledRules(lint.RuleUnusedImport),
+		lint.WithRuleSeverity(lint.RuleMissingDocumentation, diag.Warning),
+	)
+	require.NoError(t, err)
+	rules := l.Rules()
+	require.Len(t, rules, 1)
+	assert.Equal(t, lint.RuleMissingDocumentation, rules[0].Name)
+	assert.Equal(t, diag.Warning, rules[0].Severity)
+
+	_, err = lint.New(lint.WithDisabledRules("no-such-rule"))
+	require.ErrorIs(t, err, lint.ErrUnknownRule)
+	_, err = lint.New(lint.WithRuleSeverity("no-such-rule", diag.Info))
+	require.ErrorIs(t, err, lint.ErrUnknownRule)
+	_, err = lint.New(lint.WithRuleSeverity(lint.RuleUnusedImport, diag.Error))
+	require.ErrorIs(t, err, lint.ErrInvalidSeverity)
+}
+
+func TestLint_Nil(t *testing.T) {
+	var nilLinter *lint.Linter
+	assert.True(t, nilLinter.Lint(mustLoad(t, "schema \"x\"\n")).OK())
+
+	l, err := lint.New()
+	require.NoError(t, err)
+	assert.True(t, l.Lint(nil).OK())
+}
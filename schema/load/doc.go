@@ -40,4 +40,29 @@
 //	    load.WithModuleRoot("/project"),
 //	    load.WithIssueLimit(50),
 //	)
+//
+// # Linting
+//
+// Linting is opt-in. Pass a [lint.Linter] to add its findings (warnings,
+// infos, and hints) to the result of every schema that loads cleanly:
+//
+//	linter, err := lint.New(lint.WithDisabledRules(lint.RuleMissingDocumentation))
+//	if err != nil {
+//	    return err
+//	}
+//	schema, result, err := load.Load(ctx, path, load.WithLinter(linter))
+//
+// # Suppressing Diagnostics
+//
+// A line comment naming codes suppresses them on a single declaration. A
+// comment on its own line applies to the next declaration; a comment after
+// code applies to the declaration starting on that line:
+//
+//	// yammm:ignore W_UNUSED_DATATYPE kept for legacy data
+//	type Legacy = Integer
+//
+// [WithSeverityPolicy] changes severities or suppresses codes for the whole
+// load. Both only affect warnings and milder issues; errors always prevent
+// the schema from loading. An ignore comment naming a code the loader only
+// reports as an error is itself reported as an E_INVALID_IGNORE warning.
 package load
@@ -0,0 +1,215 @@
+package load
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/location"
+	"github.com/simon-lentz/yammm/schema/internal/parse"
+)
+
+// ignoreDirective marks a line comment that suppresses diagnostics on a
+// declaration: // yammm:ignore CODE[,CODE...] reason
+const ignoreDirective = "yammm:ignore"
+
+// warningCodes are the E_ codes the loader reports as warnings. Together
+// with the W_ codes, which never fail, they are the codes a yammm:ignore
+// comment can suppress; every other code is reported as an error, and
+// errors always prevent the schema from loading.
+var warningCodes = map[diag.Code]bool{
+	diag.E_INVALID_CONSTRAINT: true, // also reported as an error
+	diag.E_UNKNOWN_BUILTIN:    true,
+	diag.E_INVALID_IGNORE:     true,
+}
+
+// ignoreComment is a yammm:ignore comment found in schema source.
+type ignoreComment struct {
+	span     location.Span // the comment text, for diagnostics
+	line     int           // 1-based line of the comment
+	trailing bool          // the comment follows code on its line
+	codes    string        // the raw comma-separated code list
+}
+
+// applyIgnoreComments registers a scoped suppression for every yammm:ignore
+// comment in content, covering the declaration the comment is attached to.
+// A comment on its own line applies to the next declaration; a comment after
+// code applies to the declaration starting on that line.
+//
+// Malformed comments produce an E_INVALID_IGNORE warning.
+func (l *loader) applyIgnoreComments(sourceID location.SourceID, content []byte, model *parse.Model) {
+	if !bytes.Contains(content, []byte(ignoreDirective)) {
+		return
+	}
+	decls := declarationSpans(model)
+
+	for _, c := range l.findIgnoreComments(sourceID, content) {
+		codes, ok := l.parseIgnoreCodes(c)
+		if !ok {
+			continue
+		}
+		decl, found := attachIgnoreComment(c, decls)
+		if !found {
+			l.collector.Collect(diag.NewIssue(diag.Warning, diag.E_INVALID_IGNORE,
+				"yammm:ignore comment is not attached to a declaration").
+				WithSpan(c.span).
+				WithHint("place the comment on the line before the declaration, or after it on the same line").
+				Build())
+			continue
+		}
+		l.policy.SuppressWithin(decl, codes...)
+	}
+}
+
+// findIgnoreComments scans content line by line for yammm:ignore comments.
+func (l *loader) findIgnoreComments(sourceID location.SourceID, content []byte) []ignoreComment {
+	var comments []ignoreComment
+	offset, lineNo := 0, 0
+	for line := range strings.SplitSeq(string(content), "\n") {
+		lineStart := offset
+		offset += len(line) + 1
+		lineNo++
+
+		idx := lineCommentStart(line)
+		if idx < 0 {
+			continue
+		}
+		text := strings.TrimSpace(line[idx+2:])
+		if !strings.HasPrefix(text, ignoreDirective) {
+			continue
+		}
+		rest := text[len(ignoreDirective):]
+		if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue // e.g. "yammm:ignored"
+		}
+		end := strings.TrimRight(line, " \t\r")
+		c := ignoreComment{
+			span: location.Span{
+				Source: sourceID,
+				Start:  l.sourceRegistry.PositionAt(sourceID, lineStart+idx),
+				End:    l.sourceRegistry.PositionAt(sourceID, lineStart+len(end)),
+			},
+			line:     lineNo,
+			trailing: strings.TrimSpace(line[:idx]) != "",
+		}
+		if fields := strings.Fields(rest); len(fields) > 0 {
+			c.codes = fields[0]
+		}
+		comments = append(comments, c)
+	}
+	return comments
+}
+
+// lineCommentStart returns the index of the "//" starting a line comment,
+// or -1 if there is none. A "//" inside a string literal does not start a
+// comment; string literals cannot span lines.
+func lineCommentStart(line string) int {
+	var quote byte // the open string literal's quote, or 0
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0 && c == '\\':
+			i++ // skip the escaped character
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseIgnoreCodes resolves the codes named by an ignore comment, reporting
+// an E_INVALID_IGNORE warning if there are none, any is unknown, or any is
+// only reported as an error.
+func (l *loader) parseIgnoreCodes(c ignoreComment) ([]diag.Code, bool) {
+	if c.codes == "" {
+		l.collector.Collect(diag.NewIssue(diag.Warning, diag.E_INVALID_IGNORE,
+			"yammm:ignore comment names no diagnostic code").
+			WithSpan(c.span).
+			WithHint("write the codes to ignore after the directive, e.g. // yammm:ignore W_UNUSED_IMPORT reason").
+			Build())
+		return nil, false
+	}
+	var codes []diag.Code
+	for name := range strings.SplitSeq(c.codes, ",") {
+		code, ok := diag.LookupCode(name)
+		if !ok {
+			l.collector.Collect(diag.NewIssue(diag.Warning, diag.E_INVALID_IGNORE,
+				fmt.Sprintf("yammm:ignore comment names unknown code %q", name)).
+				WithSpan(c.span).
+				WithDetail(diag.DetailKeyName, name).
+				Build())
+			return nil, false
+		}
+		if !strings.HasPrefix(name, "W_") && !warningCodes[code] {
+			l.collector.Collect(diag.NewIssue(diag.Warning, diag.E_INVALID_IGNORE,
+				fmt.Sprintf("yammm:ignore comment names error code %q, which cannot be ignored", name)).
+				WithSpan(c.span).
+				WithDetail(diag.DetailKeyName, name).
+				WithHint("errors always prevent the schema from loading; fix the declaration instead").
+				Build())
+			return nil, false
+		}
+		codes = append(codes, code)
+	}
+	return codes, true
+}
+
+// attachIgnoreComment returns the span of the declaration an ignore comment
+// applies to. decls lists outer declarations before the ones they contain.
+func attachIgnoreComment(c ignoreComment, decls []location.Span) (location.Span, bool) {
+	if c.trailing {
+		for _, d := range decls {
+			if d.Start.Line == c.line {
+				return d, true
+			}
+		}
+		return location.Span{}, false
+	}
+
+	var next location.Span
+	for _, d := range decls {
+		if d.Start.Line > c.line && (next.IsZero() || d.Start.Line < next.Start.Line) {
+			next = d
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// declarationSpans lists the spans of all declarations in a model, outer
+// declarations before the ones they contain.
+func declarationSpans(model *parse.Model) []location.Span {
+	var spans []location.Span
+	add := func(span location.Span) {
+		if !span.IsZero() {
+			spans = append(spans, span)
+		}
+	}
+	for _, imp := range model.Imports {
+		add(imp.Span)
+	}
+	for _, dt := range model.DataTypes {
+		add(dt.Span)
+	}
+	for _, t := range model.Types {
+		add(t.Span)
+		for _, p := range t.Properties {
+			add(p.Span)
+		}
+		for _, r := range t.Relations {
+			add(r.Span)
+			for _, p := range r.Properties {
+				add(p.Span)
+			}
+		}
+		for _, inv := range t.Invariants {
+			add(inv.Span)
+		}
+	}
+	return spans
+}
@@ -14,6 +14,7 @@ import (
 	"sync"
 
 	"github.com/simon-lentz/yammm/diag"
+	"github.com/simon-lentz/yammm/internal/ident"
 	"github.com/simon-lentz/yammm/internal/source"
 	"github.com/simon-lentz/yammm/location"
 	"github.com/simon-lentz/yammm/schema"
@@ -480,6 +481,7 @@ type loader struct {
 	registry        *schema.Registry
 	sourceRegistry  *source.Registry
 	collector       *diag.Collector
+	policy          *diag.SeverityPolicy // collector's policy; extended by yammm:ignore comments
 	logger          *slog.Logger
 	disallowImports bool
 
@@ -532,12 +534,20 @@ func newLoader(cfg *config, moduleRoot string) (*loader, error) {
 		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
 	}
 
+	// The loader's policy gains a scoped suppression for every yammm:ignore
+	// comment, so it starts as a copy of the caller's. Errors are preserved
+	// because a schema with suppressed errors would be incomplete.
+	policy := cfg.policy.Clone().PreserveFailures()
+	collector := diag.NewCollector(cfg.issueLimit)
+	collector.SetPolicy(policy)
+
 	return &loader{
 		cfg:             cfg,
 		moduleRoot:      moduleRoot,
 		registry:        registry,
 		sourceRegistry:  sourceReg,
-		collector:       diag.NewCollector(cfg.issueLimit),
+		collector:       collector,
+		policy:          policy,
 		logger:          logger,
 		disallowImports: cfg.disallowImports,
 		sourceContent:   make(map[location.SourceID][]byte),
@@ -656,6 +666,9 @@ func (l *loader) loadSource(ctx context.Context, sourceID location.SourceID, con
 		return nil, l.collector.Result(), nil
 	}
 
+	// Honor yammm:ignore comments for everything reported after parsing
+	l.applyIgnoreComments(sourceID, content, model)
+
 	// Validate imports and check for duplicates
 	if !l.validateImports(sourceID, model) {
 		return nil, l.collector.Result(), nil
@@ -677,7 +690,7 @@ func (l *loader) loadSource(ctx context.Context, sourceID location.SourceID, con
 	}
 
 	// Complete the schema (resolve types, validate, etc.)
-	s := complete.Complete(model, sourceID, l.collector, &registryAdapter{l.registry}, resolvedImports)
+	s := complete.Complete(model, sourceID, l.collector, &registryAdapter{l.registry}, resolvedImports, l.cfg.customTypes)
 
 	if s == nil {
 		return nil, l.collector.Result(), nil
@@ -695,6 +708,8 @@ func (l *loader) loadSource(ctx context.Context, sourceID location.SourceID, con
 		imp.Seal()
 	}
 
+	l.checkFunctionNames(s)
+
 	// Schema must be nil if any errors exist.
 	// Check BEFORE registration to avoid registering schemas we'll discard.
 	if l.collector.HasErrors() {
@@ -704,6 +719,15 @@ func (l *loader) loadSource(ctx context.Context, sourceID location.SourceID, con
 	// Attach sources for diagnostics rendering
 	s.SetSources(schema.NewSources(l.sourceRegistry))
 
+	// Lint after attaching sources so fixes can span whole lines.
+	if l.cfg.linter != nil {
+		l.collector.Merge(l.cfg.linter.Lint(s))
+		// A severity policy may raise lint findings to errors.
+		if l.collector.HasErrors() {
+			return nil, l.collector.Result(), nil
+		}
+	}
+
 	// Seal the schema to prevent further mutation
 	s.Seal()
 
@@ -727,6 +751,31 @@ func (l *loader) loadSource(ctx context.Context, sourceID location.SourceID, con
 	return s, l.collector.Result(), nil
 }
 
+// checkFunctionNames warns about invariant expressions calling functions
+// unknown to the WithBuiltins registry. Without a registry the check is
+// skipped, since functions may be registered with the evaluator later.
+func (l *loader) checkFunctionNames(s *schema.Schema) {
+	if l.cfg.builtins == nil {
+		return
+	}
+	known := l.cfg.builtins.Names()
+	for _, t := range s.TypesSlice() {
+		for _, inv := range t.InvariantsSlice() {
+			for _, name := range l.cfg.builtins.Unknown(inv.Expression()) {
+				suggestion, _ := ident.Suggest(name, known)
+				l.collector.Collect(diag.NewIssue(
+					diag.Warning,
+					diag.E_UNKNOWN_BUILTIN,
+					fmt.Sprintf("unknown function %q in invariant %q on type %q", name, inv.Name(), t.Name()),
+				).WithSpan(inv.Span()).
+					WithDetail(diag.DetailKeyTypeName, t.Name()).
+					WithSuggestion(suggestion).
+					Build())
+			}
+		}
+	}
+}
+
 // validateImports checks for import issues.
 func (l *loader) validateImports(sourceID location.SourceID, model *parse.Model) bool {
 	if l.disallowImports && len(model.Imports) > 0 {
@@ -990,11 +1039,7 @@ func (l *loader) resolveImportToRelative(sourceID location.SourceID, importPath
 // readImportFile reads an import file using sandboxed access via rootLoader.
 // Falls back to in-memory sources if available.
 func (l *loader) readImportFile(relativePath string, imp *parse.ImportDecl) ([]byte, location.SourceID, error) {
-	// Try with .yammm extension first, then without
-	candidates := []string{relativePath}
-	if !strings.HasSuffix(relativePath, ".yammm") {
-		candidates = []string{relativePath + ".yammm", relativePath}
-	}
+	candidates := importCandidates(relativePath)
 
 	// First check if we have it in in-memory sources (for LoadSources)
 	for _, candidate := range candidates {
@@ -1058,6 +1103,15 @@ func (l *loader) readImportFile(relativePath string, imp *parse.ImportDecl) ([]b
 	return nil, location.SourceID{}, fmt.Errorf("import file %q not found", imp.Path)
 }
 
+// importCandidates returns the paths tried for an import, in order: with
+// the .yammm extension first, then as written.
+func importCandidates(relativePath string) []string {
+	if strings.HasSuffix(relativePath, ".yammm") {
+		return []string{relativePath}
+	}
+	return []string{relativePath + ".yammm", relativePath}
+}
+
 // makeCanonicalPath converts a path to absolute, cleaned, symlink-resolved form.
 // This is used for trusted entry-point paths (not imports), where we need a
 // canonical path for SourceID construction.
@@ -16,6 +16,8 @@ import (
 	"github.com/simon-lentz/yammm/diag"
 	"github.com/simon-lentz/yammm/location"
 	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/expr"
+	"github.com/simon-lentz/yammm/schema/lint"
 	"github.com/simon-lentz/yammm/schema/load"
 )
 
@@ -477,6 +479,47 @@ func TestLoad_PathEscape(t *testing.T) {
 	assert.True(t, found, "Expected path escape error, got: %v", result.Messages())
 }
 
+func TestResolveImport(t *testing.T) {
+	tmpDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	moduleRoot := filepath.Join(tmpDir, "project")
+	require.NoError(t, os.MkdirAll(filepath.Join(moduleRoot, "common", "types"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "escaped.yammm"), []byte(`schema "escaped"`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleRoot, "common", "parts.yammm"), []byte(`schema "parts"`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleRoot, "common", "raw"), []byte(`schema "raw"`), 0o600))
+	mainPath := filepath.Join(moduleRoot, "main.yammm")
+	require.NoError(t, os.WriteFile(mainPath, []byte(`schema "main"`), 0o600))
+
+	tests := []struct {
+		name       string
+		importPath string
+		moduleRoot string
+		want       string
+		wantErr    string
+	}{
+		{"relative", "./common/parts", "", filepath.Join(moduleRoot, "common", "parts.yammm"), ""},
+		{"relative with extension", "./common/parts.yammm", "", filepath.Join(moduleRoot, "common", "parts.yammm"), ""},
+		{"without extension", "./common/raw", "", filepath.Join(moduleRoot, "common", "raw"), ""},
+		{"module style", "common/parts", moduleRoot, filepath.Join(moduleRoot, "common", "parts.yammm"), ""},
+		{"module style without root", "common/parts", "", filepath.Join(moduleRoot, "common", "parts.yammm"), ""},
+		{"escape", "../escaped", moduleRoot, "", "escapes module root"},
+		{"missing", "./common/missing", "", "", "not found"},
+		{"directory", "./common/types", "", "", "is a directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := load.ResolveImport(mainPath, tt.importPath, tt.moduleRoot)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestLoad_SymlinkCanonicalization(t *testing.T) {
 	// Create a temporary directory structure
 	tmpDir := t.TempDir()
@@ -1633,6 +1676,61 @@ type Person {
 	assert.True(t, found, "expected E_UNKNOWN_PROPERTY diagnostic")
 }
 
+// TestLoadString_InvariantIDs verifies that invariant severities and IDs are
+// carried into the schema, that a subtype overrides an inherited invariant by
+// ID, and that duplicate IDs within a type are rejected.
+func TestLoadString_InvariantIDs(t *testing.T) {
+	t.Parallel()
+
+	source := `schema "test"
+
+abstract type Base {
+	age Integer
+	! adult "must be an adult" age >= 18
+}
+
+type Person extends Base {
+	!warn adult "should be an adult" age >= 18
+	!info "age is unusual" age < 120
+}`
+	s, result, err := load.LoadString(t.Context(), source, "test.yammm")
+	require.NoError(t, err)
+	require.True(t, result.OK(), "unexpected errors: %v", result.Messages())
+
+	person, ok := s.Type("Person")
+	require.True(t, ok)
+	invs := person.AllInvariantsSlice()
+	require.Len(t, invs, 2, "inherited invariant must be overridden by ID")
+
+	assert.Equal(t, "adult", invs[0].ID())
+	assert.True(t, invs[0].HasExplicitID())
+	assert.Equal(t, "should be an adult", invs[0].Name())
+	assert.Equal(t, diag.Warning, invs[0].Severity())
+
+	assert.Equal(t, "age is unusual", invs[1].ID(), "the message identifies an invariant without an ID")
+	assert.False(t, invs[1].HasExplicitID())
+	assert.Equal(t, diag.Info, invs[1].Severity())
+
+	base, ok := s.Type("Base")
+	require.True(t, ok)
+	assert.Equal(t, diag.Error, base.InvariantsSlice()[0].Severity())
+
+	dup := `schema "test"
+
+type Person {
+	age Integer
+	! adult "must be an adult" age >= 18
+	!warn adult "should be an adult" age >= 21
+}`
+	s, result, err = load.LoadString(t.Context(), dup, "dup.yammm")
+	require.NoError(t, err)
+	assert.Nil(t, s)
+	require.True(t, result.HasErrors())
+	issue := result.IssuesSlice()[0]
+	assert.Equal(t, diag.E_INVALID_INVARIANT, issue.Code())
+	assert.Contains(t, issue.Message(), `invariant id "adult" is defined multiple times`)
+}
+
 // TestLoadString_InvariantValidProperty verifies that a schema with valid
 // invariants referencing existing properties compiles successfully end-to-end.
 func TestLoadString_InvariantValidProperty(t *testing.T) {
@@ -1708,3 +1806,384 @@ type Order {
 	}
 	assert.True(t, found, "expected E_UNKNOWN_PROPERTY for nonexistent on LineItem")
 }
+
+func TestLoadString_WithBuiltins_UnknownFunction(t *testing.T) {
+	t.Parallel()
+
+	source := `schema "test"
+
+type Card {
+	number String required
+	! "checksum" number -> Luhn
+	! "weekday" number -> IsWeekday && number -> Len > 0
+}`
+	builtins := expr.NewBuiltinRegistry()
+	builtins.MustRegister("Luhn")
+
+	s, result, err := load.LoadString(t.Context(), source, "test.yammm", load.WithBuiltins(builtins))
+	require.NoError(t, err)
+	require.NotNil(t, s, "unknown functions are warnings, not errors")
+	require.True(t, result.OK())
+
+	issues := result.IssuesSlice()
+	require.Len(t, issues, 1)
+	assert.Equal(t, diag.Warning, issues[0].Severity())
+	assert.Equal(t, diag.E_UNKNOWN_BUILTIN, issues[0].Code())
+	assert.Contains(t, issues[0].Message(), `unknown function "IsWeekday" in invariant "weekday"`)
+	assert.Equal(t, 6, issues[0].Span().Start.Line)
+
+	// Without a registry, function names are not checked at load time.
+	_, result, err = load.LoadString(t.Context(), source, "test.yammm")
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Len())
+}
+
+func TestLoadString_UnknownNameSuggestions(t *testing.T) {
+	t.Parallel()
+
+	source := `schema "test"
+
+type Person {
+	id String primary
+}
+
+type Employee extends Persn {
+}`
+	_, result, err := load.LoadString(t.Context(), source, "test.yammm")
+	require.NoError(t, err)
+	issues := result.IssuesSlice()
+	require.Len(t, issues, 1)
+	assert.Equal(t, diag.E_UNKNOWN_TYPE, issues[0].Code())
+	assert.Equal(t, `did you mean "Person"?`, issues[0].Hint())
+	assert.Contains(t, issues[0].Details(), diag.Detail{Key: diag.DetailKeySuggestion, Value: "Person"})
+	fixes := issues[0].Fixes()
+	require.Len(t, fixes, 1)
+	require.Len(t, fixes[0].Edits, 1)
+	assert.Equal(t, "Person", fixes[0].Edits[0].NewText)
+	assert.Equal(t, 7, fixes[0].Edits[0].Span.Start.Line)
+
+	source = `schema "test"
+
+type Company {
+	id String primary
+}
+
+type Employee {
+	id String primary
+	--> EMPLOYER (one) Compny
+}`
+	_, result, err = load.LoadString(t.Context(), source, "test.yammm")
+	require.NoError(t, err)
+	issues = result.IssuesSlice()
+	require.Len(t, issues, 1)
+	assert.Equal(t, diag.E_UNKNOWN_TYPE, issues[0].Code())
+	assert.Equal(t, `did you mean "Company"?`, issues[0].Hint())
+
+	// Names too far from anything in scope get no suggestion.
+	source = `schema "test"
+
+type Employee {
+	id String primary
+	--> EMPLOYER (one) Organization
+}`
+	_, result, err = load.LoadString(t.Context(), source, "test.yammm")
+	require.NoError(t, err)
+	issues = result.IssuesSlice()
+	require.Len(t, issues, 1)
+	assert.Empty(t, issues[0].Hint())
+
+	// Builtin names are suggested from the WithBuiltins registry.
+	builtins := expr.NewBuiltinRegistry()
+	builtins.MustRegister("Luhn")
+	source = `schema "test"
+
+type Card {
+	number String required
+	! "checksum" number -> Lunh
+}`
+	_, result, err = load.LoadString(t.Context(), source, "test.yammm", load.WithBuiltins(builtins))
+	require.NoError(t, err)
+	issues = result.IssuesSlice()
+	require.Len(t, issues, 1)
+	assert.Equal(t, diag.E_UNKNOWN_BUILTIN, issues[0].Code())
+	assert.Equal(t, `did you mean "Luhn"?`, issues[0].Hint())
+	assert.Contains(t, issues[0].Details(), diag.Detail{Key: diag.DetailKeySuggestion, Value: "Luhn"})
+}
+
+func TestLoadSourcesWithEntry_WithLinter(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	sources := map[string][]byte{
+		filepath.Join(tmpDir, "main.yammm"): []byte(`schema "main"
+
+import "./shared" as shared
+
+type Order {
+	id String primary
+	--> placed_by (one) shared.Customer
+}`),
+		filepath.Join(tmpDir, "shared.yammm"): []byte(`schema "shared"
+
+type Unused = Integer
+
+type Customer {
+	id String primary
+}`),
+	}
+	linter, err := lint.New(lint.WithRules(lint.RuleRelationNameCase, lint.RuleUnusedDataType))
+	require.NoError(t, err)
+
+	s, result, err := load.LoadSourcesWithEntry(t.Context(), sources, filepath.Join(tmpDir, "main.yammm"), tmpDir,
+		load.WithLinter(linter))
+	require.NoError(t, err)
+	require.NotNil(t, s, "lint findings never prevent loading")
+	require.True(t, result.OK())
+
+	codes := make(map[diag.Code]string)
+	for issue := range result.Issues() {
+		codes[issue.Code()] = issue.Span().Source.String()
+	}
+	require.Len(t, codes, 2)
+	assert.Contains(t, codes[diag.W_RELATION_NAME_CASE], "main.yammm")
+	assert.Contains(t, codes[diag.W_UNUSED_DATATYPE], "shared.yammm", "imported schemas are linted too")
+
+	// Without a linter, schemas are not linted.
+	_, result, err = load.LoadSourcesWithEntry(t.Context(), sources, filepath.Join(tmpDir, "main.yammm"), tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Len())
+}
+
+func TestLoadString_IgnoreComments(t *testing.T) {
+	t.Parallel()
+
+	linter, err := lint.New(lint.WithRules(lint.RuleRelationNameCase, lint.RuleUnusedDataType))
+	require.NoError(t, err)
+
+	source := `schema "test"
+
+// yammm:ignore W_UNUSED_DATATYPE kept for legacy data
+type Legacy = Integer
+type Orphan = Integer
+
+type Company {
+	id String primary
+}
+
+type Person {
+	id String primary
+	--> worksAt (one) Company // yammm:ignore W_RELATION_NAME_CASE,W_UNUSED_DATATYPE legacy name
+	--> owns (many) Company
+}
+`
+	s, result, err := load.LoadString(t.Context(), source, "test.yammm", load.WithLinter(linter))
+	require.NoError(t, err)
+	require.NotNil(t, s)
+
+	var names []string
+	for issue := range result.Issues() {
+		names = append(names, issue.Code().String()+" "+issue.Message())
+	}
+	require.Len(t, names, 2, "%v", names)
+	assert.Contains(t, names[0], "Orphan")
+	assert.Contains(t, names[1], "owns")
+	assert.Equal(t, 2, result.SuppressedCount())
+}
+
+func TestLoadString_InvalidIgnoreComments(t *testing.T) {
+	t.Parallel()
+
+	source := `schema "test"
+
+type Person {
+	id String primary // yammm:ignore
+	name String // yammm:ignore W_NO_SUCH_CODE reason
+}
+// yammm:ignore W_UNUSED_DATATYPE nothing follows
+`
+	s, result, err := load.LoadString(t.Context(), source, "test.yammm")
+	require.NoError(t, err)
+	require.NotNil(t, s, "malformed ignore comments are only warnings")
+
+	var lines []int
+	for issue := range result.Issues() {
+		assert.Equal(t, diag.E_INVALID_IGNORE, issue.Code())
+		assert.Equal(t, diag.Warning, issue.Severity())
+		lines = append(lines, issue.Span().Start.Line)
+	}
+	assert.Equal(t, []int{4, 5, 7}, lines)
+}
+
+func TestLoadString_IgnoreCommentKeepsErrors(t *testing.T) {
+	t.Parallel()
+
+	source := `schema "test"
+
+type Person {
+	id String primary
+	// yammm:ignore E_DUPLICATE_PROPERTY
+	id String
+}
+`
+	s, result, err := load.LoadString(t.Context(), source, "test.yammm",
+		load.WithSeverityPolicy(diag.NewSeverityPolicy().Suppress(diag.E_DUPLICATE_PROPERTY)))
+	require.NoError(t, err)
+	assert.Nil(t, s)
+	assert.True(t, result.HasErrors(), "errors cannot be suppressed at load")
+	assert.Equal(t, 0, result.SuppressedCount())
+
+	var invalid []diag.Issue
+	for issue := range result.Issues() {
+		if issue.Code() == diag.E_INVALID_IGNORE {
+			invalid = append(invalid, issue)
+		}
+	}
+	require.Len(t, invalid, 1, "ignoring an error code is reported")
+	assert.Equal(t, 5, invalid[0].Span().Start.Line)
+	assert.Contains(t, invalid[0].Message(), "E_DUPLICATE_PROPERTY")
+}
+
+func TestLoadString_IgnoreCommentAfterString(t *testing.T) {
+	t.Parallel()
+
+	linter, err := lint.New(lint.WithRules(lint.RuleUnanchoredPattern))
+	require.NoError(t, err)
+
+	source := `schema "test"
+
+type Page {
+	id String primary
+	url Pattern["https?://example"] // yammm:ignore W_UNANCHORED_PATTERN any page
+	path Pattern["a//b"]
+}
+`
+	s, result, err := load.LoadString(t.Context(), source, "test.yammm", load.WithLinter(linter))
+	require.NoError(t, err)
+	require.NotNil(t, s)
+
+	issues := result.IssuesSlice()
+	require.Len(t, issues, 1, "%v", result.Messages())
+	assert.Equal(t, diag.W_UNANCHORED_PATTERN, issues[0].Code())
+	assert.Equal(t, 6, issues[0].Span().Start.Line)
+	assert.Equal(t, 1, result.SuppressedCount())
+}
+
+func TestLoadString_WithSeverityPolicy(t *testing.T) {
+	t.Parallel()
+
+	linter, err := lint.New(lint.WithRules(lint.RuleUnusedDataType, lint.RuleRelationNameCase))
+	require.NoError(t, err)
+	source := `schema "test"
+
+type Orphan = Integer
+
+type Person {
+	id String primary
+	--> knows (many) Person
+}
+`
+	policy := diag.NewSeverityPolicy().
+		Suppress(diag.W_RELATION_NAME_CASE).
+		Override(diag.W_UNUSED_DATATYPE, diag.Info)
+	s, result, err := load.LoadString(t.Context(), source, "test.yammm",
+		load.WithLinter(linter), load.WithSeverityPolicy(policy))
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	require.Equal(t, 1, result.Len())
+	assert.Equal(t, diag.Info, result.IssuesSlice()[0].Severity())
+	assert.Equal(t, 1, result.SuppressedCount())
+
+	// Raising a warning to an error makes the load fail.
+	s, result, err = load.LoadString(t.Context(), source, "test.yammm",
+		load.WithLinter(linter), load.WithSeverityPolicy(diag.NewSeverityPolicy().Override(diag.W_UNUSED_DATATYPE, diag.Error)))
+	require.NoError(t, err)
+	assert.Nil(t, s)
+	assert.True(t, result.HasErrors())
+}
+
+func TestLoadString_StructuredTypes(t *testing.T) {
+	t.Parallel()
+
+	source := `schema "test"
+
+type Coord = Float[-180, 180]
+type Point = Object { lat Coord required, lon Coord required }
+
+type Route {
+	id String primary
+	origin Point required
+	stops Map<String, Point>
+	! "northern" origin.lat > 0
+}`
+	s, result, err := load.LoadString(t.Context(), source, "test.yammm")
+	require.NoError(t, err)
+	require.True(t, result.OK(), "unexpected errors: %v", result.Messages())
+
+	route, ok := s.Type("Route")
+	require.True(t, ok)
+	stops, ok := route.Property("stops")
+	require.True(t, ok)
+	mc, ok := stops.Constraint().(schema.MapConstraint)
+	require.True(t, ok)
+	assert.True(t, mc.IsResolved(), "aliases nested in Map and Object resolve")
+	oc, ok := mc.Value().(schema.AliasConstraint).Resolved().(schema.ObjectConstraint)
+	require.True(t, ok)
+	lat, ok := oc.Field("lat")
+	require.True(t, ok)
+	assert.True(t, lat.Constraint().IsResolved())
+}
+
+func TestLoadString_StructuredTypes_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		code diag.Code
+		want string
+	}{
+		{
+			name: "non-string map key",
+			body: "m Map<Integer, String>",
+			code: diag.E_INVALID_CONSTRAINT,
+			want: "map key type must be String, Enum, Pattern or UUID, got Integer",
+		},
+		{
+			name: "nested non-string map key",
+			body: "m List<Object { inner Map<Boolean, String> }>",
+			code: diag.E_INVALID_CONSTRAINT,
+			want: "got Boolean",
+		},
+		{
+			name: "object set element",
+			body: "s Set<Object { a Integer }>",
+			code: diag.E_INVALID_CONSTRAINT,
+			want: "set element type must not be a Map or Object, got Object { a Integer }",
+		},
+		{
+			name: "map set element",
+			body: "s List<Set<Map<String, Integer>>>",
+			code: diag.E_INVALID_CONSTRAINT,
+			want: "got Map<String, Integer>",
+		},
+		{
+			name: "unknown object field in invariant",
+			body: "o Object { a Integer }\n\t! \"positive\" o.b > 0",
+			code: diag.E_UNKNOWN_PROPERTY,
+			want: `unknown field "b" on Object { a Integer }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			source := "schema \"test\"\n\ntype Thing {\n\t" + tt.body + "\n}"
+			s, result, err := load.LoadString(t.Context(), source, "test.yammm")
+			require.NoError(t, err)
+			assert.Nil(t, s)
+			require.False(t, result.OK())
+			issue := result.IssuesSlice()[0]
+			assert.Equal(t, tt.code, issue.Code())
+			assert.Contains(t, issue.Message(), tt.want)
+		})
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"errors"
 	"log/slog"
 
+	"github.com/simon-lentz/yammm/diag"
 	"github.com/simon-lentz/yammm/location"
 	"github.com/simon-lentz/yammm/schema"
+	"github.com/simon-lentz/yammm/schema/expr"
+	"github.com/simon-lentz/yammm/schema/lint"
 )
 
 // ErrSourceStoreNotSupported is returned when WithSourceRegistry is called
@@ -27,6 +30,10 @@ type config struct {
 	sourceRegistry  SourceStore
 	logger          *slog.Logger
 	disallowImports bool
+	builtins        *expr.BuiltinRegistry
+	customTypes     *schema.CustomTypeRegistry
+	linter          *lint.Linter
+	policy          *diag.SeverityPolicy
 }
 
 // defaultConfig returns a config with sensible defaults.
@@ -118,3 +125,52 @@ func WithLogger(logger *slog.Logger) Option {
 		c.logger = logger
 	}
 }
+
+// WithBuiltins enables load-time checking of function names in invariant
+// expressions. Calls to functions not in the registry produce an
+// E_UNKNOWN_BUILTIN warning; the schema still loads.
+//
+// Pass eval.FunctionRegistry.Builtins() so that user-defined functions are
+// recognized. If not provided, function names are only checked when
+// invariants are evaluated.
+func WithBuiltins(r *expr.BuiltinRegistry) Option {
+	return func(c *config) {
+		c.builtins = r
+	}
+}
+
+// WithCustomTypes makes the Go datatypes in the registry usable in the
+// loaded schemas, including imported ones, like built-in types. A datatype
+// declared in a schema shadows a custom type of the same name. If not
+// provided, no custom types are available.
+//
+// Validate instances of the schemas with the same registry (see
+// instance.WithCustomTypes).
+func WithCustomTypes(r *schema.CustomTypeRegistry) Option {
+	return func(c *config) {
+		c.customTypes = r
+	}
+}
+
+// WithLinter runs the linter on every schema that loads without errors,
+// including imported schemas, and adds its findings to the result. Lint
+// findings are warnings or milder, so they never prevent a schema from
+// loading. If not provided, schemas are not linted.
+func WithLinter(l *lint.Linter) Option {
+	return func(c *config) {
+		c.linter = l
+	}
+}
+
+// WithSeverityPolicy changes the severity of load diagnostics by code, or
+// suppresses them. Only warnings and milder issues are affected; errors are
+// always reported, since a schema cannot be built past them. A policy may
+// still raise a warning to an error, which makes the load fail.
+//
+// The policy is combined with the yammm:ignore comments found in the loaded
+// sources. Suppressed issues are counted in [diag.Result.SuppressedCount].
+func WithSeverityPolicy(p *diag.SeverityPolicy) Option {
+	return func(c *config) {
+		c.policy = p
+	}
+}
@@ -0,0 +1,66 @@
+package load
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/simon-lentz/yammm/location"
+)
+
+// ResolveImport returns the absolute path of the file that an import of
+// importPath in the schema file at path refers to, resolved as [Load]
+// resolves it: relative paths ("./common") against the file's directory,
+// module-style paths ("common/types") against the module root, with the
+// .yammm extension tried first.
+//
+// If moduleRoot is empty, the file's directory is the module root. As in
+// [Load], the file is opened through the module root, so a path that
+// escapes it is an error even if the file exists.
+func ResolveImport(path, importPath, moduleRoot string) (string, error) {
+	absPath, err := makeCanonicalPath(path)
+	if err != nil {
+		return "", fmt.Errorf("resolve path %q: %w", path, err)
+	}
+	if moduleRoot == "" {
+		moduleRoot = filepath.Dir(absPath)
+	} else if moduleRoot, err = makeCanonicalPath(moduleRoot); err != nil {
+		return "", fmt.Errorf("invalid module root: %w", err)
+	}
+	sourceID, err := location.SourceIDFromAbsolutePath(absPath)
+	if err != nil {
+		return "", fmt.Errorf("create source ID for %q: %w", path, err)
+	}
+
+	l := &loader{moduleRoot: moduleRoot}
+	relativePath, err := l.resolveImportToRelative(sourceID, importPath)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve import %q: %w", importPath, err)
+	}
+
+	rl, err := newRootLoader(moduleRoot)
+	if err != nil {
+		return "", err
+	}
+	defer rl.Close()
+
+	var lastErr error
+	for _, candidate := range importCandidates(relativePath) {
+		f, err := rl.openFile(candidate)
+		if err != nil {
+			if _, ok := errors.AsType[*pathEscapeError](err); ok { //nolint:errcheck // type check only, value unused
+				return "", err
+			}
+			lastErr = err
+			continue
+		}
+		info, statErr := f.Stat()
+		_ = f.Close() // read-only handle; nothing to flush
+		if statErr == nil && info.IsDir() {
+			lastErr = fmt.Errorf("import %q is a directory", importPath)
+			continue
+		}
+		return filepath.Join(rl.rootPath, filepath.Clean(candidate)), nil
+	}
+	return "", lastErr
+}